@@ -0,0 +1,8 @@
+"""Python client for the tsuite REST API.
+
+Methods are generated from the operationIds in internal/api/openapi.yaml.
+"""
+
+from .client import TsuiteClient, TsuiteError
+
+__all__ = ["TsuiteClient", "TsuiteError"]
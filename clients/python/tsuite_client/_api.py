@@ -0,0 +1,163 @@
+# Code generated from internal/api/openapi.yaml by go generate ./internal/api; DO NOT EDIT.
+"""Operations of the tsuite REST API, one method per operationId."""
+
+from typing import Any, Dict, Optional
+from urllib.parse import quote
+
+
+class Operations:
+    """API operations. Subclasses implement _request (see TsuiteClient)."""
+
+    def _request(
+        self,
+        method: str,
+        path: str,
+        body: Optional[Dict[str, Any]] = None,
+        query: Optional[Dict[str, Any]] = None,
+        raw: bool = False,
+    ) -> Any:
+        raise NotImplementedError
+
+    def health(self) -> Any:
+        """Health check (alias of /healthz)."""
+        return self._request("GET", "/health")
+
+    def healthz(self) -> Any:
+        """Liveness probe."""
+        return self._request("GET", "/healthz")
+
+    def readyz(self) -> Any:
+        """Readiness probe."""
+        return self._request("GET", "/readyz")
+
+    def get_version(self) -> Any:
+        """Server version and supported payload schema versions."""
+        return self._request("GET", "/api/version")
+
+    def get_branding(self) -> Any:
+        """Dashboard title, logo and accent color of this deployment."""
+        return self._request("GET", "/api/branding")
+
+    def get_branding_logo(self) -> bytes:
+        """The logo of tsuite api --logo."""
+        return self._request("GET", "/api/branding/logo", raw=True)
+
+    def list_suites(self) -> Any:
+        """List registered suites."""
+        return self._request("GET", "/api/suites")
+
+    def create_suite(self, body: Dict[str, Any]) -> Any:
+        """Register a suite folder."""
+        return self._request("POST", "/api/suites", body=body)
+
+    def get_suite(self, id: int) -> Any:
+        """Get a suite with its discovered tests."""
+        return self._request("GET", "/api/suites/" + quote(str(id)))
+
+    def list_suite_issues(self, id: int, *, open: Optional[bool] = None) -> Any:
+        """List issues opened in the suite's tracker for tests that kept failing."""
+        return self._request("GET", "/api/suites/" + quote(str(id)) + "/issues", query={"open": open})
+
+    def get_suite_badge(self, id: int, *, label: Optional[str] = None) -> bytes:
+        """Status badge of the suite's latest finished run."""
+        return self._request("GET", "/api/suites/" + quote(str(id)) + "/badge.svg", query={"label": label}, raw=True)
+
+    def export_suite_durations(self, id: int, *, format: Optional[str] = None, runs: Optional[int] = None) -> bytes:
+        """Test durations across the suite's recent finished runs as CSV."""
+        return self._request("GET", "/api/suites/" + quote(str(id)) + "/export/durations", query={"format": format, "runs": runs}, raw=True)
+
+    def list_runs(self, *, suite_id: Optional[int] = None, status: Optional[str] = None, pipeline_run_id: Optional[str] = None, sort: Optional[str] = None, order: Optional[str] = None, limit: Optional[int] = None, offset: Optional[int] = None, cursor: Optional[str] = None) -> Any:
+        """List recent runs."""
+        return self._request("GET", "/api/runs", query={"suite_id": suite_id, "status": status, "pipeline_run_id": pipeline_run_id, "sort": sort, "order": order, "limit": limit, "offset": offset, "cursor": cursor})
+
+    def create_run(self, body: Dict[str, Any]) -> Any:
+        """Create a run and its pending test records. body follows the CreateRunRequest schema."""
+        return self._request("POST", "/api/runs", body=body)
+
+    def get_latest_run(self) -> Any:
+        """Get the most recent run."""
+        return self._request("GET", "/api/runs/latest")
+
+    def get_feed(self, *, suite_id: Optional[int] = None, limit: Optional[int] = None) -> bytes:
+        """Atom feed of finished runs."""
+        return self._request("GET", "/api/feed.atom", query={"suite_id": suite_id, "limit": limit}, raw=True)
+
+    def get_run(self, run_id: str) -> Any:
+        """Get a run with its test results."""
+        return self._request("GET", "/api/runs/" + quote(str(run_id)))
+
+    def cancel_run(self, run_id: str, body: Dict[str, Any]) -> Any:
+        """Mark a run as cancelled."""
+        return self._request("PATCH", "/api/runs/" + quote(str(run_id)), body=body)
+
+    def delete_run(self, run_id: str) -> Any:
+        """Delete a run and its results."""
+        return self._request("DELETE", "/api/runs/" + quote(str(run_id)))
+
+    def get_run_tests(self, run_id: str, *, status: Optional[str] = None, uc: Optional[str] = None, tag: Optional[str] = None, owner: Optional[str] = None, sort: Optional[str] = None, order: Optional[str] = None, limit: Optional[int] = None, offset: Optional[int] = None) -> Any:
+        """List test results for a run."""
+        return self._request("GET", "/api/runs/" + quote(str(run_id)) + "/tests", query={"status": status, "uc": uc, "tag": tag, "owner": owner, "sort": sort, "order": order, "limit": limit, "offset": offset})
+
+    def get_run_slowest(self, run_id: str, *, limit: Optional[int] = None) -> Any:
+        """Slowest finished tests and duration budget violations."""
+        return self._request("GET", "/api/runs/" + quote(str(run_id)) + "/slowest", query={"limit": limit})
+
+    def get_run_scheduling(self, run_id: str) -> Any:
+        """Queue wait and worker utilization of a run."""
+        return self._request("GET", "/api/runs/" + quote(str(run_id)) + "/scheduling")
+
+    def export_run(self, run_id: str, *, format: Optional[str] = None) -> bytes:
+        """Test results of a run as CSV."""
+        return self._request("GET", "/api/runs/" + quote(str(run_id)) + "/export", query={"format": format}, raw=True)
+
+    def wait_run(self, run_id: str, *, timeout: Optional[str] = None) -> Any:
+        """Long-poll until the run finishes."""
+        return self._request("GET", "/api/runs/" + quote(str(run_id)) + "/wait", query={"timeout": timeout})
+
+    def get_step_artifact(self, run_id: str, path: str) -> bytes:
+        """Download a file collected by a step's artifacts patterns."""
+        return self._request("GET", "/api/runs/" + quote(str(run_id)) + "/artifacts/" + quote(str(path)), raw=True)
+
+    def update_test_status(self, run_id: str, test_id: str, body: Dict[str, Any]) -> Any:
+        """Report test status, steps and assertions. body follows the TestStatusUpdate schema."""
+        return self._request("PATCH", "/api/runs/" + quote(str(run_id)) + "/test/" + quote(str(test_id)), body=body)
+
+    def test_heartbeat(self, run_id: str, test_id: str) -> Any:
+        """Report that the runner executing a test is alive."""
+        return self._request("PUT", "/api/runs/" + quote(str(run_id)) + "/test/" + quote(str(test_id)) + "/heartbeat")
+
+    def cancel_test(self, run_id: str, test_id: str) -> Any:
+        """Cancel a single test and let the rest of the run continue."""
+        return self._request("POST", "/api/runs/" + quote(str(run_id)) + "/test/" + quote(str(test_id)) + "/cancel")
+
+    def complete_run(self, run_id: str, body: Optional[Dict[str, Any]] = None) -> Any:
+        """Finalize a run."""
+        return self._request("POST", "/api/runs/" + quote(str(run_id)) + "/complete", body=body)
+
+    def list_run_comments(self, run_id: str, *, test_id: Optional[str] = None) -> Any:
+        """List comments on a run and its tests, oldest first."""
+        return self._request("GET", "/api/runs/" + quote(str(run_id)) + "/comments", query={"test_id": test_id})
+
+    def create_run_comment(self, run_id: str, body: Dict[str, Any]) -> Any:
+        """Comment on a run, or on one of its tests."""
+        return self._request("POST", "/api/runs/" + quote(str(run_id)) + "/comments", body=body)
+
+    def delete_run_comment(self, run_id: str, comment_id: int) -> Any:
+        """Delete a comment."""
+        return self._request("DELETE", "/api/runs/" + quote(str(run_id)) + "/comments/" + quote(str(comment_id)))
+
+    def get_stats(self) -> Any:
+        """Aggregate statistics across runs."""
+        return self._request("GET", "/api/stats")
+
+    def get_assertion_stats(self, *, days: Optional[int] = None, limit: Optional[int] = None) -> Any:
+        """Most frequently failing assertion expressions."""
+        return self._request("GET", "/api/stats/assertions", query={"days": days, "limit": limit})
+
+    def get_coverage(self, *, run_id: Optional[str] = None, suite_id: Optional[int] = None) -> Any:
+        """mcp-mesh features covered by the tests of a run."""
+        return self._request("GET", "/api/coverage", query={"run_id": run_id, "suite_id": suite_id})
+
+    def emit_event(self, body: Dict[str, Any]) -> Any:
+        """Broadcast an event to SSE subscribers."""
+        return self._request("POST", "/api/events/emit", body=body)
@@ -0,0 +1,75 @@
+"""Thin client for the tsuite REST API (see internal/api/openapi.yaml)."""
+
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, Dict, Optional
+
+from ._api import Operations
+
+
+class TsuiteError(Exception):
+    """Raised when the API returns a non-2xx response."""
+
+    def __init__(self, status: int, message: str):
+        super().__init__(f"{status}: {message}")
+        self.status = status
+        self.message = message
+
+
+class TsuiteClient(Operations):
+    """Client for the tsuite API server.
+
+    The API methods are generated from the OpenAPI spec into _api.py; this
+    class only provides the HTTP transport.
+    """
+
+    def __init__(self, base_url: str = "http://localhost:9999", timeout: float = 30.0):
+        self.base_url = base_url.rstrip("/")
+        self.timeout = timeout
+
+    def _request(
+        self,
+        method: str,
+        path: str,
+        body: Optional[Dict[str, Any]] = None,
+        query: Optional[Dict[str, Any]] = None,
+        raw: bool = False,
+    ) -> Any:
+        url = self.base_url + path
+        if query:
+            params = {k: _query_value(v) for k, v in query.items() if v is not None}
+            if params:
+                url += "?" + urllib.parse.urlencode(params)
+
+        data = None
+        headers = {"Accept": "*/*" if raw else "application/json"}
+        if body is not None:
+            data = json.dumps(body).encode("utf-8")
+            headers["Content-Type"] = "application/json"
+
+        req = urllib.request.Request(url, data=data, headers=headers, method=method)
+        try:
+            with urllib.request.urlopen(req, timeout=self.timeout) as resp:
+                payload = resp.read()
+        except urllib.error.HTTPError as e:
+            raw_error = e.read().decode("utf-8", errors="replace")
+            try:
+                message = json.loads(raw_error).get("error", raw_error)
+            except ValueError:
+                message = raw_error
+            raise TsuiteError(e.code, message) from None
+
+        if raw:
+            return payload
+        if not payload:
+            return None
+        return json.loads(payload)
+
+
+def _query_value(value: Any) -> Any:
+    # The API parses booleans as Go does: "true" and "false"
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return value
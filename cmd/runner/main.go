@@ -7,19 +7,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/logging"
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
 )
 
 var (
-	version = "0.1.0-go"
+	// version is set at build time via ldflags: -ldflags "-X main.version=X.Y.Z"
+	version = "dev"
 
 	// Flags
 	testYamlPath string
@@ -30,6 +38,9 @@ var (
 	workdir      string
 	logDir       string
 	jsonOutput   bool
+	traceInterp  bool
+	updateSnaps  bool
+	logOpts      logging.Options
 )
 
 func main() {
@@ -51,6 +62,10 @@ It is typically invoked by the tsuite CLI, either directly or inside a container
 	rootCmd.Flags().StringVar(&workdir, "workdir", "", "Working directory for test execution")
 	rootCmd.Flags().StringVar(&logDir, "log-dir", "", "Directory for worker.log and mcp-mesh logs (env: TSUITE_LOG_DIR)")
 	rootCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output result as JSON to stdout")
+	rootCmd.Flags().BoolVar(&traceInterp, "trace-interpolation", false, "Log every ${...} resolution to worker.log (env: TSUITE_TRACE_INTERPOLATION=1)")
+	rootCmd.Flags().BoolVar(&updateSnaps, "update-snapshots", false, "Write captured values to the golden files of snapshot assertions (env: TSUITE_UPDATE_SNAPSHOTS=1)")
+	rootCmd.Flags().StringVar(&logOpts.Level, "log-level", "", "Diagnostic log level: debug, info, warn or error (env: TSUITE_LOG_LEVEL)")
+	rootCmd.Flags().StringVar(&logOpts.Format, "log-format", "", "Diagnostic log format on stderr: text or json (env: TSUITE_LOG_FORMAT)")
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -59,6 +74,10 @@ It is typically invoked by the tsuite CLI, either directly or inside a container
 }
 
 func runTest(cmd *cobra.Command, args []string) error {
+	if err := logging.Setup("runner", logOpts); err != nil {
+		return err
+	}
+
 	// Resolve configuration from flags and environment
 	if apiURL == "" {
 		apiURL = os.Getenv("TSUITE_API")
@@ -72,6 +91,12 @@ func runTest(cmd *cobra.Command, args []string) error {
 	if logDir == "" {
 		logDir = os.Getenv("TSUITE_LOG_DIR")
 	}
+	if !traceInterp {
+		traceInterp = os.Getenv("TSUITE_TRACE_INTERPOLATION") == "1"
+	}
+	if !updateSnaps {
+		updateSnaps = os.Getenv(runner.EnvUpdateSnapshots) == "1"
+	}
 
 	// Validate required parameters
 	if suitePath == "" {
@@ -113,7 +138,7 @@ func runTest(cmd *cobra.Command, args []string) error {
 		os.MkdirAll(logDir, 0755)
 		workerLog, err = NewWorkerLogger(logDir)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to create worker log: %v\n", err)
+			slog.Warn("Failed to create worker log", "log_dir", logDir, "error", err)
 		} else {
 			defer workerLog.Close()
 			workerLog.Log("=== Test Execution Started ===")
@@ -134,13 +159,6 @@ func runTest(cmd *cobra.Command, args []string) error {
 		apiClient = client.NewRunnerClient(apiURL, runID, testID)
 	}
 
-	// Report test is running
-	if apiClient != nil {
-		if err := apiClient.ReportTestRunning(); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to report test running: %v\n", err)
-		}
-	}
-
 	// Determine workdir
 	if workdir == "" {
 		workdir = filepath.Join(os.TempDir(), "tsuite_runner_"+strings.ReplaceAll(testID, "/", "_"))
@@ -157,6 +175,99 @@ func runTest(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Steps trust the CAs of the suite's proxy
+	if err := runner.UseCABundle(); err != nil {
+		if workerLog != nil {
+			workerLog.Log("ERROR: %v", err)
+		}
+		reportError(apiClient, err.Error())
+		return err
+	}
+
+	// Steps run with the suite's Node.js version
+	if err := testRunner.UseNodeVersion(); err != nil {
+		if workerLog != nil {
+			workerLog.Log("ERROR: %v", err)
+		}
+		reportError(apiClient, err.Error())
+		return err
+	}
+
+	// Report test is running, with the environment it runs in
+	env := testRunner.CaptureEnvironment(version)
+	if workerLog != nil {
+		workerLog.Log("Environment: %s/%s, runner %s, mode %s", env.OS, env.Arch, env.RunnerVersion, env.Mode)
+		if env.ImageDigest != "" {
+			workerLog.Log("Image: %s (%s)", env.Image, env.ImageDigest)
+		}
+		if env.NodeVersion != "" {
+			workerLog.Log("Node: %s", env.NodeVersion)
+		}
+	}
+	if apiClient != nil {
+		if err := apiClient.ReportTestRunning(env); err != nil {
+			slog.Warn("Failed to report test running", "test_id", testID, "error", err)
+		}
+	}
+
+	// Trace variable resolution into worker.log
+	if traceInterp {
+		if workerLog != nil {
+			testRunner.SetInterpolationTracer(func(res interpolate.Resolution) {
+				workerLog.Log("TRACE %s", res)
+			})
+		} else {
+			slog.Warn("--trace-interpolation needs --log-dir; tracing disabled")
+		}
+	}
+
+	testRunner.SetUpdateSnapshots(updateSnaps)
+	testRunner.SetParallel(os.Getenv(runner.EnvParallel) == "1")
+
+	// Processes the steps start inherit the run and test IDs, by which leak
+	// checks of parallel tests tell their own ports from other tests'
+	os.Setenv("TSUITE_RUN_ID", runID)
+	os.Setenv("TSUITE_TEST_ID", testID)
+
+	// Step artifacts are stored next to worker.log
+	if logDir != "" {
+		testRunner.SetArtifactDir(filepath.Join(logDir, "artifacts"))
+	}
+
+	// Stream step progress to the dashboard and keep the watchdog informed
+	// that this runner is alive
+	if apiClient != nil {
+		testRunner.SetStepObserver(apiClient)
+		stopHeartbeat := apiClient.StartHeartbeat(client.HeartbeatInterval)
+		defer stopHeartbeat()
+	}
+
+	// On SIGINT/SIGTERM, cancel the test so post_run still executes. A second
+	// signal, or post_run exceeding the grace period, force-kills managed
+	// processes and exits immediately.
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		slog.Info("Cancelling test (post_run will still run)", "test_id", testID, "signal", sig.String())
+		if workerLog != nil {
+			workerLog.Log("Received %s, cancelling test", sig)
+		}
+		testRunner.Cancel()
+
+		select {
+		case sig = <-signals:
+		case <-time.After(runner.CancelGracePeriod):
+		}
+		for _, released := range testRunner.Cleanup() {
+			fmt.Fprintln(os.Stderr, released)
+			if workerLog != nil {
+				workerLog.Log("Cleanup: %s", released)
+			}
+		}
+		os.Exit(128 + int(sig.(syscall.Signal)))
+	}()
+
 	result, err := testRunner.RunTest(testID)
 	if err != nil {
 		if workerLog != nil {
@@ -171,15 +282,31 @@ func runTest(cmd *cobra.Command, args []string) error {
 		workerLog.LogResult(result)
 	}
 
+	// Skipped by a skip_if condition
+	if result.Skipped {
+		if apiClient != nil {
+			if err := apiClient.ReportTestSkipped(result); err != nil {
+				slog.Warn("Failed to report test skipped", "test_id", testID, "error", err)
+			}
+		}
+		if jsonOutput {
+			jsonBytes, _ := json.MarshalIndent(convertResultToJSON(result), "", "  ")
+			fmt.Println(string(jsonBytes))
+		} else {
+			fmt.Printf("%s%s - %s\n", runner.SkippedPrefix, testID, result.SkipReason)
+		}
+		return nil
+	}
+
 	// Report result to API
 	if apiClient != nil {
 		if result.Passed {
 			if err := apiClient.ReportTestPassed(result); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to report test passed: %v\n", err)
+				slog.Warn("Failed to report test passed", "test_id", testID, "error", err)
 			}
 		} else {
 			if err := apiClient.ReportTestFailed(result); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to report test failed: %v\n", err)
+				slog.Warn("Failed to report test failed", "test_id", testID, "error", err)
 			}
 		}
 	}
@@ -223,6 +350,27 @@ func runTest(cmd *cobra.Command, args []string) error {
 		if len(result.Assertions) > 0 {
 			fmt.Printf("Assertions: %d passed, %d failed\n", assertionsPassed, assertionsFailed)
 		}
+
+		// Print assertion diffs, suggested fixes and leaks (collected by the CLI for its summary)
+		for _, assertion := range result.Assertions {
+			if !assertion.Passed && assertion.Diff != "" {
+				for _, line := range runner.FormatAssertionDiff(assertion.Expr, assertion.Diff) {
+					fmt.Println(line)
+				}
+			}
+		}
+		for _, suggestion := range result.Suggestions {
+			fmt.Println(runner.SuggestionPrefix + suggestion)
+		}
+		for _, leak := range result.Leaks.Lines() {
+			fmt.Println(runner.LeakPrefix + leak)
+		}
+		if result.BudgetExceeded() {
+			fmt.Println(runner.BudgetPrefix + runner.FormatBudgetViolation(result.Duration, result.DurationBudget))
+		}
+		for _, key := range slices.Sorted(maps.Keys(result.Recorded)) {
+			fmt.Println(runner.RecordedPrefix + key + "=" + result.Recorded[key])
+		}
 	}
 
 	// Exit with appropriate code
@@ -234,14 +382,19 @@ func runTest(cmd *cobra.Command, args []string) error {
 }
 
 func reportError(apiClient *client.RunnerClient, errMsg string) {
+	suggestions := runner.SuggestFixesForText(errMsg)
 	if apiClient != nil {
 		apiClient.ReportTestFailed(&runner.TestResult{
-			TestID: testID,
-			Passed: false,
-			Error:  errMsg,
+			TestID:      testID,
+			Passed:      false,
+			Error:       errMsg,
+			Suggestions: suggestions,
 		})
 	}
 	fmt.Fprintf(os.Stderr, "Error: %s\n", errMsg)
+	for _, suggestion := range suggestions {
+		fmt.Println(runner.SuggestionPrefix + suggestion)
+	}
 }
 
 // convertResultToJSON converts TestResult to a JSON-serializable map
@@ -258,6 +411,10 @@ func convertResultToJSON(result *runner.TestResult) map[string]any {
 			"stdout":    step.Stdout,
 			"stderr":    step.Stderr,
 			"error":     step.Error,
+			"artifacts": step.Artifacts,
+
+			"resolved_command": step.ResolvedCommand,
+			"resolved_params":  step.ResolvedParams,
 		}
 	}
 
@@ -270,6 +427,7 @@ func convertResultToJSON(result *runner.TestResult) map[string]any {
 			"passed":   assertion.Passed,
 			"actual":   assertion.Actual,
 			"expected": assertion.Expected,
+			"diff":     assertion.Diff,
 		}
 	}
 
@@ -285,15 +443,21 @@ func convertResultToJSON(result *runner.TestResult) map[string]any {
 	}
 
 	return map[string]any{
-		"test_id":      result.TestID,
-		"test_name":    result.TestName,
-		"passed":       result.Passed,
-		"error":        result.Error,
-		"duration_ms":  result.Duration.Milliseconds(),
-		"steps_passed": stepsPassed,
-		"steps_failed": stepsFailed,
-		"steps":        steps,
-		"assertions":   assertions,
+		"test_id":            result.TestID,
+		"test_name":          result.TestName,
+		"passed":             result.Passed,
+		"skipped":            result.Skipped,
+		"skip_reason":        result.SkipReason,
+		"error":              result.Error,
+		"duration_ms":        result.Duration.Milliseconds(),
+		"steps_passed":       stepsPassed,
+		"steps_failed":       stepsFailed,
+		"steps":              steps,
+		"assertions":         assertions,
+		"suggestions":        result.Suggestions,
+		"leaks":              result.Leaks,
+		"duration_budget_ms": result.DurationBudget.Milliseconds(),
+		"budget_exceeded":    result.BudgetExceeded(),
 	}
 }
 
@@ -338,7 +502,10 @@ func (w *WorkerLogger) Log(format string, args ...any) {
 // LogResult writes the test result to the log
 func (w *WorkerLogger) LogResult(result *runner.TestResult) {
 	w.Log("=== Test Result ===")
-	if result.Passed {
+	if result.Skipped {
+		w.Log("Status: SKIPPED")
+		w.Log("Reason: %s", result.SkipReason)
+	} else if result.Passed {
 		w.Log("Status: PASSED")
 	} else {
 		w.Log("Status: FAILED")
@@ -358,6 +525,9 @@ func (w *WorkerLogger) LogResult(result *runner.TestResult) {
 				status = "✗"
 			}
 			w.Log("[%s] %s: %s (%s)", status, step.Phase, step.Name, step.Handler)
+			if step.ResolvedCommand != "" {
+				w.Log("  command: %s", truncate(step.ResolvedCommand, 500))
+			}
 			if step.Stdout != "" {
 				w.Log("  stdout: %s", truncate(step.Stdout, 500))
 			}
@@ -386,10 +556,40 @@ func (w *WorkerLogger) LogResult(result *runner.TestResult) {
 			if !assertion.Passed {
 				w.Log("  actual: %s", assertion.Actual)
 				w.Log("  expected: %s", assertion.Expected)
+				if assertion.Diff != "" {
+					w.Log("  diff:")
+					for _, line := range strings.Split(assertion.Diff, "\n") {
+						w.Log("    %s", line)
+					}
+				}
 			}
 		}
 	}
 
+	// Log suggested fixes
+	if len(result.Suggestions) > 0 {
+		w.Log("")
+		w.Log("--- Suggested Fixes ---")
+		for _, suggestion := range result.Suggestions {
+			w.Log("- %s", suggestion)
+		}
+	}
+
+	// Log leaked resources
+	if leaks := result.Leaks.Lines(); len(leaks) > 0 {
+		w.Log("")
+		w.Log("--- Leaks ---")
+		for _, leak := range leaks {
+			w.Log("- %s", leak)
+		}
+	}
+
+	// Log duration budget violation
+	if result.BudgetExceeded() {
+		w.Log("")
+		w.Log("Budget exceeded: %s", runner.FormatBudgetViolation(result.Duration, result.DurationBudget))
+	}
+
 	w.Log("")
 	w.Log("=== Test Execution Completed ===")
 }
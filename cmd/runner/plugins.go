@@ -0,0 +1,11 @@
+package main
+
+// Compiled-in handlers.
+//
+// Downstream builds add custom step handlers by blank-importing packages that
+// call handlers.Register (from pkg/handlers) in their init function, e.g.:
+//
+//	import _ "example.com/tsuite-handlers/kafka"
+//
+// Keep local additions in a separate file (e.g. plugins_local.go) to avoid
+// merge conflicts with upstream.
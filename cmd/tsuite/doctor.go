@@ -0,0 +1,429 @@
+package main
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/orchestrator"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+// maxClockSkew is the largest difference between local and API server time
+// that doctor accepts. Heartbeats and durations are compared across the two.
+const maxClockSkew = 5 * time.Second
+
+// Doctor check outcomes
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+// doctorResult is the outcome of one doctor check
+type doctorResult struct {
+	name   string
+	status string
+	detail string
+	fix    string // How to resolve a warning or failure
+}
+
+// runDoctor checks for common misconfigurations and prints a fix for each problem
+func runDoctor(cmd *cobra.Command, args []string) error {
+	absPath, err := filepath.Abs(suitePath)
+	if err != nil {
+		return fmt.Errorf("invalid suite path: %w", err)
+	}
+
+	// The suite is optional: doctor also runs outside a suite directory
+	var suiteConfig *config.SuiteConfig
+	if _, err := os.Stat(filepath.Join(absPath, "config.yaml")); err == nil {
+		suiteConfig, err = config.LoadSuiteConfig(absPath)
+		if err != nil {
+			return fmt.Errorf("failed to load suite config: %w", err)
+		}
+	}
+	dockerMode := suiteConfig != nil && suiteConfig.Suite.Mode == "docker"
+
+	engine, engineErr := runner.DockerEngine()
+	healthResp, apiErr := fetchHealth(apiURL)
+
+	results := []doctorResult{
+		checkRunnerBinary(),
+		checkDockerRunnerBinary(suiteConfig, dockerMode),
+		checkContainerEngine(engine, engineErr, dockerMode),
+		checkImageJQ(suiteConfig, engine, dockerMode),
+		checkAPIReachable(healthResp, apiErr),
+		checkClockSkew(healthResp),
+		checkDatabase(),
+		checkPidFile(),
+	}
+
+	failures := 0
+	for _, r := range results {
+		symbol := map[string]string{doctorOK: "✓", doctorWarn: "!", doctorFail: "✗", doctorSkip: "-"}[r.status]
+		fmt.Printf("%s %s: %s\n", symbol, r.name, r.detail)
+		if r.fix != "" && (r.status == doctorWarn || r.status == doctorFail) {
+			fmt.Printf("    fix: %s\n", r.fix)
+		}
+		if r.status == doctorFail {
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d check(s) failed", failures)
+	}
+	return nil
+}
+
+// checkRunnerBinary verifies the runner used in standalone mode exists and
+// matches this machine's platform
+func checkRunnerBinary() doctorResult {
+	r := doctorResult{name: "runner binary"}
+	path := orchestrator.FindRunnerBinary(runnerPath)
+	if path == "" {
+		r.status = doctorFail
+		r.detail = "tsuite-runner not found next to tsuite, embedded in tsuite or in ./bin"
+		r.fix = "build it with 'make build-runner' (or tsuite with 'make build-embedded'), or pass --runner-path"
+		return r
+	}
+
+	goos, goarch, err := binaryPlatform(path)
+	if err != nil {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("%s: %v", path, err)
+		r.fix = "rebuild it with 'make build-runner'"
+		return r
+	}
+	if goos != runtime.GOOS || goarch != runtime.GOARCH {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("%s is built for %s/%s, this machine is %s/%s", path, goos, goarch, runtime.GOOS, runtime.GOARCH)
+		r.fix = "rebuild it with 'make build-runner'"
+		return r
+	}
+	if !checkRunnerVersion(&r, path, doctorFail) {
+		return r
+	}
+
+	r.status = doctorOK
+	r.detail = fmt.Sprintf("%s (%s/%s, %s)", path, goos, goarch, r.detail)
+	return r
+}
+
+// checkDockerRunnerBinary verifies the Linux runner mounted into test
+// containers exists and matches the architecture of the suite's base image
+// (or, outside a docker-mode suite, the container engine's)
+func checkDockerRunnerBinary(suiteConfig *config.SuiteConfig, dockerMode bool) doctorResult {
+	r := doctorResult{name: "docker runner binary"}
+	failStatus := doctorWarn
+	var image string
+	if dockerMode {
+		failStatus = doctorFail
+		if image = suiteConfig.Docker.BaseImage; image == "" {
+			image = "tsuite-mesh:local"
+		}
+	}
+
+	path, wantArch, err := runner.DockerRunnerBinary(image)
+	if err != nil {
+		r.status = failStatus
+		r.detail = fmt.Sprintf("no tsuite-runner-linux-%s next to tsuite or embedded in tsuite (needed for docker mode)", wantArch)
+		r.fix = fmt.Sprintf("build it with 'make build-runner-linux RUNNER_ARCH=%s' (or tsuite with 'make build-embedded'), or run 'tsuite runner install'", wantArch)
+		return r
+	}
+
+	goos, goarch, err := binaryPlatform(path)
+	if err != nil {
+		r.status = failStatus
+		r.detail = fmt.Sprintf("%s: %v", path, err)
+		r.fix = fmt.Sprintf("rebuild it with 'make build-runner-linux RUNNER_ARCH=%s'", wantArch)
+		return r
+	}
+	if goos != "linux" || goarch != wantArch {
+		r.status = failStatus
+		r.detail = fmt.Sprintf("%s is built for %s/%s, containers run linux/%s", path, goos, goarch, wantArch)
+		r.fix = fmt.Sprintf("rebuild it with 'GOOS=linux GOARCH=%s go build -o %s ./cmd/runner'", wantArch, path)
+		return r
+	}
+	if !checkRunnerVersion(&r, path, failStatus) {
+		return r
+	}
+
+	r.status = doctorOK
+	r.detail = fmt.Sprintf("%s (%s/%s, %s)", path, goos, goarch, r.detail)
+	return r
+}
+
+// checkRunnerVersion verifies a runner was built for this tsuite version. On
+// success it leaves the runner's version in r.detail.
+func checkRunnerVersion(r *doctorResult, path string, failStatus string) bool {
+	info, err := runner.InspectRunner(path)
+	if err != nil {
+		r.status = failStatus
+		r.detail = err.Error()
+		r.fix = "install the matching runner with 'tsuite runner install'"
+		return false
+	}
+	if err := info.CheckVersion(version); err != nil {
+		r.status = failStatus
+		r.detail = fmt.Sprintf("%s is version %s, tsuite is %s", path, info.Version, version)
+		r.fix = "install the matching runner with 'tsuite runner install'"
+		return false
+	}
+	r.detail = "version " + info.Version
+	return true
+}
+
+// checkContainerEngine verifies Docker (or Podman's Docker API) is reachable
+func checkContainerEngine(engine *runner.EngineInfo, engineErr error, dockerMode bool) doctorResult {
+	r := doctorResult{name: "container engine"}
+	if engineErr == nil {
+		r.detail = fmt.Sprintf("%s %s (%s/%s)", engine.Name, engine.Version, engine.OS, engine.Arch)
+		if runner.IsRemoteDockerHost(engine.Host) && os.Getenv(runner.EnvDockerSharedPaths) != "1" {
+			r.status = doctorWarn
+			if dockerMode {
+				r.status = doctorFail
+			}
+			r.detail = fmt.Sprintf("%s at remote daemon %s; docker mode bind-mounts local paths the daemon cannot see", r.detail, engine.Host)
+			r.fix = fmt.Sprintf("run tsuite on the Docker host or point DOCKER_HOST at a local daemon; set %s=1 if the daemon sees the same paths", runner.EnvDockerSharedPaths)
+			return r
+		}
+		r.status = doctorOK
+		return r
+	}
+
+	r.status = doctorWarn
+	if dockerMode {
+		r.status = doctorFail
+	}
+	r.detail = fmt.Sprintf("not available: %v", engineErr)
+	if _, err := exec.LookPath("podman"); err == nil {
+		r.fix = "podman is installed: start its Docker-compatible socket with 'systemctl --user enable --now podman.socket' " +
+			"and export DOCKER_HOST=unix://$XDG_RUNTIME_DIR/podman/podman.sock"
+	} else {
+		r.fix = "start Docker (or Docker Desktop), or check DOCKER_HOST / 'docker context use'"
+	}
+	return r
+}
+
+// checkImageJQ verifies the suite's base image has jq, which assertions and
+// JSON captures use inside the container
+func checkImageJQ(suiteConfig *config.SuiteConfig, engine *runner.EngineInfo, dockerMode bool) doctorResult {
+	r := doctorResult{name: "jq in base image", status: doctorSkip}
+	if !dockerMode {
+		r.detail = "suite is not in docker mode"
+		return r
+	}
+	if engine == nil {
+		r.detail = "container engine not available"
+		return r
+	}
+
+	image := suiteConfig.Docker.BaseImage
+	if image == "" {
+		image = "tsuite-mesh:local"
+	}
+	ok, err := runner.ImageHasCommand(image, "jq")
+	if err != nil {
+		r.status = doctorFail
+		r.detail = err.Error()
+		r.fix = fmt.Sprintf("build or pull %s, or set docker.base_image in config.yaml", image)
+		return r
+	}
+	if !ok {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("jq not found in %s", image)
+		r.fix = "add jq to the image, e.g. 'RUN apt-get update && apt-get install -y jq' in its Dockerfile"
+		return r
+	}
+
+	r.status = doctorOK
+	r.detail = image
+	return r
+}
+
+// fetchHealth calls the API server's health endpoint
+func fetchHealth(url string) (*http.Response, error) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	resp, err := httpClient.Get(strings.TrimSuffix(url, "/") + "/health")
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET /health returned %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// checkAPIReachable reports whether the API server answered /health and is
+// ready to record runs (/readyz)
+func checkAPIReachable(resp *http.Response, err error) doctorResult {
+	r := doctorResult{name: "API server"}
+	if err != nil {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("%s not reachable: %v", apiURL, err)
+		r.fix = "start it with 'tsuite api --detach', or pass --api-url"
+		return r
+	}
+	var notReady *client.NotReadyError
+	if errors.As(client.NewClient(apiURL).HealthCheck(), &notReady) {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("%s %v", apiURL, notReady)
+		r.fix = "see the server's log; results are not saved until it is ready"
+		return r
+	}
+	r.status = doctorOK
+	r.detail = apiURL
+	return r
+}
+
+// checkClockSkew compares local time with the API server's Date header
+func checkClockSkew(resp *http.Response) doctorResult {
+	r := doctorResult{name: "clock skew", status: doctorSkip}
+	if resp == nil {
+		r.detail = "API server not reachable"
+		return r
+	}
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		r.detail = "API server sent no Date header"
+		return r
+	}
+
+	skew := time.Since(serverTime).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	// Date has one-second resolution, so allow for the truncation
+	if skew > maxClockSkew+time.Second {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("local clock differs from the API server by %s", skew)
+		r.fix = "sync both machines with NTP (e.g. 'timedatectl set-ntp true'); skew breaks heartbeats and stale-test detection"
+		return r
+	}
+
+	r.status = doctorOK
+	r.detail = fmt.Sprintf("within %s of the API server", maxClockSkew)
+	return r
+}
+
+// checkDatabase runs SQLite's integrity check on the results database
+func checkDatabase() doctorResult {
+	r := doctorResult{name: "database"}
+	path := db.DefaultDBPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		r.status = doctorSkip
+		r.detail = fmt.Sprintf("%s not created yet", path)
+		return r
+	}
+
+	problems, err := db.IntegrityCheck(path)
+	if err != nil {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("%s: %v", path, err)
+		r.fix = "check the file's permissions, or move it aside and let 'tsuite api' create a new one"
+		return r
+	}
+	if len(problems) > 0 {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("%s is corrupt: %s", path, strings.Join(problems, "; "))
+		r.fix = "stop the server ('tsuite stop'), back up the file and recover it with 'sqlite3 results.db .recover', or reset with 'tsuite clear --all'"
+		return r
+	}
+
+	r.status = doctorOK
+	r.detail = fmt.Sprintf("%s passed integrity_check", path)
+	return r
+}
+
+// checkPidFile reports a server PID file whose process is gone. Unlike
+// isServerRunning, it leaves the file in place.
+func checkPidFile() doctorResult {
+	r := doctorResult{name: "PID file"}
+	pidFile := getPidFile()
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		r.status = doctorOK
+		r.detail = "no server PID file"
+		return r
+	}
+
+	var pid int
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(data)), "%d", &pid); err != nil {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("%s does not contain a PID", pidFile)
+		r.fix = fmt.Sprintf("remove it: rm %s", pidFile)
+		return r
+	}
+
+	process, err := os.FindProcess(pid)
+	if err == nil {
+		err = process.Signal(syscall.Signal(0))
+	}
+	if err != nil {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("%s points to PID %d, which is not running", pidFile, pid)
+		r.fix = fmt.Sprintf("remove it: rm %s (or run 'tsuite stop')", pidFile)
+		return r
+	}
+
+	r.status = doctorOK
+	r.detail = fmt.Sprintf("server running (PID %d)", pid)
+	return r
+}
+
+// binaryPlatform reads the target OS and architecture from an executable's header
+func binaryPlatform(path string) (goos, goarch string, err error) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		arch := map[elf.Machine]string{
+			elf.EM_X86_64:  "amd64",
+			elf.EM_AARCH64: "arm64",
+			elf.EM_386:     "386",
+			elf.EM_ARM:     "arm",
+		}[f.Machine]
+		return "linux", orUnknown(arch, f.Machine.String()), nil
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		arch := map[macho.Cpu]string{
+			macho.CpuAmd64: "amd64",
+			macho.CpuArm64: "arm64",
+		}[f.Cpu]
+		return "darwin", orUnknown(arch, f.Cpu.String()), nil
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		arch := map[uint16]string{
+			pe.IMAGE_FILE_MACHINE_AMD64: "amd64",
+			pe.IMAGE_FILE_MACHINE_ARM64: "arm64",
+		}[f.Machine]
+		return "windows", orUnknown(arch, fmt.Sprintf("machine 0x%x", f.Machine)), nil
+	}
+	return "", "", fmt.Errorf("not a recognized executable")
+}
+
+// orUnknown returns value, or a description of an unrecognized architecture
+func orUnknown(value, raw string) string {
+	if value != "" {
+		return value
+	}
+	return "unknown (" + raw + ")"
+}
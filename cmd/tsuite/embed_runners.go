@@ -0,0 +1,20 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+)
+
+//go:embed runners/*
+var runnersFS embed.FS
+
+func init() {
+	// The embed directive requires the directory to exist at build time, but
+	// runners are only used if make build-embedded put any there
+	matches, _ := fs.Glob(runnersFS, "runners/tsuite-runner-*")
+	if len(matches) > 0 {
+		runner.EmbeddedRunners, _ = fs.Sub(runnersFS, "runners")
+	}
+}
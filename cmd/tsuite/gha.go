@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/orchestrator"
+)
+
+// ghaSlowest is the number of slowest tests listed in the job summary
+const ghaSlowest = 10
+
+// =============================================================================
+// GitHub Actions Job Summary (tsuite run --gha-summary)
+// =============================================================================
+
+// writeGHASummary appends a Markdown summary of the run to
+// $GITHUB_STEP_SUMMARY (stdout outside GitHub Actions) and prints an
+// ::error annotation on test.yaml for each failed assertion, or for the
+// failed test when it has none.
+func writeGHASummary(r *orchestrator.Run, result *orchestrator.Result) error {
+	var tests []client.RunTest
+	var apiClient *client.Client
+	if result.RunID != "" {
+		apiClient = client.NewClient(apiURL)
+		var err error
+		if tests, err = apiClient.GetRunTests(result.RunID); err != nil {
+			slog.Warn("Job summary without test details", "error", err)
+		}
+	}
+
+	var b strings.Builder
+	status := "✅ Passed"
+	switch {
+	case result.Cancelled:
+		status = "⏹ Cancelled"
+	case result.Failed > 0:
+		status = "❌ Failed"
+	}
+	fmt.Fprintf(&b, "## tsuite: %s\n\n", r.Config.Suite.Name)
+	fmt.Fprintf(&b, "**%s**: %d passed, %d failed, %d skipped in %s", status,
+		result.Passed, result.Failed, result.Skipped, result.Duration.Round(time.Second))
+	if link := ghaRunLink(result.RunID); link != "" {
+		fmt.Fprintf(&b, " · %s", link)
+	}
+	b.WriteString("\n\n")
+
+	if len(result.FailedTests) > 0 {
+		errors := make(map[string]string, len(tests))
+		for _, t := range tests {
+			errors[t.TestID] = t.ErrorMessage
+		}
+		b.WriteString("### Failed tests\n\n| Test | Owners | Error |\n|------|--------|-------|\n")
+		for _, testID := range result.FailedTests {
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", testID,
+				markdownCell(strings.Join(result.FailureOwners[testID], ", ")), markdownCell(firstLine(errors[testID])))
+		}
+		b.WriteString("\n")
+	}
+
+	var timed []client.RunTest
+	for _, t := range tests {
+		if t.DurationMS != nil {
+			timed = append(timed, t)
+		}
+	}
+	sort.SliceStable(timed, func(i, j int) bool { return *timed[i].DurationMS > *timed[j].DurationMS })
+	if len(timed) > 0 {
+		b.WriteString("### Slowest tests\n\n| Test | Status | Duration |\n|------|--------|----------|\n")
+		for _, t := range timed[:min(len(timed), ghaSlowest)] {
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", t.TestID, t.Status,
+				(time.Duration(*t.DurationMS) * time.Millisecond).Round(100*time.Millisecond))
+		}
+		b.WriteString("\n")
+	}
+
+	if err := appendStepSummary(b.String()); err != nil {
+		return err
+	}
+
+	for _, testID := range result.FailedTests {
+		annotateFailedTest(os.Stdout, r.SuitePath, apiClient, result.RunID, testID)
+	}
+	return nil
+}
+
+// appendStepSummary appends Markdown to the file named by
+// $GITHUB_STEP_SUMMARY, or prints it outside GitHub Actions
+func appendStepSummary(markdown string) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		fmt.Println("\nGITHUB_STEP_SUMMARY is not set; job summary:")
+		fmt.Print(markdown)
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write job summary: %w", err)
+	}
+	defer f.Close()
+	_, err = f.WriteString(markdown)
+	return err
+}
+
+// annotateFailedTest prints an ::error workflow command for each failed
+// assertion of the test, or a single one for the test
+func annotateFailedTest(w io.Writer, suitePath string, apiClient *client.Client, runID, testID string) {
+	file := filepath.Join(suitePath, "suites", testID, "test.yaml")
+	if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
+		if rel, err := filepath.Rel(workspace, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+
+	var messages []string
+	if apiClient != nil {
+		assertions, err := apiClient.GetTestAssertions(runID, testID)
+		if err != nil {
+			slog.Debug("No assertions for annotation", "test_id", testID, "error", err)
+		}
+		for _, a := range assertions {
+			if a.Passed {
+				continue
+			}
+			msg := "Assertion failed: " + a.Expression
+			if a.Message != "" {
+				msg += "\n" + a.Message
+			}
+			if a.Expected != "" || a.Actual != "" {
+				msg += fmt.Sprintf("\nexpected: %s\nactual:   %s", a.Expected, a.Actual)
+			}
+			messages = append(messages, msg)
+		}
+	}
+	if len(messages) == 0 {
+		messages = []string{"Test failed"}
+	}
+	for _, msg := range messages {
+		fmt.Fprintf(w, "::error file=%s,title=%s::%s\n",
+			escapeWorkflowProperty(filepath.ToSlash(file)), escapeWorkflowProperty("tsuite: "+testID), escapeWorkflowData(msg))
+	}
+}
+
+// ghaRunLink links the run in the dashboard of a non-local API server
+func ghaRunLink(runID string) string {
+	if runID == "" {
+		return ""
+	}
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1" {
+		return fmt.Sprintf("run `%s`", runID[:12])
+	}
+	return fmt.Sprintf("[run %s](%s/runs?id=%s)", runID[:12], strings.TrimSuffix(apiURL, "/"), runID)
+}
+
+// markdownCell makes text safe inside a Markdown table cell
+func markdownCell(s string) string {
+	if s == "" {
+		return "-"
+	}
+	s = strings.ReplaceAll(s, "|", "\\|")
+	if len(s) > 200 {
+		s = s[:200] + "…"
+	}
+	return s
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+// escapeWorkflowData escapes the message of a workflow command
+func escapeWorkflowData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeWorkflowProperty escapes a property value of a workflow command
+func escapeWorkflowProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -6,22 +6,26 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"sort"
 	"strings"
-	"sync"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/api"
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/diskspace"
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/executor"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/logging"
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/man"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/orchestrator"
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/scaffold"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/selfupdate"
 )
 
 var (
@@ -31,246 +35,37 @@ var (
 
 // Run command flags
 var (
-	suitePath  string
-	parallel   int
-	ucFilter   []string
-	tcFilter   []string
-	tagFilter  []string
-	dryRun     bool
-	apiURL     string
-	runnerPath string
+	suitePath     string
+	parallel      int
+	ucFilter      []string
+	tcFilter      []string
+	tagFilter     []string
+	skipTagFilter []string
+	modeOverride  string        // docker or standalone instead of the suite's mode
+	profileName   string        // Run profile from config.yaml
+	envOverrides  []string      // KEY=VALUE set for every test
+	publishPorts  []string      // Container ports published to the host (docker mode)
+	keepFailed    time.Duration // Keep failed tests' containers running (docker mode)
+	keepWorkdir   bool          // Keep failed tests' workdirs
+	failFast      bool          // Start no more tests after a failure
+	smoke         bool          // Only run smoke tests, within smoke.yaml's time budget
+	changedSince  string        // Only run tests affected by git changes since this ref
+	dryRun        bool
+	explain       bool
+	ghaSummary    bool   // Write a GitHub Actions job summary and annotations
+	updateSnaps   bool   // Rewrite golden files of snapshot assertions
+	nodeVersion   string // Node.js version instead of packages.node_version
+	fromManifest  string // Run manifest whose versions and tests to reproduce
+	offline       bool   // Forbid fetching from the internet
+	apiURL        string
+	runnerPath    string
+	parentRunID   string // Run the new run is a rerun of
+	pipelineRunID string // Pipeline run the new run is part of
+	presetRunID   string // Run ID reserved by the API that launched the run
 )
 
-// findRunnerBinary finds the tsuite-runner binary
-// It looks for the runner binary in the following locations:
-// 1. Explicit path via --runner-path flag
-// 2. Same directory as the current executable
-// 3. Current working directory
-// Returns the path to the runner binary, or empty string if not found
-func findRunnerBinary() string {
-	if runnerPath != "" {
-		if _, err := os.Stat(runnerPath); err == nil {
-			return runnerPath
-		}
-	}
-
-	// Get current executable's directory
-	execPath, err := os.Executable()
-	if err == nil {
-		execPath, _ = filepath.EvalSymlinks(execPath)
-		execDir := filepath.Dir(execPath)
-
-		// Look for tsuite-runner in the same directory
-		candidates := []string{
-			filepath.Join(execDir, "tsuite-runner"),
-			filepath.Join(execDir, fmt.Sprintf("tsuite-runner-%s-%s", runtime.GOOS, runtime.GOARCH)),
-		}
-
-		for _, path := range candidates {
-			if _, err := os.Stat(path); err == nil {
-				return path
-			}
-		}
-	}
-
-	// Look in current working directory
-	cwd, err := os.Getwd()
-	if err == nil {
-		candidates := []string{
-			filepath.Join(cwd, "bin", "tsuite-runner"),
-			filepath.Join(cwd, "tsuite-runner"),
-		}
-		for _, path := range candidates {
-			if _, err := os.Stat(path); err == nil {
-				return path
-			}
-		}
-	}
-
-	return ""
-}
-
-// runTestWithRunner executes a single test using the external runner binary.
-// The runner reports results directly to the API, so we just need to wait for completion.
-// Returns: passed, error string, duration, cancelled
-func runTestWithRunner(ctx context.Context, runnerBinary, suitePath, testID, apiURL, runID, baseWorkdir string, timeout time.Duration) (bool, string, time.Duration, bool) {
-	startTime := time.Now()
-
-	// Check if already cancelled
-	select {
-	case <-ctx.Done():
-		return false, "cancelled", 0, true
-	default:
-	}
-
-	// Build command arguments
-	args := []string{
-		"--suite-path", suitePath,
-		"--test-id", testID,
-	}
-	if apiURL != "" {
-		args = append(args, "--api-url", apiURL)
-	}
-	if runID != "" {
-		args = append(args, "--run-id", runID)
-
-		// Set log directory for unified logging (standalone mode)
-		// Structure: ~/.tsuite/runs/{run_id}/{uc}/{tc}/
-		parts := strings.SplitN(testID, "/", 2)
-		if len(parts) == 2 {
-			logDir := filepath.Join(os.Getenv("HOME"), ".tsuite", "runs", runID, parts[0], parts[1])
-			os.MkdirAll(logDir, 0755)
-			args = append(args, "--log-dir", logDir)
-		}
-	}
-	if baseWorkdir != "" {
-		testWorkdir := filepath.Join(baseWorkdir, strings.ReplaceAll(testID, "/", "_"))
-		os.MkdirAll(testWorkdir, 0755)
-		args = append(args, "--workdir", testWorkdir)
-	}
-
-	// Create command with combined timeout and cancellation context
-	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, timeout)
-	defer timeoutCancel()
-
-	cmd := exec.CommandContext(timeoutCtx, runnerBinary, args...)
-	cmd.Env = os.Environ()
-	// Set process group so we can kill the whole tree
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-
-	// Capture output
-	output, err := cmd.CombinedOutput()
-	duration := time.Since(startTime)
-
-	// Check if cancelled (parent context)
-	if ctx.Err() == context.Canceled {
-		return false, "cancelled", duration, true
-	}
-
-	if timeoutCtx.Err() == context.DeadlineExceeded {
-		return false, "test timed out", duration, false
-	}
-
-	if err != nil {
-		if _, ok := err.(*exec.ExitError); ok {
-			// Runner exited with non-zero status (test failed)
-			// Extract error from output
-			errMsg := "test failed"
-			lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-			for _, line := range lines {
-				if strings.HasPrefix(line, "FAILED:") {
-					errMsg = strings.TrimPrefix(line, "FAILED: ")
-					break
-				}
-				if strings.HasPrefix(line, "Error:") {
-					errMsg = strings.TrimPrefix(line, "Error: ")
-					break
-				}
-			}
-			if errMsg == "test failed" && len(lines) > 0 {
-				// Use last line as error
-				errMsg = lines[len(lines)-1]
-			}
-			return false, errMsg, duration, false
-		}
-		return false, fmt.Sprintf("runner error: %v", err), duration, false
-	}
-
-	return true, "", duration, false
-}
-
-// runTestsWithRunnerSequential runs tests sequentially using the external runner binary
-// Returns: passed, failed, skipped, failedTests, cancelled
-func runTestsWithRunnerSequential(ctx context.Context, cancelFunc context.CancelFunc, runnerBinary, suitePath string, tests []string, apiURL, runID, baseWorkdir string, timeout time.Duration) (passed, failed, skipped int, failedTests []string, cancelled bool) {
-	apiClient := client.NewClient(apiURL)
-
-	// Start cancel checker goroutine
-	executor.StartCancelChecker(ctx, cancelFunc, apiClient, runID)
-
-	for _, testID := range tests {
-		// Check if cancelled before starting test
-		select {
-		case <-ctx.Done():
-			fmt.Printf("[SKIP] %s (cancelled)\n", testID)
-			skipped++
-			cancelled = true
-			continue
-		default:
-		}
-
-		fmt.Printf("\n[RUN] %s\n", testID)
-
-		testPassed, testError, duration, wasCancelled := runTestWithRunner(ctx, runnerBinary, suitePath, testID, apiURL, runID, baseWorkdir, timeout)
-
-		if wasCancelled {
-			fmt.Printf("[SKIP] %s (cancelled)\n", testID)
-			skipped++
-			cancelled = true
-		} else if testPassed {
-			fmt.Printf("[PASS] %s (%.1fs)\n", testID, duration.Seconds())
-			passed++
-		} else {
-			fmt.Printf("[FAIL] %s - %s (%.1fs)\n", testID, testError, duration.Seconds())
-			failed++
-			failedTests = append(failedTests, testID)
-		}
-	}
-	return
-}
-
-// runTestsWithRunnerParallel runs tests in parallel using the external runner binary
-// Returns: passed, failed, skipped, failedTests, cancelled
-func runTestsWithRunnerParallel(ctx context.Context, cancelFunc context.CancelFunc, runnerBinary, suitePath string, tests []string, workers int, apiURL, runID, baseWorkdir string, timeout time.Duration) (passed, failed, skipped int, failedTests []string, cancelled bool) {
-	testCh := make(chan string, len(tests))
-	resultCh := make(chan executor.TestResult, len(tests))
-	apiClient := client.NewClient(apiURL)
-
-	// Start cancel checker goroutine
-	executor.StartCancelChecker(ctx, cancelFunc, apiClient, runID)
-
-	// Start workers
-	var wg sync.WaitGroup
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for testID := range testCh {
-				// Check if cancelled before starting test
-				select {
-				case <-ctx.Done():
-					resultCh <- executor.TestResult{TestID: testID, Cancelled: true}
-					continue
-				default:
-				}
-
-				testPassed, testError, duration, wasCancelled := runTestWithRunner(ctx, runnerBinary, suitePath, testID, apiURL, runID, baseWorkdir, timeout)
-				resultCh <- executor.TestResult{
-					TestID:    testID,
-					Passed:    testPassed,
-					Error:     testError,
-					Duration:  duration,
-					Cancelled: wasCancelled,
-				}
-			}
-		}()
-	}
-
-	// Send tests to workers
-	for _, t := range tests {
-		testCh <- t
-	}
-	close(testCh)
-
-	// Wait for all workers
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
-
-	// Collect results
-	results := executor.CollectResults(resultCh)
-	return results.Passed, results.Failed, results.Skipped, results.FailedTests, results.Cancelled
-}
+// Diagnostic logging flags (see internal/logging)
+var logOpts logging.Options
 
 func main() {
 	rootCmd := &cobra.Command{
@@ -280,7 +75,16 @@ func main() {
 
 Features: embedded dashboard UI, Docker/standalone modes for isolation, parallel test execution.`,
 		Version: version,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			component := "cli"
+			if cmd.Name() == "api" {
+				component = "api"
+			}
+			return logging.Setup(component, logOpts)
+		},
 	}
+	rootCmd.PersistentFlags().StringVar(&logOpts.Level, "log-level", "", "Diagnostic log level: debug, info, warn or error (env: TSUITE_LOG_LEVEL, default info)")
+	rootCmd.PersistentFlags().StringVar(&logOpts.Format, "log-format", "", "Diagnostic log format on stderr: text or json (env: TSUITE_LOG_FORMAT, default text)")
 
 	// API command
 	apiCmd := &cobra.Command{
@@ -293,6 +97,24 @@ Features: embedded dashboard UI, Docker/standalone modes for isolation, parallel
 	var apiPort int
 	apiCmd.Flags().IntVarP(&apiPort, "port", "p", 9999, "Server port")
 	apiCmd.Flags().BoolP("detach", "d", false, "Run server in background")
+	apiCmd.Flags().Int("sse-buffer", 100, "Per-client SSE event buffer size")
+	apiCmd.Flags().Duration("sse-heartbeat", 15*time.Second, "Interval between SSE heartbeats")
+	apiCmd.Flags().String("sse-drop-policy", "progress", "Policy when a slow client's buffer is full: progress (never drop terminal run events) or all")
+	apiCmd.Flags().Duration("sse-retry", 3*time.Second, "Delay before SSE clients reconnect after the stream drops")
+	apiCmd.Flags().StringSlice("cors-origin", api.DefaultCORSOrigins, "Origin allowed to call the API from browsers, e.g. https://portal.example.com or https://*.example.com (repeatable; * = any, without credentials)")
+	apiCmd.Flags().Int("max-body-mb", 32, "Maximum request body size in MB (after gzip decompression)")
+	apiCmd.Flags().Duration("stale-after", api.DefaultStaleAfter, "Mark running tests crashed after this long without a runner heartbeat (0 = disabled)")
+	apiCmd.Flags().Int("max-runs-per-suite", api.DefaultMaxRunsPerSuite, "Runs of one suite started at once from the API; more are queued (config.yaml execution.max_concurrent_runs overrides)")
+	apiCmd.Flags().Bool("in-process", false, "Run tests launched from the API in the server process instead of a tsuite run subprocess")
+	apiCmd.Flags().Int("min-free-disk-mb", diskspace.DefaultMinFreeMB, "Warn running runs over SSE when free disk space drops below this many MB (0 = disabled)")
+	apiCmd.Flags().String("title", "", "Dashboard title, to tell deployments apart (default tsuite)")
+	apiCmd.Flags().String("logo", "", "SVG, PNG, JPEG, GIF or ICO file shown as the dashboard logo")
+	apiCmd.Flags().String("accent", "", "Hex color of the dashboard's highlights, e.g. #a855f7")
+	apiCmd.Flags().String("base-path", "", "Serve the API and dashboard under this path (e.g. /tsuite) behind a shared ingress")
+	apiCmd.Flags().String("tls-cert", "", "Serve HTTPS with this PEM certificate (requires --tls-key)")
+	apiCmd.Flags().String("tls-key", "", "PEM private key of --tls-cert")
+	apiCmd.Flags().Duration("db-timeout", db.DefaultQueryTimeout, "Give up on a results database call after this long")
+	apiCmd.Flags().String("secondary-db", "", "Mirror every results write to this database and log divergences, as sqlite:<path> or postgres:<url>")
 
 	rootCmd.AddCommand(apiCmd)
 
@@ -309,12 +131,55 @@ Features: embedded dashboard UI, Docker/standalone modes for isolation, parallel
 	runCmd.Flags().StringSliceVar(&ucFilter, "uc", nil, "Filter by use case (e.g., uc01_registry)")
 	runCmd.Flags().StringSliceVar(&tcFilter, "tc", nil, "Filter by test case (e.g., tc01_agent_registration)")
 	runCmd.Flags().StringSliceVar(&tagFilter, "tags", nil, "Filter by tags")
+	runCmd.Flags().StringSliceVar(&skipTagFilter, "skip-tags", nil, "Skip tests with any of these tags")
+	runCmd.Flags().StringVar(&modeOverride, "mode", "", "Execution mode instead of the suite's: docker or standalone")
+	runCmd.Flags().StringVar(&profileName, "profile", "", "Apply a run profile from config.yaml (profiles:)")
+	runCmd.Flags().StringArrayVar(&envOverrides, "env", nil, "Set an environment variable for every test (KEY=VALUE, repeatable)")
+	runCmd.Flags().StringSliceVar(&publishPorts, "publish", nil, "Publish a container port to the host in docker mode (e.g. 8000, 9000/udp, 8080:8000; repeatable)")
+	runCmd.Flags().DurationVar(&keepFailed, "keep-failed", 0, "Keep a failed test's container running this long for inspection in docker mode (e.g. 10m)")
+	runCmd.Flags().BoolVar(&keepWorkdir, "keep-workdir", false, "Keep failed tests' workdirs under ~/.tsuite/runs (default: execution.keep_failed_workdirs)")
+	runCmd.Flags().BoolVar(&smoke, "smoke", false, "Only run tests tagged smoke or listed in smoke.yaml, within its time_budget (default 3m)")
+	runCmd.Flags().StringVar(&changedSince, "changed-since", "", "Only run tests affected by files changed in git since this ref (e.g. origin/main)")
+	runCmd.Flags().BoolVar(&failFast, "fail-fast", false, "Start no more tests once a test failed (running tests finish)")
+	runCmd.Flags().StringVar(&nodeVersion, "node-version", "", "Run the tests with this Node.js version instead of packages.node_version (e.g. 22)")
+	runCmd.Flags().StringVar(&fromManifest, "from-manifest", "", "Reproduce an earlier run: pin the package versions, image and tests of its run-manifest.json")
+	runCmd.Flags().BoolVar(&offline, "offline", false, "Forbid fetching from the internet: pip and npm installs use packages.mirror, steps that need the internet fail right away")
+	runCmd.Flags().BoolVar(&updateSnaps, "update-snapshots", false, "Write captured values to the golden files of snapshot assertions instead of comparing them")
+	runCmd.Flags().BoolVar(&ghaSummary, "gha-summary", false, "Write a Markdown summary to $GITHUB_STEP_SUMMARY and ::error annotations for failed assertions")
 	runCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List tests without running")
+	runCmd.Flags().BoolVar(&explain, "explain", false, "Dry run that prints each test's steps with routines expanded and variables interpolated")
 	runCmd.Flags().StringVar(&apiURL, "api-url", "http://localhost:9999", "API server URL")
 	runCmd.Flags().StringVar(&runnerPath, "runner-path", "", "Path to runner binary (default: auto-detect)")
+	runCmd.Flags().StringVar(&parentRunID, "parent-run-id", "", "Record the run as a rerun of this run ID")
+	runCmd.Flags().StringVar(&pipelineRunID, "pipeline-run-id", "", "Record the run as part of this pipeline run ID")
+	runCmd.Flags().StringVar(&presetRunID, "run-id", "", "Create the run with this ID (set by the API)")
+	runCmd.Flags().MarkHidden("run-id")
 
 	rootCmd.AddCommand(runCmd)
 
+	// Rerun command
+	rerunCmd := &cobra.Command{
+		Use:   "rerun",
+		Short: "Rerun tests of a previous run",
+		Long: `Rerun the tests of a previous run in a new run linked to the original.
+The suite path defaults to the folder the original run was executed from.
+
+Examples:
+  tsuite rerun --run-id 3f2a9c1d8e7b --failed-only
+  tsuite rerun --run-id 3f2a9c1d8e7b --failed-only --parallel 4`,
+		RunE: rerunTests,
+	}
+
+	rerunCmd.Flags().String("run-id", "", "Run ID (or unique prefix) of the run to rerun")
+	rerunCmd.Flags().Bool("failed-only", false, "Only rerun failed and crashed tests")
+	rerunCmd.Flags().StringVarP(&suitePath, "suite-path", "s", "", "Path to test suite (default: suite of the original run)")
+	rerunCmd.Flags().IntVarP(&parallel, "parallel", "p", 1, "Number of parallel test runners")
+	rerunCmd.Flags().StringVar(&apiURL, "api-url", "http://localhost:9999", "API server URL")
+	rerunCmd.Flags().StringVar(&runnerPath, "runner-path", "", "Path to runner binary (default: auto-detect)")
+	rerunCmd.MarkFlagRequired("run-id")
+
+	rootCmd.AddCommand(rerunCmd)
+
 	// List command
 	listCmd := &cobra.Command{
 		Use:   "list",
@@ -326,9 +191,32 @@ Features: embedded dashboard UI, Docker/standalone modes for isolation, parallel
 	listCmd.Flags().StringVarP(&suitePath, "suite-path", "s", ".", "Path to test suite")
 	listCmd.Flags().StringSliceVar(&ucFilter, "uc", nil, "Filter by use case")
 	listCmd.Flags().StringSliceVar(&tagFilter, "tags", nil, "Filter by tags")
+	listCmd.Flags().StringSliceVar(&skipTagFilter, "skip-tags", nil, "Skip tests with any of these tags")
 
 	rootCmd.AddCommand(listCmd)
 
+	// Plan command
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Predict run time and worker load from historical durations",
+		Long: `Predict total wall-clock time, per-worker load and the critical path
+of a test set using durations recorded in previous runs.
+
+Examples:
+  tsuite plan --parallel 8
+  tsuite plan --uc uc01_registry --parallel 4 --history 10`,
+		RunE: runPlan,
+	}
+
+	planCmd.Flags().StringVarP(&suitePath, "suite-path", "s", ".", "Path to test suite")
+	planCmd.Flags().IntVarP(&parallel, "parallel", "p", 1, "Number of parallel test runners")
+	planCmd.Flags().StringSliceVar(&ucFilter, "uc", nil, "Filter by use case")
+	planCmd.Flags().StringSliceVar(&tcFilter, "tc", nil, "Filter by test case")
+	planCmd.Flags().Int("history", 20, "Number of recent runs to use for duration estimates (0 = all)")
+	planCmd.Flags().Duration("default-duration", 0, "Estimate for tests without history (default: median of known tests)")
+
+	rootCmd.AddCommand(planCmd)
+
 	// Version command
 	versionCmd := &cobra.Command{
 		Use:   "version",
@@ -355,6 +243,27 @@ Features: embedded dashboard UI, Docker/standalone modes for isolation, parallel
 	}
 	rootCmd.AddCommand(checkCmd)
 
+	// Doctor command
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check for common misconfigurations",
+		Long: `Check the runner binaries, Docker/Podman, the suite's base image, API
+reachability, clock skew with the API server, database integrity and stale
+PID files. Each problem is printed with a suggested fix.
+
+Examples:
+  tsuite doctor
+  tsuite doctor --suite-path ./my-suite --api-url http://ci-host:9999`,
+		RunE: runDoctor,
+		// Failed checks are not usage errors; main prints the error once
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	doctorCmd.Flags().StringVarP(&suitePath, "suite-path", "s", ".", "Path to test suite")
+	doctorCmd.Flags().StringVar(&apiURL, "api-url", "http://localhost:9999", "API server URL")
+	doctorCmd.Flags().StringVar(&runnerPath, "runner-path", "", "Path to runner binary (default: auto-detect)")
+	rootCmd.AddCommand(doctorCmd)
+
 	// Stop command
 	stopCmd := &cobra.Command{
 		Use:   "stop",
@@ -417,6 +326,156 @@ Examples:
 	scaffoldCmd.MarkFlagRequired("suite")
 	rootCmd.AddCommand(scaffoldCmd)
 
+	// Init command
+	initCmd := &cobra.Command{
+		Use:   "init <dir>",
+		Short: "Create a new test suite",
+		Long: `Create a skeleton test suite: config.yaml with commented options,
+global/routines.yaml with the standard Python/TypeScript setup routines, an
+example uc01_example/tc01_hello test and a .gitignore.
+
+Examples:
+  tsuite init ./my-suite
+  tsuite init ./my-suite --name "Registry Tests" --mode standalone`,
+		Args: cobra.ExactArgs(1),
+		RunE: runInit,
+	}
+	initCmd.Flags().String("name", "", "Suite name (default: directory name)")
+	initCmd.Flags().String("mode", "docker", "Execution mode: docker or standalone")
+	initCmd.Flags().Bool("force", false, "Overwrite an existing suite's generated files")
+	rootCmd.AddCommand(initCmd)
+
+	// Clone command
+	cloneCmd := &cobra.Command{
+		Use:   "clone",
+		Short: "Duplicate a test case",
+		Long: `Copy a test case directory to a new test ID, rewriting the test name and
+header of its test.yaml.
+
+Examples:
+  tsuite clone --tc uc01_registry/tc01_basic --to uc01_registry/tc07_with_tags
+  tsuite clone --tc uc01_registry/tc01_basic --to uc02_tags/tc01_basic --artifacts link
+  tsuite clone --tc uc01_registry/tc01_basic --to uc01_registry/tc08_retry --name "Retry on failure"`,
+		RunE: runClone,
+	}
+	cloneCmd.Flags().StringVarP(&suitePath, "suite-path", "s", ".", "Path to test suite")
+	cloneCmd.Flags().String("tc", "", "Test case to clone (uc/tc)")
+	cloneCmd.Flags().String("to", "", "New test ID (uc/tc)")
+	cloneCmd.Flags().String("name", "", "Name of the new test (default: original name + \" (copy)\")")
+	cloneCmd.Flags().String("artifacts", scaffold.CloneArtifactsCopy, "Artifacts: copy, link (symlink to the original's) or none")
+	cloneCmd.Flags().Bool("force", false, "Overwrite an existing test case")
+	cloneCmd.MarkFlagRequired("tc")
+	cloneCmd.MarkFlagRequired("to")
+	rootCmd.AddCommand(cloneCmd)
+
+	// Mv command
+	mvCmd := &cobra.Command{
+		Use:   "mv <from> <to>",
+		Short: "Rename or move a test case, keeping its history",
+		Long: `Move a test case directory to a new test ID. If the suite is registered
+in the results database, the old ID is recorded as an alias so earlier
+results (duration history, assertion stats, test history) stay attributed
+to the test under its new ID.
+
+Examples:
+  tsuite mv uc01_registry/tc02_tags uc01_registry/tc02_tag_filter
+  tsuite mv uc01_registry/tc02_tags uc03_tags/tc01_basic -s ./my-suite`,
+		Args: cobra.ExactArgs(2),
+		RunE: runMv,
+	}
+	mvCmd.Flags().StringVarP(&suitePath, "suite-path", "s", ".", "Path to test suite")
+	rootCmd.AddCommand(mvCmd)
+
+	// Pipeline command
+	pipelineCmd := &cobra.Command{
+		Use:   "pipeline",
+		Short: "Run several suites as one unit",
+	}
+	pipelineRunCmd := &cobra.Command{
+		Use:   "run <pipeline.yaml>",
+		Short: "Run the suites of a pipeline file",
+		Long: `Run the suites listed in a pipeline file one after another (mode:
+sequential) or all at once (mode: parallel). Each suite is a regular run
+linked to one pipeline run, which completes only if every suite run
+completes. Suite paths are relative to the pipeline file.
+
+  name: nightly
+  mode: sequential
+  stop_on_failure: true
+  suites:
+    - path: ../registry-suite
+      tags: [smoke]
+    - path: ../mesh-suite
+      parallel: 4
+
+Examples:
+  tsuite pipeline run nightly.yaml
+  tsuite pipeline run nightly.yaml --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: runPipeline,
+		// A failed pipeline is not a usage error; main prints the error once
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	pipelineRunCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the suites without running them")
+	pipelineRunCmd.Flags().StringVar(&apiURL, "api-url", "http://localhost:9999", "API server URL")
+	pipelineRunCmd.Flags().StringVar(&runnerPath, "runner-path", "", "Path to runner binary (default: auto-detect)")
+	pipelineRunCmd.Flags().String("pipeline-run-id", "", "Use this existing pipeline run (set by the API)")
+	pipelineRunCmd.Flags().MarkHidden("pipeline-run-id")
+	pipelineCmd.AddCommand(pipelineRunCmd)
+	rootCmd.AddCommand(pipelineCmd)
+
+	// Self-update command
+	selfUpdateCmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update tsuite and its runners from GitHub releases",
+		Long: `Download the latest (or --version) tsuite release for this platform,
+verify it against the release checksums and replace this binary. Runners
+next to tsuite (tsuite-runner, tsuite-runner-linux-amd64/arm64) are updated
+to the same version.
+
+Examples:
+  tsuite self-update
+  tsuite self-update --version 1.4.0`,
+		RunE: runSelfUpdate,
+		// A failed download is not a usage error; main prints the error once
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	selfUpdateCmd.Flags().String("version", "", "Release version to install (default: latest)")
+	selfUpdateCmd.Flags().String("repo", selfupdate.DefaultRepo, "GitHub repository to download releases from")
+	selfUpdateCmd.Flags().Bool("force", false, "Reinstall even if already at that version")
+	rootCmd.AddCommand(selfUpdateCmd)
+
+	// Runner command
+	runnerCmd := &cobra.Command{
+		Use:   "runner",
+		Short: "Manage the tsuite-runner binaries",
+	}
+	runnerInstallCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the runners matching this tsuite version",
+		Long: `Download tsuite-runner (standalone mode) and tsuite-runner-linux-amd64
+and -arm64 (docker mode, picked by the image's architecture) for this tsuite
+version from GitHub releases, verify them
+against the release checksums and install them next to tsuite, where runs
+look for them. Runs refuse to start with a runner of another version.
+
+Examples:
+  tsuite runner install
+  tsuite runner install --version 1.4.0 --dir ~/bin`,
+		Args: cobra.NoArgs,
+		RunE: runRunnerInstall,
+		// A failed download is not a usage error; main prints the error once
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	runnerInstallCmd.Flags().String("version", "", "Release version to install (default: this tsuite's version)")
+	runnerInstallCmd.Flags().String("dir", "", "Directory to install into (default: next to tsuite)")
+	runnerInstallCmd.Flags().String("repo", selfupdate.DefaultRepo, "GitHub repository to download releases from")
+	runnerCmd.AddCommand(runnerInstallCmd)
+	rootCmd.AddCommand(runnerCmd)
+
 	// Man command
 	manCmd := &cobra.Command{
 		Use:   "man [topic]",
@@ -436,7 +495,10 @@ Examples:
 	manCmd.Flags().BoolVar(&manRaw, "raw", false, "Output raw markdown without formatting (for LLM usage)")
 	rootCmd.AddCommand(manCmd)
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	// Writes queued for a secondary database reach it before exiting
+	db.Close()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -446,6 +508,55 @@ func runAPIServer(cmd *cobra.Command, args []string) error {
 	port, _ := cmd.Flags().GetInt("port")
 	detach, _ := cmd.Flags().GetBool("detach")
 
+	opts := api.DefaultOptions(port)
+	opts.Version = version
+	opts.SSE.BufferSize, _ = cmd.Flags().GetInt("sse-buffer")
+	opts.SSE.HeartbeatInterval, _ = cmd.Flags().GetDuration("sse-heartbeat")
+	opts.SSE.DropPolicy, _ = cmd.Flags().GetString("sse-drop-policy")
+	opts.SSE.RetryInterval, _ = cmd.Flags().GetDuration("sse-retry")
+	if opts.SSE.RetryInterval < 0 {
+		return fmt.Errorf("--sse-retry must not be negative")
+	}
+	if err := opts.SSE.Validate(); err != nil {
+		return err
+	}
+	maxBodyMB, _ := cmd.Flags().GetInt("max-body-mb")
+	if maxBodyMB <= 0 {
+		return fmt.Errorf("--max-body-mb must be positive")
+	}
+	opts.MaxBodyBytes = int64(maxBodyMB) << 20
+	opts.StaleAfter, _ = cmd.Flags().GetDuration("stale-after")
+	if opts.StaleAfter < 0 {
+		return fmt.Errorf("--stale-after must not be negative")
+	}
+	if opts.StaleAfter > 0 && opts.StaleAfter < 2*client.HeartbeatInterval {
+		return fmt.Errorf("--stale-after must be at least %s (twice the runner heartbeat interval)", 2*client.HeartbeatInterval)
+	}
+	opts.MaxRunsPerSuite, _ = cmd.Flags().GetInt("max-runs-per-suite")
+	if opts.MaxRunsPerSuite <= 0 {
+		return fmt.Errorf("--max-runs-per-suite must be positive")
+	}
+	opts.InProcess, _ = cmd.Flags().GetBool("in-process")
+	opts.MinFreeDiskMB, _ = cmd.Flags().GetInt("min-free-disk-mb")
+	if opts.MinFreeDiskMB < 0 {
+		return fmt.Errorf("--min-free-disk-mb must not be negative")
+	}
+	opts.CORSOrigins, _ = cmd.Flags().GetStringSlice("cors-origin")
+	opts.Branding.Title, _ = cmd.Flags().GetString("title")
+	opts.Branding.LogoFile, _ = cmd.Flags().GetString("logo")
+	opts.Branding.Accent, _ = cmd.Flags().GetString("accent")
+	opts.BasePath, _ = cmd.Flags().GetString("base-path")
+	opts.TLSCertFile, _ = cmd.Flags().GetString("tls-cert")
+	opts.TLSKeyFile, _ = cmd.Flags().GetString("tls-key")
+	if (opts.TLSCertFile == "") != (opts.TLSKeyFile == "") {
+		return fmt.Errorf("--tls-cert and --tls-key must be given together")
+	}
+	opts.SecondaryDB, _ = cmd.Flags().GetString("secondary-db")
+	opts.DBTimeout, _ = cmd.Flags().GetDuration("db-timeout")
+	if opts.DBTimeout <= 0 {
+		return fmt.Errorf("--db-timeout must be positive")
+	}
+
 	// Check if already running
 	running, existingPID := isServerRunning()
 	if running {
@@ -456,7 +567,7 @@ func runAPIServer(cmd *cobra.Command, args []string) error {
 
 	// Handle detach mode
 	if detach && os.Getenv("TSUITE_DETACHED") != "1" {
-		return startDetached(port)
+		return startDetached(opts)
 	}
 
 	// Set database path (use same location as Python version)
@@ -479,7 +590,7 @@ func runAPIServer(cmd *cobra.Command, args []string) error {
 	defer os.Remove(pidFile)
 	defer os.Remove(portFile)
 
-	server, err := api.NewServer(port)
+	server, err := api.NewServerWithOptions(opts)
 	if err != nil {
 		return fmt.Errorf("failed to create server: %w", err)
 	}
@@ -487,7 +598,9 @@ func runAPIServer(cmd *cobra.Command, args []string) error {
 	return server.Run()
 }
 
-func startDetached(port int) error {
+func startDetached(opts api.Options) error {
+	port := opts.Port
+
 	// Get executable path
 	exe, err := os.Executable()
 	if err != nil {
@@ -495,7 +608,40 @@ func startDetached(port int) error {
 	}
 
 	// Build command: tsuite api --port <port> (without --detach)
-	cmdArgs := []string{"api", "--port", fmt.Sprintf("%d", port)}
+	cmdArgs := []string{
+		"api", "--port", fmt.Sprintf("%d", port),
+		"--sse-buffer", fmt.Sprintf("%d", opts.SSE.BufferSize),
+		"--sse-heartbeat", opts.SSE.HeartbeatInterval.String(),
+		"--sse-drop-policy", opts.SSE.DropPolicy,
+		"--sse-retry", opts.SSE.RetryInterval.String(),
+		"--cors-origin", strings.Join(opts.CORSOrigins, ","),
+		"--max-body-mb", fmt.Sprintf("%d", opts.MaxBodyBytes>>20),
+		"--stale-after", opts.StaleAfter.String(),
+		"--max-runs-per-suite", fmt.Sprintf("%d", opts.MaxRunsPerSuite),
+		"--min-free-disk-mb", fmt.Sprintf("%d", opts.MinFreeDiskMB),
+		"--db-timeout", opts.DBTimeout.String(),
+	}
+	if opts.InProcess {
+		cmdArgs = append(cmdArgs, "--in-process")
+	}
+	if opts.Branding.Title != "" {
+		cmdArgs = append(cmdArgs, "--title", opts.Branding.Title)
+	}
+	if opts.Branding.LogoFile != "" {
+		cmdArgs = append(cmdArgs, "--logo", opts.Branding.LogoFile)
+	}
+	if opts.Branding.Accent != "" {
+		cmdArgs = append(cmdArgs, "--accent", opts.Branding.Accent)
+	}
+	if opts.BasePath != "" {
+		cmdArgs = append(cmdArgs, "--base-path", opts.BasePath)
+	}
+	if opts.TLSCertFile != "" {
+		cmdArgs = append(cmdArgs, "--tls-cert", opts.TLSCertFile, "--tls-key", opts.TLSKeyFile)
+	}
+	if opts.SecondaryDB != "" {
+		cmdArgs = append(cmdArgs, "--secondary-db", opts.SecondaryDB)
+	}
 
 	proc := exec.Command(exe, cmdArgs...)
 	proc.Env = append(os.Environ(), "TSUITE_DETACHED=1")
@@ -538,528 +684,494 @@ func startDetached(port int) error {
 	return nil
 }
 
-func runTests(cmd *cobra.Command, args []string) error {
-	// Resolve suite path (including symlinks for consistent matching with database)
-	absPath, err := filepath.Abs(suitePath)
-	if err != nil {
-		return fmt.Errorf("failed to resolve suite path: %w", err)
-	}
-	// Resolve symlinks to match paths stored in database
-	absPath, err = filepath.EvalSymlinks(absPath)
+// explainTests prints the fully-expanded steps of each test without running them
+func explainTests(absPath string, tests []string) error {
+	// Placeholder base workdir; the real one is a fresh temp dir per run
+	testRunner, err := runner.NewTestRunner(absPath, "", "", "<workdir>")
 	if err != nil {
-		return fmt.Errorf("failed to resolve symlinks: %w", err)
+		return err
 	}
 
-	// Load suite config to determine mode
-	suiteConfig, err := config.LoadSuiteConfig(absPath)
-	if err != nil {
-		return fmt.Errorf("failed to load suite config: %w", err)
-	}
+	for _, testID := range tests {
+		explanation, err := testRunner.ExplainTest(testID)
+		if err != nil {
+			fmt.Printf("\n%s\n  Error: %v\n", testID, err)
+			continue
+		}
 
-	// Determine mode from config (default to standalone)
-	mode := suiteConfig.Suite.Mode
-	if mode == "" {
-		mode = "standalone"
-	}
+		fmt.Printf("\n%s - %s\n", explanation.TestID, explanation.TestName)
+		fmt.Printf("  workdir: %s\n", explanation.Workdir)
+		for _, expr := range explanation.SkipIf {
+			fmt.Printf("  skip_if: %s\n", expr)
+		}
 
-	// Use config's max_workers if --parallel not explicitly set
-	if !cmd.Flags().Changed("parallel") && suiteConfig.Execution.MaxWorkers > 0 {
-		parallel = suiteConfig.Execution.MaxWorkers
+		phase := ""
+		for _, step := range explanation.Steps {
+			if step.Phase != phase {
+				phase = step.Phase
+				fmt.Printf("  %s:\n", phase)
+			}
+			header := fmt.Sprintf("    [%s] %s", step.Index, step.Handler)
+			if step.Routine != "" && step.Handler == "" {
+				header += "routine " + step.Routine
+			} else if step.Routine != "" {
+				header += " (routine " + step.Routine + ")"
+			}
+			if step.Name != "" {
+				header += ": " + step.Name
+			}
+			fmt.Println(header)
+			if step.Error != "" {
+				fmt.Printf("        Error: %s\n", step.Error)
+				continue
+			}
+			if len(step.Fields) > 0 {
+				var out strings.Builder
+				enc := yaml.NewEncoder(&out)
+				enc.SetIndent(2)
+				enc.Encode(step.Fields)
+				for _, line := range strings.Split(strings.TrimRight(out.String(), "\n"), "\n") {
+					fmt.Printf("        %s\n", line)
+				}
+			}
+			if len(step.Runtime) > 0 {
+				fmt.Printf("        # resolved at runtime: %s\n", strings.Join(step.Runtime, ", "))
+			}
+		}
+
+		if len(explanation.Assertions) > 0 {
+			fmt.Println("  assertions:")
+			for _, expr := range explanation.Assertions {
+				fmt.Printf("    - %s\n", expr)
+			}
+		}
 	}
+	return nil
+}
 
-	fmt.Printf("Suite: %s (mode: %s, parallel: %d)\n", suiteConfig.Suite.Name, mode, parallel)
+func runTests(cmd *cobra.Command, args []string) error {
+	opts := runOptions()
+	// The suite's (or profile's) max_workers applies unless --parallel is given
+	if !cmd.Flags().Changed("parallel") {
+		opts.Parallel = 0
+	}
+	// --keep-workdir=false overrides execution.keep_failed_workdirs
+	if cmd.Flags().Changed("keep-workdir") {
+		opts.KeepWorkdir = &keepWorkdir
+	}
 
-	// List all tests
-	allTests, err := runner.ListTests(absPath)
+	r, err := orchestrator.Prepare(opts)
 	if err != nil {
-		return fmt.Errorf("failed to list tests: %w", err)
+		return err
 	}
+	r.PrintHeader()
 
-	// Filter tests
-	tests := filterTests(allTests)
-
-	if len(tests) == 0 {
+	if len(r.Tests) == 0 {
 		fmt.Println("No tests found matching the filters")
 		return nil
 	}
 
-	fmt.Printf("Found %d test(s)\n", len(tests))
+	// Deep dry run - show what each test would execute
+	if explain {
+		return explainTests(r.SuitePath, r.Tests)
+	}
 
 	// Dry run - just list tests
 	if dryRun {
 		fmt.Println("\nTests to run:")
-		for _, t := range tests {
-			fmt.Printf("  - %s\n", t)
+		for _, t := range r.Tests {
+			if reason, ok := r.Affected[t]; ok {
+				fmt.Printf("  - %s (%s)\n", t, reason)
+			} else {
+				fmt.Printf("  - %s\n", t)
+			}
 		}
 		return nil
 	}
 
-	// Check Docker availability if docker mode
-	if mode == "docker" {
-		ok, msg := runner.CheckDockerAvailable()
-		if !ok {
-			return fmt.Errorf("Docker not available: %s", msg)
+	result, err := r.Execute(context.Background())
+	if err != nil {
+		return err
+	}
+	result.PrintSummary(os.Stdout)
+	if ghaSummary {
+		if err := writeGHASummary(r, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		}
-		fmt.Printf("Docker: %s\n", msg)
 	}
 
-	// Create temp workdir for test execution
-	var baseWorkdir string
-	tmpDir, err := os.MkdirTemp("", "tsuite_")
-	if err != nil {
-		return fmt.Errorf("failed to create temp workdir: %w", err)
+	if result.Failed > 0 {
+		return fmt.Errorf("%d test(s) failed", result.Failed)
 	}
-	baseWorkdir = tmpDir
-	if mode == "standalone" {
-		fmt.Printf("Workdir: %s\n", baseWorkdir)
+	if result.SmokeStatus == orchestrator.SmokeOverBudget {
+		return fmt.Errorf("smoke run exceeded its time budget of %s", r.SmokeBudget)
 	}
-	defer os.RemoveAll(baseWorkdir) // Cleanup after run
 
-	// Create API client
-	apiClient := client.NewClient(apiURL)
+	return nil
+}
 
-	// Check API server health
-	if err := apiClient.HealthCheck(); err != nil {
-		fmt.Printf("Warning: API server not available at %s: %v\n", apiURL, err)
-		fmt.Println("Results will not be saved to database. Start the API server with: tsuite api")
-		apiClient = nil
-	} else {
-		fmt.Printf("API Server: %s\n", apiURL)
-	}
-
-	// Create run via API
-	var runID string
-	var suiteID int64
-	if apiClient != nil {
-		// Sync suite to get suite_id
-		syncResp, err := apiClient.UpsertSuite(&client.SyncSuiteRequest{
-			FolderPath: absPath,
-			SuiteName:  suiteConfig.Suite.Name,
-			Mode:       mode,
-			TestCount:  len(tests),
-		})
-		if err != nil {
-			fmt.Printf("Warning: Failed to sync suite: %v\n", err)
-		} else if syncResp != nil {
-			suiteID = syncResp.ID
-		}
+// runOptions returns the orchestrator options given by the run flags
+func runOptions() orchestrator.Options {
+	return orchestrator.Options{
+		SuitePath: suitePath,
+		Filter: orchestrator.Filter{
+			UC:       ucFilter,
+			TC:       tcFilter,
+			Tags:     tagFilter,
+			SkipTags: skipTagFilter,
+		},
+		Parallel:        parallel,
+		Mode:            modeOverride,
+		Profile:         profileName,
+		Env:             envOverrides,
+		PublishPorts:    publishPorts,
+		KeepFailed:      keepFailed,
+		FailFast:        failFast,
+		Smoke:           smoke,
+		ChangedSince:    changedSince,
+		UpdateSnapshots: updateSnaps,
+		NodeVersion:     nodeVersion,
+		FromManifest:    fromManifest,
+		Offline:         offline,
+		APIURL:          apiURL,
+		RunnerPath:      runnerPath,
+		RunID:           presetRunID,
+		ParentRunID:     parentRunID,
+		PipelineRunID:   pipelineRunID,
+		Version:         version,
+	}
+}
 
-		// Build test info for API
-		testInfos := make([]client.TestInfo, len(tests))
-		for i, testID := range tests {
-			parts := strings.Split(testID, "/")
-			testInfos[i] = client.TestInfo{
-				TestID:   testID,
-				UseCase:  parts[0],
-				TestCase: parts[1],
-			}
-		}
+func listTests(cmd *cobra.Command, args []string) error {
+	absPath, err := filepath.Abs(suitePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve suite path: %w", err)
+	}
 
-		// Build display name
-		displayName := suiteConfig.Suite.Name
-		if len(tests) == 1 {
-			// Single test - include test name in display
-			displayName = suiteConfig.Suite.Name + " / " + tests[0]
-		}
+	allTests, err := runner.ListTests(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to list tests: %w", err)
+	}
 
-		createReq := &client.CreateRunRequest{
-			SuiteID:     suiteID,
-			SuiteName:   suiteConfig.Suite.Name,
-			DisplayName: displayName,
-			TotalTests:  len(tests),
-			Mode:        mode,
-			Tests:       testInfos,
-		}
+	tests := orchestrator.FilterTests(absPath, allTests, runOptions().Filter)
 
-		resp, err := apiClient.CreateRun(createReq)
-		if err != nil {
-			fmt.Printf("Warning: Failed to create run: %v\n", err)
-		} else {
-			runID = resp.RunID
-			fmt.Printf("Run ID: %s\n", runID[:12])
-		}
+	if len(tests) == 0 {
+		fmt.Println("No tests found")
+		return nil
 	}
 
-	// Run tests
-	startTime := time.Now()
-	passed := 0
-	failed := 0
-	skipped := 0
-	cancelled := false
-	var failedTests []string
-
-	// Get docker image from config
-	dockerImage := suiteConfig.Docker.BaseImage
-	if dockerImage == "" {
-		dockerImage = "tsuite-mesh:local" // Default image
+	fmt.Printf("Found %d test(s):\n", len(tests))
+	for _, t := range tests {
+		fmt.Printf("  - %s\n", t)
 	}
 
-	// Set test timeout (10 minutes default)
-	testTimeout := 10 * time.Minute
+	return nil
+}
+
+// =============================================================================
+// Rerun Command
+// =============================================================================
 
-	// Create context for cancellation
-	ctx, cancelFunc := context.WithCancel(context.Background())
-	defer cancelFunc()
+func rerunTests(cmd *cobra.Command, args []string) error {
+	runIDArg, _ := cmd.Flags().GetString("run-id")
+	failedOnly, _ := cmd.Flags().GetBool("failed-only")
 
-	if mode == "docker" {
-		// Docker mode: use DockerExecutor which mounts Go runner into container
-		if parallel > 1 && len(tests) > 1 {
-			passed, failed, skipped, failedTests, cancelled = runTestsParallelWithDocker(ctx, cancelFunc, absPath, tests, parallel, apiClient, runID, baseWorkdir, dockerImage, apiURL)
-		} else {
-			passed, failed, skipped, failedTests, cancelled = runTestsSequentialWithDocker(ctx, cancelFunc, absPath, tests, apiClient, runID, baseWorkdir, dockerImage, apiURL)
-		}
-	} else {
-		// Standalone mode: use external runner binary
-		runnerBinaryPath := findRunnerBinary()
-		if runnerBinaryPath == "" {
-			return fmt.Errorf("runner binary not found. Build it with: make build-runner")
-		}
-		if parallel > 1 && len(tests) > 1 {
-			passed, failed, skipped, failedTests, cancelled = runTestsWithRunnerParallel(ctx, cancelFunc, runnerBinaryPath, absPath, tests, parallel, apiURL, runID, baseWorkdir, testTimeout)
-		} else {
-			passed, failed, skipped, failedTests, cancelled = runTestsWithRunnerSequential(ctx, cancelFunc, runnerBinaryPath, absPath, tests, apiURL, runID, baseWorkdir, testTimeout)
-		}
+	apiClient := client.NewClient(apiURL)
+	if err := apiClient.HealthCheck(); err != nil {
+		return fmt.Errorf("API server at %s: %w", apiURL, err)
+	}
+	if _, err := apiClient.CheckVersion(); err != nil {
+		return err
 	}
 
-	// Complete or cancel run via API
-	if apiClient != nil && runID != "" {
-		if cancelled {
-			if err := apiClient.CancelRun(runID); err != nil {
-				fmt.Printf("Warning: Failed to mark run as cancelled: %v\n", err)
-			}
+	run, err := apiClient.GetRun(runIDArg)
+	if err != nil {
+		return err
+	}
+
+	var statuses []string
+	if failedOnly {
+		statuses = []string{"failed", "crashed"}
+	}
+	runTestsList, err := apiClient.GetRunTests(run.RunID, statuses...)
+	if err != nil {
+		return err
+	}
+	if len(runTestsList) == 0 {
+		if failedOnly {
+			fmt.Printf("No failed tests in run %s\n", run.RunID[:12])
 		} else {
-			if err := apiClient.CompleteRun(runID); err != nil {
-				fmt.Printf("Warning: Failed to complete run: %v\n", err)
-			}
+			fmt.Printf("No tests in run %s\n", run.RunID[:12])
 		}
+		return nil
 	}
 
-	// Print summary
-	duration := time.Since(startTime)
-	fmt.Println("\n" + strings.Repeat("=", 60))
-	if cancelled {
-		fmt.Printf("CANCELLED: %d passed, %d failed, %d skipped (%.1fs)\n", passed, failed, skipped, duration.Seconds())
-	} else {
-		fmt.Printf("SUMMARY: %d passed, %d failed (%.1fs)\n", passed, failed, duration.Seconds())
-	}
-	if len(failedTests) > 0 {
-		fmt.Println("\nFailed tests:")
-		for _, t := range failedTests {
-			fmt.Printf("  ✗ %s\n", t)
+	// Default to the suite the original run was executed from
+	if !cmd.Flags().Changed("suite-path") {
+		if run.SuiteID == nil {
+			return fmt.Errorf("run %s has no associated suite; pass --suite-path", run.RunID[:12])
+		}
+		suite, err := apiClient.GetSuite(*run.SuiteID)
+		if err != nil {
+			return err
 		}
+		suitePath = suite.FolderPath
 	}
-	fmt.Println(strings.Repeat("=", 60))
 
-	if failed > 0 {
-		return fmt.Errorf("%d test(s) failed", failed)
+	fmt.Printf("Rerunning %d test(s) of run %s\n", len(runTestsList), run.RunID[:12])
+
+	// Select exactly the tests of the original run
+	ucFilter = nil
+	tagFilter = nil
+	skipTagFilter = nil
+	tcFilter = make([]string, len(runTestsList))
+	for i, t := range runTestsList {
+		tcFilter[i] = t.TestID
 	}
+	parentRunID = run.RunID
 
-	return nil
+	return runTests(cmd, args)
 }
 
-func runTestsSequentialWithDocker(ctx context.Context, cancelFunc context.CancelFunc, suitePath string, tests []string, apiClient *client.Client, runID string, baseWorkdir string, dockerImage string, serverURL string) (passed, failed, skipped int, failedTests []string, cancelled bool) {
-	// Create docker executor
-	dockerConfig := &runner.ContainerConfig{
-		Image:   dockerImage,
-		Network: "bridge",
+// =============================================================================
+// Plan Command
+// =============================================================================
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	historyRuns, _ := cmd.Flags().GetInt("history")
+	defaultDuration, _ := cmd.Flags().GetDuration("default-duration")
+
+	absPath, err := filepath.Abs(suitePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve suite path: %w", err)
 	}
-	dockerExec, err := runner.NewDockerExecutor(serverURL, suitePath, baseWorkdir, dockerConfig, runID)
+	// Resolve symlinks to match paths stored in database
+	absPath, err = filepath.EvalSymlinks(absPath)
 	if err != nil {
-		fmt.Printf("Failed to create Docker executor: %v\n", err)
-		return 0, len(tests), 0, tests, false
+		return fmt.Errorf("failed to resolve symlinks: %w", err)
 	}
-	defer dockerExec.Close()
 
-	// Start cancel checker goroutine
-	if apiClient != nil {
-		executor.StartCancelChecker(ctx, cancelFunc, apiClient, runID)
+	suiteConfig, err := config.LoadSuiteConfig(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to load suite config: %w", err)
 	}
 
-	for _, testID := range tests {
-		// Check if cancelled before starting test
-		select {
-		case <-ctx.Done():
-			fmt.Printf("[SKIP] %s (cancelled)\n", testID)
-			skipped++
-			cancelled = true
-			continue
-		default:
-		}
-
-		fmt.Printf("\n[RUN] %s\n", testID)
+	// Use config's max_workers if --parallel not explicitly set
+	if !cmd.Flags().Changed("parallel") && suiteConfig.Execution.MaxWorkers > 0 {
+		parallel = suiteConfig.Execution.MaxWorkers
+	}
 
-		// Note: Runner inside container reports "running" status to API
-		// Don't duplicate here to avoid race conditions with counter updates
+	allTests, err := runner.ListTests(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to list tests: %w", err)
+	}
+	tests := orchestrator.FilterTests(absPath, allTests, runOptions().Filter)
+	if len(tests) == 0 {
+		fmt.Println("No tests found matching the filters")
+		return nil
+	}
 
-		// Run in Docker container (Go runner reports steps to API)
-		// Use combined context with timeout
-		testCtx, testCancel := context.WithTimeout(ctx, 10*time.Minute)
-		result, err := dockerExec.ExecuteTest(testCtx, testID, nil)
-		testCancel()
+	repo, err := db.NewRepository()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	stats, err := repo.GetTestDurationStats(cmd.Context(), absPath, historyRuns)
+	if err != nil {
+		return fmt.Errorf("failed to load test durations: %w", err)
+	}
+	history := make(map[string]time.Duration, len(stats))
+	for id, stat := range stats {
+		history[id] = time.Duration(stat.AvgMS) * time.Millisecond
+	}
 
-		// Check if cancelled during test
-		if ctx.Err() == context.Canceled {
-			fmt.Printf("[SKIP] %s (cancelled)\n", testID)
-			skipped++
-			cancelled = true
-			continue
+	// Tests without history default to the median of known averages
+	planned, defaultDuration := executor.EstimateTests(tests, history, defaultDuration)
+	var unknown []string
+	for _, t := range planned {
+		if !t.HasHistory {
+			unknown = append(unknown, t.TestID)
 		}
+	}
 
-		var testPassed bool
-		var testError string
-		var duration time.Duration
+	// tsuite run starts the longest tests first when it has history
+	order := planned
+	if len(unknown) < len(planned) {
+		order = executor.SortLongestFirst(planned)
+	}
+	plan := executor.SimulateSchedule(order, parallel)
 
-		if err != nil {
-			testPassed = false
-			testError = err.Error()
-			duration = 0
-			// Report failure to API since runner never started
-			if apiClient != nil && runID != "" {
-				apiClient.UpdateTestStatus(runID, testID, &client.UpdateTestStatusRequest{
-					Status:       "failed",
-					ErrorMessage: testError,
-				})
-			}
-		} else {
-			testPassed = result.ExitCode == 0 && result.Error == nil
-			if result.Error != nil {
-				testError = result.Error.Error()
-			} else if result.ExitCode != 0 {
-				testError = fmt.Sprintf("exit code %d", result.ExitCode)
-				if result.Stderr != "" {
-					lines := strings.Split(strings.TrimSpace(result.Stderr), "\n")
-					if len(lines) > 3 {
-						lines = lines[len(lines)-3:]
-					}
-					testError = strings.Join(lines, "; ")
-				}
-			}
-			duration = result.Duration
-		}
+	fmt.Printf("Suite: %s (%d test(s), %d with history)\n", suiteConfig.Suite.Name, len(tests), len(tests)-len(unknown))
+	fmt.Printf("Parallel: %d\n\n", parallel)
 
-		if testPassed {
-			fmt.Printf("[PASS] %s (%.1fs)\n", testID, duration.Seconds())
-			passed++
-		} else {
-			fmt.Printf("[FAIL] %s - %s (%.1fs)\n", testID, testError, duration.Seconds())
-			failed++
-			failedTests = append(failedTests, testID)
+	fmt.Printf("Predicted wall-clock: %s (total work %s, utilization %.0f%%)\n",
+		formatPlanDuration(plan.WallClock), formatPlanDuration(plan.TotalWork), plan.Utilization()*100)
+	fmt.Printf("Longest test: %s (%s) - lower bound at any parallelism\n",
+		plan.LongestTest.TestID, formatPlanDuration(plan.LongestTest.Estimate))
+
+	// Per-worker load
+	fmt.Println("\nWorker load:")
+	for _, w := range plan.Workers {
+		bar := 0
+		if plan.WallClock > 0 {
+			bar = int(float64(w.Total) / float64(plan.WallClock) * 30)
 		}
-		// Note: Go runner inside container reports final status with steps to API
+		fmt.Printf("  worker %-3d %10s  %3d test(s)  %s\n", w.Worker, formatPlanDuration(w.Total), len(w.Tests), strings.Repeat("█", bar))
 	}
-	return
-}
-
-func runTestsParallelWithDocker(ctx context.Context, cancelFunc context.CancelFunc, suitePath string, tests []string, workers int, apiClient *client.Client, runID string, baseWorkdir string, dockerImage string, serverURL string) (passed, failed, skipped int, failedTests []string, cancelled bool) {
-	testCh := make(chan string, len(tests))
-	resultCh := make(chan executor.TestResult, len(tests))
 
-	// Start cancel checker goroutine
-	if apiClient != nil {
-		executor.StartCancelChecker(ctx, cancelFunc, apiClient, runID)
+	// Critical path: tests on the worker that finishes last
+	fmt.Println("\nCritical path:")
+	for _, t := range plan.CriticalPath {
+		fmt.Printf("  %-50s %10s\n", t.TestID, formatPlanDuration(t.Estimate))
 	}
 
-	// Start workers
-	var wg sync.WaitGroup
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-
-			// Each worker gets its own docker executor (for isolation)
-			dockerConfig := &runner.ContainerConfig{
-				Image:   dockerImage,
-				Network: "bridge",
-			}
-			dockerExec, err := runner.NewDockerExecutor(serverURL, suitePath, baseWorkdir, dockerConfig, runID)
-			if err != nil {
-				fmt.Printf("Worker %d: Failed to create Docker executor: %v\n", workerID, err)
-				// Mark all remaining tests as failed
-				for testID := range testCh {
-					resultCh <- executor.TestResult{TestID: testID, Passed: false, Error: err.Error()}
-				}
-				return
-			}
-			defer dockerExec.Close()
-
-			for testID := range testCh {
-				// Check if cancelled before starting test
-				select {
-				case <-ctx.Done():
-					resultCh <- executor.TestResult{TestID: testID, Cancelled: true}
-					continue
-				default:
-				}
+	// Compare parallelism levels to help choose shard counts
+	fmt.Println("\nParallelism comparison:")
+	fmt.Printf("  %-9s %12s %12s %12s\n", "parallel", "wall-clock", "utilization", "longest-1st")
+	for _, p := range planParallelismLevels(parallel, len(tests)) {
+		fifo := executor.SimulateSchedule(planned, p)
+		lpt := executor.SimulateSchedule(executor.SortLongestFirst(planned), p)
+		fmt.Printf("  %-9d %12s %11.0f%% %12s\n", p, formatPlanDuration(fifo.WallClock), fifo.Utilization()*100, formatPlanDuration(lpt.WallClock))
+	}
 
-				// Note: Runner inside container reports "running" status to API
-				// Don't duplicate here to avoid race conditions with counter updates
+	if len(unknown) > 0 {
+		fmt.Printf("\nNo history for %d test(s) (estimated at %s each):\n", len(unknown), formatPlanDuration(defaultDuration))
+		for _, t := range unknown {
+			fmt.Printf("  - %s\n", t)
+		}
+	}
 
-				// Run in Docker container (Go runner reports steps to API)
-				// Use combined context with timeout
-				testCtx, testCancel := context.WithTimeout(ctx, 10*time.Minute)
-				result, err := dockerExec.ExecuteTest(testCtx, testID, nil)
-				testCancel()
+	return nil
+}
 
-				// Check if cancelled during test
-				if ctx.Err() == context.Canceled {
-					resultCh <- executor.TestResult{TestID: testID, Cancelled: true}
-					continue
-				}
+// planParallelismLevels returns powers of two up to the test count, plus the requested level
+func planParallelismLevels(requested, testCount int) []int {
+	levels := []int{}
+	seen := make(map[int]bool)
+	for p := 1; p <= testCount && p <= 64; p *= 2 {
+		levels = append(levels, p)
+		seen[p] = true
+	}
+	if !seen[requested] {
+		levels = append(levels, requested)
+	}
+	sort.Ints(levels)
+	return levels
+}
 
-				var testPassed bool
-				var testError string
-				var duration time.Duration
-
-				if err != nil {
-					testPassed = false
-					testError = err.Error()
-					duration = 0
-					// Report failure to API since runner never started
-					if apiClient != nil && runID != "" {
-						apiClient.UpdateTestStatus(runID, testID, &client.UpdateTestStatusRequest{
-							Status:       "failed",
-							ErrorMessage: testError,
-						})
-					}
-				} else {
-					testPassed = result.ExitCode == 0 && result.Error == nil
-					if result.Error != nil {
-						testError = result.Error.Error()
-					} else if result.ExitCode != 0 {
-						testError = fmt.Sprintf("exit code %d", result.ExitCode)
-						if result.Stderr != "" {
-							lines := strings.Split(strings.TrimSpace(result.Stderr), "\n")
-							if len(lines) > 3 {
-								lines = lines[len(lines)-3:]
-							}
-							testError = strings.Join(lines, "; ")
-						}
-					}
-					duration = result.Duration
-				}
+// formatPlanDuration formats a duration rounded to the second
+func formatPlanDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
 
-				resultCh <- executor.TestResult{
-					TestID:   testID,
-					Passed:   testPassed,
-					Error:    testError,
-					Duration: duration,
-				}
-				// Note: Go runner inside container reports final status with steps to API
-			}
-		}(i)
+// Docker execution support
+func runTestInDocker(ctx context.Context, suitePath string, testID string) (*runner.TestResult, error) {
+	// This would use DockerExecutor to run tests in containers
+	// For now, we run tests locally
+	testRunner, err := runner.NewTestRunner(suitePath, "", "", "") // Empty baseWorkdir for docker mode
+	if err != nil {
+		return nil, err
 	}
+	return testRunner.RunTest(testID)
+}
 
-	// Send tests to workers
-	for _, t := range tests {
-		testCh <- t
-	}
-	close(testCh)
+// =============================================================================
+// Init Command
+// =============================================================================
 
-	// Wait for all workers
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
+func runInit(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	mode, _ := cmd.Flags().GetString("mode")
+	force, _ := cmd.Flags().GetBool("force")
 
-	// Collect results
-	results := executor.CollectResults(resultCh)
-	return results.Passed, results.Failed, results.Skipped, results.FailedTests, results.Cancelled
+	return scaffold.Init(&scaffold.InitConfig{
+		Dir:   args[0],
+		Name:  name,
+		Mode:  mode,
+		Force: force,
+	})
 }
 
-func listTests(cmd *cobra.Command, args []string) error {
+// =============================================================================
+// Clone Command
+// =============================================================================
+
+func runClone(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetString("tc")
+	to, _ := cmd.Flags().GetString("to")
+	name, _ := cmd.Flags().GetString("name")
+	artifacts, _ := cmd.Flags().GetString("artifacts")
+	force, _ := cmd.Flags().GetBool("force")
+
 	absPath, err := filepath.Abs(suitePath)
 	if err != nil {
 		return fmt.Errorf("failed to resolve suite path: %w", err)
 	}
-
-	allTests, err := runner.ListTests(absPath)
-	if err != nil {
-		return fmt.Errorf("failed to list tests: %w", err)
-	}
-
-	tests := filterTests(allTests)
-
-	if len(tests) == 0 {
-		fmt.Println("No tests found")
-		return nil
+	if err := scaffold.ValidateSuite(absPath); err != nil {
+		return err
 	}
 
-	fmt.Printf("Found %d test(s):\n", len(tests))
-	for _, t := range tests {
-		fmt.Printf("  - %s\n", t)
+	if err := scaffold.Clone(&scaffold.CloneConfig{
+		SuitePath: absPath,
+		From:      from,
+		To:        to,
+		TestName:  name,
+		Artifacts: artifacts,
+		Force:     force,
+	}); err != nil {
+		return err
 	}
 
+	fmt.Printf("\nRun with: tsuite run --suite-path %s --tc %s\n", suitePath, to)
 	return nil
 }
 
-func filterTests(tests []string) []string {
-	var filtered []string
-
-	for _, testID := range tests {
-		parts := strings.Split(testID, "/")
-		if len(parts) < 2 {
-			continue
-		}
-		ucName := parts[0]
-		tcName := parts[1]
-
-		// Filter by use case
-		if len(ucFilter) > 0 {
-			match := false
-			for _, uc := range ucFilter {
-				if strings.Contains(ucName, uc) {
-					match = true
-					break
-				}
-			}
-			if !match {
-				continue
-			}
-		}
+// =============================================================================
+// Mv Command
+// =============================================================================
 
-		// Filter by test case
-		// Supports both formats:
-		// - Full path: --tc uc01_registry/tc01_agent (exact match on testID)
-		// - TC name only: --tc tc01_agent (substring match on tcName)
-		if len(tcFilter) > 0 {
-			match := false
-			for _, tc := range tcFilter {
-				if strings.Contains(tc, "/") {
-					// Full path format - exact match on testID
-					if testID == tc {
-						match = true
-						break
-					}
-				} else {
-					// TC name only - substring match
-					if strings.Contains(tcName, tc) {
-						match = true
-						break
-					}
-				}
-			}
-			if !match {
-				continue
-			}
-		}
+func runMv(cmd *cobra.Command, args []string) error {
+	from, to := args[0], args[1]
 
-		// TODO: Filter by tags (requires loading test.yaml)
+	absPath, err := filepath.Abs(suitePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve suite path: %w", err)
+	}
+	if err := scaffold.ValidateSuite(absPath); err != nil {
+		return err
+	}
+	// Resolve symlinks to match paths stored in database
+	dbPath, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve symlinks: %w", err)
+	}
 
-		filtered = append(filtered, testID)
+	repo, err := db.NewRepository()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	suite, err := repo.GetSuiteByPath(cmd.Context(), dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to look up suite: %w", err)
 	}
 
-	return filtered
-}
+	if err := scaffold.Move(&scaffold.MoveConfig{
+		SuitePath: absPath,
+		From:      from,
+		To:        to,
+	}); err != nil {
+		return err
+	}
 
-// Docker execution support
-func runTestInDocker(ctx context.Context, suitePath string, testID string) (*runner.TestResult, error) {
-	// This would use DockerExecutor to run tests in containers
-	// For now, we run tests locally
-	testRunner, err := runner.NewTestRunner(suitePath, "", "", "") // Empty baseWorkdir for docker mode
-	if err != nil {
-		return nil, err
+	if suite == nil {
+		fmt.Println("! Suite is not registered in the results database; no history to keep")
+		return nil
 	}
-	return testRunner.RunTest(testID)
+	if err := repo.RecordTestMove(cmd.Context(), suite.ID, from, to); err != nil {
+		return fmt.Errorf("test moved, but failed to record alias %s → %s: %w", from, to, err)
+	}
+	fmt.Printf("✓ Recorded alias: results of %s now count for %s\n", from, to)
+
+	return nil
 }
 
 // =============================================================================
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// Outcomes of one suite in a pipeline
+const (
+	pipelineSuitePassed  = "passed"
+	pipelineSuiteFailed  = "failed"
+	pipelineSuiteSkipped = "skipped"
+)
+
+// pipelineSuiteResult is the outcome of one suite run by a pipeline
+type pipelineSuiteResult struct {
+	suite    config.PipelineSuite
+	status   string
+	duration time.Duration
+}
+
+// =============================================================================
+// Pipeline Command
+// =============================================================================
+
+func runPipeline(cmd *cobra.Command, args []string) error {
+	pipelineRunID, _ := cmd.Flags().GetString("pipeline-run-id")
+
+	pipeline, err := config.LoadPipelineConfig(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Pipeline: %s (%s, %d suite(s))\n", pipeline.Name, pipeline.Mode, len(pipeline.Suites))
+
+	if dryRun {
+		fmt.Println("\nSuites to run:")
+		for i, suite := range pipeline.Suites {
+			fmt.Printf("  %d. %s\n", i+1, suite.Path)
+			if args := pipelineFilterArgs(suite); len(args) > 0 {
+				fmt.Printf("     %s\n", strings.Join(args, " "))
+			}
+		}
+		return nil
+	}
+
+	// Record the pipeline run; the API creates it up front when it launches us
+	apiClient := client.NewClient(apiURL)
+	if err := apiClient.HealthCheck(); err != nil {
+		if pipelineRunID != "" {
+			return fmt.Errorf("API server at %s: %w", apiURL, err)
+		}
+		slog.Warn("API server not available; the pipeline run will not be recorded (start it with: tsuite api)", "url", apiURL, "error", err)
+		apiClient = nil
+	} else {
+		if _, err := apiClient.CheckVersion(); err != nil {
+			return err
+		}
+		if pipelineRunID == "" {
+			created, err := apiClient.CreatePipelineRun(&client.CreatePipelineRunRequest{
+				Name:        pipeline.Name,
+				FilePath:    pipeline.Path,
+				Mode:        pipeline.Mode,
+				TotalSuites: len(pipeline.Suites),
+			})
+			if err != nil {
+				return err
+			}
+			pipelineRunID = created.PipelineRunID
+		}
+		fmt.Printf("Pipeline Run ID: %s\n", pipelineRunID[:12])
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find executable: %w", err)
+	}
+
+	// Interrupts reach the suite runs too; stop starting new ones
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// cancelled reports whether the pipeline was interrupted or cancelled via the API
+	cancelled := func() bool {
+		if ctx.Err() != nil {
+			return true
+		}
+		if apiClient == nil {
+			return false
+		}
+		info, err := apiClient.GetPipelineRun(pipelineRunID)
+		return err == nil && info.CancelRequested
+	}
+
+	startTime := time.Now()
+	results := make([]pipelineSuiteResult, len(pipeline.Suites))
+	for i, suite := range pipeline.Suites {
+		results[i] = pipelineSuiteResult{suite: suite, status: pipelineSuiteSkipped}
+	}
+
+	runSuite := func(i int, out io.Writer) {
+		suite := pipeline.Suites[i]
+		suiteArgs := []string{"run", "--suite-path", suite.Path, "--api-url", apiURL}
+		if runnerPath != "" {
+			suiteArgs = append(suiteArgs, "--runner-path", runnerPath)
+		}
+		if pipelineRunID != "" && apiClient != nil {
+			suiteArgs = append(suiteArgs, "--pipeline-run-id", pipelineRunID)
+		}
+		suiteArgs = append(suiteArgs, pipelineFilterArgs(suite)...)
+
+		suiteCmd := exec.Command(execPath, suiteArgs...)
+		suiteCmd.Dir = suite.Path
+		suiteCmd.Stdout = out
+		suiteCmd.Stderr = out
+
+		start := time.Now()
+		err := suiteCmd.Run()
+		results[i].duration = time.Since(start)
+		results[i].status = pipelineSuitePassed
+		if err != nil {
+			results[i].status = pipelineSuiteFailed
+		}
+	}
+
+	if pipeline.Mode == config.PipelineParallel {
+		var wg sync.WaitGroup
+		var outMu sync.Mutex
+		for i, suite := range pipeline.Suites {
+			wg.Add(1)
+			go func(i int, label string) {
+				defer wg.Done()
+				out := &prefixWriter{mu: &outMu, out: os.Stdout, prefix: "[" + label + "] "}
+				runSuite(i, out)
+				out.Flush()
+			}(i, filepath.Base(suite.Path))
+		}
+		wg.Wait()
+	} else {
+		for i, suite := range pipeline.Suites {
+			if cancelled() {
+				break
+			}
+			fmt.Printf("\n%s\n[%d/%d] %s\n%s\n", strings.Repeat("-", 60), i+1, len(pipeline.Suites), suite.Path, strings.Repeat("-", 60))
+			runSuite(i, os.Stdout)
+			if results[i].status == pipelineSuiteFailed && pipeline.StopOnFailure {
+				fmt.Println("\nStopping pipeline: stop_on_failure is set")
+				break
+			}
+		}
+	}
+
+	passed, failed, skipped := 0, 0, 0
+	for _, r := range results {
+		switch r.status {
+		case pipelineSuitePassed:
+			passed++
+		case pipelineSuiteFailed:
+			failed++
+		default:
+			skipped++
+		}
+	}
+
+	// Finish the pipeline run; the server derives its status from the suite runs
+	interrupted := ctx.Err() != nil
+	status := ""
+	if apiClient != nil {
+		if interrupted {
+			if err := apiClient.CancelPipelineRun(pipelineRunID); err != nil {
+				slog.Warn("Failed to mark pipeline run as cancelled", "pipeline_run_id", pipelineRunID, "error", err)
+			}
+		}
+		info, err := apiClient.CompletePipelineRun(pipelineRunID, failed)
+		if err != nil {
+			slog.Warn("Failed to complete pipeline run", "pipeline_run_id", pipelineRunID, "error", err)
+		} else {
+			status = info.Status
+		}
+	}
+
+	// Print summary
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Printf("PIPELINE %s\n", pipeline.Name)
+	for _, r := range results {
+		switch r.status {
+		case pipelineSuitePassed:
+			fmt.Printf("  ✓ %s (%.1fs)\n", r.suite.Path, r.duration.Seconds())
+		case pipelineSuiteFailed:
+			fmt.Printf("  ✗ %s (%.1fs)\n", r.suite.Path, r.duration.Seconds())
+		default:
+			fmt.Printf("  - %s (skipped)\n", r.suite.Path)
+		}
+	}
+	if status == "" {
+		status = "completed"
+		if interrupted {
+			status = "cancelled"
+		} else if failed > 0 || skipped > 0 {
+			status = "failed"
+		}
+	}
+	fmt.Printf("\n%s: %d passed, %d failed, %d skipped suite(s) (%.1fs)\n",
+		strings.ToUpper(status), passed, failed, skipped, time.Since(startTime).Seconds())
+	fmt.Println(strings.Repeat("=", 60))
+
+	if status != "completed" {
+		return fmt.Errorf("pipeline %s %s", pipeline.Name, status)
+	}
+	return nil
+}
+
+// pipelineFilterArgs returns the tsuite run flags selecting a pipeline suite's tests
+func pipelineFilterArgs(suite config.PipelineSuite) []string {
+	var args []string
+	for _, uc := range suite.UC {
+		args = append(args, "--uc", uc)
+	}
+	for _, tc := range suite.TC {
+		args = append(args, "--tc", tc)
+	}
+	for _, tag := range suite.Tags {
+		args = append(args, "--tags", tag)
+	}
+	if suite.Parallel > 0 {
+		args = append(args, "--parallel", strconv.Itoa(suite.Parallel))
+	}
+	return args
+}
+
+// prefixWriter writes complete lines to out with a prefix, so the output of
+// suites running in parallel stays readable
+type prefixWriter struct {
+	mu     *sync.Mutex // Shared by all writers to out
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.mu.Lock()
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf[:i])
+		w.mu.Unlock()
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a final line without a trailing newline
+func (w *prefixWriter) Flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+	w.mu.Lock()
+	fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf)
+	w.mu.Unlock()
+	w.buf = nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/selfupdate"
+)
+
+// runnerInstallTargets maps the runner file names tsuite looks for next to
+// itself to the platform each must be built for
+var runnerInstallTargets = []struct {
+	name   string
+	goos   string
+	goarch string
+}{
+	{"tsuite-runner", runtime.GOOS, runtime.GOARCH}, // Standalone mode
+	// Mounted into containers (docker mode), matching the image's architecture
+	{"tsuite-runner-linux-amd64", "linux", "amd64"},
+	{"tsuite-runner-linux-arm64", "linux", "arm64"},
+}
+
+// =============================================================================
+// Self-Update Command
+// =============================================================================
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	targetVersion, _ := cmd.Flags().GetString("version")
+	repo, _ := cmd.Flags().GetString("repo")
+	force, _ := cmd.Flags().GetBool("force")
+
+	releases := selfupdate.NewClient(repo)
+	release, err := releases.GetRelease(targetVersion)
+	if err != nil {
+		return err
+	}
+	if release.Version() == version && !force {
+		fmt.Printf("tsuite %s is up to date\n", version)
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find executable: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("failed to resolve executable: %w", err)
+	}
+
+	data, err := releases.Download(release, selfupdate.AssetName("tsuite", runtime.GOOS, runtime.GOARCH))
+	if err != nil {
+		return err
+	}
+	if err := selfupdate.Install(data, exe); err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s: %s → %s\n", exe, version, release.Version())
+
+	// Keep the runners next to tsuite at the same version
+	return installRunners(releases, release, filepath.Dir(exe), false)
+}
+
+// =============================================================================
+// Runner Install Command
+// =============================================================================
+
+func runRunnerInstall(cmd *cobra.Command, args []string) error {
+	targetVersion, _ := cmd.Flags().GetString("version")
+	repo, _ := cmd.Flags().GetString("repo")
+	dir, _ := cmd.Flags().GetString("dir")
+
+	if targetVersion == "" {
+		if version == runner.DevVersion {
+			return fmt.Errorf("tsuite is a development build; pass --version, or build the runner with: make build-runner")
+		}
+		targetVersion = version
+	}
+	if dir == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to find executable: %w", err)
+		}
+		if exe, err = filepath.EvalSymlinks(exe); err != nil {
+			return fmt.Errorf("failed to resolve executable: %w", err)
+		}
+		dir = filepath.Dir(exe)
+	}
+
+	releases := selfupdate.NewClient(repo)
+	release, err := releases.GetRelease(targetVersion)
+	if err != nil {
+		return err
+	}
+	return installRunners(releases, release, dir, true)
+}
+
+// installRunners installs the runners of a release into dir. Unless all is
+// set, only runners already present in dir are replaced.
+func installRunners(releases *selfupdate.Client, release *selfupdate.Release, dir string, all bool) error {
+	downloaded := make(map[string][]byte) // By asset; on Linux the native runner is a container runner too
+	for _, target := range runnerInstallTargets {
+		path := filepath.Join(dir, target.name)
+		if _, err := os.Stat(path); err != nil && !all {
+			continue
+		}
+
+		asset := selfupdate.AssetName("tsuite-runner", target.goos, target.goarch)
+		data, ok := downloaded[asset]
+		if !ok {
+			var err error
+			if data, err = releases.Download(release, asset); err != nil {
+				return err
+			}
+			downloaded[asset] = data
+		}
+		if err := selfupdate.Install(data, path); err != nil {
+			return err
+		}
+		fmt.Printf("Installed %s %s (%s/%s) to %s\n", target.name, release.Version(), target.goos, target.goarch, path)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import Link from "next/link";
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from "@/components/ui/table";
+import {
+  CoverageResponse,
+  CoverageStatus,
+  FeatureCoverage,
+  Suite,
+  getCoverage,
+  getStatusBgColor,
+} from "@/lib/api";
+import { cn } from "@/lib/utils";
+import { CheckCircle, XCircle, CircleDashed, MinusCircle, Grid3x3, Loader2 } from "lucide-react";
+
+interface CoverageMatrixProps {
+  suites: Suite[];
+}
+
+function CoverageBadge({ status }: { status: CoverageStatus }) {
+  switch (status) {
+    case "passing":
+      return (
+        <Badge variant="secondary" className="gap-1 bg-success/10 text-success">
+          <CheckCircle className="h-3 w-3" />
+          passing
+        </Badge>
+      );
+    case "failing":
+      return (
+        <Badge variant="secondary" className="gap-1 bg-destructive/10 text-destructive">
+          <XCircle className="h-3 w-3" />
+          failing
+        </Badge>
+      );
+    case "not_run":
+      return (
+        <Badge variant="secondary" className="gap-1 bg-warning/10 text-warning">
+          <MinusCircle className="h-3 w-3" />
+          not run
+        </Badge>
+      );
+    default:
+      return (
+        <Badge variant="secondary" className="gap-1 bg-destructive/10 text-destructive">
+          <CircleDashed className="h-3 w-3" />
+          no coverage
+        </Badge>
+      );
+  }
+}
+
+// Status of a feature's covering test, or undefined if it does not cover it
+function cellStatus(feature: FeatureCoverage, testId: string) {
+  return feature.tests.find((t) => t.test_id === testId)?.status;
+}
+
+export function CoverageMatrix({ suites }: CoverageMatrixProps) {
+  const [suiteId, setSuiteId] = useState<number | undefined>(suites[0]?.id);
+  const [coverage, setCoverage] = useState<CoverageResponse | null>(null);
+  const [error, setError] = useState<string | null>(null);
+  const [loading, setLoading] = useState(true);
+
+  useEffect(() => {
+    setLoading(true);
+    setError(null);
+    getCoverage(suiteId)
+      .then(setCoverage)
+      .catch(() => {
+        setCoverage(null);
+        setError("No finished run to report coverage for");
+      })
+      .finally(() => setLoading(false));
+  }, [suiteId]);
+
+  // Matrix columns: every test covering at least one feature
+  const testIds = coverage
+    ? Array.from(
+        new Set(coverage.features.flatMap((f) => f.tests.map((t) => t.test_id)))
+      ).sort()
+    : [];
+
+  return (
+    <div className="space-y-4">
+      {suites.length > 1 && (
+        <div className="flex flex-wrap gap-2">
+          {suites.map((suite) => (
+            <Button
+              key={suite.id}
+              size="sm"
+              variant={suite.id === suiteId ? "default" : "outline"}
+              onClick={() => setSuiteId(suite.id)}
+            >
+              {suite.suite_name}
+            </Button>
+          ))}
+        </div>
+      )}
+
+      <Card className="rounded-md">
+        <CardHeader className="flex flex-row items-center justify-between">
+          <CardTitle className="text-lg font-medium flex items-center gap-2">
+            <Grid3x3 className="h-5 w-5" />
+            Feature Coverage
+          </CardTitle>
+          {coverage && (
+            <span className="text-sm text-muted-foreground">
+              {coverage.summary.passing} passing · {coverage.summary.failing} failing ·{" "}
+              {coverage.summary.uncovered} without coverage · {coverage.summary.not_run} not run ·{" "}
+              <Link href={`/runs?id=${coverage.run_id}`} className="hover:underline">
+                run {coverage.run_id.slice(0, 8)}
+              </Link>
+            </span>
+          )}
+        </CardHeader>
+        <CardContent>
+          {loading ? (
+            <div className="flex justify-center py-8">
+              <Loader2 className="h-6 w-6 animate-spin text-muted-foreground" />
+            </div>
+          ) : error || !coverage ? (
+            <p className="py-8 text-center text-muted-foreground">{error}</p>
+          ) : coverage.features.length === 0 ? (
+            <p className="py-8 text-center text-muted-foreground">
+              No test declares covers: and config.yaml lists no coverage.features
+            </p>
+          ) : (
+            <div className="overflow-x-auto">
+              <Table>
+                <TableHeader>
+                  <TableRow className="border-border hover:bg-transparent">
+                    <TableHead className="text-muted-foreground">Feature</TableHead>
+                    <TableHead className="text-muted-foreground">Coverage</TableHead>
+                    {testIds.map((testId) => (
+                      <TableHead
+                        key={testId}
+                        className="text-xs font-mono text-muted-foreground whitespace-nowrap"
+                      >
+                        {testId}
+                      </TableHead>
+                    ))}
+                  </TableRow>
+                </TableHeader>
+                <TableBody>
+                  {coverage.features.map((feature) => (
+                    <TableRow key={feature.feature} className="border-border hover:bg-muted/50">
+                      <TableCell className="font-mono text-sm">
+                        {feature.feature}
+                        {coverage.unlisted.includes(feature.feature) && (
+                          <span
+                            className="ml-2 text-xs text-warning"
+                            title="Not listed in coverage.features of config.yaml"
+                          >
+                            unlisted
+                          </span>
+                        )}
+                      </TableCell>
+                      <TableCell>
+                        <CoverageBadge status={feature.status} />
+                      </TableCell>
+                      {testIds.map((testId) => {
+                        const status = cellStatus(feature, testId);
+                        return (
+                          <TableCell key={testId} className="text-center">
+                            {status && (
+                              <span
+                                className={cn(
+                                  "inline-block rounded px-1.5 py-0.5 text-xs",
+                                  getStatusBgColor(status)
+                                )}
+                              >
+                                {status}
+                              </span>
+                            )}
+                          </TableCell>
+                        );
+                      })}
+                    </TableRow>
+                  ))}
+                </TableBody>
+              </Table>
+            </div>
+          )}
+        </CardContent>
+      </Card>
+    </div>
+  );
+}
@@ -0,0 +1,48 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { Header } from "@/components/layout/Header";
+import { CoverageMatrix } from "./CoverageMatrix";
+import { getSuites, Suite } from "@/lib/api";
+import { Loader2 } from "lucide-react";
+
+export default function CoveragePage() {
+  const [suites, setSuites] = useState<Suite[]>([]);
+  const [loading, setLoading] = useState(true);
+
+  useEffect(() => {
+    async function fetchData() {
+      try {
+        const suitesData = await getSuites().catch(() => ({
+          suites: [],
+          count: 0,
+        }));
+        setSuites(suitesData.suites);
+      } finally {
+        setLoading(false);
+      }
+    }
+    fetchData();
+  }, []);
+
+  if (loading) {
+    return (
+      <div className="flex flex-col">
+        <Header title="Coverage" subtitle="mcp-mesh features covered by the latest run" />
+        <div className="flex-1 flex items-center justify-center p-6">
+          <Loader2 className="h-8 w-8 animate-spin text-muted-foreground" />
+        </div>
+      </div>
+    );
+  }
+
+  return (
+    <div className="flex flex-col">
+      <Header title="Coverage" subtitle="mcp-mesh features covered by the latest run" />
+
+      <div className="flex-1 p-6">
+        <CoverageMatrix suites={suites} />
+      </div>
+    </div>
+  );
+}
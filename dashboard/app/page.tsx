@@ -5,18 +5,20 @@ import { Header } from "@/components/layout/Header";
 import { StatsCards } from "@/components/dashboard/StatsCards";
 import { RecentRuns } from "@/components/dashboard/RecentRuns";
 import { PassRateChart } from "@/components/dashboard/PassRateChart";
-import { getRuns, getStats, Run, Stats } from "@/lib/api";
+import { SuitesOverview } from "@/components/dashboard/SuitesOverview";
+import { getOverview, getRuns, getStats, OverviewResponse, Run, Stats } from "@/lib/api";
 import { Loader2 } from "lucide-react";
 
 export default function DashboardPage() {
   const [runs, setRuns] = useState<Run[]>([]);
   const [stats, setStats] = useState<Stats | null>(null);
+  const [overview, setOverview] = useState<OverviewResponse | null>(null);
   const [loading, setLoading] = useState(true);
 
   useEffect(() => {
     async function fetchData() {
       try {
-        const [runsData, statsData] = await Promise.all([
+        const [runsData, statsData, overviewData] = await Promise.all([
           getRuns(10, 0).catch(() => ({ runs: [], count: 0, limit: 10, offset: 0 })),
           getStats().catch(() => ({
             total_runs: 0,
@@ -26,9 +28,11 @@ export default function DashboardPage() {
             avg_run_duration_ms: null,
             pass_rate: 0,
           })),
+          getOverview().catch(() => null),
         ]);
         setRuns(runsData.runs);
         setStats(statsData);
+        setOverview(overviewData);
       } finally {
         setLoading(false);
       }
@@ -60,6 +64,9 @@ export default function DashboardPage() {
           totalTests={stats?.total_tests_executed ?? 0}
         />
 
+        {/* Latest run of every suite */}
+        {overview && <SuitesOverview overview={overview} />}
+
         {/* Charts and Recent Runs */}
         <div className="grid gap-6 lg:grid-cols-2">
           <PassRateChart runs={runs} />
@@ -0,0 +1,125 @@
+"use client";
+
+import { useState, useEffect } from "react";
+import { Loader2, MessageSquare, Trash2 } from "lucide-react";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { Textarea } from "@/components/ui/textarea";
+import {
+  Comment,
+  addRunComment,
+  deleteRunComment,
+  formatRelativeTime,
+  getRunComments,
+} from "@/lib/api";
+
+// Name comments are signed with, remembered across visits
+const AUTHOR_KEY = "tsuite-comment-author";
+
+interface RunCommentsProps {
+  runId: string;
+  testId?: string;       // Comments on this test instead of the run
+  initial?: Comment[];   // Already loaded, e.g. with the test detail
+}
+
+export function RunComments({ runId, testId = "", initial }: RunCommentsProps) {
+  const [comments, setComments] = useState<Comment[]>(initial ?? []);
+  const [text, setText] = useState("");
+  const [author, setAuthor] = useState("");
+  const [saving, setSaving] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    setAuthor(localStorage.getItem(AUTHOR_KEY) ?? "");
+  }, []);
+
+  useEffect(() => {
+    if (initial) {
+      setComments(initial);
+      return;
+    }
+    getRunComments(runId, testId)
+      .then(setComments)
+      .catch((err) => setError(err instanceof Error ? err.message : "Failed to fetch comments"));
+  }, [runId, testId, initial]);
+
+  const handleAdd = async () => {
+    if (!text.trim()) return;
+    setSaving(true);
+    setError(null);
+    try {
+      const comment = await addRunComment(runId, {
+        text,
+        test_id: testId || undefined,
+        author: author.trim() || undefined,
+      });
+      localStorage.setItem(AUTHOR_KEY, author.trim());
+      setComments((prev) => [...prev, comment]);
+      setText("");
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to add comment");
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleDelete = async (id: number) => {
+    try {
+      await deleteRunComment(runId, id);
+      setComments((prev) => prev.filter((c) => c.id !== id));
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to delete comment");
+    }
+  };
+
+  return (
+    <div className="space-y-3">
+      <h4 className="font-medium flex items-center gap-2">
+        <MessageSquare className="h-4 w-4" />
+        Comments ({comments.length})
+      </h4>
+
+      {comments.map((comment) => (
+        <div key={comment.id} className="group rounded-md bg-muted/50 p-3">
+          <div className="flex items-center justify-between text-xs text-muted-foreground">
+            <span>
+              <span className="font-medium text-foreground">{comment.author}</span>
+              {" · "}
+              {formatRelativeTime(comment.created_at)}
+            </span>
+            <button
+              onClick={() => handleDelete(comment.id)}
+              className="opacity-0 group-hover:opacity-100 hover:text-destructive"
+              title="Delete comment"
+            >
+              <Trash2 className="h-3.5 w-3.5" />
+            </button>
+          </div>
+          <p className="mt-1 text-sm whitespace-pre-wrap">{comment.text}</p>
+        </div>
+      ))}
+
+      <div className="space-y-2">
+        <Textarea
+          value={text}
+          onChange={(e) => setText(e.target.value)}
+          placeholder={testId ? "Comment on this test..." : "Comment on this run, e.g. known infra outage 02:00-03:00"}
+          className="text-sm"
+        />
+        <div className="flex items-center gap-2">
+          <Input
+            value={author}
+            onChange={(e) => setAuthor(e.target.value)}
+            placeholder="Your name"
+            className="h-8 w-48 text-sm"
+          />
+          <Button size="sm" onClick={handleAdd} disabled={saving || !text.trim()}>
+            {saving && <Loader2 className="h-4 w-4 animate-spin mr-1" />}
+            Comment
+          </Button>
+          {error && <span className="text-xs text-destructive">{error}</span>}
+        </div>
+      </div>
+    </div>
+  );
+}
@@ -24,6 +24,7 @@ import {
   getTestDetail,
   rerunFromRun,
   cancelRun,
+  cancelTest,
   deleteRun,
   runTests,
 } from "@/lib/api";
@@ -46,6 +47,7 @@ import {
   Trash2,
 } from "lucide-react";
 import { cn } from "@/lib/utils";
+import { RunComments } from "./RunComments";
 
 interface RunDetailsProps {
   run: RunSummary;
@@ -90,6 +92,7 @@ export function RunDetails({ run, tests }: RunDetailsProps) {
   const router = useRouter();
   const [expandedIds, setExpandedIds] = useState<Set<string>>(new Set());
   const [filter, setFilter] = useState<string | null>(null);
+  const [owner, setOwner] = useState<string | null>(null);
   const [selectedTest, setSelectedTest] = useState<TestResult | null>(null);
   const [testDetail, setTestDetail] = useState<TestDetail | null>(null);
   const [loadingDetail, setLoadingDetail] = useState(false);
@@ -98,7 +101,15 @@ export function RunDetails({ run, tests }: RunDetailsProps) {
   const [deleting, setDeleting] = useState(false);
   const [showDeleteConfirm, setShowDeleteConfirm] = useState(false);
 
-  const useCases = useMemo(() => groupTestsByUseCase(tests), [tests]);
+  // Owners of the run's tests, from test.yaml or config.yaml owners
+  const owners = useMemo(
+    () => Array.from(new Set(tests.flatMap((t) => t.owners ?? []))).sort(),
+    [tests]
+  );
+  const useCases = useMemo(
+    () => groupTestsByUseCase(owner ? tests.filter((t) => t.owners?.includes(owner)) : tests),
+    [tests, owner]
+  );
 
   const handleRerun = async () => {
     if (!run.suite_id) return;
@@ -142,6 +153,15 @@ export function RunDetails({ run, tests }: RunDetailsProps) {
   const canCancel = run.status === "pending" || run.status === "running";
   const isCancelRequested = run.cancel_requested;
 
+  const handleCancelTest = async (testId: string) => {
+    try {
+      await cancelTest(run.run_id, testId);
+      router.refresh();
+    } catch (error) {
+      console.error("Failed to cancel test:", error);
+    }
+  };
+
   const handleRerunTest = async (testId: string) => {
     if (!run.suite_id) return;
     try {
@@ -258,6 +278,15 @@ export function RunDetails({ run, tests }: RunDetailsProps) {
                   ? "cancelling"
                   : run.status}
               </Badge>
+              {run.smoke_status && (
+                <Badge
+                  variant="secondary"
+                  className={`mt-1 ml-2 ${getStatusBgColor(run.smoke_status === "passed" ? "completed" : "failed")}`}
+                  title={run.smoke_budget_ms ? `Smoke time budget: ${formatDuration(run.smoke_budget_ms)}` : undefined}
+                >
+                  smoke {run.smoke_status === "over_budget" ? "over budget" : run.smoke_status}
+                </Badge>
+              )}
             </div>
             <div>
               <p className="text-sm text-muted-foreground">Duration</p>
@@ -289,7 +318,7 @@ export function RunDetails({ run, tests }: RunDetailsProps) {
           </div>
 
           {/* Metadata */}
-          {(run.cli_version || run.docker_image) && (
+          {(run.cli_version || run.docker_image || run.sdk_python_version || run.sdk_typescript_version) && (
             <div className="mt-6 flex gap-4 border-t border-border pt-4">
               {run.cli_version && (
                 <div>
@@ -297,6 +326,18 @@ export function RunDetails({ run, tests }: RunDetailsProps) {
                   <p className="font-mono text-sm">{run.cli_version}</p>
                 </div>
               )}
+              {run.sdk_python_version && (
+                <div>
+                  <p className="text-xs text-muted-foreground">Python SDK</p>
+                  <p className="font-mono text-sm">{run.sdk_python_version}</p>
+                </div>
+              )}
+              {run.sdk_typescript_version && (
+                <div>
+                  <p className="text-xs text-muted-foreground">TypeScript SDK</p>
+                  <p className="font-mono text-sm">{run.sdk_typescript_version}</p>
+                </div>
+              )}
               {run.docker_image && (
                 <div>
                   <p className="text-xs text-muted-foreground">Docker Image</p>
@@ -358,6 +399,33 @@ export function RunDetails({ run, tests }: RunDetailsProps) {
         </Card>
       </div>
 
+      {/* Comments on the run */}
+      <Card className="rounded-md">
+        <CardContent className="p-4">
+          <RunComments runId={run.run_id} />
+        </CardContent>
+      </Card>
+
+      {/* Owner Filter */}
+      {owners.length > 0 && (
+        <div className="flex flex-wrap items-center gap-2">
+          <span className="text-sm text-muted-foreground">Owner:</span>
+          <Button size="sm" variant={owner === null ? "default" : "outline"} onClick={() => setOwner(null)}>
+            All
+          </Button>
+          {owners.map((o) => (
+            <Button
+              key={o}
+              size="sm"
+              variant={owner === o ? "default" : "outline"}
+              onClick={() => setOwner(owner === o ? null : o)}
+            >
+              {o}
+            </Button>
+          ))}
+        </div>
+      )}
+
       {/* Test Tree */}
       <TestTree
         useCases={useCases}
@@ -365,6 +433,7 @@ export function RunDetails({ run, tests }: RunDetailsProps) {
         onToggle={toggleExpand}
         onTestClick={handleTestClick}
         onRerunTest={handleRerunTest}
+        onCancelTest={canCancel && !isCancelRequested ? handleCancelTest : undefined}
         filter={filter}
         suiteId={run.suite_id}
       />
@@ -425,6 +494,7 @@ interface TestTreeProps {
   onToggle: (id: string) => void;
   onTestClick?: (test: TestResult) => void;
   onRerunTest?: (testId: string) => void;
+  onCancelTest?: (testId: string) => void;
   filter?: string | null;
   suiteId?: number | null;
 }
@@ -445,7 +515,7 @@ function getStatusIcon(status: string) {
   }
 }
 
-function TestTree({ useCases, expandedIds, onToggle, onTestClick, onRerunTest, filter, suiteId }: TestTreeProps) {
+function TestTree({ useCases, expandedIds, onToggle, onTestClick, onRerunTest, onCancelTest, filter, suiteId }: TestTreeProps) {
   if (!useCases || useCases.length === 0) {
     return (
       <Card>
@@ -562,6 +632,19 @@ function TestTree({ useCases, expandedIds, onToggle, onTestClick, onRerunTest, f
                               {formatDuration(test.duration_ms)}
                             </span>
                           )}
+                          {onCancelTest && !test.cancel_requested &&
+                            (test.status === "pending" || test.status === "running") && (
+                            <button
+                              onClick={(e) => {
+                                e.stopPropagation();
+                                onCancelTest(test.test_id);
+                              }}
+                              className="p-1 rounded hover:bg-destructive/20 opacity-0 group-hover:opacity-100 transition-opacity"
+                              title="Cancel this test"
+                            >
+                              <StopCircle className="h-3.5 w-3.5 text-destructive" />
+                            </button>
+                          )}
                           {suiteId && onRerunTest && (
                             <button
                               onClick={(e) => {
@@ -677,6 +760,12 @@ function TestDetailDialog({ open, onOpenChange, testDetail, loading, suiteId, on
                 <Badge variant={testDetail.status === "passed" ? "default" : "destructive"}>
                   {testDetail.status}
                 </Badge>
+                {testDetail.owners && testDetail.owners.length > 0 && (
+                  <div>
+                    <span className="text-muted-foreground">Owners: </span>
+                    <span>{testDetail.owners.join(", ")}</span>
+                  </div>
+                )}
               </div>
 
               {/* Error Message */}
@@ -689,6 +778,36 @@ function TestDetailDialog({ open, onOpenChange, testDetail, loading, suiteId, on
                 </div>
               )}
 
+              {/* Environment */}
+              {testDetail.environment && (
+                <div className="rounded-md bg-muted/50 p-3 text-xs font-mono text-muted-foreground">
+                  {testDetail.environment.os}/{testDetail.environment.arch} · {testDetail.environment.mode}
+                  {testDetail.environment.runner_version && <> · runner {testDetail.environment.runner_version}</>}
+                  {testDetail.environment.tsuite_version && <> · tsuite {testDetail.environment.tsuite_version}</>}
+                  {testDetail.environment.image_digest && <> · {testDetail.environment.image_digest}</>}
+                  {testDetail.environment.node_version && <> · node {testDetail.environment.node_version}</>}
+                  {testDetail.environment.env && Object.keys(testDetail.environment.env).length > 0 && (
+                    <pre className="mt-2 whitespace-pre-wrap">
+                      {Object.entries(testDetail.environment.env).map(([k, v]) => `${k}=${v}`).join("\n")}
+                    </pre>
+                  )}
+                </div>
+              )}
+
+              {/* Kept workdir of a failed test */}
+              {testDetail.workspace_path && (
+                <div className="rounded-md bg-muted/50 p-3 text-xs font-mono text-muted-foreground">
+                  Workspace kept at {testDetail.workspace_path}
+                </div>
+              )}
+
+              {/* Comments on the test */}
+              <RunComments
+                runId={testDetail.run_id}
+                testId={testDetail.test_id}
+                initial={testDetail.comments}
+              />
+
               {/* Steps */}
               {testDetail.steps && testDetail.steps.length > 0 && (
                 <div>
@@ -738,6 +857,24 @@ function TestDetailDialog({ open, onOpenChange, testDetail, loading, suiteId, on
                           </div>
                         )}
 
+                        {/* Resolved command / params */}
+                        {step.resolved_command && (
+                          <div className="mt-2">
+                            <p className="text-xs text-muted-foreground mb-1">command:</p>
+                            <pre className="p-2 rounded bg-muted text-xs font-mono overflow-x-auto whitespace-pre-wrap max-h-40">
+                              {step.resolved_command}
+                            </pre>
+                          </div>
+                        )}
+                        {step.resolved_params && Object.keys(step.resolved_params).length > 0 && (
+                          <div className="mt-2">
+                            <p className="text-xs text-muted-foreground mb-1">params:</p>
+                            <pre className="p-2 rounded bg-muted text-xs font-mono overflow-x-auto whitespace-pre-wrap max-h-40">
+                              {JSON.stringify(step.resolved_params, null, 2)}
+                            </pre>
+                          </div>
+                        )}
+
                         {/* Stdout */}
                         {step.stdout && (
                           <div className="mt-2">
@@ -830,6 +967,21 @@ function TestDetailDialog({ open, onOpenChange, testDetail, loading, suiteId, on
                                     )}>{assertion.actual_value}</code>
                                   </p>
                                 )}
+                                {assertion.diff && (
+                                  <pre className="mt-2 bg-muted p-2 rounded overflow-x-auto whitespace-pre">
+                                    {assertion.diff.split("\n").map((line, i) => (
+                                      <div
+                                        key={i}
+                                        className={cn(
+                                          line.startsWith("-") && !line.startsWith("---") && "text-destructive",
+                                          line.startsWith("+") && !line.startsWith("+++") && "text-success"
+                                        )}
+                                      >
+                                        {line}
+                                      </div>
+                                    ))}
+                                  </pre>
+                                )}
                               </div>
                             </div>
                           )}
@@ -173,6 +173,12 @@ export function RunsList({ initialRuns }: RunsListProps) {
                         {" • "}
                         {formatRelativeTime(run.started_at)}
                         {run.cli_version && ` • v${run.cli_version}`}
+                        {run.parent_run_id && (
+                          <>
+                            {" • rerun of "}
+                            <span className="font-mono">{run.parent_run_id.slice(0, 8)}</span>
+                          </>
+                        )}
                       </p>
                     </div>
                   </div>
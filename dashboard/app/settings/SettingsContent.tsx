@@ -17,6 +17,7 @@ import {
   Edit,
   X,
   Settings,
+  History,
 } from "lucide-react";
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
 import { Button } from "@/components/ui/button";
@@ -43,7 +44,7 @@ import {
   formatRelativeTime,
 } from "@/lib/api";
 import { TestCaseTree, TestCaseEditor } from "@/components/test-editor";
-import { SuiteConfigEditor } from "@/components/suite-editor";
+import { SuiteConfigEditor, SuiteAuditLog } from "@/components/suite-editor";
 
 interface SettingsContentProps {
   initialSuites: Suite[];
@@ -77,6 +78,9 @@ export function SettingsContent({ initialSuites }: SettingsContentProps) {
   // Config editor state
   const [configSuiteId, setConfigSuiteId] = useState<number | null>(null);
 
+  // Change history (audit log) state
+  const [historySuiteId, setHistorySuiteId] = useState<number | null>(null);
+
   // Load directory listing when dialog opens or path changes
   useEffect(() => {
     if (isAddDialogOpen) {
@@ -397,6 +401,14 @@ export function SettingsContent({ initialSuites }: SettingsContentProps) {
                         >
                           <Edit className="h-4 w-4" />
                         </Button>
+                        <Button
+                          variant="ghost"
+                          size="icon"
+                          onClick={() => setHistorySuiteId(suite.id)}
+                          title="Change history"
+                        >
+                          <History className="h-4 w-4" />
+                        </Button>
                         <Button
                           variant="ghost"
                           size="icon"
@@ -459,6 +471,30 @@ export function SettingsContent({ initialSuites }: SettingsContentProps) {
         </Card>
       )}
 
+      {/* Change History Section */}
+      {historySuiteId && (
+        <Card className="rounded-md">
+          <CardHeader className="flex flex-row items-center justify-between space-y-0 pb-4">
+            <CardTitle className="text-lg font-medium">
+              Change History -{" "}
+              {suites.find((s) => s.id === historySuiteId)?.suite_name}
+            </CardTitle>
+            <Button
+              variant="ghost"
+              size="icon"
+              onClick={() => setHistorySuiteId(null)}
+            >
+              <X className="h-4 w-4" />
+            </Button>
+          </CardHeader>
+          <CardContent>
+            <div className="border rounded-md overflow-hidden h-[600px]">
+              <SuiteAuditLog suiteId={historySuiteId} />
+            </div>
+          </CardContent>
+        </Card>
+      )}
+
       {/* Test Case Editor Section */}
       {editingSuiteId && (
         <Card className="rounded-md">
@@ -0,0 +1,147 @@
+"use client";
+
+import Link from "next/link";
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import { Badge } from "@/components/ui/badge";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from "@/components/ui/table";
+import {
+  OverviewResponse,
+  SuiteOverview,
+  formatDuration,
+  formatRelativeTime,
+} from "@/lib/api";
+import { CheckCircle, XCircle, Clock, Loader2, CircleDashed } from "lucide-react";
+
+interface SuitesOverviewProps {
+  overview: OverviewResponse;
+}
+
+function HealthBadge({ suite }: { suite: SuiteOverview }) {
+  switch (suite.health) {
+    case "passing":
+      return (
+        <Badge variant="secondary" className="gap-1 bg-success/10 text-success">
+          <CheckCircle className="h-3 w-3" />
+          passing
+        </Badge>
+      );
+    case "failing":
+      return (
+        <Badge variant="secondary" className="gap-1 bg-destructive/10 text-destructive">
+          <XCircle className="h-3 w-3" />
+          failing
+        </Badge>
+      );
+    default:
+      return (
+        <Badge variant="secondary" className="gap-1 text-muted-foreground">
+          <CircleDashed className="h-3 w-3" />
+          no runs
+        </Badge>
+      );
+  }
+}
+
+export function SuitesOverview({ overview }: SuitesOverviewProps) {
+  const { suites, summary } = overview;
+
+  return (
+    <Card className="border-border bg-card rounded-md">
+      <CardHeader className="flex flex-row items-center justify-between">
+        <CardTitle className="text-lg font-semibold">Suites</CardTitle>
+        {summary.total_suites > 0 && (
+          <span
+            className={`text-sm ${
+              summary.all_green ? "text-success" : "text-muted-foreground"
+            }`}
+          >
+            {summary.all_green
+              ? "All suites passing"
+              : `${summary.passing} passing · ${summary.failing} failing · ${summary.no_runs} without runs`}
+          </span>
+        )}
+      </CardHeader>
+      <CardContent>
+        <Table>
+          <TableHeader>
+            <TableRow className="border-border hover:bg-transparent">
+              <TableHead className="text-muted-foreground">Suite</TableHead>
+              <TableHead className="text-muted-foreground">Health</TableHead>
+              <TableHead className="text-muted-foreground">Pass Rate</TableHead>
+              <TableHead className="text-muted-foreground">Duration</TableHead>
+              <TableHead className="text-muted-foreground">Last Run</TableHead>
+            </TableRow>
+          </TableHeader>
+          <TableBody>
+            {suites.length === 0 ? (
+              <TableRow>
+                <TableCell
+                  colSpan={5}
+                  className="py-8 text-center text-muted-foreground"
+                >
+                  No suites registered
+                </TableCell>
+              </TableRow>
+            ) : (
+              suites.map((suite) => (
+                <TableRow
+                  key={suite.suite_id}
+                  className="border-border hover:bg-muted/50"
+                >
+                  <TableCell>
+                    <div className="font-medium">{suite.suite_name}</div>
+                    <div className="text-xs text-muted-foreground">
+                      {suite.test_count} tests · {suite.mode}
+                    </div>
+                  </TableCell>
+                  <TableCell>
+                    <div className="flex items-center gap-2">
+                      <HealthBadge suite={suite} />
+                      {suite.active_run_id && (
+                        <Link
+                          href="/live"
+                          className="flex items-center gap-1 text-xs text-primary hover:underline"
+                        >
+                          <Loader2 className="h-3 w-3 animate-spin" />
+                          running
+                        </Link>
+                      )}
+                    </div>
+                  </TableCell>
+                  <TableCell>
+                    {suite.pass_rate !== null ? `${suite.pass_rate}%` : "-"}
+                  </TableCell>
+                  <TableCell>
+                    <div className="flex items-center gap-1 text-muted-foreground">
+                      <Clock className="h-3 w-3" />
+                      {formatDuration(suite.last_run?.duration_ms ?? null)}
+                    </div>
+                  </TableCell>
+                  <TableCell className="text-muted-foreground">
+                    {suite.last_run ? (
+                      <Link
+                        href={`/runs?id=${suite.last_run.run_id}`}
+                        className="hover:underline"
+                      >
+                        {formatRelativeTime(suite.last_run.started_at)}
+                      </Link>
+                    ) : (
+                      "-"
+                    )}
+                  </TableCell>
+                </TableRow>
+              ))
+            )}
+          </TableBody>
+        </Table>
+      </CardContent>
+    </Card>
+  );
+}
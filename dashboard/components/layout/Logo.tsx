@@ -3,13 +3,14 @@
 interface LogoProps {
   className?: string;
   size?: number;
+  src?: string; // Logo of tsuite api --logo, else the tsuite logo
 }
 
-export function Logo({ className = "", size = 32 }: LogoProps) {
+export function Logo({ className = "", size = 32, src }: LogoProps) {
   return (
     <img
-      src="/logo.svg"
-      alt="tsuite logo"
+      src={src || `${process.env.NEXT_PUBLIC_BASE_PATH || ""}/logo.svg`}
+      alt="logo"
       width={size}
       height={size}
       className={className}
@@ -2,7 +2,12 @@
 
 import { ReactNode } from "react";
 import { LiveRunProvider } from "@/lib/live-run-context";
+import { BrandingProvider } from "@/lib/branding-context";
 
 export function Providers({ children }: { children: ReactNode }) {
-  return <LiveRunProvider>{children}</LiveRunProvider>;
+  return (
+    <BrandingProvider>
+      <LiveRunProvider>{children}</LiveRunProvider>
+    </BrandingProvider>
+  );
 }
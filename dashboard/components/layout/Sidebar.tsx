@@ -2,15 +2,17 @@
 
 import Link from "next/link";
 import { usePathname } from "next/navigation";
-import { LayoutDashboard, History, Radio, Settings, FolderTree } from "lucide-react";
+import { LayoutDashboard, History, Radio, Settings, FolderTree, Grid3x3 } from "lucide-react";
 import { cn } from "@/lib/utils";
 import { useLiveRun } from "@/lib/live-run-context";
+import { useBranding } from "@/lib/branding-context";
 import { Logo } from "./Logo";
 
 const navigation = [
   { name: "Dashboard", href: "/", icon: LayoutDashboard },
   { name: "Runs", href: "/runs", icon: History },
   { name: "Tests", href: "/tests", icon: FolderTree },
+  { name: "Coverage", href: "/coverage", icon: Grid3x3 },
   { name: "Live", href: "/live", icon: Radio },
   { name: "Settings", href: "/settings", icon: Settings },
 ];
@@ -18,14 +20,15 @@ const navigation = [
 export function Sidebar() {
   const pathname = usePathname();
   const { currentRunId } = useLiveRun();
+  const branding = useBranding();
 
   return (
     <aside className="flex h-screen w-64 flex-col border-r border-border bg-sidebar">
       {/* Logo */}
       <div className="flex h-16 items-center gap-3 border-b border-border px-6">
-        <Logo size={56} />
-        <span className="text-xl font-semibold text-sidebar-foreground">
-          tsuite
+        <Logo size={56} src={branding.logo_url} />
+        <span className="truncate text-xl font-semibold text-sidebar-foreground" title={branding.title}>
+          {branding.title}
         </span>
       </div>
 
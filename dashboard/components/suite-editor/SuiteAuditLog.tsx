@@ -0,0 +1,147 @@
+"use client";
+
+import { useState, useEffect } from "react";
+import { Loader2, ChevronDown, ChevronRight, FileText } from "lucide-react";
+import { Button } from "@/components/ui/button";
+import { Badge } from "@/components/ui/badge";
+import { ScrollArea } from "@/components/ui/scroll-area";
+import { AuditEntry, getAuditLog, formatRelativeTime } from "@/lib/api";
+import { cn } from "@/lib/utils";
+
+interface SuiteAuditLogProps {
+  suiteId: number;
+}
+
+const PAGE_SIZE = 50;
+
+const actionStyles: Record<AuditEntry["action"], string> = {
+  create: "border-success/50 text-success",
+  update: "border-blue-500/50 text-blue-500",
+  delete: "border-destructive/50 text-destructive",
+};
+
+// Colors a unified diff line by its prefix
+function diffLineClass(line: string): string {
+  if (line.startsWith("+++") || line.startsWith("---")) return "text-muted-foreground";
+  if (line.startsWith("@@")) return "text-primary";
+  if (line.startsWith("+")) return "text-success";
+  if (line.startsWith("-")) return "text-destructive";
+  return "";
+}
+
+export function SuiteAuditLog({ suiteId }: SuiteAuditLogProps) {
+  const [entries, setEntries] = useState<AuditEntry[]>([]);
+  const [total, setTotal] = useState(0);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [expanded, setExpanded] = useState<Set<number>>(new Set());
+
+  useEffect(() => {
+    setEntries([]);
+    setExpanded(new Set());
+    loadEntries(0);
+  }, [suiteId]);
+
+  const loadEntries = async (offset: number) => {
+    setLoading(true);
+    setError(null);
+    try {
+      const data = await getAuditLog({ suite_id: suiteId, limit: PAGE_SIZE, offset });
+      setEntries((prev) => (offset === 0 ? data.entries : [...prev, ...data.entries]));
+      setTotal(data.total);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to load history");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const toggle = (id: number) => {
+    setExpanded((prev) => {
+      const next = new Set(prev);
+      if (next.has(id)) {
+        next.delete(id);
+      } else {
+        next.add(id);
+      }
+      return next;
+    });
+  };
+
+  if (error && entries.length === 0) {
+    return (
+      <div className="text-center py-12 text-destructive">
+        <p>{error}</p>
+        <Button variant="outline" onClick={() => loadEntries(0)} className="mt-4">
+          Retry
+        </Button>
+      </div>
+    );
+  }
+
+  if (!loading && entries.length === 0) {
+    return (
+      <div className="flex flex-col items-center justify-center py-12 text-muted-foreground">
+        <FileText className="h-12 w-12 opacity-50 mb-4" />
+        <p className="text-sm">No changes made through the dashboard or API yet</p>
+      </div>
+    );
+  }
+
+  return (
+    <ScrollArea className="h-full">
+      <div className="p-2 space-y-1">
+        {entries.map((entry) => {
+          const isExpanded = expanded.has(entry.id);
+          return (
+            <div key={entry.id} className="rounded-md border">
+              <button
+                className="flex w-full items-center gap-3 px-3 py-2 text-left hover:bg-muted/50"
+                onClick={() => toggle(entry.id)}
+              >
+                {isExpanded ? (
+                  <ChevronDown className="h-4 w-4 shrink-0" />
+                ) : (
+                  <ChevronRight className="h-4 w-4 shrink-0" />
+                )}
+                <Badge variant="outline" className={actionStyles[entry.action]}>
+                  {entry.action}
+                </Badge>
+                <span className="font-mono text-sm truncate flex-1">{entry.path}</span>
+                <span className="text-xs text-muted-foreground whitespace-nowrap">
+                  {entry.actor} · {formatRelativeTime(entry.created_at)}
+                </span>
+              </button>
+              {isExpanded && (
+                <div className="border-t bg-muted/30 px-3 py-2">
+                  <p className="text-xs text-muted-foreground mb-2">
+                    {entry.endpoint} · {new Date(entry.created_at).toLocaleString()}
+                  </p>
+                  <pre className="text-xs font-mono overflow-x-auto">
+                    {entry.diff.split("\n").map((line, i) => (
+                      <div key={i} className={cn(diffLineClass(line))}>
+                        {line || " "}
+                      </div>
+                    ))}
+                  </pre>
+                </div>
+              )}
+            </div>
+          );
+        })}
+        {loading && (
+          <div className="flex justify-center py-4">
+            <Loader2 className="h-6 w-6 animate-spin text-muted-foreground" />
+          </div>
+        )}
+        {!loading && entries.length < total && (
+          <div className="flex justify-center py-2">
+            <Button variant="outline" size="sm" onClick={() => loadEntries(entries.length)}>
+              Load more ({total - entries.length})
+            </Button>
+          </div>
+        )}
+      </div>
+    </ScrollArea>
+  );
+}
@@ -48,6 +48,8 @@ export function SuiteConfigEditor({
   const [error, setError] = useState<string | null>(null);
   const [saveSuccess, setSaveSuccess] = useState(false);
   const [configData, setConfigData] = useState<SuiteConfigResponse | null>(null);
+  // Version of config.yaml our edits are based on; saving fails if it changed since
+  const [etag, setEtag] = useState<string | undefined>(undefined);
   const [structure, setStructure] = useState<SuiteConfigStructure | null>(null);
   const [hasChanges, setHasChanges] = useState(false);
 
@@ -63,6 +65,7 @@ export function SuiteConfigEditor({
     try {
       const data = await getSuiteConfig(suiteId);
       setConfigData(data);
+      setEtag(data.etag);
       setStructure(data.structure);
     } catch (err) {
       setError(err instanceof Error ? err.message : "Failed to load config");
@@ -90,7 +93,8 @@ export function SuiteConfigEditor({
         };
       }
 
-      await updateSuiteConfig(suiteId, saveStructure);
+      const result = await updateSuiteConfig(suiteId, saveStructure, etag);
+      setEtag(result.etag);
       setHasChanges(false);
       setSaveSuccess(true);
       setTimeout(() => setSaveSuccess(false), 3000);
@@ -1 +1,2 @@
 export { SuiteConfigEditor } from "./SuiteConfigEditor";
+export { SuiteAuditLog } from "./SuiteAuditLog";
@@ -73,6 +73,8 @@ export function TestCaseEditor({
   const [error, setError] = useState<string | null>(null);
   const [saveMessage, setSaveMessage] = useState<"saved" | "auto-saved" | null>(null);
   const [yamlData, setYamlData] = useState<TestCaseYaml | null>(null);
+  // Version of test.yaml our edits are based on; writes fail if it changed since
+  const [etag, setEtag] = useState<string | undefined>(undefined);
   const [structure, setStructure] = useState<TestCaseStructure | null>(null);
   const [originalStructure, setOriginalStructure] = useState<TestCaseStructure | null>(null);
   const [changedFields, setChangedFields] = useState<Set<keyof TestCaseStructure>>(new Set());
@@ -92,6 +94,7 @@ export function TestCaseEditor({
     try {
       const data = await getTestCaseYaml(suiteId, testId);
       setYamlData(data);
+      setEtag(data.etag);
       setStructure(data.structure);
       // Store a deep copy of original for comparison
       setOriginalStructure(JSON.parse(JSON.stringify(data.structure)));
@@ -115,7 +118,8 @@ export function TestCaseEditor({
         updates[field] = structure[field] as any;
       });
 
-      await updateTestCaseYaml(suiteId, testId, { updates });
+      const result = await updateTestCaseYaml(suiteId, testId, { updates }, etag);
+      setEtag(result.etag);
       setChangedFields(new Set());
       // Update original to match current after successful save
       setOriginalStructure(JSON.parse(JSON.stringify(structure)));
@@ -161,7 +165,8 @@ export function TestCaseEditor({
     try {
       // Call API to update step (preserves YAML comments)
       // Note: Steps auto-save immediately, no Save button needed
-      await updateTestStep(suiteId, testId, phase, index, updates);
+      const result = await updateTestStep(suiteId, testId, phase, index, updates, etag);
+      setEtag(result.etag);
       // Update local state for display
       const steps = [...(structure[phase] || [])];
       steps[index] = { ...steps[index], ...updates };
@@ -179,7 +184,8 @@ export function TestCaseEditor({
     const newStep: TestStep = { name: "New step", handler: "shell", command: "" };
     try {
       // Call API to add step (auto-saves immediately)
-      await addTestStep(suiteId, testId, phase, newStep);
+      const result = await addTestStep(suiteId, testId, phase, newStep, undefined, etag);
+      setEtag(result.etag);
       // Update local state for display
       const steps = [...(structure[phase] || []), newStep];
       setStructure({ ...structure, [phase]: steps });
@@ -195,7 +201,8 @@ export function TestCaseEditor({
     if (!structure) return;
     try {
       // Call API to delete step (auto-saves immediately)
-      await deleteTestStep(suiteId, testId, phase, index);
+      const result = await deleteTestStep(suiteId, testId, phase, index, etag);
+      setEtag(result.etag);
       // Update local state for display
       const steps = [...(structure[phase] || [])];
       steps.splice(index, 1);
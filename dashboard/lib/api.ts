@@ -2,7 +2,10 @@
  * API client for tsuite backend
  */
 
-const API_BASE = process.env.NEXT_PUBLIC_API_URL || "http://localhost:9999";
+// The embedded dashboard is served by the API server, under its --base-path
+const API_BASE =
+  process.env.NEXT_PUBLIC_API_URL ||
+  (process.env.NODE_ENV === "development" ? "http://localhost:9999" : process.env.NEXT_PUBLIC_BASE_PATH || "");
 
 export interface RunFilters {
   uc?: string[];
@@ -17,7 +20,7 @@ export interface Run {
   display_name: string | null;  // Computed: tc name, uc name, or suite name
   started_at: string | null;
   finished_at: string | null;
-  status: "pending" | "running" | "completed" | "failed" | "cancelled";
+  status: "queued" | "pending" | "running" | "completed" | "failed" | "cancelled";
   total_tests: number;
   pending_count: number;
   running_count: number;
@@ -26,10 +29,20 @@ export interface Run {
   skipped: number;
   duration_ms: number | null;
   cli_version: string | null;
+  sdk_python_version?: string | null;      // mcp-mesh, pinned in config.yaml or detected
+  sdk_typescript_version?: string | null;  // @mcpmesh/core, pinned in config.yaml or detected
   docker_image: string | null;
   filters: RunFilters | null;
   mode: string | null;
+  profile?: string | null;                 // Run profile from config.yaml
   cancel_requested: boolean;
+  parent_run_id: string | null;  // Set when this run is a rerun of another run
+  pipeline_run_id: string | null;  // Set when this run is part of a pipeline run
+  runner_version?: string | null;  // tsuite-runner version the run executed with
+  runner_sha256?: string | null;
+  workers?: number | null;  // Parallel test runners the run used
+  smoke_status?: "passed" | "failed" | "over_budget" | null;  // Set for tsuite run --smoke
+  smoke_budget_ms?: number | null;
 }
 
 export interface RunSummary extends Run {
@@ -47,13 +60,57 @@ export interface TestResult {
   started_at: string | null;
   finished_at: string | null;
   duration_ms: number | null;
+  queued_at?: string | null;
+  queue_wait_ms?: number | null;  // Time the test waited for a worker
   error_message: string | null;
   tags: string[];
+  covers?: string[] | null;        // mcp-mesh feature IDs from covers: in test.yaml
+  owners?: string[] | null;        // From test.yaml, else config.yaml owners
+  cancel_requested?: boolean;     // Cancelled on its own from the dashboard
+}
+
+export interface TestEnvironment {
+  tsuite_version?: string;
+  runner_version?: string;
+  os: string;
+  arch: string;
+  go_version: string;
+  hostname?: string;
+  mode: string;
+  image?: string;
+  image_digest?: string;
+  node_version?: string;
+  env?: Record<string, string>;
+}
+
+export interface TestContainer {
+  id: string;
+  image: string;
+  exit_code: number;
+  oom_killed: boolean;
+  error?: string;
+  log_path?: string;
+  ports?: Record<string, string>;
+  kept_until?: string;
 }
 
 export interface TestDetail extends TestResult {
   steps: StepResult[];
   assertions: AssertionResult[];
+  environment: TestEnvironment | null;
+  container: TestContainer | null;
+  workspace_path: string | null;
+  comments?: Comment[];
+}
+
+// Note left on a run, or on one of its tests
+export interface Comment {
+  id: number;
+  run_id: string;
+  test_id?: string;  // Unset for comments on the run
+  author: string;
+  text: string;
+  created_at: string;
 }
 
 export interface StepResult {
@@ -68,6 +125,8 @@ export interface StepResult {
   stdout: string | null;
   stderr: string | null;
   error_message: string | null;
+  resolved_command: string | null;
+  resolved_params: Record<string, unknown> | null;
 }
 
 export interface AssertionResult {
@@ -79,6 +138,7 @@ export interface AssertionResult {
   passed: boolean;
   actual_value: string | null;
   expected_value: string | null;
+  diff: string | null;
 }
 
 export interface Stats {
@@ -204,6 +264,68 @@ export async function getStats(): Promise<Stats> {
   return res.json();
 }
 
+export interface SuiteOverview {
+  suite_id: number;
+  suite_name: string;
+  folder_path: string;
+  mode: "docker" | "standalone";
+  test_count: number;
+  health: "passing" | "failing" | "no_runs";
+  pass_rate: number | null;
+  last_run: Run | null;
+  active_run_id: string | null;
+}
+
+export interface OverviewResponse {
+  suites: SuiteOverview[];
+  summary: {
+    total_suites: number;
+    passing: number;
+    failing: number;
+    no_runs: number;
+    running: number;
+    all_green: boolean;
+  };
+}
+
+export async function getOverview(): Promise<OverviewResponse> {
+  const res = await fetch(`${API_BASE}/api/overview`, { cache: "no-store" });
+  if (!res.ok) throw new Error("Failed to fetch overview");
+  return res.json();
+}
+
+export type CoverageStatus = "passing" | "failing" | "not_run" | "uncovered";
+
+export interface FeatureCoverage {
+  feature: string;                  // Feature ID from covers: in test.yaml
+  status: CoverageStatus;
+  passed: number;
+  failed: number;                   // Failed or crashed
+  tests: { test_id: string; status: TestResult["status"] }[];
+}
+
+export interface CoverageResponse {
+  run_id: string;
+  suite_id: number | null;
+  features: FeatureCoverage[];      // Gaps first
+  unlisted: string[];               // Covered, but missing from coverage.features
+  summary: {
+    total_features: number;
+    passing: number;
+    failing: number;
+    not_run: number;
+    uncovered: number;
+  };
+}
+
+// Coverage of the latest finished run of a suite, or of the latest run
+export async function getCoverage(suiteId?: number): Promise<CoverageResponse> {
+  const query = suiteId !== undefined ? `?suite_id=${suiteId}` : "";
+  const res = await fetch(`${API_BASE}/api/coverage${query}`, { cache: "no-store" });
+  if (!res.ok) throw new Error("Failed to fetch coverage");
+  return res.json();
+}
+
 export async function getFlakyTests(limit = 20): Promise<{ tests: unknown[]; count: number }> {
   const res = await fetch(`${API_BASE}/api/stats/flaky?limit=${limit}`, {
     cache: "no-store",
@@ -220,6 +342,27 @@ export async function getSlowestTests(limit = 10): Promise<{ tests: unknown[]; c
   return res.json();
 }
 
+export interface RunScheduling {
+  run_id: string;
+  workers: number | null;
+  tests_started: number;
+  wall_ms: number;
+  exec_ms: number;
+  queue_wait_total_ms: number;
+  queue_wait_avg_ms: number;
+  queue_wait_max_ms: number;
+  parallelism: number;         // Average number of tests running at once
+  utilization: number | null;  // Parallelism as a percentage of workers
+}
+
+export async function getRunScheduling(runId: string): Promise<RunScheduling> {
+  const res = await fetch(`${API_BASE}/api/runs/${runId}/scheduling`, {
+    cache: "no-store",
+  });
+  if (!res.ok) throw new Error("Failed to fetch run scheduling");
+  return res.json();
+}
+
 // Suite API Functions
 
 export async function getSuites(): Promise<SuitesResponse> {
@@ -278,15 +421,32 @@ export async function browseFolders(path?: string): Promise<BrowseResponse> {
 
 export interface RunResponse {
   started: boolean;
-  pid: number;
+  queued: boolean;
+  queue_position?: number; // While queued (1 = next)
+  run_id?: string;
+  pid?: number;            // Once started
+  in_process?: boolean;    // Started in the API server (no pid)
   description: string;
   mode: string;
   command: string;
 }
 
+export interface RunOptions {
+  uc?: string;
+  tc?: string;
+  tags?: string[];
+  skip_tags?: string[];
+  parallel?: number;                // Default: the suite's max_workers
+  mode?: "docker" | "standalone";   // Instead of the suite's mode
+  profile?: string;                 // From the suite's config.yaml profiles
+  env?: Record<string, string>;     // Set for every test
+  smoke?: boolean;                  // Only smoke tests, within smoke.yaml's time budget
+  changed_since?: string;           // Only tests affected by git changes since this ref
+}
+
 export async function runTests(
   suiteId: number,
-  options?: { uc?: string; tc?: string; tags?: string[] }
+  options?: RunOptions
 ): Promise<RunResponse> {
   const res = await fetch(`${API_BASE}/api/suites/${suiteId}/run`, {
     method: "POST",
@@ -300,6 +460,41 @@ export async function runTests(
   return res.json();
 }
 
+export interface TestRunResponse {
+  started: boolean;
+  queued: boolean;
+  queue_position?: number;
+  run_id: string;
+  test_id: string;
+  pid?: number;
+  in_process?: boolean;
+  log_file: string;
+  // Set with wait: the run's status, with timed_out while queued or running
+  status?: string;
+  timed_out?: boolean;
+  run?: Run;
+  result?: TestResult | null;
+}
+
+export async function runSingleTest(
+  suiteId: number,
+  testId: string,
+  options?: { wait?: boolean; timeout?: string }
+): Promise<TestRunResponse> {
+  const params = new URLSearchParams();
+  if (options?.wait) params.set("wait", "true");
+  if (options?.timeout) params.set("timeout", options.timeout);
+  const query = params.toString() ? `?${params}` : "";
+  const res = await fetch(`${API_BASE}/api/suites/${suiteId}/tests/${testId}/run${query}`, {
+    method: "POST",
+  });
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to start test run");
+  }
+  return res.json();
+}
+
 export interface RerunResponse extends RunResponse {
   original_run_id: string;
 }
@@ -333,6 +528,25 @@ export async function cancelRun(runId: string): Promise<CancelResponse> {
   return res.json();
 }
 
+export interface CancelTestResponse {
+  success: boolean;
+  run_id: string;
+  test_id: string;
+  status: TestResult["status"];
+  cancel_requested: boolean;
+}
+
+export async function cancelTest(runId: string, testId: string): Promise<CancelTestResponse> {
+  const res = await fetch(`${API_BASE}/api/runs/${runId}/test/${testId}/cancel`, {
+    method: "POST",
+  });
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to cancel test");
+  }
+  return res.json();
+}
+
 export interface DeleteResponse {
   success: boolean;
   run_id: string;
@@ -350,6 +564,45 @@ export async function deleteRun(runId: string): Promise<DeleteResponse> {
   return res.json();
 }
 
+// Comments on the run itself with testId "", on one test with its ID, or on
+// the run and all its tests without one
+export async function getRunComments(runId: string, testId?: string): Promise<Comment[]> {
+  const query = testId !== undefined ? `?test_id=${encodeURIComponent(testId)}` : "";
+  const res = await fetch(`${API_BASE}/api/runs/${runId}/comments${query}`, { cache: "no-store" });
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to fetch comments");
+  }
+  const data: { comments: Comment[] } = await res.json();
+  return data.comments;
+}
+
+export async function addRunComment(
+  runId: string,
+  req: { text: string; test_id?: string; author?: string }
+): Promise<Comment> {
+  const res = await fetch(`${API_BASE}/api/runs/${runId}/comments`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(req),
+  });
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to add comment");
+  }
+  return res.json();
+}
+
+export async function deleteRunComment(runId: string, commentId: number): Promise<void> {
+  const res = await fetch(`${API_BASE}/api/runs/${runId}/comments/${commentId}`, {
+    method: "DELETE",
+  });
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to delete comment");
+  }
+}
+
 // Run Tree API Functions
 
 export interface RunTestTreeUseCase {
@@ -448,6 +701,7 @@ export function getStatusBgColor(status: string): string {
     case "running":
       return "bg-primary/20 text-primary";
     case "pending":
+    case "queued":
       return "bg-muted text-muted-foreground";
     case "skipped":
     case "cancelled":
@@ -461,12 +715,28 @@ export function getStatusBgColor(status: string): string {
 // Test Case Editor API Functions
 // ============================================================================
 
+// Request headers for a YAML editor write. etag is the one returned by the last
+// GET or write of the file; the API answers 409 if the file changed since.
+function editorHeaders(etag?: string): Record<string, string> {
+  const headers: Record<string, string> = { "Content-Type": "application/json" };
+  if (etag) {
+    headers["If-Match"] = etag;
+  }
+  return headers;
+}
+
+async function editorError(res: Response, fallback: string): Promise<Error> {
+  const error = await res.json();
+  return new Error(error.error || fallback);
+}
+
 export interface TestCaseYaml {
   suite_id: number;
   test_id: string;
   path: string;
   raw_yaml: string;
   structure: TestCaseStructure;
+  etag: string;
 }
 
 export interface TestCaseStructure {
@@ -512,6 +782,7 @@ export interface TestStepsResponse {
   test: TestStep[];
   post_run: TestStep[];
   assertions: TestAssertion[];
+  etag: string;
 }
 
 export async function getTestCaseYaml(
@@ -532,19 +803,19 @@ export async function getTestCaseYaml(
 export async function updateTestCaseYaml(
   suiteId: number,
   testId: string,
-  options: { raw_yaml?: string; updates?: Partial<TestCaseStructure> }
-): Promise<{ success: boolean; test_id: string; raw_yaml: string }> {
+  options: { raw_yaml?: string; updates?: Partial<TestCaseStructure> },
+  etag?: string
+): Promise<{ success: boolean; test_id: string; raw_yaml: string; etag: string }> {
   const res = await fetch(
     `${API_BASE}/api/suites/${suiteId}/test-yaml/${testId}`,
     {
       method: "PUT",
-      headers: { "Content-Type": "application/json" },
+      headers: editorHeaders(etag),
       body: JSON.stringify(options),
     }
   );
   if (!res.ok) {
-    const error = await res.json();
-    throw new Error(error.error || "Failed to update test YAML");
+    throw await editorError(res, "Failed to update test YAML");
   }
   return res.json();
 }
@@ -569,19 +840,19 @@ export async function updateTestStep(
   testId: string,
   phase: "pre_run" | "test" | "post_run",
   index: number,
-  step: Partial<TestStep>
-): Promise<{ success: boolean }> {
+  step: Partial<TestStep>,
+  etag?: string
+): Promise<{ success: boolean; etag: string }> {
   const res = await fetch(
     `${API_BASE}/api/suites/${suiteId}/test-step/${phase}/${index}/${testId}`,
     {
       method: "PUT",
-      headers: { "Content-Type": "application/json" },
+      headers: editorHeaders(etag),
       body: JSON.stringify(step),
     }
   );
   if (!res.ok) {
-    const error = await res.json();
-    throw new Error(error.error || "Failed to update step");
+    throw await editorError(res, "Failed to update step");
   }
   return res.json();
 }
@@ -591,19 +862,19 @@ export async function addTestStep(
   testId: string,
   phase: "pre_run" | "test" | "post_run",
   step: TestStep,
-  index?: number
-): Promise<{ success: boolean }> {
+  index?: number,
+  etag?: string
+): Promise<{ success: boolean; etag: string }> {
   const res = await fetch(
     `${API_BASE}/api/suites/${suiteId}/test-step/${phase}/${testId}`,
     {
       method: "POST",
-      headers: { "Content-Type": "application/json" },
+      headers: editorHeaders(etag),
       body: JSON.stringify({ step, index }),
     }
   );
   if (!res.ok) {
-    const error = await res.json();
-    throw new Error(error.error || "Failed to add step");
+    throw await editorError(res, "Failed to add step");
   }
   return res.json();
 }
@@ -612,15 +883,112 @@ export async function deleteTestStep(
   suiteId: number,
   testId: string,
   phase: "pre_run" | "test" | "post_run",
-  index: number
-): Promise<{ success: boolean }> {
+  index: number,
+  etag?: string
+): Promise<{ success: boolean; etag: string }> {
   const res = await fetch(
     `${API_BASE}/api/suites/${suiteId}/test-step/${phase}/${index}/${testId}`,
-    { method: "DELETE" }
+    { method: "DELETE", headers: editorHeaders(etag) }
   );
+  if (!res.ok) {
+    throw await editorError(res, "Failed to delete step");
+  }
+  return res.json();
+}
+
+// ============================================================================
+// Test Case Management API Functions
+// ============================================================================
+
+export interface CreateTestRequest {
+  uc: string;
+  tc: string;
+  name?: string;
+  raw_yaml?: string;
+  agents?: string[]; // Agent directories on the API host to scaffold from
+  filter?: string;
+  artifact_level?: "tc" | "uc";
+  symlink?: boolean;
+  force?: boolean;
+}
+
+export async function createTest(
+  suiteId: number,
+  req: CreateTestRequest
+): Promise<{ success: boolean; test_id: string; path: string; raw_yaml: string; output: string }> {
+  const res = await fetch(`${API_BASE}/api/suites/${suiteId}/tests`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(req),
+  });
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to create test");
+  }
+  return res.json();
+}
+
+export async function cloneTest(
+  suiteId: number,
+  req: { from: string; to: string; name?: string; artifacts?: "copy" | "link" | "none"; force?: boolean }
+): Promise<{ success: boolean; test_id: string; path: string; raw_yaml: string; output: string }> {
+  const res = await fetch(`${API_BASE}/api/suites/${suiteId}/tests/clone`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(req),
+  });
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to clone test");
+  }
+  return res.json();
+}
+
+export async function deleteTest(
+  suiteId: number,
+  testId: string
+): Promise<{ success: boolean; test_id: string; removed_artifacts: string[]; use_case_removed: boolean }> {
+  const res = await fetch(`${API_BASE}/api/suites/${suiteId}/tests/${testId}`, {
+    method: "DELETE",
+  });
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to delete test");
+  }
+  return res.json();
+}
+
+export async function getTestHistory(
+  suiteId: number,
+  testId: string,
+  limit = 20
+): Promise<{ suite_id: number; test_id: string; former_ids: string[]; results: TestResult[]; count: number }> {
+  const res = await fetch(`${API_BASE}/api/suites/${suiteId}/test-history/${testId}?limit=${limit}`);
   if (!res.ok) {
     const error = await res.json();
-    throw new Error(error.error || "Failed to delete step");
+    throw new Error(error.error || "Failed to fetch test history");
+  }
+  return res.json();
+}
+
+// Environment of a result: run versions and options plus the captured
+// environment (env.<NAME> for captured variables)
+export type EnvFingerprint = Record<string, string>;
+
+export interface TestEnvDiff {
+  suite_id: number;
+  test_id: string;
+  passed: { run_id: string; started_at: string | null; fingerprint: EnvFingerprint };
+  failed: { run_id: string; status: TestResult["status"]; started_at: string | null; fingerprint: EnvFingerprint };
+  differences: { key: string; passed: string; failed: string }[];
+  identical: boolean;
+}
+
+export async function getTestEnvDiff(suiteId: number, testId: string): Promise<TestEnvDiff> {
+  const res = await fetch(`${API_BASE}/api/suites/${suiteId}/test-env-diff/${testId}`);
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to compare test environments");
   }
   return res.json();
 }
@@ -665,6 +1033,7 @@ export interface SuiteConfigResponse {
   path: string;
   raw_yaml: string;
   structure: SuiteConfigStructure;
+  etag: string;
 }
 
 export async function getSuiteConfig(
@@ -682,16 +1051,135 @@ export async function getSuiteConfig(
 
 export async function updateSuiteConfig(
   suiteId: number,
-  updates: Partial<SuiteConfigStructure>
-): Promise<{ success: boolean; suite_id: number; raw_yaml: string }> {
+  updates: Partial<SuiteConfigStructure>,
+  etag?: string
+): Promise<{ success: boolean; raw_yaml: string; etag: string }> {
   const res = await fetch(`${API_BASE}/api/suites/${suiteId}/config`, {
     method: "PUT",
-    headers: { "Content-Type": "application/json" },
+    headers: editorHeaders(etag),
     body: JSON.stringify({ updates }),
   });
+  if (!res.ok) {
+    throw await editorError(res, "Failed to update config");
+  }
+  return res.json();
+}
+
+// ============================================================================
+// Routines Editor API Functions
+// ============================================================================
+
+export interface RoutinesResponse {
+  suite_id: number;
+  scope: string; // "global" or the use case name
+  path: string;
+  exists: boolean;
+  raw_yaml: string;
+  structure: { routines?: Record<string, unknown> } | null;
+  library_files: string[]; // routines/*.yaml, merged at load time but not editable here
+  etag: string;
+}
+
+export interface RoutinesUpdateResponse {
+  success: boolean;
+  suite_id: number;
+  scope: string;
+  raw_yaml: string;
+  routines: string[];
+  etag: string;
+}
+
+function routinesUrl(suiteId: number, uc?: string): string {
+  return uc
+    ? `${API_BASE}/api/suites/${suiteId}/uc/${encodeURIComponent(uc)}/routines`
+    : `${API_BASE}/api/suites/${suiteId}/routines`;
+}
+
+export async function getRoutines(
+  suiteId: number,
+  uc?: string
+): Promise<RoutinesResponse> {
+  const res = await fetch(routinesUrl(suiteId, uc), { cache: "no-store" });
   if (!res.ok) {
     const error = await res.json();
-    throw new Error(error.error || "Failed to update config");
+    throw new Error(error.error || "Failed to fetch routines");
   }
   return res.json();
 }
+
+export async function updateRoutines(
+  suiteId: number,
+  body: { raw_yaml: string } | { updates: Record<string, unknown> },
+  uc?: string,
+  etag?: string
+): Promise<RoutinesUpdateResponse> {
+  const res = await fetch(routinesUrl(suiteId, uc), {
+    method: "PUT",
+    headers: editorHeaders(etag),
+    body: JSON.stringify(body),
+  });
+  if (!res.ok) {
+    throw await editorError(res, "Failed to update routines");
+  }
+  return res.json();
+}
+
+// ============================================================================
+// Audit Log API Functions
+// ============================================================================
+
+export interface AuditEntry {
+  id: number;
+  suite_id: number | null;
+  path: string; // Relative to the suite folder
+  action: "create" | "update" | "delete";
+  endpoint: string;
+  actor: string;
+  diff: string; // Unified diff
+  created_at: string;
+}
+
+export interface AuditResponse {
+  entries: AuditEntry[];
+  count: number;
+  total: number;
+  limit: number;
+  offset: number;
+}
+
+export async function getAuditLog(filters: {
+  suite_id?: number;
+  path?: string;
+  since?: string;
+  limit?: number;
+  offset?: number;
+} = {}): Promise<AuditResponse> {
+  const params = new URLSearchParams();
+  Object.entries(filters).forEach(([key, value]) => {
+    if (value !== undefined && value !== "") {
+      params.set(key, String(value));
+    }
+  });
+  const res = await fetch(`${API_BASE}/api/audit?${params}`, { cache: "no-store" });
+  if (!res.ok) {
+    const error = await res.json();
+    throw new Error(error.error || "Failed to fetch audit log");
+  }
+  return res.json();
+}
+
+// Branding of this deployment (tsuite api --title, --logo, --accent)
+export interface Branding {
+  title: string;
+  accent: string;   // Hex color, "" for the default theme
+  logo_url: string; // "" for the tsuite logo
+}
+
+export async function getBranding(): Promise<Branding> {
+  const res = await fetch(`${API_BASE}/api/branding`, { cache: "no-store" });
+  if (!res.ok) throw new Error("Failed to fetch branding");
+  const branding: Branding = await res.json();
+  // The server sends the logo's path relative to its URL
+  if (branding.logo_url) branding.logo_url = `${API_BASE}${branding.logo_url}`;
+  return branding;
+}
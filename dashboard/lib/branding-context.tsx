@@ -0,0 +1,48 @@
+"use client";
+
+import { createContext, useContext, useEffect, useState, ReactNode } from "react";
+import { usePathname } from "next/navigation";
+import { getBranding, Branding } from "./api";
+
+const defaultBranding: Branding = { title: "tsuite", accent: "", logo_url: "" };
+
+const BrandingContext = createContext<Branding>(defaultBranding);
+
+// Theme variables the accent color replaces
+const accentVariables = ["--primary", "--ring", "--sidebar-primary", "--sidebar-ring"];
+
+export function BrandingProvider({ children }: { children: ReactNode }) {
+  const [branding, setBranding] = useState<Branding>(defaultBranding);
+  const pathname = usePathname();
+
+  useEffect(() => {
+    // Older servers have no branding; keep the defaults
+    getBranding().then(setBranding).catch(() => {});
+  }, []);
+
+  useEffect(() => {
+    const root = document.documentElement;
+    for (const name of accentVariables) {
+      if (branding.accent) {
+        root.style.setProperty(name, branding.accent);
+      } else {
+        root.style.removeProperty(name);
+      }
+    }
+  }, [branding.accent]);
+
+  // Page navigations restore the title of the layout's metadata
+  useEffect(() => {
+    document.title = `${branding.title} Dashboard`;
+  }, [branding.title, pathname]);
+
+  return (
+    <BrandingContext.Provider value={branding}>
+      {children}
+    </BrandingContext.Provider>
+  );
+}
+
+export function useBranding() {
+  return useContext(BrandingContext);
+}
@@ -6,7 +6,10 @@
 
 import { useEffect, useState, useCallback, useRef } from "react";
 
-const API_BASE = process.env.NEXT_PUBLIC_API_URL || "http://localhost:9999";
+// The embedded dashboard is served by the API server, under its --base-path
+const API_BASE =
+  process.env.NEXT_PUBLIC_API_URL ||
+  (process.env.NODE_ENV === "development" ? "http://localhost:9999" : process.env.NEXT_PUBLIC_BASE_PATH || "");
 
 export interface SSEEvent {
   type: string;
@@ -21,10 +24,18 @@ export interface SSEEvent {
   failed?: number;
   skipped?: number;
   step_index?: number;
+  step_name?: string;
   phase?: string;
   handler?: string;
+  success?: boolean;  // step_completed
+  error?: string;     // step_completed
   steps_passed?: number;
   steps_failed?: number;
+  suite_id?: number;        // run_queued
+  queue_position?: number;  // run_queued
+  path?: string;     // disk_space_low
+  free_mb?: number;  // disk_space_low
+  min_mb?: number;   // disk_space_low
 }
 
 export interface UseSSEOptions {
@@ -88,7 +99,8 @@ export function useSSE(options: UseSSEOptions = {}): UseSSEResult {
         if (event.type === "run_started" && event.run_id) {
           setCurrentRunId(event.run_id);
         } else if (event.type === "run_completed" || event.type === "run_cancelled") {
-          setCurrentRunId(null);
+          // Queued runs can finish without ever becoming the current run
+          setCurrentRunId((current) => (current === event.run_id ? null : current));
         }
 
         // Add event to list
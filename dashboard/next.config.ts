@@ -11,6 +11,10 @@ const nextConfig: NextConfig = {
 
   // Trailing slashes for static hosting compatibility
   trailingSlash: true,
+
+  // make build-dashboard builds with a placeholder the API server replaces
+  // with its --base-path
+  basePath: process.env.NEXT_PUBLIC_BASE_PATH || "",
 };
 
 export default nextConfig;
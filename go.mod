@@ -6,10 +6,12 @@ require (
 	github.com/PaesslerAG/jsonpath v0.1.1
 	github.com/charmbracelet/glamour v0.10.0
 	github.com/docker/docker v25.0.6+incompatible
+	github.com/docker/go-connections v0.6.0
 	github.com/docker/go-sdk/context v0.1.0-alpha012
 	github.com/gin-contrib/cors v1.7.2
 	github.com/gin-gonic/gin v1.10.0
 	github.com/google/uuid v1.6.0
+	github.com/lib/pq v1.10.9
 	github.com/spf13/cobra v1.8.1
 	gopkg.in/yaml.v3 v3.0.1
 	modernc.org/sqlite v1.34.4
@@ -36,7 +38,6 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/distribution/reference v0.6.0 // indirect
 	github.com/dlclark/regexp2 v1.11.0 // indirect
-	github.com/docker/go-connections v0.6.0 // indirect
 	github.com/docker/go-sdk/config v0.1.0-alpha012 // indirect
 	github.com/docker/go-units v0.5.0 // indirect
 	github.com/dustin/go-humanize v1.0.1 // indirect
@@ -0,0 +1,421 @@
+// Package affected maps files changed in git to the tests of a suite they
+// affect, for tsuite run --changed-since.
+package affected
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// ChangedFiles returns the files changed since ref in the git repository
+// containing dir, as absolute paths: files changed on this branch since it
+// forked from ref, plus uncommitted and untracked files
+func ChangedFiles(dir, ref string) ([]string, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid git ref %q", ref)
+	}
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository: %w", dir, err)
+	}
+	root = strings.TrimSpace(root)
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	committed, err := git(root, "diff", "--name-only", ref+"...HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("diffing against %s: %w", ref, err)
+	}
+	uncommitted, err := git(root, "diff", "--name-only", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(root, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, name := range strings.Split(committed+"\n"+uncommitted+"\n"+untracked, "\n") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		path := filepath.Join(root, name)
+		if !slices.Contains(files, path) {
+			files = append(files, path)
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// Commit returns the commit checked out in the git repository containing
+// dir, and whether files under dir differ from it
+func Commit(dir string) (commit string, dirty bool, err error) {
+	out, err := git(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", false, fmt.Errorf("%s is not in a git repository: %w", dir, err)
+	}
+	status, err := git(dir, "status", "--porcelain", "--", ".")
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSpace(out), strings.TrimSpace(status) != "", nil
+}
+
+// git runs a git command in dir and returns its output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// The first line of git's message says what went wrong
+		if msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimPrefix(msg, "fatal: "))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// Tests returns the tests (uc/tc IDs of the suite at suitePath) affected by
+// the changed files, with the reason each was selected. A test is affected
+// by changes to:
+//   - anything in its directory, or in the files its artifacts link to
+//   - UC-level artifacts its test.yaml refers to
+//   - routines it calls, directly or through other routines
+//   - the suite's config.yaml or other shared files in global/ (every test)
+func Tests(suitePath string, tests, files []string) (map[string]string, error) {
+	// Changed files are resolved paths, so the suite path must be too
+	if resolved, err := filepath.EvalSymlinks(suitePath); err == nil {
+		suitePath = resolved
+	}
+	a := &analysis{suitePath: suitePath, affected: make(map[string]string)}
+	for _, file := range files {
+		a.mapFile(file)
+	}
+	if err := a.mapRoutines(tests); err != nil {
+		return nil, err
+	}
+	a.mapArtifactLinks(tests)
+
+	result := make(map[string]string)
+	for _, testID := range tests {
+		if reason, ok := a.affected[testID]; ok {
+			result[testID] = reason
+			continue
+		}
+		ucName, _, _ := strings.Cut(testID, "/")
+		if reason, ok := a.affectedUC[ucName]; ok {
+			result[testID] = reason
+		} else if a.all != "" {
+			result[testID] = a.all
+		}
+	}
+	return result, nil
+}
+
+// analysis collects what the changed files affect
+type analysis struct {
+	suitePath string
+	files     []string // Changed files, for matching against artifact links
+
+	affected   map[string]string // By test ID
+	affectedUC map[string]string // Every test of the use case
+	all        string            // Reason every test is affected, if so
+
+	ucArtifacts     map[string][]string // Changed UC artifact names, by use case
+	changedRoutines map[string]string   // Qualified routine name -> changed file
+}
+
+// mapFile records what a changed file affects directly
+func (a *analysis) mapFile(file string) {
+	a.files = append(a.files, file)
+	rel, err := filepath.Rel(a.suitePath, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		// Outside the suite: only reachable through artifact links
+		return
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+
+	switch {
+	case parts[0] == "config.yaml":
+		a.setAll("config.yaml changed")
+	case parts[0] == "global" && isRoutineFile(parts[1:]):
+		a.addRoutines(file, "global")
+	case parts[0] == "global":
+		a.setAll(rel + " changed")
+	case parts[0] == "suites" && len(parts) >= 3 && parts[2] == "artifacts":
+		// suites/<uc>/artifacts/<name>/...
+		if len(parts) == 3 {
+			a.setUC(parts[1], rel+" changed")
+		} else {
+			a.addUCArtifact(parts[1], parts[3])
+		}
+	case parts[0] == "suites" && len(parts) >= 3 && isRoutineFile(parts[2:]):
+		a.addRoutines(file, parts[1])
+	case parts[0] == "suites" && len(parts) >= 4:
+		// suites/<uc>/<tc>/...
+		a.set(parts[1]+"/"+parts[2], strings.Join(parts[3:], "/")+" changed")
+	}
+}
+
+// isRoutineFile reports whether path (relative to global/ or a use case) is
+// a routine library file
+func isRoutineFile(path []string) bool {
+	switch len(path) {
+	case 1:
+		return path[0] == "routines.yaml"
+	case 2:
+		ext := filepath.Ext(path[1])
+		return path[0] == "routines" && (ext == ".yaml" || ext == ".yml")
+	}
+	return false
+}
+
+func (a *analysis) set(testID, reason string) {
+	if _, ok := a.affected[testID]; !ok {
+		a.affected[testID] = reason
+	}
+}
+
+func (a *analysis) setUC(ucName, reason string) {
+	if a.affectedUC == nil {
+		a.affectedUC = make(map[string]string)
+	}
+	if _, ok := a.affectedUC[ucName]; !ok {
+		a.affectedUC[ucName] = reason
+	}
+}
+
+func (a *analysis) setAll(reason string) {
+	if a.all == "" {
+		a.all = reason
+	}
+}
+
+func (a *analysis) addUCArtifact(ucName, name string) {
+	if a.ucArtifacts == nil {
+		a.ucArtifacts = make(map[string][]string)
+	}
+	if !slices.Contains(a.ucArtifacts[ucName], name) {
+		a.ucArtifacts[ucName] = append(a.ucArtifacts[ucName], name)
+	}
+}
+
+// addRoutines records the routines defined in a changed routine file of
+// scope ("global" or a use case). A deleted file affects every test that
+// could have called its routines.
+func (a *analysis) addRoutines(file, scope string) {
+	rel, _ := filepath.Rel(a.suitePath, file)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if scope == "global" {
+			a.setAll(rel + " changed")
+		} else {
+			a.setUC(scope, rel+" changed")
+		}
+		return
+	}
+
+	var lib struct {
+		Routines map[string]yaml.Node `yaml:"routines"`
+	}
+	if err := yaml.Unmarshal(data, &lib); err != nil {
+		return
+	}
+	if a.changedRoutines == nil {
+		a.changedRoutines = make(map[string]string)
+	}
+	for name := range lib.Routines {
+		qualified := "global." + name
+		if scope != "global" {
+			qualified = scope + "/uc." + name
+		}
+		a.changedRoutines[qualified] = rel
+	}
+}
+
+// mapRoutines marks the tests that call a changed routine, directly or
+// through other routines
+func (a *analysis) mapRoutines(tests []string) error {
+	if len(a.changedRoutines) == 0 {
+		return nil
+	}
+	global, err := config.LoadGlobalRoutines(a.suitePath)
+	if err != nil {
+		return err
+	}
+
+	ucRoutines := make(map[string]map[string]config.RoutineDefinition)
+	for _, testID := range tests {
+		ucName, tcName, _ := strings.Cut(testID, "/")
+		ucPath := filepath.Join(a.suitePath, "suites", ucName)
+		if _, ok := ucRoutines[ucName]; !ok {
+			uc, err := config.LoadUseCaseRoutines(ucPath)
+			if err != nil {
+				return err
+			}
+			ucRoutines[ucName] = uc.Routines
+		}
+
+		testConfig, err := config.LoadTestConfig(filepath.Join(ucPath, tcName))
+		if err != nil {
+			continue
+		}
+		r := &routineScope{uc: ucName, ucRoutines: ucRoutines[ucName], global: global.Routines}
+		if name, file := r.findChanged(testSteps(testConfig), a.changedRoutines, nil); name != "" {
+			a.set(testID, fmt.Sprintf("routine %s changed in %s", name, file))
+		}
+	}
+	return nil
+}
+
+// testSteps returns every step of a test, eventual assertion steps included
+func testSteps(testConfig *config.TestConfig) []config.Step {
+	steps := slices.Concat(testConfig.PreRun, testConfig.Test, testConfig.PostRun)
+	for _, assertion := range testConfig.AssertEventually {
+		if assertion.Step != nil {
+			steps = append(steps, *assertion.Step)
+		}
+	}
+	return steps
+}
+
+// routineScope resolves routine references of a test the way the runner does
+type routineScope struct {
+	uc         string
+	ucRoutines map[string]config.RoutineDefinition
+	global     map[string]config.RoutineDefinition
+}
+
+// resolve returns the routine a reference calls and its qualified name
+// ("global.name" or "<uc>/uc.name")
+func (s *routineScope) resolve(ref string) (*config.RoutineDefinition, string) {
+	if name, ok := strings.CutPrefix(ref, "global."); ok {
+		if rd, ok := s.global[name]; ok {
+			return &rd, ref
+		}
+		return nil, ""
+	}
+	if name, ok := strings.CutPrefix(ref, "uc."); ok {
+		if rd, ok := s.ucRoutines[name]; ok {
+			return &rd, s.uc + "/" + ref
+		}
+		return nil, ""
+	}
+	if rd, ok := s.ucRoutines[ref]; ok {
+		return &rd, s.uc + "/uc." + ref
+	}
+	if rd, ok := s.global[ref]; ok {
+		return &rd, "global." + ref
+	}
+	return nil, ""
+}
+
+// findChanged returns the first changed routine the steps call, directly or
+// through other routines, and the file it changed in
+func (s *routineScope) findChanged(steps []config.Step, changed map[string]string, chain []string) (string, string) {
+	for _, step := range steps {
+		if step.Routine == "" {
+			continue
+		}
+		routine, name := s.resolve(step.Routine)
+		if routine == nil || slices.Contains(chain, name) {
+			continue
+		}
+		if file, ok := changed[name]; ok {
+			return strings.TrimPrefix(name, s.uc+"/"), file
+		}
+		if found, file := s.findChanged(routine.Steps, changed, append(slices.Clip(chain), name)); found != "" {
+			return found, file
+		}
+	}
+	return "", ""
+}
+
+// mapArtifactLinks marks tests whose artifacts are, or link to, changed
+// files: agent sources symlinked into artifacts, artifacts directories
+// linked to another test's, and UC artifacts the test refers to
+func (a *analysis) mapArtifactLinks(tests []string) {
+	for _, testID := range tests {
+		ucName, tcName, _ := strings.Cut(testID, "/")
+		ucPath := filepath.Join(a.suitePath, "suites", ucName)
+		tcPath := filepath.Join(ucPath, tcName)
+
+		if file := a.changedUnder(filepath.Join(tcPath, "artifacts")); file != "" {
+			a.set(testID, "artifact source "+a.display(file)+" changed")
+			continue
+		}
+
+		testYAML, err := os.ReadFile(filepath.Join(tcPath, "test.yaml"))
+		if err != nil {
+			continue
+		}
+		for _, name := range a.ucArtifacts[ucName] {
+			if usesUCArtifact(testYAML, name) {
+				a.set(testID, "UC artifact "+ucName+"/artifacts/"+name+" changed")
+			}
+		}
+		if usesUCArtifact(testYAML, "") {
+			if file := a.changedUnder(filepath.Join(ucPath, "artifacts")); file != "" {
+				a.set(testID, "UC artifact source "+a.display(file)+" changed")
+			}
+		}
+	}
+}
+
+// usesUCArtifact reports whether a test.yaml refers to the UC artifact name
+// (as /uc-artifacts/<name>), or to any UC artifact if name is empty. Tests
+// using the ${uc_artifacts} variables may refer to any of them.
+func usesUCArtifact(testYAML []byte, name string) bool {
+	return bytes.Contains(testYAML, []byte("uc-artifacts/"+name)) ||
+		bytes.Contains(testYAML, []byte("uc_artifacts"))
+}
+
+// changedUnder returns a changed file that the artifacts directory reaches through symlinks (the directory itself, or its
+// top-level entries), or ""
+func (a *analysis) changedUnder(artifactsDir string) string {
+	if len(a.files) == 0 {
+		return ""
+	}
+	var roots []string
+	if resolved, err := filepath.EvalSymlinks(artifactsDir); err == nil && resolved != artifactsDir {
+		roots = append(roots, resolved)
+	}
+	entries, _ := os.ReadDir(artifactsDir)
+	for _, entry := range entries {
+		path := filepath.Join(artifactsDir, entry.Name())
+		if resolved, err := filepath.EvalSymlinks(path); err == nil && resolved != path {
+			roots = append(roots, resolved)
+		}
+	}
+
+	for _, file := range a.files {
+		for _, root := range roots {
+			if file == root || strings.HasPrefix(file, root+string(filepath.Separator)) {
+				return file
+			}
+		}
+	}
+	return ""
+}
+
+// display returns a changed file relative to the suite, if it is inside it
+func (a *analysis) display(file string) string {
+	if rel, err := filepath.Rel(a.suitePath, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return file
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// ==================== Audit Log ====================
+
+// AuditUserHeader names the user making a change. Without it the client IP is
+// recorded.
+const AuditUserHeader = "X-Tsuite-User"
+
+// maxDiffCells bounds the LCS table of unifiedDiff (lines before × lines after)
+const maxDiffCells = 4_000_000
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// recordAudit stores a change to a suite file made by the current request.
+// path is relative to the suite folder; before is nil for created files and
+// after is nil for deleted ones. Failures are logged, not returned: the change
+// itself has already been made.
+func (s *Server) recordAudit(c *gin.Context, suite *models.Suite, path, action string, before, after []byte) {
+	if action == models.AuditActionUpdate && bytes.Equal(before, after) {
+		return
+	}
+
+	suiteID := suite.ID
+	entry := &models.AuditEntry{
+		SuiteID:  &suiteID,
+		Path:     path,
+		Action:   action,
+		Endpoint: c.Request.Method + " " + c.Request.URL.Path,
+		Actor:    requestActor(c),
+		Diff:     unifiedDiff(path, before, after),
+	}
+	if err := s.repo.CreateAuditEntry(c.Request.Context(), entry); err != nil {
+		slog.Warn("Failed to record audit entry", "suite_id", suite.ID, "path", path, "error", err)
+	}
+}
+
+// requestActor names who made the request: the X-Tsuite-User header, or the
+// client IP without one
+func requestActor(c *gin.Context) string {
+	if actor := strings.TrimSpace(c.GetHeader(AuditUserHeader)); actor != "" {
+		return actor
+	}
+	return c.ClientIP()
+}
+
+// testYAMLRelPath returns the suite-relative path of a test's test.yaml
+func testYAMLRelPath(testID string) string {
+	return "suites/" + testID + "/test.yaml"
+}
+
+// listAudit handles GET /api/audit
+// Filters: suite_id, path (a file, or a directory ending in "/"), since
+// (RFC 3339 or a duration like 24h), limit and offset.
+func (s *Server) listAudit(c *gin.Context) {
+	limit, offset, ok := parsePagination(c, 50, 500)
+	if !ok {
+		return
+	}
+
+	filter := db.AuditFilter{
+		Path:   c.Query("path"),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if sid := c.Query("suite_id"); sid != "" {
+		parsed, err := strconv.ParseInt(sid, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suite_id: " + sid})
+			return
+		}
+		filter.SuiteID = &parsed
+	}
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			d, derr := time.ParseDuration(since)
+			if derr != nil || d <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since (expected RFC 3339 time or duration): " + since})
+				return
+			}
+			t = time.Now().Add(-d)
+		}
+		filter.Since = &t
+	}
+
+	entries, total, err := s.repo.ListAuditEntries(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// diffLine is one line of a line diff: ' ' unchanged, '-' removed, '+' added.
+// aPos and bPos count the lines of each side before this one.
+type diffLine struct {
+	kind       byte
+	text       string
+	aPos, bPos int
+}
+
+// unifiedDiff returns a unified diff (3 lines of context) between two versions
+// of a file, or "" if they are equal
+func unifiedDiff(path string, before, after []byte) string {
+	a, b := splitLines(before), splitLines(after)
+	if len(a)*len(b) > maxDiffCells {
+		return fmt.Sprintf("(file too large to diff: %d → %d lines)\n", len(a), len(b))
+	}
+
+	lines := diffLines(a, b)
+
+	var out strings.Builder
+	for start := 0; start < len(lines); {
+		// Find the next change
+		for start < len(lines) && lines[start].kind == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		first := max(start-diffContext, 0)
+		end := start
+		for i := start; i < len(lines); i++ {
+			if lines[i].kind != ' ' {
+				end = i
+			} else if i-end > 2*diffContext {
+				break
+			}
+		}
+		last := min(end+diffContext, len(lines)-1)
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", path, path)
+		}
+		hunk := lines[first : last+1]
+		aCount, bCount := 0, 0
+		for _, l := range hunk {
+			if l.kind != '+' {
+				aCount++
+			}
+			if l.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(hunk[0].aPos, aCount), hunkRange(hunk[0].bPos, bCount))
+		for _, l := range hunk {
+			out.WriteByte(l.kind)
+			out.WriteString(l.text)
+			out.WriteByte('\n')
+		}
+
+		start = last + 1
+	}
+	return out.String()
+}
+
+// hunkRange formats the start,count of a hunk side; pos is the number of
+// lines before the hunk
+func hunkRange(pos, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+// splitLines splits file content into lines without their line endings
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// diffLines computes a minimal line diff from the longest common subsequence
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i], i, j})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j], i, j})
+			j++
+		}
+	}
+	return lines
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+)
+
+// Badge colors, as used by shields.io
+const (
+	badgeGreen  = "#4c1"
+	badgeYellow = "#dfb317"
+	badgeRed    = "#e05d44"
+	badgeGrey   = "#9f9f9f"
+)
+
+// getSuiteBadge handles GET /api/suites/:id/badge.svg
+// Shields-style SVG badge of the suite's latest finished run: its passed
+// tests and a color by pass rate. ?label= replaces the "tests" label.
+func (s *Server) getSuiteBadge(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+
+	runs, _, err := s.repo.ListRuns(c.Request.Context(), db.RunFilter{SuiteID: &suite.ID, Finished: true, Limit: 1})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	label := c.DefaultQuery("label", "tests")
+	message, color := "no runs", badgeGrey
+	if len(runs) > 0 {
+		run := runs[0]
+		message = fmt.Sprintf("%d/%d passed", run.Passed, run.Passed+run.Failed)
+		switch ran := run.Passed + run.Failed; {
+		case ran == 0:
+			message = "no tests"
+		case run.Failed == 0:
+			color = badgeGreen
+		case float64(run.Passed)/float64(ran) >= 0.9:
+			color = badgeYellow
+		default:
+			color = badgeRed
+		}
+	}
+
+	// Embedded in READMEs through image proxies that would otherwise cache it
+	c.Header("Cache-Control", "no-cache, max-age=0")
+	c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", []byte(renderBadge(label, message, color)))
+}
+
+// renderBadge draws a flat two-part badge. Text widths are estimated from
+// the average width of 11px Verdana.
+func renderBadge(label, message, color string) string {
+	textWidth := func(s string) int { return len([]rune(s))*7 + 10 }
+	lw, mw := textWidth(label), textWidth(message)
+	label, message = html.EscapeString(label), html.EscapeString(message)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+<title>%[4]s: %[5]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[7]d" y="14">%[4]s</text>
+<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[8]d" y="14">%[5]s</text>
+</g>
+</svg>
+`, lw+mw, lw, mw, label, message, color, lw/2, lw+mw/2)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultDashboardTitle is the dashboard's title without --title
+const DefaultDashboardTitle = "tsuite"
+
+// accentPattern matches the CSS hex colors --accent takes
+var accentPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Branding tells the dashboards of deployments apart
+type Branding struct {
+	Title    string // Shown in the sidebar and the browser tab
+	LogoFile string // SVG, PNG, JPEG, GIF or ICO file replacing the tsuite logo
+	Accent   string // Hex color of highlights, e.g. #a855f7
+}
+
+// brandingLogo is the logo of --logo, read at startup
+type brandingLogo struct {
+	data        []byte
+	contentType string
+}
+
+// loadBranding validates the branding and reads its logo
+func loadBranding(b Branding) (*brandingLogo, error) {
+	if b.Accent != "" && !accentPattern.MatchString(b.Accent) {
+		return nil, fmt.Errorf("invalid accent color %q: use a hex color like #a855f7", b.Accent)
+	}
+	if b.LogoFile == "" {
+		return nil, nil
+	}
+	contentType := getContentType(b.LogoFile)
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("logo %s is not an SVG, PNG, JPEG, GIF or ICO file", b.LogoFile)
+	}
+	data, err := os.ReadFile(b.LogoFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logo: %w", err)
+	}
+	return &brandingLogo{data: data, contentType: contentType}, nil
+}
+
+// getBranding handles GET /api/branding
+func (s *Server) getBranding(c *gin.Context) {
+	title := s.branding.Title
+	if title == "" {
+		title = DefaultDashboardTitle
+	}
+	// Relative to the server's URL, including --base-path
+	logoURL := ""
+	if s.logo != nil {
+		logoURL = "/api/branding/logo"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"title":    title,
+		"accent":   s.branding.Accent,
+		"logo_url": logoURL,
+	})
+}
+
+// getBrandingLogo handles GET /api/branding/logo
+func (s *Server) getBrandingLogo(c *gin.Context) {
+	if s.logo == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No logo configured"})
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Data(http.StatusOK, s.logo.contentType, s.logo.data)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCORSOrigins allows any origin, without cookies or HTTP auth
+var DefaultCORSOrigins = []string{"*"}
+
+// corsConfig returns the CORS configuration allowing origins: exact origins
+// (https://portal.example.com), origins with one * (https://*.example.com),
+// or * for any. Listed origins may send credentials; browsers never send
+// them with *. The server's own origin is always allowed.
+func corsConfig(origins []string) (cors.Config, error) {
+	if len(origins) == 0 {
+		origins = DefaultCORSOrigins
+	}
+	for _, origin := range origins {
+		if strings.Count(origin, "*") > 1 {
+			return cors.Config{}, fmt.Errorf("invalid CORS origin %q: only one * is allowed", origin)
+		}
+	}
+	config := cors.Config{
+		AllowOrigins:     origins,
+		AllowWildcard:    true,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Content-Type", "Content-Encoding", "If-Match", AuditUserHeader},
+		ExposeHeaders:    []string{"ETag"},
+		AllowCredentials: true,
+	}
+	if !slices.Contains(origins, "*") {
+		// The embedded dashboard calls the API from the server's own origin
+		config.AllowOriginWithContextFunc = func(c *gin.Context, origin string) bool {
+			u, err := url.Parse(origin)
+			return err == nil && u.Host == c.Request.Host
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return cors.Config{}, fmt.Errorf("invalid CORS origins: %w", err)
+	}
+	return config, nil
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"bytes"
 	"embed"
 	"io/fs"
+	"log/slog"
 	"net/http"
 	"path"
 	"strings"
@@ -20,15 +22,22 @@ var DashboardPrefix = "dashboard"
 // HasDashboard returns true if dashboard files are embedded
 var HasDashboard = false
 
+// dashboardBasePath is the base path the dashboard is built with (make
+// build-dashboard). It is replaced with --base-path, or removed, in the files
+// served, so one build works under any base path.
+const dashboardBasePath = "/__tsuite_base_path__"
+
 // SetupDashboardRoutes configures routes to serve the embedded dashboard
 func (s *Server) SetupDashboardRoutes() {
+	root := s.routes()
 	if !HasDashboard {
 		// No dashboard embedded, serve a simple message at root
-		s.router.GET("/", func(c *gin.Context) {
+		root.GET("/", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{
 				"message": "tsuite API server",
-				"api":     "/api",
-				"health":  "/health",
+				"api":     s.basePath + "/api",
+				"health":  s.basePath + "/healthz",
+				"ready":   s.basePath + "/readyz",
 				"note":    "Dashboard not embedded. Run with dashboard build to serve UI.",
 			})
 		})
@@ -39,7 +48,7 @@ func (s *Server) SetupDashboardRoutes() {
 	subFS, err := fs.Sub(DashboardFS, DashboardPrefix)
 	if err != nil {
 		// Fallback if sub-filesystem fails
-		s.router.GET("/", func(c *gin.Context) {
+		root.GET("/", func(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": "Failed to load dashboard: " + err.Error(),
 			})
@@ -47,14 +56,32 @@ func (s *Server) SetupDashboardRoutes() {
 		return
 	}
 
+	// A dashboard built without the placeholder loads its assets from / and
+	// can't be served under a base path
+	if s.basePath != "" && !builtWithBasePath(subFS) {
+		slog.Warn("Embedded dashboard was not built for --base-path; rebuild it with make build-dashboard", "base_path", s.basePath)
+		root.GET("/", func(c *gin.Context) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "The embedded dashboard does not support --base-path. Rebuild it with make build-dashboard.",
+				"api":   s.basePath + "/api",
+			})
+		})
+		return
+	}
+
 	// Serve index.html at root
-	s.router.GET("/", func(c *gin.Context) {
-		serveDashboardFile(c, subFS, "index.html")
+	root.GET("/", func(c *gin.Context) {
+		serveDashboardFile(c, subFS, "index.html", s.basePath)
 	})
 
 	// Serve static files and handle SPA routing
 	s.router.NoRoute(func(c *gin.Context) {
-		urlPath := c.Request.URL.Path
+		// Nothing is served outside the base path
+		urlPath, ok := strings.CutPrefix(c.Request.URL.Path, s.basePath)
+		if !ok || !strings.HasPrefix(urlPath, "/") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			return
+		}
 
 		// Skip API routes - they should 404 normally
 		if strings.HasPrefix(urlPath, "/api/") {
@@ -74,27 +101,34 @@ func (s *Server) SetupDashboardRoutes() {
 		}
 
 		// Try to serve the exact file
-		if serveIfExists(c, subFS, cleanPath) {
+		if serveIfExists(c, subFS, cleanPath, s.basePath) {
 			return
 		}
 
 		// Try with .html extension (Next.js static export pattern)
-		if serveIfExists(c, subFS, cleanPath+".html") {
+		if serveIfExists(c, subFS, cleanPath+".html", s.basePath) {
 			return
 		}
 
 		// Try as directory with index.html
-		if serveIfExists(c, subFS, path.Join(cleanPath, "index.html")) {
+		if serveIfExists(c, subFS, path.Join(cleanPath, "index.html"), s.basePath) {
 			return
 		}
 
 		// Fallback to index.html for SPA client-side routing
-		serveDashboardFile(c, subFS, "index.html")
+		serveDashboardFile(c, subFS, "index.html", s.basePath)
 	})
 }
 
+// builtWithBasePath reports whether the dashboard was built with the base
+// path placeholder
+func builtWithBasePath(fsys fs.FS) bool {
+	data, err := fs.ReadFile(fsys, "index.html")
+	return err == nil && bytes.Contains(data, []byte(dashboardBasePath))
+}
+
 // serveIfExists tries to serve a file if it exists, returns true if served
-func serveIfExists(c *gin.Context, fsys fs.FS, filePath string) bool {
+func serveIfExists(c *gin.Context, fsys fs.FS, filePath, basePath string) bool {
 	file, err := fsys.Open(filePath)
 	if err != nil {
 		return false
@@ -106,17 +140,22 @@ func serveIfExists(c *gin.Context, fsys fs.FS, filePath string) bool {
 		return false
 	}
 
-	serveDashboardFile(c, fsys, filePath)
+	serveDashboardFile(c, fsys, filePath, basePath)
 	return true
 }
 
-// serveDashboardFile serves a file from the embedded filesystem
-func serveDashboardFile(c *gin.Context, fsys fs.FS, filePath string) {
+// serveDashboardFile serves a file from the embedded filesystem, with the
+// base path of the build replaced by the server's
+func serveDashboardFile(c *gin.Context, fsys fs.FS, filePath, basePath string) {
 	data, err := fs.ReadFile(fsys, filePath)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
 		return
 	}
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".html", ".js", ".css", ".json", ".txt", ".map":
+		data = bytes.ReplaceAll(data, []byte(dashboardBasePath), []byte(basePath))
+	}
 
 	// Set content type based on extension
 	contentType := getContentType(filePath)
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/digest"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// sendDigest emails the digest of a finished run to the suite's email.to,
+// if the run's profile is one of email.profiles. Failures are logged; the
+// run is already recorded.
+func (s *Server) sendDigest(runID string) {
+	ctx := context.Background()
+	run, err := s.repo.GetRunByID(ctx, runID)
+	if err != nil || run == nil || !run.SuiteID.Valid {
+		return
+	}
+	suite, err := s.repo.GetSuiteByID(ctx, run.SuiteID.Int64)
+	if err != nil || suite == nil {
+		return
+	}
+	suiteConfig, err := config.LoadSuiteConfig(suite.FolderPath)
+	if err != nil || len(suiteConfig.Email.To) == 0 {
+		return
+	}
+	settings := suiteConfig.Email
+	if len(settings.Profiles) > 0 && !slices.Contains(settings.Profiles, run.Profile.String) {
+		return
+	}
+
+	d, err := s.buildDigest(ctx, suite, run, settings)
+	if err != nil {
+		slog.Warn("Failed to build email digest", "run_id", run.RunID, "error", err)
+		return
+	}
+	subject, body, err := digest.Render(d, settings.Sections)
+	if err != nil {
+		slog.Warn("Failed to render email digest", "suite", suite.SuiteName, "error", err)
+		return
+	}
+	if err := digest.Send(settings, subject, body); err != nil {
+		slog.Warn("Failed to send email digest", "suite", suite.SuiteName, "run_id", run.RunID, "error", err)
+		return
+	}
+	slog.Info("Sent email digest", "suite", suite.SuiteName, "run_id", run.RunID, "recipients", len(settings.To))
+}
+
+// buildDigest collects the run's failures, those new since the suite's
+// previous run, the suite's flaky tests and the run's slowest tests
+func (s *Server) buildDigest(ctx context.Context, suite *models.Suite, run *models.Run, settings config.EmailSettings) (*digest.Digest, error) {
+	d := &digest.Digest{SuiteName: suite.SuiteName, Run: run}
+	if settings.DashboardURL != "" {
+		d.RunURL = strings.TrimSuffix(settings.DashboardURL, "/") + "/runs?id=" + run.RunID
+	}
+
+	results, err := s.repo.GetTestResultsByRunID(ctx, run.RunID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tr := range results {
+		if tr.Status == models.TestStatusFailed || tr.Status == models.TestStatusCrashed {
+			d.Failures = append(d.Failures, tr)
+		}
+	}
+
+	prev, err := s.repo.GetPreviousRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil {
+		d.PreviousRunID = prev.RunID
+		prevResults, err := s.repo.GetTestResultsByRunID(ctx, prev.RunID)
+		if err != nil {
+			return nil, err
+		}
+		failedBefore := make(map[string]bool)
+		for _, tr := range prevResults {
+			if tr.Status == models.TestStatusFailed || tr.Status == models.TestStatusCrashed {
+				failedBefore[tr.TestID] = true
+			}
+		}
+		for _, tr := range d.Failures {
+			if !failedBefore[tr.TestID] {
+				d.NewFailures = append(d.NewFailures, tr)
+			}
+		}
+	} else {
+		d.NewFailures = d.Failures
+	}
+
+	d.FlakyRuns = settings.FlakyRuns
+	if d.FlakyRuns <= 0 {
+		d.FlakyRuns = digest.DefaultFlakyRuns
+	}
+	if d.Flaky, err = s.repo.GetFlakyTests(ctx, suite.ID, d.FlakyRuns); err != nil {
+		return nil, err
+	}
+
+	slowest := settings.Slowest
+	if slowest <= 0 {
+		slowest = digest.DefaultSlowest
+	}
+	for _, tr := range results {
+		if tr.DurationMS.Valid {
+			d.Slowest = append(d.Slowest, tr)
+		}
+	}
+	sort.SliceStable(d.Slowest, func(i, j int) bool {
+		return d.Slowest[i].DurationMS.Int64 > d.Slowest[j].DurationMS.Int64
+	})
+	if len(d.Slowest) > slowest {
+		d.Slowest = d.Slowest[:slowest]
+	}
+	return d, nil
+}
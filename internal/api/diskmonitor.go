@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/diskspace"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// diskCheckInterval is how often the disk monitor checks free space
+const diskCheckInterval = 30 * time.Second
+
+// runDiskMonitor periodically checks free space where runs write and warns
+// subscribers of running runs when a filesystem drops below the minimum
+func (s *Server) runDiskMonitor(minMB int) {
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+
+	low := make(map[string]bool) // Paths already reported, until they recover
+	for range ticker.C {
+		s.checkDiskSpace(uint64(minMB), low)
+	}
+}
+
+// checkDiskSpace runs one disk monitor pass. Each filesystem is reported once
+// when it drops below minMB and again only after it recovered.
+func (s *Server) checkDiskSpace(minMB uint64, low map[string]bool) {
+	current := make(map[string]bool)
+	for _, l := range diskspace.Check(diskspace.Paths(), minMB) {
+		current[l.Path] = true
+		if low[l.Path] {
+			continue
+		}
+		slog.Warn("Disk space low", "path", l.Path, "free_mb", l.FreeMB, "min_mb", l.MinMB)
+
+		runs, _, err := s.repo.ListRuns(context.Background(), db.RunFilter{Status: string(models.RunStatusRunning)})
+		if err != nil {
+			slog.Error("Disk monitor failed to list running runs", "error", err)
+		}
+		for _, run := range runs {
+			s.sseHub.EmitDiskSpaceLow(run.RunID, l)
+		}
+	}
+	for path := range low {
+		if !current[path] {
+			slog.Info("Disk space recovered", "path", path)
+		}
+	}
+	clear(low)
+	for path := range current {
+		low[path] = true
+	}
+}
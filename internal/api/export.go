@@ -0,0 +1,179 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// exportFormat checks ?format= of an export; csv is the only format and
+// the default. Spreadsheet applications, Excel included, open it directly.
+func exportFormat(c *gin.Context) bool {
+	if format := c.DefaultQuery("format", "csv"); format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format: " + format + " (supported: csv)"})
+		return false
+	}
+	return true
+}
+
+// writeCSV sends rows as a CSV attachment
+func writeCSV(c *gin.Context, filename string, rows [][]string) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.WriteAll(rows); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", []byte(b.String()))
+}
+
+// exportRun handles GET /api/runs/:run_id/export
+// One CSV row per test of the run with its status, duration, error and
+// step counts.
+func (s *Server) exportRun(c *gin.Context) {
+	if !exportFormat(c) {
+		return
+	}
+	run, ok := s.getRunByIDParam(c)
+	if !ok {
+		return
+	}
+	results, err := s.repo.GetTestResultsByRunID(c.Request.Context(), run.RunID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows := [][]string{{
+		"run_id", "suite", "test_id", "name", "status", "started_at", "duration_ms",
+		"steps_passed", "steps_failed", "error_step", "error_message", "skip_reason", "tags", "owners",
+	}}
+	for _, tr := range results {
+		var started string
+		if tr.StartedAt != nil {
+			started = tr.StartedAt.UTC().Format(time.RFC3339)
+		}
+		rows = append(rows, []string{
+			run.RunID, run.SuiteName.String, tr.TestID, tr.Name.String, string(tr.Status), started,
+			nullInt64Cell(tr.DurationMS), strconv.Itoa(tr.StepsPassed), strconv.Itoa(tr.StepsFailed),
+			nullInt64Cell(tr.ErrorStep), tr.ErrorMessage.String, tr.SkipReason.String,
+			jsonListCell(tr.Tags), jsonListCell(tr.Owners),
+		})
+	}
+	writeCSV(c, fmt.Sprintf("tsuite-run-%s.csv", shortRunID(run.RunID)), rows)
+}
+
+// exportSuiteDurations handles GET /api/suites/:id/export/durations
+// Durations of each test across the suite's last ?runs= finished runs
+// (default 20, max 100): one CSV row per test, one column per run (oldest
+// first), then the test's average and maximum.
+func (s *Server) exportSuiteDurations(c *gin.Context) {
+	if !exportFormat(c) {
+		return
+	}
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+	runLimit := 20
+	if v := c.Query("runs"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid runs: " + v})
+			return
+		}
+		runLimit = min(n, 100)
+	}
+
+	runs, _, err := s.repo.ListRuns(c.Request.Context(), db.RunFilter{SuiteID: &suite.ID, Finished: true, Limit: runLimit})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// Newest first from the repository; spreadsheets read left to right
+	for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+		runs[i], runs[j] = runs[j], runs[i]
+	}
+
+	durations := make(map[string][]string) // Test ID -> duration per run column
+	for i, run := range runs {
+		results, err := s.repo.GetTestResultsByRunID(c.Request.Context(), run.RunID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for _, tr := range results {
+			if tr.Status != models.TestStatusPassed && tr.Status != models.TestStatusFailed {
+				continue
+			}
+			if durations[tr.TestID] == nil {
+				durations[tr.TestID] = make([]string, len(runs))
+			}
+			durations[tr.TestID][i] = nullInt64Cell(tr.DurationMS)
+		}
+	}
+	testIDs := make([]string, 0, len(durations))
+	for testID := range durations {
+		testIDs = append(testIDs, testID)
+	}
+	sort.Strings(testIDs)
+
+	header := []string{"test_id"}
+	for _, run := range runs {
+		header = append(header, run.StartedAt.UTC().Format("2006-01-02 15:04")+" "+shortRunID(run.RunID))
+	}
+	rows := [][]string{append(header, "avg_ms", "max_ms")}
+	for _, testID := range testIDs {
+		var total, maxMS, samples int64
+		for _, cell := range durations[testID] {
+			if ms, err := strconv.ParseInt(cell, 10, 64); err == nil {
+				total += ms
+				maxMS = max(maxMS, ms)
+				samples++
+			}
+		}
+		avg := ""
+		if samples > 0 {
+			avg = strconv.FormatInt(total/samples, 10)
+		}
+		row := append([]string{testID}, durations[testID]...)
+		rows = append(rows, append(row, avg, strconv.FormatInt(maxMS, 10)))
+	}
+	writeCSV(c, fmt.Sprintf("tsuite-%s-durations.csv", suite.SuiteName), rows)
+}
+
+// shortRunID is the 12-character run ID prefix tsuite prints
+func shortRunID(runID string) string {
+	if len(runID) > 12 {
+		return runID[:12]
+	}
+	return runID
+}
+
+func nullInt64Cell(v sql.NullInt64) string {
+	if !v.Valid {
+		return ""
+	}
+	return strconv.FormatInt(v.Int64, 10)
+}
+
+// jsonListCell joins a JSON array column with ", "
+func jsonListCell(v sql.NullString) string {
+	var list []string
+	if v.Valid && v.String != "" {
+		_ = json.Unmarshal([]byte(v.String), &list)
+	}
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+)
+
+// atomFeed is an Atom 1.0 feed (RFC 4287)
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Link      atomLink     `xml:"link"`
+	Category  atomCategory `xml:"category"`
+	Summary   string       `xml:"summary"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// getFeed handles GET /api/feed.atom
+// Atom feed with an entry per completed or failed run, newest first.
+// ?suite_id= limits it to one suite; ?limit= (default 50, max 200).
+func (s *Server) getFeed(c *gin.Context) {
+	limit, _, ok := parsePagination(c, 50, 200)
+	if !ok {
+		return
+	}
+	filter := db.RunFilter{Finished: true, Limit: limit}
+	title := "tsuite runs"
+	if sid := c.Query("suite_id"); sid != "" {
+		suiteID, err := strconv.ParseInt(sid, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suite_id"})
+			return
+		}
+		suite, err := s.repo.GetSuiteByID(c.Request.Context(), suiteID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if suite == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Suite not found"})
+			return
+		}
+		filter.SuiteID = &suiteID
+		title = "tsuite runs: " + suite.SuiteName
+	}
+
+	runs, _, err := s.repo.ListRuns(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	base := requestBaseURL(c)
+	feed := atomFeed{
+		ID:    base + c.Request.URL.RequestURI(),
+		Title: title,
+		Links: []atomLink{
+			{Href: base + c.Request.URL.RequestURI(), Rel: "self", Type: "application/atom+xml"},
+			{Href: base + s.basePath + "/runs", Rel: "alternate", Type: "text/html"},
+		},
+		Author:  atomAuthor{Name: "tsuite"},
+		Entries: []atomEntry{},
+	}
+	updated := time.Unix(0, 0).UTC()
+	for _, run := range runs {
+		finished := run.StartedAt
+		if run.FinishedAt != nil {
+			finished = *run.FinishedAt
+		}
+		if finished.After(updated) {
+			updated = finished
+		}
+		suiteName := run.SuiteName.String
+		if suiteName == "" {
+			suiteName = "unregistered suite"
+		}
+		summary := fmt.Sprintf("%d tests: %d passed, %d failed, %d skipped", run.TotalTests, run.Passed, run.Failed, run.Skipped)
+		if run.DurationMS.Valid {
+			summary += fmt.Sprintf(" in %s", (time.Duration(run.DurationMS.Int64) * time.Millisecond).Round(time.Second))
+		}
+		if run.Profile.Valid {
+			summary += fmt.Sprintf(" (profile %s)", run.Profile.String)
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:        "urn:tsuite:run:" + run.RunID,
+			Title:     fmt.Sprintf("%s: %s (%d/%d passed)", suiteName, run.Status, run.Passed, run.TotalTests),
+			Updated:   finished.UTC().Format(time.RFC3339),
+			Published: run.StartedAt.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: base + s.basePath + "/runs?id=" + run.RunID, Rel: "alternate", Type: "text/html"},
+			Category:  atomCategory{Term: string(run.Status)},
+			Summary:   summary,
+		})
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), out...))
+}
+
+// requestBaseURL is the scheme and host the client reached the server at,
+// honoring X-Forwarded-Proto from a TLS-terminating proxy
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
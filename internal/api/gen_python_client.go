@@ -0,0 +1,262 @@
+//go:build ignore
+
+// gen_python_client generates clients/python/tsuite_client/_api.py from
+// openapi.yaml: one method per operationId, in the order of the spec. Run it
+// with go generate ./internal/api (or make python-client).
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const output = "../../clients/python/tsuite_client/_api.py"
+
+// pythonKeywords can't be parameter names; they get a trailing underscore
+var pythonKeywords = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true, "break": true,
+	"class": true, "continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true,
+	"or": true, "pass": true, "raise": true, "return": true, "try": true, "while": true,
+	"with": true, "yield": true,
+}
+
+var pythonTypes = map[string]string{
+	"integer": "int",
+	"number":  "float",
+	"boolean": "bool",
+	"string":  "str",
+}
+
+type param struct {
+	name string // As in the spec
+	arg  string // Python argument name
+	typ  string
+	in   string
+}
+
+type operation struct {
+	id       string
+	method   string
+	path     string
+	summary  string
+	params   []param
+	body     string // "", "required" or "optional"
+	bodyType string // Schema name of a $ref body
+	json     bool   // 2xx responses are JSON
+}
+
+func main() {
+	spec, err := os.ReadFile("openapi.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(spec, &root); err != nil {
+		log.Fatal(err)
+	}
+	doc := root.Content[0]
+
+	ops, err := operations(doc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(output, render(ops), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// get returns the value of key in a mapping node, or nil
+func get(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// resolve follows a local $ref ("#/components/...")
+func resolve(doc, n *yaml.Node) (*yaml.Node, error) {
+	ref := get(n, "$ref")
+	if ref == nil {
+		return n, nil
+	}
+	target := doc
+	for _, key := range strings.Split(strings.TrimPrefix(ref.Value, "#/"), "/") {
+		if target = get(target, key); target == nil {
+			return nil, fmt.Errorf("unresolved $ref %s", ref.Value)
+		}
+	}
+	return target, nil
+}
+
+func operations(doc *yaml.Node) ([]operation, error) {
+	var ops []operation
+	paths := get(doc, "paths")
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		path, item := paths.Content[i].Value, paths.Content[i+1]
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			method, node := item.Content[j].Value, item.Content[j+1]
+			if method == "parameters" {
+				continue
+			}
+			op := operation{
+				method: strings.ToUpper(method),
+				path:   path,
+				json:   true,
+			}
+			if id := get(node, "operationId"); id != nil {
+				op.id = id.Value
+			} else {
+				return nil, fmt.Errorf("%s %s has no operationId", op.method, path)
+			}
+			if summary := get(node, "summary"); summary != nil {
+				op.summary = summary.Value
+			}
+
+			// Path-level parameters come first
+			for _, list := range []*yaml.Node{get(item, "parameters"), get(node, "parameters")} {
+				if list == nil {
+					continue
+				}
+				for _, p := range list.Content {
+					p, err := resolve(doc, p)
+					if err != nil {
+						return nil, err
+					}
+					op.params = append(op.params, newParam(p))
+				}
+			}
+
+			if body := get(node, "requestBody"); body != nil {
+				op.body = "optional"
+				if required := get(body, "required"); required != nil && required.Value == "true" {
+					op.body = "required"
+				}
+				if ref := get(get(get(get(body, "content"), "application/json"), "schema"), "$ref"); ref != nil {
+					op.bodyType = ref.Value[strings.LastIndex(ref.Value, "/")+1:]
+				}
+			}
+
+			responses := get(node, "responses")
+			for k := 0; k+1 < len(responses.Content); k += 2 {
+				if code := responses.Content[k].Value; strings.HasPrefix(code, "2") {
+					response, err := resolve(doc, responses.Content[k+1])
+					if err != nil {
+						return nil, err
+					}
+					if get(get(response, "content"), "application/json") == nil {
+						op.json = false
+					}
+				}
+			}
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+func newParam(n *yaml.Node) param {
+	p := param{name: get(n, "name").Value, in: get(n, "in").Value, typ: "str"}
+	if t := get(get(n, "schema"), "type"); t != nil && pythonTypes[t.Value] != "" {
+		p.typ = pythonTypes[t.Value]
+	}
+	p.arg = p.name
+	if pythonKeywords[p.arg] {
+		p.arg += "_"
+	}
+	return p
+}
+
+var wordBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// snakeCase converts an operationId (getRunTests) to a method name (get_run_tests)
+func snakeCase(s string) string {
+	return strings.ToLower(wordBoundary.ReplaceAllString(s, "${1}_${2}"))
+}
+
+func render(ops []operation) []byte {
+	var b bytes.Buffer
+	b.WriteString(`# Code generated from internal/api/openapi.yaml by go generate ./internal/api; DO NOT EDIT.
+"""Operations of the tsuite REST API, one method per operationId."""
+
+from typing import Any, Dict, Optional
+from urllib.parse import quote
+
+
+class Operations:
+    """API operations. Subclasses implement _request (see TsuiteClient)."""
+
+    def _request(
+        self,
+        method: str,
+        path: str,
+        body: Optional[Dict[str, Any]] = None,
+        query: Optional[Dict[str, Any]] = None,
+        raw: bool = False,
+    ) -> Any:
+        raise NotImplementedError
+`)
+
+	for _, op := range ops {
+		var args, query []string
+		path := op.path
+		for _, p := range op.params {
+			if p.in == "path" {
+				args = append(args, fmt.Sprintf("%s: %s", p.arg, p.typ))
+				path = strings.ReplaceAll(path, "{"+p.name+"}", `" + quote(str(`+p.arg+`)) + "`)
+			}
+		}
+		switch op.body {
+		case "required":
+			args = append(args, "body: Dict[str, Any]")
+		case "optional":
+			args = append(args, "body: Optional[Dict[str, Any]] = None")
+		}
+		var keywords []string
+		for _, p := range op.params {
+			if p.in == "query" {
+				keywords = append(keywords, fmt.Sprintf("%s: Optional[%s] = None", p.arg, p.typ))
+				query = append(query, fmt.Sprintf("%q: %s", p.name, p.arg))
+			}
+		}
+		if len(keywords) > 0 {
+			args = append(append(args, "*"), keywords...)
+		}
+
+		returns := "Any"
+		if !op.json {
+			returns = "bytes"
+		}
+		fmt.Fprintf(&b, "\n    def %s(%s) -> %s:\n", snakeCase(op.id), strings.Join(append([]string{"self"}, args...), ", "), returns)
+		doc := op.summary + "."
+		if op.bodyType != "" {
+			doc += fmt.Sprintf(" body follows the %s schema.", op.bodyType)
+		}
+		fmt.Fprintf(&b, "        \"\"\"%s\"\"\"\n", doc)
+
+		call := []string{fmt.Sprintf("%q", op.method), strings.TrimSuffix(`"`+path+`"`, ` + ""`)}
+		if op.body != "" {
+			call = append(call, "body=body")
+		}
+		if len(query) > 0 {
+			call = append(call, "query={"+strings.Join(query, ", ")+"}")
+		}
+		if !op.json {
+			call = append(call, "raw=True")
+		}
+		fmt.Fprintf(&b, "        return self._request(%s)\n", strings.Join(call, ", "))
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// ==================== Run Comments ====================
+
+// maxCommentLength bounds the text of a comment, in characters
+const maxCommentLength = 10000
+
+// CreateCommentRequest is the body of POST /api/runs/:run_id/comments
+type CreateCommentRequest struct {
+	Text   string `json:"text" binding:"required"`
+	TestID string `json:"test_id"` // Comment on this test of the run instead of the run
+	Author string `json:"author"`  // Default: X-Tsuite-User header or client IP
+}
+
+// listRunComments handles GET /api/runs/:run_id/comments
+// Returns the comments on the run and its tests; ?test_id= selects those on
+// one test, and an empty test_id those on the run itself.
+func (s *Server) listRunComments(c *gin.Context) {
+	run, ok := s.getRunByIDParam(c)
+	if !ok {
+		return
+	}
+
+	var testID *string
+	if v, ok := c.GetQuery("test_id"); ok {
+		testID = &v
+	}
+
+	comments, err := s.repo.ListComments(c.Request.Context(), run.RunID, testID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"run_id":   run.RunID,
+		"comments": comments,
+		"count":    len(comments),
+	})
+}
+
+// createRunComment handles POST /api/runs/:run_id/comments
+func (s *Server) createRunComment(c *gin.Context) {
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment text is required"})
+		return
+	}
+	if utf8.RuneCountInString(req.Text) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment text is longer than " + strconv.Itoa(maxCommentLength) + " characters"})
+		return
+	}
+
+	run, ok := s.getRunByIDParam(c)
+	if !ok {
+		return
+	}
+	if req.TestID != "" {
+		test, err := s.repo.GetTestResultByTestIDAndRunID(c.Request.Context(), req.TestID, run.RunID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if test == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Test not in this run: " + req.TestID})
+			return
+		}
+	}
+
+	author := strings.TrimSpace(req.Author)
+	if author == "" {
+		author = requestActor(c)
+	}
+
+	comment := &models.Comment{
+		RunID:  run.RunID,
+		TestID: req.TestID,
+		Author: author,
+		Text:   req.Text,
+	}
+	if err := s.repo.CreateComment(c.Request.Context(), comment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// deleteRunComment handles DELETE /api/runs/:run_id/comments/:comment_id
+func (s *Server) deleteRunComment(c *gin.Context) {
+	runID := c.Param("run_id")
+	id, err := strconv.ParseInt(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	deleted, err := s.repo.DeleteComment(c.Request.Context(), runID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "id": id})
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gopkg.in/yaml.v3"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
 )
 
 // ==================== Suite Config ====================
@@ -41,11 +43,14 @@ func (s *Server) getSuiteConfig(c *gin.Context) {
 		return
 	}
 
+	etag := contentETag(rawYAML)
+	c.Header("ETag", etag)
 	c.JSON(http.StatusOK, gin.H{
 		"suite_id":  suite.ID,
 		"path":      configPath,
 		"raw_yaml":  string(rawYAML),
 		"structure": structure,
+		"etag":      etag,
 	})
 }
 
@@ -78,6 +83,14 @@ func (s *Server) updateSuiteConfig(c *gin.Context) {
 		return
 	}
 
+	s.yamlMu.Lock()
+	defer s.yamlMu.Unlock()
+
+	oldYAML, ok := checkFileIfMatch(c, configPath, "config")
+	if !ok {
+		return
+	}
+
 	// Use YAMLDocument to preserve comments and key ordering
 	doc, err := LoadYAMLFile(configPath)
 	if err != nil {
@@ -102,10 +115,14 @@ func (s *Server) updateSuiteConfig(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write config: " + err.Error()})
 		return
 	}
+	s.recordAudit(c, suite, "config.yaml", models.AuditActionUpdate, oldYAML, newYAML)
 
+	etag := contentETag(newYAML)
+	c.Header("ETag", etag)
 	c.JSON(http.StatusOK, gin.H{
 		"success":  true,
 		"raw_yaml": string(newYAML),
+		"etag":     etag,
 	})
 }
 
@@ -236,12 +253,15 @@ func (s *Server) getTestYAML(c *gin.Context, suiteID int64, testID string) {
 		return
 	}
 
+	etag := contentETag(rawYAML)
+	c.Header("ETag", etag)
 	c.JSON(http.StatusOK, gin.H{
 		"suite_id":  suiteID,
 		"test_id":   testID,
 		"path":      testPath,
 		"raw_yaml":  string(rawYAML),
 		"structure": structure,
+		"etag":      etag,
 	})
 }
 
@@ -270,6 +290,14 @@ func (s *Server) updateTestYAML(c *gin.Context, suiteID int64, testID string) {
 		return
 	}
 
+	s.yamlMu.Lock()
+	defer s.yamlMu.Unlock()
+
+	oldYAML, ok := checkFileIfMatch(c, testPath, "test")
+	if !ok {
+		return
+	}
+
 	var newYAML []byte
 
 	if req.RawYAML != "" {
@@ -308,11 +336,15 @@ func (s *Server) updateTestYAML(c *gin.Context, suiteID int64, testID string) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write test: " + err.Error()})
 		return
 	}
+	s.recordAudit(c, suite, testYAMLRelPath(testID), models.AuditActionUpdate, oldYAML, newYAML)
 
+	etag := contentETag(newYAML)
+	c.Header("ETag", etag)
 	c.JSON(http.StatusOK, gin.H{
 		"success":  true,
 		"test_id":  testID,
 		"raw_yaml": string(newYAML),
+		"etag":     etag,
 	})
 }
 
@@ -361,12 +393,15 @@ func (s *Server) getTestSteps(c *gin.Context, suiteID int64, testID string) {
 		assertions = []any{}
 	}
 
+	etag := contentETag(rawYAML)
+	c.Header("ETag", etag)
 	c.JSON(http.StatusOK, gin.H{
 		"test_id":    testID,
 		"pre_run":    preRun,
 		"test":       test,
 		"post_run":   postRun,
 		"assertions": assertions,
+		"etag":       etag,
 	})
 }
 
@@ -394,6 +429,14 @@ func (s *Server) updateTestStep(c *gin.Context, suiteID int64, testID, phase str
 		return
 	}
 
+	s.yamlMu.Lock()
+	defer s.yamlMu.Unlock()
+
+	oldYAML, ok := checkFileIfMatch(c, testPath, "test")
+	if !ok {
+		return
+	}
+
 	// Use YAMLDocument to preserve comments and key ordering
 	doc, err := LoadYAMLFile(testPath)
 	if err != nil {
@@ -418,8 +461,11 @@ func (s *Server) updateTestStep(c *gin.Context, suiteID int64, testID, phase str
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write test: " + err.Error()})
 		return
 	}
+	s.recordAudit(c, suite, testYAMLRelPath(testID), models.AuditActionUpdate, oldYAML, newYAML)
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	etag := contentETag(newYAML)
+	c.Header("ETag", etag)
+	c.JSON(http.StatusOK, gin.H{"success": true, "etag": etag})
 }
 
 // addTestStep adds a new step
@@ -454,6 +500,14 @@ func (s *Server) addTestStep(c *gin.Context, suiteID int64, testID, phase string
 		return
 	}
 
+	s.yamlMu.Lock()
+	defer s.yamlMu.Unlock()
+
+	oldYAML, ok := checkFileIfMatch(c, testPath, "test")
+	if !ok {
+		return
+	}
+
 	// Use YAMLDocument to preserve comments and key ordering
 	doc, err := LoadYAMLFile(testPath)
 	if err != nil {
@@ -478,8 +532,11 @@ func (s *Server) addTestStep(c *gin.Context, suiteID int64, testID, phase string
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write test: " + err.Error()})
 		return
 	}
+	s.recordAudit(c, suite, testYAMLRelPath(testID), models.AuditActionUpdate, oldYAML, newYAML)
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	etag := contentETag(newYAML)
+	c.Header("ETag", etag)
+	c.JSON(http.StatusOK, gin.H{"success": true, "etag": etag})
 }
 
 // deleteTestStep deletes a step
@@ -500,6 +557,14 @@ func (s *Server) deleteTestStep(c *gin.Context, suiteID int64, testID, phase str
 		return
 	}
 
+	s.yamlMu.Lock()
+	defer s.yamlMu.Unlock()
+
+	oldYAML, ok := checkFileIfMatch(c, testPath, "test")
+	if !ok {
+		return
+	}
+
 	// Use YAMLDocument to preserve comments and key ordering
 	doc, err := LoadYAMLFile(testPath)
 	if err != nil {
@@ -524,6 +589,9 @@ func (s *Server) deleteTestStep(c *gin.Context, suiteID int64, testID, phase str
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write test: " + err.Error()})
 		return
 	}
+	s.recordAudit(c, suite, testYAMLRelPath(testID), models.AuditActionUpdate, oldYAML, newYAML)
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	etag := contentETag(newYAML)
+	c.Header("ETag", etag)
+	c.JSON(http.StatusOK, gin.H{"success": true, "etag": etag})
 }
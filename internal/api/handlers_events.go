@@ -2,7 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,12 +12,20 @@ import (
 
 // ==================== SSE Events ====================
 
-// streamEvents handles GET /api/events
-func (s *Server) streamEvents(c *gin.Context) {
+// openStream starts an event stream response. Proxies are asked not to
+// buffer or transform it, and clients told how long to wait before
+// reconnecting when it drops.
+func (s *Server) openStream(c *gin.Context) {
 	c.Header("Content-Type", "text/event-stream")
-	c.Header("Cache-Control", "no-cache")
+	c.Header("Cache-Control", "no-cache, no-transform")
 	c.Header("Connection", "keep-alive")
 	c.Header("X-Accel-Buffering", "no")
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", s.sseHub.RetryInterval().Milliseconds())
+}
+
+// streamEvents handles GET /api/events
+func (s *Server) streamEvents(c *gin.Context) {
+	s.openStream(c)
 
 	// Subscribe to global events
 	eventCh := s.sseHub.SubscribeGlobal()
@@ -25,7 +35,7 @@ func (s *Server) streamEvents(c *gin.Context) {
 	currentRunID := s.sseHub.GetCurrentRun()
 	if currentRunID == "" {
 		// Try to find from DB
-		runningRun, _ := s.repo.GetRunningRun()
+		runningRun, _ := s.repo.GetRunningRun(c.Request.Context())
 		if runningRun != nil {
 			currentRunID = runningRun.RunID
 		}
@@ -50,7 +60,7 @@ func (s *Server) streamEvents(c *gin.Context) {
 	}
 
 	// Keep connection alive with heartbeat and stream events
-	ticker := time.NewTicker(15 * time.Second)
+	ticker := time.NewTicker(s.sseHub.HeartbeatInterval())
 	defer ticker.Stop()
 
 	clientGone := c.Request.Context().Done()
@@ -77,19 +87,16 @@ func (s *Server) streamEvents(c *gin.Context) {
 func (s *Server) streamRunEvents(c *gin.Context) {
 	runID := c.Param("run_id")
 
-	c.Header("Content-Type", "text/event-stream")
-	c.Header("Cache-Control", "no-cache")
-	c.Header("Connection", "keep-alive")
-	c.Header("X-Accel-Buffering", "no")
+	s.openStream(c)
 
 	// Subscribe to run-specific events
 	eventCh := s.sseHub.SubscribeRun(runID)
 	defer s.sseHub.UnsubscribeRun(runID, eventCh)
 
 	// Send initial state
-	run, err := s.repo.GetRunByID(runID)
+	run, err := s.repo.GetRunByID(c.Request.Context(), runID)
 	if err == nil && run != nil {
-		tests, _ := s.repo.GetTestResultsByRunID(runID)
+		tests, _ := s.repo.GetTestResultsByRunID(c.Request.Context(), runID)
 		initial := map[string]any{
 			"type":   "initial_state",
 			"run_id": runID,
@@ -116,8 +123,14 @@ func (s *Server) streamRunEvents(c *gin.Context) {
 		c.Writer.Flush()
 	}
 
+	// Send the step each running test is on (step events are not cached)
+	for _, event := range s.sseHub.GetActiveSteps(runID) {
+		c.Writer.WriteString(event)
+		c.Writer.Flush()
+	}
+
 	// Keep connection alive with heartbeat and stream events
-	ticker := time.NewTicker(15 * time.Second)
+	ticker := time.NewTicker(s.sseHub.HeartbeatInterval())
 	defer ticker.Stop()
 
 	clientGone := c.Request.Context().Done()
@@ -140,6 +153,29 @@ func (s *Server) streamRunEvents(c *gin.Context) {
 	}
 }
 
+// metrics handles GET /metrics
+// Exposes SSE hub and dual-write metrics in Prometheus text format
+func (s *Server) metrics(c *gin.Context) {
+	m := s.sseHub.Metrics()
+
+	var b strings.Builder
+	writeMetric := func(name, metricType, help string, value any) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, metricType, name, value)
+	}
+	writeMetric("tsuite_sse_global_clients", "gauge", "Connected global SSE clients", m.GlobalClients)
+	writeMetric("tsuite_sse_run_clients", "gauge", "Connected per-run SSE clients", m.RunClients)
+	writeMetric("tsuite_sse_events_emitted_total", "counter", "Events emitted by the SSE hub", m.EventsEmitted)
+	writeMetric("tsuite_sse_events_dropped_total", "counter", "Events dropped because a client buffer was full", m.EventsDropped)
+	writeMetric("tsuite_sse_events_evicted_total", "counter", "Buffered events evicted to deliver terminal run events", m.EventsEvicted)
+	if dw := s.repo.DualWriteStats(); dw.Enabled {
+		writeMetric("tsuite_dualwrite_writes_total", "counter", "Writes mirrored to the secondary database", dw.Writes)
+		writeMetric("tsuite_dualwrite_divergences_total", "counter", "Mirrored writes whose outcome differed from the primary's", dw.Divergences)
+		writeMetric("tsuite_dualwrite_pending", "gauge", "Writes queued for the secondary database", dw.Pending)
+	}
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
 // emitEvent handles POST /api/events/emit
 // This endpoint receives events from CLI subprocesses and broadcasts via SSE
 func (s *Server) emitEvent(c *gin.Context) {
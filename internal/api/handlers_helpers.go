@@ -1,7 +1,10 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
@@ -24,7 +27,7 @@ func (s *Server) parseSuiteID(c *gin.Context) (int64, bool) {
 // getSuiteOrError fetches a suite by ID and handles errors.
 // Returns the suite and true if successful, or sends error response and returns false.
 func (s *Server) getSuiteOrError(c *gin.Context, id int64) (*models.Suite, bool) {
-	suite, err := s.repo.GetSuiteByID(id)
+	suite, err := s.repo.GetSuiteByID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return nil, false
@@ -49,7 +52,7 @@ func (s *Server) getSuiteByIDParam(c *gin.Context) (*models.Suite, bool) {
 // getRunOrError fetches a run by ID and handles errors.
 // Returns the run and true if successful, or sends error response and returns false.
 func (s *Server) getRunOrError(c *gin.Context, runID string) (*models.Run, bool) {
-	run, err := s.repo.GetRunByID(runID)
+	run, err := s.repo.GetRunByID(c.Request.Context(), runID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return nil, false
@@ -78,12 +81,84 @@ func validatePhase(c *gin.Context, phase string) bool {
 	return true
 }
 
+// parsePagination reads the limit and offset query parameters. A missing limit
+// uses defaultLimit (0 means no limit) and limits above maxLimit are capped.
+// Returns false after sending an error response for invalid values.
+func parsePagination(c *gin.Context, defaultLimit, maxLimit int) (limit, offset int, ok bool) {
+	limit = defaultLimit
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit: " + l})
+			return 0, 0, false
+		}
+		limit = parsed
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if o := c.Query("offset"); o != "" {
+		parsed, err := strconv.Atoi(o)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset: " + o})
+			return 0, 0, false
+		}
+		offset = parsed
+	}
+	return limit, offset, true
+}
+
 // stripLeadingSlash removes the leading slash from a path parameter.
 // Gin wildcard params include a leading slash.
 func stripLeadingSlash(path string) string {
 	return strings.TrimPrefix(path, "/")
 }
 
+// contentETag returns the ETag of a YAML file's content, as sent by the editor
+// GET endpoints and expected back in If-Match
+func contentETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// checkIfMatch compares the If-Match header with the ETag of the file's current
+// content. Without the header (or with "*") the write is unconditional. On a
+// mismatch it sends 409 Conflict with the current content and returns false.
+func checkIfMatch(c *gin.Context, current []byte) bool {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return true
+	}
+
+	etag := contentETag(current)
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if strings.Trim(candidate, `"`) == strings.Trim(etag, `"`) {
+			return true
+		}
+	}
+
+	c.Header("ETag", etag)
+	c.JSON(http.StatusConflict, gin.H{
+		"error":    "File was modified since it was loaded; reload it and reapply your changes",
+		"etag":     etag,
+		"raw_yaml": string(current),
+	})
+	return false
+}
+
+// checkFileIfMatch runs checkIfMatch against the file at path (a missing file
+// counts as empty) and returns its current content. Callers hold s.yamlMu
+// until their write is done.
+func checkFileIfMatch(c *gin.Context, path, what string) ([]byte, bool) {
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read " + what + ": " + err.Error()})
+		return nil, false
+	}
+	return current, checkIfMatch(c, current)
+}
+
 // Phase constants
 const (
 	PhasePreRun  = "pre_run"
@@ -93,6 +168,7 @@ const (
 
 // Status constants for runs
 const (
+	RunStatusQueued    = "queued"
 	RunStatusPending   = "pending"
 	RunStatusRunning   = "running"
 	RunStatusCompleted = "completed"
@@ -1,20 +1,32 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
+	"maps"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/orchestrator"
 )
 
 // ==================== Stats ====================
 
 // getStats handles GET /api/stats
 func (s *Server) getStats(c *gin.Context) {
-	stats, err := s.repo.GetRunStats()
+	stats, err := s.repo.GetRunStats(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -23,10 +35,366 @@ func (s *Server) getStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// Suite health in the overview, from the suite's latest finished run
+const (
+	SuiteHealthPassing = "passing"
+	SuiteHealthFailing = "failing"
+	SuiteHealthNoRuns  = "no_runs"
+)
+
+// suiteOverview is one suite's entry in GET /api/overview
+type suiteOverview struct {
+	SuiteID     int64       `json:"suite_id"`
+	SuiteName   string      `json:"suite_name"`
+	FolderPath  string      `json:"folder_path"`
+	Mode        string      `json:"mode"`
+	TestCount   int         `json:"test_count"`
+	Health      string      `json:"health"`
+	PassRate    *float64    `json:"pass_rate"`     // Of the last run, nil without runs
+	LastRun     *models.Run `json:"last_run"`      // Latest completed or failed run
+	ActiveRunID *string     `json:"active_run_id"` // Pending or running run, if any
+}
+
+// getOverview handles GET /api/overview
+// Summarizes the latest finished run of every registered suite.
+func (s *Server) getOverview(c *gin.Context) {
+	ctx := c.Request.Context()
+	suites, err := s.repo.GetAllSuites(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	finished, err := s.repo.GetLatestRunsBySuite(ctx, RunStatusCompleted, RunStatusFailed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	active, err := s.repo.GetLatestRunsBySuite(ctx, RunStatusPending, RunStatusRunning)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	overview := make([]suiteOverview, 0, len(suites))
+	counts := map[string]int{SuiteHealthPassing: 0, SuiteHealthFailing: 0, SuiteHealthNoRuns: 0}
+	running := 0
+	for _, suite := range suites {
+		entry := suiteOverview{
+			SuiteID:    suite.ID,
+			SuiteName:  suite.SuiteName,
+			FolderPath: suite.FolderPath,
+			Mode:       string(suite.Mode),
+			TestCount:  suite.TestCount,
+			Health:     SuiteHealthNoRuns,
+		}
+
+		if run, ok := finished[suite.ID]; ok {
+			entry.LastRun = &run
+			entry.Health = SuiteHealthPassing
+			if run.Status == models.RunStatusFailed || run.Failed > 0 {
+				entry.Health = SuiteHealthFailing
+			}
+			if executed := run.Passed + run.Failed; executed > 0 {
+				rate := float64(int(float64(run.Passed)/float64(executed)*10000)) / 100
+				entry.PassRate = &rate
+			}
+		}
+		if run, ok := active[suite.ID]; ok {
+			runID := run.RunID
+			entry.ActiveRunID = &runID
+			running++
+		}
+
+		counts[entry.Health]++
+		overview = append(overview, entry)
+	}
+
+	// Failing suites first, then by name
+	healthOrder := map[string]int{SuiteHealthFailing: 0, SuiteHealthNoRuns: 1, SuiteHealthPassing: 2}
+	sort.SliceStable(overview, func(i, j int) bool {
+		if overview[i].Health != overview[j].Health {
+			return healthOrder[overview[i].Health] < healthOrder[overview[j].Health]
+		}
+		return overview[i].SuiteName < overview[j].SuiteName
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"suites": overview,
+		"summary": gin.H{
+			"total_suites": len(suites),
+			"passing":      counts[SuiteHealthPassing],
+			"failing":      counts[SuiteHealthFailing],
+			"no_runs":      counts[SuiteHealthNoRuns],
+			"running":      running,
+			"all_green":    len(suites) > 0 && counts[SuiteHealthPassing] == len(suites),
+		},
+	})
+}
+
+// assertionFailureStat groups failures of one normalized assertion expression
+type assertionFailureStat struct {
+	Expression   string     `json:"expression"`
+	FailureCount int        `json:"failure_count"`
+	RunCount     int        `json:"run_count"`
+	Tests        []string   `json:"tests"`
+	Messages     []string   `json:"messages"`
+	Examples     []string   `json:"examples"`
+	LastFailedAt *time.Time `json:"last_failed_at"`
+
+	runs map[string]bool
+}
+
+// maxAssertionSamples caps the distinct messages/examples kept per expression
+const maxAssertionSamples = 5
+
+// getAssertionStats handles GET /api/stats/assertions
+// Reports the most frequently failing assertion expressions across runs
+func (s *Server) getAssertionStats(c *gin.Context) {
+	days := 30
+	if d := c.Query("days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
+			return
+		}
+		days = parsed
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+			if limit > 100 {
+				limit = 100
+			}
+		}
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	failures, err := s.repo.GetFailedAssertionsSince(c.Request.Context(), since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Group failures by normalized expression (results are newest first)
+	byExpr := make(map[string]*assertionFailureStat)
+	for _, f := range failures {
+		key := interpolate.NormalizeAssertion(f.Expression)
+		stat, ok := byExpr[key]
+		if !ok {
+			stat = &assertionFailureStat{
+				Expression:   key,
+				Tests:        []string{},
+				Messages:     []string{},
+				Examples:     []string{},
+				LastFailedAt: f.StartedAt,
+				runs:         make(map[string]bool),
+			}
+			byExpr[key] = stat
+		}
+
+		stat.FailureCount++
+		stat.runs[f.RunID] = true
+		stat.Tests = appendUnique(stat.Tests, f.TestID, 0)
+		if f.Message != "" {
+			stat.Messages = appendUnique(stat.Messages, f.Message, maxAssertionSamples)
+		}
+		stat.Examples = appendUnique(stat.Examples, f.Expression, maxAssertionSamples)
+	}
+
+	stats := make([]*assertionFailureStat, 0, len(byExpr))
+	for _, stat := range byExpr {
+		stat.RunCount = len(stat.runs)
+		sort.Strings(stat.Tests)
+		stats = append(stats, stat)
+	}
+
+	// Most frequent first, then most widespread
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].FailureCount != stats[j].FailureCount {
+			return stats[i].FailureCount > stats[j].FailureCount
+		}
+		if len(stats[i].Tests) != len(stats[j].Tests) {
+			return len(stats[i].Tests) > len(stats[j].Tests)
+		}
+		return stats[i].Expression < stats[j].Expression
+	})
+
+	total := len(stats)
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"days":           days,
+		"since":          since.UTC().Format(time.RFC3339),
+		"total_failures": len(failures),
+		"assertions":     stats,
+		"count":          total,
+	})
+}
+
+// appendUnique appends value if not already present, up to max entries (0 = unlimited)
+func appendUnique(values []string, value string, max int) []string {
+	if max > 0 && len(values) >= max {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// ==================== Coverage ====================
+
+// Feature coverage status in GET /api/coverage
+const (
+	CoveragePassing   = "passing"   // Every covering test that ran passed
+	CoverageFailing   = "failing"   // A covering test failed or crashed
+	CoverageNotRun    = "not_run"   // Covering tests were skipped or have not finished
+	CoverageUncovered = "uncovered" // Listed in coverage.features, but no test covers it
+)
+
+// coveringTest is a test covering a feature, with its status in the run
+type coveringTest struct {
+	TestID string            `json:"test_id"`
+	Status models.TestStatus `json:"status"`
+}
+
+// featureCoverage is one feature's entry in GET /api/coverage
+type featureCoverage struct {
+	Feature string         `json:"feature"`
+	Status  string         `json:"status"`
+	Passed  int            `json:"passed"`
+	Failed  int            `json:"failed"` // Failed or crashed
+	Tests   []coveringTest `json:"tests"`
+}
+
+// getCoverage handles GET /api/coverage
+// Reports which features the tests of a run cover (covers: in test.yaml), and
+// which features of the suite's coverage.features no test covers. The run is
+// run_id, else the latest finished run of suite_id, else the latest run.
+func (s *Server) getCoverage(c *gin.Context) {
+	ctx := c.Request.Context()
+	var run *models.Run
+	var err error
+	if runID := c.Query("run_id"); runID != "" {
+		if run, err = s.repo.GetRunByID(ctx, runID); err == nil && run == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Run not found: " + runID})
+			return
+		}
+	} else if sid := c.Query("suite_id"); sid != "" {
+		suiteID, parseErr := strconv.ParseInt(sid, 10, 64)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suite_id: " + sid})
+			return
+		}
+		var finished map[int64]models.Run
+		if finished, err = s.repo.GetLatestRunsBySuite(ctx, RunStatusCompleted, RunStatusFailed); err == nil {
+			latest, ok := finished[suiteID]
+			if !ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "No finished run of suite " + sid})
+				return
+			}
+			run = &latest
+		}
+	} else if run, err = s.repo.GetLatestRun(ctx); err == nil && run == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No runs found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	covered, err := s.repo.GetRunCoverage(ctx, run.RunID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Features are listed in the suite's current config.yaml
+	var listed []string
+	if run.SuiteID.Valid {
+		if suite, err := s.repo.GetSuiteByID(ctx, run.SuiteID.Int64); err == nil && suite != nil {
+			if suiteConfig, err := config.LoadSuiteConfig(suite.FolderPath); err == nil {
+				listed = suiteConfig.Coverage.Features
+			}
+		}
+	}
+
+	byFeature := make(map[string]*featureCoverage)
+	for _, feature := range listed {
+		byFeature[feature] = &featureCoverage{Feature: feature, Tests: []coveringTest{}}
+	}
+	unlisted := []string{}
+	for _, cf := range covered {
+		fc, ok := byFeature[cf.Feature]
+		if !ok {
+			fc = &featureCoverage{Feature: cf.Feature}
+			byFeature[cf.Feature] = fc
+			if len(listed) > 0 {
+				unlisted = append(unlisted, cf.Feature)
+			}
+		}
+		fc.Tests = append(fc.Tests, coveringTest{TestID: cf.TestID, Status: cf.Status})
+		switch cf.Status {
+		case models.TestStatusPassed:
+			fc.Passed++
+		case models.TestStatusFailed, models.TestStatusCrashed:
+			fc.Failed++
+		}
+	}
+
+	features := make([]*featureCoverage, 0, len(byFeature))
+	counts := map[string]int{CoveragePassing: 0, CoverageFailing: 0, CoverageNotRun: 0, CoverageUncovered: 0}
+	for _, fc := range byFeature {
+		switch {
+		case len(fc.Tests) == 0:
+			fc.Status = CoverageUncovered
+		case fc.Failed > 0:
+			fc.Status = CoverageFailing
+		case fc.Passed > 0:
+			fc.Status = CoveragePassing
+		default:
+			fc.Status = CoverageNotRun
+		}
+		counts[fc.Status]++
+		features = append(features, fc)
+	}
+
+	// Gaps first: failing, then uncovered, then not run; then by feature
+	statusOrder := map[string]int{CoverageFailing: 0, CoverageUncovered: 1, CoverageNotRun: 2, CoveragePassing: 3}
+	sort.Slice(features, func(i, j int) bool {
+		if features[i].Status != features[j].Status {
+			return statusOrder[features[i].Status] < statusOrder[features[j].Status]
+		}
+		return features[i].Feature < features[j].Feature
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"run_id":   run.RunID,
+		"suite_id": nullInt64Value(run.SuiteID),
+		"features": features,
+		"unlisted": unlisted, // Covered, but missing from coverage.features
+		"summary": gin.H{
+			"total_features": len(features),
+			"passing":        counts[CoveragePassing],
+			"failing":        counts[CoverageFailing],
+			"not_run":        counts[CoverageNotRun],
+			"uncovered":      counts[CoverageUncovered],
+		},
+	})
+}
+
 // ==================== Suite Run ====================
 
 // runSuite handles POST /api/suites/:id/run
-// Launches the Go CLI as a subprocess to run tests
+// Launches the Go CLI as a subprocess (or, with --in-process, the orchestrator
+// in this server) to run tests
 func (s *Server) runSuite(c *gin.Context) {
 	suite, ok := s.getSuiteByIDParam(c)
 	if !ok {
@@ -39,88 +407,374 @@ func (s *Server) runSuite(c *gin.Context) {
 		return
 	}
 
-	// Parse request body for filters
+	// Parse request body for filters and run options
 	var req struct {
-		UC       string   `json:"uc"`
-		TC       string   `json:"tc"`
-		Tags     []string `json:"tags"`
-		SkipTags []string `json:"skip_tags"`
+		UC           string            `json:"uc"`
+		TC           string            `json:"tc"`
+		Tags         []string          `json:"tags"`
+		SkipTags     []string          `json:"skip_tags"`
+		Parallel     int               `json:"parallel"`      // Parallel test runners (default: suite's max_workers)
+		Mode         string            `json:"mode"`          // docker or standalone instead of the suite's mode
+		Profile      string            `json:"profile"`       // Run profile from config.yaml
+		Env          map[string]string `json:"env"`           // Set for every test
+		Smoke        bool              `json:"smoke"`         // Only smoke tests, within smoke.yaml's time budget
+		ChangedSince string            `json:"changed_since"` // Only tests affected by git changes since this ref
+		NodeVersion  string            `json:"node_version"`  // Node.js version instead of packages.node_version
+		Offline      bool              `json:"offline"`       // Forbid fetching from the internet
 	}
 	c.ShouldBindJSON(&req) // Optional body
+	if req.Parallel < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parallel must not be negative"})
+		return
+	}
+	if req.Mode != "" && req.Mode != string(models.SuiteModeDocker) && req.Mode != string(models.SuiteModeStandalone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mode: " + req.Mode})
+		return
+	}
+	for name := range req.Env {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid env variable name: " + name})
+			return
+		}
+	}
+	if req.Profile != "" {
+		suiteConfig, err := config.LoadSuiteConfig(suite.FolderPath)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if _, ok := suiteConfig.Profiles[req.Profile]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found in config.yaml: " + req.Profile})
+			return
+		}
+	}
 
-	// Find tsuite executable
-	execPath, err := os.Executable()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find executable: " + err.Error()})
+	wait := c.Query("wait") == "true"
+	timeout, ok := parseWaitTimeout(c, defaultRunWait, maxRunWait)
+	if !ok {
 		return
 	}
 
-	// Build CLI command
-	apiURL := "http://localhost:" + strconv.Itoa(s.port)
-	args := []string{
-		"run",
-		"--suite-path", suite.FolderPath,
-		"--api-url", apiURL,
+	opts := orchestrator.Options{
+		Filter: orchestrator.Filter{
+			Tags:     req.Tags,
+			SkipTags: req.SkipTags,
+		},
+		Parallel:     req.Parallel,
+		Mode:         req.Mode,
+		Profile:      req.Profile,
+		Smoke:        req.Smoke,
+		ChangedSince: req.ChangedSince,
+		NodeVersion:  req.NodeVersion,
+		Offline:      req.Offline,
+	}
+	// If no filters, run all tests (default behavior)
+	if req.TC != "" {
+		opts.Filter.TC = []string{req.TC}
+	} else if req.UC != "" {
+		opts.Filter.UC = []string{req.UC}
+	}
+	for _, name := range slices.Sorted(maps.Keys(req.Env)) {
+		opts.Env = append(opts.Env, name+"="+req.Env[name])
+	}
+
+	// Runs beyond the suite's concurrent run limit wait in the queue
+	job, err := s.enqueueRun(c.Request.Context(), suite, "tsuite_run_*.log", opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response, ok := s.launchResponse(job)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, response)
+		return
 	}
 
-	// Add filter flags
+	// Build description
 	if req.TC != "" {
-		args = append(args, "--tc", req.TC)
+		response["description"] = "Running test case: " + req.TC
 	} else if req.UC != "" {
-		args = append(args, "--uc", req.UC)
+		response["description"] = "Running use case: " + req.UC
+	} else {
+		response["description"] = "Running all tests in: " + suite.SuiteName
+	}
+
+	if !wait {
+		c.JSON(http.StatusOK, response)
+		return
 	}
-	// If no filters, run all tests (default behavior)
 
-	// Add tag filters
-	for _, tag := range req.Tags {
-		args = append(args, "--tags", tag)
+	// Hold the request until the run finishes, for CI jobs gating on the result
+	if !s.awaitRun(c, job.runID, job.exited, timeout) {
+		return
 	}
-	// Note: skip_tags not implemented in Go CLI yet
+	s.respondRunResult(c, job.runID, response)
+}
 
-	// Create log file
-	logFile, err := os.CreateTemp("", "tsuite_run_*.log")
+// Suite runs: how long ?wait=true blocks by default and at most
+const (
+	defaultRunWait = 30 * time.Minute
+	maxRunWait     = 2 * time.Hour
+)
+
+// Exit-style status of a waited-for run, for CI scripts
+const (
+	runExitPassed    = 0 // Completed without failures
+	runExitFailed    = 1 // Failed or crashed tests
+	runExitCancelled = 2
+	runExitRunning   = 3 // Still running when the wait timed out
+)
+
+// parseWaitTimeout parses ?timeout= as seconds or a Go duration (e.g. 90s,
+// 30m), capped at max. It writes a 400 response and returns false if invalid.
+func parseWaitTimeout(c *gin.Context, def, max time.Duration) (time.Duration, bool) {
+	t := c.Query("timeout")
+	if t == "" {
+		return def, true
+	}
+	timeout, err := time.ParseDuration(t)
+	if seconds, serr := strconv.Atoi(t); serr == nil {
+		timeout, err = time.Duration(seconds)*time.Second, nil
+	}
+	if err != nil || timeout <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timeout (expected seconds or a duration like 30m): " + t})
+		return 0, false
+	}
+	return min(timeout, max), true
+}
+
+// respondRunResult adds the summary of a run to response and writes it: 200
+// once the run has finished, 202 with timed_out while it is queued or running
+func (s *Server) respondRunResult(c *gin.Context, runID string, response gin.H) {
+	run, err := s.repo.GetRunByID(c.Request.Context(), runID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create log file: " + err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if run == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Run not found"})
 		return
 	}
-	logPath := logFile.Name()
 
-	// Start subprocess
-	cmd := newExecCommand(execPath, args...)
-	cmd.Dir = suite.FolderPath
-	cmd.Stdout = logFile
-	cmd.Stderr = logFile
+	response["run_id"] = run.RunID
+	response["status"] = run.Status
+	response["finished"] = run.Status.IsTerminal()
+	response["summary"] = gin.H{
+		"total_tests": run.TotalTests,
+		"passed":      run.Passed,
+		"failed":      run.Failed,
+		"skipped":     run.Skipped,
+		"duration_ms": nullInt64Value(run.DurationMS),
+	}
 
-	if err := cmd.Start(); err != nil {
-		logFile.Close()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start CLI: " + err.Error()})
+	switch run.Status {
+	case models.RunStatusCompleted:
+		response["exit_code"] = runExitPassed
+	case models.RunStatusFailed:
+		response["exit_code"] = runExitFailed
+	case models.RunStatusCancelled:
+		response["exit_code"] = runExitCancelled
+	default:
+		response["exit_code"] = runExitRunning
+		response["timed_out"] = true
+		if run.Status == models.RunStatusQueued {
+			response["queue_position"] = s.queuePosition(run.RunID)
+		}
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	failedTests := []string{}
+	failedOwners := map[string][]string{} // Owner -> their failed tests
+	if run.Failed > 0 {
+		results, err := s.repo.GetTestResultsByRunID(c.Request.Context(), run.RunID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for _, tr := range results {
+			if tr.Status == models.TestStatusFailed || tr.Status == models.TestStatusCrashed {
+				failedTests = append(failedTests, tr.TestID)
+				var owners []string
+				if tr.Owners.Valid && tr.Owners.String != "" {
+					_ = json.Unmarshal([]byte(tr.Owners.String), &owners)
+				}
+				for _, owner := range owners {
+					failedOwners[owner] = append(failedOwners[owner], tr.TestID)
+				}
+			}
+		}
+	}
+	response["failed_tests"] = failedTests
+	response["failed_owners"] = failedOwners
+	c.JSON(http.StatusOK, response)
+}
+
+// runWaitPollInterval is how often waiting requests check the run status
+const runWaitPollInterval = time.Second
+
+// waitRun handles GET /api/runs/:run_id/wait
+// Long-polls until the run finishes or ?timeout= (default 60s, max 30m)
+// passes, then answers like POST /api/suites/:id/run?wait=true. Clients whose
+// proxies cut long requests call it again while the answer is 202.
+func (s *Server) waitRun(c *gin.Context) {
+	run, ok := s.getRunByIDParam(c)
+	if !ok {
+		return
+	}
+	timeout, ok := parseWaitTimeout(c, time.Minute, maxTestRunWait)
+	if !ok {
+		return
+	}
+
+	if !s.awaitRun(c, run.RunID, nil, timeout) {
 		return
 	}
+	s.respondRunResult(c, run.RunID, gin.H{})
+}
 
-	// Close log file - subprocess has inherited the FD
+// awaitRun blocks until the run finishes, timeout passes, or exited (if not
+// nil) is closed because the CLI running it exited. It returns false if the
+// client went away.
+func (s *Server) awaitRun(c *gin.Context, runID string, exited <-chan struct{}, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(runWaitPollInterval)
+	defer ticker.Stop()
+	for {
+		run, err := s.repo.GetRunByID(c.Request.Context(), runID)
+		if err != nil || run == nil || run.Status.IsTerminal() {
+			return true
+		}
+		select {
+		case <-ticker.C:
+		case <-exited:
+			return true
+		case <-deadline:
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	}
+}
+
+// Single test runs: how long ?wait=true blocks by default and at most
+const (
+	defaultTestRunWait = 5 * time.Minute
+	maxTestRunWait     = 30 * time.Minute
+)
+
+// runSuiteTest handles POST /api/suites/:id/tests/{uc}/{tc}/run
+// Runs one test like runSuite and returns the new run_id. With ?wait=true
+// the response is held until the run finishes (or ?timeout=, default 5m) and
+// includes the test result.
+func (s *Server) runSuiteTest(c *gin.Context, suite *models.Suite, testID string) {
+	if _, err := os.Stat(filepath.Join(suite.FolderPath, "suites", filepath.FromSlash(testID), "test.yaml")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test not found: " + testID})
+		return
+	}
+
+	wait := c.Query("wait") == "true"
+	timeout, ok := parseWaitTimeout(c, defaultTestRunWait, maxTestRunWait)
+	if !ok {
+		return
+	}
+
+	job, err := s.enqueueRun(c.Request.Context(), suite, "tsuite_run_*.log", orchestrator.Options{
+		Filter: orchestrator.Filter{TC: []string{testID}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response, ok := s.launchResponse(job)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	response["test_id"] = testID
+	if !wait {
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	if !s.awaitRun(c, job.runID, job.exited, timeout) {
+		return
+	}
+
+	run, err := s.repo.GetRunByID(c.Request.Context(), job.runID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if run == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Run not found"})
+		return
+	}
+	response["status"] = run.Status
+	if !run.Status.IsTerminal() {
+		response["timed_out"] = true
+		if run.Status == models.RunStatusQueued {
+			response["queue_position"] = s.queuePosition(run.RunID)
+		}
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	result, err := s.repo.GetTestResultByTestIDAndRunID(c.Request.Context(), testID, run.RunID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response["run"] = run
+	response["result"] = result
+	c.JSON(http.StatusOK, response)
+}
+
+// startCLI starts tsuite with args in dir, writing its output to a new temp
+// file named after logPattern. done is closed once the process has exited.
+func startCLI(dir, logPattern string, args ...string) (cmd *execCmd, logPath string, done <-chan struct{}, err error) {
+	logFile, err := os.CreateTemp("", logPattern)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("Failed to create log file: %w", err)
+	}
 	logFile.Close()
 
-	// Build description
-	var description string
-	if req.TC != "" {
-		description = "Running test case: " + req.TC
-	} else if req.UC != "" {
-		description = "Running use case: " + req.UC
-	} else {
-		description = "Running all tests in: " + suite.SuiteName
+	cmd, done, err = startCLIWithLog(dir, logFile.Name(), args...)
+	if err != nil {
+		return nil, "", nil, err
 	}
+	return cmd, logFile.Name(), done, nil
+}
+
+// startCLIWithLog starts tsuite with args in dir, appending its output to
+// logPath. done is closed once the process has exited.
+func startCLIWithLog(dir, logPath string, args ...string) (cmd *execCmd, done <-chan struct{}, err error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to find executable: %w", err)
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to open log file: %w", err)
+	}
+	// The subprocess inherits the FD
+	defer logFile.Close()
 
-	// Don't wait for subprocess - let it run independently
+	cmd = newExecCommand(execPath, args...)
+	cmd.Dir = dir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("Failed to start CLI: %w", err)
+	}
+
+	exited := make(chan struct{})
 	go func() {
 		cmd.Wait()
+		close(exited)
 	}()
-
-	c.JSON(http.StatusOK, gin.H{
-		"started":     true,
-		"pid":         cmd.Process.Pid,
-		"description": description,
-		"log_file":    logPath,
-	})
+	return cmd, exited, nil
 }
 
 // newExecCommand creates a new exec.Cmd - extracted for testing
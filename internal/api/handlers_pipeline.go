@@ -0,0 +1,251 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// ==================== Pipelines ====================
+
+// pipelineRunDetail is a pipeline run with its suite runs and their totals
+type pipelineRunDetail struct {
+	models.PipelineRun
+	Runs       []models.Run `json:"runs"`
+	TotalTests int          `json:"total_tests"`
+	Passed     int          `json:"passed"`
+	Failed     int          `json:"failed"`
+	Skipped    int          `json:"skipped"`
+}
+
+// getPipelineRunByIDParam loads the pipeline run named by the :id path
+// parameter, writing an error response if it cannot
+func (s *Server) getPipelineRunByIDParam(c *gin.Context) (*models.PipelineRun, bool) {
+	pipeline, err := s.repo.GetPipelineRunByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if pipeline == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pipeline run not found"})
+		return nil, false
+	}
+	return pipeline, true
+}
+
+// pipelineRunDetail adds the suite runs of a pipeline run, oldest first
+func (s *Server) pipelineRunDetail(ctx context.Context, pipeline *models.PipelineRun) (*pipelineRunDetail, error) {
+	runs, _, err := s.repo.ListRuns(ctx, db.RunFilter{PipelineRunID: pipeline.PipelineRunID, Asc: true})
+	if err != nil {
+		return nil, err
+	}
+	if runs == nil {
+		runs = []models.Run{}
+	}
+
+	detail := &pipelineRunDetail{PipelineRun: *pipeline, Runs: runs}
+	for _, run := range runs {
+		detail.TotalTests += run.TotalTests
+		detail.Passed += run.Passed
+		detail.Failed += run.Failed
+		detail.Skipped += run.Skipped
+	}
+	return detail, nil
+}
+
+// listPipelineRuns handles GET /api/pipelines/runs
+func (s *Server) listPipelineRuns(c *gin.Context) {
+	limit, offset, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
+	}
+
+	pipelines, total, err := s.repo.ListPipelineRuns(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pipeline_runs": pipelines,
+		"count":         len(pipelines),
+		"total":         total,
+		"limit":         limit,
+		"offset":        offset,
+	})
+}
+
+// getPipelineRun handles GET /api/pipelines/runs/:id
+func (s *Server) getPipelineRun(c *gin.Context) {
+	pipeline, ok := s.getPipelineRunByIDParam(c)
+	if !ok {
+		return
+	}
+
+	detail, err := s.pipelineRunDetail(c.Request.Context(), pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+// createPipelineRun handles POST /api/pipelines/runs
+// Called by tsuite pipeline run before it starts the suite runs.
+func (s *Server) createPipelineRun(c *gin.Context) {
+	var req struct {
+		Name        string `json:"name" binding:"required"`
+		FilePath    string `json:"file_path"`
+		Mode        string `json:"mode"`
+		TotalSuites int    `json:"total_suites"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = models.PipelineModeSequential
+	}
+	if req.Mode != models.PipelineModeSequential && req.Mode != models.PipelineModeParallel {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mode: " + req.Mode})
+		return
+	}
+
+	pipeline := &models.PipelineRun{
+		PipelineRunID: generateUUID(),
+		Name:          req.Name,
+		FilePath:      req.FilePath,
+		Mode:          req.Mode,
+		Status:        models.RunStatusRunning,
+		TotalSuites:   req.TotalSuites,
+		StartedAt:     time.Now().UTC(),
+	}
+	if err := s.repo.CreatePipelineRun(c.Request.Context(), pipeline); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pipeline run: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pipeline)
+}
+
+// completePipelineRun handles POST /api/pipelines/runs/:id/complete
+// The status is derived from the suite runs (see db.FinishPipelineRun).
+// Optional body: {"suites_failed": N}, suites the CLI saw fail.
+func (s *Server) completePipelineRun(c *gin.Context) {
+	pipeline, ok := s.getPipelineRunByIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		SuitesFailed int `json:"suites_failed"`
+	}
+	c.ShouldBindJSON(&req) // Optional body
+
+	if err := s.repo.FinishPipelineRun(c.Request.Context(), pipeline.PipelineRunID, req.SuitesFailed); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete pipeline run: " + err.Error()})
+		return
+	}
+
+	pipeline, err := s.repo.GetPipelineRunByID(c.Request.Context(), pipeline.PipelineRunID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pipeline)
+}
+
+// cancelPipelineRun handles POST /api/pipelines/runs/:id/cancel
+// Requests cancellation of the suite runs in progress; suites not started yet
+// are skipped.
+func (s *Server) cancelPipelineRun(c *gin.Context) {
+	pipeline, ok := s.getPipelineRunByIDParam(c)
+	if !ok {
+		return
+	}
+	if pipeline.Status != models.RunStatusPending && pipeline.Status != models.RunStatusRunning {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot cancel pipeline run with status: " + string(pipeline.Status)})
+		return
+	}
+
+	if err := s.repo.SetPipelineCancelRequested(c.Request.Context(), pipeline.PipelineRunID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Let live views of the suite runs know
+	runs, _, err := s.repo.ListRuns(c.Request.Context(), db.RunFilter{PipelineRunID: pipeline.PipelineRunID})
+	if err == nil {
+		for _, run := range runs {
+			if run.CancelRequested {
+				s.sseHub.EmitCancelRequested(run.RunID)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":          true,
+		"pipeline_run_id":  pipeline.PipelineRunID,
+		"cancel_requested": true,
+	})
+}
+
+// runPipeline handles POST /api/pipelines/run
+// Launches tsuite pipeline run for a pipeline file as a subprocess, like
+// runSuite does for a single suite.
+func (s *Server) runPipeline(c *gin.Context) {
+	var req struct {
+		Path string `json:"path" binding:"required"` // Pipeline file
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	pipelineConfig, err := config.LoadPipelineConfig(req.Path)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Create the pipeline run here so its ID can be returned
+	pipeline := &models.PipelineRun{
+		PipelineRunID: generateUUID(),
+		Name:          pipelineConfig.Name,
+		FilePath:      pipelineConfig.Path,
+		Mode:          pipelineConfig.Mode,
+		Status:        models.RunStatusRunning,
+		TotalSuites:   len(pipelineConfig.Suites),
+		StartedAt:     time.Now().UTC(),
+	}
+	if err := s.repo.CreatePipelineRun(c.Request.Context(), pipeline); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pipeline run: " + err.Error()})
+		return
+	}
+
+	cmd, logPath, _, err := startCLI(filepath.Dir(pipelineConfig.Path), "tsuite_pipeline_*.log",
+		"pipeline", "run", pipelineConfig.Path,
+		"--api-url", s.localURL(),
+		"--pipeline-run-id", pipeline.PipelineRunID,
+	)
+	if err != nil {
+		s.repo.FinishPipelineRun(c.Request.Context(), pipeline.PipelineRunID, 1)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"started":         true,
+		"pipeline_run_id": pipeline.PipelineRunID,
+		"pid":             cmd.Process.Pid,
+		"description":     "Running pipeline: " + pipeline.Name,
+		"log_file":        logPath,
+	})
+}
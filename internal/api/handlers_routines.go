@@ -0,0 +1,249 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// ==================== Routines YAML Editor ====================
+
+// emptyRoutinesYAML seeds a routines.yaml that does not exist yet
+const emptyRoutinesYAML = "routines: {}\n"
+
+// getGlobalRoutines handles GET /api/suites/:id/routines
+func (s *Server) getGlobalRoutines(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+	s.getRoutinesYAML(c, suite.ID, "global", filepath.Join(suite.FolderPath, "global"))
+}
+
+// updateGlobalRoutines handles PUT /api/suites/:id/routines
+func (s *Server) updateGlobalRoutines(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+	s.updateRoutinesYAML(c, suite, "global", filepath.Join(suite.FolderPath, "global"), true)
+}
+
+// getUseCaseRoutines handles GET /api/suites/:id/uc/:uc/routines
+func (s *Server) getUseCaseRoutines(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+	ucDir, ok := useCaseDir(c, suite.FolderPath)
+	if !ok {
+		return
+	}
+	s.getRoutinesYAML(c, suite.ID, c.Param("uc"), ucDir)
+}
+
+// updateUseCaseRoutines handles PUT /api/suites/:id/uc/:uc/routines
+func (s *Server) updateUseCaseRoutines(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+	ucDir, ok := useCaseDir(c, suite.FolderPath)
+	if !ok {
+		return
+	}
+	s.updateRoutinesYAML(c, suite, c.Param("uc"), ucDir, false)
+}
+
+// useCaseDir resolves the :uc param to an existing use case directory
+func useCaseDir(c *gin.Context, suitePath string) (string, bool) {
+	uc := c.Param("uc")
+	if uc == "" || uc != filepath.Base(uc) || uc == ".." {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid use case: " + uc})
+		return "", false
+	}
+	dir := filepath.Join(suitePath, "suites", uc)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Use case not found: " + uc})
+		return "", false
+	}
+	return dir, true
+}
+
+// loadRoutineScope loads the merged routines of the global or a use case directory
+func loadRoutineScope(dir string, global bool) (map[string]config.RoutineDefinition, error) {
+	if global {
+		routines, err := config.LoadGlobalRoutines(filepath.Dir(dir))
+		if err != nil {
+			return nil, err
+		}
+		return routines.Routines, nil
+	}
+	routines, err := config.LoadUseCaseRoutines(dir)
+	if err != nil {
+		return nil, err
+	}
+	return routines.Routines, nil
+}
+
+// getRoutinesYAML returns dir/routines.yaml. A missing file is returned as
+// empty with exists=false so the dashboard can create it.
+func (s *Server) getRoutinesYAML(c *gin.Context, suiteID int64, scope, dir string) {
+	routinesPath := filepath.Join(dir, "routines.yaml")
+
+	rawYAML, err := os.ReadFile(routinesPath)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read routines: " + err.Error()})
+		return
+	}
+
+	// Parse YAML into structure
+	var structure map[string]any
+	if err := yaml.Unmarshal(rawYAML, &structure); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse routines: " + err.Error()})
+		return
+	}
+
+	// Routine library files in dir/routines/ are merged with routines.yaml
+	// at load time but are not edited here
+	libraryFiles, _ := filepath.Glob(filepath.Join(dir, "routines", "*.y*ml"))
+	sort.Strings(libraryFiles)
+	library := make([]string, 0, len(libraryFiles))
+	for _, path := range libraryFiles {
+		library = append(library, filepath.Join("routines", filepath.Base(path)))
+	}
+
+	etag := contentETag(rawYAML)
+	c.Header("ETag", etag)
+	c.JSON(http.StatusOK, gin.H{
+		"suite_id":      suiteID,
+		"scope":         scope,
+		"path":          routinesPath,
+		"exists":        exists,
+		"raw_yaml":      string(rawYAML),
+		"structure":     structure,
+		"library_files": library,
+		"etag":          etag,
+	})
+}
+
+// updateRoutinesYAML writes dir/routines.yaml from raw YAML or merged updates
+// (preserving comments and key ordering). The file is created if missing. If
+// the routines no longer load (e.g. a name now clashes with a library file),
+// the previous content is restored.
+func (s *Server) updateRoutinesYAML(c *gin.Context, suite *models.Suite, scope, dir string, global bool) {
+	routinesPath := filepath.Join(dir, "routines.yaml")
+
+	var req struct {
+		RawYAML string         `json:"raw_yaml"`
+		Updates map[string]any `json:"updates"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	s.yamlMu.Lock()
+	defer s.yamlMu.Unlock()
+
+	oldYAML, err := os.ReadFile(routinesPath)
+	existed := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read routines: " + err.Error()})
+		return
+	}
+	if !checkIfMatch(c, oldYAML) {
+		return
+	}
+
+	var newYAML []byte
+
+	if req.RawYAML != "" {
+		newYAML = []byte(req.RawYAML)
+	} else if req.Updates != nil {
+		// Use YAMLDocument to preserve comments and key ordering
+		base := oldYAML
+		if len(base) == 0 {
+			base = []byte(emptyRoutinesYAML)
+		}
+		var root yaml.Node
+		if err := yaml.Unmarshal(base, &root); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse routines: " + err.Error()})
+			return
+		}
+		doc := &YAMLDocument{Root: &root}
+
+		if err := doc.MergeUpdates(req.Updates); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge updates: " + err.Error()})
+			return
+		}
+
+		newYAML, err = doc.ToBytes()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to marshal routines: " + err.Error()})
+			return
+		}
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Must provide 'raw_yaml' or 'updates'"})
+		return
+	}
+
+	// Validate against the routines schema before touching the file
+	var parsed config.GlobalRoutinesConfig
+	if err := yaml.Unmarshal(newYAML, &parsed); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid routines YAML: " + err.Error()})
+		return
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create directory: " + err.Error()})
+		return
+	}
+	if err := os.WriteFile(routinesPath, newYAML, 0644); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write routines: " + err.Error()})
+		return
+	}
+
+	routines, err := loadRoutineScope(dir, global)
+	if err != nil {
+		if existed {
+			os.WriteFile(routinesPath, oldYAML, 0644)
+		} else {
+			os.Remove(routinesPath)
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid routines: " + err.Error()})
+		return
+	}
+
+	action := models.AuditActionUpdate
+	if !existed {
+		action = models.AuditActionCreate
+	}
+	relPath, _ := filepath.Rel(suite.FolderPath, routinesPath)
+	s.recordAudit(c, suite, filepath.ToSlash(relPath), action, oldYAML, newYAML)
+
+	names := make([]string, 0, len(routines))
+	for name := range routines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	etag := contentETag(newYAML)
+	c.Header("ETag", etag)
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"suite_id": suite.ID,
+		"scope":    scope,
+		"raw_yaml": string(newYAML),
+		"routines": names,
+		"etag":     etag,
+	})
+}
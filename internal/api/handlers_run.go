@@ -3,42 +3,52 @@ package api
 import (
 	"database/sql"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/orchestrator"
 )
 
 // ==================== Runs ====================
 
 // listRuns handles GET /api/runs
 func (s *Server) listRuns(c *gin.Context) {
-	limit := 20
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
-			limit = parsed
-			if limit > 100 {
-				limit = 100
-			}
-		}
+	limit, offset, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
 	}
 
-	var suiteID *int64
+	filter := db.RunFilter{
+		Status: c.Query("status"),
+		Sort:   c.Query("sort"),
+		Asc:    c.Query("order") == "asc",
+		Limit:  limit,
+		Offset: offset,
+		Cursor: c.Query("cursor"),
+	}
+	filter.PipelineRunID = c.Query("pipeline_run_id")
 	if sid := c.Query("suite_id"); sid != "" {
 		if parsed, err := strconv.ParseInt(sid, 10, 64); err == nil {
-			suiteID = &parsed
+			filter.SuiteID = &parsed
 		}
 	}
 
-	runs, err := s.repo.GetAllRuns(suiteID, limit)
+	runs, total, err := s.repo.ListRuns(c.Request.Context(), filter)
 	if err != nil {
+		if errors.Is(err, db.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -48,16 +58,23 @@ func (s *Server) listRuns(c *gin.Context) {
 		runs = []models.Run{}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"runs":  runs,
-		"count": len(runs),
-		"limit": limit,
-	})
+	response := gin.H{
+		"runs":   runs,
+		"count":  len(runs),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	}
+	// The next page starts after the last run returned
+	if len(runs) == limit && (filter.Sort == "" || filter.Sort == "started_at") {
+		response["next_cursor"] = runs[len(runs)-1].RunID
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // getLatestRun handles GET /api/runs/latest
 func (s *Server) getLatestRun(c *gin.Context) {
-	run, err := s.repo.GetLatestRun()
+	run, err := s.repo.GetLatestRun(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -78,7 +95,7 @@ func (s *Server) getRun(c *gin.Context) {
 	}
 
 	// Get test results
-	tests, err := s.repo.GetTestResultsByRunID(run.RunID)
+	tests, err := s.repo.GetTestResultsByRunID(c.Request.Context(), run.RunID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -97,6 +114,12 @@ func (s *Server) getRun(c *gin.Context) {
 		"sdk_python_version":     nullStringValue(run.SDKPythonVersion),
 		"sdk_typescript_version": nullStringValue(run.SDKTypescriptVersion),
 		"docker_image":           nullStringValue(run.DockerImage),
+		"runner_version":         nullStringValue(run.RunnerVersion),
+		"runner_sha256":          nullStringValue(run.RunnerSHA256),
+		"workers":                nullInt64Value(run.Workers),
+		"smoke_status":           nullStringValue(run.SmokeStatus),
+		"smoke_budget_ms":        nullInt64Value(run.SmokeBudgetMS),
+		"profile":                nullStringValue(run.Profile),
 		"total_tests":            run.TotalTests,
 		"pending_count":          run.PendingCount,
 		"running_count":          run.RunningCount,
@@ -117,8 +140,31 @@ func (s *Server) getRunTests(c *gin.Context) {
 		return
 	}
 
-	tests, err := s.repo.GetTestResultsByRunID(run.RunID)
+	// No limit by default so existing clients still get every test
+	limit, offset, ok := parsePagination(c, 0, 1000)
+	if !ok {
+		return
+	}
+
+	filter := db.TestResultFilter{
+		UseCase: c.Query("uc"),
+		Tag:     c.Query("tag"),
+		Owner:   c.Query("owner"),
+		Sort:    c.Query("sort"),
+		Desc:    c.Query("order") == "desc",
+		Limit:   limit,
+		Offset:  offset,
+	}
+	if statusFilter := c.Query("status"); statusFilter != "" {
+		filter.Statuses = strings.Split(statusFilter, ",")
+	}
+
+	tests, total, err := s.repo.ListTestResults(c.Request.Context(), run.RunID, filter)
 	if err != nil {
+		if errors.Is(err, db.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -128,24 +174,74 @@ func (s *Server) getRunTests(c *gin.Context) {
 		tests = []models.TestResult{}
 	}
 
-	// Optional status filter
-	if statusFilter := c.Query("status"); statusFilter != "" {
-		filtered := make([]models.TestResult, 0)
-		for _, t := range tests {
-			if string(t.Status) == statusFilter {
-				filtered = append(filtered, t)
-			}
-		}
-		tests = filtered
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"run_id": run.RunID,
 		"tests":  tests,
 		"count":  len(tests),
+		"total":  total,
+		"offset": offset,
 	})
 }
 
+// getRunSlowest handles GET /api/runs/:run_id/slowest
+func (s *Server) getRunSlowest(c *gin.Context) {
+	run, ok := s.getRunByIDParam(c)
+	if !ok {
+		return
+	}
+
+	limit, _, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
+	}
+
+	slowest, _, err := s.repo.ListTestResults(c.Request.Context(), run.RunID, db.TestResultFilter{
+		Statuses: []string{TestStatusPassed, TestStatusFailed, TestStatusCrashed},
+		Sort:     "duration",
+		Desc:     true,
+		Limit:    limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	violations, err := s.repo.GetBudgetViolations(c.Request.Context(), run.RunID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if slowest == nil {
+		slowest = []models.TestResult{}
+	}
+	if violations == nil {
+		violations = []models.TestResult{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"run_id":            run.RunID,
+		"slowest":           slowest,
+		"budget_violations": violations,
+	})
+}
+
+// getRunScheduling handles GET /api/runs/:run_id/scheduling
+func (s *Server) getRunScheduling(c *gin.Context) {
+	run, ok := s.getRunByIDParam(c)
+	if !ok {
+		return
+	}
+
+	sched, err := s.repo.GetRunScheduling(c.Request.Context(), run)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sched)
+}
+
 // getRunTestsTree handles GET /api/runs/:run_id/tests/tree
 func (s *Server) getRunTestsTree(c *gin.Context) {
 	run, ok := s.getRunByIDParam(c)
@@ -153,7 +249,7 @@ func (s *Server) getRunTestsTree(c *gin.Context) {
 		return
 	}
 
-	tests, err := s.repo.GetTestResultsByRunID(run.RunID)
+	tests, err := s.repo.GetTestResultsByRunID(c.Request.Context(), run.RunID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -229,22 +325,32 @@ func (s *Server) getRunTestsTree(c *gin.Context) {
 
 // createRun handles POST /api/runs
 func (s *Server) createRun(c *gin.Context) {
+	ctx := c.Request.Context()
 	var req struct {
-		SuiteID              int64    `json:"suite_id"`
-		SuiteName            string   `json:"suite_name"`
-		DisplayName          string   `json:"display_name"`
-		CLIVersion           string   `json:"cli_version"`
-		SDKPythonVersion     string   `json:"sdk_python_version"`
-		SDKTypescriptVersion string   `json:"sdk_typescript_version"`
-		DockerImage          string   `json:"docker_image"`
-		TotalTests           int      `json:"total_tests"`
-		Mode                 string   `json:"mode"`
+		RunID                string `json:"run_id"` // Chosen by the API when it launched the CLI
+		SuiteID              int64  `json:"suite_id"`
+		SuiteName            string `json:"suite_name"`
+		DisplayName          string `json:"display_name"`
+		CLIVersion           string `json:"cli_version"`
+		SDKPythonVersion     string `json:"sdk_python_version"`
+		SDKTypescriptVersion string `json:"sdk_typescript_version"`
+		DockerImage          string `json:"docker_image"`
+		TotalTests           int    `json:"total_tests"`
+		Mode                 string `json:"mode"`
+		ParentRunID          string `json:"parent_run_id"`
+		PipelineRunID        string `json:"pipeline_run_id"`
+		RunnerVersion        string `json:"runner_version"`
+		Profile              string `json:"profile"`
+		RunnerSHA256         string `json:"runner_sha256"`
+		Workers              int    `json:"workers"`
 		Tests                []struct {
 			TestID   string   `json:"test_id"`
 			UseCase  string   `json:"use_case"`
 			TestCase string   `json:"test_case"`
 			Name     string   `json:"name"`
 			Tags     []string `json:"tags"`
+			Covers   []string `json:"covers"`
+			Owners   []string `json:"owners"`
 		} `json:"tests"`
 	}
 
@@ -253,8 +359,44 @@ func (s *Server) createRun(c *gin.Context) {
 		return
 	}
 
-	// Generate run ID
-	runID := generateUUID()
+	if req.ParentRunID != "" {
+		parent, err := s.repo.GetRunByID(ctx, req.ParentRunID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if parent == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent run not found: " + req.ParentRunID})
+			return
+		}
+	}
+	if req.PipelineRunID != "" {
+		pipeline, err := s.repo.GetPipelineRunByID(ctx, req.PipelineRunID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if pipeline == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Pipeline run not found: " + req.PipelineRunID})
+			return
+		}
+	}
+
+	// Generate run ID unless one was reserved for this run. A run the API
+	// queued is taken over by the CLI it started.
+	runID := req.RunID
+	claim := false
+	if runID == "" {
+		runID = generateUUID()
+	} else if existing, err := s.repo.GetRunByID(ctx, runID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	} else if existing != nil && existing.Status != models.RunStatusQueued {
+		c.JSON(http.StatusConflict, gin.H{"error": "Run already exists: " + runID})
+		return
+	} else {
+		claim = existing != nil
+	}
 
 	// Create run
 	run := &models.Run{
@@ -262,7 +404,7 @@ func (s *Server) createRun(c *gin.Context) {
 		SuiteID:              sql.NullInt64{Int64: req.SuiteID, Valid: req.SuiteID > 0},
 		SuiteName:            sql.NullString{String: req.SuiteName, Valid: req.SuiteName != ""},
 		DisplayName:          sql.NullString{String: req.DisplayName, Valid: req.DisplayName != ""},
-		StartedAt:            time.Now(),
+		StartedAt:            time.Now().UTC(),
 		Status:               models.RunStatusRunning,
 		CLIVersion:           sql.NullString{String: req.CLIVersion, Valid: req.CLIVersion != ""},
 		SDKPythonVersion:     sql.NullString{String: req.SDKPythonVersion, Valid: req.SDKPythonVersion != ""},
@@ -271,16 +413,44 @@ func (s *Server) createRun(c *gin.Context) {
 		TotalTests:           req.TotalTests,
 		PendingCount:         req.TotalTests,
 		Mode:                 req.Mode,
-	}
-
-	if err := s.repo.CreateRun(run); err != nil {
+		ParentRunID:          sql.NullString{String: req.ParentRunID, Valid: req.ParentRunID != ""},
+		PipelineRunID:        sql.NullString{String: req.PipelineRunID, Valid: req.PipelineRunID != ""},
+		RunnerVersion:        sql.NullString{String: req.RunnerVersion, Valid: req.RunnerVersion != ""},
+		RunnerSHA256:         sql.NullString{String: req.RunnerSHA256, Valid: req.RunnerSHA256 != ""},
+		Workers:              sql.NullInt64{Int64: int64(req.Workers), Valid: req.Workers > 0},
+		Profile:              sql.NullString{String: req.Profile, Valid: req.Profile != ""},
+	}
+
+	if claim {
+		claimed, err := s.repo.ClaimQueuedRun(ctx, run)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create run: " + err.Error()})
+			return
+		}
+		if !claimed {
+			c.JSON(http.StatusConflict, gin.H{"error": "Run is no longer queued: " + runID})
+			return
+		}
+	} else if err := s.repo.CreateRun(ctx, run); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create run: " + err.Error()})
 		return
 	}
 
-	// Create test result records if provided
+	// Create test result records if provided. All tests are queued for the
+	// run's workers as it starts.
+	queuedAt := run.StartedAt
 	for _, t := range req.Tests {
 		tagsJSON, _ := json.Marshal(t.Tags)
+		var covers sql.NullString
+		if len(t.Covers) > 0 {
+			coversJSON, _ := json.Marshal(t.Covers)
+			covers = sql.NullString{String: string(coversJSON), Valid: true}
+		}
+		var owners sql.NullString
+		if len(t.Owners) > 0 {
+			ownersJSON, _ := json.Marshal(t.Owners)
+			owners = sql.NullString{String: string(ownersJSON), Valid: true}
+		}
 		tr := &models.TestResult{
 			RunID:    runID,
 			TestID:   t.TestID,
@@ -288,9 +458,12 @@ func (s *Server) createRun(c *gin.Context) {
 			TestCase: t.TestCase,
 			Name:     sql.NullString{String: t.Name, Valid: t.Name != ""},
 			Tags:     sql.NullString{String: string(tagsJSON), Valid: true},
+			Covers:   covers,
+			Owners:   owners,
 			Status:   models.TestStatusPending,
+			QueuedAt: &queuedAt,
 		}
-		if err := s.repo.CreateTestResult(tr); err != nil {
+		if err := s.repo.CreateTestResult(ctx, tr); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create test result: " + err.Error()})
 			return
 		}
@@ -303,24 +476,47 @@ func (s *Server) createRun(c *gin.Context) {
 		"run_id":      runID,
 		"status":      run.Status,
 		"total_tests": run.TotalTests,
-		"started_at":  run.StartedAt.Format(time.RFC3339),
+		"started_at":  run.StartedAt.UTC().Format(time.RFC3339),
 	})
 }
 
 // completeRun handles POST /api/runs/:run_id/complete
 func (s *Server) completeRun(c *gin.Context) {
+	ctx := c.Request.Context()
 	run, ok := s.getRunByIDParam(c)
 	if !ok {
 		return
 	}
 
-	if err := s.repo.CompleteRun(run.RunID); err != nil {
+	// The body is optional; the CLI sends the outcome of smoke runs
+	var req struct {
+		SmokeStatus   string `json:"smoke_status"`
+		SmokeBudgetMS int64  `json:"smoke_budget_ms"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch req.SmokeStatus {
+	case "", orchestrator.SmokePassed, orchestrator.SmokeFailed, orchestrator.SmokeOverBudget:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid smoke_status: " + req.SmokeStatus})
+		return
+	}
+
+	if err := s.repo.CompleteRun(ctx, run.RunID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete run: " + err.Error()})
 		return
 	}
+	if req.SmokeStatus != "" {
+		if err := s.repo.SetRunSmokeStatus(ctx, run.RunID, req.SmokeStatus, req.SmokeBudgetMS); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record smoke status: " + err.Error()})
+			return
+		}
+	}
 
 	// Get updated run
-	run, _ = s.repo.GetRunByID(run.RunID)
+	run, _ = s.repo.GetRunByID(ctx, run.RunID)
 
 	// Emit SSE run_completed event
 	durationMS := int64(0)
@@ -329,13 +525,19 @@ func (s *Server) completeRun(c *gin.Context) {
 	}
 	s.sseHub.EmitRunCompleted(run.RunID, run.Passed, run.Failed, run.Skipped, durationMS)
 
+	// Open, update or close issues in the suite's tracker (issues in config.yaml)
+	go s.syncIssues(run.RunID)
+	// Email the run's digest (email in config.yaml)
+	go s.sendDigest(run.RunID)
+
 	c.JSON(http.StatusOK, gin.H{
-		"success":     true,
-		"run_id":      run.RunID,
-		"status":      run.Status,
-		"passed":      run.Passed,
-		"failed":      run.Failed,
-		"duration_ms": nullInt64Value(run.DurationMS),
+		"success":      true,
+		"run_id":       run.RunID,
+		"status":       run.Status,
+		"passed":       run.Passed,
+		"failed":       run.Failed,
+		"duration_ms":  nullInt64Value(run.DurationMS),
+		"smoke_status": nullStringValue(run.SmokeStatus),
 	})
 }
 
@@ -352,7 +554,7 @@ func (s *Server) rerunTests(c *gin.Context) {
 		return
 	}
 
-	suite, err := s.repo.GetSuiteByID(run.SuiteID.Int64)
+	suite, err := s.repo.GetSuiteByID(c.Request.Context(), run.SuiteID.Int64)
 	if err != nil || suite == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Suite not found"})
 		return
@@ -365,7 +567,7 @@ func (s *Server) rerunTests(c *gin.Context) {
 	}
 
 	// Get tests from original run to determine scope
-	tests, err := s.repo.GetTestResultsByRunID(run.RunID)
+	tests, err := s.repo.GetTestResultsByRunID(c.Request.Context(), run.RunID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -397,83 +599,50 @@ func (s *Server) rerunTests(c *gin.Context) {
 		scopeType = "all"
 	}
 
-	// Find tsuite binary (same directory as running server)
-	execPath, err := os.Executable()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cannot find executable path"})
-		return
-	}
-	execPath, _ = filepath.EvalSymlinks(execPath)
-
-	// Build CLI command
-	apiURL := fmt.Sprintf("http://%s", c.Request.Host)
-	cmd := []string{
-		execPath,
-		"run",
-		"--suite-path", suite.FolderPath,
-		"--api-url", apiURL,
+	opts := orchestrator.Options{
+		APIURL:      s.localURL(),
+		ParentRunID: run.RunID,
 	}
 
-	// Add scope flag
+	// Add scope filter
 	switch scopeType {
 	case "tc":
-		cmd = append(cmd, "--tc", scopeValue)
+		opts.Filter.TC = []string{scopeValue}
 	case "uc":
-		cmd = append(cmd, "--uc", scopeValue)
+		opts.Filter.UC = []string{scopeValue}
 	}
 
-	// Create log file for output
-	logFile, err := os.CreateTemp("", "tsuite_rerun_*.log")
+	// Runs beyond the suite's concurrent run limit wait in the queue
+	job, err := s.enqueueRun(c.Request.Context(), suite, "tsuite_rerun_*.log", opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create log file"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	logPath := logFile.Name()
-
-	// Start subprocess
-	process := newExecCommand(cmd[0], cmd[1:]...)
-	process.Stdout = logFile
-	process.Stderr = logFile
-	process.Dir = suite.FolderPath
-
-	if err := process.Start(); err != nil {
-		logFile.Close()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start CLI: " + err.Error()})
+	response, ok := s.launchResponse(job)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, response)
 		return
 	}
 
-	// Close log file - subprocess has inherited the FD
-	logFile.Close()
-
-	// Don't wait for process - let it run in background
-	go func() {
-		process.Wait()
-	}()
-
 	// Build description
-	var description string
 	switch scopeType {
 	case "tc":
-		description = "Rerunning test case: " + scopeValue
+		response["description"] = "Rerunning test case: " + scopeValue
 	case "uc":
-		description = "Rerunning use case: " + scopeValue
+		response["description"] = "Rerunning use case: " + scopeValue
 	default:
-		description = "Rerunning all tests in: " + suite.SuiteName
+		response["description"] = "Rerunning all tests in: " + suite.SuiteName
 	}
+	response["mode"] = suite.Mode
+	response["original_run_id"] = run.RunID
 
-	c.JSON(http.StatusAccepted, gin.H{
-		"started":         true,
-		"pid":             process.Process.Pid,
-		"description":     description,
-		"mode":            suite.Mode,
-		"log_file":        logPath,
-		"original_run_id": run.RunID,
-	})
+	c.JSON(http.StatusAccepted, response)
 }
 
 // updateRunStatus handles PATCH /api/runs/:run_id
 // Used by CLI to mark run as cancelled after terminating workers
 func (s *Server) updateRunStatus(c *gin.Context) {
+	ctx := c.Request.Context()
 	runID := c.Param("run_id")
 
 	var req struct {
@@ -484,7 +653,7 @@ func (s *Server) updateRunStatus(c *gin.Context) {
 		return
 	}
 
-	run, err := s.repo.GetRunByID(runID)
+	run, err := s.repo.GetRunByID(ctx, runID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -500,13 +669,13 @@ func (s *Server) updateRunStatus(c *gin.Context) {
 		return
 	}
 
-	if err := s.repo.MarkRunCancelled(runID); err != nil {
+	if err := s.repo.MarkRunCancelled(ctx, runID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel run: " + err.Error()})
 		return
 	}
 
 	// Get updated run
-	run, _ = s.repo.GetRunByID(runID)
+	run, _ = s.repo.GetRunByID(ctx, runID)
 
 	// Emit SSE run_cancelled event
 	durationMS := int64(0)
@@ -530,7 +699,7 @@ func (s *Server) updateRunStatus(c *gin.Context) {
 func (s *Server) cancelRun(c *gin.Context) {
 	runID := c.Param("run_id")
 
-	run, err := s.repo.GetRunByID(runID)
+	run, err := s.repo.GetRunByID(c.Request.Context(), runID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -540,12 +709,23 @@ func (s *Server) cancelRun(c *gin.Context) {
 		return
 	}
 
-	if run.Status != models.RunStatusPending && run.Status != models.RunStatusRunning {
+	// A run still waiting in the queue is cancelled right away
+	if run.Status == models.RunStatusQueued && s.cancelQueuedRun(runID) {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"run_id":  runID,
+			"status":  models.RunStatusCancelled,
+		})
+		return
+	}
+
+	// Queued runs whose CLI has started see the flag once they begin
+	if run.Status != models.RunStatusQueued && run.Status != models.RunStatusPending && run.Status != models.RunStatusRunning {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot cancel run with status: " + string(run.Status)})
 		return
 	}
 
-	if err := s.repo.SetCancelRequested(runID); err != nil {
+	if err := s.repo.SetCancelRequested(c.Request.Context(), runID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -564,7 +744,7 @@ func (s *Server) cancelRun(c *gin.Context) {
 func (s *Server) deleteRun(c *gin.Context) {
 	runID := c.Param("run_id")
 
-	run, err := s.repo.GetRunByID(runID)
+	run, err := s.repo.GetRunByID(c.Request.Context(), runID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -574,7 +754,7 @@ func (s *Server) deleteRun(c *gin.Context) {
 		return
 	}
 
-	if err := s.repo.DeleteRun(runID); err != nil {
+	if err := s.repo.DeleteRun(c.Request.Context(), runID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete run: " + err.Error()})
 		return
 	}
@@ -1,24 +1,31 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gopkg.in/yaml.v3"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/scaffold"
 )
 
 // ==================== Suites ====================
 
 // listSuites handles GET /api/suites
 func (s *Server) listSuites(c *gin.Context) {
-	suites, err := s.repo.GetAllSuites()
+	suites, err := s.repo.GetAllSuites(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -104,7 +111,7 @@ func (s *Server) createSuite(c *gin.Context) {
 	}
 
 	// Check if suite already exists
-	existing, err := s.repo.GetSuiteByPath(folderPath)
+	existing, err := s.repo.GetSuiteByPath(c.Request.Context(), folderPath)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -160,7 +167,7 @@ func (s *Server) createSuite(c *gin.Context) {
 	configJSON, _ := json.Marshal(config)
 
 	// Create suite
-	now := time.Now()
+	now := time.Now().UTC()
 	suite := &models.Suite{
 		FolderPath:   folderPath,
 		SuiteName:    suiteName,
@@ -170,7 +177,7 @@ func (s *Server) createSuite(c *gin.Context) {
 		LastSyncedAt: &now,
 	}
 
-	if err := s.repo.CreateSuite(suite); err != nil {
+	if err := s.repo.CreateSuite(c.Request.Context(), suite); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -210,7 +217,7 @@ func (s *Server) updateSuite(c *gin.Context) {
 		suite.Mode = models.SuiteMode(req.Mode)
 	}
 
-	if err := s.repo.UpdateSuite(suite); err != nil {
+	if err := s.repo.UpdateSuite(c.Request.Context(), suite); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -225,7 +232,7 @@ func (s *Server) deleteSuite(c *gin.Context) {
 		return
 	}
 
-	if err := s.repo.DeleteSuite(suite.ID); err != nil {
+	if err := s.repo.DeleteSuite(c.Request.Context(), suite.ID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -285,14 +292,14 @@ func (s *Server) syncSuite(c *gin.Context) {
 	configJSON, _ := json.Marshal(config)
 
 	// Update suite in database
-	now := time.Now()
+	now := time.Now().UTC()
 	suite.SuiteName = suiteName
 	suite.Mode = models.SuiteMode(mode)
 	suite.ConfigJSON = sql.NullString{String: string(configJSON), Valid: true}
 	suite.TestCount = len(tests)
 	suite.LastSyncedAt = &now
 
-	if err := s.repo.UpdateSuite(suite); err != nil {
+	if err := s.repo.UpdateSuite(c.Request.Context(), suite); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -347,3 +354,541 @@ func (s *Server) getSuiteTests(c *gin.Context) {
 		"count":    len(tests),
 	})
 }
+
+// ==================== Test Case Management ====================
+
+// validTestDirName reports whether name is a single path segment starting
+// with prefix ("uc" or "tc")
+func validTestDirName(name, prefix string) bool {
+	return strings.HasPrefix(name, prefix) && name == filepath.Base(name)
+}
+
+// refreshSuiteTestCount updates the stored test count after tests are added or removed
+func (s *Server) refreshSuiteTestCount(ctx context.Context, suite *models.Suite) {
+	tests, _, err := DiscoverTests(suite.FolderPath)
+	if err != nil {
+		return
+	}
+	suite.TestCount = len(tests)
+	s.repo.UpdateSuite(ctx, suite)
+}
+
+// createSuiteTest handles POST /api/suites/:id/tests
+// The test.yaml is taken from raw_yaml, scaffolded from agent directories on
+// the API host, or generated with a placeholder step.
+func (s *Server) createSuiteTest(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		UC            string   `json:"uc"`
+		TC            string   `json:"tc"`
+		Name          string   `json:"name"`
+		RawYAML       string   `json:"raw_yaml"`
+		Agents        []string `json:"agents"`         // Agent directories to scaffold from
+		Filter        string   `json:"filter"`         // Glob for standalone scripts in a single agents directory
+		ArtifactLevel string   `json:"artifact_level"` // "tc" (default) or "uc"
+		Symlink       bool     `json:"symlink"`
+		Force         bool     `json:"force"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if !validTestDirName(req.UC, "uc") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "uc should start with 'uc' (e.g., uc01_tags)"})
+		return
+	}
+	if !validTestDirName(req.TC, "tc") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tc should start with 'tc' (e.g., tc01_test)"})
+		return
+	}
+	if req.RawYAML != "" && len(req.Agents) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide either 'raw_yaml' or 'agents', not both"})
+		return
+	}
+	if req.ArtifactLevel == "" {
+		req.ArtifactLevel = "tc"
+	}
+	if req.ArtifactLevel != "tc" && req.ArtifactLevel != "uc" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "artifact_level must be 'tc' or 'uc'"})
+		return
+	}
+
+	testID := req.UC + "/" + req.TC
+	tcDir := filepath.Join(suite.FolderPath, "suites", req.UC, req.TC)
+	testPath := filepath.Join(tcDir, "test.yaml")
+	if _, err := os.Stat(tcDir); err == nil && !req.Force {
+		c.JSON(http.StatusConflict, gin.H{"error": "Test already exists: " + testID})
+		return
+	}
+
+	previousYAML, _ := os.ReadFile(testPath) // Set when overwriting with force
+
+	scaffoldConfig := &scaffold.Config{
+		SuitePath:     suite.FolderPath,
+		UCName:        req.UC,
+		TCName:        req.TC,
+		TestName:      req.Name,
+		ArtifactLevel: req.ArtifactLevel,
+		Force:         req.Force,
+		UseSymlinks:   req.Symlink,
+	}
+
+	var output bytes.Buffer
+	if len(req.Agents) > 0 {
+		if req.Filter != "" {
+			if len(req.Agents) != 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "'filter' requires exactly one agents directory"})
+				return
+			}
+			scripts, err := scaffold.DiscoverScriptsByFilter(req.Agents[0], req.Filter)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if len(scripts) == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "No files matching '" + req.Filter + "' found in " + req.Agents[0]})
+				return
+			}
+			scaffoldConfig.Agents = scripts
+			scaffoldConfig.FlatScriptDir = req.Agents[0]
+			scaffoldConfig.Filter = req.Filter
+		} else {
+			if err := scaffold.ValidateNoParentDirs(req.Agents); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			for _, agentPath := range req.Agents {
+				agent, err := scaffold.ValidateAgentDir(agentPath)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				scaffoldConfig.Agents = append(scaffoldConfig.Agents, *agent)
+			}
+		}
+
+		scaffoldConfig.Output = &output
+		if err := scaffold.Run(scaffoldConfig); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		content := req.RawYAML
+		if content == "" {
+			content = scaffold.GenerateBlankTestYAML(scaffoldConfig)
+		}
+
+		// Validate it's valid YAML first
+		var test map[string]any
+		if err := yaml.Unmarshal([]byte(content), &test); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid YAML: " + err.Error()})
+			return
+		}
+
+		if err := os.MkdirAll(tcDir, 0755); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create test: " + err.Error()})
+			return
+		}
+		if err := os.WriteFile(testPath, []byte(content), 0644); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write test: " + err.Error()})
+			return
+		}
+	}
+
+	rawYAML, err := os.ReadFile(testPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read test: " + err.Error()})
+		return
+	}
+
+	action := models.AuditActionCreate
+	if previousYAML != nil {
+		action = models.AuditActionUpdate
+	}
+	s.recordAudit(c, suite, testYAMLRelPath(testID), action, previousYAML, rawYAML)
+	s.refreshSuiteTestCount(c.Request.Context(), suite)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"suite_id": suite.ID,
+		"test_id":  testID,
+		"path":     testPath,
+		"raw_yaml": string(rawYAML),
+		"output":   output.String(),
+	})
+}
+
+// postSuiteTestPath handles POST /api/suites/:id/tests/*path
+// Gin allows one catch-all per path, so this routes to cloneSuiteTest
+// (.../tests/clone) and runSuiteTest (.../tests/{uc}/{tc}/run).
+func (s *Server) postSuiteTestPath(c *gin.Context) {
+	path := stripLeadingSlash(c.Param("path"))
+	testID, isRun := strings.CutSuffix(path, "/run")
+	ucName, tcName, _ := strings.Cut(testID, "/")
+	if path != "clone" && (!isRun || !validTestDirName(ucName, "uc") || !validTestDirName(tcName, "tc")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found: POST /api/suites/:id/tests/" + path})
+		return
+	}
+
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+	if path == "clone" {
+		s.cloneSuiteTest(c, suite)
+		return
+	}
+	s.runSuiteTest(c, suite, testID)
+}
+
+// cloneSuiteTest handles POST /api/suites/:id/tests/clone
+func (s *Server) cloneSuiteTest(c *gin.Context, suite *models.Suite) {
+
+	var req struct {
+		From      string `json:"from"`
+		To        string `json:"to"`
+		Name      string `json:"name"`
+		Artifacts string `json:"artifacts"` // copy (default), link or none
+		Force     bool   `json:"force"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if !req.Force {
+		if _, err := os.Stat(filepath.Join(suite.FolderPath, "suites", filepath.FromSlash(req.To))); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Test already exists: " + req.To})
+			return
+		}
+	}
+
+	testPath := filepath.Join(suite.FolderPath, "suites", filepath.FromSlash(req.To), "test.yaml")
+	previousYAML, _ := os.ReadFile(testPath) // Set when overwriting with force
+
+	var output bytes.Buffer
+	err := scaffold.Clone(&scaffold.CloneConfig{
+		SuitePath: suite.FolderPath,
+		From:      req.From,
+		To:        req.To,
+		TestName:  req.Name,
+		Artifacts: req.Artifacts,
+		Force:     req.Force,
+		Output:    &output,
+	})
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.HasPrefix(err.Error(), "test not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	rawYAML, err := os.ReadFile(testPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read test: " + err.Error()})
+		return
+	}
+
+	action := models.AuditActionCreate
+	if previousYAML != nil {
+		action = models.AuditActionUpdate
+	}
+	s.recordAudit(c, suite, testYAMLRelPath(req.To), action, previousYAML, rawYAML)
+	s.refreshSuiteTestCount(c.Request.Context(), suite)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"suite_id": suite.ID,
+		"test_id":  req.To,
+		"path":     testPath,
+		"raw_yaml": string(rawYAML),
+		"output":   output.String(),
+	})
+}
+
+// deleteSuiteTest handles DELETE /api/suites/:id/tests/*test_id
+// The TC directory is removed with its artifacts. UC-level artifacts that no
+// remaining test in the use case references are removed too, and so is the
+// use case directory once it is empty.
+func (s *Server) deleteSuiteTest(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+
+	testID := stripLeadingSlash(c.Param("test_id"))
+	ucName, tcName, found := strings.Cut(testID, "/")
+	if !found || !validTestDirName(ucName, "") || !validTestDirName(tcName, "") || ucName == ".." || tcName == ".." {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid test ID: " + testID})
+		return
+	}
+
+	ucDir := filepath.Join(suite.FolderPath, "suites", ucName)
+	tcDir := filepath.Join(ucDir, tcName)
+	oldYAML, err := os.ReadFile(filepath.Join(tcDir, "test.yaml"))
+	if os.IsNotExist(err) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test not found: " + testID})
+		return
+	}
+
+	if err := os.RemoveAll(tcDir); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete test: " + err.Error()})
+		return
+	}
+	s.recordAudit(c, suite, testYAMLRelPath(testID), models.AuditActionDelete, oldYAML, nil)
+
+	removedArtifacts := pruneUseCaseArtifacts(ucDir)
+
+	// Remove the use case once nothing is left in it (fails harmlessly otherwise)
+	ucRemoved := os.Remove(ucDir) == nil
+
+	s.refreshSuiteTestCount(c.Request.Context(), suite)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"suite_id":          suite.ID,
+		"test_id":           testID,
+		"removed_artifacts": removedArtifacts,
+		"use_case_removed":  ucRemoved,
+	})
+}
+
+// pruneUseCaseArtifacts removes entries of ucDir/artifacts that no test.yaml
+// left in the use case refers to (as /uc-artifacts/<name>), and the artifacts
+// directory itself once it is empty. It returns the removed entries.
+func pruneUseCaseArtifacts(ucDir string) []string {
+	artifactsDir := filepath.Join(ucDir, "artifacts")
+	entries, err := os.ReadDir(artifactsDir)
+	if err != nil {
+		return []string{}
+	}
+
+	var references bytes.Buffer
+	testFiles, _ := filepath.Glob(filepath.Join(ucDir, "*", "test.yaml"))
+	for _, path := range testFiles {
+		if data, err := os.ReadFile(path); err == nil {
+			references.Write(data)
+		}
+	}
+
+	removed := []string{}
+	for _, entry := range entries {
+		if bytes.Contains(references.Bytes(), []byte("uc-artifacts/"+entry.Name())) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(artifactsDir, entry.Name())); err == nil {
+			removed = append(removed, filepath.Join("artifacts", entry.Name()))
+		}
+	}
+	os.Remove(artifactsDir) // only succeeds when empty
+	return removed
+}
+
+// getTestHistory handles GET /api/suites/:id/test-history/*test_id
+// Results recorded under former IDs of a moved test (see tsuite mv) are
+// included, newest first.
+func (s *Server) getTestHistory(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+
+	testID := stripLeadingSlash(c.Param("test_id"))
+	if testID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Test ID is required"})
+		return
+	}
+
+	limit, _, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
+	}
+
+	results, err := s.repo.GetTestHistory(c.Request.Context(), suite.ID, testID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	aliases, err := s.repo.GetTestAliases(c.Request.Context(), suite.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	formerIDs := []string{}
+	for _, alias := range aliases {
+		if alias.NewTestID == testID {
+			formerIDs = append(formerIDs, alias.OldTestID)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suite_id":   suite.ID,
+		"test_id":    testID,
+		"former_ids": formerIDs,
+		"results":    results,
+		"count":      len(results),
+	})
+}
+
+// envDifference is a fingerprint entry that differs between two results
+type envDifference struct {
+	Key    string `json:"key"`
+	Passed string `json:"passed"`
+	Failed string `json:"failed"`
+}
+
+// getTestEnvDiff handles GET /api/suites/:id/test-env-diff/*test_id
+// Compares the environment fingerprints of the test's latest passed and
+// latest failed (or crashed) results, to see what changed between them.
+func (s *Server) getTestEnvDiff(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+
+	testID := stripLeadingSlash(c.Param("test_id"))
+	if testID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Test ID is required"})
+		return
+	}
+
+	passed, err := s.repo.GetLatestTestResult(c.Request.Context(), suite.ID, testID, models.TestStatusPassed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	failed, err := s.repo.GetLatestTestResult(c.Request.Context(), suite.ID, testID, models.TestStatusFailed, models.TestStatusCrashed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if passed == nil || failed == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test needs both a passed and a failed result to compare: " + testID})
+		return
+	}
+
+	passedFP, err := s.envFingerprint(c.Request.Context(), passed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	failedFP, err := s.envFingerprint(c.Request.Context(), failed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	differences := []envDifference{}
+	for _, key := range fingerprintKeys(passedFP, failedFP) {
+		if passedFP[key] != failedFP[key] {
+			differences = append(differences, envDifference{Key: key, Passed: passedFP[key], Failed: failedFP[key]})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suite_id": suite.ID,
+		"test_id":  testID,
+		"passed": gin.H{
+			"run_id":      passed.RunID,
+			"started_at":  passed.StartedAt,
+			"fingerprint": passedFP,
+		},
+		"failed": gin.H{
+			"run_id":      failed.RunID,
+			"status":      failed.Status,
+			"started_at":  failed.StartedAt,
+			"fingerprint": failedFP,
+		},
+		"differences": differences,
+		"identical":   len(differences) == 0,
+	})
+}
+
+// envFingerprintKeys orders the fingerprint; captured env vars (env.NAME)
+// follow, sorted
+var envFingerprintKeys = []string{
+	"image", "image_digest", "sdk_python_version", "sdk_typescript_version", "node_version",
+	"profile", "mode", "cli_version", "runner_version", "runner_sha256", "workers",
+	"os", "arch", "go_version", "hostname",
+}
+
+// envFingerprint describes where a test result ran: its run's versions and
+// options, and the environment the runner recorded with the result. Empty
+// values are left out.
+func (s *Server) envFingerprint(ctx context.Context, tr *models.TestResult) (map[string]string, error) {
+	run, err := s.repo.GetRunByID(ctx, tr.RunID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		run = &models.Run{}
+	}
+	var env runner.Environment
+	if tr.Environment.Valid && tr.Environment.String != "" {
+		_ = json.Unmarshal([]byte(tr.Environment.String), &env)
+	}
+
+	fp := map[string]string{
+		"image":                  firstNonEmpty(env.Image, run.DockerImage.String),
+		"image_digest":           env.ImageDigest,
+		"sdk_python_version":     run.SDKPythonVersion.String,
+		"sdk_typescript_version": run.SDKTypescriptVersion.String,
+		"node_version":           env.NodeVersion,
+		"profile":                run.Profile.String,
+		"mode":                   firstNonEmpty(env.Mode, run.Mode),
+		"cli_version":            firstNonEmpty(run.CLIVersion.String, env.TsuiteVersion),
+		"runner_version":         firstNonEmpty(env.RunnerVersion, run.RunnerVersion.String),
+		"runner_sha256":          run.RunnerSHA256.String,
+		"os":                     env.OS,
+		"arch":                   env.Arch,
+		"go_version":             env.GoVersion,
+		"hostname":               env.Hostname,
+	}
+	if run.Workers.Valid {
+		fp["workers"] = strconv.FormatInt(run.Workers.Int64, 10)
+	}
+	for name, value := range env.Env {
+		fp["env."+name] = value
+	}
+	for key, value := range fp {
+		if value == "" {
+			delete(fp, key)
+		}
+	}
+	return fp, nil
+}
+
+// fingerprintKeys returns the keys of both fingerprints in display order
+func fingerprintKeys(a, b map[string]string) []string {
+	keys := slices.Clone(envFingerprintKeys)
+	var envKeys []string
+	for _, fp := range []map[string]string{a, b} {
+		for key := range fp {
+			if strings.HasPrefix(key, "env.") && !slices.Contains(envKeys, key) {
+				envKeys = append(envKeys, key)
+			}
+		}
+	}
+	slices.Sort(envKeys)
+	return append(keys, envKeys...)
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
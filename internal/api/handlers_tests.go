@@ -3,9 +3,13 @@ package api
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -26,7 +30,7 @@ func (s *Server) getTestDetailByNumericID(c *gin.Context) {
 		return
 	}
 
-	test, err := s.repo.GetTestResultByID(testID)
+	test, err := s.repo.GetTestResultByID(c.Request.Context(), testID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -55,7 +59,7 @@ func (s *Server) getTestDetail(c *gin.Context) {
 	}
 
 	// Look up by path-based test_id (e.g., "build/tc05_verify_artifacts")
-	test, err := s.repo.GetTestResultByTestIDAndRunID(testIDStr, runID)
+	test, err := s.repo.GetTestResultByTestIDAndRunID(c.Request.Context(), testIDStr, runID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -70,64 +74,129 @@ func (s *Server) getTestDetail(c *gin.Context) {
 
 // sendTestDetailResponse sends the test detail JSON response
 func (s *Server) sendTestDetailResponse(c *gin.Context, test *models.TestResult) {
+	ctx := c.Request.Context()
 	// Get steps
-	steps, err := s.repo.GetStepResultsByTestID(test.ID)
+	steps, err := s.repo.GetStepResultsByTestID(ctx, test.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	for i := range steps {
+		steps[i].ArtifactBaseURL = fmt.Sprintf("/api/runs/%s/artifacts/%s/", test.RunID, test.TestID)
+	}
 
 	// Get assertions
-	assertions, err := s.repo.GetAssertionsByTestID(test.ID)
+	assertions, err := s.repo.GetAssertionsByTestID(ctx, test.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Get captured values
-	captured, err := s.repo.GetCapturedValuesByTestID(test.ID)
+	captured, err := s.repo.GetCapturedValuesByTestID(ctx, test.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get comments on the test
+	comments, err := s.repo.ListComments(ctx, test.RunID, &test.TestID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	var environment any
+	if test.Environment.Valid && test.Environment.String != "" {
+		_ = json.Unmarshal([]byte(test.Environment.String), &environment)
+	}
+
+	var container any
+	if test.Container.Valid && test.Container.String != "" {
+		_ = json.Unmarshal([]byte(test.Container.String), &container)
+	}
+
+	var owners []string
+	if test.Owners.Valid && test.Owners.String != "" {
+		_ = json.Unmarshal([]byte(test.Owners.String), &owners)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"id":            test.ID,
-		"run_id":        test.RunID,
-		"test_id":       test.TestID,
-		"use_case":      test.UseCase,
-		"test_case":     test.TestCase,
-		"name":          nullStringValue(test.Name),
-		"status":        test.Status,
-		"started_at":    test.StartedAt,
-		"finished_at":   test.FinishedAt,
-		"duration_ms":   nullInt64Value(test.DurationMS),
-		"error_message": nullStringValue(test.ErrorMessage),
-		"error_step":    nullInt64Value(test.ErrorStep),
-		"skip_reason":   nullStringValue(test.SkipReason),
-		"steps_passed":  test.StepsPassed,
-		"steps_failed":  test.StepsFailed,
-		"steps":         steps,
-		"assertions":    assertions,
-		"captured":      captured,
+		"id":             test.ID,
+		"run_id":         test.RunID,
+		"test_id":        test.TestID,
+		"use_case":       test.UseCase,
+		"test_case":      test.TestCase,
+		"name":           nullStringValue(test.Name),
+		"status":         test.Status,
+		"started_at":     test.StartedAt,
+		"finished_at":    test.FinishedAt,
+		"duration_ms":    nullInt64Value(test.DurationMS),
+		"error_message":  nullStringValue(test.ErrorMessage),
+		"error_step":     nullInt64Value(test.ErrorStep),
+		"skip_reason":    nullStringValue(test.SkipReason),
+		"steps_passed":   test.StepsPassed,
+		"steps_failed":   test.StepsFailed,
+		"steps":          steps,
+		"assertions":     assertions,
+		"captured":       captured,
+		"environment":    environment,
+		"container":      container,
+		"workspace_path": nullStringValue(test.WorkspacePath),
+		"owners":         owners,
+		"comments":       comments,
 	})
 }
 
+// getStepArtifact handles GET /api/runs/:run_id/artifacts/*path
+// The path is {uc}/{tc}/{phase}-{index}/{file}, as linked from step details.
+func (s *Server) getStepArtifact(c *gin.Context) {
+	runID := c.Param("run_id")
+	parts := strings.SplitN(stripLeadingSlash(c.Param("path")), "/", 3)
+	if len(parts) < 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid artifact path"})
+		return
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cannot get home directory"})
+		return
+	}
+
+	// Cleaning against "/" keeps ".." from escaping the artifact directory
+	artifactDir := filepath.Join(home, ".tsuite", "runs", filepath.Base(runID), filepath.Base(parts[0]), filepath.Base(parts[1]), "artifacts")
+	filePath := filepath.Join(artifactDir, filepath.Clean("/"+parts[2]))
+
+	info, err := os.Stat(filePath)
+	if err != nil || !info.Mode().IsRegular() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Artifact not found"})
+		return
+	}
+
+	c.File(filePath)
+}
+
 // ==================== Test Status Updates ====================
 
 // StepReport represents a step result from the runner
 // Supports both flat format (Go runner) and nested result format (Python runner)
 type StepReport struct {
-	Phase      string `json:"phase"`
-	Index      int    `json:"index"`
-	Handler    string `json:"handler"`
-	Name       string `json:"name"`
-	Success    bool   `json:"success"`
-	ExitCode   int    `json:"exit_code"`
-	Stdout     string `json:"stdout"`
-	Stderr     string `json:"stderr"`
-	Error      string `json:"error,omitempty"`
-	DurationMS int64  `json:"duration_ms,omitempty"`
+	Phase      string   `json:"phase"`
+	Index      int      `json:"index"`
+	Handler    string   `json:"handler"`
+	Name       string   `json:"name"`
+	Success    bool     `json:"success"`
+	ExitCode   int      `json:"exit_code"`
+	Stdout     string   `json:"stdout"`
+	Stderr     string   `json:"stderr"`
+	Error      string   `json:"error,omitempty"`
+	DurationMS int64    `json:"duration_ms,omitempty"`
+	Artifacts  []string `json:"artifacts,omitempty"` // Paths relative to the test's artifact dir
+
+	// Interpolated command and handler fields (or routine params)
+	ResolvedCommand string         `json:"resolved_command,omitempty"`
+	ResolvedParams  map[string]any `json:"resolved_params,omitempty"`
 }
 
 // UnmarshalJSON handles both flat and nested result formats
@@ -154,6 +223,15 @@ func (sr *StepReport) UnmarshalJSON(data []byte) error {
 	if v, ok := raw["duration_ms"]; ok {
 		json.Unmarshal(v, &sr.DurationMS)
 	}
+	if v, ok := raw["artifacts"]; ok {
+		json.Unmarshal(v, &sr.Artifacts)
+	}
+	if v, ok := raw["resolved_command"]; ok {
+		json.Unmarshal(v, &sr.ResolvedCommand)
+	}
+	if v, ok := raw["resolved_params"]; ok {
+		json.Unmarshal(v, &sr.ResolvedParams)
+	}
 
 	// Check if there's a nested "result" object (Python format)
 	if resultRaw, ok := raw["result"]; ok {
@@ -202,6 +280,7 @@ type AssertionReport struct {
 	Passed   bool   `json:"passed"`
 	Actual   string `json:"actual"`
 	Expected string `json:"expected"`
+	Diff     string `json:"diff"`
 }
 
 // updateTestStatus handles PATCH /api/runs/:run_id/test/*test_id
@@ -217,14 +296,24 @@ func (s *Server) updateTestStatus(c *gin.Context) {
 
 // doUpdateTestStatus is the shared implementation for updating test status
 func (s *Server) doUpdateTestStatus(c *gin.Context, runID, testID string) {
+	ctx := c.Request.Context()
 	var req struct {
-		Status       string            `json:"status"`
-		DurationMS   *int64            `json:"duration_ms"`
-		ErrorMessage string            `json:"error_message"`
-		StepsPassed  *int              `json:"steps_passed"`
-		StepsFailed  *int              `json:"steps_failed"`
-		Steps        []StepReport      `json:"steps"`
-		Assertions   []AssertionReport `json:"assertions"`
+		Status           string            `json:"status"`
+		DurationMS       *int64            `json:"duration_ms"`
+		ErrorMessage     string            `json:"error_message"`
+		SkipReason       string            `json:"skip_reason"`
+		StepsPassed      *int              `json:"steps_passed"`
+		StepsFailed      *int              `json:"steps_failed"`
+		Steps            []StepReport      `json:"steps"`
+		StepsAppend      bool              `json:"steps_append"`
+		Assertions       []AssertionReport `json:"assertions"`
+		Suggestions      []string          `json:"suggestions"`
+		Leaks            map[string]any    `json:"leaks"`
+		DurationBudgetMS *int64            `json:"duration_budget_ms"`
+		Environment      map[string]any    `json:"environment"`
+		Container        map[string]any    `json:"container"`
+		Captured         map[string]string `json:"captured"`
+		WorkspacePath    string            `json:"workspace_path"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -232,82 +321,114 @@ func (s *Server) doUpdateTestStatus(c *gin.Context, runID, testID string) {
 		return
 	}
 
-	// Get test result
-	tr, err := s.repo.GetTestResultByTestIDAndRunID(testID, runID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if tr == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Test not found"})
-		return
-	}
+	// The test result is read, merged with the request and written in one
+	// transaction, so concurrent updates (e.g. chunks of steps sent with
+	// steps_append) don't overwrite each other
+	tr, err := s.repo.ModifyTestResult(ctx, testID, runID, func(tr *models.TestResult) ([]*models.StepResult, []*models.AssertionResult, error) {
+		// Idempotency check: ignore updates if test is already in a terminal state
+		// This prevents race conditions in parallel execution
+		if tr.Status.IsTerminal() && req.Status != "" {
+			return nil, nil, errTestTerminal
+		}
 
-	// Capture old status for incremental counter update
-	oldStatus := tr.Status
+		// A test cancelled on its own is reported skipped, however its runner
+		// ended (a step already running when the runner is stopped completes)
+		if tr.CancelRequested && models.TestStatus(req.Status).IsTerminal() {
+			req.Status = "skipped"
+			req.SkipReason = testCancelledReason
+		}
 
-	// Idempotency check: ignore updates if test is already in a terminal state
-	// This prevents race conditions in parallel execution
-	if oldStatus.IsTerminal() && req.Status != "" {
-		// Already in terminal state, return success but don't update
-		c.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"test_id": testID,
-			"status":  tr.Status,
-			"skipped": true,
-			"reason":  "test already in terminal state",
-		})
-		return
-	}
+		// Update fields
+		now := time.Now().UTC()
+		if req.Status != "" {
+			tr.Status = models.TestStatus(req.Status)
+
+			if req.Status == "running" {
+				tr.StartedAt = &now
+				if tr.QueuedAt != nil && now.After(*tr.QueuedAt) {
+					tr.QueueWaitMS = sql.NullInt64{Int64: now.Sub(*tr.QueuedAt).Milliseconds(), Valid: true}
+				}
+			} else if req.Status == "passed" || req.Status == "failed" || req.Status == "crashed" || req.Status == "skipped" {
+				tr.FinishedAt = &now
+			}
+		}
 
-	// Update fields
-	now := time.Now()
-	newStatus := oldStatus
-	if req.Status != "" {
-		newStatus = models.TestStatus(req.Status)
-		tr.Status = newStatus
+		if req.DurationMS != nil {
+			tr.DurationMS = sql.NullInt64{Int64: *req.DurationMS, Valid: true}
+		}
 
-		if req.Status == "running" {
-			tr.StartedAt = &now
-		} else if req.Status == "passed" || req.Status == "failed" || req.Status == "crashed" || req.Status == "skipped" {
-			tr.FinishedAt = &now
+		if req.DurationBudgetMS != nil {
+			tr.DurationBudgetMS = sql.NullInt64{Int64: *req.DurationBudgetMS, Valid: true}
 		}
-	}
 
-	if req.DurationMS != nil {
-		tr.DurationMS = sql.NullInt64{Int64: *req.DurationMS, Valid: true}
-	}
+		if req.ErrorMessage != "" {
+			tr.ErrorMessage = sql.NullString{String: req.ErrorMessage, Valid: true}
+		}
 
-	if req.ErrorMessage != "" {
-		tr.ErrorMessage = sql.NullString{String: req.ErrorMessage, Valid: true}
-	}
+		if req.SkipReason != "" {
+			tr.SkipReason = sql.NullString{String: req.SkipReason, Valid: true}
+		}
 
-	if req.StepsPassed != nil {
-		tr.StepsPassed = *req.StepsPassed
-	}
+		if req.StepsPassed != nil {
+			tr.StepsPassed = *req.StepsPassed
+		}
 
-	if req.StepsFailed != nil {
-		tr.StepsFailed = *req.StepsFailed
-	}
+		if req.StepsFailed != nil {
+			tr.StepsFailed = *req.StepsFailed
+		}
 
-	// Store steps as JSON in steps_json column
-	if len(req.Steps) > 0 {
-		stepsJSON, err := json.Marshal(req.Steps)
-		if err == nil {
-			tr.StepsJSON = sql.NullString{String: string(stepsJSON), Valid: true}
+		// Store steps as JSON in steps_json column. Large runs send steps in
+		// chunks with steps_append so no single request exceeds the body limit.
+		if len(req.Steps) > 0 {
+			steps := req.Steps
+			if req.StepsAppend && tr.StepsJSON.Valid {
+				var existing []StepReport
+				if err := json.Unmarshal([]byte(tr.StepsJSON.String), &existing); err == nil {
+					steps = append(existing, req.Steps...)
+				}
+			}
+			stepsJSON, err := json.Marshal(steps)
+			if err == nil {
+				tr.StepsJSON = sql.NullString{String: string(stepsJSON), Valid: true}
+			}
 		}
-	}
 
-	if err := s.repo.UpdateTestResult(tr); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update test: " + err.Error()})
-		return
-	}
+		if len(req.Suggestions) > 0 {
+			suggestionsJSON, err := json.Marshal(req.Suggestions)
+			if err == nil {
+				tr.Suggestions = sql.NullString{String: string(suggestionsJSON), Valid: true}
+			}
+		}
+
+		if len(req.Leaks) > 0 {
+			leaksJSON, err := json.Marshal(req.Leaks)
+			if err == nil {
+				tr.Leaks = sql.NullString{String: string(leaksJSON), Valid: true}
+			}
+		}
+
+		if len(req.Environment) > 0 {
+			environmentJSON, err := json.Marshal(req.Environment)
+			if err == nil {
+				tr.Environment = sql.NullString{String: string(environmentJSON), Valid: true}
+			}
+		}
+
+		if req.WorkspacePath != "" {
+			tr.WorkspacePath = sql.NullString{String: req.WorkspacePath, Valid: true}
+		}
+
+		if len(req.Container) > 0 {
+			containerJSON, err := json.Marshal(req.Container)
+			if err == nil {
+				tr.Container = sql.NullString{String: string(containerJSON), Valid: true}
+			}
+		}
 
-	// Store step results in step_results table
-	if len(req.Steps) > 0 {
+		// Build step results for the step_results table
+		stepResults := make([]*models.StepResult, 0, len(req.Steps))
 		for _, step := range req.Steps {
 			stepResult := &models.StepResult{
-				TestResultID: tr.ID,
 				StepIndex:    step.Index,
 				Phase:        step.Phase,
 				Handler:      step.Handler,
@@ -318,40 +439,67 @@ func (s *Server) doUpdateTestStatus(c *gin.Context, runID, testID string) {
 				ErrorMessage: sql.NullString{String: step.Error, Valid: step.Error != ""},
 				DurationMS:   sql.NullInt64{Int64: step.DurationMS, Valid: step.DurationMS > 0},
 			}
+			if len(step.Artifacts) > 0 {
+				if artifactsJSON, err := json.Marshal(step.Artifacts); err == nil {
+					stepResult.Artifacts = sql.NullString{String: string(artifactsJSON), Valid: true}
+				}
+			}
+			if step.ResolvedCommand != "" {
+				stepResult.ResolvedCommand = sql.NullString{String: step.ResolvedCommand, Valid: true}
+			}
+			if len(step.ResolvedParams) > 0 {
+				if paramsJSON, err := json.Marshal(step.ResolvedParams); err == nil {
+					stepResult.ResolvedParams = sql.NullString{String: string(paramsJSON), Valid: true}
+				}
+			}
 			if step.Success {
 				stepResult.Status = models.StepStatusPassed
 			} else {
 				stepResult.Status = models.StepStatusFailed
 			}
-			if err := s.repo.CreateStepResult(stepResult); err != nil {
-				// Log error but continue (best effort)
-				fmt.Printf("Warning: Failed to insert step result: %v\n", err)
-			}
+			stepResults = append(stepResults, stepResult)
 		}
-	}
 
-	// Store assertion results
-	if len(req.Assertions) > 0 {
+		// Build assertion results
+		assertionResults := make([]*models.AssertionResult, 0, len(req.Assertions))
 		for _, assertion := range req.Assertions {
-			assertionResult := &models.AssertionResult{
-				TestResultID:   tr.ID,
+			assertionResults = append(assertionResults, &models.AssertionResult{
 				AssertionIndex: assertion.Index,
 				Expression:     assertion.Expr,
 				Message:        sql.NullString{String: assertion.Message, Valid: assertion.Message != ""},
 				Passed:         assertion.Passed,
 				ActualValue:    sql.NullString{String: assertion.Actual, Valid: assertion.Actual != ""},
 				ExpectedValue:  sql.NullString{String: assertion.Expected, Valid: assertion.Expected != ""},
-			}
-			// Ignore errors on assertion insertion (best effort)
-			s.repo.CreateAssertionResult(assertionResult)
+				Diff:           sql.NullString{String: assertion.Diff, Valid: assertion.Diff != ""},
+			})
 		}
+		return stepResults, assertionResults, nil
+	})
+	if errors.Is(err, errTestTerminal) {
+		// Already in terminal state, return success but don't update
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"test_id": testID,
+			"status":  tr.Status,
+			"skipped": true,
+			"reason":  "test already in terminal state",
+		})
+		return
 	}
-
-	// Update run counters incrementally (idempotent, avoids race conditions)
-	if err := s.repo.UpdateRunCountersIncremental(runID, oldStatus, newStatus); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update run counters: " + err.Error()})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update test: " + err.Error()})
 		return
 	}
+	if tr == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test not found"})
+		return
+	}
+	if len(req.Captured) > 0 {
+		if err := s.repo.SetCapturedValues(ctx, tr.ID, req.Captured); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store captured values: " + err.Error()})
+			return
+		}
+	}
 
 	// Emit SSE event for status change
 	if req.Status == "running" {
@@ -383,6 +531,95 @@ func (s *Server) doUpdateTestStatus(c *gin.Context, runID, testID string) {
 	})
 }
 
+// errTestTerminal aborts an update of a test that already finished
+var errTestTerminal = errors.New("test already in terminal state")
+
+// testCancelledReason is the skip reason of tests cancelled on their own
+const testCancelledReason = "Cancelled"
+
+// cancelTest handles POST /api/runs/:run_id/test/*test_id/cancel
+// A pending test is skipped right away. A running test is flagged; the CLI
+// running it stops its runner or container and the rest of the run continues.
+func (s *Server) cancelTest(c *gin.Context) {
+	ctx := c.Request.Context()
+	runID := c.Param("run_id")
+	testID, ok := strings.CutSuffix(stripLeadingSlash(c.Param("test_id")), "/cancel")
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	tr, err := s.repo.GetTestResultByTestIDAndRunID(ctx, testID, runID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if tr == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test not found"})
+		return
+	}
+	if tr.Status.IsTerminal() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot cancel test with status: " + string(tr.Status)})
+		return
+	}
+
+	if err := s.repo.SetTestCancelRequested(ctx, tr.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if tr.Status == models.TestStatusPending {
+		oldStatus := tr.Status
+		now := time.Now().UTC()
+		tr.Status = models.TestStatusSkipped
+		tr.SkipReason = sql.NullString{String: testCancelledReason, Valid: true}
+		tr.FinishedAt = &now
+		if err := s.repo.UpdateTestResultFull(ctx, tr, oldStatus, nil, nil); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update test: " + err.Error()})
+			return
+		}
+		s.sseHub.EmitTestCompleted(runID, testID, string(tr.Status), 0, 0, 0)
+	} else {
+		s.sseHub.EmitTestCancelRequested(runID, testID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":          true,
+		"run_id":           runID,
+		"test_id":          testID,
+		"status":           tr.Status,
+		"cancel_requested": true,
+	})
+}
+
+// testHeartbeat handles PUT /api/runs/:run_id/test/*test_id/heartbeat
+// Runners call it periodically while a test runs so the watchdog can detect
+// runners that died without reporting a result
+func (s *Server) testHeartbeat(c *gin.Context) {
+	runID := c.Param("run_id")
+	testID, ok := strings.CutSuffix(stripLeadingSlash(c.Param("test_id")), "/heartbeat")
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	status, err := s.repo.TouchTestHeartbeat(c.Request.Context(), runID, testID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if status == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"test_id": testID,
+		"status":  status,
+	})
+}
+
 // updateTestStatusByPath handles PATCH /api/runs/:run_id/tests/*test_id
 // This is used by the Python runner which sends test_id as a path with slashes
 func (s *Server) updateTestStatusByPath(c *gin.Context) {
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/diskspace"
+)
+
+// readyDBTimeout bounds the database check of /readyz
+const readyDBTimeout = 2 * time.Second
+
+// schedulerStaleAfter is how long the run queue may go without a pass
+// before /readyz reports the scheduler as stuck
+const schedulerStaleAfter = 5 * queueCheckInterval
+
+// Probe check statuses of /readyz
+const (
+	checkOK       = "ok"
+	checkFailing  = "failing"
+	checkDisabled = "disabled"
+)
+
+// ProbeCheck is one check of /readyz
+type ProbeCheck struct {
+	Status string `json:"status"` // ok, failing or disabled
+	Detail string `json:"detail,omitempty"`
+}
+
+// healthCheck handles GET /health and GET /healthz: the server is alive and
+// serving requests
+func (s *Server) healthCheck(c *gin.Context) {
+	version := s.version
+	if version == "" {
+		version = "dev"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "ok",
+		"version":        version,
+		"uptime_seconds": int(time.Since(s.startedAt).Seconds()),
+	})
+}
+
+// readyCheck handles GET /readyz: the server can accept runs, i.e. the
+// database answers, the run queue is scheduling and the disks runs write to
+// have space. Answers 503 with the failing checks otherwise.
+func (s *Server) readyCheck(c *gin.Context) {
+	checks := map[string]ProbeCheck{
+		"database":  s.checkDatabase(c.Request.Context()),
+		"scheduler": s.checkScheduler(),
+		"disk":      s.checkDisk(),
+	}
+
+	status, code := "ready", http.StatusOK
+	for _, check := range checks {
+		if check.Status == checkFailing {
+			status, code = "not_ready", http.StatusServiceUnavailable
+		}
+	}
+	c.JSON(code, gin.H{"status": status, "checks": checks})
+}
+
+// checkDatabase checks that the results database answers queries
+func (s *Server) checkDatabase(ctx context.Context) ProbeCheck {
+	ctx, cancel := context.WithTimeout(ctx, readyDBTimeout)
+	defer cancel()
+	if err := s.repo.Ping(ctx); err != nil {
+		return ProbeCheck{Status: checkFailing, Detail: err.Error()}
+	}
+	return ProbeCheck{Status: checkOK}
+}
+
+// checkScheduler checks that the run queue loop passed recently
+func (s *Server) checkScheduler() ProbeCheck {
+	last := s.queueChecked.Load()
+	if last == 0 {
+		return ProbeCheck{Status: checkFailing, Detail: "run queue not started"}
+	}
+	if since := time.Since(time.Unix(0, last)); since > schedulerStaleAfter {
+		return ProbeCheck{Status: checkFailing, Detail: fmt.Sprintf("run queue last checked %s ago", since.Round(time.Second))}
+	}
+	return ProbeCheck{Status: checkOK}
+}
+
+// checkDisk checks free space where runs write, against --min-free-disk-mb
+func (s *Server) checkDisk() ProbeCheck {
+	if s.minFreeDiskMB <= 0 {
+		return ProbeCheck{Status: checkDisabled}
+	}
+	low := diskspace.Check(diskspace.Paths(), uint64(s.minFreeDiskMB))
+	if len(low) == 0 {
+		return ProbeCheck{Status: checkOK}
+	}
+	details := make([]string, len(low))
+	for i, l := range low {
+		details[i] = l.String()
+	}
+	return ProbeCheck{Status: checkFailing, Detail: strings.Join(details, "; ")}
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/issues"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// issueSyncTimeout bounds the tracker calls made for one finished run
+const issueSyncTimeout = 2 * time.Minute
+
+// syncIssues updates the suite's issue tracker after a run finished: it
+// opens an issue for each test that has now failed issues.after_failures
+// runs in a row, comments on the open issues of tests that still fail, and
+// closes those of tests that passed. Failures are logged; the run is
+// already recorded.
+func (s *Server) syncIssues(runID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), issueSyncTimeout)
+	defer cancel()
+
+	run, err := s.repo.GetRunByID(ctx, runID)
+	if err != nil || run == nil || !run.SuiteID.Valid {
+		return
+	}
+	suite, err := s.repo.GetSuiteByID(ctx, run.SuiteID.Int64)
+	if err != nil || suite == nil {
+		return
+	}
+	suiteConfig, err := config.LoadSuiteConfig(suite.FolderPath)
+	if err != nil || suiteConfig.Issues.Provider == "" {
+		return
+	}
+	settings := suiteConfig.Issues
+
+	tracker, err := issues.New(settings)
+	if err != nil {
+		slog.Warn("Issue tracker not available", "suite", suite.SuiteName, "error", err)
+		return
+	}
+	afterFailures := settings.AfterFailures
+	if afterFailures <= 0 {
+		afterFailures = issues.DefaultAfterFailures
+	}
+
+	results, err := s.repo.GetTestResultsByRunID(ctx, run.RunID)
+	if err != nil {
+		slog.Warn("Failed to sync issues", "run_id", run.RunID, "error", err)
+		return
+	}
+
+	// Runs finishing together must not open the same issue twice
+	s.issuesMu.Lock()
+	defer s.issuesMu.Unlock()
+
+	for _, tr := range results {
+		var err error
+		switch tr.Status {
+		case models.TestStatusFailed, models.TestStatusCrashed:
+			err = s.reportFailingTest(ctx, tracker, settings, suite, &tr, afterFailures)
+		case models.TestStatusPassed:
+			err = s.reportPassingTest(ctx, tracker, settings, suite, &tr)
+		}
+		if err != nil {
+			slog.Warn("Failed to update issue", "suite", suite.SuiteName, "test_id", tr.TestID, "error", err)
+		}
+	}
+}
+
+// reportFailingTest comments on the test's open issue, or opens one once the
+// test has failed afterFailures runs in a row
+func (s *Server) reportFailingTest(ctx context.Context, tracker issues.Tracker, settings config.IssueSettings,
+	suite *models.Suite, tr *models.TestResult, afterFailures int) error {
+	open, err := s.repo.GetOpenTestIssue(ctx, suite.ID, tr.TestID)
+	if err != nil {
+		return err
+	}
+	if open != nil {
+		body := fmt.Sprintf("Still failing in run %s.%s", runLink(settings, tr.RunID), errorExcerpt(tr))
+		return tracker.Comment(ctx, open.IssueKey, body)
+	}
+
+	failures, err := s.consecutiveFailures(ctx, suite.ID, tr.TestID, afterFailures)
+	if err != nil || len(failures) < afterFailures {
+		return err
+	}
+	lastPassed, err := s.repo.GetLatestTestResult(ctx, suite.ID, tr.TestID, models.TestStatusPassed)
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Test `%s` of suite %s has failed %d runs in a row.%s\n\n", tr.TestID, suite.SuiteName, len(failures), errorExcerpt(tr))
+	body.WriteString("Failing runs:\n")
+	for _, f := range failures {
+		fmt.Fprintf(&body, "- %s (%s)\n", runLink(settings, f.RunID), f.Status)
+	}
+	if lastPassed != nil {
+		fmt.Fprintf(&body, "\nLast passed in run %s", runLink(settings, lastPassed.RunID))
+		if lastPassed.StartedAt != nil {
+			fmt.Fprintf(&body, " at %s", lastPassed.StartedAt.UTC().Format(time.RFC3339))
+		}
+		body.WriteString(".\n")
+	} else {
+		body.WriteString("\nNo passing result is recorded for this test.\n")
+	}
+	body.WriteString("\nOpened by tsuite; it is updated on each failing run and closed when the test passes again.\n")
+
+	title := fmt.Sprintf("%s: %s is failing", suite.SuiteName, tr.TestID)
+	issue, err := tracker.Open(ctx, title, body.String())
+	if err != nil {
+		return err
+	}
+	slog.Info("Opened issue for failing test", "suite", suite.SuiteName, "test_id", tr.TestID, "issue", issue.Key)
+	return s.repo.CreateTestIssue(ctx, &models.TestIssue{
+		SuiteID:     suite.ID,
+		TestID:      tr.TestID,
+		Provider:    strings.ToLower(settings.Provider),
+		IssueKey:    issue.Key,
+		URL:         issue.URL,
+		OpenedRunID: tr.RunID,
+	})
+}
+
+// reportPassingTest closes the test's open issue (or only comments on it
+// with issues.keep_open)
+func (s *Server) reportPassingTest(ctx context.Context, tracker issues.Tracker, settings config.IssueSettings,
+	suite *models.Suite, tr *models.TestResult) error {
+	open, err := s.repo.GetOpenTestIssue(ctx, suite.ID, tr.TestID)
+	if err != nil || open == nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Passing again in run %s.", runLink(settings, tr.RunID))
+	if settings.KeepOpen {
+		err = tracker.Comment(ctx, open.IssueKey, body)
+	} else {
+		err = tracker.Close(ctx, open.IssueKey, body)
+	}
+	if err != nil {
+		return err
+	}
+	return s.repo.CloseTestIssue(ctx, open.ID, tr.RunID)
+}
+
+// consecutiveFailures returns the test's failed and crashed results since it
+// last passed, newest first, up to limit. Skipped and unfinished results
+// neither count nor break the streak.
+func (s *Server) consecutiveFailures(ctx context.Context, suiteID int64, testID string, limit int) ([]models.TestResult, error) {
+	history, err := s.repo.GetTestHistory(ctx, suiteID, testID, limit+20)
+	if err != nil {
+		return nil, err
+	}
+	var failures []models.TestResult
+	for _, tr := range history {
+		switch tr.Status {
+		case models.TestStatusFailed, models.TestStatusCrashed:
+			failures = append(failures, tr)
+		case models.TestStatusPassed:
+			return failures, nil
+		}
+		if len(failures) == limit {
+			break
+		}
+	}
+	return failures, nil
+}
+
+// runLink links a run in the dashboard, or names it without issues.dashboard_url
+func runLink(settings config.IssueSettings, runID string) string {
+	if settings.DashboardURL == "" {
+		return runID
+	}
+	return strings.TrimSuffix(settings.DashboardURL, "/") + "/runs?id=" + runID
+}
+
+// errorExcerpt is the start of a result's error message, as a sentence to
+// append, or "" without one
+func errorExcerpt(tr *models.TestResult) string {
+	msg := strings.TrimSpace(tr.ErrorMessage.String)
+	if msg == "" {
+		return ""
+	}
+	if len(msg) > 500 {
+		msg = msg[:500] + "..."
+	}
+	return "\n\nError:\n```\n" + msg + "\n```"
+}
+
+// listSuiteIssues handles GET /api/suites/:id/issues
+// Lists the issues opened for the suite's failing tests; ?open=true for the
+// open ones only.
+func (s *Server) listSuiteIssues(c *gin.Context) {
+	suite, ok := s.getSuiteByIDParam(c)
+	if !ok {
+		return
+	}
+
+	list, err := s.repo.ListTestIssues(c.Request.Context(), suite.ID, c.Query("open") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suite_id": suite.ID,
+		"issues":   list,
+		"count":    len(list),
+	})
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxBodyBytes is the default limit for request bodies (after decompression)
+const DefaultMaxBodyBytes int64 = 32 << 20
+
+// limitRequestBody rejects request bodies larger than maxBytes with 413 and
+// transparently decompresses bodies sent with Content-Encoding: gzip. The
+// limit applies to the decompressed size.
+func limitRequestBody(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		var reader io.Reader = c.Request.Body
+		if strings.EqualFold(c.GetHeader("Content-Encoding"), "gzip") {
+			gz, err := gzip.NewReader(c.Request.Body)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid gzip request body"})
+				return
+			}
+			defer gz.Close()
+			reader = gz
+		}
+
+		// Read one byte past the limit to detect oversized bodies
+		body, err := io.ReadAll(io.LimitReader(reader, maxBytes+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body: " + err.Error()})
+			return
+		}
+		if int64(len(body)) > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":     fmt.Sprintf("Request body exceeds limit of %d bytes; send steps in smaller chunks (steps_append) or raise --max-body-mb", maxBytes),
+				"max_bytes": maxBytes,
+			})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Request.ContentLength = int64(len(body))
+		c.Request.Header.Del("Content-Encoding")
+		c.Next()
+	}
+}
+
+// logRequests logs each request with its status and latency. Server errors
+// log at error level and client errors at warn; health and metrics probes
+// (under basePath) only show up with --log-level debug.
+func logRequests(basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		path := c.Request.URL.Path
+		probe := strings.TrimPrefix(path, basePath)
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case probe == "/health" || probe == "/healthz" || probe == "/readyz" || probe == "/metrics":
+			level = slog.LevelDebug
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if query := c.Request.URL.RawQuery; query != "" {
+			attrs = append(attrs, slog.String("query", query))
+		}
+		if errs := c.Errors.ByType(gin.ErrorTypePrivate).String(); errs != "" {
+			attrs = append(attrs, slog.String("error", errs))
+		}
+		slog.LogAttrs(c.Request.Context(), level, "Request", attrs...)
+	}
+}
+
+// gzipWriter compresses the response body
+type gzipWriter struct {
+	gin.ResponseWriter
+	writer  *gzip.Writer
+	written int
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	n, err := w.writer.Write(data)
+	w.written += n
+	return n, err
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipWriter) WriteHeader(code int) {
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// gzipResponses compresses API responses (under basePath) for clients that
+// accept gzip. Event streams are left uncompressed so events are delivered
+// immediately.
+func gzipResponses(basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := strings.TrimPrefix(c.Request.URL.Path, basePath)
+		if !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") ||
+			!strings.HasPrefix(path, "/api/") ||
+			isEventStreamPath(path) {
+			c.Next()
+			return
+		}
+
+		gz := gzip.NewWriter(c.Writer)
+		c.Header("Content-Encoding", "gzip")
+		c.Header("Vary", "Accept-Encoding")
+		writer := &gzipWriter{ResponseWriter: c.Writer, writer: gz}
+		c.Writer = writer
+		defer func() {
+			// Drop the encoding if nothing was written (e.g. 204 responses)
+			if writer.written == 0 {
+				writer.Header().Del("Content-Encoding")
+				gz.Reset(io.Discard)
+			}
+			gz.Close()
+		}()
+
+		c.Next()
+	}
+}
+
+// isEventStreamPath reports whether a path, with basePath trimmed, serves
+// server-sent events
+func isEventStreamPath(path string) bool {
+	return path == "/api/events" || strings.HasSuffix(path, "/stream")
+}
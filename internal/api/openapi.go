@@ -0,0 +1,21 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPISpec is the OpenAPI description of the REST API.
+// Client bindings (e.g. clients/python) are generated from this file.
+//
+//go:embed openapi.yaml
+var OpenAPISpec []byte
+
+//go:generate go run gen_python_client.go
+
+// getOpenAPISpec handles GET /api/openapi.yaml
+func (s *Server) getOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/yaml", OpenAPISpec)
+}
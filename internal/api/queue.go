@@ -0,0 +1,424 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/orchestrator"
+)
+
+// DefaultMaxRunsPerSuite is how many runs of one suite the API server starts
+// at once, unless the suite's config.yaml sets execution.max_concurrent_runs
+const DefaultMaxRunsPerSuite = 1
+
+// queueCheckInterval is how often the queue rechecks its suites, so slots held
+// by runs started outside the API server are noticed when those runs finish
+const queueCheckInterval = 2 * time.Second
+
+// queuedRun is a run launched through the API, waiting for a free run slot of
+// its suite
+type queuedRun struct {
+	runID   string
+	suite   *models.Suite
+	opts    orchestrator.Options
+	logPath string
+
+	position int           // Last announced queue position, 0 once started
+	pid      int           // CLI process, once started (0 for in-process runs)
+	err      error         // Why the run failed to start
+	exited   chan struct{} // Closed once the run ends, or leaves the queue without starting
+}
+
+// runQueue holds the runs launched through the API, oldest first per suite,
+// until the suite has a free run slot
+type runQueue struct {
+	mu      sync.Mutex
+	waiting map[int64][]*queuedRun // By suite ID
+	started map[string]*queuedRun  // Runs still running, by run ID
+}
+
+func newRunQueue() *runQueue {
+	return &runQueue{
+		waiting: make(map[int64][]*queuedRun),
+		started: make(map[string]*queuedRun),
+	}
+}
+
+// enqueueRun records a queued run of suite and starts it with opts as soon as
+// the suite has a free run slot, writing its output to a new temp file named
+// after logPattern
+func (s *Server) enqueueRun(ctx context.Context, suite *models.Suite, logPattern string, opts orchestrator.Options) (*queuedRun, error) {
+	logFile, err := os.CreateTemp("", logPattern)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create log file: %w", err)
+	}
+	logFile.Close()
+
+	mode := string(suite.Mode)
+	if mode == "" {
+		mode = string(models.SuiteModeDocker)
+	}
+	run := &models.Run{
+		RunID:     generateUUID(),
+		SuiteID:   sql.NullInt64{Int64: suite.ID, Valid: true},
+		SuiteName: sql.NullString{String: suite.SuiteName, Valid: true},
+		StartedAt: time.Now().UTC(),
+		Status:    models.RunStatusQueued,
+		Mode:      mode,
+	}
+	if err := s.repo.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("Failed to create run: %w", err)
+	}
+
+	opts.SuitePath = suite.FolderPath
+	opts.RunID = run.RunID
+	opts.Version = s.version
+	if opts.APIURL == "" {
+		opts.APIURL = s.localURL()
+	}
+	job := &queuedRun{
+		runID:   run.RunID,
+		suite:   suite,
+		opts:    opts,
+		logPath: logFile.Name(),
+		exited:  make(chan struct{}),
+	}
+	s.queue.mu.Lock()
+	s.queue.waiting[suite.ID] = append(s.queue.waiting[suite.ID], job)
+	s.queue.mu.Unlock()
+
+	s.scheduleRuns()
+	return job, nil
+}
+
+// queueStatus returns the queue position of a run (0 once started), the PID
+// of its CLI (0 until started, or if run in-process) and why the run failed
+// to start, if it did
+func (s *Server) queueStatus(job *queuedRun) (position, pid int, err error) {
+	s.queue.mu.Lock()
+	defer s.queue.mu.Unlock()
+	return job.position, job.pid, job.err
+}
+
+// launchResponse describes a run just handed to the queue: whether it started
+// or is waiting, and where. It returns false if the run failed to start.
+func (s *Server) launchResponse(job *queuedRun) (gin.H, bool) {
+	position, pid, err := s.queueStatus(job)
+	response := gin.H{
+		"started":  position == 0,
+		"queued":   position > 0,
+		"run_id":   job.runID,
+		"log_file": job.logPath,
+	}
+	if err != nil {
+		response["error"] = err.Error()
+		return response, false
+	}
+	if position > 0 {
+		response["queue_position"] = position
+	} else if pid > 0 {
+		response["pid"] = pid
+	} else {
+		response["in_process"] = true
+	}
+	return response, true
+}
+
+// queuePosition returns the position of a waiting run in its suite's queue,
+// or 0 if the run is not waiting
+func (s *Server) queuePosition(runID string) int {
+	s.queue.mu.Lock()
+	defer s.queue.mu.Unlock()
+	for _, jobs := range s.queue.waiting {
+		for i, job := range jobs {
+			if job.runID == runID {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// scheduleRuns starts waiting runs of every suite with free run slots, then
+// announces the new queue position of each run still waiting
+func (s *Server) scheduleRuns() {
+	s.queue.mu.Lock()
+	defer s.queue.mu.Unlock()
+
+	for suiteID, jobs := range s.queue.waiting {
+		for free := s.freeRunSlots(jobs[0].suite); free > 0 && len(jobs) > 0; free-- {
+			s.startQueuedRun(jobs[0])
+			jobs = jobs[1:]
+		}
+		if len(jobs) == 0 {
+			delete(s.queue.waiting, suiteID)
+			continue
+		}
+		s.queue.waiting[suiteID] = jobs
+
+		for i, job := range jobs {
+			if job.position != i+1 {
+				job.position = i + 1
+				s.sseHub.EmitRunQueued(job.runID, suiteID, job.position)
+			}
+		}
+	}
+}
+
+// freeRunSlots returns how many more runs of suite may start now. Runs
+// started outside the API server hold slots too. Callers must hold queue.mu.
+func (s *Server) freeRunSlots(suite *models.Suite) int {
+	limit := s.maxRunsPerSuite
+	if suiteConfig, err := config.LoadSuiteConfig(suite.FolderPath); err == nil && suiteConfig.Execution.MaxConcurrentRuns > 0 {
+		limit = suiteConfig.Execution.MaxConcurrentRuns
+	}
+
+	active := 0
+	for _, job := range s.queue.started {
+		if job.suite.ID == suite.ID {
+			active++
+		}
+	}
+	runIDs, err := s.repo.ActiveRunIDsBySuite(context.Background(), suite.ID)
+	if err != nil {
+		slog.Error("Run queue failed to count active runs", "suite_id", suite.ID, "error", err)
+		return 0
+	}
+	for _, runID := range runIDs {
+		if s.queue.started[runID] == nil {
+			active++
+		}
+	}
+	return limit - active
+}
+
+// startQueuedRun starts a run leaving the queue, in a CLI subprocess or, with
+// --in-process, in the API server itself. Callers must hold queue.mu.
+func (s *Server) startQueuedRun(job *queuedRun) {
+	var err error
+	if s.inProcess {
+		err = s.startInProcessRun(job)
+	} else {
+		err = s.startCLIRun(job)
+	}
+	if err != nil {
+		slog.Error("Run queue failed to start run", "run_id", job.runID, "error", err)
+		job.err = err
+		s.finishQueuedRun(job.runID, models.RunStatusFailed)
+		close(job.exited)
+		return
+	}
+	slog.Info("Started queued run", "run_id", job.runID, "suite", job.suite.SuiteName, "pid", job.pid)
+
+	job.position = 0
+	s.queue.started[job.runID] = job
+}
+
+// startCLIRun starts tsuite run for a job leaving the queue
+func (s *Server) startCLIRun(job *queuedRun) error {
+	cmd, done, err := startCLIWithLog(job.suite.FolderPath, job.logPath, runArgs(job.opts)...)
+	if err != nil {
+		return err
+	}
+	job.pid = cmd.Process.Pid
+	go func() {
+		<-done
+		s.queuedRunExited(job, cmd.ProcessState != nil && cmd.ProcessState.Success())
+	}()
+	return nil
+}
+
+// startInProcessRun runs a job leaving the queue in a goroutine of the API
+// server. Its results still reach the server through the HTTP API, as from a
+// CLI run.
+func (s *Server) startInProcessRun(job *queuedRun) error {
+	logFile, err := os.OpenFile(job.logPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("Failed to open log file: %w", err)
+	}
+	opts := job.opts
+	opts.Output = logFile
+	r, err := orchestrator.Prepare(opts)
+	if err != nil {
+		fmt.Fprintf(logFile, "Error: %v\n", err)
+		logFile.Close()
+		return err
+	}
+
+	go func() {
+		defer logFile.Close()
+		s.queuedRunExited(job, executeInProcess(r, logFile))
+	}()
+	return nil
+}
+
+// executeInProcess executes a prepared run, printing to log what tsuite run
+// would, and reports whether it succeeded
+func executeInProcess(r *orchestrator.Run, log *os.File) (success bool) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.Error("In-process run panicked", "panic", p)
+			fmt.Fprintf(log, "Error: run panicked: %v\n", p)
+			success = false
+		}
+	}()
+
+	r.PrintHeader()
+	if len(r.Tests) == 0 {
+		fmt.Fprintln(log, "No tests found matching the filters")
+		return true
+	}
+	result, err := r.Execute(context.Background())
+	if err != nil {
+		fmt.Fprintf(log, "Error: %v\n", err)
+		return false
+	}
+	result.PrintSummary(log)
+	return result.Failed == 0
+}
+
+// runArgs returns the tsuite run arguments for opts
+func runArgs(opts orchestrator.Options) []string {
+	args := []string{
+		"run",
+		"--suite-path", opts.SuitePath,
+		"--api-url", opts.APIURL,
+	}
+	for _, uc := range opts.Filter.UC {
+		args = append(args, "--uc", uc)
+	}
+	for _, tc := range opts.Filter.TC {
+		args = append(args, "--tc", tc)
+	}
+	for _, tag := range opts.Filter.Tags {
+		args = append(args, "--tags", tag)
+	}
+	for _, tag := range opts.Filter.SkipTags {
+		args = append(args, "--skip-tags", tag)
+	}
+	if opts.Parallel > 0 {
+		args = append(args, "--parallel", strconv.Itoa(opts.Parallel))
+	}
+	if opts.Mode != "" {
+		args = append(args, "--mode", opts.Mode)
+	}
+	if opts.Profile != "" {
+		args = append(args, "--profile", opts.Profile)
+	}
+	for _, kv := range opts.Env {
+		args = append(args, "--env", kv)
+	}
+	if opts.Smoke {
+		args = append(args, "--smoke")
+	}
+	if opts.ChangedSince != "" {
+		args = append(args, "--changed-since", opts.ChangedSince)
+	}
+	if opts.NodeVersion != "" {
+		args = append(args, "--node-version", opts.NodeVersion)
+	}
+	if opts.Offline {
+		args = append(args, "--offline")
+	}
+	if opts.ParentRunID != "" {
+		args = append(args, "--parent-run-id", opts.ParentRunID)
+	}
+	if opts.PipelineRunID != "" {
+		args = append(args, "--pipeline-run-id", opts.PipelineRunID)
+	}
+	return append(args, "--run-id", opts.RunID)
+}
+
+// queuedRunExited frees the run slot of a run that ended. A run the CLI or
+// orchestrator never took over (e.g. no tests matched the filters) is finished
+// by whether it succeeded.
+func (s *Server) queuedRunExited(job *queuedRun, success bool) {
+	s.queue.mu.Lock()
+	delete(s.queue.started, job.runID)
+	s.queue.mu.Unlock()
+
+	status := models.RunStatusCompleted
+	if !success {
+		status = models.RunStatusFailed
+	}
+	s.finishQueuedRun(job.runID, status)
+	close(job.exited)
+
+	s.scheduleRuns()
+}
+
+// finishQueuedRun gives a run that is still queued its final status
+func (s *Server) finishQueuedRun(runID string, status models.RunStatus) {
+	finished, err := s.repo.FinishQueuedRun(context.Background(), runID, status)
+	if err != nil {
+		slog.Error("Failed to finish queued run", "run_id", runID, "error", err)
+		return
+	}
+	if !finished {
+		return
+	}
+	s.sseHub.EmitQueuedRunFinished(runID, status)
+}
+
+// cancelQueuedRun removes a run from the queue before it starts and
+// marks it cancelled. It returns false if the run is not waiting.
+func (s *Server) cancelQueuedRun(runID string) bool {
+	s.queue.mu.Lock()
+	var cancelled *queuedRun
+	for suiteID, jobs := range s.queue.waiting {
+		for i, job := range jobs {
+			if job.runID == runID {
+				cancelled = job
+				s.queue.waiting[suiteID] = append(jobs[:i:i], jobs[i+1:]...)
+				if len(jobs) == 1 {
+					delete(s.queue.waiting, suiteID)
+				}
+				break
+			}
+		}
+	}
+	s.queue.mu.Unlock()
+	if cancelled == nil {
+		return false
+	}
+
+	s.finishQueuedRun(runID, models.RunStatusCancelled)
+	close(cancelled.exited)
+
+	// Announce the new positions of the runs behind it
+	s.scheduleRuns()
+	return true
+}
+
+// runQueueLoop periodically starts waiting runs whose suites have freed up
+func (s *Server) runQueueLoop() {
+	ticker := time.NewTicker(queueCheckInterval)
+	defer ticker.Stop()
+	s.queueChecked.Store(time.Now().UnixNano())
+	for range ticker.C {
+		s.scheduleRuns()
+		s.queueChecked.Store(time.Now().UnixNano())
+	}
+}
+
+// recoverQueuedRuns cancels runs left queued by a previous API server
+func (s *Server) recoverQueuedRuns() {
+	runIDs, err := s.repo.CancelQueuedRuns(context.Background())
+	if err != nil {
+		slog.Error("Failed to cancel leftover queued runs", "error", err)
+	}
+	for _, runID := range runIDs {
+		slog.Warn("Cancelled queued run left by API server restart", "run_id", runID)
+		s.sseHub.EmitQueuedRunFinished(runID, models.RunStatusCancelled)
+	}
+}
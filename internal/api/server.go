@@ -4,23 +4,135 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/diskspace"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
 )
 
 // Server represents the API server
 type Server struct {
 	router *gin.Engine
-	repo   *db.Repository
+	repo   db.Store
 	port   int
 	sseHub *SSEHub
+
+	staleAfter time.Duration // Watchdog silent period (0 = disabled)
+	version    string        // tsuite version reported by /api/version
+
+	queue           *runQueue // Runs launched through the API, waiting for a slot
+	maxRunsPerSuite int       // Default concurrent runs per suite
+	inProcess       bool      // Run tests in this process instead of a CLI subprocess
+	minFreeDiskMB   int       // Warn running runs below this free space (0 = disabled)
+
+	basePath    string // Path prefix of every route, e.g. /tsuite ("" = served at /)
+	tlsCertFile string // Serve HTTPS with this certificate and key ("" = HTTP)
+	tlsKeyFile  string
+	tlsCertSHA  string // SHA-256 fingerprint of the certificate, pinned by the runs the server launches
+
+	branding Branding      // Dashboard title, logo and accent of this deployment
+	logo     *brandingLogo // nil = the tsuite logo
+
+	yamlMu   sync.Mutex // Serializes If-Match checks and writes of the YAML editors
+	issuesMu sync.Mutex // Serializes issue tracker updates of finished runs
+
+	startedAt    time.Time    // When the server was created, for /healthz
+	queueChecked atomic.Int64 // Unix nanoseconds of the run queue's latest pass, for /readyz
+}
+
+// Options configures the API server
+type Options struct {
+	Port         int
+	SSE          SSEConfig
+	MaxBodyBytes int64         // Request body limit after decompression
+	StaleAfter   time.Duration // Mark tests crashed after this long without a heartbeat (0 = disabled)
+	Version      string        // tsuite version of the server binary
+
+	MaxRunsPerSuite int  // Runs of one suite started at once; more are queued
+	InProcess       bool // Run tests in the server process instead of a CLI subprocess
+	MinFreeDiskMB   int  // Warn running runs when free disk space drops below this (0 = disabled)
+
+	CORSOrigins []string // Origins allowed to call the API from browsers (default: any)
+
+	BasePath    string // Serve every route and the dashboard under this path, e.g. /tsuite
+	TLSCertFile string // Serve HTTPS with this PEM certificate (chain) and key
+	TLSKeyFile  string
+
+	Branding Branding // Dashboard title, logo and accent color
+
+	SecondaryDB string        // <driver>:<dsn> of a database every write is mirrored to
+	DBTimeout   time.Duration // Bound on each results database call of a request or background task
+}
+
+// DefaultOptions returns the default server options for a port
+func DefaultOptions(port int) Options {
+	return Options{
+		Port:         port,
+		SSE:          DefaultSSEConfig(),
+		MaxBodyBytes: DefaultMaxBodyBytes,
+		StaleAfter:   DefaultStaleAfter,
+		CORSOrigins:  DefaultCORSOrigins,
+		DBTimeout:    db.DefaultQueryTimeout,
+
+		MaxRunsPerSuite: DefaultMaxRunsPerSuite,
+		MinFreeDiskMB:   diskspace.DefaultMinFreeMB,
+	}
 }
 
-// NewServer creates a new API server
+// NewServer creates a new API server with default options
 func NewServer(port int) (*Server, error) {
+	return NewServerWithOptions(DefaultOptions(port))
+}
+
+// NewServerWithOptions creates a new API server
+func NewServerWithOptions(opts Options) (*Server, error) {
+	if err := opts.SSE.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.MaxBodyBytes <= 0 {
+		return nil, fmt.Errorf("max body size must be positive")
+	}
+	if opts.StaleAfter < 0 {
+		return nil, fmt.Errorf("stale-after must not be negative")
+	}
+	if opts.MaxRunsPerSuite <= 0 {
+		return nil, fmt.Errorf("max runs per suite must be positive")
+	}
+	if opts.DBTimeout <= 0 {
+		return nil, fmt.Errorf("database timeout must be positive")
+	}
+	basePath, err := cleanBasePath(opts.BasePath)
+	if err != nil {
+		return nil, err
+	}
+	certSHA, err := tlsCertSHA256(opts.TLSCertFile, opts.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	corsCfg, err := corsConfig(opts.CORSOrigins)
+	if err != nil {
+		return nil, err
+	}
+	logo, err := loadBranding(opts.Branding)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetQueryTimeout(opts.DBTimeout)
+	if opts.SecondaryDB != "" {
+		// Runs launched from here write their results to both databases too
+		db.SetSecondaryDB(opts.SecondaryDB)
+		os.Setenv(db.EnvSecondaryDB, opts.SecondaryDB)
+	}
+
 	repo, err := db.NewRepository()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create repository: %w", err)
@@ -31,20 +143,38 @@ func NewServer(port int) (*Server, error) {
 
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(logRequests(basePath))
 
 	// CORS middleware
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type"},
-		AllowCredentials: true,
-	}))
+	router.Use(cors.New(corsCfg))
+
+	// Request size limit, gzip request bodies and gzip responses
+	router.Use(limitRequestBody(opts.MaxBodyBytes))
+	router.Use(gzipResponses(basePath))
 
 	s := &Server{
 		router: router,
 		repo:   repo,
-		port:   port,
-		sseHub: NewSSEHub(),
+		port:   opts.Port,
+		sseHub: NewSSEHub(opts.SSE),
+
+		staleAfter: opts.StaleAfter,
+		version:    opts.Version,
+
+		queue:           newRunQueue(),
+		maxRunsPerSuite: opts.MaxRunsPerSuite,
+		inProcess:       opts.InProcess,
+		minFreeDiskMB:   opts.MinFreeDiskMB,
+
+		basePath:    basePath,
+		tlsCertFile: opts.TLSCertFile,
+		tlsKeyFile:  opts.TLSKeyFile,
+		tlsCertSHA:  certSHA,
+
+		branding: opts.Branding,
+		logo:     logo,
+
+		startedAt: time.Now(),
 	}
 
 	s.setupRoutes()
@@ -55,18 +185,66 @@ func NewServer(port int) (*Server, error) {
 // Run starts the server
 func (s *Server) Run() error {
 	addr := fmt.Sprintf(":%d", s.port)
-	fmt.Printf("Starting API server on http://localhost%s\n", addr)
+	fmt.Printf("Starting API server on %s/\n", s.localURL())
+	if s.tlsCertSHA != "" {
+		// Runs launched from here (and their runners) trust the certificate
+		// at localhost and host.docker.internal, which it may not name
+		fmt.Printf("TLS certificate SHA-256: %s\n", s.tlsCertSHA)
+		os.Setenv(runner.EnvAPICertSHA256, s.tlsCertSHA)
+	}
+	s.recoverQueuedRuns()
+	go s.runQueueLoop()
+	if s.staleAfter > 0 {
+		s.recoverOrphanedRuns(s.staleAfter)
+		go s.runWatchdog(s.staleAfter)
+	}
+	if s.minFreeDiskMB > 0 {
+		go s.runDiskMonitor(s.minFreeDiskMB)
+	}
+	if s.tlsCertFile != "" {
+		return s.router.RunTLS(addr, s.tlsCertFile, s.tlsKeyFile)
+	}
 	return s.router.Run(addr)
 }
 
+// localURL is the URL of the server on this machine, for the runs and
+// pipelines it launches
+func (s *Server) localURL() string {
+	scheme := "http"
+	if s.tlsCertFile != "" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://localhost:%d%s", scheme, s.port, s.basePath)
+}
+
+// routes returns the group every route is registered on: the root, or
+// --base-path
+func (s *Server) routes() *gin.RouterGroup {
+	return s.router.Group(s.basePath)
+}
+
 // setupRoutes configures all API routes
 func (s *Server) setupRoutes() {
-	// Health check
-	s.router.GET("/health", s.healthCheck)
+	root := s.routes()
+
+	// Liveness and readiness probes
+	root.GET("/health", s.healthCheck)
+	root.GET("/healthz", s.healthCheck)
+	root.GET("/readyz", s.readyCheck)
+
+	// Metrics (Prometheus text format)
+	root.GET("/metrics", s.metrics)
 
 	// API routes
-	api := s.router.Group("/api")
+	api := root.Group("/api")
 	{
+		// Version handshake for CLI compatibility checks
+		api.GET("/version", s.getVersion)
+
+		// Dashboard title, logo and accent of this deployment
+		api.GET("/branding", s.getBranding)
+		api.GET("/branding/logo", s.getBrandingLogo)
+
 		// Suites
 		api.GET("/suites", s.listSuites)
 		api.POST("/suites", s.createSuite)
@@ -80,6 +258,14 @@ func (s *Server) setupRoutes() {
 
 		// Suite tests listing
 		api.GET("/suites/:id/tests", s.getSuiteTests)
+		api.POST("/suites/:id/tests", s.createSuiteTest)
+		api.POST("/suites/:id/tests/*path", s.postSuiteTestPath) // clone, or {uc}/{tc}/run
+		api.DELETE("/suites/:id/tests/*test_id", s.deleteSuiteTest)
+		api.GET("/suites/:id/test-history/*test_id", s.getTestHistory)
+		api.GET("/suites/:id/test-env-diff/*test_id", s.getTestEnvDiff) // Latest pass vs latest fail
+		api.GET("/suites/:id/issues", s.listSuiteIssues)                // Issues opened for failing tests
+		api.GET("/suites/:id/badge.svg", s.getSuiteBadge)               // Status badge for READMEs
+		api.GET("/suites/:id/export/durations", s.exportSuiteDurations) // CSV of test durations across runs
 
 		// Test Case YAML Editor (Gin-friendly routes)
 		api.GET("/suites/:id/test-yaml/*test_id", s.getTestYAMLHandler)
@@ -89,25 +275,56 @@ func (s *Server) setupRoutes() {
 		api.POST("/suites/:id/test-step/:phase/*test_id", s.addTestStepHandler)
 		api.DELETE("/suites/:id/test-step/:phase/:index/*test_id", s.deleteTestStepHandler)
 
+		// Routines YAML Editor (global and UC-level routines.yaml)
+		api.GET("/suites/:id/routines", s.getGlobalRoutines)
+		api.PUT("/suites/:id/routines", s.updateGlobalRoutines)
+		api.GET("/suites/:id/uc/:uc/routines", s.getUseCaseRoutines)
+		api.PUT("/suites/:id/uc/:uc/routines", s.updateUseCaseRoutines)
+
 		// Stats
 		api.GET("/stats", s.getStats)
+		api.GET("/stats/assertions", s.getAssertionStats)
+		api.GET("/overview", s.getOverview) // Latest run of every suite
+		api.GET("/coverage", s.getCoverage) // Features covered by a run's tests
+
+		// Pipelines (several suites run as one unit)
+		api.POST("/pipelines/run", s.runPipeline) // Launch a pipeline file
+		api.GET("/pipelines/runs", s.listPipelineRuns)
+		api.POST("/pipelines/runs", s.createPipelineRun)
+		api.GET("/pipelines/runs/:id", s.getPipelineRun)
+		api.POST("/pipelines/runs/:id/complete", s.completePipelineRun)
+		api.POST("/pipelines/runs/:id/cancel", s.cancelPipelineRun)
+
+		// Audit log of suite file changes
+		api.GET("/audit", s.listAudit)
 
 		// Runs
 		api.GET("/runs", s.listRuns)
 		api.POST("/runs", s.createRun)
 		api.GET("/runs/latest", s.getLatestRun)
+		api.GET("/feed.atom", s.getFeed) // Atom feed of finished runs
 		api.GET("/runs/:run_id", s.getRun)
 		api.PATCH("/runs/:run_id", s.updateRunStatus)
 		api.GET("/runs/:run_id/tests", s.getRunTests)
+		api.GET("/runs/:run_id/slowest", s.getRunSlowest)
+		api.GET("/runs/:run_id/scheduling", s.getRunScheduling)
+		api.GET("/runs/:run_id/export", s.exportRun) // CSV of the run's test results
+		api.GET("/runs/:run_id/wait", s.waitRun)     // Long-poll until the run finishes
+		api.GET("/runs/:run_id/artifacts/*path", s.getStepArtifact)
 		api.GET("/runs/:run_id/tests/tree", s.getRunTestsTree)              // Dashboard uses this
-		api.GET("/runs/:run_id/tests/:test_id", s.getTestDetailByNumericID)  // Dashboard uses numeric ID
-		api.GET("/runs/:run_id/test/*test_id", s.getTestDetail)              // CLI uses path-based ID
-		api.PATCH("/runs/:run_id/test/*test_id", s.updateTestStatus)          // Go runner uses wildcard path
-		api.PATCH("/runs/:run_id/tests/*test_id", s.updateTestStatusByPath)  // Python runner uses this (also wildcard for paths with /)
+		api.GET("/runs/:run_id/tests/:test_id", s.getTestDetailByNumericID) // Dashboard uses numeric ID
+		api.GET("/runs/:run_id/test/*test_id", s.getTestDetail)             // CLI uses path-based ID
+		api.PATCH("/runs/:run_id/test/*test_id", s.updateTestStatus)        // Go runner uses wildcard path
+		api.PUT("/runs/:run_id/test/*test_id", s.testHeartbeat)             // Go runner: .../test/{test_id}/heartbeat
+		api.POST("/runs/:run_id/test/*test_id", s.cancelTest)               // Dashboard: .../test/{test_id}/cancel
+		api.PATCH("/runs/:run_id/tests/*test_id", s.updateTestStatusByPath) // Python runner uses this (also wildcard for paths with /)
 		api.POST("/runs/:run_id/complete", s.completeRun)
 		api.POST("/runs/:run_id/cancel", s.cancelRun)
 		api.POST("/runs/:run_id/rerun", s.rerunTests)
 		api.DELETE("/runs/:run_id", s.deleteRun)
+		api.GET("/runs/:run_id/comments", s.listRunComments)
+		api.POST("/runs/:run_id/comments", s.createRunComment) // Run comment, or test comment with test_id
+		api.DELETE("/runs/:run_id/comments/:comment_id", s.deleteRunComment)
 
 		// SSE Events
 		api.GET("/events", s.streamEvents)
@@ -116,13 +333,24 @@ func (s *Server) setupRoutes() {
 
 		// File Browser
 		api.GET("/browse", s.browseFolders)
+
+		// API description
+		api.GET("/openapi.yaml", s.getOpenAPISpec)
 	}
 
 	// Dashboard static files (must be after API routes)
 	s.SetupDashboardRoutes()
 }
 
-// healthCheck handles GET /health
-func (s *Server) healthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+// getVersion handles GET /api/version
+func (s *Server) getVersion(c *gin.Context) {
+	version := s.version
+	if version == "" {
+		version = "dev"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"version":            version,
+		"schema_version":     models.SchemaVersion,
+		"min_schema_version": models.MinSchemaVersion,
+	})
 }
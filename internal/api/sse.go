@@ -2,8 +2,15 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/diskspace"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
 )
 
 // SSEEvent represents an SSE event to be broadcast
@@ -35,10 +42,87 @@ func (e *SSEEvent) ToSSE() string {
 	return "data: " + string(jsonBytes) + "\n\n"
 }
 
+// Drop policies applied when a subscriber's buffer is full
+const (
+	// DropPolicyProgress skips intermediate events but makes room for terminal run events
+	DropPolicyProgress = "progress"
+	// DropPolicyAll skips any event that does not fit in the buffer
+	DropPolicyAll = "all"
+)
+
+// terminalEventTypes are never dropped under DropPolicyProgress
+var terminalEventTypes = map[string]bool{
+	"run_completed": true,
+	"run_cancelled": true,
+}
+
+// runScopedEventTypes are only delivered to subscribers of the run's own stream
+// (/api/runs/:run_id/stream). They are not cached or sent to the global stream
+// to keep high-volume step progress out of the dashboard-wide feed.
+var runScopedEventTypes = map[string]bool{
+	"step_started":   true,
+	"step_completed": true,
+}
+
+// SSEConfig controls subscriber buffering, heartbeats and reconnection
+type SSEConfig struct {
+	BufferSize        int           // Per-client channel buffer
+	HeartbeatInterval time.Duration // Interval between heartbeat comments, below proxies' idle timeouts
+	DropPolicy        string        // DropPolicyProgress or DropPolicyAll
+	RetryInterval     time.Duration // Reconnection delay sent to clients in the retry field
+}
+
+// DefaultSSEConfig returns the default SSE configuration
+func DefaultSSEConfig() SSEConfig {
+	return SSEConfig{
+		BufferSize:        100,
+		HeartbeatInterval: 15 * time.Second,
+		DropPolicy:        DropPolicyProgress,
+		RetryInterval:     3 * time.Second,
+	}
+}
+
+// Validate checks the SSE configuration and fills in defaults for zero values
+func (c *SSEConfig) Validate() error {
+	defaults := DefaultSSEConfig()
+	if c.BufferSize <= 0 {
+		c.BufferSize = defaults.BufferSize
+	}
+	if c.HeartbeatInterval <= 0 {
+		c.HeartbeatInterval = defaults.HeartbeatInterval
+	}
+	if c.DropPolicy == "" {
+		c.DropPolicy = defaults.DropPolicy
+	}
+	if c.RetryInterval <= 0 {
+		c.RetryInterval = defaults.RetryInterval
+	}
+	if c.DropPolicy != DropPolicyProgress && c.DropPolicy != DropPolicyAll {
+		return fmt.Errorf("invalid SSE drop policy: %s (must be '%s' or '%s')", c.DropPolicy, DropPolicyProgress, DropPolicyAll)
+	}
+	return nil
+}
+
+// SSEHubMetrics is a snapshot of hub counters
+type SSEHubMetrics struct {
+	GlobalClients int
+	RunClients    int
+	EventsEmitted int64
+	EventsDropped int64
+	EventsEvicted int64
+}
+
 // SSEHub manages SSE subscriptions and event broadcasting
 type SSEHub struct {
 	mu sync.RWMutex
 
+	config SSEConfig
+
+	// Counters exposed on /metrics
+	eventsEmitted atomic.Int64
+	eventsDropped atomic.Int64
+	eventsEvicted atomic.Int64
+
 	// Global subscribers (receive all events)
 	globalSubscribers map[chan string]bool
 
@@ -53,21 +137,26 @@ type SSEHub struct {
 
 	// Max events to cache per run
 	maxCacheSize int
+
+	// Latest step_started event per run and test, for late run subscribers
+	activeSteps map[string]map[string]string
 }
 
 // NewSSEHub creates a new SSE hub
-func NewSSEHub() *SSEHub {
+func NewSSEHub(config SSEConfig) *SSEHub {
 	return &SSEHub{
+		config:            config,
 		globalSubscribers: make(map[chan string]bool),
 		runSubscribers:    make(map[string]map[chan string]bool),
 		eventCache:        make(map[string][]string),
 		maxCacheSize:      100,
+		activeSteps:       make(map[string]map[string]string),
 	}
 }
 
 // SubscribeGlobal adds a subscriber to the global event stream
 func (h *SSEHub) SubscribeGlobal() chan string {
-	ch := make(chan string, 100) // Buffered to avoid blocking
+	ch := make(chan string, h.config.BufferSize) // Buffered to avoid blocking
 	h.mu.Lock()
 	h.globalSubscribers[ch] = true
 	h.mu.Unlock()
@@ -84,7 +173,7 @@ func (h *SSEHub) UnsubscribeGlobal(ch chan string) {
 
 // SubscribeRun adds a subscriber to a specific run's event stream
 func (h *SSEHub) SubscribeRun(runID string) chan string {
-	ch := make(chan string, 100)
+	ch := make(chan string, h.config.BufferSize)
 	h.mu.Lock()
 	if h.runSubscribers[runID] == nil {
 		h.runSubscribers[runID] = make(map[chan string]bool)
@@ -110,10 +199,23 @@ func (h *SSEHub) UnsubscribeRun(runID string, ch chan string) {
 // Emit broadcasts an event to all relevant subscribers
 func (h *SSEHub) Emit(event *SSEEvent, runID string) {
 	sseData := event.ToSSE()
+	terminal := terminalEventTypes[event.Type]
+	h.eventsEmitted.Add(1)
 
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if runID != "" {
+		h.trackActiveStep(event, runID, sseData)
+	}
+
+	if runScopedEventTypes[event.Type] {
+		for ch := range h.runSubscribers[runID] {
+			h.send(ch, sseData, false)
+		}
+		return
+	}
+
 	// Cache event for late subscribers
 	if runID != "" {
 		h.eventCache[runID] = append(h.eventCache[runID], sseData)
@@ -126,23 +228,145 @@ func (h *SSEHub) Emit(event *SSEEvent, runID string) {
 	if runID != "" {
 		if subs := h.runSubscribers[runID]; subs != nil {
 			for ch := range subs {
-				select {
-				case ch <- sseData:
-				default:
-					// Channel full, skip (non-blocking)
-				}
+				h.send(ch, sseData, terminal)
 			}
 		}
 	}
 
 	// Send to global subscribers
 	for ch := range h.globalSubscribers {
+		h.send(ch, sseData, terminal)
+	}
+}
+
+// trackActiveStep records the step each test is currently executing.
+// Callers must hold h.mu.
+func (h *SSEHub) trackActiveStep(event *SSEEvent, runID, sseData string) {
+	testID, _ := event.Data["test_id"].(string)
+
+	switch {
+	case event.Type == "step_started" && testID != "":
+		if h.activeSteps[runID] == nil {
+			h.activeSteps[runID] = make(map[string]string)
+		}
+		h.activeSteps[runID][testID] = sseData
+	case (event.Type == "step_completed" || event.Type == "test_completed") && testID != "":
+		if steps := h.activeSteps[runID]; steps != nil {
+			delete(steps, testID)
+			if len(steps) == 0 {
+				delete(h.activeSteps, runID)
+			}
+		}
+	case terminalEventTypes[event.Type]:
+		delete(h.activeSteps, runID)
+	}
+}
+
+// GetActiveSteps returns the step_started event of each test in the run that
+// is still executing a step
+func (h *SSEHub) GetActiveSteps(runID string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	testIDs := make([]string, 0, len(h.activeSteps[runID]))
+	for testID := range h.activeSteps[runID] {
+		testIDs = append(testIDs, testID)
+	}
+	sort.Strings(testIDs)
+
+	events := make([]string, len(testIDs))
+	for i, testID := range testIDs {
+		events[i] = h.activeSteps[runID][testID]
+	}
+	return events
+}
+
+// send delivers an event without blocking, applying the drop policy when
+// the subscriber's buffer is full
+func (h *SSEHub) send(ch chan string, sseData string, terminal bool) {
+	select {
+	case ch <- sseData:
+		return
+	default:
+	}
+
+	if !terminal || h.config.DropPolicy != DropPolicyProgress {
+		h.eventsDropped.Add(1)
+		return
+	}
+
+	// Evict the oldest buffered progress event to make room for the terminal
+	// event. Buffered terminal events (of other runs, on the global stream)
+	// are kept, so the buffer is drained and refilled in order; Emit holds
+	// h.mu, so the subscriber's reader is the only other party.
+	buffered := make([]string, 0, cap(ch))
+drain:
+	for len(buffered) < cap(ch) {
 		select {
-		case ch <- sseData:
+		case event := <-ch:
+			buffered = append(buffered, event)
 		default:
-			// Channel full, skip (non-blocking)
+			break drain
+		}
+	}
+	evicted := false
+	for i, event := range buffered {
+		if !isTerminalSSE(event) {
+			buffered = append(buffered[:i], buffered[i+1:]...)
+			evicted = true
+			break
 		}
 	}
+	if evicted {
+		h.eventsEvicted.Add(1)
+	}
+	for _, event := range append(buffered, sseData) {
+		select {
+		case ch <- event:
+		default:
+			// Only buffered terminal events left: the new one does not fit
+			h.eventsDropped.Add(1)
+		}
+	}
+}
+
+// isTerminalSSE reports whether a formatted SSE message is a terminal run event
+func isTerminalSSE(sseData string) bool {
+	var event struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(sseData, "data: "), "\n\n")), &event); err != nil {
+		return false
+	}
+	return terminalEventTypes[event.Type]
+}
+
+// Metrics returns a snapshot of the hub's client and event counters
+func (h *SSEHub) Metrics() SSEHubMetrics {
+	h.mu.RLock()
+	runClients := 0
+	for _, subs := range h.runSubscribers {
+		runClients += len(subs)
+	}
+	globalClients := len(h.globalSubscribers)
+	h.mu.RUnlock()
+
+	return SSEHubMetrics{
+		GlobalClients: globalClients,
+		RunClients:    runClients,
+		EventsEmitted: h.eventsEmitted.Load(),
+		EventsDropped: h.eventsDropped.Load(),
+		EventsEvicted: h.eventsEvicted.Load(),
+	}
+}
+
+// HeartbeatInterval returns the configured heartbeat interval
+func (h *SSEHub) HeartbeatInterval() time.Duration {
+	return h.config.HeartbeatInterval
+}
+
+// RetryInterval returns the reconnection delay sent to clients
+func (h *SSEHub) RetryInterval() time.Duration {
+	return h.config.RetryInterval
 }
 
 // SetCurrentRun sets the current run ID
@@ -175,6 +399,7 @@ func (h *SSEHub) GetCachedEvents(runID string) []string {
 func (h *SSEHub) ClearCache(runID string) {
 	h.mu.Lock()
 	delete(h.eventCache, runID)
+	delete(h.activeSteps, runID)
 	h.mu.Unlock()
 }
 
@@ -229,6 +454,54 @@ func (h *SSEHub) EmitCancelRequested(runID string) {
 	}), runID)
 }
 
+// EmitTestCancelRequested broadcasts a test_cancel_requested event for a
+// running test cancelled on its own
+func (h *SSEHub) EmitTestCancelRequested(runID, testID string) {
+	h.Emit(NewSSEEvent("test_cancel_requested", map[string]any{
+		"run_id":  runID,
+		"test_id": testID,
+	}), runID)
+}
+
+// EmitRunQueued broadcasts a run_queued event with the position of a run in
+// its suite's run queue (1 = next to start)
+func (h *SSEHub) EmitRunQueued(runID string, suiteID int64, position int) {
+	h.Emit(NewSSEEvent("run_queued", map[string]any{
+		"run_id":         runID,
+		"suite_id":       suiteID,
+		"queue_position": position,
+	}), runID)
+}
+
+// EmitQueuedRunFinished broadcasts run_completed or run_cancelled for a run
+// that finished without leaving the queue. Unlike EmitRunCompleted it leaves
+// the current run alone, since a queued run never became current.
+func (h *SSEHub) EmitQueuedRunFinished(runID string, status models.RunStatus) {
+	eventType := "run_completed"
+	if status == models.RunStatusCancelled {
+		eventType = "run_cancelled"
+	}
+	h.Emit(NewSSEEvent(eventType, map[string]any{
+		"run_id":      runID,
+		"status":      status,
+		"passed":      0,
+		"failed":      0,
+		"skipped":     0,
+		"duration_ms": 0,
+	}), runID)
+}
+
+// EmitDiskSpaceLow warns subscribers of a running run that a filesystem it
+// writes to is below the minimum free space
+func (h *SSEHub) EmitDiskSpaceLow(runID string, low diskspace.Low) {
+	h.Emit(NewSSEEvent("disk_space_low", map[string]any{
+		"run_id":  runID,
+		"path":    low.Path,
+		"free_mb": low.FreeMB,
+		"min_mb":  low.MinMB,
+	}), runID)
+}
+
 // EmitRunCancelled broadcasts a run_cancelled event (after CLI terminates workers)
 func (h *SSEHub) EmitRunCancelled(runID string, passed, failed, skipped int, durationMS int64) {
 	h.Emit(NewSSEEvent("run_cancelled", map[string]any{
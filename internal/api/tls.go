@@ -0,0 +1,43 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// cleanBasePath normalizes --base-path to a path with a leading and no
+// trailing slash, or "" for the root
+func cleanBasePath(basePath string) (string, error) {
+	if basePath == "" {
+		return "", nil
+	}
+	if strings.ContainsAny(basePath, "?#*: ") {
+		return "", fmt.Errorf("invalid base path %q", basePath)
+	}
+	cleaned := path.Clean("/" + basePath)
+	if cleaned == "/" {
+		return "", nil
+	}
+	return cleaned, nil
+}
+
+// tlsCertSHA256 loads the TLS certificate and key and returns the SHA-256
+// fingerprint of the certificate; "" without TLS
+func tlsCertSHA256(certFile, keyFile string) (string, error) {
+	if certFile == "" && keyFile == "" {
+		return "", nil
+	}
+	if certFile == "" || keyFile == "" {
+		return "", fmt.Errorf("TLS needs both a certificate and a key")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	sum := sha256.Sum256(cert.Certificate[0])
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// DefaultStaleAfter is how long a running test may go without a runner
+// heartbeat before the watchdog marks it crashed
+const DefaultStaleAfter = 5 * time.Minute
+
+// runWatchdog periodically marks tests whose runner stopped sending heartbeats
+// as crashed and completes runs whose CLI went away
+func (s *Server) runWatchdog(staleAfter time.Duration) {
+	interval := staleAfter / 4
+	if interval > 30*time.Second {
+		interval = 30 * time.Second
+	}
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.checkStale(staleAfter)
+	}
+}
+
+// checkStale runs one watchdog pass
+func (s *Server) checkStale(staleAfter time.Duration) {
+	ctx := context.Background()
+	cutoff := time.Now().Add(-staleAfter)
+
+	crashed, err := s.repo.MarkStaleTestsCrashed(ctx, cutoff, fmt.Sprintf("Runner stopped responding (no heartbeat for %s)", staleAfter))
+	if err != nil {
+		slog.Error("Watchdog failed to check stale tests", "error", err)
+	}
+	for _, t := range crashed {
+		slog.Warn("Watchdog marked test crashed", "run_id", t.RunID, "test_id", t.TestID, "stale_after", staleAfter.String())
+		s.sseHub.EmitTestCompleted(t.RunID, t.TestID, TestStatusCrashed, t.DurationMS, 0, 0)
+	}
+
+	runIDs, err := s.repo.FinalizeAbandonedRuns(ctx, cutoff, fmt.Sprintf("Run abandoned (no activity for %s)", staleAfter))
+	if err != nil {
+		slog.Error("Watchdog failed to finalize abandoned runs", "error", err)
+	}
+	for _, runID := range runIDs {
+		slog.Warn("Watchdog finalized abandoned run", "run_id", runID)
+		run, err := s.repo.GetRunByID(ctx, runID)
+		if err != nil || run == nil {
+			continue
+		}
+		durationMS := int64(0)
+		if run.DurationMS.Valid {
+			durationMS = run.DurationMS.Int64
+		}
+		s.sseHub.EmitRunCompleted(runID, run.Passed, run.Failed, run.Skipped, durationMS)
+	}
+}
+
+// recoverOrphanedRuns cancels runs left running from before a server restart
+// whose CLI and runners have stopped reporting. Running tests are marked
+// crashed and pending tests skipped. Events are cached, so dashboards that
+// reconnect see the runs finish.
+func (s *Server) recoverOrphanedRuns(staleAfter time.Duration) {
+	ctx := context.Background()
+	orphans, err := s.repo.RecoverOrphanedRuns(ctx, time.Now().Add(-staleAfter),
+		fmt.Sprintf("Orphaned by API server restart (no activity for %s)", staleAfter))
+	if err != nil {
+		slog.Error("Failed to recover orphaned runs", "error", err)
+	}
+
+	for _, orphan := range orphans {
+		slog.Warn("Cancelled orphaned run", "run_id", orphan.RunID, "crashed_tests", len(orphan.CrashedTests))
+		for _, t := range orphan.CrashedTests {
+			s.sseHub.EmitTestCompleted(t.RunID, t.TestID, TestStatusCrashed, t.DurationMS, 0, 0)
+		}
+		run, err := s.repo.GetRunByID(ctx, orphan.RunID)
+		if err != nil || run == nil {
+			continue
+		}
+		durationMS := int64(0)
+		if run.DurationMS.Valid {
+			durationMS = run.DurationMS.Int64
+		}
+		s.sseHub.EmitRunCancelled(orphan.RunID, run.Passed, run.Failed, run.Skipped, durationMS)
+	}
+}
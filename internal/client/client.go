@@ -3,11 +3,22 @@ package client
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
 )
 
 // Client is an API client for the tsuite server
@@ -19,15 +30,41 @@ type Client struct {
 // NewClient creates a new API client
 func NewClient(baseURL string) *Client {
 	return &Client{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+		baseURL:    baseURL,
+		httpClient: newHTTPClient(),
+	}
+}
+
+// newHTTPClient returns the HTTP client of API clients. With
+// TSUITE_API_CERT_SHA256 set, a TLS server is trusted if it presents the
+// certificate of that fingerprint, whatever host name it was reached at.
+func newHTTPClient() *http.Client {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	pin := strings.ToLower(strings.ReplaceAll(os.Getenv(runner.EnvAPICertSHA256), ":", ""))
+	if pin == "" {
+		return httpClient
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		// Verified below against the pinned certificate instead
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) > 0 {
+				sum := sha256.Sum256(rawCerts[0])
+				if hex.EncodeToString(sum[:]) == pin {
+					return nil
+				}
+			}
+			return fmt.Errorf("API server certificate does not match %s", runner.EnvAPICertSHA256)
 		},
 	}
+	httpClient.Transport = transport
+	return httpClient
 }
 
 // CreateRunRequest contains the parameters for creating a run
 type CreateRunRequest struct {
+	RunID                string     `json:"run_id,omitempty"` // Default: generated by the server
 	SuiteID              int64      `json:"suite_id"`
 	SuiteName            string     `json:"suite_name"`
 	DisplayName          string     `json:"display_name"`
@@ -37,6 +74,12 @@ type CreateRunRequest struct {
 	DockerImage          string     `json:"docker_image"`
 	TotalTests           int        `json:"total_tests"`
 	Mode                 string     `json:"mode"`
+	ParentRunID          string     `json:"parent_run_id,omitempty"`
+	PipelineRunID        string     `json:"pipeline_run_id,omitempty"`
+	RunnerVersion        string     `json:"runner_version,omitempty"`
+	RunnerSHA256         string     `json:"runner_sha256,omitempty"`
+	Workers              int        `json:"workers,omitempty"` // Parallel test runners
+	Profile              string     `json:"profile,omitempty"` // Run profile from config.yaml
 	Tests                []TestInfo `json:"tests"`
 }
 
@@ -47,6 +90,8 @@ type TestInfo struct {
 	TestCase string   `json:"test_case"`
 	Name     string   `json:"name"`
 	Tags     []string `json:"tags"`
+	Covers   []string `json:"covers,omitempty"` // Feature IDs from covers: in test.yaml
+	Owners   []string `json:"owners,omitempty"` // From test.yaml, else config.yaml owners
 }
 
 // CreateRunResponse is the response from creating a run
@@ -88,8 +133,15 @@ type UpdateTestStatusRequest struct {
 	Status       string `json:"status"`
 	DurationMS   *int64 `json:"duration_ms,omitempty"`
 	ErrorMessage string `json:"error_message,omitempty"`
+	SkipReason   string `json:"skip_reason,omitempty"`
 	StepsPassed  *int   `json:"steps_passed,omitempty"`
 	StepsFailed  *int   `json:"steps_failed,omitempty"`
+	// Container the test ran in (docker mode)
+	Container *runner.ContainerInfo `json:"container,omitempty"`
+	// Kept workdir of a failed test
+	WorkspacePath string `json:"workspace_path,omitempty"`
+	// Values recorded as the test's captured values, by key
+	Captured map[string]string `json:"captured,omitempty"`
 }
 
 // UpdateTestStatus updates the status of a test
@@ -120,9 +172,20 @@ func (c *Client) UpdateTestStatus(runID, testID string, req *UpdateTestStatusReq
 	return nil
 }
 
+// CompleteRunRequest contains what the CLI reports when a run finishes
+type CompleteRunRequest struct {
+	SmokeStatus   string `json:"smoke_status,omitempty"`    // passed, failed or over_budget (smoke runs only)
+	SmokeBudgetMS int64  `json:"smoke_budget_ms,omitempty"` // Time budget of a smoke run
+}
+
 // CompleteRun marks a run as completed
-func (c *Client) CompleteRun(runID string) error {
-	resp, err := c.httpClient.Post(c.baseURL+"/api/runs/"+runID+"/complete", "application/json", nil)
+func (c *Client) CompleteRun(runID string, req *CompleteRunRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+"/api/runs/"+runID+"/complete", "application/json", bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
@@ -158,35 +221,97 @@ func (c *Client) CancelRun(runID string) error {
 	return nil
 }
 
-// CheckCancelRequested checks if cancellation has been requested for a run
-func (c *Client) CheckCancelRequested(runID string) (bool, error) {
+// CheckCancelRequests checks if cancellation has been requested for a run,
+// and returns the tests of the run cancelled on their own
+func (c *Client) CheckCancelRequests(runID string) (bool, []string, error) {
 	resp, err := c.httpClient.Get(c.baseURL + "/api/runs/" + runID)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return false, nil
+		return false, nil, nil
 	}
 
-	var result map[string]any
+	var result struct {
+		CancelRequested bool `json:"cancel_requested"`
+		Tests           []struct {
+			TestID          string `json:"test_id"`
+			CancelRequested bool   `json:"cancel_requested"`
+		} `json:"tests"`
+	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return false, err
+		return false, nil, err
+	}
+
+	var tests []string
+	for _, t := range result.Tests {
+		if t.CancelRequested {
+			tests = append(tests, t.TestID)
+		}
 	}
+	return result.CancelRequested, tests, nil
+}
+
+// NotReadyError is returned by HealthCheck when the API server is up but
+// one of its readiness checks fails
+type NotReadyError struct {
+	Failing []string // "check: detail" of each failing check
+}
 
-	if cancelled, ok := result["cancel_requested"].(bool); ok {
-		return cancelled, nil
+func (e *NotReadyError) Error() string {
+	if len(e.Failing) == 0 {
+		return "not ready"
 	}
+	return "not ready: " + strings.Join(e.Failing, "; ")
+}
 
-	return false, nil
+// readiness is the response of GET /readyz
+type readiness struct {
+	Status string `json:"status"`
+	Checks map[string]struct {
+		Status string `json:"status"`
+		Detail string `json:"detail"`
+	} `json:"checks"`
 }
 
-// HealthCheck checks if the API server is healthy
+// HealthCheck checks if the API server is ready to record runs. Servers
+// without /readyz are checked at /health.
 func (c *Client) HealthCheck() error {
+	resp, err := c.httpClient.Get(c.baseURL + "/readyz")
+	if err != nil {
+		return fmt.Errorf("not reachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return c.liveCheck()
+	case http.StatusServiceUnavailable:
+		var ready readiness
+		if err := json.NewDecoder(resp.Body).Decode(&ready); err != nil {
+			return fmt.Errorf("health check failed: %s", resp.Status)
+		}
+		notReady := &NotReadyError{}
+		for name, check := range ready.Checks {
+			if check.Status == "failing" {
+				notReady.Failing = append(notReady.Failing, name+": "+check.Detail)
+			}
+		}
+		slices.Sort(notReady.Failing)
+		return notReady
+	}
+	return fmt.Errorf("health check failed: %s", resp.Status)
+}
+
+// liveCheck checks GET /health of servers that predate /readyz
+func (c *Client) liveCheck() error {
 	resp, err := c.httpClient.Get(c.baseURL + "/health")
 	if err != nil {
-		return err
+		return fmt.Errorf("not reachable: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -265,3 +390,290 @@ func (c *Client) UpsertSuite(req *SyncSuiteRequest) (*SyncSuiteResponse, error)
 		FolderPath: req.FolderPath,
 	}, nil
 }
+
+// RunInfo is the subset of a run the CLI needs to rerun its tests
+type RunInfo struct {
+	RunID     string `json:"run_id"`
+	SuiteID   *int64 `json:"suite_id"`
+	SuiteName string `json:"suite_name"`
+	Status    string `json:"status"`
+}
+
+// GetRun fetches a run by ID. A unique prefix of a recent run ID (as printed
+// by tsuite run) is also accepted.
+func (c *Client) GetRun(runID string) (*RunInfo, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/runs/" + url.PathEscape(runID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		var run RunInfo
+		if err := json.NewDecoder(resp.Body).Decode(&run); err != nil {
+			return nil, err
+		}
+		return &run, nil
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get run: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	// Fall back to matching a prefix among recent runs
+	listResp, err := c.httpClient.Get(c.baseURL + "/api/runs?limit=100")
+	if err != nil {
+		return nil, err
+	}
+	defer listResp.Body.Close()
+
+	var list struct {
+		Runs []RunInfo `json:"runs"`
+	}
+	if err := json.NewDecoder(listResp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+
+	var match *RunInfo
+	for i, run := range list.Runs {
+		if strings.HasPrefix(run.RunID, runID) {
+			if match != nil {
+				return nil, fmt.Errorf("run ID prefix %q is ambiguous", runID)
+			}
+			match = &list.Runs[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("run not found: %s", runID)
+	}
+	return match, nil
+}
+
+// RunTest is a test result within a run
+type RunTest struct {
+	TestID       string `json:"test_id"`
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	DurationMS   *int64 `json:"duration_ms"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// GetRunTests lists the tests of a run, optionally only those with the given statuses
+func (c *Client) GetRunTests(runID string, statuses ...string) ([]RunTest, error) {
+	endpoint := c.baseURL + "/api/runs/" + url.PathEscape(runID) + "/tests"
+	if len(statuses) > 0 {
+		endpoint += "?status=" + url.QueryEscape(strings.Join(statuses, ","))
+	}
+
+	resp, err := c.httpClient.Get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get run tests: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result struct {
+		Tests []RunTest `json:"tests"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Tests, nil
+}
+
+// Assertion is an assertion result of a test
+type Assertion struct {
+	Expression string `json:"expression"`
+	Message    string `json:"message"`
+	Passed     bool   `json:"passed"`
+	Actual     string `json:"actual_value"`
+	Expected   string `json:"expected_value"`
+}
+
+// GetTestAssertions returns the assertion results of a test in a run
+func (c *Client) GetTestAssertions(runID, testID string) ([]Assertion, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/runs/" + url.PathEscape(runID) + "/test/" + testID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get test: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result struct {
+		Assertions []Assertion `json:"assertions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Assertions, nil
+}
+
+// GetSuite fetches a registered suite by ID
+func (c *Client) GetSuite(id int64) (*SyncSuiteResponse, error) {
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/api/suites/%d", c.baseURL, id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get suite: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var suite SyncSuiteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&suite); err != nil {
+		return nil, err
+	}
+	return &suite, nil
+}
+
+// CreatePipelineRunRequest contains the parameters for creating a pipeline run
+type CreatePipelineRunRequest struct {
+	Name        string `json:"name"`
+	FilePath    string `json:"file_path"`
+	Mode        string `json:"mode"`
+	TotalSuites int    `json:"total_suites"`
+}
+
+// PipelineRunInfo is the subset of a pipeline run the CLI needs
+type PipelineRunInfo struct {
+	PipelineRunID   string `json:"pipeline_run_id"`
+	Name            string `json:"name"`
+	Status          string `json:"status"`
+	CancelRequested bool   `json:"cancel_requested"`
+}
+
+// CreatePipelineRun creates a pipeline run that suite runs can link to
+func (c *Client) CreatePipelineRun(req *CreatePipelineRunRequest) (*PipelineRunInfo, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+"/api/pipelines/runs", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to create pipeline run: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result PipelineRunInfo
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPipelineRun fetches a pipeline run by ID
+func (c *Client) GetPipelineRun(id string) (*PipelineRunInfo, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/pipelines/runs/" + url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get pipeline run: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result PipelineRunInfo
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CompletePipelineRun marks a pipeline run finished. The server derives its
+// status from the suite runs and suitesFailed, the suites whose tsuite run
+// failed.
+func (c *Client) CompletePipelineRun(id string, suitesFailed int) (*PipelineRunInfo, error) {
+	body, err := json.Marshal(map[string]int{"suites_failed": suitesFailed})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+"/api/pipelines/runs/"+url.PathEscape(id)+"/complete", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to complete pipeline run: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result PipelineRunInfo
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CancelPipelineRun requests cancellation of a pipeline run and its suite runs
+func (c *Client) CancelPipelineRun(id string) error {
+	resp, err := c.httpClient.Post(c.baseURL+"/api/pipelines/runs/"+url.PathEscape(id)+"/cancel", "application/json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to cancel pipeline run: %s - %s", resp.Status, string(bodyBytes))
+	}
+	return nil
+}
+
+// VersionInfo is the API server's version handshake
+type VersionInfo struct {
+	Version          string `json:"version"`
+	SchemaVersion    int    `json:"schema_version"`
+	MinSchemaVersion int    `json:"min_schema_version"`
+}
+
+// CheckVersion verifies that the API server speaks a payload schema this
+// client understands. It returns an error with the fix when it does not.
+func (c *Client) CheckVersion() (*VersionInfo, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/version")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("API server at %s predates version negotiation (schema %d required); restart it with this tsuite binary: tsuite stop && tsuite api -d", c.baseURL, models.SchemaVersion)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("version check failed: %s", resp.Status)
+	}
+
+	var info VersionInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("invalid version response: %w", err)
+	}
+
+	switch {
+	case models.SchemaVersion > info.SchemaVersion:
+		return &info, fmt.Errorf("API server %s at %s uses schema %d but this tsuite uses schema %d; restart the server with this tsuite binary: tsuite stop && tsuite api -d",
+			info.Version, c.baseURL, info.SchemaVersion, models.SchemaVersion)
+	case models.SchemaVersion < info.MinSchemaVersion:
+		return &info, fmt.Errorf("API server %s at %s requires schema %d or newer but this tsuite uses schema %d; upgrade tsuite to %s",
+			info.Version, c.baseURL, info.MinSchemaVersion, models.SchemaVersion, info.Version)
+	}
+	return &info, nil
+}
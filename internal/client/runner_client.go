@@ -3,10 +3,13 @@ package client
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
@@ -24,27 +27,29 @@ type RunnerClient struct {
 // NewRunnerClient creates a new runner API client
 func NewRunnerClient(baseURL, runID, testID string) *RunnerClient {
 	return &RunnerClient{
-		baseURL: baseURL,
-		runID:   runID,
-		testID:  testID,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:    baseURL,
+		runID:      runID,
+		testID:     testID,
+		httpClient: newHTTPClient(),
 	}
 }
 
 // StepReport represents a step result for API reporting
 type StepReport struct {
-	Phase      string `json:"phase"`
-	Index      int    `json:"index"`
-	Handler    string `json:"handler"`
-	Name       string `json:"name"`
-	Success    bool   `json:"success"`
-	ExitCode   int    `json:"exit_code"`
-	Stdout     string `json:"stdout"`
-	Stderr     string `json:"stderr"`
-	Error      string `json:"error,omitempty"`
-	DurationMS int64  `json:"duration_ms,omitempty"`
+	Phase      string   `json:"phase"`
+	Index      int      `json:"index"`
+	Handler    string   `json:"handler"`
+	Name       string   `json:"name"`
+	Success    bool     `json:"success"`
+	ExitCode   int      `json:"exit_code"`
+	Stdout     string   `json:"stdout"`
+	Stderr     string   `json:"stderr"`
+	Error      string   `json:"error,omitempty"`
+	DurationMS int64    `json:"duration_ms,omitempty"`
+	Artifacts  []string `json:"artifacts,omitempty"`
+
+	ResolvedCommand string         `json:"resolved_command,omitempty"`
+	ResolvedParams  map[string]any `json:"resolved_params,omitempty"`
 }
 
 // AssertionReport represents an assertion result for API reporting
@@ -55,23 +60,33 @@ type AssertionReport struct {
 	Passed   bool   `json:"passed"`
 	Actual   string `json:"actual"`
 	Expected string `json:"expected"`
+	Diff     string `json:"diff,omitempty"`
 }
 
 // TestStatusReport is the full request body for reporting test status
 type TestStatusReport struct {
-	Status       string            `json:"status"`
-	DurationMS   *int64            `json:"duration_ms,omitempty"`
-	ErrorMessage string            `json:"error_message,omitempty"`
-	StepsPassed  *int              `json:"steps_passed,omitempty"`
-	StepsFailed  *int              `json:"steps_failed,omitempty"`
-	Steps        []StepReport      `json:"steps,omitempty"`
-	Assertions   []AssertionReport `json:"assertions,omitempty"`
+	Status           string              `json:"status"`
+	DurationMS       *int64              `json:"duration_ms,omitempty"`
+	ErrorMessage     string              `json:"error_message,omitempty"`
+	SkipReason       string              `json:"skip_reason,omitempty"`
+	StepsPassed      *int                `json:"steps_passed,omitempty"`
+	StepsFailed      *int                `json:"steps_failed,omitempty"`
+	Steps            []StepReport        `json:"steps,omitempty"`
+	StepsAppend      bool                `json:"steps_append,omitempty"`
+	Assertions       []AssertionReport   `json:"assertions,omitempty"`
+	Suggestions      []string            `json:"suggestions,omitempty"`
+	Leaks            *runner.LeakReport  `json:"leaks,omitempty"`
+	DurationBudgetMS *int64              `json:"duration_budget_ms,omitempty"`
+	Environment      *runner.Environment `json:"environment,omitempty"`
+	Captured         map[string]string   `json:"captured,omitempty"`
 }
 
-// ReportTestRunning reports that the test has started running
-func (c *RunnerClient) ReportTestRunning() error {
+// ReportTestRunning reports that the test has started running, along with the
+// environment it runs in
+func (c *RunnerClient) ReportTestRunning(env *runner.Environment) error {
 	return c.sendStatusUpdate(&TestStatusReport{
-		Status: "running",
+		Status:      "running",
+		Environment: env,
 	})
 }
 
@@ -87,6 +102,16 @@ func (c *RunnerClient) ReportTestFailed(result *runner.TestResult) error {
 	return c.sendStatusUpdate(report)
 }
 
+// ReportTestSkipped reports that the test was skipped by a skip_if condition
+func (c *RunnerClient) ReportTestSkipped(result *runner.TestResult) error {
+	durationMS := result.Duration.Milliseconds()
+	return c.sendStatusUpdate(&TestStatusReport{
+		Status:     "skipped",
+		DurationMS: &durationMS,
+		SkipReason: result.SkipReason,
+	})
+}
+
 // buildReport converts a TestResult to a TestStatusReport
 func (c *RunnerClient) buildReport(result *runner.TestResult, status string) *TestStatusReport {
 	// Convert steps
@@ -95,15 +120,19 @@ func (c *RunnerClient) buildReport(result *runner.TestResult, status string) *Te
 	stepsFailed := 0
 	for i, step := range result.Steps {
 		steps[i] = StepReport{
-			Phase:    step.Phase,
-			Index:    step.Index,
-			Handler:  step.Handler,
-			Name:     step.Name,
-			Success:  step.Success,
-			ExitCode: step.ExitCode,
-			Stdout:   step.Stdout,
-			Stderr:   step.Stderr,
-			Error:    step.Error,
+			Phase:     step.Phase,
+			Index:     step.Index,
+			Handler:   step.Handler,
+			Name:      step.Name,
+			Success:   step.Success,
+			ExitCode:  step.ExitCode,
+			Stdout:    step.Stdout,
+			Stderr:    step.Stderr,
+			Error:     step.Error,
+			Artifacts: step.Artifacts,
+
+			ResolvedCommand: step.ResolvedCommand,
+			ResolvedParams:  step.ResolvedParams,
 		}
 		if step.Success {
 			stepsPassed++
@@ -122,28 +151,100 @@ func (c *RunnerClient) buildReport(result *runner.TestResult, status string) *Te
 			Passed:   assertion.Passed,
 			Actual:   assertion.Actual,
 			Expected: assertion.Expected,
+			Diff:     assertion.Diff,
 		}
 	}
 
 	durationMS := result.Duration.Milliseconds()
 
+	var budgetMS *int64
+	if result.DurationBudget > 0 {
+		ms := result.DurationBudget.Milliseconds()
+		budgetMS = &ms
+	}
+
 	return &TestStatusReport{
-		Status:       status,
-		DurationMS:   &durationMS,
-		ErrorMessage: result.Error,
-		StepsPassed:  &stepsPassed,
-		StepsFailed:  &stepsFailed,
-		Steps:        steps,
-		Assertions:   assertions,
+		Status:           status,
+		DurationMS:       &durationMS,
+		ErrorMessage:     result.Error,
+		StepsPassed:      &stepsPassed,
+		StepsFailed:      &stepsFailed,
+		Steps:            steps,
+		Assertions:       assertions,
+		Suggestions:      result.Suggestions,
+		Leaks:            result.Leaks,
+		DurationBudgetMS: budgetMS,
+		Captured:         result.Recorded,
 	}
 }
 
-// sendStatusUpdate sends a status update to the API
+const (
+	// stepChunkBytes is the report size above which steps are sent in
+	// separate chunks before the final status update
+	stepChunkBytes = 1 << 20
+
+	// gzipMinBytes is the body size above which requests are gzip-compressed
+	gzipMinBytes = 64 << 10
+)
+
+// sendStatusUpdate sends a status update to the API. Reports with large step
+// output are split so no single request exceeds the server's body limit.
 func (c *RunnerClient) sendStatusUpdate(report *TestStatusReport) error {
 	body, err := json.Marshal(report)
 	if err != nil {
 		return fmt.Errorf("failed to marshal report: %w", err)
 	}
+	if len(body) <= stepChunkBytes || len(report.Steps) == 0 {
+		return c.patch(body)
+	}
+
+	// Send steps in chunks, then the final report without them
+	var chunk []StepReport
+	chunkSize := 0
+	for _, step := range report.Steps {
+		stepSize := len(step.Stdout) + len(step.Stderr) + len(step.Error) + len(step.ResolvedCommand)
+		if len(chunk) > 0 && chunkSize+stepSize > stepChunkBytes {
+			if err := c.sendSteps(chunk); err != nil {
+				return err
+			}
+			chunk, chunkSize = nil, 0
+		}
+		chunk = append(chunk, step)
+		chunkSize += stepSize
+	}
+	if err := c.sendSteps(chunk); err != nil {
+		return err
+	}
+
+	final := *report
+	final.Steps = nil
+	body, err = json.Marshal(&final)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	return c.patch(body)
+}
+
+// sendSteps appends a chunk of steps to the test without changing its status
+func (c *RunnerClient) sendSteps(steps []StepReport) error {
+	body, err := json.Marshal(&TestStatusReport{Steps: steps, StepsAppend: true})
+	if err != nil {
+		return fmt.Errorf("failed to marshal steps: %w", err)
+	}
+	return c.patch(body)
+}
+
+// patch sends a status update body, gzip-compressing large bodies
+func (c *RunnerClient) patch(body []byte) error {
+	encoding := ""
+	if len(body) > gzipMinBytes {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err == nil && gz.Close() == nil {
+			body = buf.Bytes()
+			encoding = "gzip"
+		}
+	}
 
 	// Use /test/ (singular) with wildcard to handle test_ids containing slashes
 	url := fmt.Sprintf("%s/api/runs/%s/test/%s", c.baseURL, c.runID, c.testID)
@@ -152,6 +253,9 @@ func (c *RunnerClient) sendStatusUpdate(report *TestStatusReport) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -166,3 +270,109 @@ func (c *RunnerClient) sendStatusUpdate(report *TestStatusReport) error {
 
 	return nil
 }
+
+// HeartbeatInterval is how often a running test reports that its runner is alive
+const HeartbeatInterval = 15 * time.Second
+
+// StartHeartbeat sends a heartbeat now and then every interval until the
+// returned stop function is called, so the API server's watchdog can tell a
+// slow test from a runner that died
+func (c *RunnerClient) StartHeartbeat(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			c.sendHeartbeat()
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// sendHeartbeat reports that the test is still running. Failures are ignored;
+// a missed heartbeat only matters if all of them are missed.
+func (c *RunnerClient) sendHeartbeat() {
+	ctx, cancel := context.WithTimeout(context.Background(), stepEventTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/api/runs/%s/test/%s/heartbeat", c.baseURL, c.runID, c.testID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, nil)
+	if err != nil {
+		return
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// stepEventTimeout bounds step progress reports so a slow API server never
+// holds up test execution
+const stepEventTimeout = 2 * time.Second
+
+// StepStarted reports a step_started event (implements runner.StepObserver)
+func (c *RunnerClient) StepStarted(phase string, index int, name, handler string) {
+	c.emitEvent(map[string]any{
+		"type":       "step_started",
+		"run_id":     c.runID,
+		"test_id":    c.testID,
+		"phase":      phase,
+		"step_index": index,
+		"step_name":  name,
+		"handler":    handler,
+	})
+}
+
+// StepCompleted reports a step_completed event (implements runner.StepObserver)
+func (c *RunnerClient) StepCompleted(result runner.StepResult, duration time.Duration) {
+	event := map[string]any{
+		"type":        "step_completed",
+		"run_id":      c.runID,
+		"test_id":     c.testID,
+		"phase":       result.Phase,
+		"step_index":  result.Index,
+		"step_name":   result.Name,
+		"handler":     result.Handler,
+		"success":     result.Success,
+		"duration_ms": duration.Milliseconds(),
+	}
+	if result.Error != "" {
+		event["error"] = result.Error
+	}
+	c.emitEvent(event)
+}
+
+// emitEvent posts a progress event for SSE broadcast. Progress events are
+// best-effort; failures are ignored.
+func (c *RunnerClient) emitEvent(event map[string]any) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stepEventTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/events/emit", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -18,6 +18,26 @@ type SuiteConfig struct {
 	Reports    ReportSettings     `yaml:"reports"`
 	Aliases    map[string]string  `yaml:"aliases"`
 
+	// Named run options, selected with tsuite run --profile
+	Profiles map[string]RunProfile `yaml:"profiles"`
+
+	// Features the suite should cover, for the coverage report
+	Coverage CoverageSettings `yaml:"coverage"`
+
+	// Owners (team, email or Slack handle) by use case or uc/tc test ID; a
+	// test's own owners in test.yaml take precedence
+	Owners map[string][]string `yaml:"owners"`
+
+	// Issues the API server opens for tests that keep failing
+	Issues IssueSettings `yaml:"issues"`
+
+	// Digest of run results the API server emails after runs
+	Email EmailSettings `yaml:"email"`
+
+	// Proxy the tests reach the internet through, and the CA that signs
+	// its certificates
+	Proxy ProxySettings `yaml:"proxy"`
+
 	// Raw map for interpolation access
 	Raw map[string]any `yaml:"-"`
 }
@@ -32,24 +52,178 @@ type SuiteSettings struct {
 type PackageSettings struct {
 	Mode  string         `yaml:"mode"`  // "local", "published", or "auto"
 	Local LocalSettings  `yaml:"local"`
+
+	// meshctl version; meshctl-install steps install it by default
+	CLIVersion string `yaml:"cli_version"`
+
+	// mcp-mesh SDK versions the tests install; recorded with each run
+	// instead of the versions detected in the image or on the host
+	SDKPythonVersion     string `yaml:"sdk_python_version"`
+	SDKTypescriptVersion string `yaml:"sdk_typescript_version"`
+
+	// Installer of pip-install steps without their own: auto (default:
+	// uv with uv.lock, poetry with poetry.lock, else pip), pip, uv or poetry
+	PythonInstaller string `yaml:"python_installer"`
+
+	// Node.js version tests run with, e.g. "20" or "22.3.0": fnm or nvm
+	// provide it in standalone mode, docker.node_images picks the image
+	NodeVersion string `yaml:"node_version"`
+
+	// Package mirrors installs use instead of PyPI and the npm registry;
+	// required by tsuite run --offline
+	Mirror MirrorSettings `yaml:"mirror"`
+}
+
+// MirrorSettings names local package mirrors
+type MirrorSettings struct {
+	PipIndexURL string `yaml:"pip_index_url"` // PyPI simple index, e.g. http://localhost:3141/root/pypi/+simple/
+	NpmRegistry string `yaml:"npm_registry"`  // npm registry, e.g. http://localhost:4873/
 }
 
 // LocalSettings contains paths for local package mode
 type LocalSettings struct {
 	WheelsDir   string `yaml:"wheels_dir"`
 	PackagesDir string `yaml:"packages_dir"`
+
+	// mcp-mesh SDK source checkouts (relative to the suite) built into
+	// wheels and npm tarballs once per run; tests install those instead of
+	// the published packages
+	PythonSource     string `yaml:"python_source"`
+	TypescriptSource string `yaml:"typescript_source"`
 }
 
 // DockerSettings contains Docker configuration
 type DockerSettings struct {
 	BaseImage string `yaml:"base_image"`
 	Network   string `yaml:"network"`
+
+	// Container ports published to the host for every test, e.g. 8000
+	// (random port on 127.0.0.1), "9000/udp" or "8080:8000"
+	PublishPorts []string `yaml:"publish_ports"`
+
+	// Images by packages.node_version (or its major), instead of base_image
+	NodeImages map[string]string `yaml:"node_images"`
+
+	// pip and npm download cache shared by the run's containers: "run"
+	// (default, removed with the run), "persistent" (~/.tsuite/cache) or "off"
+	Cache string `yaml:"cache"`
+}
+
+// ProxySettings configures the HTTP(S) proxy of the tests' steps and
+// containers. Proxies that are unset are taken from the environment of
+// tsuite run.
+type ProxySettings struct {
+	HTTPProxy  string   `yaml:"http_proxy"`
+	HTTPSProxy string   `yaml:"https_proxy"`
+	NoProxy    []string `yaml:"no_proxy"`  // Hosts, .domains and CIDRs reached directly, besides localhost and the API server
+	CABundle   string   `yaml:"ca_bundle"` // PEM file (relative to the suite) of CAs trusted besides the system's
 }
 
 // ExecutionSettings contains test execution configuration
 type ExecutionSettings struct {
-	MaxWorkers int `yaml:"max_workers"`
-	Timeout    int `yaml:"timeout"` // seconds
+	MaxWorkers int      `yaml:"max_workers"`
+	Timeout    int      `yaml:"timeout"`     // seconds
+	LeakChecks bool     `yaml:"leak_checks"` // report resources left behind after post_run
+	LeakIgnore []string `yaml:"leak_ignore"` // workdir glob patterns not reported as leaks
+	CaptureEnv []string `yaml:"capture_env"` // env var names (globs) recorded with each test result
+
+	// Shell of shell steps without their own: bash (default), sh or pwsh.
+	// StrictShell runs them with set -euo pipefail (sh: set -eu).
+	Shell       string `yaml:"shell"`
+	StrictShell bool   `yaml:"strict_shell"`
+
+	// Keep failed tests' workdirs under ~/.tsuite/runs/{run}/{uc}/{tc}/workspace
+	KeepFailedWorkdirs bool `yaml:"keep_failed_workdirs"`
+
+	// Free space (MB) the temp dir and ~/.tsuite need for a run to start
+	// (0 = 500, -1 = no check)
+	MinFreeDiskMB int `yaml:"min_free_disk_mb"`
+
+	// Runs of the suite the API server starts at once; more are queued
+	// (0 = the server's --max-runs-per-suite)
+	MaxConcurrentRuns int `yaml:"max_concurrent_runs"`
+
+	// Resources the tests of a parallel run may use at once; further tests
+	// wait while starting them would exceed the budget
+	Budget ResourceBudget `yaml:"budget"`
+
+	// Priority (high, normal or low) by use case or uc/tc test ID; a test's
+	// own priority in test.yaml takes precedence
+	Priorities map[string]string `yaml:"priorities"`
+}
+
+// ResourceBudget limits what the running tests of a parallel run use
+type ResourceBudget struct {
+	MaxProcesses    int `yaml:"max_processes"`      // Processes of running tests, agents included (standalone mode; 0 = no limit)
+	MemoryPerTestMB int `yaml:"memory_per_test_mb"` // Estimated memory of one running test (0 = no memory budget)
+	MaxMemoryMB     int `yaml:"max_memory_mb"`      // Memory running tests may use (0 = what the host has available)
+}
+
+// RunProfile is a named set of run options. Options given on the command
+// line take precedence.
+type RunProfile struct {
+	Mode     string            `yaml:"mode"`     // "docker" or "standalone"
+	Parallel int               `yaml:"parallel"` // Parallel test runners
+	Tags     []string          `yaml:"tags"`
+	SkipTags []string          `yaml:"skip_tags"`
+	Env      map[string]string `yaml:"env"` // Set for every test
+
+	NodeVersion string `yaml:"node_version"` // Instead of packages.node_version
+}
+
+// CoverageSettings lists the mcp-mesh feature IDs (e.g. registry.tags) tests
+// declare with covers:. Listed features no test covers show as uncovered.
+type CoverageSettings struct {
+	Features []string `yaml:"features"`
+}
+
+// IssueSettings configure the issue tracker integration. When a test has
+// failed AfterFailures runs in a row, the API server opens an issue for it,
+// comments on it while it keeps failing, and closes it once the test passes.
+type IssueSettings struct {
+	Provider      string `yaml:"provider"`       // "github" or "jira"; empty disables the integration
+	AfterFailures int    `yaml:"after_failures"` // Consecutive failed runs that open an issue (0 = 3)
+	KeepOpen      bool   `yaml:"keep_open"`      // Only comment when the test passes again
+	DashboardURL  string `yaml:"dashboard_url"`  // Base URL of the dashboard, for links to runs
+
+	GitHub GitHubIssueSettings `yaml:"github"`
+	Jira   JiraIssueSettings   `yaml:"jira"`
+}
+
+// GitHubIssueSettings select the repository issues are opened in
+type GitHubIssueSettings struct {
+	Repo     string   `yaml:"repo"`      // owner/name
+	TokenEnv string   `yaml:"token_env"` // Environment variable holding the token (default GITHUB_TOKEN)
+	APIURL   string   `yaml:"api_url"`   // Default https://api.github.com (set for GitHub Enterprise)
+	Labels   []string `yaml:"labels"`
+}
+
+// JiraIssueSettings select the Jira project issues are opened in
+type JiraIssueSettings struct {
+	URL             string   `yaml:"url"` // e.g. https://example.atlassian.net
+	Project         string   `yaml:"project"`
+	IssueType       string   `yaml:"issue_type"`       // Default Bug
+	EmailEnv        string   `yaml:"email_env"`        // Environment variable holding the account email (default JIRA_EMAIL)
+	TokenEnv        string   `yaml:"token_env"`        // Environment variable holding the API token (default JIRA_API_TOKEN)
+	CloseTransition string   `yaml:"close_transition"` // Transition that closes issues (default Done)
+	Labels          []string `yaml:"labels"`
+}
+
+// EmailSettings configure the digest the API server emails after a run
+// completes: its summary, failures new since the previous run, flaky tests
+// and the slowest tests
+type EmailSettings struct {
+	To           []string `yaml:"to"` // Recipients; none disables the digest
+	From         string   `yaml:"from"`
+	SMTPHost     string   `yaml:"smtp_host"`
+	SMTPPort     int      `yaml:"smtp_port"`    // Default 587
+	UsernameEnv  string   `yaml:"username_env"` // Environment variable holding the SMTP user (default SMTP_USERNAME)
+	PasswordEnv  string   `yaml:"password_env"` // Environment variable holding the SMTP password (default SMTP_PASSWORD)
+	Profiles     []string `yaml:"profiles"`     // Only after runs with one of these profiles, e.g. nightly (default: every run)
+	Sections     []string `yaml:"sections"`     // summary, new_failures, flaky, slowest (default: all)
+	Slowest      int      `yaml:"slowest"`      // Slowest tests listed (0 = 5)
+	FlakyRuns    int      `yaml:"flaky_runs"`   // Recent runs flaky tests are found in (0 = 10)
+	DashboardURL string   `yaml:"dashboard_url"`
 }
 
 // DefaultSettings contains default values for tests
@@ -72,11 +246,33 @@ type TestConfig struct {
 	Description string              `yaml:"description"`
 	Tags        []string            `yaml:"tags"`
 	Timeout     int                 `yaml:"timeout"`
+	SkipIf      []SkipCondition     `yaml:"skip_if"`
 	PreRun      []Step              `yaml:"pre_run"`
 	Test        []Step              `yaml:"test"`
 	PostRun     []Step              `yaml:"post_run"`
 	Assertions  []Assertion         `yaml:"assertions"`
 
+	// Run all test steps and the assertions even after a step failed
+	ContinueOnFailure bool `yaml:"continue_on_failure"`
+
+	// JSON values validated against JSON Schema files
+	AssertSchema []SchemaAssertion `yaml:"assert_schema"`
+
+	// Assertions re-evaluated until they pass or time out
+	AssertEventually []EventualAssertion `yaml:"assert_eventually"`
+
+	// Expected upper bound on test duration; exceeding it is a warning, not a failure
+	DurationBudgetMS int64 `yaml:"duration_budget_ms"`
+
+	// high tests run before normal (default) and low ones
+	Priority string `yaml:"priority"`
+
+	// mcp-mesh feature IDs the test exercises, e.g. registry.tags
+	Covers []string `yaml:"covers"`
+
+	// Who to tell when the test fails: team, email or Slack handle
+	Owners []string `yaml:"owners"`
+
 	// Raw map for interpolation access
 	Raw map[string]any `yaml:"-"`
 }
@@ -88,8 +284,16 @@ type Step struct {
 	Command      string         `yaml:"command,omitempty"`
 	Workdir      string         `yaml:"workdir,omitempty"`
 	Capture      string         `yaml:"capture,omitempty"`
+	CaptureRegex map[string]string `yaml:"capture_regex,omitempty"` // Variable name -> pattern matched against stdout
 	Timeout      int            `yaml:"timeout,omitempty"`
 	IgnoreErrors bool           `yaml:"ignore_errors,omitempty"`
+	Artifacts    []string       `yaml:"artifacts,omitempty"` // Workdir globs collected after the step
+	Expect       *StepExpect    `yaml:"expect,omitempty"`    // Checked right after the step runs
+
+	// Background steps run concurrently with the steps after them; a later
+	// step with wait_for set to their capture name joins them
+	Background bool   `yaml:"background,omitempty"`
+	WaitFor    string `yaml:"wait_for,omitempty"`
 
 	// Handler-specific fields
 	Path       string         `yaml:"path,omitempty"`        // npm-install, pip-install
@@ -110,10 +314,88 @@ type Step struct {
 	Raw map[string]any `yaml:"-"`
 }
 
+// UnmarshalYAML decodes a step and keeps the raw fields so custom handlers
+// can read fields that Step does not declare
+func (s *Step) UnmarshalYAML(value *yaml.Node) error {
+	type plain Step
+	if err := value.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	return value.Decode(&s.Raw)
+}
+
 // Assertion represents a test assertion
 type Assertion struct {
 	Expr    string `yaml:"expr"`
 	Message string `yaml:"message"`
+
+	// Snapshot assertions compare Value with the golden file
+	// snapshots/<Snapshot>.json (.txt for values that aren't JSON) of the
+	// test case instead of evaluating Expr
+	Snapshot string   `yaml:"snapshot,omitempty"`
+	Value    string   `yaml:"value,omitempty"`
+	Ignore   []string `yaml:"ignore,omitempty"` // JSON paths left out of the comparison, e.g. $.agents[*].uptime
+
+	// Groups hold nested assertions instead of an expression. With
+	// StopOnFailure, the assertions after the group's first failure are
+	// skipped, so failures that follow from it don't clutter the report.
+	Group         string      `yaml:"group,omitempty"`
+	StopOnFailure bool        `yaml:"stop_on_failure,omitempty"`
+	Assertions    []Assertion `yaml:"assertions,omitempty"`
+}
+
+// IsGroup reports whether the assertion is a group of assertions
+func (a Assertion) IsGroup() bool {
+	return a.Group != "" || len(a.Assertions) > 0
+}
+
+// CountAssertions returns the number of assertions, counting the members of
+// groups instead of the groups
+func CountAssertions(assertions []Assertion) int {
+	n := 0
+	for _, a := range assertions {
+		if a.IsGroup() {
+			n += CountAssertions(a.Assertions)
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// SkipCondition skips a test when its expression evaluates true
+type SkipCondition struct {
+	Expr   string `yaml:"expr"`
+	Reason string `yaml:"reason"`
+}
+
+// EventualAssertion is an assertion polled until it passes or times out.
+// The optional step is re-run before each attempt to refresh captured values.
+type EventualAssertion struct {
+	Expr     string `yaml:"expr"`
+	Message  string `yaml:"message"`
+	Timeout  int    `yaml:"timeout"`  // seconds, default 30
+	Interval int    `yaml:"interval"` // seconds, default 2
+	Step     *Step  `yaml:"step,omitempty"`
+}
+
+// SchemaAssertion validates a JSON value against a JSON Schema file
+type SchemaAssertion struct {
+	Value   string `yaml:"value"`
+	Schema  string `yaml:"schema"` // Relative to the suite's fixtures directory
+	Message string `yaml:"message"`
+}
+
+// StepExpect holds inline expectations checked as soon as a step finishes.
+// A failed expectation fails the step. Setting exit_code also makes a
+// non-zero exit a success when it is the expected code.
+type StepExpect struct {
+	ExitCode          *int   `yaml:"exit_code,omitempty"`
+	StdoutContains    string `yaml:"stdout_contains,omitempty"`
+	StdoutNotContains string `yaml:"stdout_not_contains,omitempty"`
+	StdoutMatches     string `yaml:"stdout_matches,omitempty"` // Go regular expression
+	StderrContains    string `yaml:"stderr_contains,omitempty"`
+	StderrNotContains string `yaml:"stderr_not_contains,omitempty"`
 }
 
 // GlobalRoutinesConfig represents global/routines.yaml
@@ -131,6 +413,9 @@ type RoutineDefinition struct {
 	Name        string         `yaml:"name"`
 	Description string         `yaml:"description,omitempty"`
 	Steps       []Step         `yaml:"steps"`
+
+	// Declared parameters; routines without declarations accept any params
+	Params map[string]RoutineParam `yaml:"params,omitempty"`
 }
 
 // LoadSuiteConfig loads config.yaml from a suite path
@@ -181,52 +466,24 @@ func LoadTestConfig(testPath string) (*TestConfig, error) {
 	return &config, nil
 }
 
-// LoadGlobalRoutines loads global/routines.yaml
+// LoadGlobalRoutines loads global/routines.yaml merged with the routine
+// library files in global/routines/
 func LoadGlobalRoutines(suitePath string) (*GlobalRoutinesConfig, error) {
-	routinesPath := filepath.Join(suitePath, "global", "routines.yaml")
-
-	data, err := os.ReadFile(routinesPath)
+	routines, err := loadRoutineLibrary(filepath.Join(suitePath, "global"))
 	if err != nil {
-		if os.IsNotExist(err) {
-			return &GlobalRoutinesConfig{Routines: make(map[string]RoutineDefinition)}, nil
-		}
-		return nil, fmt.Errorf("reading global routines.yaml: %w", err)
-	}
-
-	var config GlobalRoutinesConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("parsing global routines.yaml: %w", err)
-	}
-
-	if config.Routines == nil {
-		config.Routines = make(map[string]RoutineDefinition)
+		return nil, err
 	}
-
-	return &config, nil
+	return &GlobalRoutinesConfig{Routines: routines}, nil
 }
 
-// LoadUseCaseRoutines loads uc_*/routines.yaml
+// LoadUseCaseRoutines loads uc_*/routines.yaml merged with the routine
+// library files in uc_*/routines/
 func LoadUseCaseRoutines(useCasePath string) (*UseCaseRoutinesConfig, error) {
-	routinesPath := filepath.Join(useCasePath, "routines.yaml")
-
-	data, err := os.ReadFile(routinesPath)
+	routines, err := loadRoutineLibrary(useCasePath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return &UseCaseRoutinesConfig{Routines: make(map[string]RoutineDefinition)}, nil
-		}
-		return nil, fmt.Errorf("reading use case routines.yaml: %w", err)
-	}
-
-	var config UseCaseRoutinesConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("parsing use case routines.yaml: %w", err)
+		return nil, err
 	}
-
-	if config.Routines == nil {
-		config.Routines = make(map[string]RoutineDefinition)
-	}
-
-	return &config, nil
+	return &UseCaseRoutinesConfig{Routines: routines}, nil
 }
 
 // ToMap converts SuiteConfig to a map for interpolation
@@ -242,19 +499,31 @@ func (c *SuiteConfig) ToMap() map[string]any {
 		"mode": c.Suite.Mode,
 	}
 	m["packages"] = map[string]any{
-		"mode": c.Packages.Mode,
+		"mode":             c.Packages.Mode,
+		"cli_version":      c.Packages.CLIVersion,
+		"python_installer": c.Packages.PythonInstaller,
+		"node_version":     c.Packages.NodeVersion,
 		"local": map[string]any{
-			"wheels_dir":   c.Packages.Local.WheelsDir,
-			"packages_dir": c.Packages.Local.PackagesDir,
+			"wheels_dir":        c.Packages.Local.WheelsDir,
+			"packages_dir":      c.Packages.Local.PackagesDir,
+			"python_source":     c.Packages.Local.PythonSource,
+			"typescript_source": c.Packages.Local.TypescriptSource,
+		},
+		"mirror": map[string]any{
+			"pip_index_url": c.Packages.Mirror.PipIndexURL,
+			"npm_registry":  c.Packages.Mirror.NpmRegistry,
 		},
 	}
 	m["docker"] = map[string]any{
 		"base_image": c.Docker.BaseImage,
 		"network":    c.Docker.Network,
+		"cache":      c.Docker.Cache,
 	}
 	m["execution"] = map[string]any{
-		"max_workers": c.Execution.MaxWorkers,
-		"timeout":     c.Execution.Timeout,
+		"max_workers":  c.Execution.MaxWorkers,
+		"timeout":      c.Execution.Timeout,
+		"shell":        c.Execution.Shell,
+		"strict_shell": c.Execution.StrictShell,
 	}
 	m["defaults"] = map[string]any{
 		"timeout":  c.Defaults.Timeout,
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Pipeline modes
+const (
+	PipelineSequential = "sequential"
+	PipelineParallel   = "parallel"
+)
+
+// PipelineConfig represents a pipeline file: a set of suites run as one unit
+type PipelineConfig struct {
+	Name          string          `yaml:"name"`            // Default: file name without extension
+	Mode          string          `yaml:"mode"`            // "sequential" (default) or "parallel"
+	StopOnFailure bool            `yaml:"stop_on_failure"` // Sequential only: skip the remaining suites after a failure
+	Suites        []PipelineSuite `yaml:"suites"`
+
+	// Absolute path of the pipeline file
+	Path string `yaml:"-"`
+}
+
+// PipelineSuite is one suite of a pipeline and the filters to run it with
+type PipelineSuite struct {
+	Path     string   `yaml:"path"` // Suite folder, relative to the pipeline file
+	UC       []string `yaml:"uc"`
+	TC       []string `yaml:"tc"`
+	Tags     []string `yaml:"tags"`
+	Parallel int      `yaml:"parallel"` // Parallel test runners (default: suite's max_workers)
+}
+
+// LoadPipelineConfig loads and validates a pipeline file. Suite paths are
+// resolved to absolute paths.
+func LoadPipelineConfig(path string) (*PipelineConfig, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", path, err)
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading pipeline: %w", err)
+	}
+
+	var pipeline PipelineConfig
+	if err := yaml.Unmarshal(data, &pipeline); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(absPath), err)
+	}
+	pipeline.Path = absPath
+
+	if pipeline.Name == "" {
+		pipeline.Name = strings.TrimSuffix(filepath.Base(absPath), filepath.Ext(absPath))
+	}
+	switch pipeline.Mode {
+	case "":
+		pipeline.Mode = PipelineSequential
+	case PipelineSequential, PipelineParallel:
+	default:
+		return nil, fmt.Errorf("pipeline mode must be %s or %s, got %q", PipelineSequential, PipelineParallel, pipeline.Mode)
+	}
+	if len(pipeline.Suites) == 0 {
+		return nil, fmt.Errorf("pipeline %s has no suites", pipeline.Name)
+	}
+
+	dir := filepath.Dir(absPath)
+	for i := range pipeline.Suites {
+		suite := &pipeline.Suites[i]
+		if suite.Path == "" {
+			return nil, fmt.Errorf("suites[%d]: path is required", i)
+		}
+		if !filepath.IsAbs(suite.Path) {
+			suite.Path = filepath.Join(dir, suite.Path)
+		}
+		if _, err := os.Stat(filepath.Join(suite.Path, "config.yaml")); err != nil {
+			return nil, fmt.Errorf("suites[%d]: %s is not a test suite (no config.yaml)", i, suite.Path)
+		}
+		if suite.Parallel < 0 {
+			return nil, fmt.Errorf("suites[%d]: parallel must not be negative", i)
+		}
+	}
+
+	return &pipeline, nil
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadRoutineLibrary loads dir/routines.yaml and every *.yaml/*.yml file in
+// dir/routines/, merged into one set. A routine defined in more than one file
+// is an error. Missing files and directories are not.
+func loadRoutineLibrary(dir string) (map[string]RoutineDefinition, error) {
+	files := []string{filepath.Join(dir, "routines.yaml")}
+	libraryFiles, err := filepath.Glob(filepath.Join(dir, "routines", "*.y*ml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(libraryFiles)
+	files = append(files, libraryFiles...)
+
+	routines := make(map[string]RoutineDefinition)
+	definedIn := make(map[string]string)
+	for _, path := range files {
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		var file struct {
+			Routines map[string]RoutineDefinition `yaml:"routines"`
+		}
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+
+		// Name files as <global|uc>/... in errors
+		rel, _ := filepath.Rel(filepath.Dir(dir), path)
+		for name, routine := range file.Routines {
+			if other, ok := definedIn[name]; ok {
+				return nil, fmt.Errorf("routine %s is defined in both %s and %s", name, other, rel)
+			}
+			definedIn[name] = rel
+			routines[name] = routine
+		}
+	}
+	return routines, nil
+}
+
+// Routine parameter types
+const (
+	ParamTypeAny    = "any"
+	ParamTypeString = "string"
+	ParamTypeInt    = "int"
+	ParamTypeNumber = "number"
+	ParamTypeBool   = "bool"
+	ParamTypeList   = "list"
+	ParamTypeMap    = "map"
+)
+
+// RoutineParam declares a routine parameter
+type RoutineParam struct {
+	Type        string `yaml:"type,omitempty"` // string, int, number, bool, list, map (default: any)
+	Required    bool   `yaml:"required,omitempty"`
+	Default     any    `yaml:"default,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// paramTypeAliases maps accepted type spellings to canonical types
+var paramTypeAliases = map[string]string{
+	"":        ParamTypeAny,
+	"any":     ParamTypeAny,
+	"string":  ParamTypeString,
+	"str":     ParamTypeString,
+	"int":     ParamTypeInt,
+	"integer": ParamTypeInt,
+	"number":  ParamTypeNumber,
+	"float":   ParamTypeNumber,
+	"bool":    ParamTypeBool,
+	"boolean": ParamTypeBool,
+	"list":    ParamTypeList,
+	"array":   ParamTypeList,
+	"map":     ParamTypeMap,
+	"object":  ParamTypeMap,
+}
+
+// ValidateParams checks the params of a call to this routine before the test
+// runs: every required param is given, no undeclared param is passed and
+// literal values match their declared type. Values containing ${...} are
+// type-checked after interpolation by ApplyParams. ref is the routine
+// reference used in error messages (e.g. "global.setup_for_python_agent").
+func (rd RoutineDefinition) ValidateParams(ref string, params map[string]any) error {
+	if len(rd.Params) == 0 {
+		return nil
+	}
+
+	for _, name := range sortedKeys(rd.Params) {
+		decl := rd.Params[name]
+		paramType, ok := paramTypeAliases[strings.ToLower(decl.Type)]
+		if !ok {
+			return fmt.Errorf("routine %s param %s has unknown type %q (use string, int, number, bool, list, map or any)", ref, name, decl.Type)
+		}
+		value, given := params[name]
+		if !given {
+			if decl.Required && decl.Default == nil {
+				return fmt.Errorf("routine %s missing required param %s", ref, name)
+			}
+			continue
+		}
+		if s, ok := value.(string); ok && strings.Contains(s, "${") {
+			continue
+		}
+		if _, err := coerceParam(value, paramType); err != nil {
+			return fmt.Errorf("routine %s param %s: %w", ref, name, err)
+		}
+	}
+
+	for _, name := range sortedKeys(params) {
+		if _, ok := rd.Params[name]; !ok {
+			return fmt.Errorf("routine %s has no param %s (declared: %s)", ref, name, strings.Join(sortedKeys(rd.Params), ", "))
+		}
+	}
+	return nil
+}
+
+// ApplyParams fills in defaults for params that were not passed and converts
+// interpolated values to their declared types. Values still containing
+// unresolved ${...} references are passed through unchanged.
+func (rd RoutineDefinition) ApplyParams(ref string, params map[string]any) (map[string]any, error) {
+	if len(rd.Params) == 0 {
+		return params, nil
+	}
+
+	result := make(map[string]any, len(rd.Params))
+	for k, v := range params {
+		result[k] = v
+	}
+	for name, decl := range rd.Params {
+		value, given := result[name]
+		if !given {
+			if decl.Default == nil {
+				continue
+			}
+			value = decl.Default
+		}
+		if s, ok := value.(string); ok && strings.Contains(s, "${") {
+			result[name] = value
+			continue
+		}
+		converted, err := coerceParam(value, paramTypeAliases[strings.ToLower(decl.Type)])
+		if err != nil {
+			return nil, fmt.Errorf("routine %s param %s: %w", ref, name, err)
+		}
+		result[name] = converted
+	}
+	return result, nil
+}
+
+// coerceParam converts a param value to the given type. Strings (the result
+// of interpolation) are parsed; scalars are accepted for string params.
+func coerceParam(value any, paramType string) (any, error) {
+	switch paramType {
+	case ParamTypeString:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int, int64, float64, bool:
+			return fmt.Sprint(v), nil
+		}
+	case ParamTypeInt:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		case string:
+			if i, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return i, nil
+			}
+		}
+	case ParamTypeNumber:
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f, nil
+			}
+		}
+	case ParamTypeBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b, nil
+			}
+		}
+	case ParamTypeList:
+		if v, ok := value.([]any); ok {
+			return v, nil
+		}
+	case ParamTypeMap:
+		if v, ok := value.(map[string]any); ok {
+			return v, nil
+		}
+	default:
+		return value, nil
+	}
+	if s, ok := value.(string); ok {
+		return nil, fmt.Errorf("expected %s, got string %q", paramType, s)
+	}
+	return nil, fmt.Errorf("expected %s, got %s %v", paramType, paramValueKind(value), value)
+}
+
+// paramValueKind names the YAML kind of a value for error messages
+func paramValueKind(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case int, int64:
+		return "int"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
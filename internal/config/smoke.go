@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSmokeTimeBudget bounds a smoke run when smoke.yaml sets no time_budget
+const DefaultSmokeTimeBudget = 3 * time.Minute
+
+// SmokeConfig represents the suite's optional smoke.yaml: the tests run by
+// tsuite run --smoke in addition to those tagged smoke, and how long they
+// may take together
+type SmokeConfig struct {
+	TimeBudget time.Duration `yaml:"time_budget"` // e.g. 3m (default: DefaultSmokeTimeBudget)
+	Tests      []string      `yaml:"tests"`       // uc/tc test IDs or whole use cases
+}
+
+// LoadSmokeConfig loads smoke.yaml from the suite folder. A suite without one
+// gets the defaults.
+func LoadSmokeConfig(suitePath string) (*SmokeConfig, error) {
+	smoke := &SmokeConfig{}
+	data, err := os.ReadFile(filepath.Join(suitePath, "smoke.yaml"))
+	if errors.Is(err, os.ErrNotExist) {
+		smoke.TimeBudget = DefaultSmokeTimeBudget
+		return smoke, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading smoke.yaml: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, smoke); err != nil {
+		return nil, fmt.Errorf("parsing smoke.yaml: %w", err)
+	}
+	if smoke.TimeBudget < 0 {
+		return nil, fmt.Errorf("smoke.yaml: time_budget must be positive, got %s", smoke.TimeBudget)
+	}
+	if smoke.TimeBudget == 0 {
+		smoke.TimeBudget = DefaultSmokeTimeBudget
+	}
+	return smoke, nil
+}
@@ -4,6 +4,7 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -13,6 +14,7 @@ import (
 
 var (
 	db     *sql.DB
+	dual   *dualDB // db, mirroring writes to the secondary database if configured
 	dbPath string
 	once   sync.Once
 )
@@ -127,6 +129,69 @@ CREATE TABLE IF NOT EXISTS captured_values (
     UNIQUE(test_result_id, key)
 );
 
+-- Former test IDs of renamed/moved tests, so their results stay attributed
+-- to the test under its current ID
+CREATE TABLE IF NOT EXISTS test_aliases (
+    suite_id INTEGER NOT NULL REFERENCES suites(id) ON DELETE CASCADE,
+    old_test_id TEXT NOT NULL,
+    new_test_id TEXT NOT NULL,
+    created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (suite_id, old_test_id)
+);
+
+-- Changes to suite files made through the API (config, tests, routines)
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    suite_id INTEGER REFERENCES suites(id) ON DELETE SET NULL,
+    path TEXT NOT NULL,
+    action TEXT NOT NULL,
+    endpoint TEXT,
+    actor TEXT,
+    diff TEXT,
+    created_at TEXT NOT NULL
+);
+
+-- Pipeline runs: a set of suites run in order or in parallel as one unit.
+-- Their suite runs link back through runs.pipeline_run_id.
+CREATE TABLE IF NOT EXISTS pipeline_runs (
+    pipeline_run_id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    file_path TEXT,
+    mode TEXT NOT NULL DEFAULT 'sequential' CHECK(mode IN ('sequential', 'parallel')),
+    status TEXT NOT NULL DEFAULT 'running',
+    total_suites INTEGER DEFAULT 0,
+    started_at TEXT NOT NULL,
+    finished_at TEXT,
+    duration_ms INTEGER,
+    cancel_requested INTEGER DEFAULT 0
+);
+
+-- Comments left on a run, or on one of its tests (test_id set), e.g. to
+-- mark failures caused by a known infrastructure outage
+CREATE TABLE IF NOT EXISTS run_comments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_id TEXT NOT NULL REFERENCES runs(run_id),
+    test_id TEXT,
+    author TEXT NOT NULL,
+    text TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+-- Issues opened in the suite's issue tracker for tests that kept failing.
+-- closed_at is set once the test passes again.
+CREATE TABLE IF NOT EXISTS test_issues (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    suite_id INTEGER NOT NULL REFERENCES suites(id) ON DELETE CASCADE,
+    test_id TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    issue_key TEXT NOT NULL,
+    url TEXT,
+    opened_run_id TEXT,
+    opened_at TEXT NOT NULL,
+    closed_run_id TEXT,
+    closed_at TEXT
+);
+
 -- Indexes for common queries
 CREATE INDEX IF NOT EXISTS idx_test_results_run ON test_results(run_id);
 CREATE INDEX IF NOT EXISTS idx_test_results_status ON test_results(status);
@@ -134,8 +199,70 @@ CREATE INDEX IF NOT EXISTS idx_step_results_test ON step_results(test_result_id)
 CREATE INDEX IF NOT EXISTS idx_runs_status ON runs(status);
 CREATE INDEX IF NOT EXISTS idx_runs_started ON runs(started_at DESC);
 CREATE INDEX IF NOT EXISTS idx_suites_folder_path ON suites(folder_path);
+CREATE INDEX IF NOT EXISTS idx_audit_log_suite ON audit_log(suite_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_pipeline_runs_started ON pipeline_runs(started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_run_comments_run ON run_comments(run_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_test_issues_test ON test_issues(suite_id, test_id);
 `
 
+// migrations add columns introduced after the base schema.
+// Each column is only added if it does not already exist.
+var migrations = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"test_results", "suggestions", "TEXT"},
+	{"test_results", "leaks", "TEXT"},
+	{"test_results", "duration_budget_ms", "INTEGER"},
+	{"step_results", "artifacts", "TEXT"},
+	{"runs", "parent_run_id", "TEXT"},
+	{"test_results", "last_heartbeat_at", "TEXT"},
+	{"step_results", "resolved_command", "TEXT"},
+	{"step_results", "resolved_params", "TEXT"},
+	{"test_results", "environment", "TEXT"},
+	{"runs", "pipeline_run_id", "TEXT"},
+	{"runs", "runner_version", "TEXT"},
+	{"runs", "runner_sha256", "TEXT"},
+	{"test_results", "container", "TEXT"},
+	{"test_results", "workspace_path", "TEXT"},
+	{"test_results", "queued_at", "TEXT"},
+	{"test_results", "queue_wait_ms", "INTEGER"},
+	{"runs", "workers", "INTEGER"},
+	{"runs", "smoke_status", "TEXT"},
+	{"runs", "smoke_budget_ms", "INTEGER"},
+	{"test_results", "cancel_requested", "INTEGER DEFAULT 0"},
+	{"test_results", "covers", "TEXT"},
+	{"runs", "profile", "TEXT"},
+	{"test_results", "owners", "TEXT"},
+	{"assertion_results", "diff", "TEXT"},
+}
+
+// timestampColumns hold RFC3339 timestamps. Older versions wrote some of them
+// in local time; normalizeTimestamps converts those to UTC.
+var timestampColumns = []struct {
+	table  string
+	column string
+}{
+	{"suites", "last_synced_at"},
+	{"runs", "started_at"},
+	{"runs", "finished_at"},
+	{"test_results", "started_at"},
+	{"test_results", "finished_at"},
+	{"test_results", "last_heartbeat_at"},
+	{"test_results", "queued_at"},
+	{"step_results", "started_at"},
+	{"step_results", "finished_at"},
+	{"captured_values", "captured_at"},
+	{"test_aliases", "created_at"},
+	{"audit_log", "created_at"},
+	{"pipeline_runs", "started_at"},
+	{"pipeline_runs", "finished_at"},
+	{"run_comments", "created_at"},
+	{"test_issues", "opened_at"},
+	{"test_issues", "closed_at"},
+}
+
 // DefaultDBPath returns the default database path (~/.tsuite/results.db)
 func DefaultDBPath() string {
 	home, err := os.UserHomeDir()
@@ -195,21 +322,147 @@ func GetDB() (*sql.DB, error) {
 			initErr = err
 			return
 		}
+
+		dual, initErr = newDualDB(db)
 	})
 
 	return db, initErr
 }
 
-// initSchema creates tables if they don't exist
+// initSchema creates tables if they don't exist and applies column migrations
 func initSchema(db *sql.DB) error {
-	_, err := db.Exec(schema)
-	return err
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		exists, err := columnExists(db, m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec("ALTER TABLE " + m.table + " ADD COLUMN " + m.column + " " + m.definition); err != nil {
+			return err
+		}
+	}
+
+	return migrateData(db)
 }
 
-// Close closes the database connection
+// dataMigrations rewrite existing rows, each once: PRAGMA user_version
+// counts those that ran. Append new ones; never reorder them.
+var dataMigrations = []func(tx *sql.Tx) error{
+	normalizeTimestamps,
+}
+
+// migrateData runs the data migrations the database has not had yet, each
+// in a transaction with the version it brings the database to
+func migrateData(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	for ; version < len(dataMigrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := dataMigrations[version](tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("data migration %d: %w", version+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeTimestamps converts timestamps written with a UTC offset
+// ("+02:00") to UTC ("Z"). Durations of runs finished with mixed offsets were
+// computed wrong; julianday() applies the offsets, so they are recomputed
+// first. Runs that never left the queue keep their zero duration.
+func normalizeTimestamps(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		UPDATE runs SET duration_ms = CAST(ROUND((julianday(finished_at) - julianday(started_at)) * 24 * 60 * 60 * 1000) AS INTEGER)
+		WHERE finished_at IS NOT NULL AND duration_ms IS NOT 0
+		  AND (started_at LIKE '%+__:__' OR started_at LIKE '%-__:__' OR finished_at LIKE '%+__:__' OR finished_at LIKE '%-__:__')
+	`); err != nil {
+		return err
+	}
+
+	for _, c := range timestampColumns {
+		if _, err := tx.Exec("UPDATE " + c.table + " SET " + c.column + " = strftime('%Y-%m-%dT%H:%M:%SZ', " + c.column + ")" +
+			" WHERE " + c.column + " LIKE '%+__:__' OR " + c.column + " LIKE '%-__:__'"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnExists reports whether a table has the given column
+func columnExists(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// Close closes the database connection, after the writes queued for a
+// secondary database reached it
 func Close() error {
+	if dual != nil {
+		dual.close()
+	}
 	if db != nil {
 		return db.Close()
 	}
 	return nil
 }
+
+// IntegrityCheck runs PRAGMA integrity_check on the database at path. The
+// database is opened read-only and is neither created nor migrated. It returns
+// the problems SQLite reports, or nil if the database is intact.
+func IntegrityCheck(path string) ([]string, error) {
+	conn, err := sql.Open("sqlite", "file:"+path+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	rows, err := conn.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	return problems, rows.Err()
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// openTestDB opens a fresh results database in a temp dir
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "results.db")+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(30000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	// Several connections, so concurrent transactions really overlap
+	conn.SetMaxOpenConns(10)
+	if err := initSchema(conn); err != nil {
+		t.Fatalf("initSchema: %v", err)
+	}
+	return conn
+}
+
+func TestNormalizeTimestamps(t *testing.T) {
+	conn := openTestDB(t)
+
+	// Simulate a database written before the migration existed
+	if _, err := conn.Exec("PRAGMA user_version = 0"); err != nil {
+		t.Fatal(err)
+	}
+	// 10:00+02:00 is 08:00Z, so the run took 30 minutes, not 90 seconds
+	if _, err := conn.Exec(`INSERT INTO runs (run_id, started_at, finished_at, status, duration_ms)
+		VALUES ('r1', '2026-01-02T10:00:00+02:00', '2026-01-02T08:30:00Z', 'completed', 90000)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := initSchema(conn); err != nil {
+		t.Fatalf("initSchema: %v", err)
+	}
+
+	var startedAt string
+	var duration int64
+	if err := conn.QueryRow("SELECT started_at, duration_ms FROM runs WHERE run_id = 'r1'").Scan(&startedAt, &duration); err != nil {
+		t.Fatal(err)
+	}
+	if startedAt != "2026-01-02T08:00:00Z" {
+		t.Errorf("started_at = %q, want 2026-01-02T08:00:00Z", startedAt)
+	}
+	if duration != 1800000 {
+		t.Errorf("duration_ms = %d, want 1800000", duration)
+	}
+
+	var version int
+	if err := conn.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if version != len(dataMigrations) {
+		t.Errorf("user_version = %d, want %d", version, len(dataMigrations))
+	}
+
+	// Later opens must not rescan the tables
+	if _, err := conn.Exec("UPDATE runs SET started_at = '2026-01-02T10:00:00+02:00' WHERE run_id = 'r1'"); err != nil {
+		t.Fatal(err)
+	}
+	if err := initSchema(conn); err != nil {
+		t.Fatalf("initSchema: %v", err)
+	}
+	if err := conn.QueryRow("SELECT started_at FROM runs WHERE run_id = 'r1'").Scan(&startedAt); err != nil {
+		t.Fatal(err)
+	}
+	if startedAt != "2026-01-02T10:00:00+02:00" {
+		t.Errorf("started_at rewritten again: %q", startedAt)
+	}
+}
+
+func TestCompleteRunDuration(t *testing.T) {
+	conn := openTestDB(t)
+	repo := &Repository{db: &dualDB{DB: conn}}
+	ctx := context.Background()
+
+	// Started in a +02:00 zone 90 seconds ago
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	if err := repo.CreateRun(ctx, &models.Run{
+		RunID:     "offset",
+		Mode:      "standalone",
+		StartedAt: time.Now().Add(-90 * time.Second).In(zone),
+		Status:    models.RunStatusRunning,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A row written by an older version, with the offset kept in the text
+	legacyStart := time.Now().Add(-90 * time.Second).In(zone).Format(time.RFC3339)
+	if _, err := conn.Exec("INSERT INTO runs (run_id, started_at, status) VALUES ('legacy', ?, 'running')", legacyStart); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, runID := range []string{"offset", "legacy"} {
+		if err := repo.CompleteRun(ctx, runID); err != nil {
+			t.Fatalf("CompleteRun(%s): %v", runID, err)
+		}
+		run, err := repo.GetRunByID(ctx, runID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !run.DurationMS.Valid {
+			t.Fatalf("%s: duration_ms not set", runID)
+		}
+		// Second-resolution timestamps: allow for the truncation and a slow test
+		if d := run.DurationMS.Int64; d < 88000 || d > 95000 {
+			t.Errorf("%s: duration_ms = %d, want about 90000", runID, d)
+		}
+	}
+}
+
+func TestModifyTestResultConcurrent(t *testing.T) {
+	conn := openTestDB(t)
+	repo := &Repository{db: &dualDB{DB: conn}}
+	ctx := context.Background()
+
+	if _, err := conn.Exec(`INSERT INTO runs (run_id, started_at, status, mode) VALUES ('r1', '2026-01-02T08:00:00Z', 'running', 'standalone')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`INSERT INTO test_results (run_id, test_id, use_case, test_case, status, steps_json)
+		VALUES ('r1', 'uc/tc', 'uc', 'tc', 'running', '')`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each update appends to what the others wrote; none may be lost
+	const updates = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, updates)
+	for i := range updates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.ModifyTestResult(ctx, "uc/tc", "r1", func(tr *models.TestResult) ([]*models.StepResult, []*models.AssertionResult, error) {
+				tr.StepsJSON = sql.NullString{String: tr.StepsJSON.String + fmt.Sprintf("[%d]", i), Valid: true}
+				return nil, nil, nil
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("ModifyTestResult: %v", err)
+		}
+	}
+
+	tr, err := repo.GetTestResultByTestIDAndRunID(ctx, "uc/tc", "r1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(tr.StepsJSON.String, "["); n != updates {
+		t.Errorf("steps_json has %d of %d updates: %s", n, updates, tr.StepsJSON.String)
+	}
+
+	tr, err = repo.ModifyTestResult(ctx, "uc/missing", "r1", func(tr *models.TestResult) ([]*models.StepResult, []*models.AssertionResult, error) {
+		t.Error("modify called for a missing test")
+		return nil, nil, nil
+	})
+	if tr != nil || err != nil {
+		t.Errorf("ModifyTestResult(missing) = %v, %v, want nil, nil", tr, err)
+	}
+}
+
+func TestGetFlakyTestsResolvesAliases(t *testing.T) {
+	conn := openTestDB(t)
+	repo := &Repository{db: &dualDB{DB: conn}}
+	ctx := context.Background()
+
+	if _, err := conn.Exec(`INSERT INTO suites (id, folder_path, suite_name, mode) VALUES (1, '/s', 's', 'standalone')`); err != nil {
+		t.Fatal(err)
+	}
+	// The test passed as uc/old, then failed as uc/new after tsuite mv
+	for _, run := range []struct{ id, startedAt, testID, status string }{
+		{"r1", "2026-01-02T08:00:00Z", "uc/old", "passed"},
+		{"r2", "2026-01-03T08:00:00Z", "uc/new", "failed"},
+	} {
+		if _, err := conn.Exec(`INSERT INTO runs (run_id, suite_id, started_at, status, mode) VALUES (?, 1, ?, 'completed', 'standalone')`, run.id, run.startedAt); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.Exec(`INSERT INTO test_results (run_id, test_id, use_case, test_case, status) VALUES (?, ?, 'uc', ?, ?)`,
+			run.id, run.testID, strings.TrimPrefix(run.testID, "uc/"), run.status); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := conn.Exec(`INSERT INTO test_aliases (suite_id, old_test_id, new_test_id, created_at) VALUES (1, 'uc/old', 'uc/new', '2026-01-02T12:00:00Z')`); err != nil {
+		t.Fatal(err)
+	}
+
+	flaky, err := repo.GetFlakyTests(ctx, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flaky) != 1 || flaky[0] != (FlakyTest{TestID: "uc/new", Passed: 1, Failed: 1}) {
+		t.Errorf("GetFlakyTests = %+v, want uc/new with 1 pass and 1 failure", flaky)
+	}
+}
@@ -0,0 +1,600 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// EnvSecondaryDB configures dual-write for every tsuite process, like
+// tsuite api --secondary-db
+const EnvSecondaryDB = "TSUITE_SECONDARY_DB"
+
+// secondaryTimeout bounds each mirrored write (or transaction) on the
+// secondary database
+const secondaryTimeout = 5 * time.Second
+
+// mirrorQueueSize bounds the writes waiting for the secondary database.
+// Writes beyond it are dropped and counted as divergences, so a slow or
+// unreachable secondary never holds up the primary.
+const mirrorQueueSize = 1000
+
+// mirrorDrainTimeout bounds how long Close waits for queued writes to reach
+// the secondary database
+const mirrorDrainTimeout = 10 * time.Second
+
+var secondarySpec string
+
+// SetSecondaryDB turns on dual-write to a second database, given as
+// <driver>:<dsn> with driver sqlite or postgres (e.g. sqlite:/tmp/shadow.db
+// or postgres:postgres://tsuite@db/tsuite). Must be called before GetDB.
+func SetSecondaryDB(spec string) {
+	secondarySpec = spec
+}
+
+// DualWriteStats counts the writes mirrored to the secondary database, those
+// whose outcome differed from the primary's and those still queued
+type DualWriteStats struct {
+	Enabled     bool
+	Writes      int64
+	Divergences int64
+	Pending     int
+}
+
+// dualDB is the results database. Reads and writes go to SQLite; with a
+// secondary database configured, writes (ExecContext, and transactions of
+// BeginTx once committed) are queued and repeated on it in order by a
+// goroutine. Differing outcomes (an error on one side only, other rows
+// affected) are logged. The secondary never fails or delays a write.
+type dualDB struct {
+	*sql.DB
+	secondary *sql.DB
+	postgres  bool // Secondary speaks Postgres, see postgresStatement
+
+	mu     sync.RWMutex // Guards closed against enqueue
+	closed bool
+	queue  chan mirrorBatch
+	done   chan struct{} // Closed when the queue is drained
+
+	writes      atomic.Int64
+	divergences atomic.Int64
+}
+
+// mirroredWrite is a write of the primary, with its outcome there
+type mirroredWrite struct {
+	query string
+	args  []any
+	id    int64 // Row ID the primary assigned to an INSERT, to insert on the secondary too
+	rows  int64
+	err   error
+}
+
+// mirrorBatch is a single write, or the writes of a committed transaction
+type mirrorBatch struct {
+	writes []mirroredWrite
+	tx     bool
+}
+
+// newDualDB wraps the primary database, with the secondary of SetSecondaryDB
+// or TSUITE_SECONDARY_DB if one is configured
+func newDualDB(primary *sql.DB) (*dualDB, error) {
+	d := &dualDB{DB: primary}
+	spec := secondaryFromEnv()
+	if spec == "" {
+		return d, nil
+	}
+	secondary, driver, err := openSecondary(spec)
+	if err != nil {
+		return nil, err
+	}
+	d.secondary = secondary
+	d.postgres = driver == "postgres"
+	if err := d.seedSecondary(); err != nil {
+		secondary.Close()
+		return nil, fmt.Errorf("failed to copy results to secondary database: %w", err)
+	}
+	d.queue = make(chan mirrorBatch, mirrorQueueSize)
+	d.done = make(chan struct{})
+	go d.mirror()
+	slog.Info("Dual-write enabled", "driver", driver)
+	return d, nil
+}
+
+// openSecondary opens the database of a <driver>:<dsn> spec and creates the
+// schema on it
+func openSecondary(spec string) (*sql.DB, string, error) {
+	driver, dsn, ok := strings.Cut(spec, ":")
+	if !ok || driver == "" || dsn == "" {
+		return nil, "", fmt.Errorf("invalid secondary database %q: use <driver>:<dsn>, e.g. sqlite:/tmp/shadow.db", spec)
+	}
+	if driver != "sqlite" && driver != "postgres" {
+		return nil, "", fmt.Errorf("unsupported secondary database driver %q: use sqlite or postgres", driver)
+	}
+	secondary, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := secondary.Ping(); err != nil {
+		secondary.Close()
+		return nil, "", fmt.Errorf("secondary database not reachable: %w", err)
+	}
+	if driver == "postgres" {
+		err = initPostgresSchema(secondary)
+	} else {
+		err = initSchema(secondary)
+	}
+	if err != nil {
+		secondary.Close()
+		return nil, "", fmt.Errorf("failed to initialize secondary database: %w", err)
+	}
+	return secondary, driver, nil
+}
+
+// ExecContext runs a write on the primary and queues it for the secondary
+func (d *dualDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := d.DB.ExecContext(ctx, query, args...)
+	if d.secondary != nil && !isContextErr(err) {
+		d.enqueue(mirrorBatch{writes: []mirroredWrite{newMirroredWrite(query, args, result, err)}})
+	}
+	return result, err
+}
+
+// BeginTx starts a transaction on the primary. Its writes are queued for the
+// secondary when it commits.
+func (d *dualDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*dualTx, error) {
+	tx, err := d.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &dualTx{Tx: tx, db: d}, nil
+}
+
+// dualWriteStats returns the dual-write counters
+func (d *dualDB) dualWriteStats() DualWriteStats {
+	return DualWriteStats{
+		Enabled:     d.secondary != nil,
+		Writes:      d.writes.Load(),
+		Divergences: d.divergences.Load(),
+		Pending:     len(d.queue),
+	}
+}
+
+// enqueue hands writes to the mirror goroutine, or drops them if it is too
+// far behind
+func (d *dualDB) enqueue(batch mirrorBatch) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return
+	}
+	select {
+	case d.queue <- batch:
+	default:
+		d.writes.Add(int64(len(batch.writes)))
+		d.diverged(batch.writes[0].query, fmt.Sprintf("dropped: %d writes already wait for the secondary", mirrorQueueSize), nil)
+	}
+}
+
+// close waits up to mirrorDrainTimeout for queued writes to reach the
+// secondary, then closes it
+func (d *dualDB) close() {
+	if d.secondary == nil {
+		return
+	}
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	select {
+	case <-d.done:
+	case <-time.After(mirrorDrainTimeout):
+		slog.Warn("Dual-write gave up on queued writes", "pending", len(d.queue))
+	}
+	d.secondary.Close()
+}
+
+// mirror repeats queued writes on the secondary, in order
+func (d *dualDB) mirror() {
+	defer close(d.done)
+	for batch := range d.queue {
+		d.apply(batch)
+	}
+}
+
+// apply repeats a batch on the secondary, in a transaction if it was one on
+// the primary
+func (d *dualDB) apply(batch mirrorBatch) {
+	ctx, cancel := context.WithTimeout(context.Background(), secondaryTimeout)
+	defer cancel()
+
+	if !batch.tx {
+		d.exec(ctx, d.secondary, batch.writes[0])
+		return
+	}
+	tx, err := d.secondary.BeginTx(ctx, nil)
+	if err != nil {
+		d.writes.Add(int64(len(batch.writes)))
+		d.diverged("BEGIN", "secondary failed to begin a transaction", err)
+		return
+	}
+	for _, w := range batch.writes {
+		d.exec(ctx, tx, w)
+	}
+	if err := tx.Commit(); err != nil {
+		d.diverged("COMMIT", "secondary failed to commit", err)
+	}
+}
+
+// exec repeats one write on the secondary and compares the outcomes
+func (d *dualDB) exec(ctx context.Context, ex execer, w mirroredWrite) {
+	query, args := w.query, w.args
+	if w.id != 0 {
+		query, args = withInsertID(query, args, w.id)
+	}
+	if d.postgres {
+		query, args = postgresStatement(query, args)
+	}
+	result, err := ex.ExecContext(ctx, query, args...)
+
+	d.writes.Add(1)
+	switch {
+	case w.err == nil && err != nil:
+		d.diverged(w.query, "secondary failed", err)
+	case w.err != nil && err == nil:
+		d.diverged(w.query, "secondary succeeded where primary failed", w.err)
+	case err == nil:
+		rows, err := result.RowsAffected()
+		if err == nil && rows != w.rows {
+			d.diverged(w.query, fmt.Sprintf("rows affected: primary %d, secondary %d", w.rows, rows), nil)
+		}
+	}
+}
+
+// diverged counts and logs a divergence
+func (d *dualDB) diverged(query, what string, err error) {
+	d.divergences.Add(1)
+	attrs := []any{"what", what, "query", shortQuery(query)}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	slog.Warn("Dual-write divergence", attrs...)
+}
+
+// dualTx is a transaction on the primary whose writes are mirrored once it
+// commits. Writes of a rolled back transaction are never mirrored.
+type dualTx struct {
+	*sql.Tx
+	db     *dualDB
+	writes []mirroredWrite
+}
+
+// ExecContext runs a write in the transaction and records it for the
+// secondary
+func (t *dualTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := t.Tx.ExecContext(ctx, query, args...)
+	if t.db.secondary != nil {
+		t.writes = append(t.writes, newMirroredWrite(query, args, result, err))
+	}
+	return result, err
+}
+
+// Commit commits the primary and queues the transaction's writes for the
+// secondary
+func (t *dualTx) Commit() error {
+	if err := t.Tx.Commit(); err != nil {
+		return err
+	}
+	if len(t.writes) > 0 {
+		t.db.enqueue(mirrorBatch{writes: t.writes, tx: true})
+	}
+	return nil
+}
+
+// newMirroredWrite records a write and its outcome on the primary
+func newMirroredWrite(query string, args []any, result sql.Result, err error) mirroredWrite {
+	w := mirroredWrite{query: query, args: args, err: err}
+	if err != nil {
+		return w
+	}
+	w.rows, _ = result.RowsAffected()
+	if w.rows == 1 && mirrorsInsertID(query) {
+		w.id, _ = result.LastInsertId()
+	}
+	return w
+}
+
+// autoIncrementTables have an INTEGER PRIMARY KEY AUTOINCREMENT id. Rows
+// inserted into them get the primary's id on the secondary too, so later
+// writes WHERE id = ? hit the same rows.
+var autoIncrementTables = map[string]bool{
+	"suites":            true,
+	"test_results":      true,
+	"step_results":      true,
+	"assertion_results": true,
+	"captured_values":   true,
+	"audit_log":         true,
+	"run_comments":      true,
+	"test_issues":       true,
+}
+
+var (
+	insertPattern = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+(\w+)\s*\(`)
+	valuesPattern = regexp.MustCompile(`(?i)\bVALUES\s*\(`)
+)
+
+// mirrorsInsertID reports whether a write is an INSERT ... VALUES into an
+// autoincrement table whose id withInsertID can add. Upserts are left out:
+// an upsert that updated has no new id.
+func mirrorsInsertID(query string) bool {
+	m := insertPattern.FindStringSubmatch(query)
+	return m != nil && autoIncrementTables[m[1]] &&
+		valuesPattern.MatchString(query) &&
+		!strings.Contains(strings.ToUpper(query), "ON CONFLICT") &&
+		!hasNumberedPlaceholder(query)
+}
+
+// withInsertID adds the id column to an INSERT that mirrorsInsertID accepts
+func withInsertID(query string, args []any, id int64) (string, []any) {
+	columns := insertPattern.FindStringIndex(query)[1]
+	values := valuesPattern.FindStringIndex(query)[1]
+	query = query[:columns] + "id, " + query[columns:values] + "?, " + query[values:]
+	return query, append([]any{id}, args...)
+}
+
+// hasNumberedPlaceholder reports whether a query uses ?NNN placeholders
+func hasNumberedPlaceholder(query string) bool {
+	for i := 0; i+1 < len(query); i++ {
+		if query[i] == '?' && query[i+1] >= '0' && query[i+1] <= '9' {
+			return true
+		}
+	}
+	return false
+}
+
+// postgresStatement adapts a write of the repository to Postgres. ?NNN and ?
+// placeholders become $NNN, numbered like SQLite does: a bare ? takes the
+// number after the largest so far. CAST(... AS INTEGER) of julianday()
+// arithmetic truncates, as in SQLite (Postgres rounds), and booleans are
+// stored as the 0 and 1 of the INTEGER columns.
+func postgresStatement(query string, args []any) (string, []any) {
+	var b strings.Builder
+	largest := 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'':
+			inString = !inString
+		case ch == '?' && !inString:
+			j := i + 1
+			for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+				j++
+			}
+			n := largest + 1
+			if j > i+1 {
+				n, _ = strconv.Atoi(query[i+1 : j])
+			}
+			largest = max(largest, n)
+			b.WriteString("$" + strconv.Itoa(n))
+			i = j - 1
+			continue
+		}
+		b.WriteByte(ch)
+	}
+
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		if v, ok := arg.(bool); ok {
+			arg = 0
+			if v {
+				arg = 1
+			}
+		}
+		converted[i] = arg
+	}
+	return truncateJuliandayCasts(b.String()), converted
+}
+
+// truncateJuliandayCasts rewrites CAST(<julianday() arithmetic> AS INTEGER)
+// to TRUNC(<julianday() arithmetic>)::BIGINT
+func truncateJuliandayCasts(query string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(query, "CAST(")
+		if start < 0 {
+			b.WriteString(query)
+			return b.String()
+		}
+		end := closingParen(query, start+len("CAST"))
+		if end < 0 {
+			b.WriteString(query)
+			return b.String()
+		}
+		expr, isInteger := strings.CutSuffix(strings.TrimSpace(query[start+len("CAST("):end]), " AS INTEGER")
+		if isInteger && strings.Contains(expr, "julianday(") {
+			b.WriteString(query[:start] + "TRUNC(" + expr + ")::BIGINT")
+		} else {
+			b.WriteString(query[:end+1])
+		}
+		query = query[end+1:]
+	}
+}
+
+// closingParen returns the index of the parenthesis closing the one at open,
+// or -1
+func closingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// initPostgresSchema creates the schema, with its migrations, on a Postgres
+// secondary. IDs are mirrored from SQLite, so they are plain BIGINTs, and
+// julianday() is defined for the duration arithmetic of mirrored writes.
+func initPostgresSchema(pg *sql.DB) error {
+	types := strings.NewReplacer(
+		"INTEGER PRIMARY KEY AUTOINCREMENT", "BIGINT PRIMARY KEY",
+		"INTEGER", "BIGINT",
+		"INSERT OR IGNORE INTO schema_version (id, version) VALUES (1, 3);",
+		"INSERT INTO schema_version (id, version) VALUES (1, 3) ON CONFLICT DO NOTHING;",
+	)
+	if _, err := pg.Exec(types.Replace(schema)); err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if _, err := pg.Exec("ALTER TABLE " + m.table + " ADD COLUMN IF NOT EXISTS " + m.column + " " + types.Replace(m.definition)); err != nil {
+			return err
+		}
+	}
+	_, err := pg.Exec(`
+		CREATE OR REPLACE FUNCTION julianday(ts TEXT) RETURNS DOUBLE PRECISION AS $$
+			SELECT (EXTRACT(EPOCH FROM ts::TIMESTAMPTZ) / 86400 + 2440587.5)::DOUBLE PRECISION
+		$$ LANGUAGE SQL STABLE
+	`)
+	return err
+}
+
+// seededTables are copied to an empty secondary, parents before children
+var seededTables = []string{
+	"suites", "pipeline_runs", "runs", "test_results", "step_results",
+	"assertion_results", "captured_values", "test_aliases", "audit_log",
+	"run_comments", "test_issues",
+}
+
+// seedSecondary copies the results of the primary to a secondary that has
+// none yet, so mirrored writes find the rows they update. Columns the
+// secondary lacks are left out.
+func (d *dualDB) seedSecondary() error {
+	var runs, suites int
+	if err := d.secondary.QueryRow(`SELECT (SELECT COUNT(*) FROM runs), (SELECT COUNT(*) FROM suites)`).Scan(&runs, &suites); err != nil {
+		return err
+	}
+	if runs > 0 || suites > 0 {
+		return nil
+	}
+
+	tx, err := d.secondary.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	copied := 0
+	for _, table := range seededTables {
+		n, err := d.seedTable(tx, table)
+		if err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+		copied += n
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if copied > 0 {
+		slog.Info("Copied results to secondary database", "rows", copied)
+	}
+	return nil
+}
+
+// seedTable copies the rows of one table to the secondary
+func (d *dualDB) seedTable(tx *sql.Tx, table string) (int, error) {
+	secondaryColumns, err := tableColumns(tx.QueryContext, table)
+	if err != nil {
+		return 0, err
+	}
+	primaryColumns, err := tableColumns(d.DB.QueryContext, table)
+	if err != nil {
+		return 0, err
+	}
+	var columns []string
+	for _, c := range primaryColumns {
+		if slices.Contains(secondaryColumns, c) {
+			columns = append(columns, c)
+		}
+	}
+
+	rows, err := d.DB.Query("SELECT " + strings.Join(columns, ", ") + " FROM " + table)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	insert := "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES (" +
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	if d.postgres {
+		insert, _ = postgresStatement(insert, nil)
+	}
+
+	n := 0
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return n, err
+		}
+		if _, err := tx.Exec(insert, values...); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, rows.Err()
+}
+
+// tableColumns returns the columns of a table, read with query
+func tableColumns(query func(context.Context, string, ...any) (*sql.Rows, error), table string) ([]string, error) {
+	rows, err := query(context.Background(), "SELECT * FROM "+table+" WHERE 1 = 0")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return rows.Columns()
+}
+
+// isContextErr reports whether a write failed because its context ended, in
+// which case the primary did not run it
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// shortQuery collapses a query's whitespace and truncates it for logs
+func shortQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > 120 {
+		query = query[:120] + "..."
+	}
+	return query
+}
+
+// secondaryFromEnv returns the secondary database of SetSecondaryDB, else of
+// TSUITE_SECONDARY_DB
+func secondaryFromEnv() string {
+	if secondarySpec != "" {
+		return secondarySpec
+	}
+	return os.Getenv(EnvSecondaryDB)
+}
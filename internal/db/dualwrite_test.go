@@ -0,0 +1,63 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPostgresStatement(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"bare placeholders", "UPDATE runs SET status = ? WHERE run_id = ?", "UPDATE runs SET status = $1 WHERE run_id = $2"},
+		{"numbered placeholders", "UPDATE runs SET finished_at = ?2 WHERE run_id = ?1 AND ?12 = ?12", "UPDATE runs SET finished_at = $2 WHERE run_id = $1 AND $12 = $12"},
+		{"bare after numbered", "SELECT ?3, ?", "SELECT $3, $4"},
+		{"placeholder in string", "UPDATE t SET a = 'why?' WHERE b = ?", "UPDATE t SET a = 'why?' WHERE b = $1"},
+		{
+			"julianday cast",
+			"SET duration_ms = CAST(\n\t(julianday(?) - julianday(started_at)) * 24 * 60 * 60 * 1000 AS INTEGER\n)",
+			"SET duration_ms = TRUNC((julianday($1) - julianday(started_at)) * 24 * 60 * 60 * 1000)::BIGINT",
+		},
+		{"other cast", "SELECT CAST(value AS INTEGER) FROM t", "SELECT CAST(value AS INTEGER) FROM t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := postgresStatement(tt.query, nil)
+			if got != tt.want {
+				t.Errorf("postgresStatement(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	_, args := postgresStatement("INSERT INTO t (a, b, c) VALUES (?, ?, ?)", []any{true, false, "x"})
+	if want := []any{1, 0, "x"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
+
+func TestWithInsertID(t *testing.T) {
+	query := "\n\t\tINSERT INTO run_comments (run_id, test_id, author, text, created_at)\n\t\tVALUES (?, ?, ?, ?, ?)"
+	if !mirrorsInsertID(query) {
+		t.Fatalf("mirrorsInsertID(%q) = false", query)
+	}
+	got, args := withInsertID(query, []any{"r", nil, "a", "t", "now"}, 42)
+	want := "\n\t\tINSERT INTO run_comments (id, run_id, test_id, author, text, created_at)\n\t\tVALUES (?, ?, ?, ?, ?, ?)"
+	if got != want {
+		t.Errorf("withInsertID() = %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(args, []any{int64(42), "r", nil, "a", "t", "now"}) {
+		t.Errorf("args = %v", args)
+	}
+
+	for _, query := range []string{
+		"INSERT INTO runs (run_id) VALUES (?)",
+		"INSERT INTO captured_values (test_result_id, key) VALUES (?, ?) ON CONFLICT(test_result_id, key) DO UPDATE SET key = excluded.key",
+		"UPDATE suites SET suite_name = ? WHERE id = ?",
+	} {
+		if mirrorsInsertID(query) {
+			t.Errorf("mirrorsInsertID(%q) = true", query)
+		}
+	}
+}
@@ -1,31 +1,70 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
 )
 
+// DefaultQueryTimeout bounds each repository call whose context has no
+// earlier deadline
+const DefaultQueryTimeout = 30 * time.Second
+
+var queryTimeout = DefaultQueryTimeout
+
+// SetQueryTimeout changes how long a repository call may take, like
+// tsuite api --db-timeout. Must be called before the repository is used.
+func SetQueryTimeout(d time.Duration) {
+	queryTimeout = d
+}
+
+// withTimeout bounds a repository call by the query timeout
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
 // Repository provides database operations
 type Repository struct {
-	db *sql.DB
+	db *dualDB
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx so write statements can be
+// shared between single-statement methods and transactions
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 }
 
 // NewRepository creates a new repository
 func NewRepository() (*Repository, error) {
-	db, err := GetDB()
-	if err != nil {
+	if _, err := GetDB(); err != nil {
 		return nil, err
 	}
-	return &Repository{db: db}, nil
+	return &Repository{db: dual}, nil
+}
+
+// DualWriteStats returns the counters of dual-write to the secondary database
+func (r *Repository) DualWriteStats() DualWriteStats {
+	return r.db.dualWriteStats()
+}
+
+// Ping checks that the database answers queries
+func (r *Repository) Ping(ctx context.Context) error {
+	var n int
+	return r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM suites`).Scan(&n)
 }
 
 // ==================== Suites ====================
 
 // GetAllSuites returns all registered suites
-func (r *Repository) GetAllSuites() ([]models.Suite, error) {
-	rows, err := r.db.Query(`
+func (r *Repository) GetAllSuites(ctx context.Context) ([]models.Suite, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, folder_path, suite_name, mode, config_json, test_count,
 		       last_synced_at, created_at, updated_at
 		FROM suites
@@ -58,11 +97,13 @@ func (r *Repository) GetAllSuites() ([]models.Suite, error) {
 }
 
 // GetSuiteByID returns a suite by ID
-func (r *Repository) GetSuiteByID(id int64) (*models.Suite, error) {
+func (r *Repository) GetSuiteByID(ctx context.Context, id int64) (*models.Suite, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	var s models.Suite
 	var lastSynced, created, updated sql.NullString
 
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT id, folder_path, suite_name, mode, config_json, test_count,
 		       last_synced_at, created_at, updated_at
 		FROM suites WHERE id = ?
@@ -84,11 +125,13 @@ func (r *Repository) GetSuiteByID(id int64) (*models.Suite, error) {
 }
 
 // GetSuiteByPath returns a suite by folder path
-func (r *Repository) GetSuiteByPath(folderPath string) (*models.Suite, error) {
+func (r *Repository) GetSuiteByPath(ctx context.Context, folderPath string) (*models.Suite, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	var s models.Suite
 	var lastSynced, created, updated sql.NullString
 
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT id, folder_path, suite_name, mode, config_json, test_count,
 		       last_synced_at, created_at, updated_at
 		FROM suites WHERE folder_path = ?
@@ -110,8 +153,10 @@ func (r *Repository) GetSuiteByPath(folderPath string) (*models.Suite, error) {
 }
 
 // CreateSuite creates a new suite
-func (r *Repository) CreateSuite(s *models.Suite) error {
-	result, err := r.db.Exec(`
+func (r *Repository) CreateSuite(ctx context.Context, s *models.Suite) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	result, err := r.db.ExecContext(ctx, `
 		INSERT INTO suites (folder_path, suite_name, mode, config_json, test_count, last_synced_at)
 		VALUES (?, ?, ?, ?, ?, ?)
 	`, s.FolderPath, s.SuiteName, s.Mode, s.ConfigJSON, s.TestCount,
@@ -131,8 +176,10 @@ func (r *Repository) CreateSuite(s *models.Suite) error {
 }
 
 // UpdateSuite updates a suite
-func (r *Repository) UpdateSuite(s *models.Suite) error {
-	_, err := r.db.Exec(`
+func (r *Repository) UpdateSuite(ctx context.Context, s *models.Suite) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE suites SET
 			folder_path = ?,
 			suite_name = ?,
@@ -149,107 +196,296 @@ func (r *Repository) UpdateSuite(s *models.Suite) error {
 }
 
 // DeleteSuite deletes a suite
-func (r *Repository) DeleteSuite(id int64) error {
-	_, err := r.db.Exec(`DELETE FROM suites WHERE id = ?`, id)
+func (r *Repository) DeleteSuite(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `DELETE FROM suites WHERE id = ?`, id)
 	return err
 }
 
 // ==================== Runs ====================
 
-// GetAllRuns returns all runs, optionally filtered by suite
-func (r *Repository) GetAllRuns(suiteID *int64, limit int) ([]models.Run, error) {
-	query := `
-		SELECT r.run_id, r.suite_id, COALESCE(r.suite_name, s.suite_name) as suite_name, r.started_at, r.finished_at,
+// ErrInvalidFilter is returned for unknown sort fields or unsupported paging
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// runColumns lists the run columns read by scanRun (from runs r LEFT JOIN suites s)
+const runColumns = `r.run_id, r.suite_id, COALESCE(r.suite_name, s.suite_name) as suite_name, r.started_at, r.finished_at,
 		       r.status, r.cli_version, r.sdk_python_version, r.sdk_typescript_version,
 		       r.docker_image, r.total_tests, r.pending_count, r.running_count,
 		       r.passed, r.failed, r.skipped, r.duration_ms, r.filters, r.mode,
-		       r.cancel_requested,
+		       r.cancel_requested, r.parent_run_id, r.pipeline_run_id,
+		       r.runner_version, r.runner_sha256, r.workers,
+		       r.smoke_status, r.smoke_budget_ms, r.profile,
 		       CASE
 		           WHEN (SELECT COUNT(*) FROM test_results tr WHERE tr.run_id = r.run_id) = 1
 		               THEN (SELECT tr.test_id FROM test_results tr WHERE tr.run_id = r.run_id LIMIT 1)
 		           WHEN (SELECT COUNT(DISTINCT tr.use_case) FROM test_results tr WHERE tr.run_id = r.run_id) = 1
 		               THEN (SELECT tr.use_case FROM test_results tr WHERE tr.run_id = r.run_id LIMIT 1)
 		           ELSE NULL
-		       END as display_name
-		FROM runs r
-		LEFT JOIN suites s ON r.suite_id = s.id
-	`
+		       END as display_name`
+
+// scanRun scans a row selected with runColumns
+func scanRun(row rowScanner) (*models.Run, error) {
+	var run models.Run
+	var startedAt string
+	var finishedAt sql.NullString
+
+	err := row.Scan(
+		&run.RunID, &run.SuiteID, &run.SuiteName, &startedAt, &finishedAt,
+		&run.Status, &run.CLIVersion, &run.SDKPythonVersion, &run.SDKTypescriptVersion,
+		&run.DockerImage, &run.TotalTests, &run.PendingCount, &run.RunningCount,
+		&run.Passed, &run.Failed, &run.Skipped, &run.DurationMS, &run.Filters,
+		&run.Mode, &run.CancelRequested, &run.ParentRunID, &run.PipelineRunID,
+		&run.RunnerVersion, &run.RunnerSHA256, &run.Workers,
+		&run.SmokeStatus, &run.SmokeBudgetMS, &run.Profile, &run.DisplayName,
+	)
+	if err != nil {
+		return nil, err
+	}
 
+	run.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+	run.FinishedAt = parseTime(finishedAt)
+
+	return &run, nil
+}
+
+// RunFilter selects, orders and pages runs for listing
+type RunFilter struct {
+	SuiteID       *int64
+	Status        string // Run status, e.g. "failed"
+	Finished      bool   // Only completed and failed runs
+	PipelineRunID string // Only runs of this pipeline run
+	Sort          string // started_at (default), duration, passed, failed, total
+	Asc           bool   // Ascending order (default is descending)
+	Limit         int
+	Offset        int
+	Cursor        string // run_id of the last run on the previous page (started_at sort only)
+}
+
+// runSortColumns maps RunFilter.Sort values to columns
+var runSortColumns = map[string]string{
+	"":           "r.started_at",
+	"started_at": "r.started_at",
+	"duration":   "r.duration_ms",
+	"passed":     "r.passed",
+	"failed":     "r.failed",
+	"total":      "r.total_tests",
+}
+
+// GetAllRuns returns all runs, optionally filtered by suite
+func (r *Repository) GetAllRuns(ctx context.Context, suiteID *int64, limit int) ([]models.Run, error) {
+	runs, _, err := r.ListRuns(ctx, RunFilter{SuiteID: suiteID, Limit: limit})
+	return runs, err
+}
+
+// ListRuns returns one page of runs matching the filter and the total number
+// of matching runs. Cursor paging is stable while new runs are being added.
+func (r *Repository) ListRuns(ctx context.Context, f RunFilter) ([]models.Run, int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sortColumn, ok := runSortColumns[f.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: unknown sort field %q", ErrInvalidFilter, f.Sort)
+	}
+	if f.Cursor != "" && sortColumn != "r.started_at" {
+		return nil, 0, fmt.Errorf("%w: cursor pagination requires sort by started_at", ErrInvalidFilter)
+	}
+
+	var where []string
 	var args []any
-	if suiteID != nil {
-		query += " WHERE r.suite_id = ?"
-		args = append(args, *suiteID)
+	if f.SuiteID != nil {
+		where = append(where, "r.suite_id = ?")
+		args = append(args, *f.SuiteID)
+	}
+	if f.Status != "" {
+		where = append(where, "r.status = ?")
+		args = append(args, f.Status)
+	}
+	if f.Finished {
+		where = append(where, "r.status IN (?, ?)")
+		args = append(args, models.RunStatusCompleted, models.RunStatusFailed)
+	}
+	if f.PipelineRunID != "" {
+		where = append(where, "r.pipeline_run_id = ?")
+		args = append(args, f.PipelineRunID)
+	}
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM runs r`+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	direction, cmp := "DESC", "<"
+	if f.Asc {
+		direction, cmp = "ASC", ">"
+	}
+	if f.Cursor != "" {
+		where = append(where, `(r.started_at, r.run_id) `+cmp+` (SELECT started_at, run_id FROM runs WHERE run_id = ?)`)
+		args = append(args, f.Cursor)
+		whereClause = " WHERE " + strings.Join(where, " AND ")
 	}
 
-	query += " ORDER BY r.started_at DESC"
+	query := `
+		SELECT ` + runColumns + `
+		FROM runs r
+		LEFT JOIN suites s ON r.suite_id = s.id
+	` + whereClause + " ORDER BY " + sortColumn + " " + direction + ", r.run_id " + direction
 
-	if limit > 0 {
-		query += " LIMIT ?"
-		args = append(args, limit)
+	if f.Limit > 0 || f.Offset > 0 {
+		limit := f.Limit
+		if limit <= 0 {
+			limit = -1 // SQLite requires LIMIT with OFFSET
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, f.Offset)
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	var runs []models.Run
 	for rows.Next() {
-		var run models.Run
-		var startedAt string
-		var finishedAt sql.NullString
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		runs = append(runs, *run)
+	}
 
-		err := rows.Scan(
-			&run.RunID, &run.SuiteID, &run.SuiteName, &startedAt, &finishedAt,
-			&run.Status, &run.CLIVersion, &run.SDKPythonVersion, &run.SDKTypescriptVersion,
-			&run.DockerImage, &run.TotalTests, &run.PendingCount, &run.RunningCount,
-			&run.Passed, &run.Failed, &run.Skipped, &run.DurationMS, &run.Filters,
-			&run.Mode, &run.CancelRequested, &run.DisplayName,
+	return runs, total, rows.Err()
+}
+
+// GetPreviousRun returns the latest finished run of the run's suite that
+// started before it, or nil if there is none
+func (r *Repository) GetPreviousRun(ctx context.Context, run *models.Run) (*models.Run, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if !run.SuiteID.Valid {
+		return nil, nil
+	}
+	prev, err := scanRun(r.db.QueryRowContext(ctx, `
+		SELECT `+runColumns+`
+		FROM runs r
+		LEFT JOIN suites s ON r.suite_id = s.id
+		WHERE r.suite_id = ? AND r.status IN (?, ?) AND r.started_at < ? AND r.run_id != ?
+		ORDER BY r.started_at DESC
+		LIMIT 1
+	`, run.SuiteID.Int64, models.RunStatusCompleted, models.RunStatusFailed, formatUTC(run.StartedAt), run.RunID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return prev, err
+}
+
+// FlakyTest is a test that both passed and failed in a suite's recent runs
+type FlakyTest struct {
+	TestID string `json:"test_id"`
+	Passed int    `json:"passed"`
+	Failed int    `json:"failed"` // Failed or crashed
+}
+
+// GetFlakyTests returns the tests that both passed and failed in the
+// suite's last runLimit finished runs, most failures first. Results recorded
+// under former IDs of a test (tsuite mv) count toward its current ID.
+func (r *Repository) GetFlakyTests(ctx context.Context, suiteID int64, runLimit int) ([]FlakyTest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT COALESCE(a.new_test_id, t.test_id) AS current_id,
+		       SUM(CASE WHEN t.status = 'passed' THEN 1 ELSE 0 END) AS passes,
+		       SUM(CASE WHEN t.status IN ('failed', 'crashed') THEN 1 ELSE 0 END) AS failures
+		FROM test_results t
+		JOIN runs r ON r.run_id = t.run_id
+		LEFT JOIN test_aliases a ON a.suite_id = r.suite_id AND a.old_test_id = t.test_id
+			AND r.started_at <= a.created_at
+		WHERE t.run_id IN (
+			SELECT run_id FROM runs
+			WHERE suite_id = ? AND status IN (?, ?)
+			ORDER BY started_at DESC
+			LIMIT ?
 		)
-		if err != nil {
+		GROUP BY current_id
+		HAVING passes > 0 AND failures > 0
+		ORDER BY failures DESC, current_id
+	`, suiteID, models.RunStatusCompleted, models.RunStatusFailed, runLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flaky := []FlakyTest{}
+	for rows.Next() {
+		var f FlakyTest
+		if err := rows.Scan(&f.TestID, &f.Passed, &f.Failed); err != nil {
 			return nil, err
 		}
+		flaky = append(flaky, f)
+	}
+	return flaky, rows.Err()
+}
+
+// GetLatestRunsBySuite returns the most recent run of each registered suite
+// among runs with one of the given statuses, keyed by suite ID
+func (r *Repository) GetLatestRunsBySuite(ctx context.Context, statuses ...string) (map[int64]models.Run, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("%w: no statuses given", ErrInvalidFilter)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(statuses)), ", ")
+	args := make([]any, len(statuses))
+	for i, status := range statuses {
+		args[i] = status
+	}
 
-		run.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
-		run.FinishedAt = parseTime(finishedAt)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+runColumns+`
+		FROM runs r
+		LEFT JOIN suites s ON r.suite_id = s.id
+		WHERE r.run_id IN (
+			SELECT run_id FROM (
+				SELECT run_id, ROW_NUMBER() OVER (
+					PARTITION BY suite_id ORDER BY started_at DESC, run_id DESC
+				) AS rn
+				FROM runs
+				WHERE suite_id IS NOT NULL AND status IN (`+placeholders+`)
+			) WHERE rn = 1
+		)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		runs = append(runs, run)
+	latest := make(map[int64]models.Run)
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		latest[run.SuiteID.Int64] = *run
 	}
 
-	return runs, rows.Err()
+	return latest, rows.Err()
 }
 
 // GetRunByID returns a run by ID
-func (r *Repository) GetRunByID(runID string) (*models.Run, error) {
-	var run models.Run
-	var startedAt string
-	var finishedAt sql.NullString
-
-	err := r.db.QueryRow(`
-		SELECT r.run_id, r.suite_id, COALESCE(r.suite_name, s.suite_name) as suite_name, r.started_at, r.finished_at,
-		       r.status, r.cli_version, r.sdk_python_version, r.sdk_typescript_version,
-		       r.docker_image, r.total_tests, r.pending_count, r.running_count,
-		       r.passed, r.failed, r.skipped, r.duration_ms, r.filters, r.mode,
-		       r.cancel_requested,
-		       CASE
-		           WHEN (SELECT COUNT(*) FROM test_results tr WHERE tr.run_id = r.run_id) = 1
-		               THEN (SELECT tr.test_id FROM test_results tr WHERE tr.run_id = r.run_id LIMIT 1)
-		           WHEN (SELECT COUNT(DISTINCT tr.use_case) FROM test_results tr WHERE tr.run_id = r.run_id) = 1
-		               THEN (SELECT tr.use_case FROM test_results tr WHERE tr.run_id = r.run_id LIMIT 1)
-		           ELSE NULL
-		       END as display_name
+func (r *Repository) GetRunByID(ctx context.Context, runID string) (*models.Run, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	run, err := scanRun(r.db.QueryRowContext(ctx, `
+		SELECT `+runColumns+`
 		FROM runs r
 		LEFT JOIN suites s ON r.suite_id = s.id
 		WHERE r.run_id = ?
-	`, runID).Scan(
-		&run.RunID, &run.SuiteID, &run.SuiteName, &startedAt, &finishedAt,
-		&run.Status, &run.CLIVersion, &run.SDKPythonVersion, &run.SDKTypescriptVersion,
-		&run.DockerImage, &run.TotalTests, &run.PendingCount, &run.RunningCount,
-		&run.Passed, &run.Failed, &run.Skipped, &run.DurationMS, &run.Filters,
-		&run.Mode, &run.CancelRequested, &run.DisplayName,
-	)
-
+	`, runID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -257,15 +493,12 @@ func (r *Repository) GetRunByID(runID string) (*models.Run, error) {
 		return nil, err
 	}
 
-	run.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
-	run.FinishedAt = parseTime(finishedAt)
-
-	return &run, nil
+	return run, nil
 }
 
 // GetLatestRun returns the most recent run
-func (r *Repository) GetLatestRun() (*models.Run, error) {
-	runs, err := r.GetAllRuns(nil, 1)
+func (r *Repository) GetLatestRun(ctx context.Context) (*models.Run, error) {
+	runs, err := r.GetAllRuns(ctx, nil, 1)
 	if err != nil {
 		return nil, err
 	}
@@ -276,37 +509,17 @@ func (r *Repository) GetLatestRun() (*models.Run, error) {
 }
 
 // GetRunningRun returns the currently running run (if any)
-func (r *Repository) GetRunningRun() (*models.Run, error) {
-	var run models.Run
-	var startedAt string
-	var finishedAt sql.NullString
-
-	err := r.db.QueryRow(`
-		SELECT r.run_id, r.suite_id, COALESCE(r.suite_name, s.suite_name) as suite_name, r.started_at, r.finished_at,
-		       r.status, r.cli_version, r.sdk_python_version, r.sdk_typescript_version,
-		       r.docker_image, r.total_tests, r.pending_count, r.running_count,
-		       r.passed, r.failed, r.skipped, r.duration_ms, r.filters, r.mode,
-		       r.cancel_requested,
-		       CASE
-		           WHEN (SELECT COUNT(*) FROM test_results tr WHERE tr.run_id = r.run_id) = 1
-		               THEN (SELECT tr.test_id FROM test_results tr WHERE tr.run_id = r.run_id LIMIT 1)
-		           WHEN (SELECT COUNT(DISTINCT tr.use_case) FROM test_results tr WHERE tr.run_id = r.run_id) = 1
-		               THEN (SELECT tr.use_case FROM test_results tr WHERE tr.run_id = r.run_id LIMIT 1)
-		           ELSE NULL
-		       END as display_name
+func (r *Repository) GetRunningRun(ctx context.Context) (*models.Run, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	run, err := scanRun(r.db.QueryRowContext(ctx, `
+		SELECT `+runColumns+`
 		FROM runs r
 		LEFT JOIN suites s ON r.suite_id = s.id
 		WHERE r.status = 'running'
 		ORDER BY r.started_at DESC
 		LIMIT 1
-	`).Scan(
-		&run.RunID, &run.SuiteID, &run.SuiteName, &startedAt, &finishedAt,
-		&run.Status, &run.CLIVersion, &run.SDKPythonVersion, &run.SDKTypescriptVersion,
-		&run.DockerImage, &run.TotalTests, &run.PendingCount, &run.RunningCount,
-		&run.Passed, &run.Failed, &run.Skipped, &run.DurationMS, &run.Filters,
-		&run.Mode, &run.CancelRequested, &run.DisplayName,
-	)
-
+	`))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -314,25 +527,35 @@ func (r *Repository) GetRunningRun() (*models.Run, error) {
 		return nil, err
 	}
 
-	run.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
-	run.FinishedAt = parseTime(finishedAt)
-
-	return &run, nil
+	return run, nil
 }
 
 // SetCancelRequested sets the cancel flag for a run
-func (r *Repository) SetCancelRequested(runID string) error {
-	_, err := r.db.Exec(`UPDATE runs SET cancel_requested = 1 WHERE run_id = ?`, runID)
+func (r *Repository) SetCancelRequested(ctx context.Context, runID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `UPDATE runs SET cancel_requested = 1 WHERE run_id = ?`, runID)
+	return err
+}
+
+// SetTestCancelRequested flags a single test to be stopped; the CLI running
+// it polls for the flag
+func (r *Repository) SetTestCancelRequested(ctx context.Context, testResultID int64) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `UPDATE test_results SET cancel_requested = 1 WHERE id = ?`, testResultID)
 	return err
 }
 
 // MarkRunCancelled marks a run as cancelled (called by CLI after terminating workers)
 // Also marks remaining pending and running tests as skipped
-func (r *Repository) MarkRunCancelled(runID string) error {
-	now := time.Now().UTC().Format(time.RFC3339)
+func (r *Repository) MarkRunCancelled(ctx context.Context, runID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	now := formatUTC(time.Now())
 
 	// Mark all pending tests as skipped
-	_, err := r.db.Exec(`
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE test_results SET
 			status = 'skipped',
 			skip_reason = 'Run cancelled'
@@ -343,7 +566,7 @@ func (r *Repository) MarkRunCancelled(runID string) error {
 	}
 
 	// Mark all running tests as skipped (they were terminated)
-	_, err = r.db.Exec(`
+	_, err = r.db.ExecContext(ctx, `
 		UPDATE test_results SET
 			status = 'skipped',
 			finished_at = ?,
@@ -355,7 +578,7 @@ func (r *Repository) MarkRunCancelled(runID string) error {
 	}
 
 	// Update counts on run (pending and running become 0, skipped gets updated)
-	_, err = r.db.Exec(`
+	_, err = r.db.ExecContext(ctx, `
 		UPDATE runs SET
 			pending_count = 0,
 			running_count = 0,
@@ -367,12 +590,12 @@ func (r *Repository) MarkRunCancelled(runID string) error {
 	}
 
 	// Mark run as cancelled
-	_, err = r.db.Exec(`
+	_, err = r.db.ExecContext(ctx, `
 		UPDATE runs SET
 			status = 'cancelled',
 			finished_at = ?,
 			duration_ms = CAST(
-				(julianday(?) - julianday(started_at)) * 24 * 60 * 60 * 1000 AS INTEGER
+				ROUND((julianday(?) - julianday(started_at)) * 24 * 60 * 60 * 1000) AS INTEGER
 			)
 		WHERE run_id = ?
 	`, now, now, runID)
@@ -381,12 +604,48 @@ func (r *Repository) MarkRunCancelled(runID string) error {
 
 // ==================== Test Results ====================
 
-// GetTestResultsByRunID returns all test results for a run
-func (r *Repository) GetTestResultsByRunID(runID string) ([]models.TestResult, error) {
-	rows, err := r.db.Query(`
-		SELECT id, run_id, test_id, use_case, test_case, name, tags, status,
+// testResultColumns is the column list scanned by scanTestResult
+const testResultColumns = `id, run_id, test_id, use_case, test_case, name, tags, status,
 		       started_at, finished_at, duration_ms, error_message, error_step,
-		       skip_reason, steps_json, steps_passed, steps_failed
+		       skip_reason, steps_json, steps_passed, steps_failed, suggestions, leaks,
+		       duration_budget_ms, environment, container, workspace_path,
+		       queued_at, queue_wait_ms, cancel_requested, covers, owners`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTestResult scans a row selected with testResultColumns
+func scanTestResult(row rowScanner) (*models.TestResult, error) {
+	var t models.TestResult
+	var startedAt, finishedAt, queuedAt sql.NullString
+
+	err := row.Scan(
+		&t.ID, &t.RunID, &t.TestID, &t.UseCase, &t.TestCase, &t.Name, &t.Tags,
+		&t.Status, &startedAt, &finishedAt, &t.DurationMS, &t.ErrorMessage,
+		&t.ErrorStep, &t.SkipReason, &t.StepsJSON, &t.StepsPassed, &t.StepsFailed,
+		&t.Suggestions, &t.Leaks, &t.DurationBudgetMS, &t.Environment, &t.Container,
+		&t.WorkspacePath, &queuedAt, &t.QueueWaitMS, &t.CancelRequested, &t.Covers,
+		&t.Owners,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t.StartedAt = parseTime(startedAt)
+	t.FinishedAt = parseTime(finishedAt)
+	t.QueuedAt = parseTime(queuedAt)
+
+	return &t, nil
+}
+
+// GetTestResultsByRunID returns all test results for a run
+func (r *Repository) GetTestResultsByRunID(ctx context.Context, runID string) ([]models.TestResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+testResultColumns+`
 		FROM test_results
 		WHERE run_id = ?
 		ORDER BY use_case, test_case
@@ -398,44 +657,153 @@ func (r *Repository) GetTestResultsByRunID(runID string) ([]models.TestResult, e
 
 	var results []models.TestResult
 	for rows.Next() {
-		var t models.TestResult
-		var startedAt, finishedAt sql.NullString
-
-		err := rows.Scan(
-			&t.ID, &t.RunID, &t.TestID, &t.UseCase, &t.TestCase, &t.Name, &t.Tags,
-			&t.Status, &startedAt, &finishedAt, &t.DurationMS, &t.ErrorMessage,
-			&t.ErrorStep, &t.SkipReason, &t.StepsJSON, &t.StepsPassed, &t.StepsFailed,
-		)
+		t, err := scanTestResult(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		t.StartedAt = parseTime(startedAt)
-		t.FinishedAt = parseTime(finishedAt)
+		results = append(results, *t)
+	}
+
+	return results, rows.Err()
+}
+
+// TestResultFilter selects, orders and pages the test results of a run
+type TestResultFilter struct {
+	Statuses []string // Any of these statuses
+	UseCase  string
+	Tag      string
+	Owner    string
+	Sort     string // test_id (default), status, duration, started_at
+	Desc     bool
+	Limit    int
+	Offset   int
+}
+
+// testResultSortColumns maps TestResultFilter.Sort values to columns
+var testResultSortColumns = map[string]string{
+	"":           "use_case, test_case",
+	"test_id":    "use_case, test_case",
+	"status":     "status",
+	"duration":   "duration_ms",
+	"started_at": "started_at",
+}
+
+// ListTestResults returns one page of a run's test results matching the
+// filter and the total number of matching results
+func (r *Repository) ListTestResults(ctx context.Context, runID string, f TestResultFilter) ([]models.TestResult, int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sortColumns, ok := testResultSortColumns[f.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: unknown sort field %q", ErrInvalidFilter, f.Sort)
+	}
+
+	where := []string{"run_id = ?"}
+	args := []any{runID}
+	if len(f.Statuses) > 0 {
+		where = append(where, "status IN (?"+strings.Repeat(", ?", len(f.Statuses)-1)+")")
+		for _, status := range f.Statuses {
+			args = append(args, status)
+		}
+	}
+	if f.UseCase != "" {
+		where = append(where, "use_case = ?")
+		args = append(args, f.UseCase)
+	}
+	if f.Tag != "" {
+		// tags holds a JSON array
+		where = append(where, "EXISTS (SELECT 1 FROM json_each(COALESCE(NULLIF(tags, ''), '[]')) WHERE value = ?)")
+		args = append(args, f.Tag)
+	}
+	if f.Owner != "" {
+		// owners holds a JSON array
+		where = append(where, "EXISTS (SELECT 1 FROM json_each(COALESCE(NULLIF(owners, ''), '[]')) WHERE value = ?)")
+		args = append(args, f.Owner)
+	}
+	whereClause := " WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM test_results`+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	if f.Desc {
+		direction = "DESC"
+	}
+	var order []string
+	for _, col := range strings.Split(sortColumns, ", ") {
+		order = append(order, col+" "+direction)
+	}
+	query := `SELECT ` + testResultColumns + ` FROM test_results` + whereClause +
+		" ORDER BY " + strings.Join(order, ", ") + ", id"
+	if f.Limit > 0 || f.Offset > 0 {
+		limit := f.Limit
+		if limit <= 0 {
+			limit = -1 // SQLite requires LIMIT with OFFSET
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, f.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var results []models.TestResult
+	for rows.Next() {
+		t, err := scanTestResult(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		results = append(results, *t)
+	}
+
+	return results, total, rows.Err()
+}
+
+// GetBudgetViolations returns the tests of a run that exceeded their
+// duration_budget_ms, largest overrun first
+func (r *Repository) GetBudgetViolations(ctx context.Context, runID string) ([]models.TestResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+testResultColumns+`
+		FROM test_results
+		WHERE run_id = ? AND duration_budget_ms IS NOT NULL AND duration_ms > duration_budget_ms
+		ORDER BY CAST(duration_ms AS REAL) / duration_budget_ms DESC
+	`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		results = append(results, t)
+	var results []models.TestResult
+	for rows.Next() {
+		t, err := scanTestResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *t)
 	}
 
 	return results, rows.Err()
 }
 
 // GetTestResultByID returns a test result by ID
-func (r *Repository) GetTestResultByID(id int64) (*models.TestResult, error) {
-	var t models.TestResult
-	var startedAt, finishedAt sql.NullString
-
-	err := r.db.QueryRow(`
-		SELECT id, run_id, test_id, use_case, test_case, name, tags, status,
-		       started_at, finished_at, duration_ms, error_message, error_step,
-		       skip_reason, steps_json, steps_passed, steps_failed
+func (r *Repository) GetTestResultByID(ctx context.Context, id int64) (*models.TestResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+testResultColumns+`
 		FROM test_results
 		WHERE id = ?
-	`, id).Scan(
-		&t.ID, &t.RunID, &t.TestID, &t.UseCase, &t.TestCase, &t.Name, &t.Tags,
-		&t.Status, &startedAt, &finishedAt, &t.DurationMS, &t.ErrorMessage,
-		&t.ErrorStep, &t.SkipReason, &t.StepsJSON, &t.StepsPassed, &t.StepsFailed,
-	)
+	`, id)
 
+	t, err := scanTestResult(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -443,19 +811,19 @@ func (r *Repository) GetTestResultByID(id int64) (*models.TestResult, error) {
 		return nil, err
 	}
 
-	t.StartedAt = parseTime(startedAt)
-	t.FinishedAt = parseTime(finishedAt)
-
-	return &t, nil
+	return t, nil
 }
 
 // ==================== Step Results ====================
 
 // GetStepResultsByTestID returns all step results for a test
-func (r *Repository) GetStepResultsByTestID(testResultID int64) ([]models.StepResult, error) {
-	rows, err := r.db.Query(`
+func (r *Repository) GetStepResultsByTestID(ctx context.Context, testResultID int64) ([]models.StepResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, test_result_id, step_index, phase, handler, description, status,
-		       started_at, finished_at, duration_ms, exit_code, stdout, stderr, error_message
+		       started_at, finished_at, duration_ms, exit_code, stdout, stderr, error_message,
+		       artifacts, resolved_command, resolved_params
 		FROM step_results
 		WHERE test_result_id = ?
 		ORDER BY phase, step_index
@@ -473,7 +841,8 @@ func (r *Repository) GetStepResultsByTestID(testResultID int64) ([]models.StepRe
 		err := rows.Scan(
 			&s.ID, &s.TestResultID, &s.StepIndex, &s.Phase, &s.Handler, &s.Description,
 			&s.Status, &startedAt, &finishedAt, &s.DurationMS, &s.ExitCode,
-			&s.Stdout, &s.Stderr, &s.ErrorMessage,
+			&s.Stdout, &s.Stderr, &s.ErrorMessage, &s.Artifacts,
+			&s.ResolvedCommand, &s.ResolvedParams,
 		)
 		if err != nil {
 			return nil, err
@@ -491,10 +860,12 @@ func (r *Repository) GetStepResultsByTestID(testResultID int64) ([]models.StepRe
 // ==================== Assertions ====================
 
 // GetAssertionsByTestID returns all assertions for a test
-func (r *Repository) GetAssertionsByTestID(testResultID int64) ([]models.AssertionResult, error) {
-	rows, err := r.db.Query(`
+func (r *Repository) GetAssertionsByTestID(ctx context.Context, testResultID int64) ([]models.AssertionResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, test_result_id, assertion_index, expression, message, passed,
-		       actual_value, expected_value
+		       actual_value, expected_value, diff
 		FROM assertion_results
 		WHERE test_result_id = ?
 		ORDER BY assertion_index
@@ -510,7 +881,7 @@ func (r *Repository) GetAssertionsByTestID(testResultID int64) ([]models.Asserti
 
 		err := rows.Scan(
 			&a.ID, &a.TestResultID, &a.AssertionIndex, &a.Expression, &a.Message,
-			&a.Passed, &a.ActualValue, &a.ExpectedValue,
+			&a.Passed, &a.ActualValue, &a.ExpectedValue, &a.Diff,
 		)
 		if err != nil {
 			return nil, err
@@ -525,8 +896,10 @@ func (r *Repository) GetAssertionsByTestID(testResultID int64) ([]models.Asserti
 // ==================== Captured Values ====================
 
 // GetCapturedValuesByTestID returns all captured values for a test
-func (r *Repository) GetCapturedValuesByTestID(testResultID int64) ([]models.CapturedValue, error) {
-	rows, err := r.db.Query(`
+func (r *Repository) GetCapturedValuesByTestID(ctx context.Context, testResultID int64) ([]models.CapturedValue, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, test_result_id, key, value, captured_at
 		FROM captured_values
 		WHERE test_result_id = ?
@@ -555,6 +928,25 @@ func (r *Repository) GetCapturedValuesByTestID(testResultID int64) ([]models.Cap
 	return results, rows.Err()
 }
 
+// SetCapturedValues records captured values of a test, replacing values
+// already stored under the same keys
+func (r *Repository) SetCapturedValues(ctx context.Context, testResultID int64, values map[string]string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	now := formatUTC(time.Now())
+	for key, value := range values {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO captured_values (test_result_id, key, value, captured_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT(test_result_id, key) DO UPDATE SET value = excluded.value, captured_at = excluded.captured_at
+		`, testResultID, key, value, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ==================== Helpers ====================
 
 func parseTime(ns sql.NullString) *time.Time {
@@ -576,25 +968,44 @@ func formatTime(t *time.Time) interface{} {
 	if t == nil {
 		return nil
 	}
-	return t.Format(time.RFC3339)
+	return formatUTC(*t)
+}
+
+// formatUTC formats a timestamp for storage. Timestamps are always stored in
+// UTC: they are compared as strings and subtracted with julianday(), which
+// both give wrong results for values written with different offsets.
+func formatUTC(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatTimeMillis is formatTime with milliseconds, for timestamps that
+// sub-second durations are computed from
+func formatTimeMillis(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
 }
 
 // ==================== Run Creation ====================
 
 // CreateRun creates a new test run
-func (r *Repository) CreateRun(run *models.Run) error {
-	_, err := r.db.Exec(`
+func (r *Repository) CreateRun(ctx context.Context, run *models.Run) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO runs (
 			run_id, suite_id, suite_name, started_at, status,
 			cli_version, sdk_python_version, sdk_typescript_version, docker_image,
 			total_tests, pending_count, running_count, passed, failed, skipped,
-			mode, cancel_requested
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			mode, cancel_requested, parent_run_id, pipeline_run_id,
+			runner_version, runner_sha256, workers, profile
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		run.RunID,
 		nullInt64(run.SuiteID),
 		nullString(run.SuiteName),
-		run.StartedAt.Format(time.RFC3339),
+		formatUTC(run.StartedAt),
 		run.Status,
 		nullString(run.CLIVersion),
 		nullString(run.SDKPythonVersion),
@@ -608,27 +1019,148 @@ func (r *Repository) CreateRun(run *models.Run) error {
 		run.Skipped,
 		run.Mode,
 		run.CancelRequested,
+		nullString(run.ParentRunID),
+		nullString(run.PipelineRunID),
+		nullString(run.RunnerVersion),
+		nullString(run.RunnerSHA256),
+		nullInt64(run.Workers),
+		nullString(run.Profile),
 	)
 	return err
 }
 
-// CreateTestResult creates a new test result record
-func (r *Repository) CreateTestResult(tr *models.TestResult) error {
-	result, err := r.db.Exec(`
-		INSERT INTO test_results (
-			run_id, test_id, use_case, test_case, name, tags, status,
-			steps_passed, steps_failed
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+// ClaimQueuedRun fills in a run the API server queued and marks it running.
+// It returns false if the run is no longer queued.
+func (r *Repository) ClaimQueuedRun(ctx context.Context, run *models.Run) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE runs SET
+			suite_id = ?, suite_name = ?, started_at = ?, status = ?,
+			cli_version = ?, sdk_python_version = ?, sdk_typescript_version = ?, docker_image = ?,
+			total_tests = ?, pending_count = ?, mode = ?, parent_run_id = ?, pipeline_run_id = ?,
+			runner_version = ?, runner_sha256 = ?, workers = ?, profile = ?
+		WHERE run_id = ? AND status = 'queued'
 	`,
-		tr.RunID,
-		tr.TestID,
-		tr.UseCase,
+		nullInt64(run.SuiteID),
+		nullString(run.SuiteName),
+		formatUTC(run.StartedAt),
+		run.Status,
+		nullString(run.CLIVersion),
+		nullString(run.SDKPythonVersion),
+		nullString(run.SDKTypescriptVersion),
+		nullString(run.DockerImage),
+		run.TotalTests,
+		run.PendingCount,
+		run.Mode,
+		nullString(run.ParentRunID),
+		nullString(run.PipelineRunID),
+		nullString(run.RunnerVersion),
+		nullString(run.RunnerSHA256),
+		nullInt64(run.Workers),
+		nullString(run.Profile),
+		run.RunID,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// FinishQueuedRun gives a run that never left the queue its final status.
+// It returns false if the run is no longer queued.
+func (r *Repository) FinishQueuedRun(ctx context.Context, runID string, status models.RunStatus) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE runs SET status = ?, finished_at = ?, duration_ms = 0
+		WHERE run_id = ? AND status = 'queued'
+	`, status, formatUTC(time.Now()), runID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// CancelQueuedRuns cancels all queued runs, returning their IDs. The queue
+// lives in the API server, so its runs do not survive a restart.
+func (r *Repository) CancelQueuedRuns(ctx context.Context) ([]string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `SELECT run_id FROM runs WHERE status = 'queued'`)
+	if err != nil {
+		return nil, err
+	}
+	var runIDs []string
+	for rows.Next() {
+		var runID string
+		if err := rows.Scan(&runID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		runIDs = append(runIDs, runID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, runID := range runIDs {
+		if _, err := r.FinishQueuedRun(ctx, runID, models.RunStatusCancelled); err != nil {
+			return nil, err
+		}
+	}
+	return runIDs, nil
+}
+
+// ActiveRunIDsBySuite returns the IDs of a suite's pending and running runs
+func (r *Repository) ActiveRunIDsBySuite(ctx context.Context, suiteID int64) ([]string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT run_id FROM runs
+		WHERE suite_id = ? AND status IN ('pending', 'running')
+	`, suiteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runIDs []string
+	for rows.Next() {
+		var runID string
+		if err := rows.Scan(&runID); err != nil {
+			return nil, err
+		}
+		runIDs = append(runIDs, runID)
+	}
+	return runIDs, rows.Err()
+}
+
+// CreateTestResult creates a new test result record
+func (r *Repository) CreateTestResult(ctx context.Context, tr *models.TestResult) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO test_results (
+			run_id, test_id, use_case, test_case, name, tags, covers, owners,
+			status, steps_passed, steps_failed, queued_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		tr.RunID,
+		tr.TestID,
+		tr.UseCase,
 		tr.TestCase,
 		nullString(tr.Name),
 		nullString(tr.Tags),
+		nullString(tr.Covers),
+		nullString(tr.Owners),
 		tr.Status,
 		tr.StepsPassed,
 		tr.StepsFailed,
+		formatTimeMillis(tr.QueuedAt),
 	)
 	if err != nil {
 		return err
@@ -643,8 +1175,98 @@ func (r *Repository) CreateTestResult(tr *models.TestResult) error {
 }
 
 // UpdateTestResult updates an existing test result
-func (r *Repository) UpdateTestResult(tr *models.TestResult) error {
-	_, err := r.db.Exec(`
+func (r *Repository) UpdateTestResult(ctx context.Context, tr *models.TestResult) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return updateTestResult(ctx, r.db, tr)
+}
+
+// UpdateTestResultFull updates a test result, inserts its step and assertion
+// results, and moves the run counters from oldStatus to the new status in a
+// single transaction, so a failure part-way leaves nothing half-written.
+func (r *Repository) UpdateTestResultFull(ctx context.Context, tr *models.TestResult, oldStatus models.TestStatus, steps []*models.StepResult, assertions []*models.AssertionResult) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := writeTestResult(ctx, tx, tr, oldStatus, steps, assertions); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ModifyTestResult is UpdateTestResultFull for changes that depend on the
+// stored test result, e.g. steps appended to those already reported. The
+// result is read in the same transaction and passed to modify, which returns
+// the step and assertion results to insert. It returns the test result as
+// modify left it, or nil if there is none. An error from modify rolls the
+// transaction back and is returned as is.
+func (r *Repository) ModifyTestResult(ctx context.Context, testID, runID string, modify func(tr *models.TestResult) ([]*models.StepResult, []*models.AssertionResult, error)) (*models.TestResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the row before reading it. SQLite would otherwise fail the write
+	// if another update committed after the read.
+	if _, err := tx.ExecContext(ctx, "UPDATE test_results SET id = id WHERE test_id = ? AND run_id = ?", testID, runID); err != nil {
+		return nil, err
+	}
+	tr, err := scanTestResult(tx.QueryRowContext(ctx, `
+		SELECT `+testResultColumns+`
+		FROM test_results
+		WHERE test_id = ? AND run_id = ?
+	`, testID, runID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	oldStatus := tr.Status
+	steps, assertions, err := modify(tr)
+	if err != nil {
+		return tr, err
+	}
+	if err := writeTestResult(ctx, tx, tr, oldStatus, steps, assertions); err != nil {
+		return nil, err
+	}
+	return tr, tx.Commit()
+}
+
+// writeTestResult is the write half of UpdateTestResultFull
+func writeTestResult(ctx context.Context, ex execer, tr *models.TestResult, oldStatus models.TestStatus, steps []*models.StepResult, assertions []*models.AssertionResult) error {
+	if err := updateTestResult(ctx, ex, tr); err != nil {
+		return fmt.Errorf("update test result: %w", err)
+	}
+	for _, sr := range steps {
+		sr.TestResultID = tr.ID
+		if err := createStepResult(ctx, ex, sr); err != nil {
+			return fmt.Errorf("insert step result: %w", err)
+		}
+	}
+	for _, ar := range assertions {
+		ar.TestResultID = tr.ID
+		if err := createAssertionResult(ctx, ex, ar); err != nil {
+			return fmt.Errorf("insert assertion result: %w", err)
+		}
+	}
+	if err := updateRunCountersIncremental(ctx, ex, tr.RunID, oldStatus, tr.Status); err != nil {
+		return fmt.Errorf("update run counters: %w", err)
+	}
+	return nil
+}
+
+func updateTestResult(ctx context.Context, ex execer, tr *models.TestResult) error {
+	_, err := ex.ExecContext(ctx, `
 		UPDATE test_results SET
 			status = ?,
 			started_at = ?,
@@ -652,9 +1274,17 @@ func (r *Repository) UpdateTestResult(tr *models.TestResult) error {
 			duration_ms = ?,
 			error_message = ?,
 			error_step = ?,
+			skip_reason = ?,
 			steps_passed = ?,
 			steps_failed = ?,
-			steps_json = ?
+			steps_json = ?,
+			suggestions = ?,
+			leaks = ?,
+			duration_budget_ms = ?,
+			environment = ?,
+			container = ?,
+			workspace_path = ?,
+			queue_wait_ms = ?
 		WHERE id = ?
 	`,
 		tr.Status,
@@ -663,31 +1293,33 @@ func (r *Repository) UpdateTestResult(tr *models.TestResult) error {
 		nullInt64(tr.DurationMS),
 		nullString(tr.ErrorMessage),
 		nullInt64(tr.ErrorStep),
+		nullString(tr.SkipReason),
 		tr.StepsPassed,
 		tr.StepsFailed,
 		nullString(tr.StepsJSON),
+		nullString(tr.Suggestions),
+		nullString(tr.Leaks),
+		nullInt64(tr.DurationBudgetMS),
+		nullString(tr.Environment),
+		nullString(tr.Container),
+		nullString(tr.WorkspacePath),
+		nullInt64(tr.QueueWaitMS),
 		tr.ID,
 	)
 	return err
 }
 
 // GetTestResultByTestIDAndRunID gets a test result by test_id and run_id
-func (r *Repository) GetTestResultByTestIDAndRunID(testID, runID string) (*models.TestResult, error) {
-	var t models.TestResult
-	var startedAt, finishedAt sql.NullString
-
-	err := r.db.QueryRow(`
-		SELECT id, run_id, test_id, use_case, test_case, name, tags, status,
-		       started_at, finished_at, duration_ms, error_message, error_step,
-		       skip_reason, steps_json, steps_passed, steps_failed
+func (r *Repository) GetTestResultByTestIDAndRunID(ctx context.Context, testID, runID string) (*models.TestResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+testResultColumns+`
 		FROM test_results
 		WHERE test_id = ? AND run_id = ?
-	`, testID, runID).Scan(
-		&t.ID, &t.RunID, &t.TestID, &t.UseCase, &t.TestCase, &t.Name, &t.Tags,
-		&t.Status, &startedAt, &finishedAt, &t.DurationMS, &t.ErrorMessage,
-		&t.ErrorStep, &t.SkipReason, &t.StepsJSON, &t.StepsPassed, &t.StepsFailed,
-	)
+	`, testID, runID)
 
+	t, err := scanTestResult(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -695,15 +1327,14 @@ func (r *Repository) GetTestResultByTestIDAndRunID(testID, runID string) (*model
 		return nil, err
 	}
 
-	t.StartedAt = parseTime(startedAt)
-	t.FinishedAt = parseTime(finishedAt)
-
-	return &t, nil
+	return t, nil
 }
 
 // UpdateRunCounters updates the test count fields on a run (full recount - use sparingly)
-func (r *Repository) UpdateRunCounters(runID string) error {
-	_, err := r.db.Exec(`
+func (r *Repository) UpdateRunCounters(ctx context.Context, runID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE runs SET
 			pending_count = (SELECT COUNT(*) FROM test_results WHERE run_id = ? AND status = 'pending'),
 			running_count = (SELECT COUNT(*) FROM test_results WHERE run_id = ? AND status = 'running'),
@@ -717,51 +1348,63 @@ func (r *Repository) UpdateRunCounters(runID string) error {
 
 // UpdateRunCountersIncremental updates run counters based on status transition (idempotent)
 // This is the preferred method during test execution to avoid race conditions
-func (r *Repository) UpdateRunCountersIncremental(runID string, oldStatus, newStatus models.TestStatus) error {
+func (r *Repository) UpdateRunCountersIncremental(ctx context.Context, runID string, oldStatus, newStatus models.TestStatus) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return updateRunCountersIncremental(ctx, r.db, runID, oldStatus, newStatus)
+}
+
+func updateRunCountersIncremental(ctx context.Context, ex execer, runID string, oldStatus, newStatus models.TestStatus) error {
 	if oldStatus == newStatus {
 		return nil
 	}
 
 	// Decrement old status counter
+	var err error
 	switch oldStatus {
 	case models.TestStatusPending:
-		r.db.Exec("UPDATE runs SET pending_count = pending_count - 1 WHERE run_id = ? AND pending_count > 0", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET pending_count = pending_count - 1 WHERE run_id = ? AND pending_count > 0", runID)
 	case models.TestStatusRunning:
-		r.db.Exec("UPDATE runs SET running_count = running_count - 1 WHERE run_id = ? AND running_count > 0", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET running_count = running_count - 1 WHERE run_id = ? AND running_count > 0", runID)
 	case models.TestStatusPassed:
-		r.db.Exec("UPDATE runs SET passed = passed - 1 WHERE run_id = ? AND passed > 0", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET passed = passed - 1 WHERE run_id = ? AND passed > 0", runID)
 	case models.TestStatusFailed, models.TestStatusCrashed:
 		// Both count as failed
-		r.db.Exec("UPDATE runs SET failed = failed - 1 WHERE run_id = ? AND failed > 0", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET failed = failed - 1 WHERE run_id = ? AND failed > 0", runID)
 	case models.TestStatusSkipped:
-		r.db.Exec("UPDATE runs SET skipped = skipped - 1 WHERE run_id = ? AND skipped > 0", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET skipped = skipped - 1 WHERE run_id = ? AND skipped > 0", runID)
+	}
+	if err != nil {
+		return err
 	}
 
 	// Increment new status counter
 	switch newStatus {
 	case models.TestStatusPending:
-		r.db.Exec("UPDATE runs SET pending_count = pending_count + 1 WHERE run_id = ?", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET pending_count = pending_count + 1 WHERE run_id = ?", runID)
 	case models.TestStatusRunning:
-		r.db.Exec("UPDATE runs SET running_count = running_count + 1 WHERE run_id = ?", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET running_count = running_count + 1 WHERE run_id = ?", runID)
 	case models.TestStatusPassed:
-		r.db.Exec("UPDATE runs SET passed = passed + 1 WHERE run_id = ?", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET passed = passed + 1 WHERE run_id = ?", runID)
 	case models.TestStatusFailed, models.TestStatusCrashed:
 		// Both count as failed
-		r.db.Exec("UPDATE runs SET failed = failed + 1 WHERE run_id = ?", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET failed = failed + 1 WHERE run_id = ?", runID)
 	case models.TestStatusSkipped:
-		r.db.Exec("UPDATE runs SET skipped = skipped + 1 WHERE run_id = ?", runID)
+		_, err = ex.ExecContext(ctx, "UPDATE runs SET skipped = skipped + 1 WHERE run_id = ?", runID)
 	}
 
-	return nil
+	return err
 }
 
 // CompleteRun marks a run as completed and calculates duration
-func (r *Repository) CompleteRun(runID string) error {
-	now := time.Now().Format(time.RFC3339)
+func (r *Repository) CompleteRun(ctx context.Context, runID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	now := formatUTC(time.Now())
 
 	// Determine status based on test results
 	var failed int
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM test_results
 		WHERE run_id = ? AND status IN ('failed', 'crashed')
 	`, runID).Scan(&failed)
@@ -776,12 +1419,12 @@ func (r *Repository) CompleteRun(runID string) error {
 
 	// Calculate duration as wall-clock time (finished - started), not sum of test durations
 	// With parallel execution, sum would be much larger than actual elapsed time
-	_, err = r.db.Exec(`
+	_, err = r.db.ExecContext(ctx, `
 		UPDATE runs SET
 			status = ?,
 			finished_at = ?,
 			duration_ms = CAST(
-				(julianday(?) - julianday(started_at)) * 24 * 60 * 60 * 1000 AS INTEGER
+				ROUND((julianday(?) - julianday(started_at)) * 24 * 60 * 60 * 1000) AS INTEGER
 			)
 		WHERE run_id = ?
 	`, status, now, now, runID)
@@ -789,23 +1432,36 @@ func (r *Repository) CompleteRun(runID string) error {
 	return err
 }
 
+// SetRunSmokeStatus records the outcome of a smoke run and its time budget
+func (r *Repository) SetRunSmokeStatus(ctx context.Context, runID, status string, budgetMS int64) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `UPDATE runs SET smoke_status = ?, smoke_budget_ms = ? WHERE run_id = ?`,
+		status, sql.NullInt64{Int64: budgetMS, Valid: budgetMS > 0}, runID)
+	return err
+}
+
 // UpdateRunStatus updates the status of a run
-func (r *Repository) UpdateRunStatus(runID string, status models.RunStatus) error {
-	_, err := r.db.Exec(`UPDATE runs SET status = ? WHERE run_id = ?`, status, runID)
+func (r *Repository) UpdateRunStatus(ctx context.Context, runID string, status models.RunStatus) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `UPDATE runs SET status = ? WHERE run_id = ?`, status, runID)
 	return err
 }
 
-// DeleteRun deletes a run and all associated records (test results, steps, assertions, captured values)
-func (r *Repository) DeleteRun(runID string) error {
+// DeleteRun deletes a run and all associated records (test results, steps, assertions, captured values, comments)
+func (r *Repository) DeleteRun(ctx context.Context, runID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 	// Start a transaction
-	tx, err := r.db.Begin()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
 	// Delete step_results for all tests in this run
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		DELETE FROM step_results
 		WHERE test_result_id IN (SELECT id FROM test_results WHERE run_id = ?)
 	`, runID)
@@ -814,7 +1470,7 @@ func (r *Repository) DeleteRun(runID string) error {
 	}
 
 	// Delete assertion_results for all tests in this run
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		DELETE FROM assertion_results
 		WHERE test_result_id IN (SELECT id FROM test_results WHERE run_id = ?)
 	`, runID)
@@ -823,7 +1479,7 @@ func (r *Repository) DeleteRun(runID string) error {
 	}
 
 	// Delete captured_values for all tests in this run
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		DELETE FROM captured_values
 		WHERE test_result_id IN (SELECT id FROM test_results WHERE run_id = ?)
 	`, runID)
@@ -831,14 +1487,20 @@ func (r *Repository) DeleteRun(runID string) error {
 		return err
 	}
 
+	// Delete comments on the run and its tests
+	_, err = tx.ExecContext(ctx, `DELETE FROM run_comments WHERE run_id = ?`, runID)
+	if err != nil {
+		return err
+	}
+
 	// Delete test_results for this run
-	_, err = tx.Exec(`DELETE FROM test_results WHERE run_id = ?`, runID)
+	_, err = tx.ExecContext(ctx, `DELETE FROM test_results WHERE run_id = ?`, runID)
 	if err != nil {
 		return err
 	}
 
 	// Delete the run itself
-	_, err = tx.Exec(`DELETE FROM runs WHERE run_id = ?`, runID)
+	_, err = tx.ExecContext(ctx, `DELETE FROM runs WHERE run_id = ?`, runID)
 	if err != nil {
 		return err
 	}
@@ -849,12 +1511,19 @@ func (r *Repository) DeleteRun(runID string) error {
 // ==================== Step Results ====================
 
 // CreateStepResult creates a new step result record
-func (r *Repository) CreateStepResult(sr *models.StepResult) error {
-	result, err := r.db.Exec(`
+func (r *Repository) CreateStepResult(ctx context.Context, sr *models.StepResult) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return createStepResult(ctx, r.db, sr)
+}
+
+func createStepResult(ctx context.Context, ex execer, sr *models.StepResult) error {
+	result, err := ex.ExecContext(ctx, `
 		INSERT INTO step_results (
 			test_result_id, step_index, phase, handler, description, status,
-			started_at, finished_at, duration_ms, exit_code, stdout, stderr, error_message
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			started_at, finished_at, duration_ms, exit_code, stdout, stderr, error_message,
+			artifacts, resolved_command, resolved_params
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		sr.TestResultID,
 		sr.StepIndex,
@@ -869,6 +1538,9 @@ func (r *Repository) CreateStepResult(sr *models.StepResult) error {
 		nullString(sr.Stdout),
 		nullString(sr.Stderr),
 		nullString(sr.ErrorMessage),
+		nullString(sr.Artifacts),
+		nullString(sr.ResolvedCommand),
+		nullString(sr.ResolvedParams),
 	)
 	if err != nil {
 		return err
@@ -885,12 +1557,18 @@ func (r *Repository) CreateStepResult(sr *models.StepResult) error {
 // ==================== Assertion Results ====================
 
 // CreateAssertionResult creates a new assertion result record
-func (r *Repository) CreateAssertionResult(ar *models.AssertionResult) error {
-	result, err := r.db.Exec(`
+func (r *Repository) CreateAssertionResult(ctx context.Context, ar *models.AssertionResult) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return createAssertionResult(ctx, r.db, ar)
+}
+
+func createAssertionResult(ctx context.Context, ex execer, ar *models.AssertionResult) error {
+	result, err := ex.ExecContext(ctx, `
 		INSERT INTO assertion_results (
 			test_result_id, assertion_index, expression, message, passed,
-			actual_value, expected_value
-		) VALUES (?, ?, ?, ?, ?, ?, ?)
+			actual_value, expected_value, diff
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		ar.TestResultID,
 		ar.AssertionIndex,
@@ -899,6 +1577,7 @@ func (r *Repository) CreateAssertionResult(ar *models.AssertionResult) error {
 		ar.Passed,
 		nullString(ar.ActualValue),
 		nullString(ar.ExpectedValue),
+		nullString(ar.Diff),
 	)
 	if err != nil {
 		return err
@@ -923,8 +1602,10 @@ type RunStats struct {
 }
 
 // GetRunStats returns aggregate statistics across all completed/failed runs
-func (r *Repository) GetRunStats() (*RunStats, error) {
-	row := r.db.QueryRow(`
+func (r *Repository) GetRunStats(ctx context.Context) (*RunStats, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	row := r.db.QueryRowContext(ctx, `
 		SELECT
 			COUNT(*) as total_runs,
 			COALESCE(SUM(total_tests), 0) as total_tests_executed,
@@ -965,6 +1646,1063 @@ func (r *Repository) GetRunStats() (*RunStats, error) {
 	return stats, nil
 }
 
+// RunScheduling summarizes how a run's tests were scheduled on its workers
+type RunScheduling struct {
+	RunID            string   `json:"run_id"`
+	Workers          *int64   `json:"workers"`             // Parallel test runners, if the CLI reported them
+	TestsStarted     int64    `json:"tests_started"`       // Tests with a recorded queue wait
+	WallMS           int64    `json:"wall_ms"`             // Run duration, or time since start while running
+	ExecMS           int64    `json:"exec_ms"`             // Sum of test durations
+	QueueWaitTotalMS int64    `json:"queue_wait_total_ms"` // Sum of time tests waited for a worker
+	QueueWaitAvgMS   int64    `json:"queue_wait_avg_ms"`
+	QueueWaitMaxMS   int64    `json:"queue_wait_max_ms"`
+	Parallelism      float64  `json:"parallelism"` // Average number of tests running at once (exec/wall)
+	Utilization      *float64 `json:"utilization"` // Parallelism as a percentage of workers
+}
+
+// GetRunScheduling returns queue wait and worker utilization of a run
+func (r *Repository) GetRunScheduling(ctx context.Context, run *models.Run) (*RunScheduling, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	sched := &RunScheduling{RunID: run.RunID}
+	if run.Workers.Valid {
+		sched.Workers = &run.Workers.Int64
+	}
+
+	var avgWait sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(queue_wait_ms),
+			COALESCE(SUM(queue_wait_ms), 0),
+			AVG(queue_wait_ms),
+			COALESCE(MAX(queue_wait_ms), 0),
+			COALESCE(SUM(duration_ms), 0)
+		FROM test_results
+		WHERE run_id = ?
+	`, run.RunID).Scan(
+		&sched.TestsStarted,
+		&sched.QueueWaitTotalMS,
+		&avgWait,
+		&sched.QueueWaitMaxMS,
+		&sched.ExecMS,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if avgWait.Valid {
+		sched.QueueWaitAvgMS = int64(avgWait.Float64)
+	}
+
+	if run.DurationMS.Valid {
+		sched.WallMS = run.DurationMS.Int64
+	} else if run.FinishedAt == nil {
+		sched.WallMS = time.Since(run.StartedAt).Milliseconds()
+	}
+	if sched.WallMS > 0 {
+		// Round to 2 decimal places
+		sched.Parallelism = float64(int(float64(sched.ExecMS)/float64(sched.WallMS)*100)) / 100
+		if sched.Workers != nil && *sched.Workers > 0 {
+			utilization := float64(int(float64(sched.ExecMS)/float64(sched.WallMS*(*sched.Workers))*10000)) / 100
+			sched.Utilization = &utilization
+		}
+	}
+
+	return sched, nil
+}
+
+// TestDurationStat summarizes historical durations of a test
+type TestDurationStat struct {
+	TestID  string `json:"test_id"`
+	Samples int    `json:"samples"`
+	AvgMS   int64  `json:"avg_duration_ms"`
+	MaxMS   int64  `json:"max_duration_ms"`
+	LastMS  int64  `json:"last_duration_ms"`
+}
+
+// GetTestDurationStats returns duration statistics for completed tests of a suite,
+// using at most the given number of most recent runs (0 = all runs). Results of
+// moved tests are keyed by their current test ID.
+func (r *Repository) GetTestDurationStats(ctx context.Context, folderPath string, runLimit int) (map[string]TestDurationStat, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if runLimit <= 0 {
+		runLimit = -1 // SQLite: no limit
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH recent_runs AS (
+			SELECT r.run_id, r.suite_id, r.started_at
+			FROM runs r
+			JOIN suites s ON s.id = r.suite_id
+			WHERE s.folder_path = ?
+			ORDER BY r.started_at DESC
+			LIMIT ?
+		)
+		SELECT COALESCE(a.new_test_id, t.test_id), t.duration_ms
+		FROM test_results t
+		JOIN recent_runs rr ON rr.run_id = t.run_id
+		LEFT JOIN test_aliases a ON a.suite_id = rr.suite_id AND a.old_test_id = t.test_id
+			AND rr.started_at <= a.created_at
+		WHERE t.status IN ('passed', 'failed') AND t.duration_ms IS NOT NULL
+		ORDER BY rr.started_at DESC
+	`, folderPath, runLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]TestDurationStat)
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var testID string
+		var durationMS int64
+		if err := rows.Scan(&testID, &durationMS); err != nil {
+			return nil, err
+		}
+
+		stat := stats[testID]
+		if stat.Samples == 0 {
+			// Rows are newest first
+			stat.TestID = testID
+			stat.LastMS = durationMS
+		}
+		stat.Samples++
+		if durationMS > stat.MaxMS {
+			stat.MaxMS = durationMS
+		}
+		totals[testID] += durationMS
+		stat.AvgMS = totals[testID] / int64(stat.Samples)
+		stats[testID] = stat
+	}
+
+	return stats, rows.Err()
+}
+
+// FailedAssertion is a single failed assertion joined with its test and run
+type FailedAssertion struct {
+	Expression string
+	Message    string
+	TestID     string
+	RunID      string
+	StartedAt  *time.Time
+}
+
+// GetFailedAssertionsSince returns all failed assertions from runs started at or
+// after since, with moved tests under their current test ID
+func (r *Repository) GetFailedAssertionsSince(ctx context.Context, since time.Time) ([]FailedAssertion, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.expression, a.message, COALESCE(ta.new_test_id, t.test_id), t.run_id, r.started_at
+		FROM assertion_results a
+		JOIN test_results t ON t.id = a.test_result_id
+		JOIN runs r ON r.run_id = t.run_id
+		LEFT JOIN test_aliases ta ON ta.suite_id = r.suite_id AND ta.old_test_id = t.test_id
+			AND r.started_at <= ta.created_at
+		WHERE a.passed = 0 AND r.started_at >= ?
+		ORDER BY r.started_at DESC
+	`, formatUTC(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []FailedAssertion
+	for rows.Next() {
+		var fa FailedAssertion
+		var message, startedAt sql.NullString
+
+		if err := rows.Scan(&fa.Expression, &message, &fa.TestID, &fa.RunID, &startedAt); err != nil {
+			return nil, err
+		}
+
+		fa.Message = message.String
+		fa.StartedAt = parseTime(startedAt)
+
+		results = append(results, fa)
+	}
+
+	return results, rows.Err()
+}
+
+// CoveredFeature is a feature a test of a run declares it covers, with the
+// test's status
+type CoveredFeature struct {
+	Feature string
+	TestID  string
+	Status  models.TestStatus
+}
+
+// GetRunCoverage returns the features the tests of a run cover (covers: in
+// test.yaml), one entry per feature and test, ordered by feature
+func (r *Repository) GetRunCoverage(ctx context.Context, runID string) ([]CoveredFeature, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT f.value, t.test_id, t.status
+		FROM test_results t, json_each(COALESCE(NULLIF(t.covers, ''), '[]')) f
+		WHERE t.run_id = ?
+		ORDER BY f.value, t.test_id
+	`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var covered []CoveredFeature
+	for rows.Next() {
+		var cf CoveredFeature
+		if err := rows.Scan(&cf.Feature, &cf.TestID, &cf.Status); err != nil {
+			return nil, err
+		}
+		covered = append(covered, cf)
+	}
+
+	return covered, rows.Err()
+}
+
+// ==================== Test Aliases ====================
+
+// TestAlias maps a former test ID of a suite to the test's current ID
+type TestAlias struct {
+	OldTestID string     `json:"old_test_id"`
+	NewTestID string     `json:"new_test_id"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+// RecordTestMove records that a suite's test oldID now lives at newID. Results
+// recorded under oldID (or any earlier ID of the test) before the move are
+// attributed to newID; later results under oldID belong to whatever test uses
+// that ID next.
+func (r *Repository) RecordTestMove(ctx context.Context, suiteID int64, oldID, newID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// newID is a live test again (e.g. a move being undone)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM test_aliases WHERE suite_id = ? AND old_test_id = ?`, suiteID, newID); err != nil {
+		return err
+	}
+	// Keep aliases one hop deep so queries need a single join
+	if _, err := tx.ExecContext(ctx, `UPDATE test_aliases SET new_test_id = ? WHERE suite_id = ? AND new_test_id = ?`, newID, suiteID, oldID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO test_aliases (suite_id, old_test_id, new_test_id, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(suite_id, old_test_id) DO UPDATE SET
+			new_test_id = excluded.new_test_id,
+			created_at = excluded.created_at
+	`, suiteID, oldID, newID, formatUTC(time.Now())); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetTestAliases returns the former IDs of a suite's tests, oldest move first
+func (r *Repository) GetTestAliases(ctx context.Context, suiteID int64) ([]TestAlias, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT old_test_id, new_test_id, created_at
+		FROM test_aliases
+		WHERE suite_id = ?
+		ORDER BY created_at, old_test_id
+	`, suiteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []TestAlias{}
+	for rows.Next() {
+		var a TestAlias
+		var createdAt sql.NullString
+		if err := rows.Scan(&a.OldTestID, &a.NewTestID, &createdAt); err != nil {
+			return nil, err
+		}
+		a.CreatedAt = parseTime(createdAt)
+		aliases = append(aliases, a)
+	}
+
+	return aliases, rows.Err()
+}
+
+// GetTestHistory returns the most recent results of a suite's test, newest
+// first, including results recorded under former IDs of the test
+func (r *Repository) GetTestHistory(ctx context.Context, suiteID int64, testID string, limit int) ([]models.TestResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+testResultColumns+`
+		FROM test_results
+		WHERE id IN (
+			SELECT t.id
+			FROM test_results t
+			JOIN runs r ON r.run_id = t.run_id
+			LEFT JOIN test_aliases a ON a.suite_id = r.suite_id AND a.old_test_id = t.test_id
+				AND r.started_at <= a.created_at
+			WHERE r.suite_id = ? AND COALESCE(a.new_test_id, t.test_id) = ?
+		)
+		ORDER BY id DESC
+		LIMIT ?
+	`, suiteID, testID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.TestResult{}
+	for rows.Next() {
+		t, err := scanTestResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *t)
+	}
+
+	return results, rows.Err()
+}
+
+// GetLatestTestResult returns the most recent result of a suite's test with
+// one of the given statuses, including results recorded under former IDs of
+// the test, or nil if there is none
+func (r *Repository) GetLatestTestResult(ctx context.Context, suiteID int64, testID string, statuses ...models.TestStatus) (*models.TestResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("%w: no statuses given", ErrInvalidFilter)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(statuses)), ", ")
+	args := []any{suiteID, testID}
+	for _, status := range statuses {
+		args = append(args, status)
+	}
+
+	t, err := scanTestResult(r.db.QueryRowContext(ctx, `
+		SELECT `+testResultColumns+`
+		FROM test_results
+		WHERE id IN (
+			SELECT t.id
+			FROM test_results t
+			JOIN runs r ON r.run_id = t.run_id
+			LEFT JOIN test_aliases a ON a.suite_id = r.suite_id AND a.old_test_id = t.test_id
+				AND r.started_at <= a.created_at
+			WHERE r.suite_id = ? AND COALESCE(a.new_test_id, t.test_id) = ?
+				AND t.status IN (`+placeholders+`)
+		)
+		ORDER BY id DESC
+		LIMIT 1
+	`, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ==================== Audit Log ====================
+
+// AuditFilter selects and pages audit log entries
+type AuditFilter struct {
+	SuiteID *int64
+	Path    string // Entries for this file, or for files below it if it ends in "/"
+	Since   *time.Time
+	Limit   int
+	Offset  int
+}
+
+// CreateAuditEntry records a change to a suite file
+func (r *Repository) CreateAuditEntry(ctx context.Context, e *models.AuditEntry) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if e.CreatedAt == nil {
+		now := time.Now().UTC()
+		e.CreatedAt = &now
+	}
+
+	var suiteID any
+	if e.SuiteID != nil {
+		suiteID = *e.SuiteID
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO audit_log (suite_id, path, action, endpoint, actor, diff, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, suiteID, e.Path, e.Action, e.Endpoint, e.Actor, e.Diff, formatUTC(*e.CreatedAt))
+	if err != nil {
+		return err
+	}
+
+	e.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// ListAuditEntries returns one page of audit log entries matching the filter,
+// newest first, and the total number of matching entries
+func (r *Repository) ListAuditEntries(ctx context.Context, f AuditFilter) ([]models.AuditEntry, int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var where []string
+	var args []any
+	if f.SuiteID != nil {
+		where = append(where, "suite_id = ?")
+		args = append(args, *f.SuiteID)
+	}
+	if strings.HasSuffix(f.Path, "/") {
+		where = append(where, "substr(path, 1, ?) = ?")
+		args = append(args, len(f.Path), f.Path)
+	} else if f.Path != "" {
+		where = append(where, "path = ?")
+		args = append(args, f.Path)
+	}
+	if f.Since != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, formatUTC(*f.Since))
+	}
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log`+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, suite_id, path, action, endpoint, actor, diff, created_at
+		FROM audit_log
+	` + whereClause + " ORDER BY created_at DESC, id DESC"
+
+	if f.Limit > 0 || f.Offset > 0 {
+		limit := f.Limit
+		if limit <= 0 {
+			limit = -1 // SQLite requires LIMIT with OFFSET
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, f.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		var suiteID sql.NullInt64
+		var endpoint, actor, diff, createdAt sql.NullString
+		if err := rows.Scan(&e.ID, &suiteID, &e.Path, &e.Action, &endpoint, &actor, &diff, &createdAt); err != nil {
+			return nil, 0, err
+		}
+		if suiteID.Valid {
+			e.SuiteID = &suiteID.Int64
+		}
+		e.Endpoint = endpoint.String
+		e.Actor = actor.String
+		e.Diff = diff.String
+		e.CreatedAt = parseTime(createdAt)
+		entries = append(entries, e)
+	}
+
+	return entries, total, rows.Err()
+}
+
+// ==================== Run Comments ====================
+
+// CreateComment adds a comment to a run, or to one of its tests
+func (r *Repository) CreateComment(ctx context.Context, cm *models.Comment) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if cm.CreatedAt == nil {
+		now := time.Now().UTC()
+		cm.CreatedAt = &now
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO run_comments (run_id, test_id, author, text, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, cm.RunID, sql.NullString{String: cm.TestID, Valid: cm.TestID != ""}, cm.Author, cm.Text, formatUTC(*cm.CreatedAt))
+	if err != nil {
+		return err
+	}
+
+	cm.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// ListComments returns the comments on a run and its tests, oldest first.
+// A non-nil testID selects the comments on that test; "" selects those on
+// the run itself.
+func (r *Repository) ListComments(ctx context.Context, runID string, testID *string) ([]models.Comment, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	query := `SELECT id, run_id, test_id, author, text, created_at FROM run_comments WHERE run_id = ?`
+	args := []any{runID}
+	if testID != nil {
+		query += ` AND COALESCE(test_id, '') = ?`
+		args = append(args, *testID)
+	}
+	query += ` ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []models.Comment{}
+	for rows.Next() {
+		var cm models.Comment
+		var testID, createdAt sql.NullString
+		if err := rows.Scan(&cm.ID, &cm.RunID, &testID, &cm.Author, &cm.Text, &createdAt); err != nil {
+			return nil, err
+		}
+		cm.TestID = testID.String
+		cm.CreatedAt = parseTime(createdAt)
+		comments = append(comments, cm)
+	}
+	return comments, rows.Err()
+}
+
+// DeleteComment deletes a comment of a run, reporting whether it existed
+func (r *Repository) DeleteComment(ctx context.Context, runID string, id int64) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	result, err := r.db.ExecContext(ctx, `DELETE FROM run_comments WHERE run_id = ? AND id = ?`, runID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// ==================== Test Issues ====================
+
+const testIssueColumns = `id, suite_id, test_id, provider, issue_key, url, opened_run_id, opened_at,
+		closed_run_id, closed_at`
+
+// scanTestIssue scans a row selected with testIssueColumns
+func scanTestIssue(row rowScanner) (*models.TestIssue, error) {
+	var i models.TestIssue
+	var url, openedRunID, openedAt, closedRunID, closedAt sql.NullString
+	err := row.Scan(&i.ID, &i.SuiteID, &i.TestID, &i.Provider, &i.IssueKey, &url, &openedRunID, &openedAt,
+		&closedRunID, &closedAt)
+	if err != nil {
+		return nil, err
+	}
+	i.URL = url.String
+	i.OpenedRunID = openedRunID.String
+	i.OpenedAt = parseTime(openedAt)
+	i.ClosedRunID = closedRunID.String
+	i.ClosedAt = parseTime(closedAt)
+	return &i, nil
+}
+
+// CreateTestIssue records an issue opened for a test
+func (r *Repository) CreateTestIssue(ctx context.Context, i *models.TestIssue) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if i.OpenedAt == nil {
+		now := time.Now().UTC()
+		i.OpenedAt = &now
+	}
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO test_issues (suite_id, test_id, provider, issue_key, url, opened_run_id, opened_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, i.SuiteID, i.TestID, i.Provider, i.IssueKey, i.URL, i.OpenedRunID, formatUTC(*i.OpenedAt))
+	if err != nil {
+		return err
+	}
+	i.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetOpenTestIssue returns the open issue of a suite's test, or nil if it
+// has none
+func (r *Repository) GetOpenTestIssue(ctx context.Context, suiteID int64, testID string) (*models.TestIssue, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	i, err := scanTestIssue(r.db.QueryRowContext(ctx, `
+		SELECT `+testIssueColumns+` FROM test_issues
+		WHERE suite_id = ? AND test_id = ? AND closed_at IS NULL
+		ORDER BY id DESC LIMIT 1
+	`, suiteID, testID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return i, err
+}
+
+// CloseTestIssue records that the test of an issue passed again in a run
+func (r *Repository) CloseTestIssue(ctx context.Context, id int64, runID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `UPDATE test_issues SET closed_run_id = ?, closed_at = ? WHERE id = ?`,
+		runID, formatUTC(time.Now().UTC()), id)
+	return err
+}
+
+// ListTestIssues returns the issues opened for a suite's tests, newest
+// first; only open ones if openOnly
+func (r *Repository) ListTestIssues(ctx context.Context, suiteID int64, openOnly bool) ([]models.TestIssue, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	query := `SELECT ` + testIssueColumns + ` FROM test_issues WHERE suite_id = ?`
+	if openOnly {
+		query += ` AND closed_at IS NULL`
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY id DESC`, suiteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	issues := []models.TestIssue{}
+	for rows.Next() {
+		i, err := scanTestIssue(rows)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, *i)
+	}
+	return issues, rows.Err()
+}
+
+// ==================== Pipeline Runs ====================
+
+const pipelineRunColumns = `pipeline_run_id, name, file_path, mode, status, total_suites,
+		started_at, finished_at, duration_ms, cancel_requested`
+
+// scanPipelineRun scans a row selected with pipelineRunColumns
+func scanPipelineRun(row rowScanner) (*models.PipelineRun, error) {
+	var p models.PipelineRun
+	var filePath, finishedAt sql.NullString
+	var startedAt string
+	var durationMS sql.NullInt64
+	err := row.Scan(&p.PipelineRunID, &p.Name, &filePath, &p.Mode, &p.Status, &p.TotalSuites,
+		&startedAt, &finishedAt, &durationMS, &p.CancelRequested)
+	if err != nil {
+		return nil, err
+	}
+	p.FilePath = filePath.String
+	p.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+	p.FinishedAt = parseTime(finishedAt)
+	if durationMS.Valid {
+		p.DurationMS = &durationMS.Int64
+	}
+	return &p, nil
+}
+
+// CreatePipelineRun creates a pipeline run
+func (r *Repository) CreatePipelineRun(ctx context.Context, p *models.PipelineRun) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO pipeline_runs (pipeline_run_id, name, file_path, mode, status, total_suites, started_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, p.PipelineRunID, p.Name, p.FilePath, p.Mode, p.Status, p.TotalSuites, formatUTC(p.StartedAt))
+	return err
+}
+
+// GetPipelineRunByID returns a pipeline run, or nil if it does not exist
+func (r *Repository) GetPipelineRunByID(ctx context.Context, id string) (*models.PipelineRun, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	p, err := scanPipelineRun(r.db.QueryRowContext(ctx, `SELECT `+pipelineRunColumns+` FROM pipeline_runs WHERE pipeline_run_id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return p, err
+}
+
+// ListPipelineRuns returns one page of pipeline runs, newest first, and the
+// total number of pipeline runs
+func (r *Repository) ListPipelineRuns(ctx context.Context, limit, offset int) ([]models.PipelineRun, int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM pipeline_runs`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	if limit <= 0 {
+		limit = -1 // SQLite requires LIMIT with OFFSET
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+pipelineRunColumns+`
+		FROM pipeline_runs
+		ORDER BY started_at DESC, pipeline_run_id DESC
+		LIMIT ? OFFSET ?
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	pipelines := []models.PipelineRun{}
+	for rows.Next() {
+		p, err := scanPipelineRun(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		pipelines = append(pipelines, *p)
+	}
+	return pipelines, total, rows.Err()
+}
+
+// SetPipelineCancelRequested requests cancellation of a pipeline run and of
+// its suite runs still in progress
+func (r *Repository) SetPipelineCancelRequested(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE pipeline_runs SET cancel_requested = 1 WHERE pipeline_run_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE runs SET cancel_requested = 1
+		WHERE pipeline_run_id = ? AND status IN ('pending', 'running')
+	`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FinishPipelineRun sets the final status of a pipeline run: cancelled if
+// cancellation was requested, failed if any of its suite runs did not
+// complete or suitesFailed suites failed without a run of their own,
+// completed otherwise
+func (r *Repository) FinishPipelineRun(ctx context.Context, id string, suitesFailed int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	now := formatUTC(time.Now())
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE pipeline_runs SET
+			status = CASE
+				WHEN cancel_requested = 1 THEN 'cancelled'
+				WHEN ? > 0 THEN 'failed'
+				WHEN EXISTS (SELECT 1 FROM runs WHERE pipeline_run_id = ? AND status != 'completed') THEN 'failed'
+				ELSE 'completed'
+			END,
+			finished_at = ?,
+			duration_ms = CAST(
+				ROUND((julianday(?) - julianday(started_at)) * 24 * 60 * 60 * 1000) AS INTEGER
+			)
+		WHERE pipeline_run_id = ?
+	`, suitesFailed, id, now, now, id)
+	return err
+}
+
+// ==================== Heartbeats ====================
+
+// StaleTest is a running test marked crashed by MarkStaleTestsCrashed
+type StaleTest struct {
+	RunID      string
+	TestID     string
+	DurationMS int64
+}
+
+// TouchTestHeartbeat records a runner heartbeat for a test. It returns the
+// test's current status, or "" if the test does not exist.
+func (r *Repository) TouchTestHeartbeat(ctx context.Context, runID, testID string) (models.TestStatus, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE test_results SET last_heartbeat_at = ?
+		WHERE run_id = ? AND test_id = ?
+	`, formatUTC(time.Now()), runID, testID)
+	if err != nil {
+		return "", err
+	}
+
+	var status models.TestStatus
+	err = r.db.QueryRowContext(ctx, `
+		SELECT status FROM test_results WHERE run_id = ? AND test_id = ?
+	`, runID, testID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return status, err
+}
+
+// MarkStaleTestsCrashed marks running tests whose last heartbeat is older than
+// cutoff as crashed, finished at their last heartbeat. Tests that never sent a
+// heartbeat are left alone, since not every runner sends them.
+func (r *Repository) MarkStaleTestsCrashed(ctx context.Context, cutoff time.Time, reason string) ([]StaleTest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, run_id, test_id, started_at, last_heartbeat_at FROM test_results
+		WHERE status = 'running'
+		  AND last_heartbeat_at IS NOT NULL
+		  AND julianday(last_heartbeat_at) < julianday(?)
+	`, formatUTC(cutoff))
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		id            int64
+		stale         StaleTest
+		startedAt     sql.NullString
+		lastHeartbeat sql.NullString
+	}
+	var candidates []candidate
+	for rows.Next() {
+		var c candidate
+		if err := rows.Scan(&c.id, &c.stale.RunID, &c.stale.TestID, &c.startedAt, &c.lastHeartbeat); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var crashed []StaleTest
+	for _, c := range candidates {
+		finishedAt := time.Now()
+		if lastHeartbeat := parseTime(c.lastHeartbeat); lastHeartbeat != nil {
+			finishedAt = *lastHeartbeat
+		}
+		if startedAt := parseTime(c.startedAt); startedAt != nil && finishedAt.After(*startedAt) {
+			c.stale.DurationMS = finishedAt.Sub(*startedAt).Milliseconds()
+		}
+
+		ok, err := r.markTestCrashed(ctx, c.id, c.stale, finishedAt, reason)
+		if err != nil {
+			return crashed, err
+		}
+		if ok {
+			crashed = append(crashed, c.stale)
+		}
+	}
+	return crashed, nil
+}
+
+// markTestCrashed marks one running test crashed and updates run counters.
+// It reports false if the test finished in the meantime.
+func (r *Repository) markTestCrashed(ctx context.Context, id int64, stale StaleTest, finishedAt time.Time, reason string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE test_results SET
+			status = 'crashed',
+			finished_at = ?,
+			duration_ms = ?,
+			error_message = ?
+		WHERE id = ? AND status = 'running'
+	`, formatUTC(finishedAt), stale.DurationMS, reason, id)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	if err := updateRunCountersIncremental(ctx, tx, stale.RunID, models.TestStatusRunning, models.TestStatusCrashed); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// FinalizeAbandonedRuns completes running runs that have no running tests and
+// no test activity since cutoff, i.e. whose CLI went away. Pending tests are
+// marked skipped with reason. It returns the finalized run IDs.
+func (r *Repository) FinalizeAbandonedRuns(ctx context.Context, cutoff time.Time, reason string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	runIDs, err := r.idleRunIDs(ctx, cutoff, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, runID := range runIDs {
+		if _, err := r.db.ExecContext(ctx, `
+			UPDATE test_results SET status = 'skipped', skip_reason = ?
+			WHERE run_id = ? AND status = 'pending'
+		`, reason, runID); err != nil {
+			return nil, err
+		}
+		if err := r.UpdateRunCounters(ctx, runID); err != nil {
+			return nil, err
+		}
+		if err := r.CompleteRun(ctx, runID); err != nil {
+			return nil, err
+		}
+	}
+	return runIDs, nil
+}
+
+// OrphanedRun is a run cancelled by RecoverOrphanedRuns
+type OrphanedRun struct {
+	RunID        string
+	CrashedTests []StaleTest
+}
+
+// RecoverOrphanedRuns cancels pending and running runs with no activity since
+// cutoff. It is meant for API startup, when runs left over from before a
+// restart have lost their CLI and runners: running tests are marked crashed
+// and pending tests skipped, both with reason.
+func (r *Repository) RecoverOrphanedRuns(ctx context.Context, cutoff time.Time, reason string) ([]OrphanedRun, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	runIDs, err := r.idleRunIDs(ctx, cutoff, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var recovered []OrphanedRun
+	for _, runID := range runIDs {
+		orphan, err := r.cancelOrphanedRun(ctx, runID, reason)
+		if err != nil {
+			return recovered, err
+		}
+		recovered = append(recovered, *orphan)
+	}
+	return recovered, nil
+}
+
+// cancelOrphanedRun crashes running tests, skips pending ones and cancels the run
+func (r *Repository) cancelOrphanedRun(ctx context.Context, runID, reason string) (*OrphanedRun, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT test_id, started_at, last_heartbeat_at FROM test_results
+		WHERE run_id = ? AND status = 'running'
+		ORDER BY test_id
+	`, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	orphan := &OrphanedRun{RunID: runID}
+	now := time.Now()
+	for rows.Next() {
+		var startedAt, lastHeartbeat sql.NullString
+		stale := StaleTest{RunID: runID}
+		if err := rows.Scan(&stale.TestID, &startedAt, &lastHeartbeat); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		finishedAt := now
+		if t := parseTime(lastHeartbeat); t != nil {
+			finishedAt = *t
+		}
+		if t := parseTime(startedAt); t != nil && finishedAt.After(*t) {
+			stale.DurationMS = finishedAt.Sub(*t).Milliseconds()
+		}
+		orphan.CrashedTests = append(orphan.CrashedTests, stale)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, stale := range orphan.CrashedTests {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE test_results SET
+				status = 'crashed',
+				finished_at = COALESCE(last_heartbeat_at, ?),
+				duration_ms = ?,
+				error_message = ?
+			WHERE run_id = ? AND test_id = ? AND status = 'running'
+		`, formatUTC(now), stale.DurationMS, reason, runID, stale.TestID); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE test_results SET status = 'skipped', skip_reason = ?
+		WHERE run_id = ? AND status = 'pending'
+	`, reason, runID); err != nil {
+		return nil, err
+	}
+
+	nowStr := formatUTC(now)
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE runs SET
+			status = 'cancelled',
+			finished_at = ?2,
+			duration_ms = CAST(
+				ROUND((julianday(?2) - julianday(started_at)) * 24 * 60 * 60 * 1000) AS INTEGER
+			),
+			pending_count = 0,
+			running_count = 0,
+			passed = (SELECT COUNT(*) FROM test_results WHERE run_id = ?1 AND status = 'passed'),
+			failed = (SELECT COUNT(*) FROM test_results WHERE run_id = ?1 AND status IN ('failed', 'crashed')),
+			skipped = (SELECT COUNT(*) FROM test_results WHERE run_id = ?1 AND status = 'skipped')
+		WHERE run_id = ?1
+	`, runID, nowStr); err != nil {
+		return nil, err
+	}
+
+	return orphan, tx.Commit()
+}
+
+// idleRunIDs returns pending/running runs started before cutoff whose tests
+// show no activity (start, finish or heartbeat) since cutoff. Unless
+// includeRunning is set, runs that still have a running test are excluded.
+func (r *Repository) idleRunIDs(ctx context.Context, cutoff time.Time, includeRunning bool) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.run_id FROM runs r
+		WHERE r.status IN ('pending', 'running')
+		  AND julianday(r.started_at) < julianday(?1)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM test_results tr
+		      WHERE tr.run_id = r.run_id
+		        AND ((tr.status = 'running' AND NOT ?2)
+		             OR julianday(tr.started_at) >= julianday(?1)
+		             OR julianday(tr.finished_at) >= julianday(?1)
+		             OR julianday(tr.last_heartbeat_at) >= julianday(?1))
+		  )
+		ORDER BY r.started_at
+	`, formatUTC(cutoff), includeRunning)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runIDs []string
+	for rows.Next() {
+		var runID string
+		if err := rows.Scan(&runID); err != nil {
+			return nil, err
+		}
+		runIDs = append(runIDs, runID)
+	}
+	return runIDs, rows.Err()
+}
+
 // Helper functions for null values
 func nullString(ns sql.NullString) interface{} {
 	if ns.Valid {
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// Store is the results database as the API server uses it. *Repository
+// implements it; handlers can be tested against a fake. Every call takes the
+// context of its request, and gives up when the context ends or after the
+// query timeout.
+type Store interface {
+	DualWriteStats() DualWriteStats
+	Ping(ctx context.Context) error
+
+	// Suites
+	GetAllSuites(ctx context.Context) ([]models.Suite, error)
+	GetSuiteByID(ctx context.Context, id int64) (*models.Suite, error)
+	GetSuiteByPath(ctx context.Context, folderPath string) (*models.Suite, error)
+	CreateSuite(ctx context.Context, s *models.Suite) error
+	UpdateSuite(ctx context.Context, s *models.Suite) error
+	DeleteSuite(ctx context.Context, id int64) error
+
+	// Runs
+	GetAllRuns(ctx context.Context, suiteID *int64, limit int) ([]models.Run, error)
+	ListRuns(ctx context.Context, f RunFilter) ([]models.Run, int, error)
+	GetPreviousRun(ctx context.Context, run *models.Run) (*models.Run, error)
+	GetFlakyTests(ctx context.Context, suiteID int64, runLimit int) ([]FlakyTest, error)
+	GetLatestRunsBySuite(ctx context.Context, statuses ...string) (map[int64]models.Run, error)
+	GetRunByID(ctx context.Context, runID string) (*models.Run, error)
+	GetLatestRun(ctx context.Context) (*models.Run, error)
+	GetRunningRun(ctx context.Context) (*models.Run, error)
+	SetCancelRequested(ctx context.Context, runID string) error
+	SetTestCancelRequested(ctx context.Context, testResultID int64) error
+	MarkRunCancelled(ctx context.Context, runID string) error
+
+	// Test results
+	GetTestResultsByRunID(ctx context.Context, runID string) ([]models.TestResult, error)
+	ListTestResults(ctx context.Context, runID string, f TestResultFilter) ([]models.TestResult, int, error)
+	GetBudgetViolations(ctx context.Context, runID string) ([]models.TestResult, error)
+	GetTestResultByID(ctx context.Context, id int64) (*models.TestResult, error)
+	GetStepResultsByTestID(ctx context.Context, testResultID int64) ([]models.StepResult, error)
+	GetAssertionsByTestID(ctx context.Context, testResultID int64) ([]models.AssertionResult, error)
+	GetCapturedValuesByTestID(ctx context.Context, testResultID int64) ([]models.CapturedValue, error)
+	SetCapturedValues(ctx context.Context, testResultID int64, values map[string]string) error
+
+	// Run creation and updates
+	CreateRun(ctx context.Context, run *models.Run) error
+	ClaimQueuedRun(ctx context.Context, run *models.Run) (bool, error)
+	FinishQueuedRun(ctx context.Context, runID string, status models.RunStatus) (bool, error)
+	CancelQueuedRuns(ctx context.Context) ([]string, error)
+	ActiveRunIDsBySuite(ctx context.Context, suiteID int64) ([]string, error)
+	CreateTestResult(ctx context.Context, tr *models.TestResult) error
+	UpdateTestResult(ctx context.Context, tr *models.TestResult) error
+	UpdateTestResultFull(ctx context.Context, tr *models.TestResult, oldStatus models.TestStatus, steps []*models.StepResult, assertions []*models.AssertionResult) error
+	ModifyTestResult(ctx context.Context, testID, runID string, modify func(tr *models.TestResult) ([]*models.StepResult, []*models.AssertionResult, error)) (*models.TestResult, error)
+	GetTestResultByTestIDAndRunID(ctx context.Context, testID, runID string) (*models.TestResult, error)
+	UpdateRunCounters(ctx context.Context, runID string) error
+	UpdateRunCountersIncremental(ctx context.Context, runID string, oldStatus, newStatus models.TestStatus) error
+	CompleteRun(ctx context.Context, runID string) error
+	SetRunSmokeStatus(ctx context.Context, runID, status string, budgetMS int64) error
+	UpdateRunStatus(ctx context.Context, runID string, status models.RunStatus) error
+	DeleteRun(ctx context.Context, runID string) error
+	CreateStepResult(ctx context.Context, sr *models.StepResult) error
+	CreateAssertionResult(ctx context.Context, ar *models.AssertionResult) error
+
+	// Statistics
+	GetRunStats(ctx context.Context) (*RunStats, error)
+	GetRunScheduling(ctx context.Context, run *models.Run) (*RunScheduling, error)
+	GetTestDurationStats(ctx context.Context, folderPath string, runLimit int) (map[string]TestDurationStat, error)
+	GetFailedAssertionsSince(ctx context.Context, since time.Time) ([]FailedAssertion, error)
+	GetRunCoverage(ctx context.Context, runID string) ([]CoveredFeature, error)
+
+	// Test aliases and history
+	RecordTestMove(ctx context.Context, suiteID int64, oldID, newID string) error
+	GetTestAliases(ctx context.Context, suiteID int64) ([]TestAlias, error)
+	GetTestHistory(ctx context.Context, suiteID int64, testID string, limit int) ([]models.TestResult, error)
+	GetLatestTestResult(ctx context.Context, suiteID int64, testID string, statuses ...models.TestStatus) (*models.TestResult, error)
+
+	// Audit log
+	CreateAuditEntry(ctx context.Context, e *models.AuditEntry) error
+	ListAuditEntries(ctx context.Context, f AuditFilter) ([]models.AuditEntry, int, error)
+
+	// Run comments
+	CreateComment(ctx context.Context, cm *models.Comment) error
+	ListComments(ctx context.Context, runID string, testID *string) ([]models.Comment, error)
+	DeleteComment(ctx context.Context, runID string, id int64) (bool, error)
+
+	// Test issues
+	CreateTestIssue(ctx context.Context, i *models.TestIssue) error
+	GetOpenTestIssue(ctx context.Context, suiteID int64, testID string) (*models.TestIssue, error)
+	CloseTestIssue(ctx context.Context, id int64, runID string) error
+	ListTestIssues(ctx context.Context, suiteID int64, openOnly bool) ([]models.TestIssue, error)
+
+	// Pipeline runs
+	CreatePipelineRun(ctx context.Context, p *models.PipelineRun) error
+	GetPipelineRunByID(ctx context.Context, id string) (*models.PipelineRun, error)
+	ListPipelineRuns(ctx context.Context, limit, offset int) ([]models.PipelineRun, int, error)
+	SetPipelineCancelRequested(ctx context.Context, id string) error
+	FinishPipelineRun(ctx context.Context, id string, suitesFailed int) error
+
+	// Heartbeats and recovery
+	TouchTestHeartbeat(ctx context.Context, runID, testID string) (models.TestStatus, error)
+	MarkStaleTestsCrashed(ctx context.Context, cutoff time.Time, reason string) ([]StaleTest, error)
+	FinalizeAbandonedRuns(ctx context.Context, cutoff time.Time, reason string) ([]string, error)
+	RecoverOrphanedRuns(ctx context.Context, cutoff time.Time, reason string) ([]OrphanedRun, error)
+}
+
+var _ Store = (*Repository)(nil)
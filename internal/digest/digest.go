@@ -0,0 +1,166 @@
+// Package digest renders the email digest of a finished run and sends it
+// over SMTP.
+package digest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/models"
+)
+
+// Digest sections, in the order they are rendered
+const (
+	SectionSummary     = "summary"
+	SectionNewFailures = "new_failures"
+	SectionFlaky       = "flaky"
+	SectionSlowest     = "slowest"
+)
+
+// AllSections are rendered when email.sections is not set
+var AllSections = []string{SectionSummary, SectionNewFailures, SectionFlaky, SectionSlowest}
+
+// Defaults for unset email settings
+const (
+	DefaultSlowest   = 5
+	DefaultFlakyRuns = 10
+)
+
+// Digest is the data of a run's digest
+type Digest struct {
+	SuiteName string
+	Run       *models.Run
+	RunURL    string // Dashboard page of the run; empty without email.dashboard_url
+
+	PreviousRunID string              // Previous finished run of the suite; empty if none
+	Failures      []models.TestResult // Failed and crashed tests of the run
+	NewFailures   []models.TestResult // Failures that did not fail in the previous run
+
+	Flaky     []db.FlakyTest
+	FlakyRuns int // Recent runs Flaky was found in
+
+	Slowest []models.TestResult
+}
+
+// Render returns the subject and plain text body of the digest with the
+// given sections
+func Render(d *Digest, sections []string) (subject, body string, err error) {
+	if len(sections) == 0 {
+		sections = AllSections
+	}
+
+	var b strings.Builder
+	run := d.Run
+	fmt.Fprintf(&b, "Suite %s, run %s: %s\n", d.SuiteName, shortID(run.RunID), run.Status)
+	if d.RunURL != "" {
+		fmt.Fprintf(&b, "%s\n", d.RunURL)
+	}
+
+	for _, section := range sections {
+		b.WriteString("\n")
+		switch section {
+		case SectionSummary:
+			d.renderSummary(&b)
+		case SectionNewFailures:
+			d.renderNewFailures(&b)
+		case SectionFlaky:
+			d.renderFlaky(&b)
+		case SectionSlowest:
+			d.renderSlowest(&b)
+		default:
+			return "", "", fmt.Errorf("unknown email section %q: must be %s", section, strings.Join(AllSections, ", "))
+		}
+	}
+
+	subject = fmt.Sprintf("[tsuite] %s: %s (%d passed, %d failed)", d.SuiteName, run.Status, run.Passed, run.Failed)
+	if n := len(d.NewFailures); n > 0 {
+		subject += fmt.Sprintf(", %d new failure(s)", n)
+	}
+	return subject, b.String(), nil
+}
+
+func (d *Digest) renderSummary(b *strings.Builder) {
+	run := d.Run
+	b.WriteString("Summary\n")
+	fmt.Fprintf(b, "  Tests:    %d (%d passed, %d failed, %d skipped)\n", run.TotalTests, run.Passed, run.Failed, run.Skipped)
+	if run.DurationMS.Valid {
+		fmt.Fprintf(b, "  Duration: %s\n", formatDuration(run.DurationMS.Int64))
+	}
+	fmt.Fprintf(b, "  Started:  %s\n", run.StartedAt.UTC().Format(time.RFC3339))
+	if run.Profile.Valid {
+		fmt.Fprintf(b, "  Profile:  %s\n", run.Profile.String)
+	}
+	if run.SDKPythonVersion.Valid || run.SDKTypescriptVersion.Valid {
+		fmt.Fprintf(b, "  SDK:      python %s, typescript %s\n", orDash(run.SDKPythonVersion.String), orDash(run.SDKTypescriptVersion.String))
+	}
+}
+
+func (d *Digest) renderNewFailures(b *strings.Builder) {
+	if d.PreviousRunID == "" {
+		b.WriteString("Failures (no previous run to compare with)\n")
+		writeFailures(b, d.Failures)
+		return
+	}
+	fmt.Fprintf(b, "New failures since the previous run (%s)\n", shortID(d.PreviousRunID))
+	writeFailures(b, d.NewFailures)
+	if still := len(d.Failures) - len(d.NewFailures); still > 0 {
+		fmt.Fprintf(b, "  %d test(s) also failed in the previous run\n", still)
+	}
+}
+
+func writeFailures(b *strings.Builder, failures []models.TestResult) {
+	if len(failures) == 0 {
+		b.WriteString("  None\n")
+		return
+	}
+	for _, tr := range failures {
+		fmt.Fprintf(b, "  ✗ %s", tr.TestID)
+		if msg, _, _ := strings.Cut(strings.TrimSpace(tr.ErrorMessage.String), "\n"); msg != "" {
+			fmt.Fprintf(b, ": %s", msg)
+		}
+		b.WriteString("\n")
+	}
+}
+
+func (d *Digest) renderFlaky(b *strings.Builder) {
+	fmt.Fprintf(b, "Flaky tests (passed and failed in the last %d runs)\n", d.FlakyRuns)
+	if len(d.Flaky) == 0 {
+		b.WriteString("  None\n")
+		return
+	}
+	for _, f := range d.Flaky {
+		fmt.Fprintf(b, "  ~ %s: %d passed, %d failed\n", f.TestID, f.Passed, f.Failed)
+	}
+}
+
+func (d *Digest) renderSlowest(b *strings.Builder) {
+	b.WriteString("Slowest tests\n")
+	if len(d.Slowest) == 0 {
+		b.WriteString("  None\n")
+		return
+	}
+	for _, tr := range d.Slowest {
+		fmt.Fprintf(b, "  %8s  %s\n", formatDuration(tr.DurationMS.Int64), tr.TestID)
+	}
+}
+
+// shortID is the 12-character run ID prefix tsuite prints
+func shortID(runID string) string {
+	if len(runID) > 12 {
+		return runID[:12]
+	}
+	return runID
+}
+
+func formatDuration(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
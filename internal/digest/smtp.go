@@ -0,0 +1,104 @@
+package digest
+
+import (
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// DefaultSMTPPort is the submission port, used with STARTTLS
+const DefaultSMTPPort = 587
+
+// smtpTimeout bounds connecting to and talking with the SMTP server
+const smtpTimeout = time.Minute
+
+// Send emails a digest to email.to. STARTTLS is used when the server offers
+// it; credentials are read from the environment variables the settings name.
+func Send(cfg config.EmailSettings, subject, body string) error {
+	if len(cfg.To) == 0 {
+		return fmt.Errorf("email.to lists no recipients")
+	}
+	if cfg.SMTPHost == "" || cfg.From == "" {
+		return fmt.Errorf("email needs smtp_host and from")
+	}
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = DefaultSMTPPort
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(port)), smtpTimeout)
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(smtpTimeout))
+	c, err := smtp.NewClient(conn, cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: cfg.SMTPHost}); err != nil {
+			return err
+		}
+	}
+	if user := envOr(cfg.UsernameEnv, "SMTP_USERNAME"); user != "" {
+		// PlainAuth refuses to send credentials without TLS, except to localhost
+		auth := smtp.PlainAuth("", user, envOr(cfg.PasswordEnv, "SMTP_PASSWORD"), cfg.SMTPHost)
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(cfg.From); err != nil {
+		return err
+	}
+	for _, to := range cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("recipient %s: %w", to, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message(cfg.From, cfg.To, subject, body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message formats a plain text UTF-8 email
+func message(from string, to []string, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// envOr reads the environment variable named by name, or by fallback if
+// name is empty
+func envOr(name, fallback string) string {
+	if name == "" {
+		name = fallback
+	}
+	return os.Getenv(name)
+}
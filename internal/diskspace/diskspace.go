@@ -0,0 +1,62 @@
+// Package diskspace checks free space on the filesystems runs write to, so
+// runs don't fail halfway with corrupted results when a disk fills up.
+package diskspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// DefaultMinFreeMB is the free space below which runs refuse to start
+const DefaultMinFreeMB = 500
+
+// Low is a filesystem with less free space than required
+type Low struct {
+	Path   string // Directory checked
+	FreeMB uint64
+	MinMB  uint64
+}
+
+func (l Low) String() string {
+	return fmt.Sprintf("%s has %d MB free (minimum %d MB)", l.Path, l.FreeMB, l.MinMB)
+}
+
+// Paths returns the directories runs write to: the temp dir holding test
+// workdirs and ~/.tsuite holding the results database and logs
+func Paths() []string {
+	return []string{os.TempDir(), filepath.Join(os.Getenv("HOME"), ".tsuite")}
+}
+
+// FreeMB returns the space available to unprivileged users on the filesystem
+// holding path. Paths that don't exist yet are checked at their nearest
+// existing parent.
+func FreeMB(path string) (uint64, error) {
+	for {
+		var st syscall.Statfs_t
+		err := syscall.Statfs(path, &st)
+		if err == nil {
+			return uint64(st.Bavail) * uint64(st.Bsize) >> 20, nil
+		}
+		parent := filepath.Dir(path)
+		if !os.IsNotExist(err) || parent == path {
+			return 0, err
+		}
+		path = parent
+	}
+}
+
+// Check returns the paths whose filesystem has less than minMB free.
+// Filesystems that can't be inspected are skipped.
+func Check(paths []string, minMB uint64) []Low {
+	var low []Low
+	for _, path := range paths {
+		free, err := FreeMB(path)
+		if err != nil || free >= minMB {
+			continue
+		}
+		low = append(low, Low{Path: path, FreeMB: free, MinMB: minMB})
+	}
+	return low
+}
@@ -4,26 +4,35 @@ package executor
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
 )
 
 // CancelChecker polls the API for cancel requests and cancels the context when requested.
+// Tests cancelled on their own are passed to onTestCancel, once each.
 type CancelChecker struct {
-	client     *client.Client
-	runID      string
-	cancelFunc context.CancelFunc
-	interval   time.Duration
+	client       *client.Client
+	runID        string
+	cancelFunc   context.CancelFunc
+	onTestCancel func(testID string)
+	out          io.Writer // The run's progress output
+	interval     time.Duration
+	seen         map[string]bool
 }
 
-// NewCancelChecker creates a new cancel checker.
-func NewCancelChecker(apiClient *client.Client, runID string, cancelFunc context.CancelFunc) *CancelChecker {
+// NewCancelChecker creates a new cancel checker that reports a cancelled run
+// to out. onTestCancel may be nil.
+func NewCancelChecker(apiClient *client.Client, runID string, cancelFunc context.CancelFunc, onTestCancel func(testID string), out io.Writer) *CancelChecker {
 	return &CancelChecker{
-		client:     apiClient,
-		runID:      runID,
-		cancelFunc: cancelFunc,
-		interval:   2 * time.Second,
+		client:       apiClient,
+		runID:        runID,
+		cancelFunc:   cancelFunc,
+		onTestCancel: onTestCancel,
+		out:          out,
+		interval:     2 * time.Second,
+		seen:         make(map[string]bool),
 	}
 }
 
@@ -39,11 +48,18 @@ func (cc *CancelChecker) Start(ctx context.Context) {
 				return
 			case <-ticker.C:
 				if cc.runID != "" {
-					if isCancelled, _ := cc.client.CheckCancelRequested(cc.runID); isCancelled {
-						fmt.Println("\n[CANCEL] Cancel requested - terminating...")
+					isCancelled, tests, _ := cc.client.CheckCancelRequests(cc.runID)
+					if isCancelled {
+						fmt.Fprintln(cc.out, "\n[CANCEL] Cancel requested - terminating...")
 						cc.cancelFunc()
 						return
 					}
+					for _, testID := range tests {
+						if !cc.seen[testID] && cc.onTestCancel != nil {
+							cc.seen[testID] = true
+							cc.onTestCancel(testID)
+						}
+					}
 				}
 			}
 		}
@@ -52,7 +68,7 @@ func (cc *CancelChecker) Start(ctx context.Context) {
 
 // StartCancelChecker is a convenience function that creates and starts a cancel checker.
 // This is the most common usage pattern.
-func StartCancelChecker(ctx context.Context, cancelFunc context.CancelFunc, apiClient *client.Client, runID string) {
-	cc := NewCancelChecker(apiClient, runID, cancelFunc)
+func StartCancelChecker(ctx context.Context, cancelFunc context.CancelFunc, apiClient *client.Client, runID string, onTestCancel func(testID string), out io.Writer) {
+	cc := NewCancelChecker(apiClient, runID, cancelFunc, onTestCancel, out)
 	cc.Start(ctx)
 }
@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"sort"
+	"time"
+)
+
+// PlannedTest is a test with its estimated duration.
+type PlannedTest struct {
+	TestID     string
+	Estimate   time.Duration
+	HasHistory bool // false if Estimate is a default guess
+}
+
+// EstimateTests pairs tests with their average duration from history. Tests
+// without history are estimated at fallback, or, if fallback is zero, at the
+// median of the known averages (a minute when nothing is known). It returns
+// the estimate used for tests without history.
+func EstimateTests(testIDs []string, history map[string]time.Duration, fallback time.Duration) ([]PlannedTest, time.Duration) {
+	if fallback <= 0 {
+		var known []time.Duration
+		for _, t := range testIDs {
+			if d, ok := history[t]; ok {
+				known = append(known, d)
+			}
+		}
+		fallback = time.Minute
+		if len(known) > 0 {
+			sort.Slice(known, func(i, j int) bool { return known[i] < known[j] })
+			fallback = known[len(known)/2]
+		}
+	}
+
+	planned := make([]PlannedTest, len(testIDs))
+	for i, t := range testIDs {
+		planned[i] = PlannedTest{TestID: t, Estimate: fallback}
+		if d, ok := history[t]; ok {
+			planned[i].Estimate = d
+			planned[i].HasHistory = true
+		}
+	}
+	return planned, fallback
+}
+
+// WorkerPlan is the predicted load of a single worker.
+type WorkerPlan struct {
+	Worker int
+	Tests  []PlannedTest
+	Total  time.Duration
+}
+
+// Plan is a predicted schedule for a test set.
+type Plan struct {
+	Workers      []WorkerPlan
+	WallClock    time.Duration // Time until the last worker finishes
+	TotalWork    time.Duration // Sum of all test estimates
+	CriticalPath []PlannedTest // Tests on the worker that finishes last
+	LongestTest  PlannedTest   // Lower bound on wall-clock time
+}
+
+// Utilization returns the fraction of worker time spent running tests.
+func (p *Plan) Utilization() float64 {
+	if p.WallClock == 0 || len(p.Workers) == 0 {
+		return 0
+	}
+	return float64(p.TotalWork) / (float64(p.WallClock) * float64(len(p.Workers)))
+}
+
+// SimulateSchedule predicts how tests are distributed across workers.
+// Tests are dispatched in the given order to whichever worker frees up first,
+// matching how the CLI feeds its worker pool.
+func SimulateSchedule(tests []PlannedTest, workers int) *Plan {
+	if workers < 1 {
+		workers = 1
+	}
+
+	plan := &Plan{Workers: make([]WorkerPlan, workers)}
+	for i := range plan.Workers {
+		plan.Workers[i].Worker = i + 1
+	}
+
+	for _, t := range tests {
+		// Pick the worker that becomes free first (lowest index on ties)
+		next := 0
+		for i := 1; i < workers; i++ {
+			if plan.Workers[i].Total < plan.Workers[next].Total {
+				next = i
+			}
+		}
+		plan.Workers[next].Tests = append(plan.Workers[next].Tests, t)
+		plan.Workers[next].Total += t.Estimate
+
+		plan.TotalWork += t.Estimate
+		if t.Estimate > plan.LongestTest.Estimate {
+			plan.LongestTest = t
+		}
+	}
+
+	last := 0
+	for i := range plan.Workers {
+		if plan.Workers[i].Total > plan.Workers[last].Total {
+			last = i
+		}
+	}
+	plan.WallClock = plan.Workers[last].Total
+	plan.CriticalPath = plan.Workers[last].Tests
+
+	return plan
+}
+
+// SortLongestFirst orders tests by descending estimate (stable for equal estimates).
+func SortLongestFirst(tests []PlannedTest) []PlannedTest {
+	sorted := make([]PlannedTest, len(tests))
+	copy(sorted, tests)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Estimate > sorted[j].Estimate
+	})
+	return sorted
+}
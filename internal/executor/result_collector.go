@@ -3,17 +3,20 @@ package executor
 
 import (
 	"fmt"
+	"io"
 	"sync"
 	"time"
 )
 
 // TestResult represents the outcome of a single test execution.
 type TestResult struct {
-	TestID    string
-	Passed    bool
-	Error     string
-	Duration  time.Duration
-	Cancelled bool
+	TestID     string
+	Passed     bool
+	Error      string
+	Duration   time.Duration
+	Cancelled  bool
+	Skipped    bool // Skipped by a skip_if condition
+	SkipReason string
 }
 
 // TestResults holds the aggregated test results.
@@ -26,23 +29,26 @@ type TestResults struct {
 }
 
 // CollectResults reads from a result channel and aggregates test outcomes.
-// It prints status messages for each test and returns aggregated results.
+// It prints status messages for each test to out and returns aggregated results.
 // The mutex is used to safely accumulate results when called concurrently.
-func CollectResults(resultCh <-chan TestResult) TestResults {
+func CollectResults(resultCh <-chan TestResult, out io.Writer) TestResults {
 	var results TestResults
 	var mu sync.Mutex
 
 	for result := range resultCh {
 		mu.Lock()
 		if result.Cancelled {
-			fmt.Printf("[SKIP] %s (cancelled)\n", result.TestID)
+			fmt.Fprintf(out, "[SKIP] %s (cancelled)\n", result.TestID)
 			results.Skipped++
 			results.Cancelled = true
+		} else if result.Skipped {
+			fmt.Fprintf(out, "[SKIP] %s (%s)\n", result.TestID, result.SkipReason)
+			results.Skipped++
 		} else if result.Passed {
-			fmt.Printf("[PASS] %s (%.1fs)\n", result.TestID, result.Duration.Seconds())
+			fmt.Fprintf(out, "[PASS] %s (%.1fs)\n", result.TestID, result.Duration.Seconds())
 			results.Passed++
 		} else {
-			fmt.Printf("[FAIL] %s - %s (%.1fs)\n", result.TestID, result.Error, result.Duration.Seconds())
+			fmt.Fprintf(out, "[FAIL] %s - %s (%.1fs)\n", result.TestID, result.Error, result.Duration.Seconds())
 			results.Failed++
 			results.FailedTests = append(results.FailedTests, result.TestID)
 		}
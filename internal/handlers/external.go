@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+	ext "github.com/dhyansraj/mcp-mesh-test-suite/go/pkg/handlers"
+)
+
+// externalHandler adapts a handler registered through the public API
+type externalHandler struct {
+	handler ext.Handler
+}
+
+func (h *externalHandler) Name() string {
+	return h.handler.Name()
+}
+
+func (h *externalHandler) Execute(step map[string]any, ctx *interpolate.Context) StepResult {
+	result := h.handler.Execute(step, publicContext(ctx))
+	return StepResult{
+		Success:  result.Success,
+		ExitCode: result.ExitCode,
+		Stdout:   result.Stdout,
+		Stderr:   result.Stderr,
+		Error:    result.Error,
+	}
+}
+
+// publicContext copies the fields of an interpolation context exposed to external handlers
+func publicContext(ctx *interpolate.Context) *ext.Context {
+	return &ext.Context{
+		SuitePath:   ctx.SuitePath,
+		Workdir:     ctx.Workdir,
+		FixturesDir: ctx.FixturesDir,
+		Artifacts:   ctx.Artifacts,
+		Config:      copyMap(ctx.Config),
+		State:       copyMap(ctx.State),
+		Captured:    copyMap(ctx.Captured),
+		Params:      copyMap(ctx.Params),
+	}
+}
+
+func copyMap(m map[string]any) map[string]any {
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
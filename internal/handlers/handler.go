@@ -1,7 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"os/exec"
+	"sort"
+	"syscall"
+	"time"
+
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+	ext "github.com/dhyansraj/mcp-mesh-test-suite/go/pkg/handlers"
 )
 
 // StepResult holds the result of executing a step
@@ -11,6 +18,9 @@ type StepResult struct {
 	Stdout   string `json:"stdout"`
 	Stderr   string `json:"stderr"`
 	Error    string `json:"error,omitempty"`
+
+	// Values recorded on the test result, e.g. meshctl.version
+	Recorded map[string]string `json:"recorded,omitempty"`
 }
 
 // Handler is the interface for all step handlers
@@ -39,6 +49,15 @@ func NewRegistry() *Registry {
 	r.Register(&HTTPHandler{})
 	r.Register(&NpmInstallHandler{})
 	r.Register(&PipInstallHandler{})
+	r.Register(&MeshctlInstallHandler{})
+	r.Register(&MCPHandler{})
+	r.Register(&RegistrySnapshotHandler{})
+	r.Register(NewProcessHandler())
+
+	// Register handlers compiled in through the public extension API
+	for _, h := range ext.Registered() {
+		r.Register(&externalHandler{handler: h})
+	}
 
 	return r
 }
@@ -54,6 +73,24 @@ func (r *Registry) Get(name string) (Handler, bool) {
 	return h, ok
 }
 
+// Cleanup releases resources held by handlers (e.g. background processes)
+// and returns a description of each resource released
+func (r *Registry) Cleanup() []string {
+	names := make([]string, 0, len(r.handlers))
+	for name := range r.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var released []string
+	for _, name := range names {
+		if c, ok := r.handlers[name].(Cleaner); ok {
+			released = append(released, c.Cleanup()...)
+		}
+	}
+	return released
+}
+
 // Execute runs a step using the appropriate handler
 func (r *Registry) Execute(handlerName string, step map[string]any, ctx *interpolate.Context) StepResult {
 	handler, ok := r.Get(handlerName)
@@ -66,3 +103,33 @@ func (r *Registry) Execute(handlerName string, step map[string]any, ctx *interpo
 
 	return handler.Execute(step, ctx)
 }
+
+// stepWaitDelay bounds how long a step waits for the output of processes
+// that outlive the command it killed
+const stepWaitDelay = 5 * time.Second
+
+// stepContext returns the context a step's commands run under. It is done
+// when timeout elapses or the test is cancelled.
+func stepContext(ctx *interpolate.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx.RunContext(), timeout)
+}
+
+// stepCancelled returns why the step was cancelled (e.g. "test cancelled"),
+// or nil if it wasn't
+func stepCancelled(ctx *interpolate.Context) error {
+	if ctx.RunContext().Err() == nil {
+		return nil
+	}
+	return context.Cause(ctx.RunContext())
+}
+
+// killProcessGroup runs cmd in its own process group and kills the whole
+// group when cmd's context is done, so children of a shell don't keep the
+// step running
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = stepWaitDelay
+}
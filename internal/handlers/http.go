@@ -77,7 +77,7 @@ func (h *HTTPHandler) Execute(step map[string]any, ctx *interpolate.Context) Ste
 	}
 
 	// Create request
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx.RunContext(), method, url, bodyReader)
 	if err != nil {
 		return StepResult{
 			Success:  false,
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// Directories of locally built mcp-mesh packages that replace the published
+// ones (local package mode). Containers have them at /wheels and /packages;
+// standalone runs of packages.local sources get them through these.
+const (
+	EnvWheelsDir   = "TSUITE_WHEELS_DIR"
+	EnvPackagesDir = "TSUITE_PACKAGES_DIR"
+)
+
+// localPackageDir returns the local package directory named by env, else
+// dir, or "" if it doesn't exist (published mode)
+func localPackageDir(env, dir string) string {
+	if v := os.Getenv(env); v != "" {
+		dir = v
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return ""
+	}
+	return dir
+}
+
+// runInstall runs an install command. It returns the failed step's result
+// and false if the command failed, timed out or was cancelled.
+func runInstall(cmdCtx context.Context, cmd *exec.Cmd, name string, stdout, stderr *bytes.Buffer) (StepResult, bool) {
+	killProcessGroup(cmd)
+	err := cmd.Run()
+	if err == nil {
+		return StepResult{}, true
+	}
+	if cmdCtx.Err() == context.Canceled {
+		return StepResult{
+			Success:  false,
+			ExitCode: 130,
+			Stdout:   stdout.String(),
+			Stderr:   stderr.String(),
+			Error:    name + " killed: " + context.Cause(cmdCtx).Error(),
+		}, false
+	}
+	if cmdCtx.Err() == context.DeadlineExceeded {
+		return StepResult{
+			Success:  false,
+			ExitCode: 124,
+			Stdout:   stdout.String(),
+			Stderr:   stderr.String(),
+			Error:    name + " timed out",
+		}, false
+	}
+	return StepResult{
+		Success:  false,
+		ExitCode: 1,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Error:    fmt.Sprintf("%s failed: %v", name, err),
+	}, false
+}
+
+// installStamp records what an install step installed, so a later step
+// installing the same inputs into the same place can skip it. This matters
+// in standalone mode, where every test installs into the host's Python or a
+// shared node_modules; containers start without stamps.
+type installStamp struct {
+	path   string   // Stamp file under ~/.tsuite/installs
+	target string   // Directory installed into; modifying it invalidates the stamp
+	files  []string // Files installed from, e.g. requirements.txt
+	extra  []string // Other inputs, e.g. package names or the package mode
+}
+
+// newInstallStamp returns the stamp of installs by handler from key (a
+// requirements file or package directory) into target. It returns false
+// when stamps can't be kept.
+func newInstallStamp(handler, key, target string, files []string, extra ...string) (installStamp, bool) {
+	home := os.Getenv("HOME")
+	if home == "" || target == "" {
+		return installStamp{}, false
+	}
+	sum := sha256.Sum256([]byte(handler + "\x00" + key + "\x00" + target))
+	return installStamp{
+		path:   filepath.Join(home, ".tsuite", "installs", hex.EncodeToString(sum[:8])),
+		target: target,
+		files:  files,
+		extra:  extra,
+	}, true
+}
+
+// current returns the checksum of the inputs and the modification time of
+// the target, or "" if the target doesn't exist
+func (s installStamp) current() string {
+	info, err := os.Stat(s.target)
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	for _, path := range s.files {
+		fmt.Fprintf(h, "%s\x00", path)
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(h, "missing\x00")
+			continue
+		}
+		io.Copy(h, f)
+		f.Close()
+		h.Write([]byte{0})
+	}
+	for _, v := range s.extra {
+		fmt.Fprintf(h, "%s\x00", v)
+	}
+	return fmt.Sprintf("%x %d\n", h.Sum(nil), info.ModTime().UnixNano())
+}
+
+// unchanged reports whether the last recorded install had the same inputs
+// and nothing modified the target since
+func (s installStamp) unchanged() bool {
+	recorded, err := os.ReadFile(s.path)
+	if err != nil {
+		return false
+	}
+	current := s.current()
+	return current != "" && string(recorded) == current
+}
+
+// record writes the stamp after a successful install
+func (s installStamp) record() {
+	current := s.current()
+	if current == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return
+	}
+	os.WriteFile(s.path, []byte(current), 0644)
+}
+
+// dirListing describes the files matching a glob in dir by name, size and
+// modification time, so replacing a local wheel or tarball changes it
+func dirListing(dir, pattern string) string {
+	if dir == "" {
+		return ""
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+	sort.Strings(matches)
+	listing := ""
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil {
+			listing += fmt.Sprintf("%s %d %d\n", filepath.Base(path), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return listing
+}
+
+// reuseInstall reports whether an install step may skip an unchanged
+// install (reuse: false always installs)
+func reuseInstall(step map[string]any) bool {
+	if v, ok := step["reuse"].(bool); ok {
+		return v
+	}
+	return true
+}
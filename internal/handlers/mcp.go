@@ -0,0 +1,680 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// mcpProtocolVersion is the MCP protocol version requested during initialize
+const mcpProtocolVersion = "2025-03-26"
+
+// MCPHandler speaks the MCP protocol directly to an agent or server.
+//
+// Supported transports:
+//   - stdio: spawns `command` and exchanges newline-delimited JSON-RPC
+//   - sse:   legacy HTTP+SSE transport (GET url for the event stream, POST to the announced endpoint)
+//   - http:  streamable HTTP transport (POST to url, JSON or SSE responses)
+//
+// Supported actions: initialize, list_tools, list_resources, list_prompts,
+// call_tool, read_resource. The structured JSON-RPC result is written to
+// stdout so it can be captured and queried with ${jq:...}.
+type MCPHandler struct{}
+
+func (h *MCPHandler) Name() string {
+	return "mcp"
+}
+
+func (h *MCPHandler) Execute(step map[string]any, ctx *interpolate.Context) StepResult {
+	action, _ := step["action"].(string)
+	if action == "" {
+		action = "list_tools"
+	}
+
+	method, params, err := mcpRequest(action, step)
+	if err != nil {
+		return StepResult{Success: false, Error: err.Error()}
+	}
+
+	timeout := 30 * time.Second
+	if t, ok := step["timeout"].(int); ok && t > 0 {
+		timeout = time.Duration(t) * time.Second
+	}
+	runCtx, cancel := stepContext(ctx, timeout)
+	defer cancel()
+
+	transport, _ := step["transport"].(string)
+	if transport == "" {
+		transport = "http"
+		if _, ok := step["command"].(string); ok {
+			transport = "stdio"
+		}
+	}
+
+	var session mcpSession
+	switch transport {
+	case "stdio":
+		session, err = newMCPStdioSession(runCtx, step, ctx)
+	case "sse":
+		session, err = newMCPSSESession(runCtx, step)
+	case "http", "streamable-http":
+		session, err = newMCPHTTPSession(step)
+	default:
+		err = fmt.Errorf("unknown mcp transport: %s (expected stdio, sse or http)", transport)
+	}
+	if err != nil {
+		return StepResult{Success: false, Error: err.Error()}
+	}
+
+	result := mcpExchange(runCtx, session, method, params, timeout)
+	// The server's stderr is only complete, and no longer written, once the
+	// session is closed
+	session.Close()
+	result.Stderr = session.Stderr()
+	return result
+}
+
+// mcpExchange initializes the session and sends the request. The result has
+// no stderr yet.
+func mcpExchange(ctx context.Context, session mcpSession, method string, params map[string]any, timeout time.Duration) StepResult {
+	initResult, err := mcpInitialize(ctx, session)
+	if err != nil {
+		return mcpErrorResult(ctx, timeout, fmt.Errorf("initialize failed: %w", err))
+	}
+
+	result := initResult
+	if method != "initialize" {
+		result, err = session.Call(ctx, method, params)
+		if err != nil {
+			return mcpErrorResult(ctx, timeout, err)
+		}
+	}
+
+	output, _ := json.MarshalIndent(result, "", "  ")
+
+	// Tool calls report failures in-band via isError
+	var toolResult struct {
+		IsError bool `json:"isError"`
+	}
+	if json.Unmarshal(result, &toolResult) == nil && toolResult.IsError {
+		return StepResult{
+			Success:  false,
+			ExitCode: 1,
+			Stdout:   string(output),
+			Error:    "tool returned isError: true",
+		}
+	}
+
+	return StepResult{
+		Success:  true,
+		ExitCode: 0,
+		Stdout:   string(output),
+	}
+}
+
+// mcpRequest maps a handler action to a JSON-RPC method and params
+func mcpRequest(action string, step map[string]any) (string, map[string]any, error) {
+	switch action {
+	case "initialize":
+		return "initialize", nil, nil
+	case "list_tools":
+		return "tools/list", map[string]any{}, nil
+	case "list_resources":
+		return "resources/list", map[string]any{}, nil
+	case "list_prompts":
+		return "prompts/list", map[string]any{}, nil
+	case "call_tool":
+		tool, _ := step["tool"].(string)
+		if tool == "" {
+			return "", nil, fmt.Errorf("mcp call_tool requires 'tool' field")
+		}
+		arguments, _ := step["arguments"].(map[string]any)
+		if arguments == nil {
+			arguments = map[string]any{}
+		}
+		return "tools/call", map[string]any{"name": tool, "arguments": arguments}, nil
+	case "read_resource":
+		uri, _ := step["uri"].(string)
+		if uri == "" {
+			return "", nil, fmt.Errorf("mcp read_resource requires 'uri' field")
+		}
+		return "resources/read", map[string]any{"uri": uri}, nil
+	default:
+		return "", nil, fmt.Errorf("unknown mcp action: %s", action)
+	}
+}
+
+// mcpInitialize performs the initialize handshake
+func mcpInitialize(ctx context.Context, session mcpSession) (json.RawMessage, error) {
+	result, err := session.Call(ctx, "initialize", map[string]any{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "tsuite", "version": "1.0"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Notify(ctx, "notifications/initialized"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func mcpErrorResult(ctx context.Context, timeout time.Duration, err error) StepResult {
+	if ctx.Err() == context.Canceled {
+		return StepResult{
+			Success:  false,
+			ExitCode: 130,
+			Error:    "mcp request cancelled: " + context.Cause(ctx).Error(),
+		}
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return StepResult{
+			Success:  false,
+			ExitCode: 124,
+			Error:    fmt.Sprintf("mcp request timed out after %v", timeout),
+		}
+	}
+	return StepResult{
+		Success:  false,
+		ExitCode: 1,
+		Error:    err.Error(),
+	}
+}
+
+// =============================================================================
+// JSON-RPC
+// =============================================================================
+
+type mcpMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *mcpError       `json:"error,omitempty"`
+}
+
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *mcpError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+// mcpSession is a connected MCP transport
+type mcpSession interface {
+	Call(ctx context.Context, method string, params any) (json.RawMessage, error)
+	Notify(ctx context.Context, method string) error
+	Stderr() string // Complete only after Close
+	Close()
+}
+
+// mcpIDs numbers requests across all sessions
+var mcpIDs atomic.Int64
+
+func newMCPCall(method string, params any) mcpMessage {
+	id := mcpIDs.Add(1)
+	return mcpMessage{JSONRPC: "2.0", ID: &id, Method: method, Params: params}
+}
+
+// responseFor returns the result if msg is the response to id
+func responseFor(msg mcpMessage, id int64) (json.RawMessage, bool, error) {
+	if msg.ID == nil || *msg.ID != id || msg.Method != "" {
+		return nil, false, nil
+	}
+	if msg.Error != nil {
+		return nil, true, msg.Error
+	}
+	return msg.Result, true, nil
+}
+
+// =============================================================================
+// stdio transport
+// =============================================================================
+
+type mcpStdioSession struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	lines  chan []byte
+	done   chan struct{} // Closed by Close
+	stderr bytes.Buffer
+}
+
+func newMCPStdioSession(ctx context.Context, step map[string]any, ictx *interpolate.Context) (*mcpStdioSession, error) {
+	command, _ := step["command"].(string)
+	if command == "" {
+		return nil, fmt.Errorf("mcp stdio transport requires 'command' field")
+	}
+
+	s := &mcpStdioSession{lines: make(chan []byte, 16), done: make(chan struct{})}
+	s.cmd = exec.CommandContext(ctx, "bash", "-c", command)
+	s.cmd.Env = os.Environ()
+	if w, ok := step["workdir"].(string); ok && w != "" {
+		s.cmd.Dir = w
+	} else if ictx.Workdir != "" {
+		s.cmd.Dir = ictx.Workdir
+	}
+	s.cmd.Stderr = &s.stderr
+	// Children of the server (bash -c) are killed with it, and can't keep
+	// Wait blocked by holding stderr open
+	killProcessGroup(s.cmd)
+
+	stdin, err := s.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := s.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	s.stdin = stdin
+
+	if err := s.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start mcp server: %w", err)
+	}
+
+	go func() {
+		defer close(s.lines)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case s.lines <- line:
+			case <-s.done:
+				// Nobody reads responses any more; keep draining stdout so
+				// the server can exit
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+func (s *mcpStdioSession) send(msg mcpMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = s.stdin.Write(append(data, '\n'))
+	return err
+}
+
+func (s *mcpStdioSession) Call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	req := newMCPCall(method, params)
+	if err := s.send(req); err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", method, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case line, ok := <-s.lines:
+			if !ok {
+				return nil, fmt.Errorf("mcp server exited before responding to %s", method)
+			}
+			var msg mcpMessage
+			if json.Unmarshal(line, &msg) != nil {
+				continue // Ignore non-JSON log output
+			}
+			if result, done, err := responseFor(msg, *req.ID); done {
+				return result, err
+			}
+		}
+	}
+}
+
+func (s *mcpStdioSession) Notify(ctx context.Context, method string) error {
+	return s.send(mcpMessage{JSONRPC: "2.0", Method: method})
+}
+
+func (s *mcpStdioSession) Stderr() string {
+	return s.stderr.String()
+}
+
+func (s *mcpStdioSession) Close() {
+	close(s.done)
+	s.stdin.Close()
+	done := make(chan struct{})
+	go func() {
+		s.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		syscall.Kill(-s.cmd.Process.Pid, syscall.SIGKILL)
+		<-done
+	}
+}
+
+// =============================================================================
+// HTTP transports
+// =============================================================================
+
+// mcpHeaders returns the interpolated headers from a step
+func mcpHeaders(step map[string]any) map[string]string {
+	headers := make(map[string]string)
+	switch h := step["headers"].(type) {
+	case map[string]string:
+		for k, v := range h {
+			headers[k] = v
+		}
+	case map[string]any:
+		for k, v := range h {
+			if vs, ok := v.(string); ok {
+				headers[k] = vs
+			}
+		}
+	}
+	return headers
+}
+
+// readSSE reads server-sent events and calls fn for each until it returns false
+func readSSE(body io.Reader, fn func(event, data string) bool) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	event := ""
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 && !fn(event, strings.Join(data, "\n")) {
+				return nil
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// mcpHTTPSession implements the streamable HTTP transport
+type mcpHTTPSession struct {
+	url       string
+	headers   map[string]string
+	sessionID string
+	client    *http.Client
+}
+
+func newMCPHTTPSession(step map[string]any) (*mcpHTTPSession, error) {
+	u, _ := step["url"].(string)
+	if u == "" {
+		return nil, fmt.Errorf("mcp http transport requires 'url' field")
+	}
+	return &mcpHTTPSession{url: u, headers: mcpHeaders(step), client: &http.Client{}}, nil
+}
+
+func (s *mcpHTTPSession) post(ctx context.Context, msg mcpMessage) (*http.Response, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if s.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", s.sessionID)
+	}
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		s.sessionID = id
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned HTTP %d: %s", msg.Method, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+func (s *mcpHTTPSession) Call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	req := newMCPCall(method, params)
+	resp, err := s.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var result json.RawMessage
+		var callErr error
+		found := false
+		err := readSSE(resp.Body, func(event, data string) bool {
+			var msg mcpMessage
+			if json.Unmarshal([]byte(data), &msg) != nil {
+				return true
+			}
+			result, found, callErr = responseFor(msg, *req.ID)
+			return !found
+		})
+		if !found {
+			if err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("no response to %s: %w", method, err)
+		}
+		return result, callErr
+	}
+
+	var msg mcpMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return nil, fmt.Errorf("invalid response to %s: %w", method, err)
+	}
+	result, found, err := responseFor(msg, *req.ID)
+	if !found {
+		return nil, fmt.Errorf("no response to %s", method)
+	}
+	return result, err
+}
+
+func (s *mcpHTTPSession) Notify(ctx context.Context, method string) error {
+	resp, err := s.post(ctx, mcpMessage{JSONRPC: "2.0", Method: method})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *mcpHTTPSession) Stderr() string {
+	return ""
+}
+
+func (s *mcpHTTPSession) Close() {
+	if s.sessionID == "" {
+		return
+	}
+	// Best-effort session termination
+	req, err := http.NewRequest("DELETE", s.url, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Mcp-Session-Id", s.sessionID)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if resp, err := s.client.Do(req.WithContext(ctx)); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// mcpSSESession implements the legacy HTTP+SSE transport
+type mcpSSESession struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+	stream   io.ReadCloser
+	messages chan mcpMessage
+	done     chan struct{} // Closed by Close
+}
+
+func newMCPSSESession(ctx context.Context, step map[string]any) (*mcpSSESession, error) {
+	u, _ := step["url"].(string)
+	if u == "" {
+		return nil, fmt.Errorf("mcp sse transport requires 'url' field")
+	}
+
+	s := &mcpSSESession{
+		headers:  mcpHeaders(step),
+		client:   &http.Client{},
+		messages: make(chan mcpMessage, 16),
+		done:     make(chan struct{}),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sse stream: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("sse stream returned HTTP %d", resp.StatusCode)
+	}
+	s.stream = resp.Body
+
+	// The first event announces the endpoint for client messages
+	endpoint := make(chan string, 1)
+	go func() {
+		defer close(s.messages)
+		readSSE(resp.Body, func(event, data string) bool {
+			if event == "endpoint" {
+				// Only the first endpoint event counts
+				select {
+				case endpoint <- data:
+				default:
+				}
+				return true
+			}
+			var msg mcpMessage
+			if json.Unmarshal([]byte(data), &msg) == nil {
+				select {
+				case s.messages <- msg:
+				case <-s.done:
+					return false
+				}
+			}
+			return true
+		})
+	}()
+
+	select {
+	case <-ctx.Done():
+		s.Close()
+		return nil, fmt.Errorf("timed out waiting for sse endpoint event")
+	case e := <-endpoint:
+		base, err := url.Parse(u)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		ref, err := url.Parse(e)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("invalid sse endpoint %q: %w", e, err)
+		}
+		s.endpoint = base.ResolveReference(ref).String()
+	}
+
+	return s, nil
+}
+
+func (s *mcpSSESession) post(ctx context.Context, msg mcpMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s returned HTTP %d: %s", msg.Method, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func (s *mcpSSESession) Call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	req := newMCPCall(method, params)
+	if err := s.post(ctx, req); err != nil {
+		return nil, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case msg, ok := <-s.messages:
+			if !ok {
+				return nil, fmt.Errorf("sse stream closed before responding to %s", method)
+			}
+			if result, done, err := responseFor(msg, *req.ID); done {
+				return result, err
+			}
+		}
+	}
+}
+
+func (s *mcpSSESession) Notify(ctx context.Context, method string) error {
+	return s.post(ctx, mcpMessage{JSONRPC: "2.0", Method: method})
+}
+
+func (s *mcpSSESession) Stderr() string {
+	return ""
+}
+
+func (s *mcpSSESession) Close() {
+	close(s.done)
+	s.stream.Close()
+}
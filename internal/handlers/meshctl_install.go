@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/selfupdate"
+)
+
+// MeshctlRepo is the GitHub repository meshctl releases are published in
+const MeshctlRepo = "dhyansraj/mcp-mesh"
+
+// EnvCacheDir overrides where downloads such as meshctl releases are cached
+// (default ~/.tsuite/cache). Docker mode points it at the mounted cache.
+const EnvCacheDir = "TSUITE_CACHE_DIR"
+
+// MeshctlInstallHandler downloads a meshctl release for the platform the
+// test runs on, caches it and puts it first on PATH for the test's steps
+type MeshctlInstallHandler struct{}
+
+func (h *MeshctlInstallHandler) Name() string {
+	return "meshctl-install"
+}
+
+func (h *MeshctlInstallHandler) Execute(step map[string]any, ctx *interpolate.Context) StepResult {
+	version := meshctlVersion(step, ctx)
+	repo := MeshctlRepo
+	if v, ok := step["repo"].(string); ok && v != "" {
+		repo = v
+	}
+	url, _ := step["url"].(string)
+	if url != "" && version == "latest" {
+		return StepResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    "meshctl-install with url needs a version (or packages.cli_version)",
+		}
+	}
+
+	// Offline, only cached versions and a url (a local mirror) are installed
+	if Offline() && url == "" {
+		if version == "latest" {
+			return offlineResult("meshctl-install of the latest meshctl", "pin a version with version or packages.cli_version")
+		}
+		if _, err := os.Stat(meshctlPath(version, runtime.GOOS, runtime.GOARCH)); err != nil {
+			return offlineResult("meshctl-install of meshctl "+version, "it is not in the cache; run it once with internet access or set url to a local mirror")
+		}
+	}
+
+	var stdout bytes.Buffer
+	releases := selfupdate.NewClient(repo)
+
+	// latest is resolved first, so the release it names is cached like a
+	// pinned one
+	var release *selfupdate.Release
+	if version == "latest" && url == "" {
+		var err error
+		if release, err = releases.GetRelease(""); err != nil {
+			return StepResult{Success: false, ExitCode: 1, Error: err.Error()}
+		}
+		version = release.Version()
+	}
+
+	bin := meshctlPath(version, runtime.GOOS, runtime.GOARCH)
+	if _, err := os.Stat(bin); err == nil {
+		fmt.Fprintf(&stdout, "meshctl %s cached at %s\n", version, bin)
+	} else {
+		name, data, err := downloadMeshctl(releases, release, version, url)
+		if err != nil {
+			return StepResult{Success: false, ExitCode: 1, Stdout: stdout.String(), Error: err.Error()}
+		}
+		binary, err := extractMeshctl(name, data)
+		if err != nil {
+			return StepResult{Success: false, ExitCode: 1, Stdout: stdout.String(), Error: err.Error()}
+		}
+		if err := selfupdate.Install(binary, bin); err != nil {
+			return StepResult{Success: false, ExitCode: 1, Stdout: stdout.String(), Error: err.Error()}
+		}
+		fmt.Fprintf(&stdout, "Downloaded meshctl %s (%s) to %s\n", version, name, bin)
+	}
+
+	// The runner runs one test per process, so PATH holds for the test's
+	// remaining steps
+	if dir := filepath.Dir(bin); filepath.SplitList(os.Getenv("PATH"))[0] != dir {
+		os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	}
+
+	out, err := exec.Command(bin, "--version").CombinedOutput()
+	stdout.Write(out)
+	if err != nil {
+		return StepResult{
+			Success:  false,
+			ExitCode: 1,
+			Stdout:   stdout.String(),
+			Error:    fmt.Sprintf("meshctl --version failed: %v", err),
+		}
+	}
+
+	return StepResult{
+		Success:  true,
+		ExitCode: 0,
+		Stdout:   stdout.String(),
+		Recorded: map[string]string{"meshctl.version": version},
+	}
+}
+
+// meshctlVersion returns the version a step asks for, without the v: its
+// version field, else packages.cli_version, else "latest"
+func meshctlVersion(step map[string]any, ctx *interpolate.Context) string {
+	version, _ := step["version"].(string)
+	if version == "" {
+		if packages, ok := ctx.Config["packages"].(map[string]any); ok {
+			version, _ = packages["cli_version"].(string)
+		}
+	}
+	if version == "" {
+		return "latest"
+	}
+	return strings.TrimPrefix(version, "v")
+}
+
+// meshctlPath returns where a meshctl version for a platform is cached
+func meshctlPath(version, goos, goarch string) string {
+	dir := os.Getenv(EnvCacheDir)
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".tsuite", "cache")
+	}
+	return filepath.Join(dir, "meshctl", version, goos+"-"+goarch, "meshctl")
+}
+
+// downloadMeshctl fetches a meshctl release asset, from url ({version},
+// {os} and {arch} are replaced) if set, else from the release's GitHub
+// assets. It returns the asset's name and contents.
+func downloadMeshctl(releases *selfupdate.Client, release *selfupdate.Release, version, url string) (string, []byte, error) {
+	if url != "" {
+		url = strings.NewReplacer("{version}", version, "{os}", runtime.GOOS, "{arch}", runtime.GOARCH).Replace(url)
+		data, err := releases.DownloadURL(url)
+		return path.Base(url), data, err
+	}
+
+	if release == nil {
+		var err error
+		if release, err = releases.GetRelease(version); err != nil {
+			return "", nil, err
+		}
+	}
+	name := meshctlAsset(release, runtime.GOOS, runtime.GOARCH)
+	if name == "" {
+		return "", nil, fmt.Errorf("release %s has no meshctl asset for %s/%s", release.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	if !release.HasChecksums() {
+		data, err := releases.DownloadUnverified(release, name)
+		return name, data, err
+	}
+	data, err := releases.Download(release, name)
+	return name, data, err
+}
+
+// platformNames are the spellings of an OS or architecture in release
+// asset names
+var platformNames = map[string][]string{
+	"darwin": {"darwin", "macos"},
+	"amd64":  {"amd64", "x86_64"},
+	"arm64":  {"arm64", "aarch64"},
+}
+
+// meshctlAsset returns the name of the release asset holding meshctl for a
+// platform, or "" if there is none. Assets named meshctl are preferred over
+// mcp-mesh bundles.
+func meshctlAsset(release *selfupdate.Release, goos, goarch string) string {
+	matches := func(name, key string) bool {
+		spellings, ok := platformNames[key]
+		if !ok {
+			spellings = []string{key}
+		}
+		for _, s := range spellings {
+			if strings.Contains(name, s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var bundle string
+	for _, asset := range release.Assets {
+		name := strings.ToLower(asset.Name)
+		switch {
+		case !matches(name, goos) || !matches(name, goarch):
+			continue
+		case strings.HasSuffix(name, ".txt") || strings.HasSuffix(name, ".sha256") || strings.HasSuffix(name, ".sig"),
+			strings.HasSuffix(name, ".deb") || strings.HasSuffix(name, ".rpm") || strings.HasSuffix(name, ".apk"):
+			continue
+		case strings.Contains(name, "meshctl"):
+			return asset.Name
+		case strings.Contains(name, "mcp-mesh") && bundle == "":
+			bundle = asset.Name
+		}
+	}
+	return bundle
+}
+
+// extractMeshctl returns the meshctl binary of a downloaded asset: a
+// .tar.gz or .zip archive holding it, or the binary itself
+func extractMeshctl(name string, data []byte) ([]byte, error) {
+	switch {
+	case strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz"):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == "meshctl" {
+				return io.ReadAll(tr)
+			}
+		}
+	case strings.HasSuffix(name, ".zip"):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != "meshctl" {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+	default:
+		return data, nil
+	}
+	return nil, fmt.Errorf("%s has no meshctl binary", name)
+}
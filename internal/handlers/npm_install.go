@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -53,6 +52,51 @@ func (h *NpmInstallHandler) Execute(step map[string]any, ctx *interpolate.Contex
 		}
 	}
 
+	// Determine package mode (local or published)
+	// Check for /packages directory (local mode) or default to published
+	mode := "published"
+	packagesDir := localPackageDir(EnvPackagesDir, "/packages")
+	if packagesDir != "" {
+		mode = "local"
+	}
+
+	// Get version from config if available, otherwise use "*"
+	version := "*"
+	if packages, ok := ctx.Config["packages"].(map[string]any); ok {
+		if v, ok := packages["sdk_typescript_version"].(string); ok && v != "" {
+			version = v
+		}
+	}
+
+	pm, err := detectPackageManager(path, step)
+	if err != nil {
+		return StepResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err.Error(),
+		}
+	}
+	lockfile := filepath.Join(path, pm.lockfile)
+
+	// node_modules installed from the same package.json and lockfile is
+	// reused. The check comes before file: dependencies are replaced, as
+	// the stamp records package.json after the install rewrote it.
+	var stamp installStamp
+	stamped := false
+	if reuseInstall(step) {
+		files := []string{packageJSON, lockfile}
+		stamp, stamped = newInstallStamp(h.Name(), path, filepath.Join(path, "node_modules"), files, pm.name, mode, version, dirListing(packagesDir, "*.tgz"))
+		if stamped && stamp.unchanged() {
+			return StepResult{
+				Success: true,
+				Stdout:  "package.json unchanged, reusing node_modules\n",
+			}
+		}
+	}
+	if Offline() && npmMirror() == "" {
+		return offlineResult(pm.name+" install of "+packageJSON, "set packages.mirror.npm_registry to a local npm registry")
+	}
+
 	// Replace file: dependencies by default (set replace_file_deps: false to disable)
 	replaceFileDeps := true
 	if v, ok := step["replace_file_deps"].(bool); ok {
@@ -63,16 +107,9 @@ func (h *NpmInstallHandler) Execute(step map[string]any, ctx *interpolate.Contex
 		replaceFileDeps = v
 	}
 
+	replaced := false
 	if replaceFileDeps {
-		// Get version from config if available, otherwise use "*"
-		version := "*"
-		if packages, ok := ctx.Config["packages"].(map[string]any); ok {
-			if v, ok := packages["sdk_typescript_version"].(string); ok && v != "" {
-				version = v
-			}
-		}
-
-		if err := replaceFileDepependencies(packageJSON, version); err != nil {
+		if replaced, err = replaceFileDepependencies(packageJSON, version, packagesDir); err != nil {
 			return StepResult{
 				Success:  false,
 				ExitCode: 1,
@@ -81,96 +118,58 @@ func (h *NpmInstallHandler) Execute(step map[string]any, ctx *interpolate.Contex
 		}
 	}
 
-	// Determine package mode (local or published)
-	// Check for /packages directory (local mode) or default to published
-	mode := "published"
-	if _, err := os.Stat("/packages"); err == nil {
-		mode = "local"
-	}
-
 	timeout := 300 * time.Second
 	if t, ok := step["timeout"].(int); ok && t > 0 {
 		timeout = time.Duration(t) * time.Second
 	}
 
-	cmdCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	cmdCtx, cancel := stepContext(ctx, timeout)
 	defer cancel()
 
 	var stdout, stderr bytes.Buffer
 
+	command, err := pm.command()
+	if err != nil {
+		return StepResult{
+			Success:  false,
+			ExitCode: 127,
+			Error:    err.Error(),
+		}
+	}
+
+	// The lockfile must match package.json unless the install may update
+	// it: there is none yet, or file: dependencies were just replaced
+	_, statErr := os.Stat(lockfile)
+	frozen := statErr == nil && !replaced
+	cmd := exec.CommandContext(cmdCtx, command[0], append(command[1:], pm.installArgs(frozen)...)...)
+	cmd.Dir = path
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if result, ok := runInstall(cmdCtx, cmd, pm.name+" install", &stdout, &stderr); !ok {
+		return result
+	}
+
+	// Local mode: override @mcpmesh packages with the local tarballs
 	if mode == "local" {
-		// Local mode: npm install with local packages
-		// First run npm install, then override with local packages
-		cmd := exec.CommandContext(cmdCtx, "bash", "-c", `
-			cd "$1"
-
-			# Run standard npm install first
-			npm install --legacy-peer-deps
-
-			# Then override with local packages if they exist
-			if [ -d /packages ]; then
-				echo "Overriding with local packages from /packages"
-
-				# Install @mcpmesh packages from local tarballs (overrides npm versions)
-				for pkg in /packages/*.tgz; do
-					if [ -f "$pkg" ]; then
-						echo "Installing local package: $pkg"
-						npm install "$pkg" --save --legacy-peer-deps
-					fi
-				done
-			fi
-		`, "bash", path)
-
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-
-		err := cmd.Run()
-		if err != nil {
-			if cmdCtx.Err() == context.DeadlineExceeded {
-				return StepResult{
-					Success:  false,
-					ExitCode: 124,
-					Stdout:   stdout.String(),
-					Stderr:   stderr.String(),
-					Error:    "npm install timed out",
-				}
-			}
-			return StepResult{
-				Success:  false,
-				ExitCode: 1,
-				Stdout:   stdout.String(),
-				Stderr:   stderr.String(),
-				Error:    fmt.Sprintf("npm install failed: %v", err),
-			}
+		tarballs, _ := filepath.Glob(filepath.Join(packagesDir, "*.tgz"))
+		if len(tarballs) > 0 {
+			fmt.Fprintf(&stdout, "Overriding with local packages from %s\n", packagesDir)
 		}
-	} else {
-		// Published mode: just run npm install
-		cmd := exec.CommandContext(cmdCtx, "npm", "install", "--legacy-peer-deps")
-		cmd.Dir = path
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-
-		err := cmd.Run()
-		if err != nil {
-			if cmdCtx.Err() == context.DeadlineExceeded {
-				return StepResult{
-					Success:  false,
-					ExitCode: 124,
-					Stdout:   stdout.String(),
-					Stderr:   stderr.String(),
-					Error:    "npm install timed out",
-				}
-			}
-			return StepResult{
-				Success:  false,
-				ExitCode: 1,
-				Stdout:   stdout.String(),
-				Stderr:   stderr.String(),
-				Error:    fmt.Sprintf("npm install failed: %v", err),
+		for _, pkg := range tarballs {
+			fmt.Fprintf(&stdout, "Installing local package: %s\n", pkg)
+			cmd := exec.CommandContext(cmdCtx, command[0], append(command[1:], pm.addArgs(pkg)...)...)
+			cmd.Dir = path
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			if result, ok := runInstall(cmdCtx, cmd, pm.name+" install", &stdout, &stderr); !ok {
+				return result
 			}
 		}
 	}
 
+	if stamped {
+		stamp.record()
+	}
 	return StepResult{
 		Success:  true,
 		ExitCode: 0,
@@ -179,21 +178,123 @@ func (h *NpmInstallHandler) Execute(step map[string]any, ctx *interpolate.Contex
 	}
 }
 
+// packageManager installs a Node.js project: npm, pnpm or yarn
+type packageManager struct {
+	name     string
+	lockfile string
+	berry    bool // yarn 2 or later
+}
+
+// packageManagers by name, with their lockfiles in detection order
+var packageManagers = []packageManager{
+	{name: "pnpm", lockfile: "pnpm-lock.yaml"},
+	{name: "yarn", lockfile: "yarn.lock"},
+	{name: "npm", lockfile: "package-lock.json"},
+}
+
+// detectPackageManager returns the package manager of a project: the step's
+// package_manager, else the packageManager field of package.json, else the
+// one whose lockfile the project has, else npm
+func detectPackageManager(path string, step map[string]any) (packageManager, error) {
+	name, _ := step["package_manager"].(string)
+	version := ""
+	if name == "" {
+		// corepack's "packageManager": "pnpm@9.1.0"
+		if data, err := os.ReadFile(filepath.Join(path, "package.json")); err == nil {
+			var pkg struct {
+				PackageManager string `json:"packageManager"`
+			}
+			if json.Unmarshal(data, &pkg) == nil {
+				name, version, _ = strings.Cut(pkg.PackageManager, "@")
+			}
+		}
+	}
+	if name == "" {
+		for _, pm := range packageManagers {
+			if _, err := os.Stat(filepath.Join(path, pm.lockfile)); err == nil {
+				name = pm.name
+				break
+			}
+		}
+	}
+	if name == "" {
+		name = "npm"
+	}
+
+	for _, pm := range packageManagers {
+		if pm.name != name {
+			continue
+		}
+		if pm.name == "yarn" {
+			_, err := os.Stat(filepath.Join(path, ".yarnrc.yml"))
+			pm.berry = err == nil || (version != "" && !strings.HasPrefix(version, "1."))
+		}
+		return pm, nil
+	}
+	return packageManager{}, fmt.Errorf("unknown package_manager %q: must be npm, pnpm or yarn", name)
+}
+
+// command returns how to run the package manager: itself, or through
+// corepack (bundled with Node.js) when it isn't installed
+func (pm packageManager) command() ([]string, error) {
+	if _, err := exec.LookPath(pm.name); err == nil {
+		return []string{pm.name}, nil
+	}
+	if pm.name != "npm" {
+		if _, err := exec.LookPath("corepack"); err == nil {
+			return []string{"corepack", pm.name}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s not found: install it in the image or enable it with corepack enable", pm.name)
+}
+
+// installArgs returns the arguments installing the project's dependencies;
+// frozen installs fail instead of updating the lockfile
+func (pm packageManager) installArgs(frozen bool) []string {
+	switch pm.name {
+	case "pnpm":
+		if frozen {
+			return []string{"install", "--frozen-lockfile"}
+		}
+		return []string{"install", "--no-frozen-lockfile"}
+	case "yarn":
+		switch {
+		case !frozen:
+			return []string{"install"}
+		case pm.berry:
+			return []string{"install", "--immutable"}
+		}
+		return []string{"install", "--frozen-lockfile"}
+	}
+	return []string{"install", "--legacy-peer-deps"}
+}
+
+// addArgs returns the arguments adding a local package tarball
+func (pm packageManager) addArgs(tarball string) []string {
+	if pm.name == "npm" {
+		return []string{"install", tarball, "--save", "--legacy-peer-deps"}
+	}
+	return []string{"add", tarball}
+}
+
 // replaceFileDepependencies replaces file: dependencies in package.json with a version
+// and reports whether it replaced any.
 // This is useful when examples reference local packages via file: paths
 // that don't exist in the container. The version is replaced so npm install
 // can resolve the package, and local .tgz packages can override afterward.
-func replaceFileDepependencies(packageJSONPath string, version string) error {
+// Packages with a local tarball in packagesDir are replaced by the tarball,
+// as their version may not be published.
+func replaceFileDepependencies(packageJSONPath, version, packagesDir string) (bool, error) {
 	// Read package.json
 	data, err := os.ReadFile(packageJSONPath)
 	if err != nil {
-		return fmt.Errorf("failed to read package.json: %w", err)
+		return false, fmt.Errorf("failed to read package.json: %w", err)
 	}
 
 	// Parse as generic map to preserve structure
 	var pkg map[string]any
 	if err := json.Unmarshal(data, &pkg); err != nil {
-		return fmt.Errorf("failed to parse package.json: %w", err)
+		return false, fmt.Errorf("failed to parse package.json: %w", err)
 	}
 
 	modified := false
@@ -202,7 +303,7 @@ func replaceFileDepependencies(packageJSONPath string, version string) error {
 	if deps, ok := pkg["dependencies"].(map[string]any); ok {
 		for name, ver := range deps {
 			if v, ok := ver.(string); ok && strings.HasPrefix(v, "file:") {
-				deps[name] = version
+				deps[name] = fileDepReplacement(name, version, packagesDir)
 				modified = true
 			}
 		}
@@ -212,7 +313,7 @@ func replaceFileDepependencies(packageJSONPath string, version string) error {
 	if deps, ok := pkg["devDependencies"].(map[string]any); ok {
 		for name, ver := range deps {
 			if v, ok := ver.(string); ok && strings.HasPrefix(v, "file:") {
-				deps[name] = version
+				deps[name] = fileDepReplacement(name, version, packagesDir)
 				modified = true
 			}
 		}
@@ -222,7 +323,7 @@ func replaceFileDepependencies(packageJSONPath string, version string) error {
 	if deps, ok := pkg["optionalDependencies"].(map[string]any); ok {
 		for name, ver := range deps {
 			if v, ok := ver.(string); ok && strings.HasPrefix(v, "file:") {
-				deps[name] = version
+				deps[name] = fileDepReplacement(name, version, packagesDir)
 				modified = true
 			}
 		}
@@ -232,7 +333,7 @@ func replaceFileDepependencies(packageJSONPath string, version string) error {
 	if deps, ok := pkg["peerDependencies"].(map[string]any); ok {
 		for name, ver := range deps {
 			if v, ok := ver.(string); ok && strings.HasPrefix(v, "file:") {
-				deps[name] = version
+				deps[name] = fileDepReplacement(name, version, packagesDir)
 				modified = true
 			}
 		}
@@ -243,14 +344,32 @@ func replaceFileDepependencies(packageJSONPath string, version string) error {
 		// Marshal with indentation to preserve readability
 		newData, err := json.MarshalIndent(pkg, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal package.json: %w", err)
+			return false, fmt.Errorf("failed to marshal package.json: %w", err)
 		}
 
 		// Write back
 		if err := os.WriteFile(packageJSONPath, newData, 0644); err != nil {
-			return fmt.Errorf("failed to write package.json: %w", err)
+			return false, fmt.Errorf("failed to write package.json: %w", err)
 		}
 	}
 
-	return nil
+	return modified, nil
+}
+
+// fileDepReplacement returns what replaces a file: dependency on a package:
+// the tarball npm pack built for it in packagesDir, else version
+func fileDepReplacement(name, version, packagesDir string) string {
+	if packagesDir == "" {
+		return version
+	}
+	// npm pack names @mcpmesh/core 0.9.0 mcpmesh-core-0.9.0.tgz
+	prefix := strings.ReplaceAll(strings.TrimPrefix(name, "@"), "/", "-") + "-"
+	tarballs, _ := filepath.Glob(filepath.Join(packagesDir, prefix+"*.tgz"))
+	for _, tarball := range tarballs {
+		// Not mcpmesh-core-utils-0.9.0.tgz
+		if rest := strings.TrimPrefix(filepath.Base(tarball), prefix); rest != "" && rest[0] >= '0' && rest[0] <= '9' {
+			return "file:" + tarball
+		}
+	}
+	return version
 }
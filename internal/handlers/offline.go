@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+)
+
+// EnvOffline is set for the runners of tsuite run --offline. Handlers that
+// would fetch from the internet fail right away instead, unless they install
+// from a package mirror (packages.mirror).
+const EnvOffline = "TSUITE_OFFLINE"
+
+// Offline reports whether the test runs without internet access
+func Offline() bool {
+	return os.Getenv(EnvOffline) != ""
+}
+
+// MirrorEnv returns the environment pointing pip, uv, npm, pnpm, yarn and
+// corepack at the package mirrors, for install steps and shell steps alike.
+// Offline, installs without a mirror are made to fail right away instead of
+// retrying registries they can't reach.
+func MirrorEnv(pipIndexURL, npmRegistry string, offline bool) map[string]string {
+	env := make(map[string]string)
+	switch {
+	case pipIndexURL != "":
+		env["PIP_INDEX_URL"] = pipIndexURL
+		env["UV_INDEX_URL"] = pipIndexURL
+	case offline:
+		env["PIP_NO_INDEX"] = "1"
+		env["UV_OFFLINE"] = "1"
+	}
+	switch {
+	case npmRegistry != "":
+		env["npm_config_registry"] = npmRegistry
+		env["YARN_NPM_REGISTRY_SERVER"] = npmRegistry
+		env["COREPACK_NPM_REGISTRY"] = npmRegistry
+	case offline:
+		env["npm_config_offline"] = "true"
+		env["YARN_ENABLE_NETWORK"] = "false"
+		env["COREPACK_ENABLE_NETWORK"] = "0"
+	}
+	return env
+}
+
+// pipMirror returns the package index pip installs from if it is a mirror,
+// else ""
+func pipMirror() string {
+	return os.Getenv("PIP_INDEX_URL")
+}
+
+// npmMirror returns the registry npm installs from if it is a mirror, else ""
+func npmMirror() string {
+	if v := os.Getenv("npm_config_registry"); v != "" {
+		return v
+	}
+	return os.Getenv("NPM_CONFIG_REGISTRY")
+}
+
+// offlineResult fails a step of an offline run that would need the internet
+func offlineResult(what, fix string) StepResult {
+	return StepResult{
+		Success:  false,
+		ExitCode: 1,
+		Error:    fmt.Sprintf("%s needs the internet, which --offline forbids: %s", what, fix),
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
@@ -20,14 +22,14 @@ func (h *PipInstallHandler) Name() string {
 }
 
 func (h *PipInstallHandler) Execute(step map[string]any, ctx *interpolate.Context) StepResult {
-	// Get path (for requirements.txt) or packages list
+	// Get path (for requirements.txt or a project) or packages list
 	path, hasPath := step["path"].(string)
 	packages, hasPackages := step["packages"].([]any)
 
 	if !hasPath && !hasPackages {
 		return StepResult{
 			Success: false,
-			Error:   "pip-install handler requires 'path' (for requirements.txt) or 'packages' field",
+			Error:   "pip-install handler requires 'path' (for requirements.txt or pyproject.toml) or 'packages' field",
 		}
 	}
 
@@ -36,17 +38,33 @@ func (h *PipInstallHandler) Execute(step map[string]any, ctx *interpolate.Contex
 		timeout = time.Duration(t) * time.Second
 	}
 
-	cmdCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	cmdCtx, cancel := stepContext(ctx, timeout)
 	defer cancel()
 
 	var stdout, stderr bytes.Buffer
 
 	// Determine package mode (local or published)
 	mode := "published"
-	if _, err := os.Stat("/wheels"); err == nil {
+	wheels := localPackageDir(EnvWheelsDir, "/wheels")
+	if wheels != "" {
 		mode = "local"
 	}
 
+	// An install of unchanged inputs into the same environment is skipped
+	var stamp installStamp
+	stamped := false
+	reuse := func(key, what string, files ...string) bool {
+		if !reuseInstall(step) {
+			return false
+		}
+		stamp, stamped = newInstallStamp(h.Name(), key, sitePackages(cmdCtx), files, mode, dirListing(wheels, "*.whl"))
+		if stamped && stamp.unchanged() {
+			fmt.Fprintf(&stdout, "%s unchanged, reusing install\n", what)
+			return true
+		}
+		return false
+	}
+
 	if hasPath {
 		// Interpolate path
 		path, _ = interpolate.Interpolate(path, ctx)
@@ -60,79 +78,86 @@ func (h *PipInstallHandler) Execute(step map[string]any, ctx *interpolate.Contex
 			path = filepath.Join(workdir, path)
 		}
 
-		// Check if requirements.txt exists
-		requirementsFile := path
-		if info, err := os.Stat(path); err == nil && info.IsDir() {
-			requirementsFile = filepath.Join(path, "requirements.txt")
-		}
-
-		if _, err := os.Stat(requirementsFile); os.IsNotExist(err) {
+		project, err := findPythonProject(path, pythonInstaller(step, ctx))
+		if err != nil {
 			return StepResult{
 				Success:  false,
 				ExitCode: 1,
-				Error:    fmt.Sprintf("requirements.txt not found at %s", requirementsFile),
+				Error:    err.Error(),
 			}
 		}
+		if reuse(project.path, project.describe(), project.files...) {
+			return StepResult{Success: true, Stdout: stdout.String()}
+		}
+		if Offline() && pipMirror() == "" {
+			return offlineResult(project.installer+" install of "+project.describe(), "set packages.mirror.pip_index_url to a local PyPI mirror")
+		}
 
-		if mode == "local" {
-			// Local mode: install from local wheels first
-			cmd := exec.CommandContext(cmdCtx, "bash", "-c", fmt.Sprintf(`
-				# Install from local wheels if available
-				if [ -d /wheels ]; then
-					echo "Using local wheels from /wheels"
-					pip install --find-links=/wheels --no-index /wheels/*.whl 2>/dev/null || true
-				fi
-
-				# Install remaining packages from requirements.txt
-				pip install -r "%s"
-			`, requirementsFile))
-
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-
-			err := cmd.Run()
-			if err != nil {
-				if cmdCtx.Err() == context.DeadlineExceeded {
-					return StepResult{
-						Success:  false,
-						ExitCode: 124,
-						Stdout:   stdout.String(),
-						Stderr:   stderr.String(),
-						Error:    "pip install timed out",
-					}
-				}
+		if project.installer != "pip" {
+			if _, err := exec.LookPath(project.installer); err != nil {
 				return StepResult{
 					Success:  false,
-					ExitCode: 1,
-					Stdout:   stdout.String(),
-					Stderr:   stderr.String(),
-					Error:    fmt.Sprintf("pip install failed: %v", err),
+					ExitCode: 127,
+					Error:    fmt.Sprintf("%s not found: install it in the image or set installer: pip", project.installer),
 				}
 			}
-		} else {
-			// Published mode: just run pip install
-			cmd := exec.CommandContext(cmdCtx, "pip", "install", "-r", requirementsFile)
+		}
+
+		var cmd *exec.Cmd
+		switch project.installer {
+		case "uv":
+			// Sync the locked dependencies into the environment pip uses,
+			// keeping packages the lock doesn't list
+			cmd = exec.CommandContext(cmdCtx, "uv", "sync", "--frozen", "--inexact")
+			cmd.Env = append(os.Environ(), "UV_PROJECT_ENVIRONMENT="+pythonPrefix(cmdCtx))
+		case "poetry":
+			cmd = exec.CommandContext(cmdCtx, "poetry", "install", "--no-interaction")
+			cmd.Env = append(os.Environ(), "POETRY_VIRTUALENVS_CREATE=false")
+		default:
+			// pip installs a requirements file, or the project with its
+			// dependencies
+			args := []string{"install", project.path}
+			if project.requirements != "" {
+				args = []string{"install", "-r", project.requirements}
+			}
+			if mode == "local" {
+				// Local mode: install from local wheels first
+				script := `
+					wheels="$1"; shift
+
+					# Install from local wheels if available
+					if [ -d "$wheels" ]; then
+						echo "Using local wheels from $wheels"
+						pip install --find-links="$wheels" --no-index "$wheels"/*.whl 2>/dev/null || true
+					fi
+
+					# Install remaining packages
+					pip "$@"
+				`
+				cmd = exec.CommandContext(cmdCtx, "bash", append([]string{"-c", script, "bash", wheels}, args...)...)
+			} else {
+				// Published mode: just run pip install
+				cmd = exec.CommandContext(cmdCtx, "pip", args...)
+			}
+		}
+		cmd.Dir = project.path
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if result, ok := runInstall(cmdCtx, cmd, project.installer+" install", &stdout, &stderr); !ok {
+			return result
+		}
+
+		// The lock pins published mcp-mesh packages; local wheels replace
+		// them afterwards
+		if project.installer != "pip" && mode == "local" {
+			cmd := exec.CommandContext(cmdCtx, "bash", "-c", `
+				echo "Overriding with local wheels from $1"
+				pip install --no-index --no-deps --force-reinstall "$1"/*.whl
+			`, "bash", wheels)
 			cmd.Stdout = &stdout
 			cmd.Stderr = &stderr
-
-			err := cmd.Run()
-			if err != nil {
-				if cmdCtx.Err() == context.DeadlineExceeded {
-					return StepResult{
-						Success:  false,
-						ExitCode: 124,
-						Stdout:   stdout.String(),
-						Stderr:   stderr.String(),
-						Error:    "pip install timed out",
-					}
-				}
-				return StepResult{
-					Success:  false,
-					ExitCode: 1,
-					Stdout:   stdout.String(),
-					Stderr:   stderr.String(),
-					Error:    fmt.Sprintf("pip install failed: %v", err),
-				}
+			if result, ok := runInstall(cmdCtx, cmd, "pip install", &stdout, &stderr); !ok {
+				return result
 			}
 		}
 	} else if hasPackages {
@@ -144,37 +169,29 @@ func (h *PipInstallHandler) Execute(step map[string]any, ctx *interpolate.Contex
 				pkgList = append(pkgList, ps)
 			}
 		}
+		if reuse(strings.Join(pkgList, " "), "packages") {
+			return StepResult{Success: true, Stdout: stdout.String()}
+		}
+		if Offline() && pipMirror() == "" {
+			return offlineResult("pip install "+strings.Join(pkgList, " "), "set packages.mirror.pip_index_url to a local PyPI mirror")
+		}
 
 		args := append([]string{"install"}, pkgList...)
 		if mode == "local" {
-			args = append([]string{"install", "--find-links=/wheels"}, pkgList...)
+			args = append([]string{"install", "--find-links=" + wheels}, pkgList...)
 		}
 
 		cmd := exec.CommandContext(cmdCtx, "pip", args...)
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
-
-		err := cmd.Run()
-		if err != nil {
-			if cmdCtx.Err() == context.DeadlineExceeded {
-				return StepResult{
-					Success:  false,
-					ExitCode: 124,
-					Stdout:   stdout.String(),
-					Stderr:   stderr.String(),
-					Error:    "pip install timed out",
-				}
-			}
-			return StepResult{
-				Success:  false,
-				ExitCode: 1,
-				Stdout:   stdout.String(),
-				Stderr:   stderr.String(),
-				Error:    fmt.Sprintf("pip install failed: %v", err),
-			}
+		if result, ok := runInstall(cmdCtx, cmd, "pip install", &stdout, &stderr); !ok {
+			return result
 		}
 	}
 
+	if stamped {
+		stamp.record()
+	}
 	return StepResult{
 		Success:  true,
 		ExitCode: 0,
@@ -182,3 +199,113 @@ func (h *PipInstallHandler) Execute(step map[string]any, ctx *interpolate.Contex
 		Stderr:   stderr.String(),
 	}
 }
+
+// pythonProject is what a pip-install path points at
+type pythonProject struct {
+	path         string   // Project directory
+	installer    string   // pip, uv or poetry
+	requirements string   // requirements file pip installs, "" to install the project
+	files        []string // Files the install reads
+}
+
+// describe names the project's inputs in messages
+func (p pythonProject) describe() string {
+	return p.files[len(p.files)-1]
+}
+
+// pythonInstaller returns the installer a step asks for: its installer
+// field, else packages.python_installer, else "auto"
+func pythonInstaller(step map[string]any, ctx *interpolate.Context) string {
+	if v, ok := step["installer"].(string); ok && v != "" {
+		return v
+	}
+	if packages, ok := ctx.Config["packages"].(map[string]any); ok {
+		if v, ok := packages["python_installer"].(string); ok && v != "" {
+			return v
+		}
+	}
+	return "auto"
+}
+
+// findPythonProject resolves a pip-install path. A file is a requirements
+// file. In a directory, auto picks uv with uv.lock, poetry with poetry.lock,
+// else pip with requirements.txt or pyproject.toml.
+func findPythonProject(path, installer string) (pythonProject, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return pythonProject{}, fmt.Errorf("requirements.txt not found at %s", path)
+	}
+	if !info.IsDir() {
+		if installer != "auto" && installer != "pip" {
+			return pythonProject{}, fmt.Errorf("%s installs a project directory, not %s", installer, path)
+		}
+		return pythonProject{path: filepath.Dir(path), installer: "pip", requirements: path, files: []string{path}}, nil
+	}
+
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(path, name))
+		return err == nil
+	}
+	if installer == "auto" {
+		switch {
+		case exists("uv.lock"):
+			installer = "uv"
+		case exists("poetry.lock"):
+			installer = "poetry"
+		default:
+			installer = "pip"
+		}
+	}
+
+	switch installer {
+	case "uv", "poetry":
+		if !exists("pyproject.toml") {
+			return pythonProject{}, fmt.Errorf("%s needs a pyproject.toml, none found at %s", installer, path)
+		}
+		lock := installer + ".lock"
+		if !exists(lock) {
+			return pythonProject{}, fmt.Errorf("%s not found at %s; lock the project with %s lock", lock, path, installer)
+		}
+		files := []string{filepath.Join(path, "pyproject.toml"), filepath.Join(path, lock)}
+		return pythonProject{path: path, installer: installer, files: files}, nil
+	case "pip":
+		if exists("requirements.txt") {
+			requirements := filepath.Join(path, "requirements.txt")
+			return pythonProject{path: path, installer: installer, requirements: requirements, files: []string{requirements}}, nil
+		}
+		if exists("pyproject.toml") {
+			return pythonProject{path: path, installer: installer, files: []string{filepath.Join(path, "pyproject.toml")}}, nil
+		}
+		return pythonProject{}, fmt.Errorf("requirements.txt not found at %s (nor pyproject.toml)", filepath.Join(path, "requirements.txt"))
+	}
+	return pythonProject{}, fmt.Errorf("unknown installer %q: must be auto, pip, uv or poetry", installer)
+}
+
+// pipLocation matches where pip --version says pip is installed:
+// "pip 24.0 from /usr/lib/python3/site-packages/pip (python 3.11)"
+var pipLocation = regexp.MustCompile(`from (.+) \(python`)
+
+// sitePackages returns the site-packages directory pip installs into, or ""
+// if pip doesn't say
+func sitePackages(ctx context.Context) string {
+	out, err := exec.CommandContext(ctx, "pip", "--version").Output()
+	if err != nil {
+		return ""
+	}
+	m := pipLocation.FindSubmatch(out)
+	if m == nil {
+		return ""
+	}
+	return filepath.Dir(string(m[1]))
+}
+
+// pythonPrefix returns the prefix (sys.prefix) of the Python environment on
+// PATH, which uv installs into like pip does. It defaults to /usr/local,
+// the prefix in python images.
+func pythonPrefix(ctx context.Context) string {
+	out, err := exec.CommandContext(ctx, "python3", "-c", "import sys; print(sys.prefix)").Output()
+	if prefix := strings.TrimSpace(string(out)); err == nil && prefix != "" {
+		return prefix
+	}
+	return "/usr/local"
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// Cleaner is implemented by handlers that hold resources across steps.
+// Cleanup releases them and returns a description of each resource released.
+type Cleaner interface {
+	Cleanup() []string
+}
+
+// managedProcess is a background process started by the process handler
+type managedProcess struct {
+	handle  string
+	command string
+	cmd     *exec.Cmd
+	logPath string
+	done    chan struct{}
+	err     error
+}
+
+func (p *managedProcess) running() bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// ProcessHandler starts and stops background processes identified by a handle.
+// Processes run in their own process group so children (e.g. agents started
+// by meshctl) are stopped with them. Anything still running when the test
+// finishes is force-killed by Cleanup.
+type ProcessHandler struct {
+	mu        sync.Mutex
+	processes map[string]*managedProcess
+}
+
+// NewProcessHandler creates a process handler with no managed processes
+func NewProcessHandler() *ProcessHandler {
+	return &ProcessHandler{processes: make(map[string]*managedProcess)}
+}
+
+func (h *ProcessHandler) Name() string {
+	return "process"
+}
+
+func (h *ProcessHandler) Execute(step map[string]any, ctx *interpolate.Context) StepResult {
+	action, _ := step["action"].(string)
+	handle, _ := step["handle"].(string)
+	if handle == "" {
+		return StepResult{
+			Success: false,
+			Error:   "process handler requires 'handle' field",
+		}
+	}
+
+	switch action {
+	case "start":
+		return h.start(handle, step, ctx)
+	case "stop":
+		return h.stop(handle, step)
+	case "status":
+		return h.status(handle)
+	default:
+		return StepResult{
+			Success: false,
+			Error:   fmt.Sprintf("unknown process action: %q (expected start, stop or status)", action),
+		}
+	}
+}
+
+func (h *ProcessHandler) start(handle string, step map[string]any, ctx *interpolate.Context) StepResult {
+	command, _ := step["command"].(string)
+	if command == "" {
+		return StepResult{
+			Success: false,
+			Error:   "process start requires 'command' field",
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p, ok := h.processes[handle]; ok && p.running() {
+		return StepResult{
+			Success: false,
+			Error:   fmt.Sprintf("process %q is already running (pid %d)", handle, p.cmd.Process.Pid),
+		}
+	}
+
+	// Get workdir
+	workdir := "/workspace"
+	if w, ok := step["workdir"].(string); ok && w != "" {
+		workdir = w
+	} else if ctx.Workdir != "" {
+		workdir = ctx.Workdir
+	}
+
+	// Output goes to a log file so it survives the step
+	logPath, _ := step["log"].(string)
+	if logPath == "" {
+		logPath = filepath.Join(workdir, handle+".log")
+	}
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return StepResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to create process log: %v", err),
+		}
+	}
+
+	cmd := exec.Command("bash", "-c", command)
+	cmd.Dir = workdir
+	cmd.Env = os.Environ()
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return StepResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to start process: %v", err),
+		}
+	}
+
+	p := &managedProcess{handle: handle, command: command, cmd: cmd, logPath: logPath, done: make(chan struct{})}
+	go func() {
+		p.err = cmd.Wait()
+		logFile.Close()
+		close(p.done)
+	}()
+	h.processes[handle] = p
+
+	return StepResult{
+		Success:  true,
+		ExitCode: 0,
+		Stdout:   fmt.Sprintf("%d", cmd.Process.Pid),
+	}
+}
+
+func (h *ProcessHandler) stop(handle string, step map[string]any) StepResult {
+	h.mu.Lock()
+	p, ok := h.processes[handle]
+	h.mu.Unlock()
+	if !ok {
+		return StepResult{
+			Success: false,
+			Error:   fmt.Sprintf("unknown process: %q", handle),
+		}
+	}
+
+	// Grace period before SIGKILL
+	grace := 10 * time.Second
+	if t, ok := step["timeout"].(int); ok && t > 0 {
+		grace = time.Duration(t) * time.Second
+	}
+
+	exitCode := terminateProcess(p, grace)
+
+	h.mu.Lock()
+	delete(h.processes, handle)
+	h.mu.Unlock()
+
+	return StepResult{
+		Success:  true,
+		ExitCode: 0,
+		Stdout:   fmt.Sprintf("%d", exitCode),
+	}
+}
+
+func (h *ProcessHandler) status(handle string) StepResult {
+	h.mu.Lock()
+	p, ok := h.processes[handle]
+	h.mu.Unlock()
+
+	status := "not_started"
+	if ok {
+		status = "exited"
+		if p.running() {
+			status = "running"
+		}
+	}
+
+	return StepResult{
+		Success:  true,
+		ExitCode: 0,
+		Stdout:   status,
+	}
+}
+
+// Cleanup force-kills every process that is still running
+func (h *ProcessHandler) Cleanup() []string {
+	h.mu.Lock()
+	processes := h.processes
+	h.processes = make(map[string]*managedProcess)
+	h.mu.Unlock()
+
+	names := make([]string, 0, len(processes))
+	for handle := range processes {
+		names = append(names, handle)
+	}
+	sort.Strings(names)
+
+	var killed []string
+	for _, handle := range names {
+		p := processes[handle]
+		wasRunning := p.running()
+		// Kill the group even if the leader exited, in case children remain
+		terminateProcess(p, 0)
+		if wasRunning {
+			killed = append(killed, fmt.Sprintf("killed process %q (pid %d): %s", handle, p.cmd.Process.Pid, p.command))
+		}
+	}
+	return killed
+}
+
+// terminateProcess stops a process group with SIGTERM, then SIGKILL after grace,
+// and returns the exit code
+func terminateProcess(p *managedProcess, grace time.Duration) int {
+	pgid := -p.cmd.Process.Pid
+
+	if p.running() && grace > 0 {
+		syscall.Kill(pgid, syscall.SIGTERM)
+		select {
+		case <-p.done:
+		case <-time.After(grace):
+		}
+	}
+
+	syscall.Kill(pgid, syscall.SIGKILL)
+	<-p.done
+
+	return p.cmd.ProcessState.ExitCode()
+}
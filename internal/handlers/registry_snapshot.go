@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// DefaultRegistryURL is used when neither the step nor MCP_MESH_REGISTRY_URL sets one
+const DefaultRegistryURL = "http://localhost:8000"
+
+// volatileAgentFields change on every heartbeat and are left out of snapshots
+var volatileAgentFields = map[string]bool{
+	"last_seen":          true,
+	"last_heartbeat":     true,
+	"created_at":         true,
+	"updated_at":         true,
+	"uptime":             true,
+	"time_since_last_hb": true,
+}
+
+// RegistrySnapshotHandler captures the mcp-mesh registry state as normalized JSON.
+//
+// The snapshot has the form:
+//
+//	{"agents": {"<name>": {"status": ..., "capabilities": {"<cap>": {"version": ..., "tags": [...]}}}}}
+//
+// Capture it and compare two snapshots with ${snapdiff:before:after}.
+type RegistrySnapshotHandler struct{}
+
+func (h *RegistrySnapshotHandler) Name() string {
+	return "registry-snapshot"
+}
+
+func (h *RegistrySnapshotHandler) Execute(step map[string]any, ctx *interpolate.Context) StepResult {
+	registryURL, _ := step["url"].(string)
+	if registryURL == "" {
+		registryURL = os.Getenv("MCP_MESH_REGISTRY_URL")
+	}
+	if registryURL == "" {
+		registryURL = DefaultRegistryURL
+	}
+
+	timeout := 10
+	if t, ok := step["timeout"].(int); ok && t > 0 {
+		timeout = t
+	}
+
+	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	resp, err := client.Get(strings.TrimSuffix(registryURL, "/") + "/agents")
+	if err != nil {
+		return StepResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to query registry: %v", err),
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return StepResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to read registry response: %v", err),
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return StepResult{
+			Success: false,
+			Stderr:  string(body),
+			Error:   fmt.Sprintf("registry returned HTTP %d", resp.StatusCode),
+		}
+	}
+
+	snapshot, err := normalizeRegistryAgents(body)
+	if err != nil {
+		return StepResult{
+			Success: false,
+			Stderr:  string(body),
+			Error:   err.Error(),
+		}
+	}
+
+	output, _ := json.MarshalIndent(snapshot, "", "  ")
+	return StepResult{
+		Success:  true,
+		ExitCode: 0,
+		Stdout:   string(output),
+	}
+}
+
+// normalizeRegistryAgents converts a registry /agents response into a snapshot
+// keyed by agent and capability name
+func normalizeRegistryAgents(body []byte) (map[string]any, error) {
+	var agents []map[string]any
+
+	// Accept both {"agents": [...]} and a bare array
+	var wrapped struct {
+		Agents []map[string]any `json:"agents"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err == nil && wrapped.Agents != nil {
+		agents = wrapped.Agents
+	} else if err := json.Unmarshal(body, &agents); err != nil {
+		return nil, fmt.Errorf("unexpected registry response: %v", err)
+	}
+
+	result := make(map[string]any)
+	for _, agent := range agents {
+		name, _ := agent["name"].(string)
+		if name == "" {
+			name, _ = agent["id"].(string)
+		}
+		if name == "" {
+			continue
+		}
+
+		entry := make(map[string]any)
+		for k, v := range agent {
+			if volatileAgentFields[k] || k == "name" || k == "capabilities" {
+				continue
+			}
+			entry[k] = v
+		}
+
+		capabilities := make(map[string]any)
+		if caps, ok := agent["capabilities"].([]any); ok {
+			for _, c := range caps {
+				capability, ok := c.(map[string]any)
+				if !ok {
+					continue
+				}
+				capName, _ := capability["name"].(string)
+				if capName == "" {
+					capName, _ = capability["capability"].(string)
+				}
+				if capName == "" {
+					continue
+				}
+				capEntry := make(map[string]any)
+				for k, v := range capability {
+					if k == "name" || k == "capability" {
+						continue
+					}
+					capEntry[k] = v
+				}
+				if tags, ok := capEntry["tags"].([]any); ok {
+					capEntry["tags"] = sortedStrings(tags)
+				}
+				capabilities[capName] = capEntry
+			}
+		}
+		entry["capabilities"] = capabilities
+
+		result[name] = entry
+	}
+
+	return map[string]any{"agents": result}, nil
+}
+
+// sortedStrings returns string values sorted so tag order does not show up as a change
+func sortedStrings(values []any) []any {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		strs = append(strs, fmt.Sprintf("%v", v))
+	}
+	sort.Strings(strs)
+	result := make([]any, len(strs))
+	for i, s := range strs {
+		result[i] = s
+	}
+	return result
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
@@ -54,16 +56,24 @@ func (h *ShellHandler) Execute(step map[string]any, ctx *interpolate.Context) St
 		timeout = time.Duration(t) * time.Second
 	}
 
-	// Create command context with timeout
-	cmdCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	// Create command context with timeout, also done if the test is cancelled
+	cmdCtx, cancel := stepContext(ctx, timeout)
 	defer cancel()
 
-	// Execute command with bash
-	cmd := exec.CommandContext(cmdCtx, "bash", "-c", interpolatedCmd)
+	shell, strict := shellSettings(step, ctx)
+	args, err := shellArgs(shell, strict, interpolatedCmd)
+	if err != nil {
+		return StepResult{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+	cmd := exec.CommandContext(cmdCtx, args[0], args[1:]...)
 	cmd.Dir = workdir
+	killProcessGroup(cmd)
 
-	// Set up environment
-	cmd.Env = os.Environ()
+	// Set up environment, with the workspace's tools first on PATH
+	cmd.Env = workspaceEnv(os.Environ(), workdir)
 	if apiURL := os.Getenv("TSUITE_API"); apiURL != "" {
 		cmd.Env = append(cmd.Env, "TSUITE_API="+apiURL)
 	}
@@ -78,8 +88,14 @@ func (h *ShellHandler) Execute(step map[string]any, ctx *interpolate.Context) St
 
 	exitCode := 0
 	if err != nil {
-		if exitError, ok := err.(*exec.ExitError); ok {
-			exitCode = exitError.ExitCode()
+		if cause := stepCancelled(ctx); cause != nil {
+			return StepResult{
+				Success:  false,
+				ExitCode: 130,
+				Stdout:   stdout.String(),
+				Stderr:   stderr.String(),
+				Error:    "command killed: " + cause.Error(),
+			}
 		} else if cmdCtx.Err() == context.DeadlineExceeded {
 			return StepResult{
 				Success:  false,
@@ -88,6 +104,8 @@ func (h *ShellHandler) Execute(step map[string]any, ctx *interpolate.Context) St
 				Stderr:   stderr.String(),
 				Error:    fmt.Sprintf("command timed out after %v", timeout),
 			}
+		} else if exitError, ok := err.(*exec.ExitError); ok {
+			exitCode = exitError.ExitCode()
 		} else {
 			return StepResult{
 				Success:  false,
@@ -107,3 +125,95 @@ func (h *ShellHandler) Execute(step map[string]any, ctx *interpolate.Context) St
 		Error:    "",
 	}
 }
+
+// shellSettings returns the shell and strict mode of a step: its own shell
+// and strict fields, else the suite's execution.shell and strict_shell
+func shellSettings(step map[string]any, ctx *interpolate.Context) (string, bool) {
+	shell, _ := step["shell"].(string)
+	strict, hasStrict := step["strict"].(bool)
+
+	execution, _ := ctx.Config["execution"].(map[string]any)
+	if shell == "" {
+		shell, _ = execution["shell"].(string)
+	}
+	if !hasStrict {
+		strict, _ = execution["strict_shell"].(bool)
+	}
+	if shell == "" {
+		shell = "bash"
+	}
+	return shell, strict
+}
+
+// shellArgs returns the command line running a script in a shell. Strict
+// mode stops the script at the first failing command, unset variable or
+// (bash) failing pipeline stage.
+func shellArgs(shell string, strict bool, script string) ([]string, error) {
+	switch shell {
+	case "bash":
+		if strict {
+			script = "set -euo pipefail\n" + script
+		}
+		return []string{"bash", "-c", script}, nil
+	case "sh":
+		if strict {
+			script = "set -eu\n" + script
+		}
+		return []string{"sh", "-c", script}, nil
+	case "pwsh":
+		if strict {
+			script = "$ErrorActionPreference = 'Stop'\nSet-StrictMode -Version Latest\n" + script
+		}
+		return []string{"pwsh", "-NoProfile", "-NonInteractive", "-Command", script}, nil
+	}
+	return nil, fmt.Errorf("unknown shell %q (expected bash, sh or pwsh)", shell)
+}
+
+// workspaceBinDirs are the directories of tools installed into a workdir,
+// in PATH order
+var workspaceBinDirs = []struct {
+	dir        string
+	virtualEnv bool // dir is the bin directory of a Python virtualenv
+}{
+	{".venv/bin", true},
+	{"venv/bin", true},
+	{"node_modules/.bin", false},
+}
+
+// workspaceEnv puts the workdir's virtualenv and node_modules/.bin first on
+// PATH, so scripts find the tools installed for the test the same way in
+// standalone and docker mode. A virtualenv also sets VIRTUAL_ENV.
+func workspaceEnv(env []string, workdir string) []string {
+	var dirs []string
+	virtualEnv := ""
+	for _, bin := range workspaceBinDirs {
+		path := filepath.Join(workdir, bin.dir)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			dirs = append(dirs, path)
+			if bin.virtualEnv && virtualEnv == "" {
+				virtualEnv = filepath.Dir(path)
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		return env
+	}
+
+	pathValue := strings.Join(dirs, string(os.PathListSeparator))
+	result := make([]string, 0, len(env)+2)
+	for _, kv := range env {
+		if value, ok := strings.CutPrefix(kv, "PATH="); ok {
+			pathValue += string(os.PathListSeparator) + value
+			continue
+		}
+		if virtualEnv != "" && strings.HasPrefix(kv, "VIRTUAL_ENV=") {
+			continue
+		}
+		result = append(result, kv)
+	}
+	result = append(result, "PATH="+pathValue)
+	if virtualEnv != "" {
+		result = append(result, "VIRTUAL_ENV="+virtualEnv)
+	}
+	return result
+}
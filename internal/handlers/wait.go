@@ -23,7 +23,7 @@ func (h *WaitHandler) Execute(step map[string]any, ctx *interpolate.Context) Ste
 
 	switch waitType {
 	case "seconds":
-		return h.waitSeconds(step)
+		return h.waitSeconds(step, ctx)
 	case "http":
 		return h.waitHTTP(step, ctx)
 	default:
@@ -34,13 +34,15 @@ func (h *WaitHandler) Execute(step map[string]any, ctx *interpolate.Context) Ste
 	}
 }
 
-func (h *WaitHandler) waitSeconds(step map[string]any) StepResult {
+func (h *WaitHandler) waitSeconds(step map[string]any, ctx *interpolate.Context) StepResult {
 	seconds := 1
 	if s, ok := step["seconds"].(int); ok && s > 0 {
 		seconds = s
 	}
 
-	time.Sleep(time.Duration(seconds) * time.Second)
+	if !sleep(ctx, time.Duration(seconds)*time.Second) {
+		return waitCancelled(ctx)
+	}
 
 	return StepResult{
 		Success:  true,
@@ -80,7 +82,15 @@ func (h *WaitHandler) waitHTTP(step map[string]any, ctx *interpolate.Context) St
 	}
 
 	for time.Since(startTime) < timeoutDuration {
-		resp, err := client.Get(url)
+		req, err := http.NewRequestWithContext(ctx.RunContext(), http.MethodGet, url, nil)
+		if err != nil {
+			return StepResult{
+				Success:  false,
+				ExitCode: 1,
+				Error:    fmt.Sprintf("invalid url %s: %v", url, err),
+			}
+		}
+		resp, err := client.Do(req)
 		if err == nil {
 			resp.Body.Close()
 			if resp.StatusCode < 400 {
@@ -91,7 +101,9 @@ func (h *WaitHandler) waitHTTP(step map[string]any, ctx *interpolate.Context) St
 				}
 			}
 		}
-		time.Sleep(intervalDuration)
+		if !sleep(ctx, intervalDuration) {
+			return waitCancelled(ctx)
+		}
 	}
 
 	return StepResult{
@@ -100,3 +112,23 @@ func (h *WaitHandler) waitHTTP(step map[string]any, ctx *interpolate.Context) St
 		Error:    fmt.Sprintf("URL %s not ready after %d seconds", url, timeout),
 	}
 }
+
+// sleep waits for d and returns false if the step is cancelled first
+func sleep(ctx *interpolate.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.RunContext().Done():
+		return false
+	}
+}
+
+func waitCancelled(ctx *interpolate.Context) StepResult {
+	return StepResult{
+		Success:  false,
+		ExitCode: 130,
+		Error:    "wait cancelled: " + stepCancelled(ctx).Error(),
+	}
+}
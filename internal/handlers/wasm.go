@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// DefaultWasmRuntime is the WASI runtime used when TSUITE_WASM_RUNTIME is not set
+const DefaultWasmRuntime = "wasmtime"
+
+// WasmHandler runs a prebuilt WASI module as a step handler.
+//
+// The module receives {"step": {...}, "context": {...}} as JSON on stdin and
+// may write a JSON result ({"success", "exit_code", "stdout", "stderr", "error"})
+// to stdout. Any other output is treated as plain stdout, and success is
+// derived from the module's exit code.
+type WasmHandler struct {
+	name       string
+	modulePath string
+}
+
+// NewWasmHandler creates a handler for the module at modulePath
+func NewWasmHandler(name, modulePath string) *WasmHandler {
+	return &WasmHandler{name: name, modulePath: modulePath}
+}
+
+func (h *WasmHandler) Name() string {
+	return h.name
+}
+
+func (h *WasmHandler) Execute(step map[string]any, ctx *interpolate.Context) StepResult {
+	runtime := os.Getenv("TSUITE_WASM_RUNTIME")
+	if runtime == "" {
+		runtime = DefaultWasmRuntime
+	}
+	if _, err := exec.LookPath(runtime); err != nil {
+		return StepResult{
+			Success: false,
+			Error:   fmt.Sprintf("wasm runtime %q not found (install it or set TSUITE_WASM_RUNTIME)", runtime),
+		}
+	}
+
+	input, err := json.Marshal(map[string]any{
+		"step":    step,
+		"context": publicContext(ctx),
+	})
+	if err != nil {
+		return StepResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to encode step for wasm handler: %v", err),
+		}
+	}
+
+	// Get workdir
+	workdir := "/workspace"
+	if w, ok := step["workdir"].(string); ok && w != "" {
+		workdir = w
+	} else if ctx.Workdir != "" {
+		workdir = ctx.Workdir
+	}
+
+	// Get timeout
+	timeout := 120 * time.Second
+	if t, ok := step["timeout"].(int); ok && t > 0 {
+		timeout = time.Duration(t) * time.Second
+	}
+
+	cmdCtx, cancel := stepContext(ctx, timeout)
+	defer cancel()
+
+	// Both wasmtime and wasmer accept "run --dir=<dir> <module>"
+	cmd := exec.CommandContext(cmdCtx, runtime, "run", "--dir="+workdir, h.modulePath)
+	cmd.Dir = workdir
+	cmd.Stdin = bytes.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+
+	exitCode := 0
+	if err != nil {
+		if cause := stepCancelled(ctx); cause != nil {
+			return StepResult{
+				Success:  false,
+				ExitCode: 130,
+				Stdout:   stdout.String(),
+				Stderr:   stderr.String(),
+				Error:    "wasm handler killed: " + cause.Error(),
+			}
+		} else if cmdCtx.Err() == context.DeadlineExceeded {
+			return StepResult{
+				Success:  false,
+				ExitCode: 124,
+				Stdout:   stdout.String(),
+				Stderr:   stderr.String(),
+				Error:    fmt.Sprintf("wasm handler timed out after %v", timeout),
+			}
+		} else if exitError, ok := err.(*exec.ExitError); ok {
+			exitCode = exitError.ExitCode()
+		} else {
+			return StepResult{
+				Success:  false,
+				ExitCode: 1,
+				Stdout:   stdout.String(),
+				Stderr:   stderr.String(),
+				Error:    err.Error(),
+			}
+		}
+	}
+
+	// Prefer a structured result if the module wrote one
+	output := bytes.TrimSpace(stdout.Bytes())
+	var result StepResult
+	if bytes.HasPrefix(output, []byte("{")) && json.Unmarshal(output, &result) == nil {
+		if result.Stderr == "" {
+			result.Stderr = stderr.String()
+		}
+		return result
+	}
+
+	return StepResult{
+		Success:  exitCode == 0,
+		ExitCode: exitCode,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+	}
+}
+
+// LoadWasmHandlers registers every *.wasm module in dir, named after the file
+// (e.g. handlers/kafka-produce.wasm registers "kafka-produce"). Modules cannot
+// replace built-in or compiled-in handlers.
+func (r *Registry) LoadWasmHandlers(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".wasm" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".wasm")
+		if _, exists := r.Get(name); exists {
+			return fmt.Errorf("wasm handler %s conflicts with existing handler %q", entry.Name(), name)
+		}
+		r.Register(NewWasmHandler(name, filepath.Join(dir, entry.Name())))
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package interpolate
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -13,22 +14,56 @@ type AssertionResult struct {
 	Message       string
 	ActualValue   string
 	ExpectedValue string
+	Diff          string // How actual differs from expected, for failed equality (see Diff)
 }
 
+// Assertion operators
+const operators = `(==|!=|>=|<=|>|<|contains|matches|exists|not\s+(?:exists|contains|icontains|matches|startswith|endswith)|is|length|iequal|ieq|icontains|startswith|endswith)`
+
 // Expression pattern: ${var} operator value
 var exprPattern = regexp.MustCompile(
 	`^\$\{([^}]+)\}\s+` +
-		`(==|!=|>=|<=|>|<|contains|matches|exists|not\s+exists|not\s+contains|is|length|iequal|ieq|icontains|startswith|endswith)\s*` +
+		operators + `\s*` +
 		`(.*)$`)
 
+// operatorPattern matches the operator and value after a function assertion
+var operatorPattern = regexp.MustCompile(`^` + operators + `\s*(.*)$`)
+
+// NormalizeAssertion reduces an expression to its variable and operator so that
+// assertions differing only in expected value can be grouped together.
+// Example: ${stdout} contains "agent-a" -> ${stdout} contains <value>
+func NormalizeAssertion(expr string) string {
+	expr = strings.Join(strings.Fields(expr), " ")
+
+	if name, args, rest, ok := parseFuncExpr(expr); ok {
+		return normalizeFuncAssertion(name, args, rest)
+	}
+
+	match := exprPattern.FindStringSubmatch(expr)
+	if match == nil {
+		return expr
+	}
+
+	normalized := fmt.Sprintf("${%s} %s", strings.TrimSpace(match[1]), strings.ToLower(strings.Join(strings.Fields(match[2]), " ")))
+	if strings.TrimSpace(match[3]) != "" {
+		normalized += " <value>"
+	}
+	return normalized
+}
+
 // EvaluateAssertion evaluates an assertion expression
 // Examples:
 // - ${exit_code} == 0
 // - ${stdout} contains "success"
 // - ${json:$.status} == "ok"
+// - file_exists('${workdir}/out.json')
 func EvaluateAssertion(expr string, ctx *Context) AssertionResult {
 	expr = strings.TrimSpace(expr)
 
+	if name, args, rest, ok := parseFuncExpr(expr); ok {
+		return evaluateFuncAssertion(expr, name, args, rest, ctx)
+	}
+
 	match := exprPattern.FindStringSubmatch(expr)
 	if match == nil {
 		return AssertionResult{
@@ -38,12 +73,20 @@ func EvaluateAssertion(expr string, ctx *Context) AssertionResult {
 	}
 
 	varName := match[1]
-	operator := strings.ToLower(strings.TrimSpace(match[2]))
+	operator := match[2]
 	expectedRaw := strings.TrimSpace(match[3])
 
 	// Resolve the variable
 	actual, _ := ResolveVariable(varName, ctx)
 
+	return evaluateOperator(actual, operator, expectedRaw, ctx)
+}
+
+// evaluateOperator compares a resolved value with the raw expected value of
+// an expression
+func evaluateOperator(actual any, operator, expectedRaw string, ctx *Context) AssertionResult {
+	operator = strings.ToLower(strings.Join(strings.Fields(operator), " "))
+
 	// Handle operators that don't need an expected value
 	if operator == "exists" {
 		passed := actual != nil
@@ -81,10 +124,19 @@ func EvaluateAssertion(expr string, ctx *Context) AssertionResult {
 	// Interpolate variables in expected value
 	expected, _ = Interpolate(expected, ctx)
 
+	// not icontains, not matches, ...: the negation of the operator
+	if negated, ok := strings.CutPrefix(operator, "not "); ok && operator != "not contains" {
+		result := evaluateOperator(actual, negated, expectedRaw, ctx)
+		if !strings.HasPrefix(result.Message, "Invalid") {
+			result.Passed = !result.Passed
+		}
+		return result
+	}
+
 	// Execute operator
 	switch operator {
 	case "==":
-		return evaluateEquals(actual, expected)
+		return withDiff(evaluateEquals(actual, expected), actual, expected)
 
 	case "!=":
 		return evaluateNotEquals(actual, expected)
@@ -99,7 +151,7 @@ func EvaluateAssertion(expr string, ctx *Context) AssertionResult {
 		return evaluateContains(actual, expected, true)
 
 	case "iequal", "ieq":
-		return evaluateIEquals(actual, expected)
+		return withDiff(evaluateIEquals(actual, expected), actual, expected)
 
 	case "startswith":
 		return evaluateStartsWith(actual, expected)
@@ -111,6 +163,12 @@ func EvaluateAssertion(expr string, ctx *Context) AssertionResult {
 		return evaluateMatches(actual, expected)
 
 	case "is":
+		switch strings.ToLower(strings.Join(strings.Fields(expected), " ")) {
+		case "empty":
+			return evaluateEmpty(actual, true)
+		case "not empty":
+			return evaluateEmpty(actual, false)
+		}
 		return evaluateIs(actual, expected)
 
 	case "length":
@@ -127,6 +185,14 @@ func EvaluateAssertion(expr string, ctx *Context) AssertionResult {
 	}
 }
 
+// withDiff adds the diff of actual and expected to a failed result
+func withDiff(result AssertionResult, actual any, expected string) AssertionResult {
+	if !result.Passed {
+		result.Diff = Diff(actual, expected)
+	}
+	return result
+}
+
 func evaluateEquals(actual any, expected string) AssertionResult {
 	actualStr := fmt.Sprintf("%v", actual)
 
@@ -327,6 +393,48 @@ func evaluateIs(actual any, expectedType string) AssertionResult {
 	}
 }
 
+// evaluateEmpty checks that a value is (or is not) empty: missing, null,
+// blank text, or an empty array or object, also as JSON text
+func evaluateEmpty(actual any, wantEmpty bool) AssertionResult {
+	empty := isEmpty(actual)
+	msg := "is empty"
+	if !empty {
+		msg = "is not empty"
+	}
+	expected := "empty"
+	if !wantEmpty {
+		expected = "not empty"
+	}
+	return AssertionResult{
+		Passed:        empty == wantEmpty,
+		Message:       msg,
+		ActualValue:   fmt.Sprintf("%v", actual),
+		ExpectedValue: expected,
+	}
+}
+
+func isEmpty(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		text := strings.TrimSpace(val)
+		if text == "" {
+			return true
+		}
+		var doc any
+		if json.Unmarshal([]byte(text), &doc) == nil && isJSONContainer(doc) {
+			return isEmpty(doc)
+		}
+		return text == "null"
+	case []any:
+		return len(val) == 0
+	case map[string]any:
+		return len(val) == 0
+	}
+	return false
+}
+
 func evaluateLength(actual any, expected string) AssertionResult {
 	// Parse "length > 5" style
 	lengthPattern := regexp.MustCompile(`([><=!]+)\s*(\d+)`)
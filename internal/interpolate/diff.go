@@ -0,0 +1,209 @@
+package interpolate
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Limits that keep diffs of large values readable and cheap
+const (
+	diffContext   = 3    // Unchanged lines around each change
+	diffMaxLines  = 2000 // Longer texts are not diffed line by line
+	diffMaxChange = 100  // Changed JSON paths listed
+)
+
+// Diff describes how actual differs from expected for a failed equality
+// assertion. When both parse as JSON and one is an object or array, the
+// values are compared by JSON path; otherwise multi-line texts get a
+// unified diff. Lines start with "-" for expected and "+" for actual.
+// Returns "" when a diff would say no more than the two values.
+func Diff(actual any, expected string) string {
+	actualStr := diffString(actual)
+
+	var expectedJSON, actualJSON any
+	if json.Unmarshal([]byte(expected), &expectedJSON) == nil &&
+		json.Unmarshal([]byte(actualStr), &actualJSON) == nil &&
+		(isJSONContainer(expectedJSON) || isJSONContainer(actualJSON)) {
+		var lines []string
+		diffJSON("$", expectedJSON, actualJSON, &lines)
+		if len(lines) == 0 {
+			return "equal as JSON: the values differ only in formatting or key order"
+		}
+		if len(lines) > diffMaxChange {
+			lines = append(lines[:diffMaxChange], fmt.Sprintf("... %d more differences", len(lines)-diffMaxChange))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	if !strings.Contains(expected, "\n") && !strings.Contains(actualStr, "\n") {
+		return ""
+	}
+	return unifiedDiff(strings.Split(expected, "\n"), strings.Split(actualStr, "\n"))
+}
+
+// diffString formats a resolved value as the assertion compares it, with
+// JSON query results as JSON
+func diffString(v any) string {
+	switch v.(type) {
+	case map[string]any, []any:
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func isJSONContainer(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// diffJSON appends the differences between two JSON values under path
+func diffJSON(path string, expected, actual any, lines *[]string) {
+	switch exp := expected.(type) {
+	case map[string]any:
+		act, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(exp)+len(act))
+		for k := range exp {
+			keys = append(keys, k)
+		}
+		for k := range act {
+			if _, ok := exp[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "." + k
+			e, inExp := exp[k]
+			a, inAct := act[k]
+			switch {
+			case !inAct:
+				*lines = append(*lines, fmt.Sprintf("- %s: %s", child, jsonValue(e)))
+			case !inExp:
+				*lines = append(*lines, fmt.Sprintf("+ %s: %s", child, jsonValue(a)))
+			default:
+				diffJSON(child, e, a, lines)
+			}
+		}
+		return
+	case []any:
+		act, ok := actual.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(exp), len(act)); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(act):
+				*lines = append(*lines, fmt.Sprintf("- %s: %s", child, jsonValue(exp[i])))
+			case i >= len(exp):
+				*lines = append(*lines, fmt.Sprintf("+ %s: %s", child, jsonValue(act[i])))
+			default:
+				diffJSON(child, exp[i], act[i], lines)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		*lines = append(*lines,
+			fmt.Sprintf("- %s: %s", path, jsonValue(expected)),
+			fmt.Sprintf("+ %s: %s", path, jsonValue(actual)))
+	}
+}
+
+func jsonValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// unifiedDiff returns a unified diff of two texts split into lines, with
+// diffContext unchanged lines around each change
+func unifiedDiff(expected, actual []string) string {
+	if len(expected) > diffMaxLines || len(actual) > diffMaxLines {
+		return fmt.Sprintf("texts too long to diff (%d and %d lines)", len(expected), len(actual))
+	}
+
+	// Longest common subsequence of lines, from the end
+	lcs := make([][]int, len(expected)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(actual)+1)
+	}
+	for i := len(expected) - 1; i >= 0; i-- {
+		for j := len(actual) - 1; j >= 0; j-- {
+			if expected[i] == actual[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte // ' ', '-' or '+'
+		text string
+		i, j int // Line numbers in expected and actual before this line
+	}
+	var ops []diffLine
+	i, j := 0, 0
+	for i < len(expected) || j < len(actual) {
+		switch {
+		case i < len(expected) && j < len(actual) && expected[i] == actual[j]:
+			ops = append(ops, diffLine{' ', expected[i], i, j})
+			i, j = i+1, j+1
+		case i < len(expected) && (j == len(actual) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffLine{'-', expected[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffLine{'+', actual[j], i, j})
+			j++
+		}
+	}
+
+	// Group changes into hunks with their context
+	var b strings.Builder
+	b.WriteString("--- expected\n+++ actual")
+	for start := 0; start < len(ops); {
+		if ops[start].op == ' ' {
+			start++
+			continue
+		}
+		from := max(start-diffContext, 0)
+		end := start
+		for k := start; k < len(ops) && k-end <= 2*diffContext; k++ {
+			if ops[k].op != ' ' {
+				end = k
+			}
+		}
+		to := min(end+diffContext+1, len(ops))
+
+		expLines, actLines := 0, 0
+		for _, op := range ops[from:to] {
+			if op.op != '+' {
+				expLines++
+			}
+			if op.op != '-' {
+				actLines++
+			}
+		}
+		fmt.Fprintf(&b, "\n@@ -%d,%d +%d,%d @@", ops[from].i+1, expLines, ops[from].j+1, actLines)
+		for _, op := range ops[from:to] {
+			fmt.Fprintf(&b, "\n%c%s", op.op, op.text)
+		}
+		start = to
+	}
+	return b.String()
+}
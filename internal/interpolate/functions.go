@@ -0,0 +1,258 @@
+package interpolate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+)
+
+// assertionFunc is a function usable as an assertion: name(args...).
+// Checks are assertions on their own; values are compared by an operator,
+// like a ${...} reference: name(args...) == value.
+type assertionFunc struct {
+	minArgs, maxArgs int
+	check            func(args []any, ctx *Context) AssertionResult
+	value            func(args []any, ctx *Context) (any, error)
+}
+
+var assertionFuncs = map[string]assertionFunc{
+	"file_exists":   {minArgs: 1, maxArgs: 1, check: fileExists},
+	"file_contains": {minArgs: 2, maxArgs: 2, check: fileContains},
+	"file_json":     {minArgs: 2, maxArgs: 2, value: fileJSON},
+	"count":         {minArgs: 1, maxArgs: 2, value: count},
+}
+
+// funcExprPattern matches the start of a function assertion: its name
+var funcExprPattern = regexp.MustCompile(`^([a-z_]+)\(`)
+
+// parseFuncExpr splits a function assertion into its name, its arguments
+// (unquoted, not yet interpolated) and what follows the closing parenthesis.
+// Arguments are separated by commas; quote them to include commas or
+// parentheses.
+func parseFuncExpr(expr string) (name string, args []string, rest string, ok bool) {
+	match := funcExprPattern.FindStringSubmatch(expr)
+	if match == nil {
+		return "", nil, "", false
+	}
+	if _, known := assertionFuncs[match[1]]; !known {
+		return "", nil, "", false
+	}
+
+	var arg strings.Builder
+	var quote rune
+	depth := 0
+	for i, c := range expr[len(match[0]):] {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			arg.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote = c
+			arg.WriteRune(c)
+		case c == '{' || c == '(':
+			depth++
+			arg.WriteRune(c)
+		case (c == '}' || c == ')') && depth > 0:
+			depth--
+			arg.WriteRune(c)
+		case c == ',' && depth == 0:
+			args = append(args, unquote(strings.TrimSpace(arg.String())))
+			arg.Reset()
+		case c == ')':
+			if s := strings.TrimSpace(arg.String()); s != "" || len(args) > 0 {
+				args = append(args, unquote(s))
+			}
+			return match[1], args, strings.TrimSpace(expr[len(match[0])+i+1:]), true
+		default:
+			arg.WriteRune(c)
+		}
+	}
+	return "", nil, "", false
+}
+
+// resolveArg resolves a function argument. An argument that is a single
+// ${...} reference keeps the type of its value (nil when it doesn't
+// resolve); others are interpolated into a string.
+func resolveArg(arg string, ctx *Context) (any, error) {
+	if m := varPattern.FindStringSubmatchIndex(arg); m != nil && m[0] == 0 && m[1] == len(arg) {
+		return ResolveVariable(arg[2:len(arg)-1], ctx)
+	}
+	return Interpolate(arg, ctx)
+}
+
+// argString formats a resolved argument as text; nil is ""
+func argString(arg any) string {
+	if arg == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", arg)
+}
+
+// unquote removes one pair of surrounding quotes
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// evaluateFuncAssertion evaluates name(args...) [operator value]
+func evaluateFuncAssertion(expr, name string, rawArgs []string, rest string, ctx *Context) AssertionResult {
+	fn := assertionFuncs[name]
+	if len(rawArgs) < fn.minArgs || len(rawArgs) > fn.maxArgs {
+		want := strconv.Itoa(fn.minArgs)
+		if fn.maxArgs > fn.minArgs {
+			want += "-" + strconv.Itoa(fn.maxArgs)
+		}
+		return AssertionResult{Message: fmt.Sprintf("%s takes %s argument(s), got %d: %s", name, want, len(rawArgs), expr)}
+	}
+	args := make([]any, len(rawArgs))
+	for i, arg := range rawArgs {
+		var err error
+		if args[i], err = resolveArg(arg, ctx); err != nil {
+			return AssertionResult{Message: fmt.Sprintf("failed to interpolate %s argument %q: %v", name, arg, err)}
+		}
+	}
+
+	if fn.check != nil {
+		if rest != "" {
+			return AssertionResult{Message: fmt.Sprintf("%s is an assertion on its own and takes no operator: %s", name, expr)}
+		}
+		return fn.check(args, ctx)
+	}
+
+	actual, err := fn.value(args, ctx)
+	if err != nil {
+		return AssertionResult{Message: fmt.Sprintf("%s: %v", name, err)}
+	}
+	if rest == "" {
+		return evaluateOperator(actual, "exists", "", ctx)
+	}
+	match := operatorPattern.FindStringSubmatch(rest)
+	if match == nil {
+		return AssertionResult{Message: fmt.Sprintf("Invalid expression syntax: %s", expr)}
+	}
+	return evaluateOperator(actual, strings.ToLower(strings.TrimSpace(match[1])), strings.TrimSpace(match[2]), ctx)
+}
+
+// normalizeFuncAssertion reduces a function assertion to its function and
+// operator, like NormalizeAssertion
+func normalizeFuncAssertion(name string, args []string, rest string) string {
+	normalized := name + "(" + strings.Join(args, ", ") + ")"
+	if match := operatorPattern.FindStringSubmatch(rest); match != nil {
+		normalized += " " + strings.ToLower(match[1])
+		if strings.TrimSpace(match[2]) != "" {
+			normalized += " <value>"
+		}
+	}
+	return normalized
+}
+
+// assertionPath resolves a file argument; relative paths are relative to the
+// test's workdir
+func assertionPath(path string, ctx *Context) string {
+	if filepath.IsAbs(path) || ctx.Workdir == "" {
+		return path
+	}
+	return filepath.Join(ctx.Workdir, path)
+}
+
+func fileExists(args []any, ctx *Context) AssertionResult {
+	path := assertionPath(argString(args[0]), ctx)
+	_, err := os.Stat(path)
+	passed := err == nil
+	msg := path + " exists"
+	if !passed {
+		msg = path + " does not exist"
+	}
+	return AssertionResult{Passed: passed, Message: msg, ActualValue: path}
+}
+
+func fileContains(args []any, ctx *Context) AssertionResult {
+	path := assertionPath(argString(args[0]), ctx)
+	text := argString(args[1])
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AssertionResult{Message: fmt.Sprintf("failed to read %s: %v", path, err), ExpectedValue: text}
+	}
+	result := evaluateContains(string(data), text, false)
+	result.Message = path + " " + result.Message
+	return result
+}
+
+func fileJSON(args []any, ctx *Context) (any, error) {
+	path := assertionPath(argString(args[0]), ctx)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s is not JSON: %w", path, err)
+	}
+	// A path matching nothing resolves to nil, as in ${jsonfile:...}
+	value, _ := jsonpath.Get(argString(args[1]), doc)
+	return value, nil
+}
+
+// count returns how many elements an array or object has, or how many
+// non-blank lines a text has; JSON text counts as its array or object.
+// With a second argument it counts the array elements equal to it, or its
+// occurrences in the text. A missing value counts 0.
+func count(args []any, ctx *Context) (any, error) {
+	value := args[0]
+	if text, ok := value.(string); ok {
+		var doc any
+		if json.Unmarshal([]byte(text), &doc) == nil && isJSONContainer(doc) {
+			value = doc
+		}
+	}
+
+	if len(args) == 1 {
+		switch v := value.(type) {
+		case nil:
+			return 0, nil
+		case []any:
+			return len(v), nil
+		case map[string]any:
+			return len(v), nil
+		case string:
+			n := 0
+			for _, line := range strings.Split(v, "\n") {
+				if strings.TrimSpace(line) != "" {
+					n++
+				}
+			}
+			return n, nil
+		}
+		return nil, fmt.Errorf("cannot count a %s", getTypeName(value))
+	}
+
+	needle := argString(args[1])
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case []any:
+		n := 0
+		for _, element := range v {
+			if diffString(element) == needle {
+				n++
+			}
+		}
+		return n, nil
+	case string:
+		if needle == "" {
+			return nil, fmt.Errorf("cannot count an empty string")
+		}
+		return strings.Count(v, needle), nil
+	}
+	return nil, fmt.Errorf("cannot count in a %s", getTypeName(value))
+}
@@ -2,8 +2,10 @@ package interpolate
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -30,6 +32,13 @@ type Context struct {
 	Artifacts     string         `json:"artifacts"`       // Test-specific artifacts directory
 	UCArtifacts   string         `json:"uc_artifacts"`    // Use-case level artifacts directory
 	Extra         map[string]any `json:"-"`               // Additional top-level variables
+
+	// Tracer, if set, is called for every variable resolution
+	Tracer func(Resolution) `json:"-"`
+
+	// Run, if set, is done once the test is cancelled. Handlers derive the
+	// context of the step they run from it (see RunContext).
+	Run context.Context `json:"-"`
 }
 
 // NewContext creates a new context with initialized maps
@@ -45,6 +54,30 @@ func NewContext() *Context {
 	}
 }
 
+// Clone returns a copy of the context whose top-level maps can be changed
+// without affecting the original. The tracer is not copied.
+func (c *Context) Clone() *Context {
+	clone := *c
+	clone.Config = maps.Clone(c.Config)
+	clone.State = maps.Clone(c.State)
+	clone.Captured = maps.Clone(c.Captured)
+	clone.Last = maps.Clone(c.Last)
+	clone.Steps = maps.Clone(c.Steps)
+	clone.Params = maps.Clone(c.Params)
+	clone.Extra = maps.Clone(c.Extra)
+	clone.Tracer = nil
+	return &clone
+}
+
+// RunContext returns the context steps run under: Run, or a context that is
+// never done
+func (c *Context) RunContext() context.Context {
+	if c.Run == nil {
+		return context.Background()
+	}
+	return c.Run
+}
+
 // Pattern for ${...} variables
 var varPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
 
@@ -62,6 +95,15 @@ func Interpolate(text string, ctx *Context) (string, error) {
 	return result, nil
 }
 
+// References returns the variable names of the ${...} references in a string
+func References(text string) []string {
+	var names []string
+	for _, m := range varPattern.FindAllStringSubmatch(text, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
 // InterpolateMap recursively interpolates all string values in a map
 func InterpolateMap(m map[string]any, ctx *Context) (map[string]any, error) {
 	result := make(map[string]any)
@@ -142,48 +184,62 @@ func InterpolateSlice(s []any, ctx *Context) ([]any, error) {
 // - fixture:expected/foo.json -> Fixture file contents
 // - env:VAR_NAME -> Environment variable
 // - params.name -> Routine parameter
+// - snapdiff:before:after:added -> Diff of two captured registry snapshots
 func ResolveVariable(varName string, ctx *Context) (any, error) {
+	value, source, err := resolveVariable(varName, ctx)
+	if ctx.Tracer != nil {
+		ctx.Tracer(Resolution{Expr: varName, Source: source, Value: value})
+	}
+	return value, err
+}
+
+// resolveVariable resolves a variable reference and reports where the value
+// came from
+func resolveVariable(varName string, ctx *Context) (any, string, error) {
 	// Handle prefixed variables
 	switch {
 	case strings.HasPrefix(varName, "config."):
-		return resolvePath(ctx.Config, varName[7:]), nil
+		return resolvePath(ctx.Config, varName[7:]), "config", nil
 
 	case strings.HasPrefix(varName, "state."):
-		return resolvePath(ctx.State, varName[6:]), nil
+		return resolvePath(ctx.State, varName[6:]), "state", nil
 
 	case strings.HasPrefix(varName, "captured."):
-		return resolvePath(ctx.Captured, varName[9:]), nil
+		return resolvePath(ctx.Captured, varName[9:]), "captured", nil
 
 	case strings.HasPrefix(varName, "last."):
-		return resolvePath(ctx.Last, varName[5:]), nil
+		return resolvePath(ctx.Last, varName[5:]), "last", nil
 
 	case strings.HasPrefix(varName, "params."):
-		return resolvePath(ctx.Params, varName[7:]), nil
+		return resolvePath(ctx.Params, varName[7:]), "params", nil
 
 	case strings.HasPrefix(varName, "steps."):
-		return resolvePath(ctx.Steps, varName[6:]), nil
+		return resolvePath(ctx.Steps, varName[6:]), "steps", nil
 
 	case strings.HasPrefix(varName, "json:"):
 		// JSONPath on stdout
 		path := varName[5:]
 		stdout, _ := ctx.Last["stdout"].(string)
-		return resolveJSONPath(stdout, path)
+		value, err := resolveJSONPath(stdout, path)
+		return value, "jsonpath(last.stdout)", err
 
 	case strings.HasPrefix(varName, "jq:"):
 		// jq query
-		return resolveJQ(varName[3:], ctx)
+		value, err := resolveJQ(varName[3:], ctx)
+		return value, "jq(" + jqInputName(varName[3:]) + ")", err
 
 	case strings.HasPrefix(varName, "jsonfile:"):
 		// ${jsonfile:/path:$.query}
-		return resolveJSONFile(varName[9:])
+		value, err := resolveJSONFile(varName[9:])
+		return value, "jsonfile", err
 
 	case strings.HasPrefix(varName, "file:"):
 		path := varName[5:]
 		data, err := os.ReadFile(path)
 		if err != nil {
-			return nil, nil
+			return nil, "file", nil
 		}
-		return string(data), nil
+		return string(data), "file", nil
 
 	case strings.HasPrefix(varName, "fixture:"):
 		fixtureName := varName[8:]
@@ -191,53 +247,57 @@ func ResolveVariable(varName string, ctx *Context) (any, error) {
 			path := filepath.Join(ctx.FixturesDir, fixtureName)
 			data, err := os.ReadFile(path)
 			if err != nil {
-				return nil, nil
+				return nil, "fixture", nil
 			}
-			return string(data), nil
+			return string(data), "fixture", nil
 		}
-		return nil, nil
+		return nil, "fixture", nil
 
 	case strings.HasPrefix(varName, "env:"):
-		return os.Getenv(varName[4:]), nil
+		return os.Getenv(varName[4:]), "env", nil
+
+	case strings.HasPrefix(varName, "snapdiff:"):
+		value, err := resolveSnapshotDiff(varName[9:], ctx)
+		return value, "snapdiff", err
 	}
 
 	// Try common paths without prefix
 	// Check last first (most common in assertions)
 	if varName == "exit_code" || varName == "stdout" || varName == "stderr" {
-		return ctx.Last[varName], nil
+		return ctx.Last[varName], "last", nil
 	}
 
 	// Check top-level context variables
 	switch varName {
 	case "suite_path":
-		return ctx.SuitePath, nil
+		return ctx.SuitePath, "context", nil
 	case "workdir":
-		return ctx.Workdir, nil
+		return ctx.Workdir, "context", nil
 	case "fixtures_dir":
-		return ctx.FixturesDir, nil
+		return ctx.FixturesDir, "context", nil
 	case "artifacts", "artifacts_path":
-		return ctx.Artifacts, nil
+		return ctx.Artifacts, "context", nil
 	case "uc_artifacts", "uc_artifacts_path":
-		return ctx.UCArtifacts, nil
+		return ctx.UCArtifacts, "context", nil
 	}
 
 	// Check Extra map for additional top-level variables
 	if val, ok := ctx.Extra[varName]; ok {
-		return val, nil
+		return val, "context", nil
 	}
 
 	// Then check captured
 	if val, ok := ctx.Captured[varName]; ok {
-		return val, nil
+		return val, "captured (unprefixed)", nil
 	}
 
 	// Then check state
 	if val, ok := ctx.State[varName]; ok {
-		return val, nil
+		return val, "state (unprefixed)", nil
 	}
 
 	// Then check config (for unprefixed config access)
-	return resolvePath(ctx.Config, varName), nil
+	return resolvePath(ctx.Config, varName), "config (unprefixed)", nil
 }
 
 // resolvePath resolves a dot-notation path in a map
@@ -277,6 +337,15 @@ func resolveJSONPath(jsonStr string, path string) (any, error) {
 	return result, nil
 }
 
+// jqInputName names the input of a jq query for traces
+func jqInputName(query string) string {
+	if strings.HasPrefix(query, "captured.") {
+		name, _, _ := strings.Cut(query[9:], ":")
+		return "captured." + name
+	}
+	return "last.stdout"
+}
+
 // resolveJQ executes a jq query
 func resolveJQ(query string, ctx *Context) (any, error) {
 	var inputData string
@@ -0,0 +1,157 @@
+package interpolate
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SnapshotDiff describes the differences between two registry snapshots.
+// Entries are agent names ("weather"), capabilities ("weather/get_forecast"),
+// or changed fields ("weather.status", "weather/get_forecast.tags").
+type SnapshotDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// DiffSnapshots compares two snapshots produced by the registry-snapshot handler
+func DiffSnapshots(before, after string) (*SnapshotDiff, error) {
+	beforeAgents, err := snapshotAgents(before)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'before' snapshot: %w", err)
+	}
+	afterAgents, err := snapshotAgents(after)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'after' snapshot: %w", err)
+	}
+
+	diff := &SnapshotDiff{Added: []string{}, Removed: []string{}, Changed: []string{}}
+
+	for name, a := range afterAgents {
+		b, ok := beforeAgents[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+			for capName := range snapshotCapabilities(a) {
+				diff.Added = append(diff.Added, name+"/"+capName)
+			}
+			continue
+		}
+		diff.Changed = append(diff.Changed, changedFields(name, b, a)...)
+
+		beforeCaps := snapshotCapabilities(b)
+		afterCaps := snapshotCapabilities(a)
+		for capName, ac := range afterCaps {
+			bc, ok := beforeCaps[capName]
+			if !ok {
+				diff.Added = append(diff.Added, name+"/"+capName)
+				continue
+			}
+			diff.Changed = append(diff.Changed, changedFields(name+"/"+capName, bc, ac)...)
+		}
+		for capName := range beforeCaps {
+			if _, ok := afterCaps[capName]; !ok {
+				diff.Removed = append(diff.Removed, name+"/"+capName)
+			}
+		}
+	}
+	for name, b := range beforeAgents {
+		if _, ok := afterAgents[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+			for capName := range snapshotCapabilities(b) {
+				diff.Removed = append(diff.Removed, name+"/"+capName)
+			}
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff, nil
+}
+
+// snapshotAgents extracts the agents map from a snapshot
+func snapshotAgents(snapshot string) (map[string]map[string]any, error) {
+	var data struct {
+		Agents map[string]map[string]any `json:"agents"`
+	}
+	if err := json.Unmarshal([]byte(snapshot), &data); err != nil {
+		return nil, err
+	}
+	return data.Agents, nil
+}
+
+// snapshotCapabilities extracts the capabilities map of an agent
+func snapshotCapabilities(agent map[string]any) map[string]map[string]any {
+	result := make(map[string]map[string]any)
+	caps, _ := agent["capabilities"].(map[string]any)
+	for name, c := range caps {
+		if m, ok := c.(map[string]any); ok {
+			result[name] = m
+		}
+	}
+	return result
+}
+
+// changedFields returns "<prefix>.<field>" for each field that differs, ignoring capabilities
+func changedFields(prefix string, before, after map[string]any) []string {
+	var changed []string
+	seen := make(map[string]bool)
+	for _, m := range []map[string]any{before, after} {
+		for k := range m {
+			if k == "capabilities" || seen[k] {
+				continue
+			}
+			seen[k] = true
+			if !reflect.DeepEqual(before[k], after[k]) {
+				changed = append(changed, prefix+"."+k)
+			}
+		}
+	}
+	return changed
+}
+
+// resolveSnapshotDiff handles ${snapdiff:before:after[:added|removed|changed]}
+// where before and after are captured variable names. The result is JSON so it
+// can be checked with contains or compared against [].
+func resolveSnapshotDiff(expr string, ctx *Context) (any, error) {
+	parts := strings.Split(expr, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, nil
+	}
+
+	before, _ := ResolveVariable(parts[0], ctx)
+	after, _ := ResolveVariable(parts[1], ctx)
+	beforeStr, ok1 := before.(string)
+	afterStr, ok2 := after.(string)
+	if !ok1 || !ok2 {
+		return nil, nil
+	}
+
+	diff, err := DiffSnapshots(beforeStr, afterStr)
+	if err != nil {
+		return nil, nil
+	}
+
+	var value any = diff
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "added":
+			value = diff.Added
+		case "removed":
+			value = diff.Removed
+		case "changed":
+			value = diff.Changed
+		default:
+			return nil, nil
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, nil
+	}
+	return string(data), nil
+}
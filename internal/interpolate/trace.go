@@ -0,0 +1,108 @@
+package interpolate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxTraceValueLen caps how much of a resolved value a trace line shows
+const maxTraceValueLen = 200
+
+// secretMarkers identify variable references whose values are redacted in traces
+var secretMarkers = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "credential", "private_key", "authorization"}
+
+// Resolution describes how a single ${...} reference was resolved. It is passed
+// to Context.Tracer when tracing is enabled.
+type Resolution struct {
+	Expr   string // Variable reference without ${}
+	Source string // Where the value came from, e.g. "captured" or "jq(last.stdout)"
+	Value  any    // Resolved value (nil if unresolved)
+}
+
+// Resolved reports whether the reference produced a value
+func (r Resolution) Resolved() bool {
+	return r.Value != nil
+}
+
+// String formats the resolution for worker.log, redacting secret values
+func (r Resolution) String() string {
+	if !r.Resolved() {
+		return fmt.Sprintf("${%s} via %s -> unresolved", r.Expr, r.Source)
+	}
+	if IsSecret(r.Expr) {
+		return fmt.Sprintf("${%s} via %s -> <redacted>", r.Expr, r.Source)
+	}
+	value := fmt.Sprintf("%v", RedactSecrets(r.Value))
+	if len(value) > maxTraceValueLen {
+		value = value[:maxTraceValueLen] + "..."
+	}
+	return fmt.Sprintf("${%s} via %s -> %q", r.Expr, r.Source, value)
+}
+
+// RedactSecrets returns a copy of v with the values of secret-named map keys
+// (see IsSecret) replaced by "<redacted>", at any depth
+func RedactSecrets(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(val))
+		for k, item := range val {
+			if IsSecret(k) {
+				redacted[k] = "<redacted>"
+			} else {
+				redacted[k] = RedactSecrets(item)
+			}
+		}
+		return redacted
+	case map[string]string:
+		redacted := make(map[string]string, len(val))
+		for k, item := range val {
+			if IsSecret(k) {
+				redacted[k] = "<redacted>"
+			} else {
+				redacted[k] = item
+			}
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(val))
+		for i, item := range val {
+			redacted[i] = RedactSecrets(item)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+// RedactInterpolated masks secrets in resolved, the result of interpolating
+// text: the values of ${...} references in text named like secrets, and the
+// secret-named keys of maps references resolve to (see RedactSecrets)
+func RedactInterpolated(text, resolved string, ctx *Context) string {
+	for _, match := range varPattern.FindAllStringSubmatch(text, -1) {
+		value, _, err := resolveVariable(match[1], ctx)
+		if err != nil || value == nil {
+			continue
+		}
+		formatted := fmt.Sprintf("%v", value)
+		masked := "<redacted>"
+		if !IsSecret(match[1]) {
+			masked = fmt.Sprintf("%v", RedactSecrets(value))
+		}
+		if formatted != "" && formatted != masked {
+			resolved = strings.ReplaceAll(resolved, formatted, masked)
+		}
+	}
+	return resolved
+}
+
+// IsSecret reports whether a variable reference names a secret (password,
+// token, API key, ...) whose value must not be logged
+func IsSecret(expr string) bool {
+	lower := strings.ToLower(expr)
+	for _, marker := range secretMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
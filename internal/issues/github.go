@@ -0,0 +1,72 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// defaultGitHubAPIURL is the API of github.com
+const defaultGitHubAPIURL = "https://api.github.com"
+
+// gitHub opens issues in a GitHub repository
+type gitHub struct {
+	http    httpClient
+	repoURL string // API URL of the repository
+	labels  []string
+}
+
+func newGitHub(cfg config.GitHubIssueSettings) (*gitHub, error) {
+	if owner, name, ok := strings.Cut(cfg.Repo, "/"); !ok || owner == "" || name == "" {
+		return nil, fmt.Errorf("issues.github.repo must be owner/name, got %q", cfg.Repo)
+	}
+	tokenEnv, token := envOrDefault(cfg.TokenEnv, "GITHUB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("no GitHub token: set %s", tokenEnv)
+	}
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = defaultGitHubAPIURL
+	}
+
+	return &gitHub{
+		http: httpClient{
+			client: &http.Client{Timeout: requestTimeout},
+			authorize: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer "+token)
+				req.Header.Set("Accept", "application/vnd.github+json")
+			},
+		},
+		repoURL: strings.TrimSuffix(apiURL, "/") + "/repos/" + cfg.Repo,
+		labels:  cfg.Labels,
+	}, nil
+}
+
+func (g *gitHub) Open(ctx context.Context, title, body string) (*Issue, error) {
+	req := map[string]any{"title": title, "body": body}
+	if len(g.labels) > 0 {
+		req["labels"] = g.labels
+	}
+	var resp struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := g.http.do(ctx, http.MethodPost, g.repoURL+"/issues", req, &resp); err != nil {
+		return nil, err
+	}
+	return &Issue{Key: fmt.Sprint(resp.Number), URL: resp.HTMLURL}, nil
+}
+
+func (g *gitHub) Comment(ctx context.Context, key, body string) error {
+	return g.http.do(ctx, http.MethodPost, g.repoURL+"/issues/"+key+"/comments", map[string]string{"body": body}, nil)
+}
+
+func (g *gitHub) Close(ctx context.Context, key, body string) error {
+	if err := g.Comment(ctx, key, body); err != nil {
+		return err
+	}
+	return g.http.do(ctx, http.MethodPatch, g.repoURL+"/issues/"+key, map[string]string{"state": "closed", "state_reason": "completed"}, nil)
+}
@@ -0,0 +1,119 @@
+// Package issues opens, updates and closes issues in GitHub or Jira for
+// tests that keep failing.
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// Issue tracker providers
+const (
+	ProviderGitHub = "github"
+	ProviderJira   = "jira"
+)
+
+// DefaultAfterFailures is the number of consecutive failed runs that opens
+// an issue when issues.after_failures is not set
+const DefaultAfterFailures = 3
+
+// Issue identifies an issue in the tracker
+type Issue struct {
+	Key string // GitHub issue number or Jira issue key
+	URL string // Web page of the issue
+}
+
+// Tracker opens and updates issues
+type Tracker interface {
+	// Open opens an issue
+	Open(ctx context.Context, title, body string) (*Issue, error)
+	// Comment adds a comment to an issue
+	Comment(ctx context.Context, key, body string) error
+	// Close comments on an issue and closes it
+	Close(ctx context.Context, key, body string) error
+}
+
+// New returns the tracker configured in a suite's issues settings
+func New(cfg config.IssueSettings) (Tracker, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case ProviderGitHub:
+		g, err := newGitHub(cfg.GitHub)
+		if err != nil {
+			return nil, err
+		}
+		return g, nil
+	case ProviderJira:
+		j, err := newJira(cfg.Jira)
+		if err != nil {
+			return nil, err
+		}
+		return j, nil
+	default:
+		return nil, fmt.Errorf("unknown issues.provider %q: must be github or jira", cfg.Provider)
+	}
+}
+
+// requestTimeout bounds each call to the tracker's API
+const requestTimeout = 30 * time.Second
+
+// httpClient sends JSON requests to a tracker's REST API
+type httpClient struct {
+	client    *http.Client
+	authorize func(req *http.Request)
+}
+
+// do sends body (if not nil) as JSON and decodes the response into out (if
+// not nil). Non-2xx responses are errors carrying the start of the body.
+func (h *httpClient) do(ctx context.Context, method, url string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	h.authorize(req)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s - %s", method, url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// envOrDefault reads the environment variable named by name, or by fallback
+// if name is empty
+func envOrDefault(name, fallback string) (string, string) {
+	if name == "" {
+		name = fallback
+	}
+	return name, os.Getenv(name)
+}
@@ -0,0 +1,100 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// jira opens issues in a Jira project (REST API v2, which takes plain text)
+type jira struct {
+	http            httpClient
+	baseURL         string
+	project         string
+	issueType       string
+	closeTransition string
+	labels          []string
+}
+
+func newJira(cfg config.JiraIssueSettings) (*jira, error) {
+	if cfg.URL == "" || cfg.Project == "" {
+		return nil, fmt.Errorf("issues.jira needs url and project")
+	}
+	emailEnv, email := envOrDefault(cfg.EmailEnv, "JIRA_EMAIL")
+	tokenEnv, token := envOrDefault(cfg.TokenEnv, "JIRA_API_TOKEN")
+	if email == "" || token == "" {
+		return nil, fmt.Errorf("no Jira credentials: set %s and %s", emailEnv, tokenEnv)
+	}
+
+	j := &jira{
+		http: httpClient{
+			client: &http.Client{Timeout: requestTimeout},
+			authorize: func(req *http.Request) {
+				req.SetBasicAuth(email, token)
+			},
+		},
+		baseURL:         strings.TrimSuffix(cfg.URL, "/"),
+		project:         cfg.Project,
+		issueType:       cfg.IssueType,
+		closeTransition: cfg.CloseTransition,
+		labels:          cfg.Labels,
+	}
+	if j.issueType == "" {
+		j.issueType = "Bug"
+	}
+	if j.closeTransition == "" {
+		j.closeTransition = "Done"
+	}
+	return j, nil
+}
+
+func (j *jira) Open(ctx context.Context, title, body string) (*Issue, error) {
+	fields := map[string]any{
+		"project":     map[string]string{"key": j.project},
+		"issuetype":   map[string]string{"name": j.issueType},
+		"summary":     title,
+		"description": body,
+	}
+	if len(j.labels) > 0 {
+		fields["labels"] = j.labels
+	}
+	var resp struct {
+		Key string `json:"key"`
+	}
+	if err := j.http.do(ctx, http.MethodPost, j.baseURL+"/rest/api/2/issue", map[string]any{"fields": fields}, &resp); err != nil {
+		return nil, err
+	}
+	return &Issue{Key: resp.Key, URL: j.baseURL + "/browse/" + resp.Key}, nil
+}
+
+func (j *jira) Comment(ctx context.Context, key, body string) error {
+	return j.http.do(ctx, http.MethodPost, j.baseURL+"/rest/api/2/issue/"+key+"/comment", map[string]string{"body": body}, nil)
+}
+
+// Close comments on the issue and applies the close transition, found by
+// name among the transitions the issue currently allows
+func (j *jira) Close(ctx context.Context, key, body string) error {
+	if err := j.Comment(ctx, key, body); err != nil {
+		return err
+	}
+
+	var resp struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	url := j.baseURL + "/rest/api/2/issue/" + key + "/transitions"
+	if err := j.http.do(ctx, http.MethodGet, url, nil, &resp); err != nil {
+		return err
+	}
+	for _, t := range resp.Transitions {
+		if strings.EqualFold(t.Name, j.closeTransition) {
+			return j.http.do(ctx, http.MethodPost, url, map[string]any{"transition": map[string]string{"id": t.ID}}, nil)
+		}
+	}
+	return fmt.Errorf("issue %s has no %q transition", key, j.closeTransition)
+}
@@ -0,0 +1,474 @@
+// Package jsonschema validates JSON values against JSON Schema documents.
+//
+// It implements the validation keywords of drafts 7 and 2020-12 that
+// describe structure: type, enum, const, the numeric, string, array and
+// object bounds, properties and items (both the tuple forms), the
+// combinators (allOf, anyOf, oneOf, not, if/then/else) and $ref to "#"
+// JSON pointers within the same document. format is an annotation and is
+// not checked; remote $refs are reported as violations.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxRefDepth bounds $ref resolution so recursive schemas can't loop
+const maxRefDepth = 64
+
+// Schema is a parsed JSON Schema document
+type Schema struct {
+	root any
+}
+
+// Violation is a value not matching its schema
+type Violation struct {
+	Path    string // JSON path of the value, e.g. $.result.tools[0].name
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Load reads a JSON Schema document from a file
+func Load(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses a JSON Schema document
+func Parse(data []byte) (*Schema, error) {
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	switch root.(type) {
+	case map[string]any, bool:
+		return &Schema{root: root}, nil
+	}
+	return nil, fmt.Errorf("invalid schema: must be an object or a boolean")
+}
+
+// Validate returns every violation of the schema by a decoded JSON value,
+// in document order; none when the value is valid
+func (s *Schema) Validate(value any) []Violation {
+	v := &validator{root: s.root}
+	v.validate(s.root, value, "$", 0)
+	return v.violations
+}
+
+type validator struct {
+	root       any
+	violations []Violation
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// valid reports whether value matches a subschema, without recording its
+// violations
+func (v *validator) valid(schema, value any, path string, depth int) bool {
+	sub := &validator{root: v.root}
+	sub.validate(schema, value, path, depth)
+	return len(sub.violations) == 0
+}
+
+func (v *validator) validate(schema, value any, path string, depth int) {
+	s, ok := schema.(map[string]any)
+	if !ok {
+		if b, ok := schema.(bool); ok && !b {
+			v.fail(path, "no value is allowed here")
+		}
+		return
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		target, err := v.resolve(ref)
+		switch {
+		case err != nil:
+			v.fail(path, "%v", err)
+		case depth >= maxRefDepth:
+			v.fail(path, "$ref %s nested too deeply", ref)
+		default:
+			v.validate(target, value, path, depth+1)
+		}
+	}
+
+	if t, ok := s["type"]; ok && !matchesType(t, value) {
+		v.fail(path, "expected %s, got %s", typeNames(t), typeOf(value))
+	}
+	if enum, ok := s["enum"].([]any); ok && !containsValue(enum, value) {
+		v.fail(path, "%s is not one of %s", jsonText(value), jsonText(enum))
+	}
+	if c, ok := s["const"]; ok && !reflect.DeepEqual(c, value) {
+		v.fail(path, "expected %s, got %s", jsonText(c), jsonText(value))
+	}
+
+	switch val := value.(type) {
+	case string:
+		v.validateString(s, val, path)
+	case float64:
+		v.validateNumber(s, val, path)
+	case []any:
+		v.validateArray(s, val, path, depth)
+	case map[string]any:
+		v.validateObject(s, val, path, depth)
+	}
+
+	v.validateCombinators(s, value, path, depth)
+}
+
+func (v *validator) validateString(s map[string]any, val, path string) {
+	length := utf8.RuneCountInString(val)
+	if n, ok := number(s["minLength"]); ok && float64(length) < n {
+		v.fail(path, "string is shorter than %v characters", n)
+	}
+	if n, ok := number(s["maxLength"]); ok && float64(length) > n {
+		v.fail(path, "string is longer than %v characters", n)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.fail(path, "invalid pattern %q in schema: %v", pattern, err)
+		} else if !re.MatchString(val) {
+			v.fail(path, "%s does not match pattern %q", jsonText(val), pattern)
+		}
+	}
+}
+
+func (v *validator) validateNumber(s map[string]any, val float64, path string) {
+	if n, ok := number(s["minimum"]); ok && val < n {
+		v.fail(path, "%v is less than the minimum %v", val, n)
+	}
+	if n, ok := number(s["maximum"]); ok && val > n {
+		v.fail(path, "%v is greater than the maximum %v", val, n)
+	}
+	// Draft 4 used booleans modifying minimum and maximum
+	if n, ok := number(s["exclusiveMinimum"]); ok && val <= n {
+		v.fail(path, "%v is not greater than %v", val, n)
+	} else if b, _ := s["exclusiveMinimum"].(bool); b {
+		if n, ok := number(s["minimum"]); ok && val == n {
+			v.fail(path, "%v is not greater than %v", val, n)
+		}
+	}
+	if n, ok := number(s["exclusiveMaximum"]); ok && val >= n {
+		v.fail(path, "%v is not less than %v", val, n)
+	} else if b, _ := s["exclusiveMaximum"].(bool); b {
+		if n, ok := number(s["maximum"]); ok && val == n {
+			v.fail(path, "%v is not less than %v", val, n)
+		}
+	}
+	if n, ok := number(s["multipleOf"]); ok && n > 0 {
+		if q := val / n; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(path, "%v is not a multiple of %v", val, n)
+		}
+	}
+}
+
+func (v *validator) validateArray(s map[string]any, val []any, path string, depth int) {
+	if n, ok := number(s["minItems"]); ok && float64(len(val)) < n {
+		v.fail(path, "array has %d items, fewer than %v", len(val), n)
+	}
+	if n, ok := number(s["maxItems"]); ok && float64(len(val)) > n {
+		v.fail(path, "array has %d items, more than %v", len(val), n)
+	}
+	if unique, _ := s["uniqueItems"].(bool); unique {
+		for i := 1; i < len(val); i++ {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(val[i], val[j]) {
+					v.fail(path, "items %d and %d are equal", j, i)
+				}
+			}
+		}
+	}
+
+	// Leading items checked by position: prefixItems (2020-12) or an items
+	// array (draft 7); the rest by items or additionalItems
+	prefix, rest := s["prefixItems"], s["items"]
+	if tuple, ok := rest.([]any); ok {
+		prefix, rest = tuple, s["additionalItems"]
+	}
+	tuple, _ := prefix.([]any)
+	for i, item := range val {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i < len(tuple):
+			v.validate(tuple[i], item, itemPath, depth)
+		case rest != nil:
+			v.validate(rest, item, itemPath, depth)
+		}
+	}
+
+	if contains, ok := s["contains"]; ok {
+		matches := 0
+		for i, item := range val {
+			if v.valid(contains, item, path+"["+strconv.Itoa(i)+"]", depth) {
+				matches++
+			}
+		}
+		minContains := 1.0
+		if n, ok := number(s["minContains"]); ok {
+			minContains = n
+		}
+		if float64(matches) < minContains {
+			v.fail(path, "array has %d items matching contains, fewer than %v", matches, minContains)
+		}
+		if n, ok := number(s["maxContains"]); ok && float64(matches) > n {
+			v.fail(path, "array has %d items matching contains, more than %v", matches, n)
+		}
+	}
+}
+
+func (v *validator) validateObject(s map[string]any, val map[string]any, path string, depth int) {
+	if n, ok := number(s["minProperties"]); ok && float64(len(val)) < n {
+		v.fail(path, "object has %d properties, fewer than %v", len(val), n)
+	}
+	if n, ok := number(s["maxProperties"]); ok && float64(len(val)) > n {
+		v.fail(path, "object has %d properties, more than %v", len(val), n)
+	}
+	if required, ok := s["required"].([]any); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, ok := val[key]; !ok {
+					v.fail(path, "missing required property %q", key)
+				}
+			}
+		}
+	}
+	if deps, ok := s["dependentRequired"].(map[string]any); ok {
+		for _, key := range sortedKeys(deps) {
+			if _, ok := val[key]; !ok {
+				continue
+			}
+			names, _ := deps[key].([]any)
+			for _, name := range names {
+				if dep, ok := name.(string); ok {
+					if _, ok := val[dep]; !ok {
+						v.fail(path, "property %q requires property %q", key, dep)
+					}
+				}
+			}
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]any)
+	patterns, _ := s["patternProperties"].(map[string]any)
+	additional, hasAdditional := s["additionalProperties"]
+	names, hasNames := s["propertyNames"]
+	for _, key := range sortedKeys(val) {
+		propPath := propertyPath(path, key)
+		if hasNames && !v.valid(names, key, propPath, depth) {
+			v.fail(propPath, "property name %q is not allowed by propertyNames", key)
+		}
+
+		matched := false
+		if prop, ok := properties[key]; ok {
+			matched = true
+			v.validate(prop, val[key], propPath, depth)
+		}
+		for _, pattern := range sortedKeys(patterns) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				v.fail(path, "invalid patternProperties pattern %q in schema: %v", pattern, err)
+				continue
+			}
+			if re.MatchString(key) {
+				matched = true
+				v.validate(patterns[pattern], val[key], propPath, depth)
+			}
+		}
+		if !matched && hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				v.fail(propPath, "additional property %q is not allowed", key)
+			} else {
+				v.validate(additional, val[key], propPath, depth)
+			}
+		}
+	}
+}
+
+func (v *validator) validateCombinators(s map[string]any, value any, path string, depth int) {
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			v.validate(sub, value, path, depth)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if v.valid(sub, value, path, depth) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "does not match any of the %d anyOf schemas", len(anyOf))
+		}
+	}
+	if one, ok := s["oneOf"].([]any); ok {
+		matches := 0
+		for _, sub := range one {
+			if v.valid(sub, value, path, depth) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			v.fail(path, "matches %d of the %d oneOf schemas, expected exactly 1", matches, len(one))
+		}
+	}
+	if not, ok := s["not"]; ok && v.valid(not, value, path, depth) {
+		v.fail(path, "must not match the not schema")
+	}
+	if cond, ok := s["if"]; ok {
+		if v.valid(cond, value, path, depth) {
+			if then, ok := s["then"]; ok {
+				v.validate(then, value, path, depth)
+			}
+		} else if els, ok := s["else"]; ok {
+			v.validate(els, value, path, depth)
+		}
+	}
+}
+
+// resolve returns the subschema a $ref points to; only references into
+// the same document ("#" and "#/json/pointer") are supported
+func (v *validator) resolve(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q: only references within the schema (#/...) are resolved", ref)
+	}
+	target := v.root
+	if pointer == "" {
+		return target, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch node := target.(type) {
+		case map[string]any:
+			target, ok = node[token]
+		case []any:
+			i, err := strconv.Atoi(token)
+			ok = err == nil && i >= 0 && i < len(node)
+			if ok {
+				target = node[i]
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			return nil, fmt.Errorf("$ref %q not found in schema", ref)
+		}
+	}
+	return target, nil
+}
+
+// matchesType reports whether value has the type, or one of the types, of
+// a type keyword
+func matchesType(t, value any) bool {
+	types, ok := t.([]any)
+	if !ok {
+		types = []any{t}
+	}
+	actual := typeOf(value)
+	for _, t := range types {
+		switch t {
+		case actual:
+			return true
+		case "number":
+			if actual == "integer" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type of a decoded JSON value; whole
+// numbers are integers
+func typeOf(value any) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) && !math.IsInf(val, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func typeNames(t any) string {
+	types, ok := t.([]any)
+	if !ok {
+		return fmt.Sprint(t)
+	}
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = fmt.Sprint(t)
+	}
+	return strings.Join(names, " or ")
+}
+
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func number(v any) (float64, bool) {
+	n, ok := v.(float64)
+	return n, ok
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// propertyPath appends a property to a JSON path, quoting names that
+// aren't plain identifiers
+func propertyPath(path, key string) string {
+	if key != "" && strings.IndexFunc(key, func(r rune) bool {
+		return !(r == '_' || r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}) < 0 {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+func jsonText(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,119 @@
+// Package logging configures structured (slog) diagnostics for the tsuite
+// CLI, runner and API server.
+//
+// Diagnostics go to stderr so they never mix with output other tools parse
+// (test results, runner result lines). The level and format are inherited by
+// child processes through TSUITE_LOG_LEVEL and TSUITE_LOG_FORMAT.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Environment variables that carry the log settings to child processes
+const (
+	EnvLevel  = "TSUITE_LOG_LEVEL"
+	EnvFormat = "TSUITE_LOG_FORMAT"
+)
+
+// Log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options selects the log level and format. Empty fields fall back to the
+// environment, then to info/text.
+type Options struct {
+	Level  string // debug, info, warn or error
+	Format string // text or json
+}
+
+// ParseLevel converts a level name to a slog level
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level: %s (must be debug, info, warn or error)", level)
+	}
+}
+
+// New creates a logger writing to w whose records carry component=<component>
+func New(w io.Writer, component string, opts Options) (*slog.Logger, error) {
+	opts = opts.withDefaults()
+
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	handlerOpts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch opts.Format {
+	case FormatText:
+		handler = slog.NewTextHandler(w, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		return nil, fmt.Errorf("invalid log format: %s (must be %s or %s)", opts.Format, FormatText, FormatJSON)
+	}
+
+	return slog.New(handler).With("component", component), nil
+}
+
+// Setup installs a stderr logger for component as the slog default and
+// exports the settings so child processes log the same way
+func Setup(component string, opts Options) error {
+	opts = opts.withDefaults()
+
+	logger, err := New(os.Stderr, component, opts)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+
+	os.Setenv(EnvLevel, opts.Level)
+	os.Setenv(EnvFormat, opts.Format)
+	return nil
+}
+
+// Env returns KEY=value pairs that pass the current settings to a process
+// that does not inherit this environment (e.g. a container)
+func Env() []string {
+	var env []string
+	for _, key := range []string{EnvLevel, EnvFormat} {
+		if value := os.Getenv(key); value != "" {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+func (o Options) withDefaults() Options {
+	if o.Level == "" {
+		o.Level = os.Getenv(EnvLevel)
+	}
+	if o.Level == "" {
+		o.Level = "info"
+	}
+	if o.Format == "" {
+		o.Format = os.Getenv(EnvFormat)
+	}
+	if o.Format == "" {
+		o.Format = FormatText
+	}
+	o.Level = strings.ToLower(o.Level)
+	o.Format = strings.ToLower(o.Format)
+	return o
+}
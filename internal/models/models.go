@@ -8,10 +8,20 @@ import (
 	"time"
 )
 
+// SchemaVersion is the version of the CLI/runner <-> API payload schema.
+// Bump it when a change makes old clients and new servers (or vice versa)
+// misread each other's payloads, and raise MinSchemaVersion when the server
+// drops support for older clients.
+const SchemaVersion = 1
+
+// MinSchemaVersion is the oldest client schema version the API server accepts
+const MinSchemaVersion = 1
+
 // RunStatus represents the status of a test run
 type RunStatus string
 
 const (
+	RunStatusQueued    RunStatus = "queued" // Waiting in the API server's run queue
 	RunStatusPending   RunStatus = "pending"
 	RunStatusRunning   RunStatus = "running"
 	RunStatusCompleted RunStatus = "completed"
@@ -19,6 +29,11 @@ const (
 	RunStatusCancelled RunStatus = "cancelled"
 )
 
+// IsTerminal returns true if the run has finished (status won't change further)
+func (s RunStatus) IsTerminal() bool {
+	return s == RunStatusCompleted || s == RunStatusFailed || s == RunStatusCancelled
+}
+
 // TestStatus represents the status of a test case
 type TestStatus string
 
@@ -111,6 +126,14 @@ type Run struct {
 	FiltersJSON          any            `json:"filters,omitempty"`
 	Mode                 string         `json:"mode"`
 	CancelRequested      bool           `json:"cancel_requested"`
+	ParentRunID          sql.NullString `json:"parent_run_id,omitempty"`   // Run this run is a rerun of
+	PipelineRunID        sql.NullString `json:"pipeline_run_id,omitempty"` // Pipeline run this run is part of
+	RunnerVersion        sql.NullString `json:"runner_version,omitempty"`  // tsuite-runner version the run executed with
+	RunnerSHA256         sql.NullString `json:"runner_sha256,omitempty"`   // SHA-256 of that runner binary
+	Workers              sql.NullInt64  `json:"workers,omitempty"`         // Parallel test runners the run used
+	SmokeStatus          sql.NullString `json:"smoke_status,omitempty"`    // passed, failed or over_budget (tsuite run --smoke)
+	SmokeBudgetMS        sql.NullInt64  `json:"smoke_budget_ms,omitempty"` // Time budget of a smoke run
+	Profile              sql.NullString `json:"profile,omitempty"`         // Run profile from config.yaml (tsuite run --profile)
 }
 
 // MarshalJSON customizes JSON output for Run
@@ -125,7 +148,7 @@ func (r Run) MarshalJSON() ([]byte, error) {
 		"suite_id":               nullInt64ToAny(r.SuiteID),
 		"suite_name":             nullStringToAny(r.SuiteName),
 		"display_name":           nullStringToAny(r.DisplayName),
-		"started_at":             r.StartedAt.Format(time.RFC3339),
+		"started_at":             r.StartedAt.UTC().Format(time.RFC3339),
 		"finished_at":            timeToAny(r.FinishedAt),
 		"status":                 r.Status,
 		"cli_version":            nullStringToAny(r.CLIVersion),
@@ -142,30 +165,54 @@ func (r Run) MarshalJSON() ([]byte, error) {
 		"filters":                filters,
 		"mode":                   r.Mode,
 		"cancel_requested":       r.CancelRequested,
+		"parent_run_id":          nullStringToAny(r.ParentRunID),
+		"pipeline_run_id":        nullStringToAny(r.PipelineRunID),
+		"runner_version":         nullStringToAny(r.RunnerVersion),
+		"runner_sha256":          nullStringToAny(r.RunnerSHA256),
+		"workers":                nullInt64ToAny(r.Workers),
+		"smoke_status":           nullStringToAny(r.SmokeStatus),
+		"smoke_budget_ms":        nullInt64ToAny(r.SmokeBudgetMS),
+		"profile":                nullStringToAny(r.Profile),
 	})
 }
 
 // TestResult represents a test case result
 type TestResult struct {
-	ID           int64          `json:"id"`
-	RunID        string         `json:"run_id"`
-	TestID       string         `json:"test_id"`
-	UseCase      string         `json:"use_case"`
-	TestCase     string         `json:"test_case"`
-	Name         sql.NullString `json:"name,omitempty"`
-	Tags         sql.NullString `json:"-"`
-	TagsList     []string       `json:"tags"`
-	Status       TestStatus     `json:"status"`
-	StartedAt    *time.Time     `json:"started_at,omitempty"`
-	FinishedAt   *time.Time     `json:"finished_at,omitempty"`
-	DurationMS   sql.NullInt64  `json:"duration_ms,omitempty"`
-	ErrorMessage sql.NullString `json:"error_message,omitempty"`
-	ErrorStep    sql.NullInt64  `json:"error_step,omitempty"`
-	SkipReason   sql.NullString `json:"skip_reason,omitempty"`
-	StepsJSON    sql.NullString `json:"-"`
-	Steps        any            `json:"steps,omitempty"`
-	StepsPassed  int            `json:"steps_passed"`
-	StepsFailed  int            `json:"steps_failed"`
+	ID               int64          `json:"id"`
+	RunID            string         `json:"run_id"`
+	TestID           string         `json:"test_id"`
+	UseCase          string         `json:"use_case"`
+	TestCase         string         `json:"test_case"`
+	Name             sql.NullString `json:"name,omitempty"`
+	Tags             sql.NullString `json:"-"`
+	TagsList         []string       `json:"tags"`
+	Covers           sql.NullString `json:"-"` // JSON array of feature IDs from covers: in test.yaml
+	Owners           sql.NullString `json:"-"` // JSON array of owners from test.yaml or config.yaml
+	Status           TestStatus     `json:"status"`
+	StartedAt        *time.Time     `json:"started_at,omitempty"`
+	FinishedAt       *time.Time     `json:"finished_at,omitempty"`
+	DurationMS       sql.NullInt64  `json:"duration_ms,omitempty"`
+	ErrorMessage     sql.NullString `json:"error_message,omitempty"`
+	ErrorStep        sql.NullInt64  `json:"error_step,omitempty"`
+	SkipReason       sql.NullString `json:"skip_reason,omitempty"`
+	StepsJSON        sql.NullString `json:"-"`
+	Steps            any            `json:"steps,omitempty"`
+	StepsPassed      int            `json:"steps_passed"`
+	StepsFailed      int            `json:"steps_failed"`
+	Suggestions      sql.NullString `json:"-"`                            // JSON array of suggested fixes
+	Leaks            sql.NullString `json:"-"`                            // JSON object of resources left behind
+	DurationBudgetMS sql.NullInt64  `json:"duration_budget_ms,omitempty"` // duration_budget_ms from test.yaml
+	Environment      sql.NullString `json:"-"`                            // JSON object describing where the test ran
+	Container        sql.NullString `json:"-"`                            // JSON object describing the test's container (docker mode)
+	WorkspacePath    sql.NullString `json:"-"`                            // Kept workdir of a failed test
+	QueuedAt         *time.Time     `json:"queued_at,omitempty"`          // When the test was queued for a worker
+	QueueWaitMS      sql.NullInt64  `json:"queue_wait_ms,omitempty"`      // Time from queued_at until it started
+	CancelRequested  bool           `json:"cancel_requested"`             // Cancelled from the dashboard
+}
+
+// BudgetExceeded reports whether the test ran longer than its duration budget
+func (t *TestResult) BudgetExceeded() bool {
+	return t.DurationBudgetMS.Valid && t.DurationMS.Valid && t.DurationMS.Int64 > t.DurationBudgetMS.Int64
 }
 
 // MarshalJSON customizes JSON output for TestResult
@@ -175,29 +222,71 @@ func (t TestResult) MarshalJSON() ([]byte, error) {
 		_ = json.Unmarshal([]byte(t.Tags.String), &tags)
 	}
 
+	var covers []string
+	if t.Covers.Valid && t.Covers.String != "" {
+		_ = json.Unmarshal([]byte(t.Covers.String), &covers)
+	}
+
+	var owners []string
+	if t.Owners.Valid && t.Owners.String != "" {
+		_ = json.Unmarshal([]byte(t.Owners.String), &owners)
+	}
+
 	var steps any
 	if t.StepsJSON.Valid && t.StepsJSON.String != "" {
 		_ = json.Unmarshal([]byte(t.StepsJSON.String), &steps)
 	}
 
+	var suggestions []string
+	if t.Suggestions.Valid && t.Suggestions.String != "" {
+		_ = json.Unmarshal([]byte(t.Suggestions.String), &suggestions)
+	}
+
+	var leaks any
+	if t.Leaks.Valid && t.Leaks.String != "" {
+		_ = json.Unmarshal([]byte(t.Leaks.String), &leaks)
+	}
+
+	var environment any
+	if t.Environment.Valid && t.Environment.String != "" {
+		_ = json.Unmarshal([]byte(t.Environment.String), &environment)
+	}
+
+	var container any
+	if t.Container.Valid && t.Container.String != "" {
+		_ = json.Unmarshal([]byte(t.Container.String), &container)
+	}
+
 	return json.Marshal(map[string]any{
-		"id":            t.ID,
-		"run_id":        t.RunID,
-		"test_id":       t.TestID,
-		"use_case":      t.UseCase,
-		"test_case":     t.TestCase,
-		"name":          nullStringToAny(t.Name),
-		"tags":          tags,
-		"status":        t.Status,
-		"started_at":    timeToAny(t.StartedAt),
-		"finished_at":   timeToAny(t.FinishedAt),
-		"duration_ms":   nullInt64ToAny(t.DurationMS),
-		"error_message": nullStringToAny(t.ErrorMessage),
-		"error_step":    nullInt64ToAny(t.ErrorStep),
-		"skip_reason":   nullStringToAny(t.SkipReason),
-		"steps":         steps,
-		"steps_passed":  t.StepsPassed,
-		"steps_failed":  t.StepsFailed,
+		"id":                 t.ID,
+		"run_id":             t.RunID,
+		"test_id":            t.TestID,
+		"use_case":           t.UseCase,
+		"test_case":          t.TestCase,
+		"name":               nullStringToAny(t.Name),
+		"tags":               tags,
+		"covers":             covers,
+		"owners":             owners,
+		"status":             t.Status,
+		"started_at":         timeToAny(t.StartedAt),
+		"finished_at":        timeToAny(t.FinishedAt),
+		"duration_ms":        nullInt64ToAny(t.DurationMS),
+		"error_message":      nullStringToAny(t.ErrorMessage),
+		"error_step":         nullInt64ToAny(t.ErrorStep),
+		"skip_reason":        nullStringToAny(t.SkipReason),
+		"steps":              steps,
+		"steps_passed":       t.StepsPassed,
+		"steps_failed":       t.StepsFailed,
+		"suggestions":        suggestions,
+		"leaks":              leaks,
+		"duration_budget_ms": nullInt64ToAny(t.DurationBudgetMS),
+		"budget_exceeded":    t.BudgetExceeded(),
+		"environment":        environment,
+		"container":          container,
+		"workspace_path":     nullStringToAny(t.WorkspacePath),
+		"queued_at":          timeToAny(t.QueuedAt),
+		"queue_wait_ms":      nullInt64ToAny(t.QueueWaitMS),
+		"cancel_requested":   t.CancelRequested,
 	})
 }
 
@@ -217,10 +306,43 @@ type StepResult struct {
 	Stdout       sql.NullString `json:"stdout,omitempty"`
 	Stderr       sql.NullString `json:"stderr,omitempty"`
 	ErrorMessage sql.NullString `json:"error_message,omitempty"`
+	Artifacts    sql.NullString `json:"-"` // JSON array of paths relative to the test's artifact dir
+
+	// What ran after interpolation: the shell command and the other handler
+	// fields (or routine params) as a JSON object
+	ResolvedCommand sql.NullString `json:"resolved_command,omitempty"`
+	ResolvedParams  sql.NullString `json:"-"`
+
+	// URL prefix artifact paths are served under; set by the API before rendering
+	ArtifactBaseURL string `json:"-"`
+}
+
+// StepArtifact is a collected step artifact and the URL it is served at
+type StepArtifact struct {
+	Path string `json:"path"`
+	URL  string `json:"url,omitempty"`
 }
 
 // MarshalJSON customizes JSON output for StepResult
 func (s StepResult) MarshalJSON() ([]byte, error) {
+	var artifacts []StepArtifact
+	if s.Artifacts.Valid && s.Artifacts.String != "" {
+		var paths []string
+		_ = json.Unmarshal([]byte(s.Artifacts.String), &paths)
+		for _, p := range paths {
+			a := StepArtifact{Path: p}
+			if s.ArtifactBaseURL != "" {
+				a.URL = s.ArtifactBaseURL + p
+			}
+			artifacts = append(artifacts, a)
+		}
+	}
+
+	var resolvedParams map[string]any
+	if s.ResolvedParams.Valid && s.ResolvedParams.String != "" {
+		_ = json.Unmarshal([]byte(s.ResolvedParams.String), &resolvedParams)
+	}
+
 	return json.Marshal(map[string]any{
 		"id":             s.ID,
 		"test_result_id": s.TestResultID,
@@ -236,6 +358,10 @@ func (s StepResult) MarshalJSON() ([]byte, error) {
 		"stdout":         nullStringToAny(s.Stdout),
 		"stderr":         nullStringToAny(s.Stderr),
 		"error_message":  nullStringToAny(s.ErrorMessage),
+		"artifacts":      artifacts,
+
+		"resolved_command": nullStringToAny(s.ResolvedCommand),
+		"resolved_params":  resolvedParams,
 	})
 }
 
@@ -249,6 +375,7 @@ type AssertionResult struct {
 	Passed         bool           `json:"passed"`
 	ActualValue    sql.NullString `json:"actual_value,omitempty"`
 	ExpectedValue  sql.NullString `json:"expected_value,omitempty"`
+	Diff           sql.NullString `json:"diff,omitempty"` // How the actual value differs from the expected one
 }
 
 // MarshalJSON customizes JSON output for AssertionResult
@@ -262,6 +389,7 @@ func (a AssertionResult) MarshalJSON() ([]byte, error) {
 		"passed":          a.Passed,
 		"actual_value":    nullStringToAny(a.ActualValue),
 		"expected_value":  nullStringToAny(a.ExpectedValue),
+		"diff":            nullStringToAny(a.Diff),
 	})
 }
 
@@ -274,6 +402,71 @@ type CapturedValue struct {
 	CapturedAt   *time.Time     `json:"captured_at,omitempty"`
 }
 
+// Audit actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditEntry records a change to a suite file made through the API
+type AuditEntry struct {
+	ID        int64      `json:"id"`
+	SuiteID   *int64     `json:"suite_id"` // nil once the suite is removed
+	Path      string     `json:"path"`     // Relative to the suite folder
+	Action    string     `json:"action"`   // create, update or delete
+	Endpoint  string     `json:"endpoint"` // e.g. "PUT /api/suites/:id/config"
+	Actor     string     `json:"actor"`    // X-Tsuite-User header or client IP
+	Diff      string     `json:"diff"`     // Unified diff of the file
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+// Comment is a note left on a run, or on one of its tests
+type Comment struct {
+	ID        int64      `json:"id"`
+	RunID     string     `json:"run_id"`
+	TestID    string     `json:"test_id,omitempty"` // Empty for comments on the run
+	Author    string     `json:"author"`
+	Text      string     `json:"text"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+// TestIssue is an issue opened in a suite's issue tracker for a test that
+// kept failing
+type TestIssue struct {
+	ID          int64      `json:"id"`
+	SuiteID     int64      `json:"suite_id"`
+	TestID      string     `json:"test_id"`
+	Provider    string     `json:"provider"`  // github or jira
+	IssueKey    string     `json:"issue_key"` // GitHub issue number or Jira key
+	URL         string     `json:"url"`
+	OpenedRunID string     `json:"opened_run_id"`
+	OpenedAt    *time.Time `json:"opened_at"`
+	ClosedRunID string     `json:"closed_run_id,omitempty"` // Run the test passed again in
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+// Pipeline execution modes
+const (
+	PipelineModeSequential = "sequential"
+	PipelineModeParallel   = "parallel"
+)
+
+// PipelineRun groups the runs of several suites executed as one unit. Its
+// status uses the run statuses: completed only if every suite run completed.
+type PipelineRun struct {
+	PipelineRunID   string     `json:"pipeline_run_id"`
+	Name            string     `json:"name"`
+	FilePath        string     `json:"file_path,omitempty"` // Pipeline file it was run from
+	Mode            string     `json:"mode"`                // sequential or parallel
+	Status          RunStatus  `json:"status"`
+	TotalSuites     int        `json:"total_suites"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+	DurationMS      *int64     `json:"duration_ms"`
+	CancelRequested bool       `json:"cancel_requested"`
+}
+
 // Helper functions for JSON marshaling
 
 func nullStringToAny(ns sql.NullString) any {
@@ -292,7 +485,7 @@ func nullInt64ToAny(ni sql.NullInt64) any {
 
 func timeToAny(t *time.Time) any {
 	if t != nil {
-		return t.Format(time.RFC3339)
+		return t.UTC().Format(time.RFC3339)
 	}
 	return nil
 }
@@ -0,0 +1,164 @@
+package orchestrator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// budgetPollInterval is how often a test held back by the resource budget
+// checks it again
+const budgetPollInterval = 500 * time.Millisecond
+
+// resourceBudget holds back test starts of a parallel run while another test
+// would exceed execution.budget, lowering parallelism until tests finish
+type resourceBudget struct {
+	cfg config.ResourceBudget
+	out io.Writer
+
+	mu      sync.Mutex
+	running int
+	holding string // Limit tests are held back by, printed when it changes
+}
+
+// newResourceBudget returns nil if the suite sets no budget
+func newResourceBudget(cfg config.ResourceBudget, out io.Writer) *resourceBudget {
+	if cfg.MaxProcesses <= 0 && cfg.MemoryPerTestMB <= 0 {
+		return nil
+	}
+	return &resourceBudget{cfg: cfg, out: out}
+}
+
+// maxTests is the number of tests max_memory_mb fits (0 = not limited)
+func (b *resourceBudget) maxTests() int {
+	if b == nil || b.cfg.MemoryPerTestMB <= 0 || b.cfg.MaxMemoryMB <= 0 {
+		return 0
+	}
+	return max(1, b.cfg.MaxMemoryMB/b.cfg.MemoryPerTestMB)
+}
+
+// acquire waits until one more test fits in the budget. A test always starts
+// when none are running, so a budget smaller than one test runs tests one at
+// a time instead of never.
+func (b *resourceBudget) acquire(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		limit, detail := "", ""
+		if b.running > 0 {
+			limit, detail = b.exceeded()
+		}
+		if limit == "" {
+			b.running++
+			b.holding = ""
+			b.mu.Unlock()
+			return nil
+		}
+		if limit != b.holding {
+			fmt.Fprintf(b.out, "[WAIT] %d test(s) running, holding back more: %s\n", b.running, detail)
+			b.holding = limit
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(budgetPollInterval):
+		}
+	}
+}
+
+// release frees the budget of a finished test
+func (b *resourceBudget) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.running--
+	b.mu.Unlock()
+}
+
+// exceeded returns the limit another test would exceed, and a description
+func (b *resourceBudget) exceeded() (limit, detail string) {
+	if per := b.cfg.MemoryPerTestMB; per > 0 {
+		if b.cfg.MaxMemoryMB > 0 {
+			if (b.running+1)*per > b.cfg.MaxMemoryMB {
+				return "max_memory_mb", fmt.Sprintf("%d x %d MB would exceed max_memory_mb %d", b.running+1, per, b.cfg.MaxMemoryMB)
+			}
+		} else if avail, ok := memAvailableMB(); ok && avail < per {
+			return "memory_available", fmt.Sprintf("%d MB available, memory_per_test_mb is %d", avail, per)
+		}
+	}
+	if b.cfg.MaxProcesses > 0 {
+		if n, err := countDescendants(os.Getpid()); err == nil && n >= b.cfg.MaxProcesses {
+			return "max_processes", fmt.Sprintf("%d processes running, max_processes is %d", n, b.cfg.MaxProcesses)
+		}
+	}
+	return "", ""
+}
+
+// memAvailableMB returns the memory available for new processes (Linux only)
+func memAvailableMB() (int, bool) {
+	data, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0, false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemAvailable:" {
+			kb, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return 0, false
+			}
+			return kb / 1024, true
+		}
+	}
+	return 0, false
+}
+
+// countDescendants returns the number of processes below pid: the runners of
+// running tests and everything they started
+func countDescendants(pid int) (int, error) {
+	cmd := exec.Command("ps", "-A", "-o", "pid=,ppid=")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, err
+	}
+
+	children := make(map[int][]int)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		child, err1 := strconv.Atoi(fields[0])
+		parent, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil || child == cmd.Process.Pid {
+			continue
+		}
+		children[parent] = append(children[parent], child)
+	}
+
+	count := 0
+	queue := children[pid]
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		count++
+		queue = append(queue, children[p]...)
+	}
+	return count, nil
+}
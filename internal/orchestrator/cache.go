@@ -0,0 +1,35 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Package caches of docker-mode containers (docker.cache)
+const (
+	cacheRun        = "run"        // Shared by the run's tests, removed with the run
+	cachePersistent = "persistent" // Kept in ~/.tsuite/cache across runs
+	cacheOff        = "off"
+)
+
+// validateCache checks docker.cache
+func validateCache(cache string) error {
+	switch cache {
+	case "", cacheRun, cachePersistent, cacheOff:
+		return nil
+	}
+	return fmt.Errorf("docker.cache must be run, persistent or off, got %q", cache)
+}
+
+// cacheDir returns the host directory mounted into the run's containers for
+// the pip and npm download caches, or "" with docker.cache: off
+func (r *Run) cacheDir(baseWorkdir string) string {
+	switch r.Config.Docker.Cache {
+	case cacheOff:
+		return ""
+	case cachePersistent:
+		return filepath.Join(os.Getenv("HOME"), ".tsuite", "cache")
+	}
+	return filepath.Join(baseWorkdir, ".cache")
+}
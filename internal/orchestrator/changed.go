@@ -0,0 +1,41 @@
+package orchestrator
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/affected"
+)
+
+// selectChanged keeps only the selected tests affected by the files changed
+// in git since ChangedSince, recording why each was kept
+func (r *Run) selectChanged() error {
+	files, err := affected.ChangedFiles(r.SuitePath, r.ChangedSince)
+	if err != nil {
+		return fmt.Errorf("--changed-since: %w", err)
+	}
+	reasons, err := affected.Tests(r.SuitePath, r.Tests, files)
+	if err != nil {
+		return fmt.Errorf("--changed-since: %w", err)
+	}
+
+	r.changedFiles = len(files)
+	r.Affected = reasons
+	var tests []string
+	for _, testID := range r.Tests {
+		if reason, ok := reasons[testID]; ok {
+			slog.Debug("Test affected by changes", "test_id", testID, "reason", reason)
+			tests = append(tests, testID)
+		}
+	}
+	r.Tests = tests
+	return nil
+}
+
+// printChanged prints how many tests the changes since ChangedSince affect
+func (r *Run) printChanged() {
+	if r.ChangedSince == "" {
+		return
+	}
+	fmt.Fprintf(r.out, "Changed since %s: %d file(s), %d test(s) affected\n", r.ChangedSince, r.changedFiles, len(r.Tests))
+}
@@ -0,0 +1,324 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/executor"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+)
+
+// defaultDockerImage is used when the suite's config.yaml sets no docker.base_image
+const defaultDockerImage = "tsuite-mesh:local"
+
+// dockerImage returns the image of the run's test containers: the image
+// pinned by the run's manifest, else the image of the run's Node.js version
+// in docker.node_images (by version, else by major), else docker.base_image
+func (r *Run) dockerImage() string {
+	if r.image != "" {
+		return r.image
+	}
+	if r.NodeVersion != "" {
+		version := strings.TrimPrefix(r.NodeVersion, "v")
+		major, _, _ := strings.Cut(version, ".")
+		for _, key := range []string{r.NodeVersion, version, major} {
+			if image, ok := r.Config.Docker.NodeImages[key]; ok {
+				return image
+			}
+		}
+	}
+	if r.Config.Docker.BaseImage != "" {
+		return r.Config.Docker.BaseImage
+	}
+	return defaultDockerImage
+}
+
+// containerConfig returns the container settings for the run's tests
+func (r *Run) containerConfig(baseWorkdir string) *runner.ContainerConfig {
+	return &runner.ContainerConfig{
+		Image:        r.dockerImage(),
+		Network:      "bridge",
+		Env:          r.Env,
+		Mounts:       r.mounts,
+		PublishPorts: append(append([]string{}, r.Config.Docker.PublishPorts...), r.opts.PublishPorts...),
+		KeepFailed:   r.opts.KeepFailed,
+
+		UpdateSnapshots: r.opts.UpdateSnapshots,
+		CacheDir:        r.cacheDir(baseWorkdir),
+	}
+}
+
+// recordDockerOutput stores what the runner in the container reported for a
+// test. Failed tests the runner suggested no fixes for get suggestions from
+// the container's stderr and errors.
+func (r *Run) recordDockerOutput(testID string, o dockerOutcome, failed bool) {
+	if o.err != nil {
+		if failed {
+			r.recordSuggestions(testID, runner.SuggestFixesForText(o.err.Error()))
+		}
+		return
+	}
+	if o.result == nil {
+		return
+	}
+	reported := runner.ParseOutput(o.result.Stdout)
+	r.recordOutput(testID, reported, failed)
+	if failed && len(reported.Suggestions) == 0 {
+		texts := []string{o.result.Stderr}
+		if o.result.Error != nil {
+			texts = append(texts, o.result.Error.Error())
+		}
+		r.recordSuggestions(testID, runner.SuggestFixesForText(texts...))
+	}
+}
+
+// dockerSkipReason reports whether the runner in a container skipped the test
+func dockerSkipReason(result *runner.ContainerResult, err error) (string, bool) {
+	if err != nil || result == nil || result.ExitCode != 0 {
+		return "", false
+	}
+	reported := runner.ParseOutput(result.Stdout)
+	return reported.SkipReason, reported.Skipped
+}
+
+// dockerOutcome is the outcome of one test run in a container
+type dockerOutcome struct {
+	result   *runner.ContainerResult
+	err      error // The container could not be run
+	passed   bool
+	errMsg   string
+	duration time.Duration
+}
+
+// testResult returns the result of the test run in the container
+func (o dockerOutcome) testResult(testID string) executor.TestResult {
+	skipReason, wasSkipped := dockerSkipReason(o.result, o.err)
+	return executor.TestResult{
+		TestID:     testID,
+		Passed:     o.passed,
+		Error:      o.errMsg,
+		Duration:   o.duration,
+		Skipped:    wasSkipped,
+		SkipReason: skipReason,
+	}
+}
+
+// executeInDocker runs one test in a container
+func (r *Run) executeInDocker(ctx context.Context, dockerExec *runner.DockerExecutor, apiClient *client.Client, testID, baseWorkdir string) dockerOutcome {
+	// Note: Runner inside container reports "running" status to API
+	// Don't duplicate here to avoid race conditions with counter updates
+
+	// Run in Docker container (Go runner reports steps to API)
+	// Use combined context with timeout
+	testCtx, testCancel := context.WithTimeout(ctx, testTimeout)
+	result, err := dockerExec.ExecuteTest(testCtx, testID, nil)
+	testCancel()
+
+	out := dockerOutcome{result: result, err: err}
+	if err != nil {
+		out.errMsg = err.Error()
+		// Report failure to API since runner never started
+		if apiClient != nil && r.runID != "" {
+			apiClient.UpdateTestStatus(r.runID, testID, &client.UpdateTestStatusRequest{
+				Status:       "failed",
+				ErrorMessage: out.errMsg,
+			})
+		}
+		return out
+	}
+
+	if apiClient != nil && r.runID != "" && result.Container != nil {
+		// Status is left to the runner unless the container died before it
+		// could report (the API ignores updates to finished tests)
+		req := &client.UpdateTestStatusRequest{Container: result.Container}
+		if len(result.Container.Ports) > 0 {
+			// Published ports are recorded as captured values, e.g. port.8000/tcp
+			req.Captured = make(map[string]string, len(result.Container.Ports))
+			for port, addr := range result.Container.Ports {
+				req.Captured["port."+port] = addr
+			}
+		}
+		apiClient.UpdateTestStatus(r.runID, testID, req)
+		if result.Container.OOMKilled {
+			apiClient.UpdateTestStatus(r.runID, testID, &client.UpdateTestStatusRequest{
+				Status:       "failed",
+				ErrorMessage: result.Error.Error(),
+			})
+		}
+	}
+
+	if info := result.Container; info != nil && info.KeptUntil != "" {
+		fmt.Fprintf(r.out, "[KEEP] %s container %.12s kept until %s: docker exec -it %.12s bash\n", testID, info.ID, info.KeptUntil, info.ID)
+		for port, addr := range info.Ports {
+			fmt.Fprintf(r.out, "[KEEP] %s %s published at %s\n", testID, port, addr)
+		}
+	}
+
+	out.passed = result.ExitCode == 0 && result.Error == nil
+	if result.Error != nil {
+		out.errMsg = result.Error.Error()
+	} else if result.ExitCode != 0 {
+		out.errMsg = fmt.Sprintf("exit code %d", result.ExitCode)
+		if result.Stderr != "" {
+			lines := strings.Split(strings.TrimSpace(result.Stderr), "\n")
+			if len(lines) > 3 {
+				lines = lines[len(lines)-3:]
+			}
+			out.errMsg = strings.Join(lines, "; ")
+		}
+	}
+	out.duration = result.Duration
+	if !out.passed && ctx.Err() == nil {
+		r.keepWorkdir(testID, baseWorkdir)
+	}
+	return out
+}
+
+// runSequentialWithDocker runs tests one after another, each in its own container
+func (r *Run) runSequentialWithDocker(ctx context.Context, cancelFunc context.CancelFunc, apiClient *client.Client, baseWorkdir string, res *Result) {
+	dockerExec, err := runner.NewDockerExecutor(r.opts.APIURL, r.SuitePath, baseWorkdir, r.containerConfig(baseWorkdir), r.runID)
+	if err != nil {
+		fmt.Fprintf(r.out, "Failed to create Docker executor: %v\n", err)
+		res.Failed = len(r.Tests)
+		res.FailedTests = r.Tests
+		return
+	}
+	defer dockerExec.Close()
+
+	// Start cancel checker goroutine
+	if apiClient != nil {
+		executor.StartCancelChecker(ctx, cancelFunc, apiClient, r.runID, r.cancelTest, r.out)
+	}
+
+	for _, testID := range r.Tests {
+		// Check if cancelled before starting test
+		select {
+		case <-ctx.Done():
+			fmt.Fprintf(r.out, "[SKIP] %s (cancelled)\n", testID)
+			res.Skipped++
+			res.Cancelled = true
+			continue
+		default:
+		}
+
+		var out dockerOutcome
+		result := r.runTest(ctx, testID, func(ctx context.Context) executor.TestResult {
+			fmt.Fprintf(r.out, "\n[RUN] %s\n", testID)
+			out = r.executeInDocker(ctx, dockerExec, apiClient, testID, baseWorkdir)
+			result := out.testResult(testID)
+			result.Cancelled = ctx.Err() != nil
+			return result
+		})
+
+		// Check if cancelled during test
+		if ctx.Err() == context.Canceled {
+			fmt.Fprintf(r.out, "[SKIP] %s (cancelled)\n", testID)
+			res.Skipped++
+			res.Cancelled = true
+			continue
+		}
+
+		r.recordDockerOutput(testID, out, !result.Passed && !result.Skipped)
+
+		if result.Skipped {
+			fmt.Fprintf(r.out, "[SKIP] %s (%s)\n", testID, result.SkipReason)
+			res.Skipped++
+		} else if result.Passed {
+			fmt.Fprintf(r.out, "[PASS] %s (%.1fs)\n", testID, out.duration.Seconds())
+			res.Passed++
+		} else {
+			fmt.Fprintf(r.out, "[FAIL] %s - %s (%.1fs)\n", testID, out.errMsg, out.duration.Seconds())
+			res.Failed++
+			res.FailedTests = append(res.FailedTests, testID)
+		}
+		// Note: Go runner inside container reports final status with steps to API
+	}
+}
+
+// runParallelWithDocker runs tests on parallel workers, each with its own
+// Docker executor
+func (r *Run) runParallelWithDocker(ctx context.Context, cancelFunc context.CancelFunc, apiClient *client.Client, baseWorkdir string, res *Result) {
+	testCh := make(chan string, len(r.Tests))
+	resultCh := make(chan executor.TestResult, len(r.Tests))
+
+	// Start cancel checker goroutine
+	if apiClient != nil {
+		executor.StartCancelChecker(ctx, cancelFunc, apiClient, r.runID, r.cancelTest, r.out)
+	}
+
+	// Start workers
+	var wg sync.WaitGroup
+	for i := 0; i < r.Parallel; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+
+			// Each worker gets its own docker executor (for isolation)
+			dockerExec, err := runner.NewDockerExecutor(r.opts.APIURL, r.SuitePath, baseWorkdir, r.containerConfig(baseWorkdir), r.runID)
+			if err != nil {
+				fmt.Fprintf(r.out, "Worker %d: Failed to create Docker executor: %v\n", workerID, err)
+				// Mark all remaining tests as failed
+				for testID := range testCh {
+					resultCh <- executor.TestResult{TestID: testID, Passed: false, Error: err.Error()}
+				}
+				return
+			}
+			defer dockerExec.Close()
+
+			for testID := range testCh {
+				// Check if cancelled before starting test
+				select {
+				case <-ctx.Done():
+					resultCh <- executor.TestResult{TestID: testID, Cancelled: true}
+					continue
+				default:
+				}
+
+				if err := r.resources.acquire(ctx); err != nil {
+					resultCh <- executor.TestResult{TestID: testID, Cancelled: true}
+					continue
+				}
+				var out dockerOutcome
+				result := r.runTest(ctx, testID, func(ctx context.Context) executor.TestResult {
+					out = r.executeInDocker(ctx, dockerExec, apiClient, testID, baseWorkdir)
+					result := out.testResult(testID)
+					result.Cancelled = ctx.Err() != nil
+					return result
+				})
+				r.resources.release()
+
+				// Check if cancelled during test
+				if ctx.Err() == context.Canceled {
+					resultCh <- executor.TestResult{TestID: testID, Cancelled: true}
+					continue
+				}
+
+				r.recordDockerOutput(testID, out, !result.Passed && !result.Skipped)
+
+				resultCh <- result
+				// Note: Go runner inside container reports final status with steps to API
+			}
+		}(i)
+	}
+
+	// Send tests to workers
+	for _, t := range r.Tests {
+		testCh <- t
+	}
+	close(testCh)
+
+	// Wait for all workers
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	// Collect results
+	results := executor.CollectResults(resultCh, r.out)
+	res.Passed, res.Failed, res.Skipped = results.Passed, results.Failed, results.Skipped
+	res.FailedTests, res.Cancelled = results.FailedTests, results.Cancelled
+}
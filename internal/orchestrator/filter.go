@@ -0,0 +1,111 @@
+package orchestrator
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// Filter selects tests by use case, test case and tags
+type Filter struct {
+	UC       []string // Use case name substrings
+	TC       []string // Test case name substrings, or full uc/tc test IDs
+	Tags     []string // Any of these tags
+	SkipTags []string // None of these tags
+
+	// Only smoke tests: tagged smoke, or listed in SmokeTests (test IDs or
+	// use cases, from smoke.yaml)
+	Smoke      bool
+	SmokeTests []string
+}
+
+// smokeTag marks the tests tsuite run --smoke selects
+const smokeTag = "smoke"
+
+// FilterTests returns the tests of the suite at suitePath that match filter
+func FilterTests(suitePath string, tests []string, filter Filter) []string {
+	var filtered []string
+
+	for _, testID := range tests {
+		parts := strings.Split(testID, "/")
+		if len(parts) < 2 {
+			continue
+		}
+		ucName := parts[0]
+		tcName := parts[1]
+
+		// Filter by use case
+		if len(filter.UC) > 0 {
+			match := false
+			for _, uc := range filter.UC {
+				if strings.Contains(ucName, uc) {
+					match = true
+					break
+				}
+			}
+			if !match {
+				continue
+			}
+		}
+
+		// Filter by test case
+		// Supports both formats:
+		// - Full path: --tc uc01_registry/tc01_agent (exact match on testID)
+		// - TC name only: --tc tc01_agent (substring match on tcName)
+		if len(filter.TC) > 0 {
+			match := false
+			for _, tc := range filter.TC {
+				if strings.Contains(tc, "/") {
+					// Full path format - exact match on testID
+					if testID == tc {
+						match = true
+						break
+					}
+				} else {
+					// TC name only - substring match
+					if strings.Contains(tcName, tc) {
+						match = true
+						break
+					}
+				}
+			}
+			if !match {
+				continue
+			}
+		}
+
+		// Filter by tags (any of Tags, none of SkipTags) and smoke
+		if len(filter.Tags) > 0 || len(filter.SkipTags) > 0 || filter.Smoke {
+			var tags []string
+			if testConfig, err := config.LoadTestConfig(filepath.Join(suitePath, "suites", ucName, tcName)); err == nil {
+				tags = testConfig.Tags
+			}
+			if filter.Smoke && !slices.Contains(tags, smokeTag) &&
+				!slices.Contains(filter.SmokeTests, testID) && !slices.Contains(filter.SmokeTests, ucName) {
+				continue
+			}
+			if len(filter.Tags) > 0 && !hasAnyTag(tags, filter.Tags) {
+				continue
+			}
+			if hasAnyTag(tags, filter.SkipTags) {
+				continue
+			}
+		}
+
+		filtered = append(filtered, testID)
+	}
+
+	return filtered
+}
+
+// hasAnyTag reports whether tags contains any of want
+func hasAnyTag(tags, want []string) bool {
+	for _, tag := range tags {
+		if slices.Contains(want, tag) {
+			return true
+		}
+	}
+	return false
+}
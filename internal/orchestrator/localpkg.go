@@ -0,0 +1,194 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/handlers"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+)
+
+// localBuildTimeout bounds building one SDK checkout
+const localBuildTimeout = 10 * time.Minute
+
+// buildLocalPackages builds the SDK checkouts of packages.local into wheels
+// and npm tarballs under the run's workdir, once per run. Tests install
+// them instead of the published packages: containers mount them at /wheels
+// and /packages, standalone runners get their directories in the
+// environment. The built versions replace packages.sdk_python_version and
+// packages.sdk_typescript_version.
+func (r *Run) buildLocalPackages(ctx context.Context, baseWorkdir string) error {
+	local := r.Config.Packages.Local
+
+	if local.PythonSource != "" {
+		dir := filepath.Join(baseWorkdir, "local", "wheels")
+		version, err := buildWheels(ctx, r.sourcePath(local.PythonSource), dir, r.Env)
+		if err != nil {
+			return fmt.Errorf("failed to build packages.local.python_source%s: %w", r.offlineHint(r.Config.Packages.Mirror.PipIndexURL, "pip_index_url"), err)
+		}
+		fmt.Fprintf(r.out, "Built %s %s from %s\n", runner.SDKPythonPackage, version, local.PythonSource)
+		r.Config.Packages.SDKPythonVersion = version
+		r.Env = append(r.Env, runner.EnvSDKPythonVersion+"="+version)
+		// pip install in shell steps finds the wheels too
+		wheels := r.useLocalPackages(dir, "/wheels", handlers.EnvWheelsDir)
+		r.Env = append(r.Env, "PIP_FIND_LINKS="+wheels)
+	}
+
+	if local.TypescriptSource != "" {
+		dir := filepath.Join(baseWorkdir, "local", "packages")
+		version, err := buildTarballs(ctx, r.sourcePath(local.TypescriptSource), dir, r.Env)
+		if err != nil {
+			return fmt.Errorf("failed to build packages.local.typescript_source%s: %w", r.offlineHint(r.Config.Packages.Mirror.NpmRegistry, "npm_registry"), err)
+		}
+		fmt.Fprintf(r.out, "Built %s %s from %s\n", runner.SDKTypescriptPackage, version, local.TypescriptSource)
+		r.Config.Packages.SDKTypescriptVersion = version
+		r.Env = append(r.Env, runner.EnvSDKTypescriptVersion+"="+version)
+		r.useLocalPackages(dir, "/packages", handlers.EnvPackagesDir)
+	}
+	return nil
+}
+
+// sourcePath resolves a path of config.yaml against the suite
+func (r *Run) sourcePath(source string) string {
+	if filepath.IsAbs(source) {
+		return source
+	}
+	return filepath.Join(r.SuitePath, source)
+}
+
+// useLocalPackages hands a directory of built packages to the tests and
+// returns where they see it: mounted at mountPath in containers, or named
+// by env for standalone runners
+func (r *Run) useLocalPackages(dir, mountPath, env string) string {
+	if r.Mode == "docker" {
+		r.mounts = append(r.mounts, runner.MountConfig{
+			Type:          "host",
+			HostPath:      dir,
+			ContainerPath: mountPath,
+			ReadOnly:      true,
+		})
+		return mountPath
+	}
+	r.Env = append(r.Env, env+"="+dir)
+	return dir
+}
+
+// buildWheels builds the wheel of a Python project into dir, with the run's
+// env (e.g. its package mirror), and returns its version, the mcp-mesh
+// wheel's if the project builds several
+func buildWheels(ctx context.Context, src, dir string, env []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, localBuildTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "python3", "-m", "pip", "wheel", "--no-deps", "--wheel-dir", dir, src)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("pip wheel %s failed: %v\n%s", src, err, lastLines(out, 20))
+	}
+
+	// Wheels are named {distribution}-{version}-...whl, with - in the
+	// distribution name written as _
+	wheels, _ := filepath.Glob(filepath.Join(dir, "*.whl"))
+	if len(wheels) == 0 {
+		return "", fmt.Errorf("pip wheel %s built no wheel", src)
+	}
+	version := ""
+	for _, wheel := range wheels {
+		parts := strings.Split(filepath.Base(wheel), "-")
+		if len(parts) < 2 {
+			continue
+		}
+		if version == "" || parts[0] == strings.ReplaceAll(runner.SDKPythonPackage, "-", "_") {
+			version = parts[1]
+		}
+	}
+	return version, nil
+}
+
+// buildTarballs packs an npm package, or each package of a workspace, into
+// dir with the run's env and returns the version of @mcpmesh/core, else of
+// the first package
+func buildTarballs(ctx context.Context, src, dir string, env []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, localBuildTimeout)
+	defer cancel()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	args := []string{"pack", "--json", "--pack-destination", dir}
+	if hasWorkspaces(src) {
+		args = append(args, "--workspaces")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "npm", args...)
+	cmd.Dir = src
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("npm pack in %s failed: %v\n%s", src, err, lastLines(stderr.Bytes(), 20))
+	}
+
+	packed := parsePackOutput(out)
+	if len(packed) == 0 {
+		return "", fmt.Errorf("npm pack in %s packed nothing", src)
+	}
+	for _, p := range packed {
+		if p.Name == runner.SDKTypescriptPackage {
+			return p.Version, nil
+		}
+	}
+	return packed[0].Version, nil
+}
+
+// packedPackage is an entry of npm pack --json output
+type packedPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// parsePackOutput reads npm pack --json output, nil if there is none. Scripts npm pack runs (e.g.
+// a prepack build) may print before the JSON, so it is parsed from the
+// last line that starts a valid array.
+func parsePackOutput(out []byte) []packedPackage {
+	lines := bytes.Split(out, []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		if !bytes.HasPrefix(lines[i], []byte("[")) {
+			continue
+		}
+		var packed []packedPackage
+		if json.Unmarshal(bytes.Join(lines[i:], []byte("\n")), &packed) == nil {
+			return packed
+		}
+	}
+	return nil
+}
+
+// hasWorkspaces reports whether the package.json in dir declares workspaces
+func hasWorkspaces(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	return json.Unmarshal(data, &pkg) == nil && len(pkg.Workspaces) > 0
+}
+
+// lastLines returns the last n lines of command output
+func lastLines(out []byte, n int) string {
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
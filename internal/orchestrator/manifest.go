@@ -0,0 +1,237 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/affected"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+)
+
+// ManifestFile is the name of the manifest each run writes to its directory
+// under ~/.tsuite/runs
+const ManifestFile = "run-manifest.json"
+
+// Manifest records what a run resolved: the versions of everything it tested
+// with and the tests it ran. tsuite run --from-manifest pins them to
+// reproduce the run.
+type Manifest struct {
+	RunID     string    `json:"run_id,omitempty"` // Empty if the API server was not available
+	CreatedAt time.Time `json:"created_at"`
+
+	Suite       string   `json:"suite"`
+	SuiteCommit string   `json:"suite_commit,omitempty"` // Empty if the suite is not in git
+	SuiteDirty  bool     `json:"suite_dirty,omitempty"`  // The suite had uncommitted changes
+	Mode        string   `json:"mode"`
+	Profile     string   `json:"profile,omitempty"`
+	Tests       []string `json:"tests"`
+
+	CLIVersion    string `json:"cli_version"`
+	RunnerVersion string `json:"runner_version,omitempty"`
+	RunnerSHA256  string `json:"runner_sha256,omitempty"`
+
+	Image       string `json:"image,omitempty"`        // Docker mode
+	ImageDigest string `json:"image_digest,omitempty"` // Repo digest, or image ID of a local build
+	NodeVersion string `json:"node_version,omitempty"`
+
+	Packages ManifestPackages `json:"packages"`
+}
+
+// ManifestPackages are the package versions a run tested
+type ManifestPackages struct {
+	SDKPython     string `json:"sdk_python,omitempty"`
+	SDKTypescript string `json:"sdk_typescript,omitempty"`
+	CLI           string `json:"cli,omitempty"`         // packages.cli_version
+	Meshctl       string `json:"meshctl,omitempty"`     // Installed by meshctl-install steps
+	LocalBuild    bool   `json:"local_build,omitempty"` // SDKs built from packages.local checkouts
+}
+
+// LoadManifest reads a run manifest
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse run manifest %s: %w", path, err)
+	}
+	if len(m.Tests) == 0 {
+		return nil, fmt.Errorf("run manifest %s lists no tests", path)
+	}
+	return &m, nil
+}
+
+// useManifest pins the run to the versions of a manifest and, without a
+// filter of its own, to its tests. What can't be pinned (the tsuite and
+// runner binaries, the suite's files) is compared and reported by
+// PrintHeader.
+func (r *Run) useManifest(m *Manifest, env map[string]string, filter *Filter) {
+	if r.opts.Mode == "" {
+		r.Mode = m.Mode
+	}
+	if r.opts.NodeVersion == "" && m.NodeVersion != "" {
+		r.NodeVersion = m.NodeVersion
+	}
+
+	// Images are never pulled: the digest pins the exact image while it is
+	// still on this machine, else the image's tag is used
+	if r.Mode == "docker" && m.ImageDigest != "" {
+		if runner.ImageDigest(m.ImageDigest) != "" {
+			r.image = m.ImageDigest
+		} else {
+			r.image = m.Image
+			r.manifestDrift = append(r.manifestDrift, fmt.Sprintf("image %s (%s) is no longer available locally; using %s as tagged now", m.Image, m.ImageDigest, m.Image))
+		}
+	}
+
+	// Runners read packages from config.yaml; the pins reach them through
+	// the environment
+	pin := func(name string, field *string, version string) {
+		if version != "" {
+			*field = version
+			env[name] = version
+		}
+	}
+	pin(runner.EnvSDKPythonVersion, &r.Config.Packages.SDKPythonVersion, m.Packages.SDKPython)
+	pin(runner.EnvSDKTypescriptVersion, &r.Config.Packages.SDKTypescriptVersion, m.Packages.SDKTypescript)
+	cli := m.Packages.CLI
+	if m.Packages.Meshctl != "" {
+		cli = m.Packages.Meshctl
+	}
+	pin(runner.EnvCLIVersion, &r.Config.Packages.CLIVersion, cli)
+	if m.Packages.LocalBuild {
+		r.manifestDrift = append(r.manifestDrift, "the SDKs were built from packages.local checkouts; check out the same SDK commits to test the same code")
+	}
+
+	if len(filter.UC) == 0 && len(filter.TC) == 0 && len(r.opts.Filter.Tags) == 0 && len(r.opts.Filter.SkipTags) == 0 && !r.Smoke && r.ChangedSince == "" {
+		*filter = Filter{TC: m.Tests}
+	}
+}
+
+// checkManifest compares what the run can't pin with its manifest
+func (r *Run) checkManifest(m *Manifest, allTests []string) {
+	if m.CLIVersion != r.opts.Version {
+		r.manifestDrift = append(r.manifestDrift, fmt.Sprintf("the run used tsuite %s, this is %s (switch with: tsuite self-update --version %s)", m.CLIVersion, r.opts.Version, m.CLIVersion))
+	}
+	if m.SuiteCommit != "" {
+		commit, dirty, err := affected.Commit(r.SuitePath)
+		switch {
+		case err != nil:
+			r.manifestDrift = append(r.manifestDrift, fmt.Sprintf("the suite was at commit %s but is no longer in git", shortCommit(m.SuiteCommit)))
+		case commit != m.SuiteCommit:
+			r.manifestDrift = append(r.manifestDrift, fmt.Sprintf("the suite was at commit %s, now %s (check it out with: git checkout %s)", shortCommit(m.SuiteCommit), shortCommit(commit), m.SuiteCommit))
+		case dirty:
+			r.manifestDrift = append(r.manifestDrift, "the suite has uncommitted changes")
+		}
+	}
+	if m.SuiteDirty {
+		r.manifestDrift = append(r.manifestDrift, "the run's suite had uncommitted changes, which the manifest doesn't hold")
+	}
+	var missing []string
+	for _, testID := range m.Tests {
+		if !slices.Contains(allTests, testID) {
+			missing = append(missing, testID)
+		}
+	}
+	if len(missing) > 0 {
+		r.manifestDrift = append(r.manifestDrift, fmt.Sprintf("%d test(s) of the run no longer exist: %s", len(missing), strings.Join(missing, ", ")))
+	}
+}
+
+// printManifest prints the manifest the run reproduces and how it differs
+func (r *Run) printManifest() {
+	if r.manifest == nil {
+		return
+	}
+	run := r.manifest.RunID
+	if len(run) > 12 {
+		run = run[:12]
+	}
+	if run == "" {
+		run = r.opts.FromManifest
+	}
+	fmt.Fprintf(r.out, "Reproducing run %s (%s)\n", run, r.manifest.CreatedAt.Local().Format(time.DateTime))
+	for _, drift := range r.manifestDrift {
+		fmt.Fprintf(r.out, "Warning: %s\n", drift)
+	}
+}
+
+// writeManifest writes the run's manifest to its directory under
+// ~/.tsuite/runs and returns its path
+func (r *Run) writeManifest(baseWorkdir string) (string, error) {
+	m := &Manifest{
+		RunID:       r.runID,
+		CreatedAt:   time.Now().UTC(),
+		Suite:       r.Config.Suite.Name,
+		Mode:        r.Mode,
+		Profile:     r.opts.Profile,
+		Tests:       r.Tests,
+		CLIVersion:  r.opts.Version,
+		NodeVersion: r.NodeVersion,
+		Packages: ManifestPackages{
+			SDKPython:     r.sdk.Python,
+			SDKTypescript: r.sdk.Typescript,
+			CLI:           r.Config.Packages.CLIVersion,
+			Meshctl:       r.recordedValue("meshctl.version"),
+			LocalBuild:    r.Config.Packages.Local.PythonSource != "" || r.Config.Packages.Local.TypescriptSource != "",
+		},
+	}
+	if r.runner != nil {
+		m.RunnerVersion = r.runner.Version
+		m.RunnerSHA256 = r.runner.SHA256
+	}
+	if commit, dirty, err := affected.Commit(r.SuitePath); err == nil {
+		m.SuiteCommit = commit
+		m.SuiteDirty = dirty
+	}
+	if r.Mode == "docker" {
+		m.Image = r.dockerImage()
+		m.ImageDigest = runner.ImageDigest(m.Image)
+		if r.manifest != nil && m.Image == r.manifest.ImageDigest {
+			m.Image = r.manifest.Image
+		}
+	}
+	// The version the tests ran with, e.g. v20.11.1 for node_version 20
+	if node := r.recordedValue("node.version"); node != "" {
+		m.NodeVersion = node
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(r.runDir(baseWorkdir), ManifestFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// recordedValue returns the value the run's tests recorded for key, or ""
+// if none did or they recorded different values
+func (r *Run) recordedValue(key string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.recorded[key]) != 1 {
+		return ""
+	}
+	return slices.Collect(maps.Keys(r.recorded[key]))[0]
+}
+
+// shortCommit abbreviates a commit hash for messages
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
@@ -0,0 +1,25 @@
+package orchestrator
+
+import "fmt"
+
+// printOffline prints where an offline run's installs come from
+func (r *Run) printOffline() {
+	mirror := r.Config.Packages.Mirror
+	pip, npm := mirror.PipIndexURL, mirror.NpmRegistry
+	if pip == "" {
+		pip = "none, pip installs fail"
+	}
+	if npm == "" {
+		npm = "none, npm installs fail"
+	}
+	fmt.Fprintf(r.out, "Offline: pip mirror %s; npm mirror %s\n", pip, npm)
+}
+
+// offlineHint explains why an install of an offline run likely failed: it
+// had no mirror (packages.mirror.<setting>). It is "" otherwise.
+func (r *Run) offlineHint(mirror, setting string) string {
+	if !r.Offline || mirror != "" {
+		return ""
+	}
+	return " (--offline without packages.mirror." + setting + ")"
+}
@@ -0,0 +1,728 @@
+// Package orchestrator runs the tests of a suite: it selects the tests,
+// records the run with the API server and executes each test with the runner
+// binary or in a Docker container. It is used by tsuite run and by API
+// servers started with --in-process.
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/diskspace"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/handlers"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+)
+
+// Options selects the tests of a run and how they are executed
+type Options struct {
+	SuitePath string
+	Filter    Filter
+	Parallel  int      // Parallel test runners (0 = the profile's, else the suite's max_workers, else 1)
+	Mode      string   // docker or standalone instead of the suite's mode
+	Profile   string   // Run profile from config.yaml
+	Env       []string // KEY=VALUE set for every test, over the profile's env
+
+	// Docker mode: container ports published to the host, in addition to
+	// the suite's docker.publish_ports, and how long failed tests' containers
+	// keep running for inspection
+	PublishPorts []string
+	KeepFailed   time.Duration
+
+	// Keep failed tests' workdirs (nil = the suite's execution.keep_failed_workdirs)
+	KeepWorkdir *bool
+
+	// Start no more tests once a test failed
+	FailFast bool
+
+	// Only run the smoke tests, within the time budget of smoke.yaml
+	Smoke bool
+
+	// Only run the tests affected by files changed in git since this ref
+	ChangedSince string
+
+	// Write captured values to the golden files of snapshot assertions
+	UpdateSnapshots bool
+
+	// Node.js version instead of the profile's or packages.node_version
+	NodeVersion string
+
+	// Run manifest of an earlier run whose versions and tests to reproduce
+	FromManifest string
+
+	// Forbid fetching from the internet: installs must use packages.mirror
+	Offline bool
+
+	APIURL        string
+	RunnerPath    string // Runner binary (default: auto-detect)
+	RunID         string // Run ID reserved by the API that launched the run
+	ParentRunID   string // Run the new run is a rerun of
+	PipelineRunID string // Pipeline run the new run is part of
+	Version       string // tsuite version recorded with the run
+
+	Output io.Writer // Progress output (default: stdout)
+}
+
+// Run is a prepared run: the suite, the resolved options and the selected tests
+type Run struct {
+	SuitePath string // Absolute, with symlinks resolved
+	Config    *config.SuiteConfig
+	Mode      string
+	Parallel  int
+	Env       []string // KEY=VALUE set for every test
+	Tests     []string
+
+	KeepWorkdir bool // Keep failed tests' workdirs under ~/.tsuite/runs
+	FailFast    bool // Start no more tests once a test failed
+
+	Smoke       bool          // Smoke run: only smoke tests, within SmokeBudget
+	SmokeBudget time.Duration // Time the smoke tests may take together
+
+	NodeVersion string // Node.js version the tests run with (packages.node_version)
+
+	Offline bool // No internet: installs use packages.mirror, others fail fast
+
+	ChangedSince string            // Git ref the run selects changed tests against
+	Affected     map[string]string // With ChangedSince: why each test was selected
+
+	opts   Options
+	out    io.Writer
+	runID  string
+	runner *runner.RunnerInfo
+	api    *client.Client // nil without an API server
+
+	mu               sync.Mutex
+	suggestedFixes   map[string][]string
+	assertionDiffs   map[string][]string
+	leakedResources  map[string][]string
+	budgetViolations map[string]string
+	testCancels      map[string]context.CancelFunc // Running tests, by test ID
+	cancelledTests   map[string]bool               // Tests cancelled on their own
+	stopped          *stopReason                   // Why no more tests start, if so
+	smokeExceeded    bool                          // The smoke time budget ran out
+
+	priorities   map[string]int // Tests with a priority other than normal
+	changedFiles int            // Files changed since ChangedSince
+
+	resources         *resourceBudget // execution.budget, nil if unset
+	requestedParallel int             // Parallel before execution.budget lowered it
+
+	mounts []runner.MountConfig // Docker mode: SDK packages built from packages.local, the CA bundle
+
+	sdk           runner.SDKVersions         // SDK versions the run tests
+	recorded      map[string]map[string]bool // Values tests recorded, by key
+	image         string                     // Docker image pinned by the manifest
+	manifest      *Manifest                  // With FromManifest
+	manifestDrift []string                   // How the run differs from the manifest
+}
+
+// Result is the outcome of an executed run
+type Result struct {
+	RunID       string // Empty if the API server was not available
+	Passed      int
+	Failed      int
+	Skipped     int
+	FailedTests []string
+	Cancelled   bool
+	Duration    time.Duration
+	SmokeStatus string // SmokePassed, SmokeFailed or SmokeOverBudget; empty if not a smoke run
+
+	SuggestedFixes   map[string][]string // Per failed test
+	AssertionDiffs   map[string][]string // Per failed test: diff lines of its failed assertions
+	FailureOwners    map[string][]string // Per failed test with owners
+	LeakedResources  map[string][]string // Per test (execution.leak_checks)
+	BudgetViolations map[string]string   // Per test (duration_budget_ms)
+}
+
+// testTimeout bounds each test (10 minutes)
+const testTimeout = 10 * time.Minute
+
+// Prepare loads the suite, applies the run profile and the options, and
+// selects the tests to run
+func Prepare(opts Options) (*Run, error) {
+	// Resolve suite path (including symlinks for consistent matching with database)
+	absPath, err := filepath.Abs(opts.SuitePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve suite path: %w", err)
+	}
+	absPath, err = filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve symlinks: %w", err)
+	}
+
+	suiteConfig, err := config.LoadSuiteConfig(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suite config: %w", err)
+	}
+
+	// The manifest's profile applies unless another is given
+	var manifest *Manifest
+	if opts.FromManifest != "" {
+		if manifest, err = LoadManifest(opts.FromManifest); err != nil {
+			return nil, err
+		}
+		if opts.Profile == "" {
+			opts.Profile = manifest.Profile
+		}
+	}
+
+	r := &Run{
+		SuitePath: absPath,
+		Config:    suiteConfig,
+		Mode:      suiteConfig.Suite.Mode,
+		Parallel:  1,
+		opts:      opts,
+		out:       opts.Output,
+
+		KeepWorkdir: suiteConfig.Execution.KeepFailedWorkdirs,
+		NodeVersion: suiteConfig.Packages.NodeVersion,
+		FailFast:    opts.FailFast,
+		Smoke:       opts.Smoke,
+		Offline:     opts.Offline,
+
+		ChangedSince: opts.ChangedSince,
+
+		suggestedFixes:   make(map[string][]string),
+		assertionDiffs:   make(map[string][]string),
+		leakedResources:  make(map[string][]string),
+		budgetViolations: make(map[string]string),
+		testCancels:      make(map[string]context.CancelFunc),
+		cancelledTests:   make(map[string]bool),
+		recorded:         make(map[string]map[string]bool),
+		manifest:         manifest,
+	}
+	if r.out == nil {
+		r.out = os.Stdout
+	}
+	if suiteConfig.Execution.MaxWorkers > 0 {
+		r.Parallel = suiteConfig.Execution.MaxWorkers
+	}
+
+	// The profile fills in options that were not given
+	filter := opts.Filter
+	mirror := suiteConfig.Packages.Mirror
+	env := handlers.MirrorEnv(mirror.PipIndexURL, mirror.NpmRegistry, opts.Offline)
+	if opts.Offline {
+		env[handlers.EnvOffline] = "1"
+	}
+	if opts.Profile != "" {
+		profile, ok := suiteConfig.Profiles[opts.Profile]
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found in config.yaml", opts.Profile)
+		}
+		if profile.Mode != "" {
+			r.Mode = profile.Mode
+		}
+		if profile.Parallel > 0 {
+			r.Parallel = profile.Parallel
+		}
+		if len(filter.Tags) == 0 {
+			filter.Tags = profile.Tags
+		}
+		if len(filter.SkipTags) == 0 {
+			filter.SkipTags = profile.SkipTags
+		}
+		maps.Copy(env, profile.Env)
+		if profile.NodeVersion != "" {
+			r.NodeVersion = profile.NodeVersion
+		}
+	}
+
+	if opts.Mode != "" {
+		r.Mode = opts.Mode
+	}
+	if r.Mode == "" {
+		r.Mode = "standalone"
+	}
+	if r.Mode != "docker" && r.Mode != "standalone" {
+		return nil, fmt.Errorf("mode must be docker or standalone, got %q", r.Mode)
+	}
+	if err := validateCache(suiteConfig.Docker.Cache); err != nil {
+		return nil, err
+	}
+	if manifest != nil {
+		r.useManifest(manifest, env, &filter)
+	}
+
+	// Steps and containers go through the suite's proxy, unless the profile
+	// sets the variables itself
+	for name, value := range r.proxyEnv() {
+		if _, ok := env[name]; !ok {
+			env[name] = value
+		}
+	}
+	bundle, err := r.useCABundle()
+	if err != nil {
+		return nil, err
+	}
+	if bundle != "" {
+		env[runner.EnvCABundle] = bundle
+	}
+	if opts.Parallel > 0 {
+		r.Parallel = opts.Parallel
+	}
+	r.resources = newResourceBudget(suiteConfig.Execution.Budget, r.out)
+	if n := r.resources.maxTests(); n > 0 && r.Parallel > n {
+		r.requestedParallel = r.Parallel
+		r.Parallel = n
+	}
+	if opts.KeepWorkdir != nil {
+		r.KeepWorkdir = *opts.KeepWorkdir
+	}
+
+	if opts.NodeVersion != "" {
+		r.NodeVersion = opts.NodeVersion
+	}
+	// Runners read packages.node_version from config.yaml; overrides reach
+	// them through the environment
+	if r.NodeVersion != suiteConfig.Packages.NodeVersion {
+		env[runner.EnvNodeVersion] = r.NodeVersion
+	}
+
+	for _, kv := range opts.Env {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("--env must be KEY=VALUE, got %q", kv)
+		}
+		env[name] = value
+	}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		r.Env = append(r.Env, name+"="+env[name])
+	}
+
+	if r.Smoke {
+		smoke, err := config.LoadSmokeConfig(absPath)
+		if err != nil {
+			return nil, err
+		}
+		filter.Smoke = true
+		filter.SmokeTests = smoke.Tests
+		r.SmokeBudget = smoke.TimeBudget
+	}
+
+	allTests, err := runner.ListTests(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tests: %w", err)
+	}
+	r.Tests = FilterTests(absPath, allTests, filter)
+	if manifest != nil {
+		r.checkManifest(manifest, allTests)
+	}
+	if r.ChangedSince != "" {
+		if err := r.selectChanged(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.loadPriorities(); err != nil {
+		return nil, err
+	}
+	r.orderByPriority()
+
+	return r, nil
+}
+
+// PrintHeader prints the suite, its resolved mode and parallelism and the
+// number of selected tests
+func (r *Run) PrintHeader() {
+	fmt.Fprintf(r.out, "Suite: %s (mode: %s, parallel: %d)\n", r.Config.Suite.Name, r.Mode, r.Parallel)
+	r.printManifest()
+	if r.requestedParallel > 0 {
+		fmt.Fprintf(r.out, "Parallel lowered from %d: execution.budget fits %d test(s) in max_memory_mb\n", r.requestedParallel, r.Parallel)
+	}
+	if len(r.Tests) > 0 {
+		fmt.Fprintf(r.out, "Found %d test(s)\n", len(r.Tests))
+	}
+	r.printChanged()
+	if r.Smoke {
+		fmt.Fprintf(r.out, "Smoke run: time budget %s\n", r.SmokeBudget)
+	}
+	if r.NodeVersion != "" {
+		fmt.Fprintf(r.out, "Node: %s\n", r.NodeVersion)
+	}
+	if r.Offline {
+		r.printOffline()
+	}
+	if len(r.priorities) > 0 {
+		fmt.Fprintf(r.out, "Priority: %d high (run first), %d low (run last)\n", r.countPriority(priorityHigh), r.countPriority(priorityLow))
+	}
+}
+
+// Execute runs the selected tests and records the run with the API server,
+// if it is available. Cancelling ctx or the run (via the API) skips the
+// remaining tests.
+func (r *Run) Execute(ctx context.Context) (*Result, error) {
+	// Runners record the CLI version with each result
+	os.Setenv(runner.EnvTsuiteVersion, r.opts.Version)
+
+	if err := r.checkDiskSpace(); err != nil {
+		return nil, err
+	}
+
+	// Check Docker availability if docker mode
+	if r.Mode == "docker" {
+		ok, msg := runner.CheckDockerAvailable()
+		if !ok {
+			return nil, fmt.Errorf("Docker not available: %s", msg)
+		}
+		if err := runner.CheckDockerHostLocal(); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(r.out, "Docker: %s\n", msg)
+	}
+
+	// Create temp workdir for test execution
+	baseWorkdir, err := os.MkdirTemp("", "tsuite_")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp workdir: %w", err)
+	}
+	if r.Mode == "standalone" {
+		fmt.Fprintf(r.out, "Workdir: %s\n", baseWorkdir)
+	}
+	defer os.RemoveAll(baseWorkdir) // Cleanup after run
+
+	// Verify the runner matches this version of tsuite before recording the run
+	if err := r.resolveRunner(); err != nil {
+		return nil, err
+	}
+
+	// SDK checkouts are built before the run is recorded, which records
+	// their versions
+	if err := r.buildLocalPackages(ctx, baseWorkdir); err != nil {
+		return nil, err
+	}
+
+	// The SDK versions are recorded with the run and in its manifest
+	r.sdk = r.sdkVersions(ctx)
+	r.printSDKVersions(r.sdk)
+
+	// Create API client
+	apiClient := client.NewClient(r.opts.APIURL)
+
+	// Check API server health
+	if err := apiClient.HealthCheck(); err != nil {
+		var notReady *client.NotReadyError
+		if errors.As(err, &notReady) {
+			slog.Warn("API server not ready; results will not be saved", "url", r.opts.APIURL, "failing", strings.Join(notReady.Failing, "; "))
+		} else {
+			slog.Warn("API server not available; results will not be saved (start it with: tsuite api)", "url", r.opts.APIURL, "error", err)
+		}
+		apiClient = nil
+	} else {
+		// Refuse to record results a mismatched server would misread
+		if _, err := apiClient.CheckVersion(); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(r.out, "API Server: %s\n", r.opts.APIURL)
+	}
+
+	if apiClient != nil {
+		r.api = apiClient
+		r.createRun(apiClient)
+	}
+
+	if r.Parallel > 1 && len(r.Tests) > 1 {
+		r.orderLongestFirst(ctx)
+		// Leak checks must not count the other tests' ports and containers
+		r.Env = append(r.Env, runner.EnvParallel+"=1")
+	}
+
+	// Run tests
+	startTime := time.Now()
+	result := &Result{RunID: r.runID}
+
+	// Create context for cancellation
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+
+	stopSmokeBudget := r.startSmokeBudget()
+
+	if r.Mode == "docker" {
+		// Docker mode: use DockerExecutor which mounts Go runner into container
+		if r.Parallel > 1 && len(r.Tests) > 1 {
+			r.runParallelWithDocker(ctx, cancelFunc, apiClient, baseWorkdir, result)
+		} else {
+			r.runSequentialWithDocker(ctx, cancelFunc, apiClient, baseWorkdir, result)
+		}
+	} else {
+		// Standalone mode: use external runner binary
+		if r.Parallel > 1 && len(r.Tests) > 1 {
+			r.runParallelWithRunner(ctx, cancelFunc, r.runner.Path, baseWorkdir, result)
+		} else {
+			r.runSequentialWithRunner(ctx, cancelFunc, r.runner.Path, baseWorkdir, result)
+		}
+	}
+
+	stopSmokeBudget()
+	if !result.Cancelled {
+		result.SmokeStatus = r.smokeStatus(result)
+	}
+
+	// Complete or cancel run via API
+	if apiClient != nil && r.runID != "" {
+		if result.Cancelled {
+			if err := apiClient.CancelRun(r.runID); err != nil {
+				slog.Warn("Failed to mark run as cancelled", "run_id", r.runID, "error", err)
+			}
+		} else {
+			if err := apiClient.CompleteRun(r.runID, &client.CompleteRunRequest{
+				SmokeStatus:   result.SmokeStatus,
+				SmokeBudgetMS: r.SmokeBudget.Milliseconds(),
+			}); err != nil {
+				slog.Warn("Failed to complete run", "run_id", r.runID, "error", err)
+			}
+		}
+	}
+
+	result.Duration = time.Since(startTime)
+	r.mu.Lock()
+	result.SuggestedFixes = r.suggestedFixes
+	result.AssertionDiffs = r.assertionDiffs
+	result.LeakedResources = r.leakedResources
+	result.BudgetViolations = r.budgetViolations
+	r.mu.Unlock()
+	result.FailureOwners = r.failureOwners(result.FailedTests)
+
+	if path, err := r.writeManifest(baseWorkdir); err != nil {
+		slog.Warn("Failed to write run manifest", "error", err)
+	} else {
+		fmt.Fprintf(r.out, "Manifest: %s\n", path)
+	}
+	return result, nil
+}
+
+// checkDiskSpace refuses to start a run when the temp dir or ~/.tsuite is
+// below the suite's minimum free space, and warns when it is getting close
+func (r *Run) checkDiskSpace() error {
+	minMB := r.Config.Execution.MinFreeDiskMB
+	if minMB < 0 {
+		return nil
+	}
+	if minMB == 0 {
+		minMB = diskspace.DefaultMinFreeMB
+	}
+
+	if low := diskspace.Check(diskspace.Paths(), uint64(minMB)); len(low) > 0 {
+		return fmt.Errorf("not enough disk space to start the run: %s; free up space or lower execution.min_free_disk_mb", low[0])
+	}
+	for _, low := range diskspace.Check(diskspace.Paths(), 2*uint64(minMB)) {
+		fmt.Fprintf(r.out, "Warning: disk space is low: %d MB free on %s\n", low.FreeMB, low.Path)
+	}
+	return nil
+}
+
+// resolveRunner finds the runner binary for the run's mode and checks that it
+// was built for this version of tsuite
+func (r *Run) resolveRunner() error {
+	var path string
+	if r.Mode == "docker" {
+		// Tests overriding the image get the runner for that image's
+		// architecture when their container starts
+		image := r.dockerImage()
+		var err error
+		if path, _, err = runner.DockerRunnerBinary(image); err != nil {
+			return fmt.Errorf("failed to find runner binary for docker image %s: %w", image, err)
+		}
+	} else {
+		path = FindRunnerBinary(r.opts.RunnerPath)
+		if path == "" {
+			return fmt.Errorf("runner binary not found. Build it with: make build-runner")
+		}
+	}
+
+	info, err := runner.InspectRunner(path)
+	if err != nil {
+		return err
+	}
+	if err := info.CheckVersion(r.opts.Version); err != nil {
+		return err
+	}
+	r.runner = info
+	fmt.Fprintf(r.out, "Runner: %s (%s, sha256 %s)\n", info.Path, info.Version, info.SHA256[:12])
+	return nil
+}
+
+// createRun syncs the suite and creates the run via the API
+func (r *Run) createRun(apiClient *client.Client) {
+	// Sync suite to get suite_id
+	var suiteID int64
+	syncResp, err := apiClient.UpsertSuite(&client.SyncSuiteRequest{
+		FolderPath: r.SuitePath,
+		SuiteName:  r.Config.Suite.Name,
+		Mode:       r.Mode,
+		TestCount:  len(r.Tests),
+	})
+	if err != nil {
+		slog.Warn("Failed to sync suite", "path", r.SuitePath, "error", err)
+	} else if syncResp != nil {
+		suiteID = syncResp.ID
+	}
+
+	// Build test info for API
+	testInfos := make([]client.TestInfo, len(r.Tests))
+	for i, testID := range r.Tests {
+		parts := strings.Split(testID, "/")
+		testInfos[i] = client.TestInfo{
+			TestID:   testID,
+			UseCase:  parts[0],
+			TestCase: parts[1],
+		}
+		testConfig, err := config.LoadTestConfig(filepath.Join(r.SuitePath, "suites", parts[0], parts[1]))
+		if err == nil {
+			testInfos[i].Name = testConfig.Name
+			testInfos[i].Tags = testConfig.Tags
+			testInfos[i].Covers = testConfig.Covers
+		}
+		testInfos[i].Owners = r.testOwners(testID, testConfig)
+	}
+
+	// Build display name
+	displayName := r.Config.Suite.Name
+	if len(r.Tests) == 1 {
+		// Single test - include test name in display
+		displayName = r.Config.Suite.Name + " / " + r.Tests[0]
+	}
+
+	resp, err := apiClient.CreateRun(&client.CreateRunRequest{
+		RunID:         r.opts.RunID,
+		SuiteID:       suiteID,
+		SuiteName:     r.Config.Suite.Name,
+		DisplayName:   displayName,
+		CLIVersion:    r.opts.Version,
+		TotalTests:    len(r.Tests),
+		Mode:          r.Mode,
+		Tests:         testInfos,
+		ParentRunID:   r.opts.ParentRunID,
+		PipelineRunID: r.opts.PipelineRunID,
+		RunnerVersion: r.runner.Version,
+		RunnerSHA256:  r.runner.SHA256,
+		Workers:       min(r.Parallel, len(r.Tests)), // Workers beyond the test count stay idle
+
+		SDKPythonVersion:     r.sdk.Python,
+		SDKTypescriptVersion: r.sdk.Typescript,
+		Profile:              r.opts.Profile,
+	})
+	if err != nil {
+		slog.Warn("Failed to create run", "error", err)
+		return
+	}
+
+	r.runID = resp.RunID
+	if r.opts.ParentRunID != "" {
+		fmt.Fprintf(r.out, "Run ID: %s (rerun of %s)\n", r.runID[:12], r.opts.ParentRunID[:12])
+	} else {
+		fmt.Fprintf(r.out, "Run ID: %s\n", r.runID[:12])
+	}
+}
+
+// recordSuggestions stores suggested fixes for a failed test
+func (r *Run) recordSuggestions(testID string, suggestions []string) {
+	if len(suggestions) == 0 {
+		return
+	}
+	r.mu.Lock()
+	r.suggestedFixes[testID] = suggestions
+	r.mu.Unlock()
+}
+
+// recordOutput stores what the runner reported for a test: leaks, budget
+// violations and recorded values, and if the test failed its suggested fixes
+// and assertion diffs
+func (r *Run) recordOutput(testID string, out runner.Output, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(out.Leaks) > 0 {
+		r.leakedResources[testID] = out.Leaks
+	}
+	if out.BudgetViolation != "" {
+		r.budgetViolations[testID] = out.BudgetViolation
+	}
+	for key, value := range out.Recorded {
+		if r.recorded[key] == nil {
+			r.recorded[key] = make(map[string]bool)
+		}
+		r.recorded[key][value] = true
+	}
+	if !failed {
+		return
+	}
+	if len(out.Suggestions) > 0 {
+		r.suggestedFixes[testID] = out.Suggestions
+	}
+	if len(out.AssertionDiffs) > 0 {
+		r.assertionDiffs[testID] = out.AssertionDiffs
+	}
+}
+
+// PrintSummary prints the totals, failed tests with their assertion diffs and
+// suggested fixes, leaks and budget violations
+func (res *Result) PrintSummary(w io.Writer) {
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	if res.Cancelled {
+		fmt.Fprintf(w, "CANCELLED: %d passed, %d failed, %d skipped (%.1fs)\n", res.Passed, res.Failed, res.Skipped, res.Duration.Seconds())
+	} else {
+		if res.Skipped > 0 {
+			fmt.Fprintf(w, "SUMMARY: %d passed, %d failed, %d skipped (%.1fs)\n", res.Passed, res.Failed, res.Skipped, res.Duration.Seconds())
+		} else {
+			fmt.Fprintf(w, "SUMMARY: %d passed, %d failed (%.1fs)\n", res.Passed, res.Failed, res.Duration.Seconds())
+		}
+	}
+	switch res.SmokeStatus {
+	case SmokePassed:
+		fmt.Fprintln(w, "SMOKE: passed")
+	case SmokeFailed:
+		fmt.Fprintln(w, "SMOKE: failed")
+	case SmokeOverBudget:
+		fmt.Fprintln(w, "SMOKE: over budget (tests stopped when the time budget ran out)")
+	}
+	if len(res.FailedTests) > 0 {
+		fmt.Fprintln(w, "\nFailed tests:")
+		for _, t := range res.FailedTests {
+			fmt.Fprintf(w, "  ✗ %s%s\n", t, formatOwners(res.FailureOwners[t]))
+			for _, line := range res.AssertionDiffs[t] {
+				fmt.Fprintf(w, "      %s\n", line)
+			}
+			for _, suggestion := range res.SuggestedFixes[t] {
+				fmt.Fprintf(w, "      → %s\n", suggestion)
+			}
+		}
+	}
+	if len(res.LeakedResources) > 0 {
+		fmt.Fprintln(w, "\nLeaks (resources left after post_run):")
+		leakedTests := make([]string, 0, len(res.LeakedResources))
+		for t := range res.LeakedResources {
+			leakedTests = append(leakedTests, t)
+		}
+		sort.Strings(leakedTests)
+		for _, t := range leakedTests {
+			fmt.Fprintf(w, "  ! %s\n", t)
+			for _, leak := range res.LeakedResources[t] {
+				fmt.Fprintf(w, "      %s\n", leak)
+			}
+		}
+	}
+	if len(res.BudgetViolations) > 0 {
+		fmt.Fprintln(w, "\nBudget violations (duration_budget_ms):")
+		slowTests := make([]string, 0, len(res.BudgetViolations))
+		for t := range res.BudgetViolations {
+			slowTests = append(slowTests, t)
+		}
+		sort.Strings(slowTests)
+		for _, t := range slowTests {
+			fmt.Fprintf(w, "  ⚠ %s: %s\n", t, res.BudgetViolations[t])
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+}
@@ -0,0 +1,44 @@
+package orchestrator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// testOwners returns who owns a test: owners in its test.yaml (testConfig,
+// nil if it did not load), else owners by test ID in config.yaml, else by
+// use case
+func (r *Run) testOwners(testID string, testConfig *config.TestConfig) []string {
+	if testConfig != nil && len(testConfig.Owners) > 0 {
+		return testConfig.Owners
+	}
+	if owners, ok := r.Config.Owners[testID]; ok {
+		return owners
+	}
+	ucName, _, _ := strings.Cut(testID, "/")
+	return r.Config.Owners[ucName]
+}
+
+// failureOwners returns the owners of each failed test that has any
+func (r *Run) failureOwners(failedTests []string) map[string][]string {
+	owners := make(map[string][]string)
+	for _, testID := range failedTests {
+		ucName, tcName, _ := strings.Cut(testID, "/")
+		testConfig, _ := config.LoadTestConfig(filepath.Join(r.SuitePath, "suites", ucName, tcName))
+		if o := r.testOwners(testID, testConfig); len(o) > 0 {
+			owners[testID] = o
+		}
+	}
+	return owners
+}
+
+// formatOwners formats owners for the summary, e.g. " (owners: a, b)"
+func formatOwners(owners []string) string {
+	if len(owners) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (owners: %s)", strings.Join(owners, ", "))
+}
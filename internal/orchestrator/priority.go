@@ -0,0 +1,81 @@
+package orchestrator
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// Test priorities, in the order tests run
+const (
+	priorityHigh = iota
+	priorityNormal
+	priorityLow
+)
+
+var priorityNames = map[string]int{
+	"high":   priorityHigh,
+	"normal": priorityNormal,
+	"low":    priorityLow,
+}
+
+// loadPriorities reads the priority of each selected test: priority in its
+// test.yaml, else execution.priorities by test ID, else by use case
+func (r *Run) loadPriorities() error {
+	r.priorities = make(map[string]int)
+	for _, testID := range r.Tests {
+		name, source := "", ""
+		ucName, tcName, _ := strings.Cut(testID, "/")
+		if testConfig, err := config.LoadTestConfig(filepath.Join(r.SuitePath, "suites", ucName, tcName)); err == nil && testConfig.Priority != "" {
+			name, source = testConfig.Priority, testID+"/test.yaml"
+		} else if p, ok := r.Config.Execution.Priorities[testID]; ok {
+			name, source = p, "execution.priorities."+testID
+		} else if p, ok := r.Config.Execution.Priorities[ucName]; ok {
+			name, source = p, "execution.priorities."+ucName
+		}
+		if name == "" {
+			continue
+		}
+		priority, ok := priorityNames[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("invalid priority %q in %s: must be high, normal or low", name, source)
+		}
+		if priority != priorityNormal {
+			r.priorities[testID] = priority
+		}
+	}
+	return nil
+}
+
+// orderByPriority moves high priority tests to the front and low priority
+// tests to the end, keeping the order within each priority
+func (r *Run) orderByPriority() {
+	if len(r.priorities) == 0 {
+		return
+	}
+	slices.SortStableFunc(r.Tests, func(a, b string) int {
+		return r.testPriority(a) - r.testPriority(b)
+	})
+}
+
+// testPriority returns the priority of a selected test
+func (r *Run) testPriority(testID string) int {
+	if p, ok := r.priorities[testID]; ok {
+		return p
+	}
+	return priorityNormal
+}
+
+// countPriority returns the number of selected tests with a priority
+func (r *Run) countPriority(priority int) int {
+	n := 0
+	for _, testID := range r.Tests {
+		if r.testPriority(testID) == priority {
+			n++
+		}
+	}
+	return n
+}
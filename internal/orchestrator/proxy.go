@@ -0,0 +1,90 @@
+package orchestrator
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+)
+
+// caBundleMountPath is where containers have the suite's proxy.ca_bundle
+const caBundleMountPath = "/etc/tsuite/ca-bundle.pem"
+
+// proxyEnv returns the proxy variables of the run's tests: config.yaml's
+// proxy, else the proxy tsuite run has, in both spellings tools read. Tests
+// always reach localhost, the API server and, from containers, the host
+// directly. Nothing is set without a proxy.
+func (r *Run) proxyEnv() map[string]string {
+	proxy := r.Config.Proxy
+	httpProxy := firstEnv(proxy.HTTPProxy, "HTTP_PROXY", "http_proxy")
+	httpsProxy := firstEnv(proxy.HTTPSProxy, "HTTPS_PROXY", "https_proxy")
+	if httpProxy == "" && httpsProxy == "" {
+		return nil
+	}
+
+	noProxy := []string{"localhost", "127.0.0.1", "::1"}
+	if r.Mode == "docker" {
+		noProxy = append(noProxy, "host.docker.internal")
+	}
+	if u, err := url.Parse(r.opts.APIURL); err == nil && u.Hostname() != "" {
+		noProxy = append(noProxy, u.Hostname())
+	}
+	noProxy = append(noProxy, proxy.NoProxy...)
+	for _, host := range strings.Split(firstEnv("", "NO_PROXY", "no_proxy"), ",") {
+		noProxy = append(noProxy, strings.TrimSpace(host))
+	}
+	var hosts []string
+	for _, host := range noProxy {
+		if host != "" && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	env := make(map[string]string)
+	for name, value := range map[string]string{"HTTP_PROXY": httpProxy, "HTTPS_PROXY": httpsProxy, "NO_PROXY": strings.Join(hosts, ",")} {
+		if value != "" {
+			env[name] = value
+			env[strings.ToLower(name)] = value
+		}
+	}
+	return env
+}
+
+// useCABundle hands the suite's proxy.ca_bundle to the tests, mounted in
+// containers, and returns the TSUITE_CA_BUNDLE runners read it from; "" if
+// the suite has none
+func (r *Run) useCABundle() (string, error) {
+	bundle := r.Config.Proxy.CABundle
+	if bundle == "" {
+		return "", nil
+	}
+	path := r.sourcePath(bundle)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("proxy.ca_bundle: %w", err)
+	}
+	if r.Mode == "docker" {
+		r.mounts = append(r.mounts, runner.MountConfig{
+			Type:          "host",
+			HostPath:      path,
+			ContainerPath: caBundleMountPath,
+			ReadOnly:      true,
+		})
+		path = caBundleMountPath
+	}
+	return path, nil
+}
+
+// firstEnv returns value, else the first of the environment variables that
+// is set
+func firstEnv(value string, names ...string) string {
+	for _, name := range names {
+		if value != "" {
+			break
+		}
+		value = os.Getenv(name)
+	}
+	return value
+}
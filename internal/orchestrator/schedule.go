@@ -0,0 +1,84 @@
+package orchestrator
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/db"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/executor"
+)
+
+// scheduleHistoryRuns is the number of recent runs whose durations order the
+// tests of a parallel run
+const scheduleHistoryRuns = 20
+
+// orderLongestFirst sorts the tests of a parallel run by their average
+// duration in previous runs, longest first, so a long test doesn't start last
+// and run alone after the other workers are done. Tests without history are
+// estimated at the median of the known ones. Without any history the
+// directory order is kept. Tests of higher priority still run first.
+func (r *Run) orderLongestFirst(ctx context.Context) {
+	history := r.durationHistory(ctx)
+	if len(history) == 0 {
+		slog.Debug("No duration history, scheduling tests in directory order")
+		return
+	}
+
+	planned, fallback := executor.EstimateTests(r.Tests, history, 0)
+	sorted := executor.SortLongestFirst(planned)
+
+	slog.Debug("Scheduling tests longest first",
+		"tests", len(planned),
+		"with_history", len(planned)-countUnknown(planned),
+		"default_estimate", fallback.Round(time.Millisecond),
+		"predicted_wall_clock", executor.SimulateSchedule(sorted, r.Parallel).WallClock.Round(time.Millisecond),
+		"directory_order_wall_clock", executor.SimulateSchedule(planned, r.Parallel).WallClock.Round(time.Millisecond))
+	estimates := make(map[string]executor.PlannedTest, len(sorted))
+	for i, t := range sorted {
+		r.Tests[i] = t.TestID
+		estimates[t.TestID] = t
+	}
+	r.orderByPriority()
+	for i, testID := range r.Tests {
+		t := estimates[testID]
+		slog.Debug("Scheduled test", "position", i+1, "test_id", testID,
+			"estimate", t.Estimate.Round(time.Millisecond), "from_history", t.HasHistory)
+	}
+}
+
+// durationHistory returns the average duration of the suite's tests in recent
+// runs, read from the local results database. Runs never create it.
+func (r *Run) durationHistory(ctx context.Context) map[string]time.Duration {
+	if _, err := os.Stat(db.DefaultDBPath()); err != nil {
+		return nil
+	}
+	repo, err := db.NewRepository()
+	if err != nil {
+		slog.Debug("Failed to open results database for scheduling", "error", err)
+		return nil
+	}
+	stats, err := repo.GetTestDurationStats(ctx, r.SuitePath, scheduleHistoryRuns)
+	if err != nil {
+		slog.Debug("Failed to load test durations for scheduling", "error", err)
+		return nil
+	}
+
+	history := make(map[string]time.Duration, len(stats))
+	for id, stat := range stats {
+		history[id] = time.Duration(stat.AvgMS) * time.Millisecond
+	}
+	return history
+}
+
+// countUnknown returns the number of tests estimated without history
+func countUnknown(tests []executor.PlannedTest) int {
+	n := 0
+	for _, t := range tests {
+		if !t.HasHistory {
+			n++
+		}
+	}
+	return n
+}
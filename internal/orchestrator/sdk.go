@@ -0,0 +1,53 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+)
+
+// sdkVersions returns the mcp-mesh SDK versions the run tests: those pinned
+// in config.yaml packages, else those installed in the image (docker mode) or
+// on the host (standalone mode)
+func (r *Run) sdkVersions(ctx context.Context) runner.SDKVersions {
+	versions := runner.SDKVersions{
+		Python:     r.Config.Packages.SDKPythonVersion,
+		Typescript: r.Config.Packages.SDKTypescriptVersion,
+	}
+	if versions.Python != "" && versions.Typescript != "" {
+		return versions
+	}
+
+	var detected runner.SDKVersions
+	if r.Mode == "docker" {
+		detected = runner.DetectImageSDKVersions(ctx, r.dockerImage())
+	} else {
+		detected = runner.DetectSDKVersions(ctx, r.SuitePath)
+	}
+	slog.Debug("Detected SDK versions", "python", detected.Python, "typescript", detected.Typescript)
+
+	if versions.Python == "" {
+		versions.Python = detected.Python
+	}
+	if versions.Typescript == "" {
+		versions.Typescript = detected.Typescript
+	}
+	return versions
+}
+
+// printSDKVersions prints the SDK versions recorded with the run, if any
+func (r *Run) printSDKVersions(versions runner.SDKVersions) {
+	var sdks []string
+	if versions.Python != "" {
+		sdks = append(sdks, runner.SDKPythonPackage+" "+versions.Python)
+	}
+	if versions.Typescript != "" {
+		sdks = append(sdks, runner.SDKTypescriptPackage+" "+versions.Typescript)
+	}
+	if len(sdks) > 0 {
+		fmt.Fprintf(r.out, "SDK: %s\n", strings.Join(sdks, ", "))
+	}
+}
@@ -0,0 +1,62 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+)
+
+// Smoke statuses recorded on the run
+const (
+	SmokePassed     = "passed"
+	SmokeFailed     = "failed"
+	SmokeOverBudget = "over_budget"
+)
+
+// startSmokeBudget enforces the time budget of a smoke run: once it runs
+// out, running tests are stopped and the rest skipped. The returned func
+// stops the timer.
+func (r *Run) startSmokeBudget() func() {
+	if !r.Smoke {
+		return func() {}
+	}
+	timer := time.AfterFunc(r.SmokeBudget, r.exceedSmokeBudget)
+	return func() { timer.Stop() }
+}
+
+// exceedSmokeBudget stops a smoke run that ran out of time
+func (r *Run) exceedSmokeBudget() {
+	r.mu.Lock()
+	r.smokeExceeded = true
+	fmt.Fprintf(r.out, "\n[SMOKE] Time budget of %s exceeded, stopping the run\n", r.SmokeBudget)
+	for _, cancel := range r.testCancels {
+		cancel()
+	}
+	r.mu.Unlock()
+
+	r.stopStarting(stopReason{
+		reason:    "smoke time budget exceeded",
+		apiReason: fmt.Sprintf("Not run: smoke time budget of %s exceeded", r.SmokeBudget),
+	})
+}
+
+// smokeBudgetExceeded reports whether a smoke run ran out of time
+func (r *Run) smokeBudgetExceeded() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.smokeExceeded
+}
+
+// smokeStatus returns the status of a finished smoke run, or "" if the run
+// was not a smoke run
+func (r *Run) smokeStatus(res *Result) string {
+	switch {
+	case !r.Smoke:
+		return ""
+	case r.smokeBudgetExceeded():
+		return SmokeOverBudget
+	case res.Failed > 0:
+		return SmokeFailed
+	default:
+		return SmokePassed
+	}
+}
@@ -0,0 +1,285 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/executor"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/runner"
+)
+
+// FindRunnerBinary finds the tsuite-runner binary
+// It looks for the runner binary in the following locations:
+// 1. Explicit path (--runner-path)
+// 2. Same directory as the current executable
+// 3. Runner embedded in tsuite, extracted to ~/.tsuite/bin
+// 4. Current working directory
+// Returns the path to the runner binary, or empty string if not found
+func FindRunnerBinary(explicit string) string {
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err == nil {
+			return explicit
+		}
+	}
+
+	// Get current executable's directory
+	execPath, err := os.Executable()
+	if err == nil {
+		execPath, _ = filepath.EvalSymlinks(execPath)
+		execDir := filepath.Dir(execPath)
+
+		// Look for tsuite-runner in the same directory
+		candidates := []string{
+			filepath.Join(execDir, "tsuite-runner"),
+			filepath.Join(execDir, fmt.Sprintf("tsuite-runner-%s-%s", runtime.GOOS, runtime.GOARCH)),
+		}
+
+		for _, path := range candidates {
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+
+	// Extract the runner embedded in tsuite
+	if path, err := runner.ExtractEmbeddedRunner(runtime.GOOS, runtime.GOARCH); err != nil {
+		slog.Warn("Failed to extract embedded runner", "error", err)
+	} else if path != "" {
+		return path
+	}
+
+	// Look in current working directory
+	cwd, err := os.Getwd()
+	if err == nil {
+		candidates := []string{
+			filepath.Join(cwd, "bin", "tsuite-runner"),
+			filepath.Join(cwd, "tsuite-runner"),
+		}
+		for _, path := range candidates {
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+
+	return ""
+}
+
+// runTestWithRunner executes a single test using the external runner binary.
+// The runner reports results directly to the API, so we just need to wait for completion.
+func (r *Run) runTestWithRunner(ctx context.Context, runnerBinary, testID, baseWorkdir string) (result executor.TestResult) {
+	startTime := time.Now()
+
+	// Check if already cancelled
+	select {
+	case <-ctx.Done():
+		return executor.TestResult{TestID: testID, Error: "cancelled", Cancelled: true}
+	default:
+	}
+
+	defer func() {
+		if !result.Passed && !result.Skipped && !result.Cancelled {
+			r.keepWorkdir(testID, baseWorkdir)
+		}
+	}()
+
+	// Build command arguments
+	args := []string{
+		"--suite-path", r.SuitePath,
+		"--test-id", testID,
+	}
+	if r.opts.APIURL != "" {
+		args = append(args, "--api-url", r.opts.APIURL)
+	}
+	if r.opts.UpdateSnapshots {
+		args = append(args, "--update-snapshots")
+	}
+	if r.runID != "" {
+		args = append(args, "--run-id", r.runID)
+
+		// Set log directory for unified logging (standalone mode)
+		// Structure: ~/.tsuite/runs/{run_id}/{uc}/{tc}/
+		parts := strings.SplitN(testID, "/", 2)
+		if len(parts) == 2 {
+			logDir := filepath.Join(os.Getenv("HOME"), ".tsuite", "runs", r.runID, parts[0], parts[1])
+			os.MkdirAll(logDir, 0755)
+			args = append(args, "--log-dir", logDir)
+		}
+	}
+	if baseWorkdir != "" {
+		testWorkdir := filepath.Join(baseWorkdir, strings.ReplaceAll(testID, "/", "_"))
+		os.MkdirAll(testWorkdir, 0755)
+		args = append(args, "--workdir", testWorkdir)
+	}
+
+	// Create command with combined timeout and cancellation context
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, testTimeout)
+	defer timeoutCancel()
+
+	slog.Debug("Starting runner", "test_id", testID, "binary", runnerBinary, "args", args)
+	cmd := exec.CommandContext(timeoutCtx, runnerBinary, args...)
+	// The run's env is passed explicitly, as other runs may share this process
+	cmd.Env = append(os.Environ(), r.Env...)
+	// Set process group so we can kill the whole tree
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	// On timeout or cancel, ask the runner to stop so post_run still executes,
+	// and kill it only if it does not exit within the grace period
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = runner.CancelGracePeriod + 5*time.Second
+
+	// Capture output
+	output, err := cmd.CombinedOutput()
+	duration := time.Since(startTime)
+	slog.Debug("Runner exited", "test_id", testID, "duration_ms", duration.Milliseconds(), "error", err)
+
+	// Check if cancelled (parent context)
+	if ctx.Err() == context.Canceled {
+		return executor.TestResult{TestID: testID, Error: "cancelled", Duration: duration, Cancelled: true}
+	}
+
+	if timeoutCtx.Err() == context.DeadlineExceeded {
+		return executor.TestResult{TestID: testID, Error: "test timed out", Duration: duration}
+	}
+
+	reported := runner.ParseOutput(string(output))
+	r.recordOutput(testID, reported, err != nil)
+
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			// Runner exited with non-zero status (test failed)
+			// Extract error from output
+			errMsg := "test failed"
+			lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+			for _, line := range lines {
+				if strings.HasPrefix(line, "FAILED:") {
+					errMsg = strings.TrimPrefix(line, "FAILED: ")
+					break
+				}
+				if strings.HasPrefix(line, "Error:") {
+					errMsg = strings.TrimPrefix(line, "Error: ")
+					break
+				}
+			}
+			if errMsg == "test failed" && len(lines) > 0 {
+				// Use last line as error
+				errMsg = lines[len(lines)-1]
+			}
+			return executor.TestResult{TestID: testID, Error: errMsg, Duration: duration}
+		}
+		return executor.TestResult{TestID: testID, Error: fmt.Sprintf("runner error: %v", err), Duration: duration}
+	}
+
+	// Runner exits 0 for tests skipped by skip_if
+	if reported.Skipped {
+		return executor.TestResult{TestID: testID, Skipped: true, SkipReason: reported.SkipReason, Duration: duration}
+	}
+
+	return executor.TestResult{TestID: testID, Passed: true, Duration: duration}
+}
+
+// runSequentialWithRunner runs tests sequentially using the external runner binary
+func (r *Run) runSequentialWithRunner(ctx context.Context, cancelFunc context.CancelFunc, runnerBinary, baseWorkdir string, res *Result) {
+	apiClient := client.NewClient(r.opts.APIURL)
+
+	// Start cancel checker goroutine
+	executor.StartCancelChecker(ctx, cancelFunc, apiClient, r.runID, r.cancelTest, r.out)
+
+	for _, testID := range r.Tests {
+		// Check if cancelled before starting test
+		select {
+		case <-ctx.Done():
+			fmt.Fprintf(r.out, "[SKIP] %s (cancelled)\n", testID)
+			res.Skipped++
+			res.Cancelled = true
+			continue
+		default:
+		}
+
+		result := r.runTest(ctx, testID, func(ctx context.Context) executor.TestResult {
+			fmt.Fprintf(r.out, "\n[RUN] %s\n", testID)
+			return r.runTestWithRunner(ctx, runnerBinary, testID, baseWorkdir)
+		})
+
+		if result.Cancelled {
+			fmt.Fprintf(r.out, "[SKIP] %s (cancelled)\n", testID)
+			res.Skipped++
+			res.Cancelled = true
+		} else if result.Skipped {
+			fmt.Fprintf(r.out, "[SKIP] %s (%s)\n", testID, result.SkipReason)
+			res.Skipped++
+		} else if result.Passed {
+			fmt.Fprintf(r.out, "[PASS] %s (%.1fs)\n", testID, result.Duration.Seconds())
+			res.Passed++
+		} else {
+			fmt.Fprintf(r.out, "[FAIL] %s - %s (%.1fs)\n", testID, result.Error, result.Duration.Seconds())
+			res.Failed++
+			res.FailedTests = append(res.FailedTests, testID)
+		}
+	}
+}
+
+// runParallelWithRunner runs tests in parallel using the external runner binary
+func (r *Run) runParallelWithRunner(ctx context.Context, cancelFunc context.CancelFunc, runnerBinary, baseWorkdir string, res *Result) {
+	testCh := make(chan string, len(r.Tests))
+	resultCh := make(chan executor.TestResult, len(r.Tests))
+	apiClient := client.NewClient(r.opts.APIURL)
+
+	// Start cancel checker goroutine
+	executor.StartCancelChecker(ctx, cancelFunc, apiClient, r.runID, r.cancelTest, r.out)
+
+	// Start workers
+	var wg sync.WaitGroup
+	for i := 0; i < r.Parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for testID := range testCh {
+				// Check if cancelled before starting test
+				select {
+				case <-ctx.Done():
+					resultCh <- executor.TestResult{TestID: testID, Cancelled: true}
+					continue
+				default:
+				}
+
+				if err := r.resources.acquire(ctx); err != nil {
+					resultCh <- executor.TestResult{TestID: testID, Cancelled: true}
+					continue
+				}
+				resultCh <- r.runTest(ctx, testID, func(ctx context.Context) executor.TestResult {
+					return r.runTestWithRunner(ctx, runnerBinary, testID, baseWorkdir)
+				})
+				r.resources.release()
+			}
+		}()
+	}
+
+	// Send tests to workers
+	for _, t := range r.Tests {
+		testCh <- t
+	}
+	close(testCh)
+
+	// Wait for all workers
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	// Collect results
+	results := executor.CollectResults(resultCh, r.out)
+	res.Passed, res.Failed, res.Skipped = results.Passed, results.Failed, results.Skipped
+	res.FailedTests, res.Cancelled = results.FailedTests, results.Cancelled
+}
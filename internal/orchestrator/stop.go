@@ -0,0 +1,53 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/executor"
+)
+
+// stopReason is why a run starts no more tests
+type stopReason struct {
+	reason    string // Printed with each skipped test
+	apiReason string // Recorded as the skipped tests' skip reason
+	notice    string // Printed before the first skipped test, if set
+}
+
+// stopStarting makes runTest skip the tests that have not started yet.
+// Running tests are not affected. The first reason given is kept.
+func (r *Run) stopStarting(stop stopReason) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped == nil {
+		r.stopped = &stop
+	}
+}
+
+// stoppedSkip returns the skipped result of a test not started after
+// stopStarting
+func (r *Run) stoppedSkip(testID string) (executor.TestResult, bool) {
+	r.mu.Lock()
+	stop := r.stopped
+	if stop != nil && stop.notice != "" {
+		fmt.Fprint(r.out, stop.notice)
+		stop.notice = ""
+	}
+	r.mu.Unlock()
+	if stop == nil {
+		return executor.TestResult{}, false
+	}
+	return r.skipTest(testID, stop.reason, stop.apiReason, 0), true
+}
+
+// recordFailure stops a FailFast run after its first failed test; running
+// tests finish
+func (r *Run) recordFailure(testID string) {
+	if !r.FailFast {
+		return
+	}
+	r.stopStarting(stopReason{
+		reason:    "fail-fast",
+		apiReason: fmt.Sprintf("Not run: %s failed (--fail-fast)", testID),
+		notice:    fmt.Sprintf("\n[FAIL-FAST] %s failed, not starting the remaining tests\n", testID),
+	})
+}
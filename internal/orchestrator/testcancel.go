@@ -0,0 +1,93 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/executor"
+)
+
+// cancelTest stops a test cancelled on its own from the dashboard. It is the
+// cancel checker's onTestCancel callback; the rest of the run continues.
+func (r *Run) cancelTest(testID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancelledTests[testID] = true
+	if cancel, ok := r.testCancels[testID]; ok {
+		fmt.Fprintf(r.out, "\n[CANCEL] Cancel requested for %s - stopping test...\n", testID)
+		cancel()
+	}
+}
+
+// testCancelled reports whether a test was cancelled on its own
+func (r *Run) testCancelled(testID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cancelledTests[testID]
+}
+
+// runTest runs a test with a context that cancelTest cancels. A test
+// cancelled before it starts, or after the run stopped starting tests
+// (--fail-fast, smoke time budget), is not run. One interrupted by its cancel
+// is skipped without cancelling the run; one stopped by the smoke time budget
+// fails.
+func (r *Run) runTest(ctx context.Context, testID string, run func(ctx context.Context) executor.TestResult) executor.TestResult {
+	if r.testCancelled(testID) {
+		return r.skipTest(testID, "cancelled", testCancelledReason, 0)
+	}
+	if result, skipped := r.stoppedSkip(testID); skipped {
+		return result
+	}
+
+	testCtx, cancel := context.WithCancel(ctx)
+	r.mu.Lock()
+	r.testCancels[testID] = cancel
+	if r.smokeExceeded {
+		cancel()
+	}
+	r.mu.Unlock()
+
+	result := run(testCtx)
+
+	r.mu.Lock()
+	delete(r.testCancels, testID)
+	r.mu.Unlock()
+	cancel()
+
+	if result.Cancelled && ctx.Err() == nil {
+		if r.testCancelled(testID) {
+			return r.skipTest(testID, "cancelled", testCancelledReason, result.Duration)
+		}
+		if r.smokeBudgetExceeded() {
+			return executor.TestResult{TestID: testID, Error: "stopped: smoke time budget exceeded", Duration: result.Duration}
+		}
+	}
+	if !result.Passed && !result.Skipped && !result.Cancelled {
+		r.recordFailure(testID)
+	}
+	return result
+}
+
+// testCancelledReason is the skip reason recorded for tests cancelled on
+// their own
+const testCancelledReason = "Cancelled"
+
+// skipTest reports a test the CLI skipped to the API, as its runner was not
+// started or was stopped before reporting. reason is printed, apiReason
+// recorded with the test.
+func (r *Run) skipTest(testID, reason, apiReason string, duration time.Duration) executor.TestResult {
+	if r.api != nil {
+		durationMS := duration.Milliseconds()
+		if err := r.api.UpdateTestStatus(r.runID, testID, &client.UpdateTestStatusRequest{
+			Status:     "skipped",
+			DurationMS: &durationMS,
+			SkipReason: apiReason,
+		}); err != nil {
+			slog.Debug("Failed to report skipped test", "test_id", testID, "error", err)
+		}
+	}
+	return executor.TestResult{TestID: testID, Skipped: true, SkipReason: reason, Duration: duration}
+}
@@ -0,0 +1,106 @@
+package orchestrator
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/client"
+)
+
+// keepWorkdir moves the workdir of a failed test out of the run's temp
+// workdir, which is removed when the run ends, to
+// ~/.tsuite/runs/{run_id}/{uc}/{tc}/workspace, and records the path on the
+// test result
+func (r *Run) keepWorkdir(testID, baseWorkdir string) {
+	if !r.KeepWorkdir || baseWorkdir == "" {
+		return
+	}
+	src := filepath.Join(baseWorkdir, strings.ReplaceAll(testID, "/", "_"))
+	if _, err := os.Stat(src); err != nil {
+		return
+	}
+
+	dst := filepath.Join(r.runDir(baseWorkdir), filepath.FromSlash(testID), "workspace")
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		slog.Warn("Failed to keep workdir", "test_id", testID, "error", err)
+		return
+	}
+	os.RemoveAll(dst)
+
+	// The temp dir may be on another filesystem; copy if it can't be moved
+	if err := os.Rename(src, dst); err != nil {
+		if err := copyTree(src, dst); err != nil {
+			slog.Warn("Failed to keep workdir", "test_id", testID, "error", err)
+			return
+		}
+	}
+	fmt.Fprintf(r.out, "[KEEP] %s workdir kept at %s\n", testID, dst)
+
+	if r.api != nil && r.runID != "" {
+		r.api.UpdateTestStatus(r.runID, testID, &client.UpdateTestStatusRequest{WorkspacePath: dst})
+	}
+}
+
+// runDir returns the run's directory under ~/.tsuite/runs, named by its
+// run ID. Runs without an API server have none and use the temp workdir's
+// name.
+func (r *Run) runDir(baseWorkdir string) string {
+	name := r.runID
+	if name == "" {
+		name = filepath.Base(baseWorkdir)
+	}
+	return filepath.Join(os.Getenv("HOME"), ".tsuite", "runs", name)
+}
+
+// copyTree copies a directory tree, keeping file modes and symlinks
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		return nil // Sockets, pipes and devices are not kept
+	})
+}
+
+// copyFile copies a single file
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
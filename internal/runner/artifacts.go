@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// SetArtifactDir sets the directory step artifacts are collected into. It is
+// normally <log-dir>/artifacts, i.e. ~/.tsuite/runs/{run_id}/{uc}/{tc}/artifacts.
+// Without an artifact directory, step artifacts are not collected.
+func (r *TestRunner) SetArtifactDir(dir string) {
+	r.artifactDir = dir
+}
+
+// collectArtifacts copies files matching the step's artifact patterns into
+// <artifactDir>/<phase>-<index>/ and returns their paths relative to
+// artifactDir. Patterns are globs relative to the step workdir. Problems are
+// returned as warnings and never fail the step.
+func (r *TestRunner) collectArtifacts(patterns []string, workdir string, ctx *interpolate.Context, phase string, index int) (paths []string, warnings []string) {
+	if r.artifactDir == "" || len(patterns) == 0 {
+		return nil, nil
+	}
+
+	stepDir := fmt.Sprintf("%s-%d", phase, index)
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		pattern, err := interpolate.Interpolate(pattern, ctx)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("artifact pattern %q: %v", pattern, err))
+			continue
+		}
+		absPattern := pattern
+		if !filepath.IsAbs(absPattern) {
+			absPattern = filepath.Join(workdir, pattern)
+		}
+
+		matches, err := filepath.Glob(absPattern)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("artifact pattern %q: %v", pattern, err))
+			continue
+		}
+		if len(matches) == 0 {
+			warnings = append(warnings, fmt.Sprintf("artifact pattern %q matched no files", pattern))
+			continue
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+
+			// Keep the path relative to the workdir; files outside it keep their name
+			rel, err := filepath.Rel(workdir, match)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				rel = filepath.Base(match)
+			}
+			dest := filepath.Join(stepDir, rel)
+			if seen[dest] {
+				continue
+			}
+			seen[dest] = true
+
+			if err := copyArtifact(match, filepath.Join(r.artifactDir, dest)); err != nil {
+				warnings = append(warnings, fmt.Sprintf("artifact %s: %v", rel, err))
+				continue
+			}
+			paths = append(paths, filepath.ToSlash(dest))
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, warnings
+}
+
+// copyArtifact copies a single file, creating parent directories
+func copyArtifact(src, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// errNotJoined is why background steps no wait_for joined were stopped
+var errNotJoined = errors.New("background step not joined by wait_for")
+
+// backgroundStep is a step started with background: true
+type backgroundStep struct {
+	step   config.Step
+	cancel context.CancelCauseFunc // Stops the step
+	done   chan struct{}
+	result StepResult // Set before done is closed
+}
+
+// validateBackgroundSteps checks that background steps can be joined: they
+// need a capture name and a handler, and wait_for steps do nothing else
+func validateBackgroundSteps(testConfig *config.TestConfig) error {
+	for _, steps := range [][]config.Step{testConfig.PreRun, testConfig.Test, testConfig.PostRun} {
+		for _, step := range steps {
+			switch {
+			case step.Background && step.Routine != "":
+				return fmt.Errorf("background is not supported on routine calls (%s)", step.Routine)
+			case step.Background && step.Capture == "":
+				return fmt.Errorf("background step %q needs a capture name for wait_for to join it", step.Name)
+			case step.WaitFor != "" && (step.Handler != "" || step.Routine != "" || step.Background):
+				return fmt.Errorf("wait_for step %q joins background step %q and cannot also run a handler or routine", step.Name, step.WaitFor)
+			}
+		}
+	}
+	return nil
+}
+
+// startBackground runs a handler step concurrently and returns at once. The
+// step works on a copy of the context taken now, so the steps after it
+// don't race with it, and can be stopped on its own (see joinUnfinished).
+func (r *TestRunner) startBackground(step config.Step, interpolatedMap map[string]any, ctx *interpolate.Context, phase string, index int) StepResult {
+	started := StepResult{
+		Phase:   phase,
+		Index:   index,
+		Name:    step.Name,
+		Handler: step.Handler,
+	}
+	if _, ok := r.background[step.Capture]; ok {
+		started.Error = fmt.Sprintf("background step %q is already running", step.Capture)
+		return started
+	}
+
+	bgCtx := ctx.Clone()
+	runCtx, cancel := context.WithCancelCause(ctx.RunContext())
+	bgCtx.Run = runCtx
+	bg := &backgroundStep{step: step, cancel: cancel, done: make(chan struct{})}
+	r.background[step.Capture] = bg
+	go func() {
+		defer close(bg.done)
+		defer func() {
+			if p := recover(); p != nil {
+				bg.result = StepResult{Phase: phase, Index: index, Name: step.Name, Handler: step.Handler, Error: fmt.Sprintf("panic in background step: %v", p)}
+			}
+		}()
+		start := time.Now()
+		bg.result = r.runHandler(step, interpolatedMap, bgCtx, phase, index)
+		bg.result.Duration = time.Since(start)
+	}()
+
+	started.Success = true
+	started.Stdout = fmt.Sprintf("started in background; join with wait_for: %s\n", step.Capture)
+	started.ResolvedCommand = resolvedCommand(step, interpolatedMap, ctx)
+	started.ResolvedParams = resolvedParams(interpolatedMap, "name", "handler", "command")
+	return started
+}
+
+// joinBackground waits for the background step named by wait_for and
+// returns its result under the wait_for step, so a failed background step
+// fails there. The step's timeout bounds the wait.
+func (r *TestRunner) joinBackground(step config.Step, phase string, index int) StepResult {
+	joined := StepResult{
+		Phase: phase,
+		Index: index,
+		Name:  step.Name,
+	}
+	bg, ok := r.background[step.WaitFor]
+	if !ok {
+		joined.Error = fmt.Sprintf("wait_for: no background step captured as %q was started", step.WaitFor)
+		return joined
+	}
+
+	var timeout <-chan time.Time
+	if step.Timeout > 0 {
+		timeout = time.After(time.Duration(step.Timeout) * time.Second)
+	}
+	select {
+	case <-bg.done:
+	case <-timeout:
+		joined.Error = fmt.Sprintf("wait_for: background step %q still running after %ds", step.WaitFor, step.Timeout)
+		return joined
+	}
+	delete(r.background, step.WaitFor)
+	bg.cancel(nil)
+
+	result := bg.result
+	result.Phase, result.Index = phase, index
+	if step.Name != "" {
+		result.Name = step.Name
+	}
+	return result
+}
+
+// joinUnfinished stops background steps no wait_for joined, as nothing
+// waits for them and they may never exit (tail -f), and records them in the
+// cleanup phase, indexed from index. They don't affect the test result.
+func (r *TestRunner) joinUnfinished(result *TestResult, index int) {
+	names := make([]string, 0, len(r.background))
+	for name := range r.background {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		bg := r.background[name]
+		note := fmt.Sprintf("tsuite: background step %q was never joined by wait_for\n", name)
+		select {
+		case <-bg.done:
+		default:
+			note = fmt.Sprintf("tsuite: background step %q was never joined by wait_for and was stopped after post_run\n", name)
+		}
+		bg.cancel(errNotJoined)
+		<-bg.done
+
+		stepResult := bg.result
+		stepResult.Phase, stepResult.Index = "cleanup", index
+		stepResult.Stderr += note
+		result.Steps = append(result.Steps, stepResult)
+		index++
+	}
+	r.background = nil
+}
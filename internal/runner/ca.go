@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// EnvCABundle names a PEM file of CA certificates the test's steps trust
+// besides the system's (proxy.ca_bundle in config.yaml)
+const EnvCABundle = "TSUITE_CA_BUNDLE"
+
+// caBundleVars are the variables Go, OpenSSL, Python requests, pip, curl,
+// git and Node.js read a CA bundle from
+var caBundleVars = []string{"SSL_CERT_FILE", "REQUESTS_CA_BUNDLE", "PIP_CERT", "CURL_CA_BUNDLE", "GIT_SSL_CAINFO", "NODE_EXTRA_CA_CERTS"}
+
+// systemCABundles are where Linux distributions and macOS keep the system's
+// CA bundle
+var systemCABundles = []string{
+	"/etc/ssl/certs/ca-certificates.crt", // Debian, Ubuntu, Alpine
+	"/etc/pki/tls/certs/ca-bundle.crt",   // Fedora, RHEL
+	"/etc/ssl/ca-bundle.pem",             // openSUSE
+	"/etc/ssl/cert.pem",                  // macOS
+}
+
+// UseCABundle makes the test's steps trust the CAs of TSUITE_CA_BUNDLE. Most
+// tools replace the CAs they trust with a bundle given in the environment,
+// so the bundle is combined with the system's (or $SSL_CERT_FILE) in a file
+// the variables of every tool point at. The file is named by its contents,
+// so the tests of a run share it.
+func UseCABundle() error {
+	bundle := os.Getenv(EnvCABundle)
+	if bundle == "" {
+		return nil
+	}
+	extra, err := os.ReadFile(bundle)
+	if err != nil {
+		return fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	var combined []byte
+	for _, path := range append([]string{os.Getenv("SSL_CERT_FILE")}, systemCABundles...) {
+		if path == "" {
+			continue
+		}
+		if combined, err = os.ReadFile(path); err == nil {
+			break
+		}
+	}
+	if len(combined) > 0 && combined[len(combined)-1] != '\n' {
+		combined = append(combined, '\n')
+	}
+	combined = append(combined, extra...)
+
+	sum := sha256.Sum256(combined)
+	path := filepath.Join(os.TempDir(), "tsuite-ca-"+hex.EncodeToString(sum[:6])+".pem")
+	if _, err := os.Stat(path); err != nil {
+		// Written aside and renamed, as parallel tests may race
+		tmp, err := os.CreateTemp(os.TempDir(), "tsuite-ca-*.tmp")
+		if err != nil {
+			return fmt.Errorf("failed to write CA bundle: %w", err)
+		}
+		_, err = tmp.Write(combined)
+		tmp.Close()
+		if err == nil {
+			err = os.Rename(tmp.Name(), path)
+		}
+		if err != nil {
+			os.Remove(tmp.Name())
+			return fmt.Errorf("failed to write CA bundle: %w", err)
+		}
+	}
+
+	for _, name := range caBundleVars {
+		os.Setenv(name, path)
+	}
+	return nil
+}
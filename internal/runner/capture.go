@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// captureRegex extracts the capture_regex variables of a successful step
+// from its stdout: the first capture group of each pattern's first match,
+// or the whole match when the pattern has no group. A pattern that doesn't
+// match (or doesn't compile) fails the step, so later steps never run with
+// a missing value.
+func captureRegex(patterns map[string]string, result *StepResult, ctx *interpolate.Context) {
+	if !result.Success {
+		return
+	}
+
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	captures := make(map[string]string, len(patterns))
+	for _, name := range names {
+		pattern, _ := interpolate.Interpolate(patterns[name], ctx)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			result.Success = false
+			result.Error = fmt.Sprintf("capture_regex %s: invalid pattern %q: %v", name, pattern, err)
+			return
+		}
+		m := re.FindStringSubmatch(result.Stdout)
+		if m == nil {
+			result.Success = false
+			result.Error = fmt.Sprintf("capture_regex %s: /%s/ does not match stdout", name, pattern) + quoteOutput("stdout", result.Stdout)
+			return
+		}
+		if len(m) > 1 {
+			captures[name] = m[1]
+		} else {
+			captures[name] = m[0]
+		}
+	}
+	result.Captures = captures
+}
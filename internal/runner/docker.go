@@ -3,11 +3,15 @@ package runner
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/handlers"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/logging"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -26,8 +30,24 @@ type ContainerConfig struct {
 	MemoryLimit int64
 	CPUQuota    int64
 	Mounts      []MountConfig
+	Env         []string // KEY=VALUE set for every test, after the test's container env
+
+	// Container ports published to the host, e.g. "8000" (random port on
+	// 127.0.0.1), "9000/udp" or "8080:8000"
+	PublishPorts []string
+	// Keep a failed test's container running this long for inspection
+	KeepFailed time.Duration
+	// Rewrite golden files of snapshot assertions; the test's snapshots
+	// directory is mounted writable
+	UpdateSnapshots bool
+	// Host directory mounted at CacheMountPath for the pip and npm download
+	// caches, shared by the run's containers ("" = no cache)
+	CacheDir string
 }
 
+// CacheMountPath is where containers see ContainerConfig.CacheDir
+const CacheMountPath = "/cache"
+
 // MountConfig holds a volume mount configuration
 type MountConfig struct {
 	Type          string // "host" or "volume"
@@ -47,6 +67,16 @@ func DefaultContainerConfig() ContainerConfig {
 	}
 }
 
+// Labels set on test containers. Tests can label containers they start with
+// RunIDLabel=${run_id} so leak checks can find them, and TestIDLabel=${test_id}
+// so they are still checked when tests run in parallel.
+const (
+	RunIDLabel  = "tsuite.run_id"
+	TestIDLabel = "tsuite.test_id"
+	RoleLabel   = "tsuite.role"
+	RoleRunner  = "runner"
+)
+
 // DockerExecutor runs tests inside Docker containers
 type DockerExecutor struct {
 	client      *client.Client
@@ -55,7 +85,9 @@ type DockerExecutor struct {
 	baseWorkdir string
 	config      ContainerConfig
 	runID       string
-	runnerPath  string // Path to Go runner binary (Linux version for container)
+
+	runnersMu sync.Mutex
+	runners   map[string]string // Linux runner binary by image architecture
 }
 
 // NewDockerExecutor creates a new Docker executor
@@ -87,6 +119,11 @@ func NewDockerExecutor(serverURL, suitePath, baseWorkdir string, config *Contain
 		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
+	if err := checkDockerHostLocal(cli.DaemonHost()); err != nil {
+		cli.Close()
+		return nil, err
+	}
+
 	cfg := DefaultContainerConfig()
 	if config != nil {
 		if config.Image != "" {
@@ -105,13 +142,11 @@ func NewDockerExecutor(serverURL, suitePath, baseWorkdir string, config *Contain
 			cfg.MemoryLimit = config.MemoryLimit
 		}
 		cfg.Mounts = config.Mounts
-	}
-
-	// Find the Go runner binary for Linux (container architecture)
-	runnerPath, err := findRunnerBinaryForDocker()
-	if err != nil {
-		cli.Close()
-		return nil, fmt.Errorf("failed to find runner binary for docker: %w", err)
+		cfg.Env = config.Env
+		cfg.PublishPorts = config.PublishPorts
+		cfg.KeepFailed = config.KeepFailed
+		cfg.UpdateSnapshots = config.UpdateSnapshots
+		cfg.CacheDir = config.CacheDir
 	}
 
 	// Prune stopped containers on startup to prevent accumulation
@@ -127,12 +162,15 @@ func NewDockerExecutor(serverURL, suitePath, baseWorkdir string, config *Contain
 		baseWorkdir: baseWorkdir,
 		config:        cfg,
 		runID:         runID,
-		runnerPath:    runnerPath,
+		runners:       make(map[string]string),
 	}, nil
 }
 
-// findRunnerBinaryForDocker finds the Go runner binary for Linux containers
-func findRunnerBinaryForDocker() (string, error) {
+// FindDockerRunner finds the Go runner binary for Linux containers of the
+// given architecture (GOARCH naming): tsuite-runner-linux-<arch> next to the
+// executable, tsuite-runner-linux if it was built for that architecture, else
+// the embedded Linux runner
+func FindDockerRunner(arch string) (string, error) {
 	// Get the directory of the current executable
 	execPath, err := os.Executable()
 	if err != nil {
@@ -140,22 +178,109 @@ func findRunnerBinaryForDocker() (string, error) {
 	}
 	execDir := filepath.Dir(execPath)
 
-	// Look for tsuite-runner-linux (built with GOOS=linux, uses host architecture)
-	runnerPath := filepath.Join(execDir, "tsuite-runner-linux")
+	runnerPath := filepath.Join(execDir, "tsuite-runner-linux-"+arch)
 	if _, err := os.Stat(runnerPath); err == nil {
 		return runnerPath, nil
 	}
 
-	return "", fmt.Errorf("runner binary not found. Run 'make build-runner-linux' to build it. Expected: %s", runnerPath)
+	// tsuite-runner-linux is built for a single architecture (make build-runner-linux
+	// used to build it for the host), so only use it if it matches
+	legacyPath := filepath.Join(execDir, "tsuite-runner-linux")
+	if _, err := os.Stat(legacyPath); err == nil {
+		if goos, goarch, err := BinaryPlatform(legacyPath); err == nil && goos == "linux" && goarch == arch {
+			return legacyPath, nil
+		}
+	}
+
+	embedded, err := ExtractEmbeddedRunner("linux", arch)
+	if err != nil {
+		return "", err
+	}
+	if embedded != "" {
+		return embedded, nil
+	}
+
+	return "", fmt.Errorf("no runner binary for linux/%s found. Build it with 'make build-runner-linux RUNNER_ARCH=%s' or install it with 'tsuite runner install'. Expected: %s", arch, arch, runnerPath)
+}
+
+// imageArch returns the architecture (GOARCH naming) of a local image.
+// Images without one are assumed to match the engine's architecture.
+func imageArch(ctx context.Context, cli *client.Client, imageName string) (string, error) {
+	inspect, _, err := cli.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return "", err
+	}
+	if inspect.Os != "" && inspect.Os != "linux" {
+		return "", fmt.Errorf("image %q is a %s image; docker mode only runs Linux containers", imageName, inspect.Os)
+	}
+	if arch := normalizeArch(inspect.Architecture); arch != "" {
+		return arch, nil
+	}
+	version, err := cli.ServerVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	return normalizeArch(version.Arch), nil
+}
+
+// normalizeArch maps architecture names reported by container engines
+// (e.g. "x86_64", "aarch64") to GOARCH naming
+func normalizeArch(arch string) string {
+	switch arch {
+	case "x86_64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	}
+	return arch
+}
+
+// runnerForImage returns the runner binary to mount into containers of a
+// local image, matching the image's architecture (e.g. amd64 images
+// emulated on Apple Silicon need the amd64 runner)
+func (e *DockerExecutor) runnerForImage(ctx context.Context, imageName string) (string, error) {
+	arch, err := imageArch(ctx, e.client, imageName)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect architecture of image %q: %w", imageName, err)
+	}
+
+	e.runnersMu.Lock()
+	defer e.runnersMu.Unlock()
+	if path, ok := e.runners[arch]; ok {
+		return path, nil
+	}
+	path, err := FindDockerRunner(arch)
+	if err != nil {
+		return "", fmt.Errorf("image %q is linux/%s: %w", imageName, arch, err)
+	}
+	e.runners[arch] = path
+	return path, nil
 }
 
 // ContainerResult holds the result of running a container
 type ContainerResult struct {
-	ExitCode int
-	Stdout   string
-	Stderr   string
-	Error    error
-	Duration time.Duration
+	ExitCode  int
+	Stdout    string
+	Stderr    string
+	Error     error
+	Duration  time.Duration
+	Container *ContainerInfo // Set once the container was created
+}
+
+// ContainerInfo describes the container a test ran in. It is recorded on the
+// test result so crashes the runner can't report (e.g. OOM kills) stay visible.
+type ContainerInfo struct {
+	ID        string `json:"id"`
+	Image     string `json:"image"`
+	ExitCode  int    `json:"exit_code"`
+	OOMKilled bool   `json:"oom_killed"`
+	Error     string `json:"error,omitempty"`    // Engine error starting or running the container
+	LogPath   string `json:"log_path,omitempty"` // container.log in the test's log directory
+
+	// Published ports by container port, e.g. "8000/tcp" -> "127.0.0.1:49153"
+	Ports map[string]string `json:"ports,omitempty"`
+	// When a failed test's container kept for inspection stops (RFC3339)
+	KeptUntil string `json:"kept_until,omitempty"`
 }
 
 // ExecuteTest runs a test inside a Docker container
@@ -191,6 +316,22 @@ func (e *DockerExecutor) ExecuteTest(ctx context.Context, testID string, testCon
 	if e.runID != "" {
 		env = append(env, fmt.Sprintf("TSUITE_RUN_ID=%s", e.runID))
 	}
+	env = append(env, logging.Env()...)
+	if os.Getenv("TSUITE_TRACE_INTERPOLATION") == "1" {
+		env = append(env, "TSUITE_TRACE_INTERPOLATION=1")
+	}
+	if v := os.Getenv(EnvTsuiteVersion); v != "" {
+		env = append(env, EnvTsuiteVersion+"="+v)
+	}
+	if v := os.Getenv(EnvAPICertSHA256); v != "" {
+		env = append(env, EnvAPICertSHA256+"="+v)
+	}
+
+	// pip, npm and meshctl-install keep their downloads in the shared cache;
+	// tests can still override the variables in their container env
+	if e.config.CacheDir != "" {
+		env = append(env, "PIP_CACHE_DIR="+CacheMountPath+"/pip", "npm_config_cache="+CacheMountPath+"/npm", handlers.EnvCacheDir+"="+CacheMountPath)
+	}
 
 	// Add env from test config
 	if envMap, ok := containerConfigMap["env"].(map[string]any); ok {
@@ -204,15 +345,10 @@ func (e *DockerExecutor) ExecuteTest(ctx context.Context, testID string, testCon
 			env = append(env, fmt.Sprintf("%s=%s", k, value))
 		}
 	}
+	env = append(env, e.config.Env...)
 
 	// Prepare volume mounts
 	mounts := []mount.Mount{
-		{
-			Type:     mount.TypeBind,
-			Source:   e.runnerPath,
-			Target:   "/usr/local/bin/tsuite-runner",
-			ReadOnly: true,
-		},
 		{
 			Type:     mount.TypeBind,
 			Source:   e.suitePath,
@@ -232,6 +368,30 @@ func (e *DockerExecutor) ExecuteTest(ctx context.Context, testID string, testCon
 	artifactsPath := filepath.Join(e.suitePath, "suites", testID, "artifacts")
 	mounts = append(mounts, mountArtifactsDir(artifactsPath, "/artifacts")...)
 
+	// The suite is read-only; golden files being updated are written through
+	// a writable mount of the test's snapshots directory
+	if e.config.UpdateSnapshots {
+		snapshotsPath := filepath.Join(e.suitePath, "suites", testID, SnapshotsDir)
+		if err := os.MkdirAll(snapshotsPath, 0755); err == nil {
+			mounts = append(mounts, mount.Mount{
+				Type:   mount.TypeBind,
+				Source: snapshotsPath,
+				Target: "/tests/suites/" + testID + "/" + SnapshotsDir,
+			})
+			env = append(env, EnvUpdateSnapshots+"=1")
+		}
+	}
+
+	if e.config.CacheDir != "" {
+		if err := os.MkdirAll(e.config.CacheDir, 0755); err == nil {
+			mounts = append(mounts, mount.Mount{
+				Type:   mount.TypeBind,
+				Source: e.config.CacheDir,
+				Target: CacheMountPath,
+			})
+		}
+	}
+
 	// Auto-mount UC-level artifacts directory if it exists
 	// Mount each item inside artifacts separately, resolving symlinks
 	parts := strings.Split(testID, "/")
@@ -244,10 +404,12 @@ func (e *DockerExecutor) ExecuteTest(ctx context.Context, testID string, testCon
 	// Structure: ~/.tsuite/runs/{run_id}/{uc}/{tc}/
 	//   - worker.log: runner execution trace
 	//   - logs/: mcp-mesh agent logs
+	var containerLogPath string
 	if e.runID != "" && len(parts) >= 2 {
 		testLogDir := filepath.Join(os.Getenv("HOME"), ".tsuite", "runs", e.runID, parts[0], parts[1])
 		logsPath := filepath.Join(testLogDir, "logs")
 		if err := os.MkdirAll(logsPath, 0755); err == nil {
+			containerLogPath = filepath.Join(testLogDir, "container.log")
 			// Mount parent directory for worker.log
 			mounts = append(mounts, mount.Mount{
 				Type:     mount.TypeBind,
@@ -289,12 +451,39 @@ func (e *DockerExecutor) ExecuteTest(ctx context.Context, testID string, testCon
 		return nil, fmt.Errorf("failed to pull image: %w", err)
 	}
 
+	// Mount the runner built for the image's architecture
+	runnerPath, err := e.runnerForImage(ctx, imageName)
+	if err != nil {
+		return nil, err
+	}
+	mounts = append(mounts, mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   runnerPath,
+		Target:   "/usr/local/bin/tsuite-runner",
+		ReadOnly: true,
+	})
+	env = append(env, EnvImage+"="+imageName, EnvImageDigest+"="+imageDigest(ctx, e.client, imageName))
+	if e.config.KeepFailed > 0 {
+		env = append(env, fmt.Sprintf("%s=%d", EnvKeepFailed, int(e.config.KeepFailed.Seconds())))
+	}
+
+	exposedPorts, portMap, err := portBindings(e.config.PublishPorts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create container
 	containerConfig := &container.Config{
 		Image:      imageName,
 		Cmd:        command,
 		Env:        env,
 		WorkingDir: "/workspace",
+		ExposedPorts: exposedPorts,
+		Labels: map[string]string{
+			RunIDLabel:  e.runID,
+			TestIDLabel: testID,
+			RoleLabel:   RoleRunner,
+		},
 	}
 
 	hostConfig := &container.HostConfig{
@@ -304,6 +493,7 @@ func (e *DockerExecutor) ExecuteTest(ctx context.Context, testID string, testCon
 			Memory: e.config.MemoryLimit,
 		},
 		ExtraHosts: []string{"host.docker.internal:host-gateway"},
+		PortBindings: portMap,
 	}
 
 	resp, err := e.client.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, "")
@@ -312,17 +502,31 @@ func (e *DockerExecutor) ExecuteTest(ctx context.Context, testID string, testCon
 	}
 
 	containerID := resp.ID
+	kept := false
 	defer func() {
-		// Always remove container
+		// Remove the container unless it is kept for inspection
+		if kept {
+			return
+		}
 		removeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		e.client.ContainerRemove(removeCtx, containerID, container.RemoveOptions{Force: true})
 	}()
+	info := &ContainerInfo{ID: containerID, Image: imageName, LogPath: containerLogPath}
 
 	// Start container
 	if err := e.client.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
 		return nil, fmt.Errorf("failed to start container: %w", err)
 	}
+	if len(portMap) > 0 {
+		info.Ports = e.publishedPorts(ctx, containerID)
+	}
+
+	// Stream logs to container.log while the container runs, so they survive
+	// an OOM kill or a crash of tsuite itself
+	var stdout, stderr syncBuffer
+	watcher := newKeptWatcher()
+	logsDone := e.streamLogs(containerID, containerLogPath, io.MultiWriter(&stdout, watcher), &stderr)
 
 	// Wait for container with timeout
 	waitCtx, waitCancel := context.WithTimeout(ctx, timeout)
@@ -334,43 +538,112 @@ func (e *DockerExecutor) ExecuteTest(ctx context.Context, testID string, testCon
 	select {
 	case err := <-errCh:
 		if err != nil {
-			// Timeout or other error - kill container
-			killCtx, killCancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer killCancel()
-			e.client.ContainerKill(killCtx, containerID, "SIGKILL")
+			// Timeout or other error - stop container with SIGTERM so the runner
+			// can execute post_run, then SIGKILL after the grace period
+			graceSeconds := int(CancelGracePeriod.Seconds())
+			stopCtx, stopCancel := context.WithTimeout(context.Background(), CancelGracePeriod+10*time.Second)
+			defer stopCancel()
+			e.client.ContainerStop(stopCtx, containerID, container.StopOptions{Timeout: &graceSeconds})
+			waitForLogs(logsDone)
+			e.inspectExit(info)
 			return &ContainerResult{
-				ExitCode: 124,
-				Error:    fmt.Errorf("container execution failed: %w", err),
-				Duration: time.Since(startTime),
+				ExitCode:  124,
+				Stdout:    stdout.String(),
+				Stderr:    stderr.String(),
+				Error:     fmt.Errorf("container execution failed: %w", err),
+				Duration:  time.Since(startTime),
+				Container: info,
 			}, nil
 		}
 	case status := <-statusCh:
 		exitCode = int(status.StatusCode)
-	}
-
-	// Get logs
-	logsReader, err := e.client.ContainerLogs(ctx, containerID, container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-	})
-	if err != nil {
+	case code := <-watcher.kept:
+		// The runner failed and the container waits to be inspected; it
+		// exits by itself and is pruned by a later run
+		kept = true
+		info.ExitCode = code
+		info.KeptUntil = keptUntil(e.config.KeepFailed)
 		return &ContainerResult{
-			ExitCode: exitCode,
-			Error:    fmt.Errorf("failed to get container logs: %w", err),
-			Duration: time.Since(startTime),
+			ExitCode:  code,
+			Stdout:    stdout.String(),
+			Stderr:    stderr.String(),
+			Duration:  time.Since(startTime),
+			Container: info,
 		}, nil
 	}
-	defer logsReader.Close()
 
-	var stdout, stderr strings.Builder
-	_, _ = stdcopy.StdCopy(&stdout, &stderr, logsReader)
+	waitForLogs(logsDone)
+	info.ExitCode = exitCode
+	e.inspectExit(info)
 
-	return &ContainerResult{
-		ExitCode: exitCode,
-		Stdout:   stdout.String(),
-		Stderr:   stderr.String(),
-		Duration: time.Since(startTime),
-	}, nil
+	result := &ContainerResult{
+		ExitCode:  exitCode,
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Duration:  time.Since(startTime),
+		Container: info,
+	}
+	if info.OOMKilled {
+		result.Error = fmt.Errorf("container was OOM-killed (exit code %d); raise the memory limit or reduce the test's memory use", exitCode)
+	}
+	return result, nil
+}
+
+// streamLogs follows a container's output into stdout and stderr and, if
+// logPath is set, into that file, until the container stops. The returned
+// channel is closed when the stream ends.
+func (e *DockerExecutor) streamLogs(containerID, logPath string, stdout, stderr io.Writer) <-chan struct{} {
+	done := make(chan struct{})
+
+	var logFile *os.File
+	if logPath != "" {
+		if f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644); err == nil {
+			logFile = f
+			stdout = io.MultiWriter(stdout, f)
+			stderr = io.MultiWriter(stderr, f)
+		}
+	}
+
+	go func() {
+		defer close(done)
+		if logFile != nil {
+			defer logFile.Close()
+		}
+		// Not tied to the test context: after a timeout or cancel the output
+		// of post_run during the stop grace period is still captured
+		reader, err := e.client.ContainerLogs(context.Background(), containerID, container.LogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Follow:     true,
+		})
+		if err != nil {
+			return
+		}
+		defer reader.Close()
+		_, _ = stdcopy.StdCopy(stdout, stderr, reader)
+	}()
+	return done
+}
+
+// waitForLogs waits for a log stream to drain after its container stopped
+func waitForLogs(done <-chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+	}
+}
+
+// inspectExit fills in how a stopped container exited
+func (e *DockerExecutor) inspectExit(info *ContainerInfo) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	inspect, err := e.client.ContainerInspect(ctx, info.ID)
+	if err != nil || inspect.State == nil {
+		return
+	}
+	info.ExitCode = inspect.State.ExitCode
+	info.OOMKilled = inspect.State.OOMKilled
+	info.Error = inspect.State.Error
 }
 
 // ensureImage checks if an image exists locally.
@@ -404,6 +677,19 @@ func (e *DockerExecutor) ensureImage(ctx context.Context, imageName string) erro
 	return fmt.Errorf("failed to check image %q: %w", imageName, err)
 }
 
+// imageDigest returns the repo digest of a local image, or its image ID for
+// images that were built locally and never pushed
+func imageDigest(ctx context.Context, cli *client.Client, imageName string) string {
+	inspect, _, err := cli.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return ""
+	}
+	if len(inspect.RepoDigests) > 0 {
+		return inspect.RepoDigests[0]
+	}
+	return inspect.ID
+}
+
 // buildTestCommand creates the command to run inside the container
 func (e *DockerExecutor) buildTestCommand(testID string) []string {
 	// Run the Go runner binary (mounted at /usr/local/bin/tsuite-runner)
@@ -411,16 +697,24 @@ func (e *DockerExecutor) buildTestCommand(testID string) []string {
 	script := fmt.Sprintf(`
 set -e
 
-# Install jq if not present (for jq-based assertions)
-if ! command -v jq &> /dev/null; then
+# Install jq if not present (for jq-based assertions), unless offline
+if [ -z "${%s:-}" ] && ! command -v jq &> /dev/null; then
     apt-get update -qq && apt-get install -y -qq jq 2>/dev/null || true
 fi
 
 # Run the Go test runner (uses TSUITE_LOG_DIR env var for logging)
+status=0
 /usr/local/bin/tsuite-runner \
     --test-yaml /tests/suites/%s/test.yaml \
-    --suite-path /tests
-`, testID)
+    --suite-path /tests || status=$?
+
+# Keep a failed test's container running for inspection
+if [ "$status" -ne 0 ] && [ -n "${%s:-}" ]; then
+    echo "%s$status"
+    sleep "$%s"
+fi
+exit $status
+`, handlers.EnvOffline, testID, EnvKeepFailed, keptMarker, EnvKeepFailed)
 
 	return []string{"bash", "-c", script}
 }
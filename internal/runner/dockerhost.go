@@ -0,0 +1,64 @@
+package runner
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+)
+
+// EnvDockerSharedPaths set to 1 allows docker mode against a remote Docker
+// daemon that sees this machine's paths at the same locations (e.g. home
+// directories shared over NFS), so bind mounts resolve as they do locally
+const EnvDockerSharedPaths = "TSUITE_DOCKER_SHARED_PATHS"
+
+// IsRemoteDockerHost reports whether a Docker daemon address (as in
+// DOCKER_HOST) points at another machine. Unix sockets, including rootless
+// Docker's under $XDG_RUNTIME_DIR and Podman's, named pipes and loopback TCP
+// addresses are local.
+func IsRemoteDockerHost(daemonHost string) bool {
+	u, err := url.Parse(daemonHost)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "tcp", "http", "https", "ssh":
+	default:
+		return false
+	}
+
+	host := u.Hostname()
+	if host == "" || host == "localhost" {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return false
+	}
+	return true
+}
+
+// checkDockerHostLocal returns an error if the daemon at daemonHost is remote
+// and not known to share this machine's paths. Docker mode bind-mounts the
+// suite, the test workspace, the logs directory and the runner binary from
+// this machine; a remote daemon would mount its own (missing or empty) paths
+// instead and tests would fail obscurely.
+func checkDockerHostLocal(daemonHost string) error {
+	if !IsRemoteDockerHost(daemonHost) || os.Getenv(EnvDockerSharedPaths) == "1" {
+		return nil
+	}
+	return fmt.Errorf("Docker daemon %s is remote: docker mode bind-mounts the suite, workspace and runner from this machine, "+
+		"which the daemon cannot see. Run tsuite on the Docker host, point DOCKER_HOST at a local daemon "+
+		"(rootless Docker and Podman sockets work), use standalone mode, or set %s=1 if the daemon sees the same paths",
+		daemonHost, EnvDockerSharedPaths)
+}
+
+// CheckDockerHostLocal returns an error if docker mode can't bind-mount local
+// paths into containers because the Docker daemon runs on another machine
+func CheckDockerHostLocal() error {
+	cli, err := newDockerClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	return checkDockerHostLocal(cli.DaemonHost())
+}
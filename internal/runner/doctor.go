@@ -0,0 +1,144 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	dockercontext "github.com/docker/go-sdk/context"
+)
+
+// EngineInfo describes the container engine tests would run on
+type EngineInfo struct {
+	Name    string // "Docker" or "Podman"
+	Version string
+	OS      string
+	Arch    string // GOARCH naming, e.g. "amd64" or "arm64"
+	Host    string // Daemon address, e.g. "unix:///var/run/docker.sock"
+}
+
+// newDockerClient connects to the Docker host of the current context, as
+// NewDockerExecutor does
+func newDockerClient() (*client.Client, error) {
+	dockerHost, err := dockercontext.CurrentDockerHost()
+	if err != nil {
+		dockerHost = ""
+	}
+	if dockerHost != "" {
+		return client.NewClientWithOpts(client.WithHost(dockerHost), client.WithAPIVersionNegotiation())
+	}
+	return client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+}
+
+// DockerEngine reports which container engine answers on the Docker socket.
+// Podman is detected through its Docker-compatible API.
+func DockerEngine() (*EngineInfo, error) {
+	cli, err := newDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	version, err := cli.ServerVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &EngineInfo{Name: "Docker", Version: version.Version, OS: version.Os, Arch: version.Arch, Host: cli.DaemonHost()}
+	for _, component := range version.Components {
+		if strings.Contains(strings.ToLower(component.Name), "podman") {
+			info.Name = "Podman"
+		}
+	}
+	return info, nil
+}
+
+// DockerRunnerBinary returns the Linux runner binary mounted into containers
+// of imageName and the architecture it was chosen for. If the image is not
+// available locally, the container engine's architecture (or, without an
+// engine, this machine's) is used.
+func DockerRunnerBinary(imageName string) (path, arch string, err error) {
+	arch = runtime.GOARCH
+	if cli, cliErr := newDockerClient(); cliErr == nil {
+		defer cli.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if detected, archErr := imageArch(ctx, cli, imageName); archErr == nil {
+			arch = detected
+		} else if version, versionErr := cli.ServerVersion(ctx); versionErr == nil {
+			arch = normalizeArch(version.Arch)
+		}
+	}
+	path, err = FindDockerRunner(arch)
+	return path, arch, err
+}
+
+// ImageHasCommand reports whether command is on the PATH of a local image. The
+// image is not pulled; a missing image is an error.
+func ImageHasCommand(imageName, command string) (bool, error) {
+	cli, err := newDockerClient()
+	if err != nil {
+		return false, err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, _, err := cli.ImageInspectWithRaw(ctx, imageName); err != nil {
+		if client.IsErrNotFound(err) {
+			return false, fmt.Errorf("image %q not found locally", imageName)
+		}
+		return false, err
+	}
+
+	resp, err := cli.ContainerCreate(ctx, &container.Config{
+		Image:      imageName,
+		Entrypoint: []string{"sh", "-c"},
+		Cmd:        []string{"command -v " + command},
+	}, nil, nil, nil, "")
+	if err != nil {
+		return false, fmt.Errorf("failed to create container: %w", err)
+	}
+	defer func() {
+		removeCtx, removeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer removeCancel()
+		cli.ContainerRemove(removeCtx, resp.ID, container.RemoveOptions{Force: true})
+	}()
+
+	if err := cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return false, fmt.Errorf("failed to start container: %w", err)
+	}
+
+	statusCh, errCh := cli.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		return false, err
+	case status := <-statusCh:
+		return status.StatusCode == 0, nil
+	}
+}
+
+// ImageDigest returns the repo digest of a local image, or its image ID for
+// images that were built locally and never pushed; "" if the image is not
+// available locally
+func ImageDigest(imageName string) string {
+	cli, err := newDockerClient()
+	if err != nil {
+		return ""
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return imageDigest(ctx, cli, imageName)
+}
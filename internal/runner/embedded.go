@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// EmbeddedRunners holds the runner binaries embedded in the tsuite binary,
+// named by EmbeddedRunnerName. It is set by the main package if any were
+// embedded (make build-embedded).
+var EmbeddedRunners fs.FS
+
+// EmbeddedRunnerName returns the name of the embedded runner for a platform
+func EmbeddedRunnerName(goos, goarch string) string {
+	return fmt.Sprintf("tsuite-runner-%s-%s", goos, goarch)
+}
+
+// ExtractedRunnerDir is where embedded runners are extracted (~/.tsuite/bin)
+func ExtractedRunnerDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".tsuite", "bin")
+}
+
+// ExtractEmbeddedRunner writes the embedded runner for a platform to
+// ExtractedRunnerDir, unless an identical copy is already there, and returns
+// its path. It returns "" if no runner for the platform is embedded.
+func ExtractEmbeddedRunner(goos, goarch string) (string, error) {
+	if EmbeddedRunners == nil {
+		return "", nil
+	}
+	name := EmbeddedRunnerName(goos, goarch)
+	data, err := fs.ReadFile(EmbeddedRunners, name)
+	if err != nil {
+		return "", nil
+	}
+
+	dir := ExtractedRunnerDir()
+	path := filepath.Join(dir, name)
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return path, nil
+	}
+
+	// Write to a temp file and rename, so concurrent runs never see a partial binary
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	tmp, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		return "", fmt.Errorf("failed to extract runner: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to extract runner: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to extract runner: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return "", fmt.Errorf("failed to extract runner: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to extract runner: %w", err)
+	}
+	return path, nil
+}
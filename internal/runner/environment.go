@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// Environment variables through which the CLI and docker executor describe
+// the environment to the runner
+const (
+	EnvTsuiteVersion = "TSUITE_VERSION"      // tsuite CLI version
+	EnvImage         = "TSUITE_IMAGE"        // Container image (docker mode)
+	EnvImageDigest   = "TSUITE_IMAGE_DIGEST" // Repo digest or image ID (docker mode)
+)
+
+// EnvAPICertSHA256 is the SHA-256 fingerprint of the API server's TLS
+// certificate. API clients given it trust a server presenting exactly that
+// certificate at any host name, e.g. localhost or host.docker.internal for
+// the runs the server launches, or a self-signed server.
+const EnvAPICertSHA256 = "TSUITE_API_CERT_SHA256"
+
+// Environment is the effective environment a test ran in. It is stored with
+// the test result so failures can be compared across machines and CI.
+type Environment struct {
+	TsuiteVersion string            `json:"tsuite_version,omitempty"`
+	RunnerVersion string            `json:"runner_version,omitempty"`
+	OS            string            `json:"os"`
+	Arch          string            `json:"arch"`
+	GoVersion     string            `json:"go_version"`
+	Hostname      string            `json:"hostname,omitempty"`
+	Mode          string            `json:"mode"`
+	Image         string            `json:"image,omitempty"`
+	ImageDigest   string            `json:"image_digest,omitempty"`
+	NodeVersion   string            `json:"node_version,omitempty"` // With packages.node_version
+	Env           map[string]string `json:"env,omitempty"`          // Variables matching execution.capture_env
+}
+
+// CaptureEnvironment describes the environment this runner executes tests in.
+// Only environment variables matching execution.capture_env are included, and
+// values of secret-looking names are redacted.
+func (r *TestRunner) CaptureEnvironment(runnerVersion string) *Environment {
+	mode := r.suiteConfig.Suite.Mode
+	if mode == "" {
+		mode = "standalone"
+	}
+	hostname, _ := os.Hostname()
+
+	env := &Environment{
+		TsuiteVersion: os.Getenv(EnvTsuiteVersion),
+		RunnerVersion: runnerVersion,
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		GoVersion:     runtime.Version(),
+		Hostname:      hostname,
+		Mode:          mode,
+		Image:         os.Getenv(EnvImage),
+		ImageDigest:   os.Getenv(EnvImageDigest),
+		NodeVersion:   r.nodeVersion,
+	}
+
+	patterns := r.suiteConfig.Execution.CaptureEnv
+	if len(patterns) == 0 {
+		return env
+	}
+	env.Env = make(map[string]string)
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if !matchesAny(name, patterns) {
+			continue
+		}
+		if interpolate.IsSecret(name) {
+			value = "<redacted>"
+		}
+		env.Env[name] = value
+	}
+	return env
+}
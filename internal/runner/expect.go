@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// maxExpectOutputLen caps how much step output an expectation failure quotes
+const maxExpectOutputLen = 300
+
+// checkExpect evaluates a step's inline expectations and fails the step on the
+// first one that does not hold. A handler error (timeout, bad config) is never
+// overridden. When exit_code is expected, it decides success instead of the
+// usual "exit code 0".
+func checkExpect(expect *config.StepExpect, result *StepResult, ctx *interpolate.Context) {
+	if result.Error != "" {
+		return
+	}
+
+	if expect.ExitCode != nil {
+		if result.ExitCode != *expect.ExitCode {
+			failExpect(result, fmt.Sprintf("expected exit code %d, got %d", *expect.ExitCode, result.ExitCode), "stderr", result.Stderr)
+			return
+		}
+		result.Success = true
+	}
+	if !result.Success {
+		return
+	}
+
+	checks := []struct {
+		stream, output, text string
+		negate               bool
+	}{
+		{"stdout", result.Stdout, expect.StdoutContains, false},
+		{"stdout", result.Stdout, expect.StdoutNotContains, true},
+		{"stderr", result.Stderr, expect.StderrContains, false},
+		{"stderr", result.Stderr, expect.StderrNotContains, true},
+	}
+	for _, check := range checks {
+		if check.text == "" {
+			continue
+		}
+		text, _ := interpolate.Interpolate(check.text, ctx)
+		if strings.Contains(check.output, text) == check.negate {
+			verb := "to contain"
+			if check.negate {
+				verb = "not to contain"
+			}
+			failExpect(result, fmt.Sprintf("expected %s %s %q", check.stream, verb, text), check.stream, check.output)
+			return
+		}
+	}
+
+	if expect.StdoutMatches != "" {
+		pattern, _ := interpolate.Interpolate(expect.StdoutMatches, ctx)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			failExpect(result, fmt.Sprintf("invalid stdout_matches pattern %q: %v", pattern, err), "", "")
+			return
+		}
+		if !re.MatchString(result.Stdout) {
+			failExpect(result, fmt.Sprintf("expected stdout to match /%s/", pattern), "stdout", result.Stdout)
+		}
+	}
+}
+
+// failExpect marks a step failed by an expectation, quoting the relevant output
+func failExpect(result *StepResult, message, stream, output string) {
+	result.Success = false
+	result.Error = "expect: " + message
+	if stream != "" {
+		result.Error += quoteOutput(stream, output)
+	}
+}
+
+// quoteOutput returns the start of a step's output for an error message
+func quoteOutput(stream, output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxExpectOutputLen {
+		output = output[:maxExpectOutputLen] + "..."
+	}
+	if output == "" {
+		return fmt.Sprintf(" (%s was empty)", stream)
+	}
+	return fmt.Sprintf(" (%s: %q)", stream, output)
+}
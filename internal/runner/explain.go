@@ -0,0 +1,196 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// TestExplanation is a test as it would execute, without running anything
+type TestExplanation struct {
+	TestID     string
+	TestName   string
+	Workdir    string
+	SkipIf     []string
+	Steps      []ExplainedStep
+	Assertions []string
+}
+
+// ExplainedStep is a single handler invocation after routine expansion and
+// interpolation. Values that depend on earlier steps (captured output, last
+// step results) cannot be known up front and are left as ${...}.
+type ExplainedStep struct {
+	Phase   string
+	Index   string // "2" for top-level steps, "2.0" for the first step of a routine called at step 2
+	Name    string
+	Handler string
+	Routine string         // Routine the step was expanded from ("" for top-level steps)
+	Fields  map[string]any // Interpolated handler fields (name and handler omitted)
+	Runtime []string       // ${...} references resolved only at runtime
+	Error   string         // Why the step could not be expanded
+}
+
+// ExplainTest loads a test, expands routine calls and interpolates what can be
+// resolved before the test runs. No handler is executed.
+func (r *TestRunner) ExplainTest(testID string) (*TestExplanation, error) {
+	ucName, tcName, ok := strings.Cut(testID, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid test ID format: %s (expected uc/tc)", testID)
+	}
+
+	testConfig, err := config.LoadTestConfig(filepath.Join(r.suitePath, "suites", ucName, tcName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load test config: %w", err)
+	}
+
+	ucRoutinesConfig, err := config.LoadUseCaseRoutines(filepath.Join(r.suitePath, "suites", ucName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load use case routines: %w", err)
+	}
+	r.ucRoutines = ucRoutinesConfig.Routines
+
+	// Routine calls are validated (and free of cycles) before expansion
+	if err := r.validateRoutineCalls(testConfig); err != nil {
+		return nil, err
+	}
+
+	workdir := r.testWorkdir(testID)
+	ctx := r.newTestContext(testID, ucName, tcName, workdir)
+
+	explanation := &TestExplanation{
+		TestID:   testID,
+		TestName: testConfig.Name,
+		Workdir:  workdir,
+	}
+	for _, cond := range testConfig.SkipIf {
+		explanation.SkipIf = append(explanation.SkipIf, cond.Expr)
+	}
+
+	phases := []struct {
+		name  string
+		steps []config.Step
+	}{
+		{"pre_run", testConfig.PreRun},
+		{"test", testConfig.Test},
+		{"post_run", testConfig.PostRun},
+	}
+	for _, phase := range phases {
+		for i, step := range phase.steps {
+			explanation.Steps = r.explainStep(explanation.Steps, step, ctx, phase.name, fmt.Sprint(i), "")
+		}
+	}
+
+	explanation.Assertions = explainAssertions(explanation.Assertions, testConfig.Assertions, "")
+	for _, schemaAssertion := range testConfig.AssertSchema {
+		explanation.Assertions = append(explanation.Assertions, "schema "+schemaAssertion.Schema+": "+schemaAssertion.Value)
+	}
+	for _, eventual := range testConfig.AssertEventually {
+		explanation.Assertions = append(explanation.Assertions, "eventually: "+eventual.Expr)
+	}
+
+	return explanation, nil
+}
+
+// explainAssertions appends the assertions of a list, prefixing the members
+// of groups with the group name
+func explainAssertions(explained []string, assertions []config.Assertion, prefix string) []string {
+	for _, assertion := range assertions {
+		switch {
+		case assertion.IsGroup():
+			groupPrefix := prefix + assertion.Group
+			if assertion.StopOnFailure {
+				groupPrefix += " (stop on failure)"
+			}
+			explained = explainAssertions(explained, assertion.Assertions, groupPrefix+": ")
+		case assertion.Snapshot != "":
+			explained = append(explained, prefix+"snapshot "+assertion.Snapshot+": "+assertion.Value)
+		default:
+			explained = append(explained, prefix+assertion.Expr)
+		}
+	}
+	return explained
+}
+
+// explainStep appends the expansion of a step, recursing into routine calls
+func (r *TestRunner) explainStep(steps []ExplainedStep, step config.Step, ctx *interpolate.Context, phase, index, routine string) []ExplainedStep {
+	if step.Routine != "" {
+		rd, _ := r.resolveRoutine(step.Routine)
+
+		params, err := rd.ApplyParams(step.Routine, interpolateParams(step.Params, ctx))
+		if err != nil {
+			return append(steps, ExplainedStep{Phase: phase, Index: index, Name: step.Name, Routine: step.Routine, Error: err.Error()})
+		}
+
+		routineCtx := *ctx // shallow copy, as in executeRoutine
+		routineCtx.Params = params
+		for i, routineStep := range rd.Steps {
+			steps = r.explainStep(steps, routineStep, &routineCtx, phase, fmt.Sprintf("%s.%d", index, i), step.Routine)
+		}
+		return steps
+	}
+
+	explained := ExplainedStep{
+		Phase:   phase,
+		Index:   index,
+		Name:    step.Name,
+		Handler: step.Handler,
+		Routine: routine,
+	}
+	if step.WaitFor != "" {
+		explained.Fields = map[string]any{"wait_for": step.WaitFor}
+		return append(steps, explained)
+	}
+	if step.Handler == "" {
+		explained.Error = "step missing 'handler' or 'routine'"
+		return append(steps, explained)
+	}
+
+	fields, err := interpolate.InterpolateMap(stepToMap(step), ctx)
+	if err != nil {
+		explained.Error = fmt.Sprintf("interpolation failed: %v", err)
+		return append(steps, explained)
+	}
+	delete(fields, "name")
+	delete(fields, "handler")
+	explained.Fields = fields
+	explained.Runtime = runtimeReferences(fields)
+	return append(steps, explained)
+}
+
+// runtimeReferences lists the ${...} references left after interpolation
+func runtimeReferences(fields map[string]any) []string {
+	seen := make(map[string]bool)
+	var walk func(v any)
+	walk = func(v any) {
+		switch val := v.(type) {
+		case string:
+			for _, name := range interpolate.References(val) {
+				seen[name] = true
+			}
+		case map[string]any:
+			for _, item := range val {
+				walk(item)
+			}
+		case map[string]string:
+			for _, item := range val {
+				walk(item)
+			}
+		case []any:
+			for _, item := range val {
+				walk(item)
+			}
+		}
+	}
+	walk(fields)
+
+	refs := make([]string, 0, len(seen))
+	for name := range seen {
+		refs = append(refs, name)
+	}
+	sort.Strings(refs)
+	return refs
+}
@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+)
+
+// EnvKeepFailed is the number of seconds a test container keeps running after
+// the runner fails, so developers can attach to it (docker mode)
+const EnvKeepFailed = "TSUITE_KEEP_FAILED"
+
+// keptMarker is printed by the container command when it keeps a failed
+// container running; it is followed by the runner's exit code
+const keptMarker = "tsuite: keeping failed container, exit code "
+
+// portBindings publishes container ports to the host. Bare ports ("8000",
+// "9000/udp") are bound to a random port on 127.0.0.1; full specs such as
+// "8080:8000" are used as given.
+func portBindings(specs []string) (nat.PortSet, nat.PortMap, error) {
+	full := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		if !strings.Contains(spec, ":") {
+			spec = "127.0.0.1::" + spec
+		}
+		full = append(full, spec)
+	}
+	exposed, bindings, err := nat.ParsePortSpecs(full)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid published port: %w", err)
+	}
+	return exposed, bindings, nil
+}
+
+// publishedPorts returns the host address of each published container port,
+// e.g. "8000/tcp" -> "127.0.0.1:49153"
+func (e *DockerExecutor) publishedPorts(ctx context.Context, containerID string) map[string]string {
+	inspect, err := e.client.ContainerInspect(ctx, containerID)
+	if err != nil || inspect.NetworkSettings == nil {
+		return nil
+	}
+	ports := make(map[string]string)
+	for port, bindings := range inspect.NetworkSettings.Ports {
+		if len(bindings) == 0 {
+			continue
+		}
+		host := bindings[0].HostIP
+		if host == "" || host == "0.0.0.0" {
+			host = "127.0.0.1"
+		}
+		ports[string(port)] = host + ":" + bindings[0].HostPort
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+	return ports
+}
+
+// syncBuffer is a strings.Builder that can be written by a log stream while
+// it is read
+type syncBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+// keptWatcher watches container stdout for keptMarker and sends the runner's
+// exit code once the container starts waiting to be inspected
+type keptWatcher struct {
+	line []byte
+	kept chan int
+	sent bool
+}
+
+func newKeptWatcher() *keptWatcher {
+	return &keptWatcher{kept: make(chan int, 1)}
+}
+
+func (w *keptWatcher) Write(p []byte) (int, error) {
+	if w.sent {
+		return len(p), nil
+	}
+	w.line = append(w.line, p...)
+	for {
+		i := bytes.IndexByte(w.line, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(w.line[:i])
+		w.line = w.line[i+1:]
+		if rest, ok := strings.CutPrefix(line, keptMarker); ok {
+			code, err := strconv.Atoi(strings.TrimSpace(rest))
+			if err != nil {
+				code = 1
+			}
+			w.kept <- code
+			w.sent = true
+			w.line = nil
+			break
+		}
+	}
+	return len(p), nil
+}
+
+// keptUntil is when a container kept for inspection stops, in RFC3339 UTC
+func keptUntil(keep time.Duration) string {
+	return time.Now().Add(keep).UTC().Format(time.RFC3339)
+}
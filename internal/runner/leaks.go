@@ -0,0 +1,326 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvParallel is set (to "1") for runners whose test runs alongside others.
+// Their leak checks only count ports of the test's own processes and
+// containers labeled with its test ID.
+const EnvParallel = "TSUITE_PARALLEL"
+
+// LeakReport lists resources a test left behind after post_run
+type LeakReport struct {
+	Ports      []string `json:"ports,omitempty"`      // Listening sockets opened during the test
+	Containers []string `json:"containers,omitempty"` // Running containers labeled with the run (or test) ID
+	Files      []string `json:"files,omitempty"`      // New workdir entries not matched by leak_ignore
+}
+
+// Empty reports whether no leaks were found
+func (l *LeakReport) Empty() bool {
+	return l == nil || (len(l.Ports) == 0 && len(l.Containers) == 0 && len(l.Files) == 0)
+}
+
+// Lines returns one human-readable line per leaked resource
+func (l *LeakReport) Lines() []string {
+	if l == nil {
+		return nil
+	}
+	var lines []string
+	for _, p := range l.Ports {
+		lines = append(lines, "port "+p)
+	}
+	for _, c := range l.Containers {
+		lines = append(lines, "container "+c)
+	}
+	for _, f := range l.Files {
+		lines = append(lines, "file "+f)
+	}
+	return lines
+}
+
+// defaultLeakIgnore lists workdir patterns that are never reported as leaks
+var defaultLeakIgnore = []string{"*.log"}
+
+// leakBaseline records resources that existed before the test started
+type leakBaseline struct {
+	ports      map[string]listener
+	containers map[string]string
+	files      map[string]bool
+}
+
+// listener is a listening TCP socket
+type listener struct {
+	desc string // e.g. ":8080 (tcp) pid 42 python"
+	pid  string // Owning process, "" if not visible
+}
+
+// SetParallel tells the runner whether its test runs alongside others (see
+// EnvParallel)
+func (r *TestRunner) SetParallel(parallel bool) {
+	r.parallel = parallel
+}
+
+// takeLeakBaseline snapshots listening ports, labeled containers and workdir entries
+func (r *TestRunner) takeLeakBaseline(testID, workdir string) *leakBaseline {
+	if r.parallel && runtime.GOOS != "linux" {
+		slog.Warn("Port leak checks are off for tests running in parallel on " + runtime.GOOS)
+	}
+	return &leakBaseline{
+		ports:      listeningPorts(),
+		containers: runContainers(r.runID, r.containerScope(testID)),
+		files:      workdirEntries(workdir),
+	}
+}
+
+// checkLeaks compares the current state against the baseline
+func (r *TestRunner) checkLeaks(baseline *leakBaseline, testID, workdir string) *LeakReport {
+	report := &LeakReport{}
+
+	ports := listeningPorts()
+	if len(newKeys(baseline.ports, ports)) > 0 {
+		// Give agents stopped in post_run a moment to release their sockets
+		time.Sleep(time.Second)
+		ports = listeningPorts()
+	}
+	var own map[string]bool
+	if r.parallel {
+		// Other tests' sockets are not this test's leaks
+		own = testProcesses(r.runID, testID)
+	}
+	for _, key := range newKeys(baseline.ports, ports) {
+		if own != nil && !own[ports[key].pid] {
+			continue
+		}
+		report.Ports = append(report.Ports, ports[key].desc)
+	}
+
+	containers := runContainers(r.runID, r.containerScope(testID))
+	for _, key := range newKeys(baseline.containers, containers) {
+		report.Containers = append(report.Containers, containers[key])
+	}
+
+	ignore := append(append([]string{}, defaultLeakIgnore...), r.suiteConfig.Execution.LeakIgnore...)
+	for name := range workdirEntries(workdir) {
+		if baseline.files[name] || matchesAny(strings.TrimSuffix(name, "/"), ignore) {
+			continue
+		}
+		report.Files = append(report.Files, name)
+	}
+	sort.Strings(report.Files)
+
+	return report
+}
+
+// containerScope returns the test ID containers must be labeled with to
+// count as the test's, "" when every container of the run does
+func (r *TestRunner) containerScope(testID string) string {
+	if r.parallel {
+		return testID
+	}
+	return ""
+}
+
+// testProcesses returns the PIDs of processes the test started. Steps inherit
+// TSUITE_RUN_ID and TSUITE_TEST_ID from the runner and keep them when they
+// detach, so they are found by environment rather than by parent. On
+// platforms without /proc it returns an empty set.
+func testProcesses(runID, testID string) map[string]bool {
+	pids := make(map[string]bool)
+	if runtime.GOOS != "linux" {
+		return pids
+	}
+	// The runner's own /proc environ predates the IDs it exported
+	pids[strconv.Itoa(os.Getpid())] = true
+	markers := [][]byte{[]byte("\x00TSUITE_TEST_ID=" + testID + "\x00")}
+	if runID != "" {
+		markers = append(markers, []byte("\x00TSUITE_RUN_ID="+runID+"\x00"))
+	}
+	environs, _ := filepath.Glob("/proc/[0-9]*/environ")
+	for _, path := range environs {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		data = append(append([]byte{0}, data...), 0)
+		owned := true
+		for _, marker := range markers {
+			if !bytes.Contains(data, marker) {
+				owned = false
+				break
+			}
+		}
+		if owned {
+			pids[strings.Split(path, "/")[2]] = true
+		}
+	}
+	return pids
+}
+
+// newKeys returns the sorted keys of after that are not in before
+func newKeys[V any](before, after map[string]V) []string {
+	var keys []string
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// workdirEntries returns the top-level entries of the workdir (directories end in "/")
+func workdirEntries(workdir string) map[string]bool {
+	entries := make(map[string]bool)
+	dirEntries, err := os.ReadDir(workdir)
+	if err != nil {
+		return entries
+	}
+	for _, e := range dirEntries {
+		name := e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		entries[name] = true
+	}
+	return entries
+}
+
+// runContainers returns running containers labeled with the run ID (and the
+// test ID, if one is given), keyed by container ID. The containers tsuite
+// itself runs tests in are excluded.
+func runContainers(runID, testID string) map[string]string {
+	containers := make(map[string]string)
+	if runID == "" {
+		return containers
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		return containers
+	}
+
+	args := []string{"ps", "--filter", "label=" + RunIDLabel + "=" + runID}
+	if testID != "" {
+		args = append(args, "--filter", "label="+TestIDLabel+"="+testID)
+	}
+	args = append(args, "--format", `{{.ID}}|{{.Image}}|{{.Names}}|{{.Label "`+RoleLabel+`"}}`)
+	out, err := exec.Command("docker", args...).Output()
+	if err != nil {
+		return containers
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Split(line, "|")
+		if len(parts) != 4 || parts[3] == RoleRunner {
+			continue
+		}
+		containers[parts[0]] = fmt.Sprintf("%s (%s, image %s)", parts[2], parts[0], parts[1])
+	}
+	return containers
+}
+
+// listeningPorts returns listening TCP sockets keyed by "proto:port"
+func listeningPorts() map[string]listener {
+	if runtime.GOOS == "linux" {
+		return listeningPortsProc()
+	}
+	return listeningPortsLsof()
+}
+
+// listeningPortsProc reads /proc/net/tcp{,6} and maps socket inodes to processes
+func listeningPortsProc() map[string]listener {
+	ports := make(map[string]listener)
+	inodes := make(map[string]string) // inode -> key
+
+	for _, proto := range []string{"tcp", "tcp6"} {
+		data, err := os.ReadFile("/proc/net/" + proto)
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Scan() // header
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			// sl local_address rem_address st ... inode is field 9
+			if len(fields) < 10 || fields[3] != "0A" { // 0A = LISTEN
+				continue
+			}
+			idx := strings.LastIndex(fields[1], ":")
+			port, err := strconv.ParseInt(fields[1][idx+1:], 16, 32)
+			if err != nil {
+				continue
+			}
+			key := fmt.Sprintf("%s:%d", proto, port)
+			ports[key] = listener{desc: fmt.Sprintf(":%d (%s)", port, proto)}
+			inodes[fields[9]] = key
+		}
+	}
+
+	// Attribute sockets to processes where /proc permissions allow
+	attributed := make(map[string]bool)
+	procs, _ := filepath.Glob("/proc/[0-9]*/fd/*")
+	for _, fd := range procs {
+		link, err := os.Readlink(fd)
+		if err != nil || !strings.HasPrefix(link, "socket:[") {
+			continue
+		}
+		key, ok := inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")]
+		if !ok || attributed[key] {
+			continue
+		}
+		attributed[key] = true
+		pid := strings.Split(fd, "/")[2]
+		comm, _ := os.ReadFile(filepath.Join("/proc", pid, "comm"))
+		ports[key] = listener{
+			desc: fmt.Sprintf("%s pid %s %s", ports[key].desc, pid, strings.TrimSpace(string(comm))),
+			pid:  pid,
+		}
+	}
+
+	return ports
+}
+
+// listeningPortsLsof uses lsof where /proc is unavailable (e.g. macOS)
+func listeningPortsLsof() map[string]listener {
+	ports := make(map[string]listener)
+	if _, err := exec.LookPath("lsof"); err != nil {
+		return ports
+	}
+	out, err := exec.Command("lsof", "-nP", "-iTCP", "-sTCP:LISTEN").Output()
+	if err != nil {
+		return ports
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for _, line := range lines[1:] {
+		// COMMAND PID USER FD TYPE DEVICE SIZE/OFF NODE NAME (LISTEN)
+		fields := strings.Fields(line)
+		if len(fields) < 9 {
+			continue
+		}
+		name := fields[8]
+		port := name[strings.LastIndex(name, ":")+1:]
+		key := "tcp:" + port
+		ports[key] = listener{desc: fmt.Sprintf(":%s (tcp) pid %s %s", port, fields[1], fields[0]), pid: fields[1]}
+	}
+	return ports
+}
@@ -0,0 +1,127 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/handlers"
+)
+
+// EnvNodeVersion overrides packages.node_version (tsuite run --node-version)
+const EnvNodeVersion = "TSUITE_NODE_VERSION"
+
+// nodeInstallTimeout bounds installing a Node.js version with fnm or nvm
+const nodeInstallTimeout = 5 * time.Minute
+
+// UseNodeVersion makes the Node.js version of packages.node_version (or
+// TSUITE_NODE_VERSION) the one the test's steps run. Node on PATH is used
+// if it matches; otherwise fnm or nvm provide the version, installing it if
+// needed, and its bin directory is put first on PATH.
+func (r *TestRunner) UseNodeVersion() error {
+	want := os.Getenv(EnvNodeVersion)
+	if want == "" {
+		want = r.suiteConfig.Packages.NodeVersion
+	}
+	if want == "" {
+		return nil
+	}
+
+	have := nodeVersion("node")
+	if matchesNodeVersion(have, want) {
+		r.nodeVersion = have
+		return nil
+	}
+
+	node, err := managedNode(want)
+	if err != nil {
+		if have == "" {
+			have = "not installed"
+		}
+		return fmt.Errorf("packages.node_version is %s but node on PATH is %s: %w", want, have, err)
+	}
+	os.Setenv("PATH", filepath.Dir(node)+string(os.PathListSeparator)+os.Getenv("PATH"))
+	r.nodeVersion = nodeVersion(node)
+	if !matchesNodeVersion(r.nodeVersion, want) {
+		return fmt.Errorf("packages.node_version is %s but %s is %s", want, node, r.nodeVersion)
+	}
+	return nil
+}
+
+// nodeVersion returns the version of a node binary, e.g. "v20.11.1", or ""
+// if it doesn't run
+func nodeVersion(node string) string {
+	out, err := exec.Command(node, "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// matchesNodeVersion reports whether a node version (v20.11.1) is the
+// wanted one: a major (20), major.minor (20.11) or full version, with or
+// without the v
+func matchesNodeVersion(have, want string) bool {
+	have = strings.TrimPrefix(have, "v")
+	want = strings.TrimPrefix(want, "v")
+	if have == "" || want == "" {
+		return false
+	}
+	return have == want || strings.HasPrefix(have, want+".")
+}
+
+// managedNode returns the node binary of a version managed by fnm or nvm,
+// installing the version if needed. Offline, only installed versions are
+// used.
+func managedNode(version string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), nodeInstallTimeout)
+	defer cancel()
+	offline := handlers.Offline()
+
+	if _, err := exec.LookPath("fnm"); err == nil {
+		if !offline {
+			if out, err := exec.CommandContext(ctx, "fnm", "install", version).CombinedOutput(); err != nil {
+				return "", fmt.Errorf("fnm install %s failed: %s", version, strings.TrimSpace(string(out)))
+			}
+		}
+		out, err := exec.CommandContext(ctx, "fnm", "exec", "--using="+version, "node", "-p", "process.execPath").Output()
+		if err != nil && offline {
+			return "", fmt.Errorf("it is not installed with fnm and --offline forbids downloading it")
+		}
+		if err != nil {
+			return "", fmt.Errorf("fnm exec --using=%s failed: %w", version, err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	nvmDir := os.Getenv("NVM_DIR")
+	if nvmDir == "" {
+		nvmDir = filepath.Join(os.Getenv("HOME"), ".nvm")
+	}
+	if _, err := os.Stat(filepath.Join(nvmDir, "nvm.sh")); err == nil {
+		// nvm is a shell function; nvm which prints the node binary
+		script := `. "$1/nvm.sh" && nvm install "$2" >&2 && nvm which "$2"`
+		if offline {
+			script = `. "$1/nvm.sh" && nvm which "$2"`
+		}
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "bash", "-c", script, "bash", nvmDir, version)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil && offline {
+			return "", fmt.Errorf("it is not installed with nvm and --offline forbids downloading it")
+		}
+		if err != nil {
+			return "", fmt.Errorf("nvm install %s failed: %s", version, strings.TrimSpace(stderr.String()))
+		}
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		return strings.TrimSpace(lines[len(lines)-1]), nil
+	}
+
+	return "", fmt.Errorf("install fnm or nvm to provide it")
+}
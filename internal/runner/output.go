@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Prefixes of the lines the runner prints for the CLI to collect (see ParseOutput)
+const (
+	// SkippedPrefix marks a skipped test: "<test_id> - <reason>"
+	SkippedPrefix = "SKIPPED: "
+	// LeakPrefix marks a leaked resource
+	LeakPrefix = "Leak: "
+	// BudgetPrefix marks a duration budget violation
+	BudgetPrefix = "Budget exceeded: "
+	// RecordedPrefix marks a value recorded on the test result (key=value),
+	// e.g. for the run manifest
+	RecordedPrefix = "Recorded: "
+	// SuggestionPrefix marks a suggested fix
+	SuggestionPrefix = "Suggested fix: "
+	// DiffPrefix marks a line of a failed assertion's diff, shown in the
+	// CLI's summary
+	DiffPrefix = "Diff: "
+)
+
+// Output is what the runner reported in its output for one test
+type Output struct {
+	Skipped         bool
+	SkipReason      string
+	Leaks           []string
+	BudgetViolation string // "" if the test kept to its budget
+	Recorded        map[string]string
+	Suggestions     []string
+	AssertionDiffs  []string
+}
+
+// ParseOutput collects the prefixed lines printed by the runner
+func ParseOutput(output string) Output {
+	var out Output
+	for _, line := range strings.Split(output, "\n") {
+		// Diff lines keep their indentation
+		if diff, ok := strings.CutPrefix(strings.TrimRight(line, "\r"), DiffPrefix); ok {
+			out.AssertionDiffs = append(out.AssertionDiffs, diff)
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if reason, ok := strings.CutPrefix(line, SkippedPrefix); ok && !out.Skipped {
+			if _, r, ok := strings.Cut(reason, " - "); ok {
+				reason = r
+			}
+			out.Skipped, out.SkipReason = true, reason
+		} else if leak, ok := strings.CutPrefix(line, LeakPrefix); ok {
+			out.Leaks = append(out.Leaks, leak)
+		} else if violation, ok := strings.CutPrefix(line, BudgetPrefix); ok && out.BudgetViolation == "" {
+			out.BudgetViolation = violation
+		} else if v, ok := strings.CutPrefix(line, RecordedPrefix); ok {
+			if key, value, ok := strings.Cut(v, "="); ok {
+				if out.Recorded == nil {
+					out.Recorded = make(map[string]string)
+				}
+				out.Recorded[key] = value
+			}
+		} else if suggestion, ok := strings.CutPrefix(line, SuggestionPrefix); ok {
+			out.Suggestions = appendSuggestion(out.Suggestions, suggestion)
+		}
+	}
+	return out
+}
+
+// FormatBudgetViolation describes how far a test went over its duration budget
+func FormatBudgetViolation(duration, budget time.Duration) string {
+	return fmt.Sprintf("%.1fs > budget %.1fs (+%.0f%%)", duration.Seconds(), budget.Seconds(),
+		(duration.Seconds()/budget.Seconds()-1)*100)
+}
+
+// FormatAssertionDiff returns the runner output lines of a failed
+// assertion's diff: the expression, then the diff indented
+func FormatAssertionDiff(expr, diff string) []string {
+	lines := []string{DiffPrefix + expr}
+	for _, line := range strings.Split(diff, "\n") {
+		lines = append(lines, DiffPrefix+"  "+line)
+	}
+	return lines
+}
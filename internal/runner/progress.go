@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// StepObserver is notified as the top-level steps of a test start and finish.
+// Steps inside routines are reported as part of the routine step.
+type StepObserver interface {
+	StepStarted(phase string, index int, name, handler string)
+	StepCompleted(result StepResult, duration time.Duration)
+}
+
+// SetStepObserver sets the observer notified of step progress
+func (r *TestRunner) SetStepObserver(observer StepObserver) {
+	r.stepObserver = observer
+}
+
+// SetInterpolationTracer sets a function called for every ${...} resolution
+// made while running a test (steps, routine params and assertions)
+func (r *TestRunner) SetInterpolationTracer(tracer func(interpolate.Resolution)) {
+	r.tracer = tracer
+}
+
+// runStep executes a top-level step, notifying the step observer
+func (r *TestRunner) runStep(step config.Step, ctx *interpolate.Context, phase string, index int) StepResult {
+	if r.stepObserver == nil {
+		return r.executeStep(step, ctx, phase, index)
+	}
+
+	name := step.Name
+	if name == "" && step.Routine != "" {
+		name = step.Routine
+	}
+	r.stepObserver.StepStarted(phase, index, name, step.Handler)
+
+	start := time.Now()
+	stepResult := r.executeStep(step, ctx, phase, index)
+	r.stepObserver.StepCompleted(stepResult, time.Since(start))
+	return stepResult
+}
@@ -1,10 +1,17 @@
 package runner
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
@@ -12,16 +19,33 @@ import (
 	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
 )
 
+// errTestCancelled is why steps interrupted by Cancel were stopped
+var errTestCancelled = errors.New("test cancelled")
+
+// CancelGracePeriod is how long a cancelled or timed-out runner is given to
+// run post_run before it is killed
+const CancelGracePeriod = 30 * time.Second
+
 // TestRunner executes tests locally (inside container or standalone)
 type TestRunner struct {
-	suitePath      string
-	suiteConfig    *config.SuiteConfig
-	globalRoutines map[string]config.RoutineDefinition
-	ucRoutines     map[string]config.RoutineDefinition // UC-level routines
-	handlers       *handlers.Registry
-	serverURL      string
-	runID          string
-	baseWorkdir    string // Base workdir for standalone mode
+	suitePath       string
+	suiteConfig     *config.SuiteConfig
+	globalRoutines  map[string]config.RoutineDefinition
+	ucRoutines      map[string]config.RoutineDefinition // UC-level routines
+	handlers        *handlers.Registry
+	serverURL       string
+	runID           string
+	baseWorkdir     string // Base workdir for standalone mode
+	artifactDir     string // Where step artifacts are collected (see SetArtifactDir)
+	stepObserver    StepObserver
+	tracer          func(interpolate.Resolution)
+	updateSnapshots bool                       // Rewrite golden files of snapshot assertions (see SetUpdateSnapshots)
+	background      map[string]*backgroundStep // Background steps of the running test by capture name
+	nodeVersion     string                     // Node.js version UseNodeVersion selected
+	parallel        bool                       // Other tests run alongside this one (see SetParallel)
+	cancelled       atomic.Bool
+	ctx             context.Context // Done once the test is cancelled; steps before post_run run under it
+	cancel          context.CancelCauseFunc
 }
 
 // TestResult holds the complete result of a test execution
@@ -29,15 +53,35 @@ type TestResult struct {
 	TestID     string
 	TestName   string
 	Passed     bool
+	Skipped    bool
+	SkipReason string
 	Error      string
 	Duration   time.Duration
 	Steps      []StepResult
 	Assertions []AssertionResult
+
+	// Suggested fixes for recognizable failure signatures
+	Suggestions []string
+
+	// Resources left behind after post_run (execution.leak_checks)
+	Leaks *LeakReport
+
+	// duration_budget_ms from test.yaml (0 = no budget)
+	DurationBudget time.Duration
+
+	// Values steps recorded, e.g. meshctl.version from meshctl-install;
+	// reported as captured values
+	Recorded map[string]string
+}
+
+// BudgetExceeded reports whether the test took longer than its duration budget
+func (r *TestResult) BudgetExceeded() bool {
+	return r.DurationBudget > 0 && r.Duration > r.DurationBudget
 }
 
 // StepResult holds the result of a single step
 type StepResult struct {
-	Phase    string // "pre_run", "test", "post_run"
+	Phase    string // "pre_run", "test", "assert_eventually" or "cleanup" (post_run)
 	Index    int
 	Name     string
 	Handler  string
@@ -46,6 +90,25 @@ type StepResult struct {
 	Stdout   string
 	Stderr   string
 	Error    string
+
+	// Wall time of the step, routine steps included
+	Duration time.Duration
+
+	// Files collected by the step's artifacts patterns, relative to the artifact dir
+	Artifacts []string
+
+	// Variables extracted from stdout by the step's capture_regex patterns
+	Captures map[string]string
+
+	// Values the handler recorded on the test result (a routine's include
+	// those of its steps)
+	Recorded map[string]string
+
+	// What actually ran, after interpolation: the shell command and the
+	// other handler fields (or the params of a routine call), so failures can
+	// be reproduced. Fields named like secrets are redacted.
+	ResolvedCommand string
+	ResolvedParams  map[string]any
 }
 
 // AssertionResult holds the result of an assertion
@@ -57,6 +120,7 @@ type AssertionResult struct {
 	Details  string
 	Actual   string
 	Expected string
+	Diff     string // How Actual differs from Expected (failed equality only)
 }
 
 // NewTestRunner creates a new test runner
@@ -66,6 +130,7 @@ func NewTestRunner(suitePath string, serverURL string, runID string, baseWorkdir
 	if err != nil {
 		return nil, fmt.Errorf("failed to load suite config: %w", err)
 	}
+	usePackageVersions(suiteConfig)
 
 	// Load global routines
 	globalRoutinesConfig, err := config.LoadGlobalRoutines(suitePath)
@@ -73,20 +138,29 @@ func NewTestRunner(suitePath string, serverURL string, runID string, baseWorkdir
 		return nil, fmt.Errorf("failed to load global routines: %w", err)
 	}
 
+	// Load prebuilt WASM handler modules dropped into the suite
+	registry := handlers.NewRegistry()
+	if err := registry.LoadWasmHandlers(filepath.Join(suitePath, "handlers")); err != nil {
+		return nil, fmt.Errorf("failed to load wasm handlers: %w", err)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
 	return &TestRunner{
 		suitePath:      suitePath,
 		suiteConfig:    suiteConfig,
 		globalRoutines: globalRoutinesConfig.Routines,
 		ucRoutines:     make(map[string]config.RoutineDefinition),
-		handlers:       handlers.NewRegistry(),
+		handlers:       registry,
 		serverURL:      serverURL,
 		runID:          runID,
 		baseWorkdir:    baseWorkdir,
+		ctx:            ctx,
+		cancel:         cancel,
 	}, nil
 }
 
 // RunTest executes a single test
-func (r *TestRunner) RunTest(testID string) (*TestResult, error) {
+func (r *TestRunner) RunTest(testID string) (result *TestResult, err error) {
 	startTime := time.Now()
 
 	// Parse test path
@@ -108,57 +182,95 @@ func (r *TestRunner) RunTest(testID string) (*TestResult, error) {
 	// Load UC-level routines
 	ucPath := filepath.Join(r.suitePath, "suites", ucName)
 	ucRoutinesConfig, err := config.LoadUseCaseRoutines(ucPath)
-	if err == nil {
-		r.ucRoutines = ucRoutinesConfig.Routines
+	if err != nil {
+		return nil, fmt.Errorf("failed to load use case routines: %w", err)
 	}
+	r.ucRoutines = ucRoutinesConfig.Routines
 
-	// Determine workdir based on mode
-	var workdir string
-	mode := r.suiteConfig.Suite.Mode
-	if mode == "docker" {
-		// Docker mode uses /workspace inside container
-		workdir = "/workspace"
-	} else {
-		// Standalone mode uses temp directory
-		if r.baseWorkdir != "" {
-			// Create test-specific workdir under base
-			workdir = filepath.Join(r.baseWorkdir, strings.ReplaceAll(testID, "/", "_"))
-			if err := os.MkdirAll(workdir, 0755); err != nil {
-				return nil, fmt.Errorf("failed to create workdir: %w", err)
-			}
-		} else {
-			// Fallback to suite path if no base workdir
-			workdir = r.suitePath
+	// Catch bad routine calls before any step runs
+	if err := r.validateRoutineCalls(testConfig); err != nil {
+		return nil, err
+	}
+	if err := validateBackgroundSteps(testConfig); err != nil {
+		return nil, err
+	}
+	r.background = make(map[string]*backgroundStep)
+
+	// Create test-specific workdir under base (standalone mode)
+	workdir := r.testWorkdir(testID)
+	if r.suiteConfig.Suite.Mode != "docker" && r.baseWorkdir != "" {
+		if err := os.MkdirAll(workdir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create workdir: %w", err)
 		}
 	}
 
 	// Build execution context
-	ctx := interpolate.NewContext()
-	ctx.Config = r.suiteConfig.ToMap()
-	ctx.SuitePath = r.suitePath
-	ctx.Workdir = workdir
-	ctx.FixturesDir = filepath.Join(r.suitePath, "fixtures")
-	ctx.Artifacts = filepath.Join(r.suitePath, "suites", testID, "artifacts")
-	ctx.UCArtifacts = filepath.Join(r.suitePath, "suites", ucName, "artifacts")
-	ctx.Extra["test_id"] = testID
-	ctx.Extra["uc_name"] = ucName
-	ctx.Extra["tc_name"] = tcName
+	ctx := r.newTestContext(testID, ucName, tcName, workdir)
+
+	result = &TestResult{
+		TestID:         testID,
+		TestName:       testConfig.Name,
+		Passed:         true,
+		Steps:          []StepResult{},
+		DurationBudget: time.Duration(testConfig.DurationBudgetMS) * time.Millisecond,
+	}
+
+	// Check skip conditions before running any steps
+	if reason, skip := r.checkSkipConditions(testConfig.SkipIf, ctx); skip {
+		result.Skipped = true
+		result.SkipReason = reason
+		result.Duration = time.Since(startTime)
+		return result, nil
+	}
 
-	result := &TestResult{
-		TestID:   testID,
-		TestName: testConfig.Name,
-		Passed:   true,
-		Steps:    []StepResult{},
+	// Snapshot resources so anything left behind after post_run can be reported
+	var baseline *leakBaseline
+	if r.suiteConfig.Execution.LeakChecks {
+		baseline = r.takeLeakBaseline(testID, workdir)
 	}
 
+	// post_run always executes, even if a step panics, pre_run fails or the
+	// test is cancelled, and is recorded in the "cleanup" phase. It runs to
+	// completion after a cancel, which only interrupts the steps before it.
+	aborted := false
+	defer func() {
+		if p := recover(); p != nil {
+			result.Passed = false
+			result.Error = fmt.Sprintf("panic during test: %v", p)
+			aborted = true
+		}
+		ctx.Run = nil
+		r.runPostRun(testConfig.PostRun, ctx, result)
+		r.joinUnfinished(result, len(testConfig.PostRun)+1)
+		if r.nodeVersion != "" {
+			result.Recorded = mergeRecorded(result.Recorded, map[string]string{"node.version": r.nodeVersion})
+		}
+		for _, step := range result.Steps {
+			result.Recorded = mergeRecorded(result.Recorded, step.Recorded)
+		}
+		if baseline != nil {
+			if leaks := r.checkLeaks(baseline, testID, workdir); !leaks.Empty() {
+				result.Leaks = leaks
+			}
+		}
+		result.Suggestions = SuggestFixes(result)
+		result.Duration = time.Since(startTime)
+	}()
+
 	// Execute pre_run
 	for i, step := range testConfig.PreRun {
-		stepResult := r.executeStep(step, ctx, "pre_run", i)
+		if r.abortIfCancelled(result) {
+			aborted = true
+			break
+		}
+
+		stepResult := r.runStep(step, ctx, "pre_run", i)
 		result.Steps = append(result.Steps, stepResult)
 
 		if !stepResult.Success && !step.IgnoreErrors {
 			result.Passed = false
 			result.Error = fmt.Sprintf("pre_run step %d failed: %s", i, stepResult.Error)
+			aborted = true
 			break
 		}
 
@@ -166,15 +278,33 @@ func (r *TestRunner) RunTest(testID string) (*TestResult, error) {
 		r.updateContext(ctx, stepResult, step)
 	}
 
-	// Execute test steps (if pre_run succeeded)
+	// Execute test steps (if pre_run succeeded). With continue_on_failure,
+	// a failed step fails the test but the remaining steps and the
+	// assertions still run.
+	stepsFailed := false
 	if result.Passed {
 		for i, step := range testConfig.Test {
-			stepResult := r.executeStep(step, ctx, "test", i)
+			if r.abortIfCancelled(result) {
+				aborted = true
+				break
+			}
+
+			stepResult := r.runStep(step, ctx, "test", i)
 			result.Steps = append(result.Steps, stepResult)
 
 			if !stepResult.Success && !step.IgnoreErrors {
 				result.Passed = false
+				if testConfig.ContinueOnFailure {
+					if stepsFailed {
+						result.Error += "; "
+					}
+					result.Error += fmt.Sprintf("test step %d failed: %s", i, stepResult.Error)
+					stepsFailed = true
+					r.updateContext(ctx, stepResult, step)
+					continue
+				}
 				result.Error = fmt.Sprintf("test step %d failed: %s", i, stepResult.Error)
+				aborted = true
 				break
 			}
 
@@ -183,40 +313,292 @@ func (r *TestRunner) RunTest(testID string) (*TestResult, error) {
 		}
 	}
 
-	// Evaluate assertions (if test steps succeeded)
-	if result.Passed {
-		for i, assertion := range testConfig.Assertions {
-			assertResult := interpolate.EvaluateAssertion(assertion.Expr, ctx)
+	// Evaluate assertions (if test steps succeeded, or ran to the end with
+	// continue_on_failure)
+	if result.Passed || (stepsFailed && !aborted) {
+		assertionCount := config.CountAssertions(testConfig.Assertions)
+		next := 0
+		if !r.evaluateAssertions(testConfig.Assertions, false, testPath, ctx, result, &next) {
+			result.Passed = false
+		}
+
+		for i, schemaAssertion := range testConfig.AssertSchema {
+			assertResult := r.evaluateSchema(schemaAssertion, ctx)
+			assertResult.Index = assertionCount + i
+			result.Assertions = append(result.Assertions, assertResult)
+
+			if !assertResult.Passed {
+				result.Passed = false
+			}
+		}
+
+		// Evaluate polling assertions after the regular ones
+		for i, eventual := range testConfig.AssertEventually {
+			if r.abortIfCancelled(result) {
+				aborted = true
+				break
+			}
+
+			assertResult := r.evaluateEventually(eventual, ctx)
+			assertResult.Index = assertionCount + len(testConfig.AssertSchema) + i
+			result.Assertions = append(result.Assertions, assertResult)
+
+			if !assertResult.Passed {
+				result.Passed = false
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// testWorkdir returns the directory a test runs in
+func (r *TestRunner) testWorkdir(testID string) string {
+	if r.suiteConfig.Suite.Mode == "docker" {
+		// Docker mode uses /workspace inside container
+		return "/workspace"
+	}
+	if r.baseWorkdir != "" {
+		// Standalone mode uses a test-specific directory under the base workdir
+		return filepath.Join(r.baseWorkdir, strings.ReplaceAll(testID, "/", "_"))
+	}
+	// Fallback to suite path if no base workdir
+	return r.suitePath
+}
+
+// newTestContext builds the interpolation context a test starts with
+func (r *TestRunner) newTestContext(testID, ucName, tcName, workdir string) *interpolate.Context {
+	ctx := interpolate.NewContext()
+	ctx.Config = r.suiteConfig.ToMap()
+	ctx.SuitePath = r.suitePath
+	ctx.Workdir = workdir
+	ctx.FixturesDir = filepath.Join(r.suitePath, "fixtures")
+	ctx.Artifacts = filepath.Join(r.suitePath, "suites", testID, "artifacts")
+	ctx.UCArtifacts = filepath.Join(r.suitePath, "suites", ucName, "artifacts")
+	ctx.Extra["test_id"] = testID
+	ctx.Extra["uc_name"] = ucName
+	ctx.Extra["tc_name"] = tcName
+	ctx.Extra["run_id"] = r.runID
+	ctx.Extra["os"] = runtime.GOOS
+	ctx.Extra["arch"] = runtime.GOARCH
+	ctx.Tracer = r.tracer
+	ctx.Run = r.ctx
+	return ctx
+}
+
+// runPostRun executes post_run steps in the "cleanup" phase and then
+// force-stops anything handlers left running. Errors in post_run never fail
+// the test.
+func (r *TestRunner) runPostRun(steps []config.Step, ctx *interpolate.Context, result *TestResult) {
+	for i, step := range steps {
+		step.IgnoreErrors = true // Always ignore errors in post_run
+		result.Steps = append(result.Steps, r.executePostRunStep(step, ctx, "cleanup", i))
+	}
+
+	// Force-kill managed processes that post_run did not stop. The step is
+	// indexed after post_run so it never collides with post_run steps.
+	if released := r.handlers.Cleanup(); len(released) > 0 {
+		result.Steps = append(result.Steps, StepResult{
+			Phase:   "cleanup",
+			Index:   len(steps),
+			Name:    "Stop managed processes",
+			Handler: "process",
+			Success: true,
+			Stdout:  strings.Join(released, "\n"),
+		})
+	}
+}
+
+// executePostRunStep runs a post_run step, converting a panic into a failed
+// step so the remaining post_run steps still execute
+func (r *TestRunner) executePostRunStep(step config.Step, ctx *interpolate.Context, phase string, index int) (stepResult StepResult) {
+	defer func() {
+		if p := recover(); p != nil {
+			stepResult = StepResult{
+				Phase:   phase,
+				Index:   index,
+				Name:    step.Name,
+				Handler: step.Handler,
+				Success: false,
+				Error:   fmt.Sprintf("panic: %v", p),
+			}
+		}
+	}()
+	return r.runStep(step, ctx, phase, index)
+}
+
+// Cancel stops the running test: the running step is interrupted and no
+// further steps start. post_run still executes.
+func (r *TestRunner) Cancel() {
+	r.cancelled.Store(true)
+	r.cancel(errTestCancelled)
+}
+
+// abortIfCancelled marks the result failed if the test was cancelled
+func (r *TestRunner) abortIfCancelled(result *TestResult) bool {
+	if !r.cancelled.Load() {
+		return false
+	}
+	result.Passed = false
+	if result.Error == "" {
+		result.Error = "test cancelled"
+	}
+	return true
+}
+
+// checkSkipConditions returns the reason for the first skip_if expression
+// that evaluates true
+func (r *TestRunner) checkSkipConditions(conditions []config.SkipCondition, ctx *interpolate.Context) (string, bool) {
+	for _, cond := range conditions {
+		// Only probe Docker when a condition needs it
+		if strings.Contains(cond.Expr, "docker_available") {
+			if _, ok := ctx.Extra["docker_available"]; !ok {
+				available, _ := CheckDockerAvailable()
+				ctx.Extra["docker_available"] = strconv.FormatBool(available)
+			}
+		}
+
+		if interpolate.EvaluateAssertion(cond.Expr, ctx).Passed {
+			if cond.Reason != "" {
+				return cond.Reason, true
+			}
+			return fmt.Sprintf("skip_if: %s", cond.Expr), true
+		}
+	}
+	return "", false
+}
+
+// evaluateAssertions evaluates assertions in order, recursing into groups,
+// and appends their results; next is the index of the next assertion. With
+// stopOnFailure (a group's stop_on_failure), the assertions after the first
+// failure are skipped, noting how many on the failed one. Reports whether
+// all passed.
+func (r *TestRunner) evaluateAssertions(assertions []config.Assertion, stopOnFailure bool, testPath string, ctx *interpolate.Context, result *TestResult, next *int) bool {
+	allPassed := true
+	for i, assertion := range assertions {
+		start := len(result.Assertions)
+		if assertion.IsGroup() {
+			if !r.evaluateAssertions(assertion.Assertions, assertion.StopOnFailure, testPath, ctx, result, next) {
+				allPassed = false
+			}
+		} else {
+			expr := assertion.Expr
+			var assertResult interpolate.AssertionResult
+			if assertion.Snapshot != "" {
+				expr = "snapshot " + assertion.Snapshot + ": " + assertion.Value
+				assertResult = r.evaluateSnapshot(testPath, assertion, ctx)
+			} else {
+				assertResult = interpolate.EvaluateAssertion(assertion.Expr, ctx)
+			}
 
 			result.Assertions = append(result.Assertions, AssertionResult{
-				Index:    i,
-				Expr:     assertion.Expr,
+				Index:    *next,
+				Expr:     expr,
 				Message:  assertion.Message,
 				Passed:   assertResult.Passed,
 				Details:  assertResult.Message,
 				Actual:   assertResult.ActualValue,
 				Expected: assertResult.ExpectedValue,
+				Diff:     assertResult.Diff,
 			})
+			*next++
 
 			if !assertResult.Passed {
-				result.Passed = false
+				allPassed = false
+			}
+		}
+
+		if !allPassed && stopOnFailure {
+			if skipped := config.CountAssertions(assertions[i+1:]); skipped > 0 {
+				*next += skipped
+				for j := start; j < len(result.Assertions); j++ {
+					if !result.Assertions[j].Passed {
+						result.Assertions[j].Details += fmt.Sprintf(" (skipped %d dependent assertion(s) of the group)", skipped)
+						break
+					}
+				}
 			}
+			break
 		}
 	}
+	return allPassed
+}
 
-	// Execute post_run (always)
-	for i, step := range testConfig.PostRun {
-		step.IgnoreErrors = true // Always ignore errors in post_run
-		stepResult := r.executeStep(step, ctx, "post_run", i)
-		result.Steps = append(result.Steps, stepResult)
+// Defaults for assert_eventually polling
+const (
+	defaultEventuallyTimeout  = 30 // seconds
+	defaultEventuallyInterval = 2  // seconds
+)
+
+// evaluateEventually re-runs the assertion's step and re-evaluates its
+// expression every interval until it passes or the timeout elapses
+func (r *TestRunner) evaluateEventually(eventual config.EventualAssertion, ctx *interpolate.Context) AssertionResult {
+	timeout := eventual.Timeout
+	if timeout <= 0 {
+		timeout = defaultEventuallyTimeout
+	}
+	interval := eventual.Interval
+	if interval <= 0 {
+		interval = defaultEventuallyInterval
 	}
 
-	result.Duration = time.Since(startTime)
-	return result, nil
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	attempts := 0
+	var assertResult interpolate.AssertionResult
+
+	for {
+		attempts++
+
+		// Refresh captured values before evaluating
+		if eventual.Step != nil {
+			stepResult := r.executeStep(*eventual.Step, ctx, "assert_eventually", attempts-1)
+			r.updateContext(ctx, stepResult, *eventual.Step)
+		}
+
+		assertResult = interpolate.EvaluateAssertion(eventual.Expr, ctx)
+		if assertResult.Passed || !time.Now().Add(time.Duration(interval)*time.Second).Before(deadline) {
+			break
+		}
+		if r.cancelled.Load() {
+			break
+		}
+
+		select {
+		case <-time.After(time.Duration(interval) * time.Second):
+		case <-r.ctx.Done():
+		}
+	}
+
+	details := fmt.Sprintf("%s (after %d attempt(s))", assertResult.Message, attempts)
+	if !assertResult.Passed {
+		details = fmt.Sprintf("%s (timed out after %ds, %d attempt(s))", assertResult.Message, timeout, attempts)
+	}
+
+	return AssertionResult{
+		Expr:     eventual.Expr,
+		Message:  eventual.Message,
+		Passed:   assertResult.Passed,
+		Details:  details,
+		Actual:   assertResult.ActualValue,
+		Expected: assertResult.ExpectedValue,
+		Diff:     assertResult.Diff,
+	}
 }
 
 // executeStep runs a single step
-func (r *TestRunner) executeStep(step config.Step, ctx *interpolate.Context, phase string, index int) StepResult {
+func (r *TestRunner) executeStep(step config.Step, ctx *interpolate.Context, phase string, index int) (result StepResult) {
+	start := time.Now()
+	defer func() {
+		// A joined background step keeps the time it ran for
+		if result.Duration == 0 {
+			result.Duration = time.Since(start)
+		}
+	}()
+
+	if step.WaitFor != "" {
+		return r.joinBackground(step, phase, index)
+	}
+
 	// Check if this is a routine call
 	if step.Routine != "" {
 		return r.executeRoutine(step, ctx, phase, index)
@@ -251,10 +633,20 @@ func (r *TestRunner) executeStep(step config.Step, ctx *interpolate.Context, pha
 		}
 	}
 
-	// Execute handler
+	if step.Background {
+		return r.startBackground(step, interpolatedMap, ctx, phase, index)
+	}
+	return r.runHandler(step, interpolatedMap, ctx, phase, index)
+}
+
+// runHandler executes a handler step with its interpolated fields, then
+// checks its expectations, extracts capture_regex values and collects its
+// artifacts
+func (r *TestRunner) runHandler(step config.Step, interpolatedMap map[string]any, ctx *interpolate.Context, phase string, index int) StepResult {
+	handlerName := step.Handler
 	handlerResult := r.handlers.Execute(handlerName, interpolatedMap, ctx)
 
-	return StepResult{
+	stepResult := StepResult{
 		Phase:    phase,
 		Index:    index,
 		Name:     step.Name,
@@ -264,30 +656,43 @@ func (r *TestRunner) executeStep(step config.Step, ctx *interpolate.Context, pha
 		Stdout:   handlerResult.Stdout,
 		Stderr:   handlerResult.Stderr,
 		Error:    handlerResult.Error,
+		Recorded: handlerResult.Recorded,
 	}
-}
+	stepResult.ResolvedCommand = resolvedCommand(step, interpolatedMap, ctx)
+	stepResult.ResolvedParams = resolvedParams(interpolatedMap, "name", "handler", "command")
 
-// executeRoutine runs a routine
-func (r *TestRunner) executeRoutine(step config.Step, ctx *interpolate.Context, phase string, index int) StepResult {
-	routineRef := step.Routine
-	params := step.Params
+	// Check inline expectations as soon as the step finishes
+	if step.Expect != nil {
+		checkExpect(step.Expect, &stepResult, ctx)
+	}
+	if len(step.CaptureRegex) > 0 {
+		captureRegex(step.CaptureRegex, &stepResult, ctx)
+	}
 
-	// Resolve routine name
-	var routine *config.RoutineDefinition
-	if strings.HasPrefix(routineRef, "global.") {
-		routineName := routineRef[7:]
-		if rd, ok := r.globalRoutines[routineName]; ok {
-			routine = &rd
+	// Collect artifacts even when the step failed; that is when they matter most
+	if len(step.Artifacts) > 0 {
+		workdir := ctx.Workdir
+		if w, ok := interpolatedMap["workdir"].(string); ok && w != "" {
+			workdir = w
 		}
-	} else {
-		// Try UC-level routines first, then global
-		if rd, ok := r.ucRoutines[routineRef]; ok {
-			routine = &rd
-		} else if rd, ok := r.globalRoutines[routineRef]; ok {
-			routine = &rd
+		artifacts, warnings := r.collectArtifacts(step.Artifacts, workdir, ctx, phase, index)
+		stepResult.Artifacts = artifacts
+		for _, warning := range warnings {
+			if stepResult.Stderr != "" && !strings.HasSuffix(stepResult.Stderr, "\n") {
+				stepResult.Stderr += "\n"
+			}
+			stepResult.Stderr += "tsuite: " + warning + "\n"
 		}
 	}
 
+	return stepResult
+}
+
+// executeRoutine runs a routine
+func (r *TestRunner) executeRoutine(step config.Step, ctx *interpolate.Context, phase string, index int) StepResult {
+	routineRef := step.Routine
+
+	routine, _ := r.resolveRoutine(routineRef)
 	if routine == nil {
 		return StepResult{
 			Phase:   phase,
@@ -299,24 +704,29 @@ func (r *TestRunner) executeRoutine(step config.Step, ctx *interpolate.Context,
 		}
 	}
 
-	// Interpolate params
-	interpolatedParams := make(map[string]any)
-	for k, v := range params {
-		if s, ok := v.(string); ok {
-			interpolated, _ := interpolate.Interpolate(s, ctx)
-			interpolatedParams[k] = interpolated
-		} else {
-			interpolatedParams[k] = v
+	params, err := routine.ApplyParams(routineRef, interpolateParams(step.Params, ctx))
+	if err != nil {
+		return StepResult{
+			Phase:   phase,
+			Index:   index,
+			Name:    step.Name,
+			Handler: routineRef,
+			Success: false,
+			Error:   err.Error(),
 		}
 	}
 
 	// Create routine context with params
 	routineCtx := *ctx // shallow copy
-	routineCtx.Params = interpolatedParams
+	routineCtx.Params = params
+
+	resolved := resolvedParams(params)
 
 	// Execute routine steps
+	var recorded map[string]string
 	for i, routineStep := range routine.Steps {
 		stepResult := r.executeStep(routineStep, &routineCtx, phase, i)
+		recorded = mergeRecorded(recorded, stepResult.Recorded)
 
 		if !stepResult.Success && !routineStep.IgnoreErrors {
 			return StepResult{
@@ -329,6 +739,10 @@ func (r *TestRunner) executeRoutine(step config.Step, ctx *interpolate.Context,
 				Stdout:   stepResult.Stdout,
 				Stderr:   stepResult.Stderr,
 				Error:    fmt.Sprintf("routine step %d failed: %s", i, stepResult.Error),
+
+				Recorded:        recorded,
+				ResolvedCommand: stepResult.ResolvedCommand,
+				ResolvedParams:  resolved,
 			}
 		}
 
@@ -345,39 +759,180 @@ func (r *TestRunner) executeRoutine(step config.Step, ctx *interpolate.Context,
 	}
 
 	return StepResult{
-		Phase:   phase,
-		Index:   index,
-		Name:    step.Name,
-		Handler: routineRef,
-		Success: true,
+		Phase:          phase,
+		Index:          index,
+		Name:           step.Name,
+		Handler:        routineRef,
+		Success:        true,
+		Recorded:       recorded,
+		ResolvedParams: resolved,
+	}
+}
+
+// mergeRecorded adds recorded values to dst, allocating it if needed; later
+// values win
+func mergeRecorded(dst, values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(values))
+	}
+	maps.Copy(dst, values)
+	return dst
+}
+
+// resolvedCommand returns the interpolated command of a step for the step
+// result, with the values of secret references masked
+func resolvedCommand(step config.Step, interpolatedMap map[string]any, ctx *interpolate.Context) string {
+	command, _ := interpolatedMap["command"].(string)
+	return interpolate.RedactInterpolated(step.Command, command, ctx)
+}
+
+// resolvedParams copies interpolated step fields for the step result,
+// leaving out the given keys and redacting secrets
+func resolvedParams(fields map[string]any, omit ...string) map[string]any {
+	resolved := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if !slices.Contains(omit, k) {
+			resolved[k] = v
+		}
+	}
+	if len(resolved) == 0 {
+		return nil
+	}
+	return interpolate.RedactSecrets(resolved).(map[string]any)
+}
+
+// resolveRoutine looks up a routine reference and returns it with its
+// qualified name ("global.name" or "uc.name"). "global.name" and "uc.name"
+// refer to a routine in that scope; plain names try UC-level routines first,
+// then global. References from inside routines resolve the same way.
+func (r *TestRunner) resolveRoutine(routineRef string) (*config.RoutineDefinition, string) {
+	if name, ok := strings.CutPrefix(routineRef, "global."); ok {
+		if rd, ok := r.globalRoutines[name]; ok {
+			return &rd, routineRef
+		}
+		return nil, ""
+	}
+	if name, ok := strings.CutPrefix(routineRef, "uc."); ok {
+		if rd, ok := r.ucRoutines[name]; ok {
+			return &rd, routineRef
+		}
+		return nil, ""
+	}
+	if rd, ok := r.ucRoutines[routineRef]; ok {
+		return &rd, "uc." + routineRef
+	}
+	if rd, ok := r.globalRoutines[routineRef]; ok {
+		return &rd, "global." + routineRef
+	}
+	return nil, ""
+}
+
+// validateRoutineCalls checks that every routine a test calls exists and is
+// given valid params, including calls made from inside routines, and that no
+// routine ends up calling itself
+func (r *TestRunner) validateRoutineCalls(testConfig *config.TestConfig) error {
+	var validate func(steps []config.Step, chain []string) error
+	validate = func(steps []config.Step, chain []string) error {
+		for _, step := range steps {
+			if step.Routine == "" {
+				continue
+			}
+			routine, name := r.resolveRoutine(step.Routine)
+			if routine == nil {
+				if len(chain) > 0 {
+					return fmt.Errorf("routine not found: %s (called from %s)", step.Routine, chain[len(chain)-1])
+				}
+				return fmt.Errorf("routine not found: %s", step.Routine)
+			}
+			if step.Expect != nil {
+				return fmt.Errorf("expect is not supported on routine calls (%s); put it on a step inside the routine", step.Routine)
+			}
+			if len(step.CaptureRegex) > 0 {
+				return fmt.Errorf("capture_regex is not supported on routine calls (%s); put it on a step inside the routine", step.Routine)
+			}
+			if slices.Contains(chain, name) {
+				return fmt.Errorf("routine cycle: %s -> %s", strings.Join(chain, " -> "), name)
+			}
+			if err := routine.ValidateParams(step.Routine, step.Params); err != nil {
+				return err
+			}
+			if err := validate(routine.Steps, append(slices.Clip(chain), name)); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
+
+	phases := [][]config.Step{testConfig.PreRun, testConfig.Test, testConfig.PostRun}
+	for _, eventual := range testConfig.AssertEventually {
+		if eventual.Step != nil {
+			phases = append(phases, []config.Step{*eventual.Step})
+		}
+	}
+	for _, steps := range phases {
+		if err := validate(steps, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// interpolateParams interpolates the string values of routine params
+func interpolateParams(params map[string]any, ctx *interpolate.Context) map[string]any {
+	interpolated := make(map[string]any)
+	for k, v := range params {
+		if s, ok := v.(string); ok {
+			interpolated[k], _ = interpolate.Interpolate(s, ctx)
+		} else {
+			interpolated[k] = v
+		}
+	}
+	return interpolated
 }
 
 // updateContext updates the execution context after a step
 func (r *TestRunner) updateContext(ctx *interpolate.Context, result StepResult, step config.Step) {
+	// A background step's results arrive with its wait_for step
+	if step.Background {
+		return
+	}
+
 	// Update last
 	ctx.Last = map[string]any{
-		"exit_code": result.ExitCode,
-		"stdout":    result.Stdout,
-		"stderr":    result.Stderr,
+		"exit_code":   result.ExitCode,
+		"stdout":      result.Stdout,
+		"stderr":      result.Stderr,
+		"duration_ms": result.Duration.Milliseconds(),
 	}
 
-	// Handle capture
-	if step.Capture != "" {
+	// Handle capture; a wait_for step captures under the background step's name
+	capture := step.Capture
+	if capture == "" {
+		capture = step.WaitFor
+	}
+	if capture != "" {
 		// Store full step result
-		ctx.Steps[step.Capture] = map[string]any{
-			"exit_code": result.ExitCode,
-			"stdout":    result.Stdout,
-			"stderr":    result.Stderr,
-			"success":   result.Success,
-			"error":     result.Error,
+		ctx.Steps[capture] = map[string]any{
+			"exit_code":   result.ExitCode,
+			"stdout":      result.Stdout,
+			"stderr":      result.Stderr,
+			"success":     result.Success,
+			"error":       result.Error,
+			"duration_ms": result.Duration.Milliseconds(),
 		}
 
 		// Store stdout in captured for backward compatibility
 		if result.Success {
-			ctx.Captured[step.Capture] = result.Stdout
+			ctx.Captured[capture] = result.Stdout
 		}
 	}
+
+	for name, value := range result.Captures {
+		ctx.Captured[name] = value
+	}
 }
 
 // stepToMap converts a Step struct to a map for handler execution
@@ -433,9 +988,23 @@ func stepToMap(step config.Step) map[string]any {
 		m["content"] = step.Content
 	}
 
+	// Pass through fields only known to custom handlers
+	for k, v := range step.Raw {
+		if _, ok := m[k]; !ok && k != "routine" && k != "params" && k != "artifacts" && k != "expect" {
+			m[k] = v
+		}
+	}
+
 	return m
 }
 
+// Cleanup force-stops resources handlers left running (e.g. background
+// processes). RunTest calls it automatically; callers only need it when
+// interrupting a test.
+func (r *TestRunner) Cleanup() []string {
+	return r.handlers.Cleanup()
+}
+
 // GetSuiteConfig returns the loaded suite configuration
 func (r *TestRunner) GetSuiteConfig() *config.SuiteConfig {
 	return r.suiteConfig
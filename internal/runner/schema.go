@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/jsonschema"
+)
+
+// evaluateSchema validates the interpolated value of an assert_schema
+// entry against its JSON Schema file. All violations are reported, one
+// per line, as the assertion's diff.
+func (r *TestRunner) evaluateSchema(assertion config.SchemaAssertion, ctx *interpolate.Context) AssertionResult {
+	result := AssertionResult{
+		Expr:    fmt.Sprintf("schema %s: %s", assertion.Schema, assertion.Value),
+		Message: assertion.Message,
+	}
+
+	schemaPath, err := interpolate.Interpolate(assertion.Schema, ctx)
+	if err != nil {
+		result.Details = fmt.Sprintf("failed to interpolate schema path: %v", err)
+		return result
+	}
+	if !filepath.IsAbs(schemaPath) {
+		schemaPath = filepath.Join(ctx.FixturesDir, schemaPath)
+	}
+	schema, err := jsonschema.Load(schemaPath)
+	if err != nil {
+		result.Details = err.Error()
+		return result
+	}
+
+	value, err := interpolate.Interpolate(assertion.Value, ctx)
+	if err != nil {
+		result.Details = fmt.Sprintf("failed to interpolate schema value: %v", err)
+		return result
+	}
+	result.Actual = value
+	var doc any
+	if err := json.Unmarshal([]byte(value), &doc); err != nil {
+		result.Details = fmt.Sprintf("value is not JSON: %v", err)
+		return result
+	}
+
+	violations := schema.Validate(doc)
+	if len(violations) == 0 {
+		result.Passed = true
+		result.Details = "valid against " + assertion.Schema
+		return result
+	}
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.String()
+	}
+	result.Details = fmt.Sprintf("%d schema violation(s) against %s", len(violations), assertion.Schema)
+	result.Diff = strings.Join(lines, "\n")
+	return result
+}
@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+)
+
+// mcp-mesh SDK packages whose versions are recorded with each run
+const (
+	SDKPythonPackage     = "mcp-mesh"
+	SDKTypescriptPackage = "@mcpmesh/core"
+)
+
+// Environment variables through which the CLI passes package versions that
+// replace those in config.yaml: SDK packages built from packages.local
+// sources for the run, or versions pinned by tsuite run --from-manifest
+const (
+	EnvCLIVersion           = "TSUITE_CLI_VERSION"
+	EnvSDKPythonVersion     = "TSUITE_SDK_PYTHON_VERSION"
+	EnvSDKTypescriptVersion = "TSUITE_SDK_TYPESCRIPT_VERSION"
+)
+
+// usePackageVersions makes packages.cli_version, sdk_python_version and
+// sdk_typescript_version, and so ${config.packages.*_version}, the versions
+// the CLI passed
+func usePackageVersions(cfg *config.SuiteConfig) {
+	overrides := []struct {
+		env, key string
+		field    *string
+	}{
+		{EnvCLIVersion, "cli_version", &cfg.Packages.CLIVersion},
+		{EnvSDKPythonVersion, "sdk_python_version", &cfg.Packages.SDKPythonVersion},
+		{EnvSDKTypescriptVersion, "sdk_typescript_version", &cfg.Packages.SDKTypescriptVersion},
+	}
+	for _, o := range overrides {
+		version := os.Getenv(o.env)
+		if version == "" {
+			continue
+		}
+		*o.field = version
+		if cfg.Raw == nil {
+			continue
+		}
+		packages, ok := cfg.Raw["packages"].(map[string]any)
+		if !ok {
+			packages = make(map[string]any)
+			cfg.Raw["packages"] = packages
+		}
+		packages[o.key] = version
+	}
+}
+
+// sdkDetectTimeout bounds version detection, which runs before every run
+const sdkDetectTimeout = 30 * time.Second
+
+// SDKVersions are the mcp-mesh SDK versions installed where tests run; empty
+// if not installed
+type SDKVersions struct {
+	Python     string
+	Typescript string
+}
+
+// sdkVersionScript prints pip show and npm ls output for both SDKs. npm ls
+// checks the working directory's node_modules before the global ones.
+const sdkVersionScript = `(pip show ` + SDKPythonPackage + ` || python3 -m pip show ` + SDKPythonPackage + `) 2>/dev/null
+(npm ls ` + SDKTypescriptPackage + ` --depth=0 --json || npm ls -g ` + SDKTypescriptPackage + ` --depth=0 --json) 2>/dev/null
+true`
+
+// DetectSDKVersions reports the SDK versions installed on this host, looking
+// for npm packages under dir first
+func DetectSDKVersions(ctx context.Context, dir string) SDKVersions {
+	ctx, cancel := context.WithTimeout(ctx, sdkDetectTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", sdkVersionScript)
+	cmd.Dir = dir
+	out, _ := cmd.Output()
+	return parseSDKVersions(string(out))
+}
+
+// DetectImageSDKVersions reports the SDK versions installed in a container
+// image. The image must exist locally; it is never pulled.
+func DetectImageSDKVersions(ctx context.Context, image string) SDKVersions {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return SDKVersions{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, sdkDetectTimeout)
+	defer cancel()
+
+	out, _ := exec.CommandContext(ctx, "docker", "run", "--rm", "--pull", "never",
+		"--entrypoint", "sh", image, "-c", sdkVersionScript).Output()
+	return parseSDKVersions(string(out))
+}
+
+// npmVersionPattern matches the SDK's version in npm ls --json output
+var npmVersionPattern = regexp.MustCompile(`"` + regexp.QuoteMeta(SDKTypescriptPackage) + `":\s*\{\s*"version":\s*"([^"]+)"`)
+
+// parseSDKVersions reads the versions from sdkVersionScript's output
+func parseSDKVersions(output string) SDKVersions {
+	var versions SDKVersions
+	for _, line := range strings.Split(output, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "Version:"); ok && versions.Python == "" {
+			versions.Python = strings.TrimSpace(v)
+		}
+	}
+	if m := npmVersionPattern.FindStringSubmatch(output); m != nil {
+		versions.Typescript = m[1]
+	}
+	return versions
+}
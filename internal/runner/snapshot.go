@@ -0,0 +1,185 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/config"
+	"github.com/dhyansraj/mcp-mesh-test-suite/go/internal/interpolate"
+)
+
+// SnapshotsDir is the directory of a test case holding its golden files
+const SnapshotsDir = "snapshots"
+
+// EnvUpdateSnapshots makes the runner rewrite golden files instead of
+// comparing against them (tsuite run --update-snapshots)
+const EnvUpdateSnapshots = "TSUITE_UPDATE_SNAPSHOTS"
+
+// SetUpdateSnapshots makes snapshot assertions write the captured values to
+// their golden files and pass
+func (r *TestRunner) SetUpdateSnapshots(update bool) {
+	r.updateSnapshots = update
+}
+
+// evaluateSnapshot compares the interpolated value of a snapshot assertion
+// with its golden file. JSON values are compared normalized: ignored paths
+// removed, keys sorted and indented. Other values are compared as text
+// without trailing whitespace.
+func (r *TestRunner) evaluateSnapshot(testPath string, assertion config.Assertion, ctx *interpolate.Context) interpolate.AssertionResult {
+	value, err := interpolate.Interpolate(assertion.Value, ctx)
+	if err != nil {
+		return interpolate.AssertionResult{Message: fmt.Sprintf("failed to interpolate snapshot value: %v", err)}
+	}
+
+	actual, ext, err := normalizeSnapshot(value, assertion.Ignore)
+	if err != nil {
+		return interpolate.AssertionResult{Message: err.Error()}
+	}
+	dir := filepath.Join(testPath, SnapshotsDir)
+	path := filepath.Join(dir, assertion.Snapshot+ext)
+	rel := filepath.Join(SnapshotsDir, assertion.Snapshot+ext)
+
+	golden, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return interpolate.AssertionResult{Message: fmt.Sprintf("failed to read snapshot: %v", err)}
+	}
+	exists := err == nil
+
+	var expected string
+	if exists {
+		// Golden files are normalized when written; normalize again so
+		// ignore paths added later apply to them too
+		if expected, _, err = normalizeSnapshot(string(golden), assertion.Ignore); err != nil {
+			expected = strings.TrimRight(string(golden), " \t\r\n")
+		}
+	}
+
+	if r.updateSnapshots {
+		if exists && expected == actual {
+			return interpolate.AssertionResult{Passed: true, Message: "snapshot " + rel + " unchanged"}
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return interpolate.AssertionResult{Message: fmt.Sprintf("failed to write snapshot: %v", err)}
+		}
+		if err := os.WriteFile(path, []byte(actual+"\n"), 0644); err != nil {
+			return interpolate.AssertionResult{Message: fmt.Sprintf("failed to write snapshot: %v", err)}
+		}
+		if exists {
+			return interpolate.AssertionResult{Passed: true, Message: "snapshot " + rel + " updated"}
+		}
+		return interpolate.AssertionResult{Passed: true, Message: "snapshot " + rel + " written"}
+	}
+
+	if !exists {
+		return interpolate.AssertionResult{
+			Message:     fmt.Sprintf("snapshot %s does not exist; run tsuite run --update-snapshots to write it", rel),
+			ActualValue: actual,
+		}
+	}
+	result := interpolate.AssertionResult{
+		Passed:        actual == expected,
+		Message:       "matches snapshot " + rel,
+		ActualValue:   actual,
+		ExpectedValue: expected,
+	}
+	if !result.Passed {
+		result.Message = fmt.Sprintf("does not match snapshot %s; run tsuite run --update-snapshots if the change is intended", rel)
+		result.Diff = interpolate.Diff(actual, expected)
+	}
+	return result
+}
+
+// normalizeSnapshot returns the form a value is stored and compared in and
+// the golden file extension: indented JSON with sorted keys and the ignored
+// paths removed (.json), or text without trailing whitespace (.txt)
+func normalizeSnapshot(value string, ignore []string) (string, string, error) {
+	var doc any
+	if err := json.Unmarshal([]byte(value), &doc); err != nil {
+		if len(ignore) > 0 {
+			return "", "", fmt.Errorf("snapshot ignore paths need a JSON value: %v", err)
+		}
+		return strings.TrimRight(value, " \t\r\n"), ".txt", nil
+	}
+
+	for _, path := range ignore {
+		segments, err := parseIgnorePath(path)
+		if err != nil {
+			return "", "", err
+		}
+		doc = removePath(doc, segments)
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return "", "", err
+	}
+	return strings.TrimRight(b.String(), "\n"), ".json", nil
+}
+
+// ignoreSegment matches one step of an ignore path: .key, ["key"], [0] or [*]
+var ignoreSegment = regexp.MustCompile(`^(?:\.([^.\[]+)|\["([^"]+)"\]|\[(\d+|\*)\])`)
+
+// parseIgnorePath splits a path like $.agents[*].uptime into its keys and
+// indexes; "*" matches every key or element
+func parseIgnorePath(path string) ([]string, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(path), "$")
+	if !ok {
+		return nil, fmt.Errorf("invalid snapshot ignore path %q: must start with $", path)
+	}
+	var segments []string
+	for rest != "" {
+		m := ignoreSegment.FindStringSubmatch(rest)
+		if m == nil {
+			return nil, fmt.Errorf("invalid snapshot ignore path %q at %q", path, rest)
+		}
+		segments = append(segments, m[1]+m[2]+m[3])
+		rest = rest[len(m[0]):]
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid snapshot ignore path %q: ignores the whole value", path)
+	}
+	return segments, nil
+}
+
+// removePath deletes the values at a parsed path from a decoded JSON document
+func removePath(doc any, segments []string) any {
+	seg, last := segments[0], len(segments) == 1
+	switch v := doc.(type) {
+	case map[string]any:
+		for key := range v {
+			if seg != "*" && key != seg {
+				continue
+			}
+			if last {
+				delete(v, key)
+			} else {
+				v[key] = removePath(v[key], segments[1:])
+			}
+		}
+	case []any:
+		if last {
+			if seg == "*" {
+				return []any{}
+			}
+			if i, err := strconv.Atoi(seg); err == nil && i < len(v) {
+				return append(v[:i:i], v[i+1:]...)
+			}
+			return v
+		}
+		for i := range v {
+			if seg == "*" || seg == strconv.Itoa(i) {
+				v[i] = removePath(v[i], segments[1:])
+			}
+		}
+	}
+	return doc
+}
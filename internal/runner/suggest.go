@@ -0,0 +1,99 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// fixRule maps a recognizable failure signature to a suggested fix
+type fixRule struct {
+	pattern    *regexp.Regexp
+	suggestion string
+}
+
+// fixRules are checked against step output and error text of failed tests
+var fixRules = []fixRule{
+	{
+		pattern:    regexp.MustCompile(`(?i)address already in use|port is already allocated`),
+		suggestion: "A port is already in use. Stop leftover processes from a previous run (e.g. `lsof -i :<port>`) or pick a different port.",
+	},
+	{
+		pattern:    regexp.MustCompile(`(?i)no such image|pull access denied|manifest unknown|image .* not found`),
+		suggestion: "Docker image not found. Build or pull it, or check docker.base_image in config.yaml.",
+	},
+	{
+		pattern:    regexp.MustCompile(`(?i)meshctl: (command )?not found|"meshctl": executable file not found`),
+		suggestion: "meshctl is not on PATH. Install it in a pre_run step or use a base image that includes it (see packages.cli_version).",
+	},
+	{
+		pattern:    regexp.MustCompile(`(?i)jq: (command )?not found|"jq": executable file not found`),
+		suggestion: "jq is not installed. Install it in the image, or use ${jq:...} / ${json:...} interpolation instead of piping to jq.",
+	},
+	{
+		pattern:    regexp.MustCompile(`(?i)(python3?|pip3?|node|npm|npx): (command )?not found`),
+		suggestion: "A required runtime is missing from PATH. Check the base image or install it in a pre_run step.",
+	},
+	{
+		pattern:    regexp.MustCompile(`(?i)connection refused`),
+		suggestion: "Connection refused. The target service may not be ready yet; add a wait step (handler: wait, type: http) or use assert_eventually.",
+	},
+}
+
+// quotePattern strips surrounding quotes and whitespace for comparison
+var quotePattern = regexp.MustCompile(`^[\s"']+|[\s"']+$`)
+
+// SuggestFixes returns suggested fixes for recognizable failure signatures
+// in a failed test's steps, assertions and error message
+func SuggestFixes(result *TestResult) []string {
+	if result == nil || result.Passed {
+		return nil
+	}
+
+	texts := []string{result.Error}
+	for _, step := range result.Steps {
+		if step.Success {
+			continue
+		}
+		texts = append(texts, step.Error, step.Stderr, step.Stdout)
+	}
+
+	suggestions := SuggestFixesForText(texts...)
+
+	// Assertions that differ only by quoting (e.g. "200" vs 200)
+	for _, assertion := range result.Assertions {
+		if assertion.Passed || assertion.Actual == assertion.Expected {
+			continue
+		}
+		if quotePattern.ReplaceAllString(assertion.Actual, "") == quotePattern.ReplaceAllString(assertion.Expected, "") {
+			suggestions = appendSuggestion(suggestions, fmt.Sprintf(
+				"Assertion %q compares %q with %q, which differ only by quotes or whitespace. Use jq -r for raw output or adjust the expected value.",
+				assertion.Expr, assertion.Actual, assertion.Expected))
+		}
+	}
+
+	return suggestions
+}
+
+// SuggestFixesForText returns suggested fixes matching any of the given texts
+func SuggestFixesForText(texts ...string) []string {
+	var suggestions []string
+	for _, rule := range fixRules {
+		for _, text := range texts {
+			if text != "" && rule.pattern.MatchString(text) {
+				suggestions = appendSuggestion(suggestions, rule.suggestion)
+				break
+			}
+		}
+	}
+	return suggestions
+}
+
+// appendSuggestion appends a suggestion if it is not already present
+func appendSuggestion(suggestions []string, suggestion string) []string {
+	for _, s := range suggestions {
+		if s == suggestion {
+			return suggestions
+		}
+	}
+	return append(suggestions, suggestion)
+}
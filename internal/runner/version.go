@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// DevVersion is the version of tsuite and runner builds without -X main.version
+const DevVersion = "dev"
+
+// RunnerInfo identifies the runner binary a run executes with
+type RunnerInfo struct {
+	Path    string
+	Version string
+	SHA256  string
+}
+
+// InspectRunner reads the version and checksum of a runner binary. The
+// version comes from its build info, so runners built for other platforms
+// (e.g. the Linux runner mounted into containers) can be inspected too.
+func InspectRunner(path string) (*RunnerInfo, error) {
+	version, err := BinaryVersion(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runner version of %s: %w", path, err)
+	}
+	sum, err := FileSHA256(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checksum runner %s: %w", path, err)
+	}
+	return &RunnerInfo{Path: path, Version: version, SHA256: sum}, nil
+}
+
+// CheckVersion returns an error if the runner was built for a different
+// tsuite version. Development builds match any version.
+func (ri *RunnerInfo) CheckVersion(want string) error {
+	if ri.Version == want || ri.Version == DevVersion || want == DevVersion {
+		return nil
+	}
+	return fmt.Errorf("runner %s is version %s but tsuite is %s; install the matching runner with: tsuite runner install", ri.Path, ri.Version, want)
+}
+
+// BinaryVersion returns the version a tsuite or runner binary was built with
+// (-ldflags "-X main.version=..."), or DevVersion if none was set
+func BinaryVersion(path string) (string, error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for _, setting := range info.Settings {
+		if setting.Key != "-ldflags" {
+			continue
+		}
+		fields := strings.Fields(setting.Value)
+		for i, field := range fields {
+			value, ok := strings.CutPrefix(field, "-X=")
+			if !ok && field == "-X" && i+1 < len(fields) {
+				value, ok = fields[i+1], true
+			}
+			if version, found := strings.CutPrefix(strings.Trim(value, `'"`), "main.version="); ok && found {
+				return version, nil
+			}
+		}
+	}
+	return DevVersion, nil
+}
+
+// BinaryPlatform returns the GOOS and GOARCH a Go binary was built for
+func BinaryPlatform(path string) (goos, goarch string, err error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "GOOS":
+			goos = setting.Value
+		case "GOARCH":
+			goarch = setting.Value
+		}
+	}
+	if goos == "" || goarch == "" {
+		return "", "", fmt.Errorf("%s has no platform in its build info", path)
+	}
+	return goos, goarch, nil
+}
+
+// FileSHA256 returns the hex SHA-256 of a file
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
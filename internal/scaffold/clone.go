@@ -0,0 +1,188 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Artifact handling when cloning a test case
+const (
+	CloneArtifactsCopy = "copy" // Copy the artifacts directory (symlinks inside are kept as links)
+	CloneArtifactsLink = "link" // Symlink the clone's artifacts directory to the original's
+	CloneArtifactsNone = "none" // Leave artifacts out
+)
+
+// CloneConfig holds configuration for cloning a test case.
+type CloneConfig struct {
+	SuitePath string
+	From      string    // Source test ID (uc/tc)
+	To        string    // Destination test ID (uc/tc)
+	TestName  string    // Name of the clone (default: "<original name> (copy)")
+	Artifacts string    // copy (default), link or none
+	Force     bool      // Overwrite an existing destination
+	Output    io.Writer // Progress messages (default: os.Stdout)
+}
+
+var (
+	testNameLine   = regexp.MustCompile(`(?m)^name:.*$`)
+	testHeaderLine = regexp.MustCompile(`(?m)^# Test Case: .*$`)
+)
+
+// splitTestID splits uc/tc into its two path segments.
+func splitTestID(testID string) (string, string, error) {
+	uc, tc, ok := strings.Cut(testID, "/")
+	if !ok || uc == "" || tc == "" || uc != filepath.Base(uc) || tc != filepath.Base(tc) || uc == ".." || tc == ".." {
+		return "", "", fmt.Errorf("invalid test ID: %s (expected uc/tc)", testID)
+	}
+	return uc, tc, nil
+}
+
+// Clone copies a test case directory to a new test ID, rewriting the name
+// and "# Test Case:" header of its test.yaml. Everything else in test.yaml,
+// including comments, is kept as is.
+func Clone(config *CloneConfig) error {
+	out := config.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	if config.Artifacts == "" {
+		config.Artifacts = CloneArtifactsCopy
+	}
+	if config.Artifacts != CloneArtifactsCopy && config.Artifacts != CloneArtifactsLink && config.Artifacts != CloneArtifactsNone {
+		return fmt.Errorf("invalid artifacts mode %q (expected copy, link or none)", config.Artifacts)
+	}
+
+	fromUC, fromTC, err := splitTestID(config.From)
+	if err != nil {
+		return err
+	}
+	toUC, toTC, err := splitTestID(config.To)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(toUC, "uc") || !strings.HasPrefix(toTC, "tc") {
+		return fmt.Errorf("destination should be uc.../tc... (e.g., uc01_tags/tc07_variation)")
+	}
+
+	srcDir := filepath.Join(config.SuitePath, "suites", fromUC, fromTC)
+	dstDir := filepath.Join(config.SuitePath, "suites", toUC, toTC)
+
+	testYAML, err := os.ReadFile(filepath.Join(srcDir, "test.yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("test not found: %s", config.From)
+		}
+		return err
+	}
+	if srcDir == dstDir {
+		return fmt.Errorf("cannot clone %s onto itself", config.From)
+	}
+	if _, err := os.Stat(dstDir); err == nil {
+		if !config.Force {
+			return fmt.Errorf("test case already exists: %s\nUse --force to overwrite", config.To)
+		}
+		os.RemoveAll(dstDir)
+	}
+
+	var original struct {
+		Name string `yaml:"name"`
+	}
+	if err := yaml.Unmarshal(testYAML, &original); err != nil {
+		return fmt.Errorf("failed to parse %s/test.yaml: %w", config.From, err)
+	}
+	testName := config.TestName
+	if testName == "" {
+		testName = strings.TrimSpace(original.Name + " (copy)")
+	}
+
+	// Copy everything except artifacts, which are handled below
+	if err := copyTree(srcDir, dstDir, func(rel string) bool { return rel == "artifacts" }); err != nil {
+		return fmt.Errorf("failed to copy test case: %w", err)
+	}
+	fmt.Fprintf(out, "✓ Copied: %s → %s\n", config.From, config.To)
+
+	srcArtifacts := filepath.Join(srcDir, "artifacts")
+	if _, err := os.Stat(srcArtifacts); err == nil {
+		dstArtifacts := filepath.Join(dstDir, "artifacts")
+		switch config.Artifacts {
+		case CloneArtifactsCopy:
+			if err := copyTree(srcArtifacts, dstArtifacts, nil); err != nil {
+				return fmt.Errorf("failed to copy artifacts: %w", err)
+			}
+			fmt.Fprintln(out, "✓ Copied artifacts")
+		case CloneArtifactsLink:
+			relPath, err := filepath.Rel(dstDir, srcArtifacts)
+			if err != nil {
+				relPath = srcArtifacts
+			}
+			if err := os.Symlink(relPath, dstArtifacts); err != nil {
+				return fmt.Errorf("failed to link artifacts: %w", err)
+			}
+			fmt.Fprintf(out, "✓ Linked artifacts → %s\n", relPath)
+		case CloneArtifactsNone:
+			fmt.Fprintln(out, "! Skipped artifacts")
+		}
+	}
+
+	// UC-level artifacts are shared by the use case and never copied
+	if fromUC != toUC && strings.Contains(string(testYAML), "/uc-artifacts/") {
+		fmt.Fprintf(out, "! Test uses UC-level artifacts from %s; copy them to suites/%s/artifacts if needed\n", fromUC, toUC)
+	}
+
+	// Rewrite name and header; json.Marshal gives a valid double-quoted YAML string
+	quoted, _ := json.Marshal(testName)
+	nameLine := "name: " + string(quoted)
+	rewritten := testHeaderLine.ReplaceAllLiteral(testYAML, []byte("# Test Case: "+toTC))
+	if testNameLine.Match(rewritten) {
+		rewritten = testNameLine.ReplaceAllLiteral(rewritten, []byte(nameLine))
+	} else {
+		rewritten = append([]byte(nameLine+"\n"), rewritten...)
+	}
+	if err := os.WriteFile(filepath.Join(dstDir, "test.yaml"), rewritten, 0644); err != nil {
+		return fmt.Errorf("failed to write test.yaml: %w", err)
+	}
+	fmt.Fprintf(out, "✓ Renamed to %q\n", testName)
+
+	return nil
+}
+
+// copyTree copies a directory recursively like copyDir, but recreates
+// symlinks instead of following them. Relative links stay valid because test
+// case directories are all at the same depth.
+func copyTree(src, dst string, ignore func(string) bool) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, _ := filepath.Rel(src, path)
+		dstPath := filepath.Join(dst, relPath)
+
+		if ignore != nil && ignore(relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, dstPath)
+		case info.IsDir():
+			return os.MkdirAll(dstPath, info.Mode())
+		default:
+			return copyFile(path, dstPath)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InitConfig holds options for bootstrapping a new suite.
+type InitConfig struct {
+	Dir   string // Suite directory (created if missing)
+	Name  string // Suite name (default: directory name)
+	Mode  string // "docker" or "standalone"
+	Force bool   // Overwrite files of an existing suite
+}
+
+// initFile is a file generated by Init, relative to the suite directory.
+type initFile struct {
+	path    string
+	content string
+}
+
+// Init generates a skeleton suite: config.yaml with commented options, global
+// routines for Python/TypeScript agent setup, an example uc01/tc01 test and a
+// .gitignore.
+func Init(config *InitConfig) error {
+	if config.Mode != "docker" && config.Mode != "standalone" {
+		return fmt.Errorf("invalid mode %q (expected docker or standalone)", config.Mode)
+	}
+
+	absDir, err := filepath.Abs(config.Dir)
+	if err != nil {
+		return fmt.Errorf("invalid directory: %w", err)
+	}
+	name := config.Name
+	if name == "" {
+		name = filepath.Base(absDir)
+	}
+
+	if _, err := os.Stat(filepath.Join(absDir, "config.yaml")); err == nil && !config.Force {
+		return fmt.Errorf("suite already exists: %s\nUse --force to overwrite", absDir)
+	}
+
+	files := []initFile{
+		{"config.yaml", generateConfigYAML(name, config.Mode)},
+		{"global/routines.yaml", globalRoutinesYAML},
+		{"suites/uc01_example/tc01_hello/test.yaml", exampleTestYAML},
+		{".gitignore", suiteGitignore},
+	}
+
+	for _, f := range files {
+		path := filepath.Join(absDir, filepath.FromSlash(f.path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(f.content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+		fmt.Printf("✓ Created: %s\n", f.path)
+	}
+
+	fmt.Printf("\nSuite %q initialized in %s\n", name, absDir)
+	fmt.Println("\nNext steps:")
+	if config.Mode == "docker" {
+		fmt.Println("  1. Set docker.base_image in config.yaml (run 'tsuite doctor' to check it)")
+	} else {
+		fmt.Println("  1. Review config.yaml (run 'tsuite doctor' to check your setup)")
+	}
+	fmt.Printf("  2. Run the example: tsuite run --suite-path %s --uc uc01_example\n", config.Dir)
+	fmt.Printf("  3. Add tests with: tsuite scaffold --suite %s --uc uc02_my_feature --tc tc01_my_test ./my-agent\n", config.Dir)
+
+	return nil
+}
+
+// generateConfigYAML returns config.yaml with the less common options commented out.
+func generateConfigYAML(name, mode string) string {
+	return strings.NewReplacer("{{name}}", name, "{{mode}}", mode).Replace(`# Suite configuration. Values are available to tests as ${config.<path>},
+# e.g. ${config.packages.cli_version}.
+
+suite:
+  name: "{{name}}"
+  mode: "{{mode}}"  # docker (each test in a fresh container) or standalone (on this machine)
+
+# Package versions used by the setup routines in global/routines.yaml
+packages:
+  cli_version: "0.8.0"
+  sdk_python_version: "0.8.0"
+  sdk_typescript_version: "0.8.0"
+  # node_version: "20"       # Node.js the tests run with (fnm/nvm in standalone, docker.node_images)
+  # local:                   # SDK checkouts built once per run and installed instead of the published SDKs
+  #   python_source: ../mcp-mesh/src/runtime/python
+  #   typescript_source: ../mcp-mesh/src/runtime/typescript
+
+docker:
+  base_image: "tsuite-mesh:local"  # Image for test containers (docker mode); needs sh and jq
+  # network: "bridge"              # bridge (default), host, or a custom network name
+
+execution:
+  max_workers: 4  # Parallel workers (overridden by --parallel)
+  timeout: 300    # Default test timeout in seconds
+  # leak_checks: true          # Report ports, containers and files left behind after post_run
+  # leak_ignore: ["*.tmp"]     # Workdir globs not reported as leaks
+  # capture_env: ["CI", "MCP_MESH_*"]  # Env vars recorded with each test result
+`)
+}
+
+// globalRoutinesYAML holds the standard agent setup routines.
+const globalRoutinesYAML = `# Global routines, available to every test as global.<name>.
+# Larger suites can split these into global/routines/*.yaml.
+
+routines:
+  setup_for_python_agent:
+    description: "Create a venv and install meshctl and the Python SDK"
+    params:
+      meshctl_version:
+        type: string
+        required: true
+      mcpmesh_version:
+        type: string
+        required: true
+    steps:
+      - handler: meshctl-install
+        version: "${params.meshctl_version}"
+
+      - handler: shell
+        command: "python3 -m venv /workspace/.venv"
+
+      - handler: shell
+        command: |
+          source /workspace/.venv/bin/activate
+          pip install mcp-mesh==${params.mcpmesh_version}
+
+  setup_for_typescript_agent:
+    description: "Install meshctl for TypeScript agents"
+    params:
+      meshctl_version:
+        type: string
+        required: true
+    steps:
+      - handler: meshctl-install
+        version: "${params.meshctl_version}"
+
+  cleanup_workspace:
+    description: "Clean up workspace directory"
+    steps:
+      - handler: shell
+        command: "rm -rf /workspace/*"
+        ignore_errors: true
+`
+
+// exampleTestYAML is a self-contained test that passes in both modes.
+const exampleTestYAML = `# Example test: runs a command, captures its output and checks it.
+# Replace with real tests, or generate them with 'tsuite scaffold'.
+
+name: "Hello tsuite"
+description: "Smoke test showing steps, captures and assertions"
+tags:
+  - example
+  - smoke
+timeout: 60
+
+# pre_run:
+#   - routine: global.setup_for_python_agent
+#     params:
+#       meshctl_version: "${config.packages.cli_version}"
+#       mcpmesh_version: "${config.packages.sdk_python_version}"
+
+test:
+  - name: "Say hello"
+    handler: shell
+    command: echo "hello from ${config.suite.name}"
+    capture: greeting
+    expect:
+      exit_code: 0
+      stdout_contains: "hello"
+
+assertions:
+  - expr: "${captured.greeting} contains 'hello'"
+    message: "Greeting should be captured"
+`
+
+// suiteGitignore ignores files tests and agents leave inside the suite.
+const suiteGitignore = `# Python
+.venv/
+__pycache__/
+*.pyc
+
+# Node
+node_modules/
+
+# Logs and reports
+*.log
+reports/
+
+# OS
+.DS_Store
+`
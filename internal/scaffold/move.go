@@ -0,0 +1,103 @@
+package scaffold
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MoveConfig holds configuration for moving (renaming) a test case.
+type MoveConfig struct {
+	SuitePath string
+	From      string    // Current test ID (uc/tc)
+	To        string    // New test ID (uc/tc)
+	Output    io.Writer // Progress messages (default: os.Stdout)
+}
+
+// Move renames a test case directory, creating the destination use case if
+// needed and removing the source use case once it has no tests left. The
+// "# Test Case:" header of test.yaml is updated; its name is kept.
+func Move(config *MoveConfig) error {
+	out := config.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	fromUC, fromTC, err := splitTestID(config.From)
+	if err != nil {
+		return err
+	}
+	toUC, toTC, err := splitTestID(config.To)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(toUC, "uc") || !strings.HasPrefix(toTC, "tc") {
+		return fmt.Errorf("destination should be uc.../tc... (e.g., uc03_other/tc01_basic)")
+	}
+
+	srcDir := filepath.Join(config.SuitePath, "suites", fromUC, fromTC)
+	dstDir := filepath.Join(config.SuitePath, "suites", toUC, toTC)
+
+	testYAMLPath := filepath.Join(srcDir, "test.yaml")
+	if _, err := os.Stat(testYAMLPath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("test not found: %s", config.From)
+		}
+		return err
+	}
+	if srcDir == dstDir {
+		return fmt.Errorf("cannot move %s onto itself", config.From)
+	}
+	if _, err := os.Stat(dstDir); err == nil {
+		return fmt.Errorf("test case already exists: %s", config.To)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dstDir), 0755); err != nil {
+		return fmt.Errorf("failed to create use case directory: %w", err)
+	}
+	if err := os.Rename(srcDir, dstDir); err != nil {
+		return fmt.Errorf("failed to move test case: %w", err)
+	}
+	fmt.Fprintf(out, "✓ Moved: %s → %s\n", config.From, config.To)
+
+	testYAMLPath = filepath.Join(dstDir, "test.yaml")
+	if testYAML, err := os.ReadFile(testYAMLPath); err == nil && testHeaderLine.Match(testYAML) {
+		rewritten := testHeaderLine.ReplaceAllLiteral(testYAML, []byte("# Test Case: "+toTC))
+		if err := os.WriteFile(testYAMLPath, rewritten, 0644); err != nil {
+			return fmt.Errorf("failed to write test.yaml: %w", err)
+		}
+	}
+
+	// UC-level artifacts stay with the source use case
+	if fromUC != toUC {
+		if testYAML, err := os.ReadFile(testYAMLPath); err == nil && strings.Contains(string(testYAML), "/uc-artifacts/") {
+			fmt.Fprintf(out, "! Test uses UC-level artifacts from %s; copy them to suites/%s/artifacts if needed\n", fromUC, toUC)
+		}
+		removeIfNoTests(filepath.Join(config.SuitePath, "suites", fromUC), out)
+	}
+
+	return nil
+}
+
+// removeIfNoTests removes a use case directory that no longer contains test
+// case directories. Use cases with routines or artifacts of their own are kept.
+func removeIfNoTests(ucDir string, out io.Writer) {
+	entries, err := os.ReadDir(ucDir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "tc") {
+			return
+		}
+	}
+	if len(entries) == 0 {
+		if os.Remove(ucDir) == nil {
+			fmt.Fprintf(out, "✓ Removed empty use case: %s\n", filepath.Base(ucDir))
+		}
+		return
+	}
+	fmt.Fprintf(out, "! Use case %s has no tests left\n", filepath.Base(ucDir))
+}
@@ -32,9 +32,10 @@ type Config struct {
 	DryRun           bool
 	Force            bool
 	SkipArtifactCopy bool
-	UseSymlinks      bool   // Create symlinks instead of copying artifacts
-	FlatScriptDir    string // For --filter mode: directory containing flat scripts
-	Filter           string // Glob pattern for flat script discovery (e.g., "*.py")
+	UseSymlinks      bool      // Create symlinks instead of copying artifacts
+	FlatScriptDir    string    // For --filter mode: directory containing flat scripts
+	Filter           string    // Glob pattern for flat script discovery (e.g., "*.py")
+	Output           io.Writer // Progress messages (default: os.Stdout)
 }
 
 // ValidateSuite checks that suite exists and has config.yaml.
@@ -275,11 +276,11 @@ func copyFile(src, dst string) error {
 }
 
 // copyAgentToArtifacts copies agent directory to artifacts.
-func copyAgentToArtifacts(agent *AgentInfo, artifactsDir string, dryRun bool) (string, error) {
+func copyAgentToArtifacts(out io.Writer, agent *AgentInfo, artifactsDir string, dryRun bool) (string, error) {
 	targetPath := filepath.Join(artifactsDir, agent.Name)
 
 	if dryRun {
-		fmt.Printf("  Would copy: %s → %s\n", agent.Path, targetPath)
+		fmt.Fprintf(out, "  Would copy: %s → %s\n", agent.Path, targetPath)
 		return targetPath, nil
 	}
 
@@ -288,7 +289,7 @@ func copyAgentToArtifacts(agent *AgentInfo, artifactsDir string, dryRun bool) (s
 	os.MkdirAll(targetPath, 0755)
 
 	if agent.AgentType == "typescript" {
-		return targetPath, copyTypeScriptAgent(agent, targetPath)
+		return targetPath, copyTypeScriptAgent(out, agent, targetPath)
 	}
 	return targetPath, copyPythonAgent(agent, targetPath)
 }
@@ -296,7 +297,7 @@ func copyAgentToArtifacts(agent *AgentInfo, artifactsDir string, dryRun bool) (s
 // symlinkAgentToArtifacts creates a symlink to agent directory in artifacts.
 // This is useful for testing existing examples without copying them.
 // Uses relative paths to make symlinks portable across machines.
-func symlinkAgentToArtifacts(agent *AgentInfo, artifactsDir string, dryRun bool) (string, error) {
+func symlinkAgentToArtifacts(out io.Writer, agent *AgentInfo, artifactsDir string, dryRun bool) (string, error) {
 	targetPath := filepath.Join(artifactsDir, agent.Name)
 
 	// Get absolute path of agent directory for the symlink target
@@ -314,7 +315,7 @@ func symlinkAgentToArtifacts(agent *AgentInfo, artifactsDir string, dryRun bool)
 	}
 
 	if dryRun {
-		fmt.Printf("  Would symlink: %s → %s\n", targetPath, relPath)
+		fmt.Fprintf(out, "  Would symlink: %s → %s\n", targetPath, relPath)
 		return targetPath, nil
 	}
 
@@ -330,7 +331,7 @@ func symlinkAgentToArtifacts(agent *AgentInfo, artifactsDir string, dryRun bool)
 }
 
 // copyTypeScriptAgent copies TypeScript agent using whitelist approach.
-func copyTypeScriptAgent(agent *AgentInfo, targetPath string) error {
+func copyTypeScriptAgent(out io.Writer, agent *AgentInfo, targetPath string) error {
 	source := agent.Path
 
 	// Essential files
@@ -361,7 +362,7 @@ func copyTypeScriptAgent(agent *AgentInfo, targetPath string) error {
 	// Clean npm local references
 	pkgJSON := filepath.Join(targetPath, "package.json")
 	if changed, _ := cleanNpmLocalReferences(pkgJSON); changed {
-		fmt.Printf("  Cleaned local npm references in %s/package.json\n", agent.Name)
+		fmt.Fprintf(out, "  Cleaned local npm references in %s/package.json\n", agent.Name)
 	}
 
 	return nil
@@ -401,6 +402,40 @@ func copyPythonAgent(agent *AgentInfo, targetPath string) error {
 	return copyDir(agent.Path, targetPath, ignore)
 }
 
+// defaultTestName returns config.TestName, or a name derived from the TC name.
+func defaultTestName(config *Config) string {
+	if config.TestName != "" {
+		return config.TestName
+	}
+	testName := strings.ReplaceAll(config.TCName, "_", " ")
+	testName = strings.ReplaceAll(testName, "tc", "Test")
+	return strings.Title(testName)
+}
+
+// GenerateBlankTestYAML generates a test.yaml without agents, with a single
+// placeholder step to replace.
+func GenerateBlankTestYAML(config *Config) string {
+	return fmt.Sprintf(`# Test Case: %s
+
+name: "%s"
+description: "TODO: Add description"
+tags:
+  - TODO
+timeout: 300
+
+test:
+  # === TODO: Replace with your test steps ===
+  - name: "Placeholder"
+    handler: shell
+    command: "echo TODO"
+    capture: output
+
+assertions:
+  - expr: "${captured.output} contains 'TODO'"
+    message: "Placeholder assertion"
+`, config.TCName, defaultTestName(config))
+}
+
 // GenerateTestYAML generates test.yaml content.
 func GenerateTestYAML(config *Config) string {
 	agents := config.Agents
@@ -512,13 +547,7 @@ func GenerateTestYAML(config *Config) string {
 		agentNames = append(agentNames, a.Name)
 	}
 
-	// Test name
-	testName := config.TestName
-	if testName == "" {
-		testName = strings.ReplaceAll(config.TCName, "_", " ")
-		testName = strings.ReplaceAll(testName, "tc", "Test")
-		testName = strings.Title(testName)
-	}
+	testName := defaultTestName(config)
 
 	return fmt.Sprintf(`# Test Case: %s
 # Auto-generated by tsuite scaffold
@@ -599,6 +628,11 @@ post_run:
 
 // Run executes the scaffold operation.
 func Run(config *Config) error {
+	out := config.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
 	suitePath := config.SuitePath
 	suitesDir := filepath.Join(suitePath, "suites")
 	ucDir := filepath.Join(suitesDir, config.UCName)
@@ -639,29 +673,29 @@ func Run(config *Config) error {
 	}
 
 	if config.DryRun {
-		fmt.Println("\nDry run - no files will be created")
+		fmt.Fprintln(out, "\nDry run - no files will be created")
 	}
 
 	// Create UC if needed
 	if _, err := os.Stat(ucDir); os.IsNotExist(err) {
 		if config.DryRun {
-			fmt.Printf("Would create UC: %s\n", ucDir)
+			fmt.Fprintf(out, "Would create UC: %s\n", ucDir)
 		} else {
 			os.MkdirAll(ucDir, 0755)
-			fmt.Printf("✓ Created UC: %s/\n", config.UCName)
+			fmt.Fprintf(out, "✓ Created UC: %s/\n", config.UCName)
 		}
 	}
 
 	// Create TC
 	if _, err := os.Stat(tcDir); os.IsNotExist(err) {
 		if config.DryRun {
-			fmt.Printf("Would create TC: %s\n", tcDir)
+			fmt.Fprintf(out, "Would create TC: %s\n", tcDir)
 		} else {
 			os.MkdirAll(tcDir, 0755)
-			fmt.Printf("✓ Created TC: %s/%s/\n", config.UCName, config.TCName)
+			fmt.Fprintf(out, "✓ Created TC: %s/%s/\n", config.UCName, config.TCName)
 		}
 	} else if config.Force {
-		fmt.Printf("! Overwriting TC: %s/%s/\n", config.UCName, config.TCName)
+		fmt.Fprintf(out, "! Overwriting TC: %s/%s/\n", config.UCName, config.TCName)
 	}
 
 	// Copy or symlink artifacts
@@ -676,7 +710,7 @@ func Run(config *Config) error {
 			targetPath := filepath.Join(artifactsDir, dirName)
 
 			if config.UseSymlinks {
-				fmt.Println("✓ Creating artifact symlink:")
+				fmt.Fprintln(out, "✓ Creating artifact symlink:")
 				absPath, _ := filepath.Abs(config.FlatScriptDir)
 				// Calculate relative path for portable symlinks
 				relPath, err := filepath.Rel(artifactsDir, absPath)
@@ -684,18 +718,18 @@ func Run(config *Config) error {
 					relPath = absPath // Fall back to absolute if relative fails
 				}
 				if config.DryRun {
-					fmt.Printf("  Would symlink: %s → %s\n", targetPath, relPath)
+					fmt.Fprintf(out, "  Would symlink: %s → %s\n", targetPath, relPath)
 				} else {
 					os.RemoveAll(targetPath)
 					if err := os.Symlink(relPath, targetPath); err != nil {
 						return fmt.Errorf("failed to create symlink: %w", err)
 					}
-					fmt.Printf("    - %s → %s (%d scripts)\n", dirName, relPath, len(config.Agents))
+					fmt.Fprintf(out, "    - %s → %s (%d scripts)\n", dirName, relPath, len(config.Agents))
 				}
 			} else {
-				fmt.Println("✓ Copying artifacts:")
+				fmt.Fprintln(out, "✓ Copying artifacts:")
 				if config.DryRun {
-					fmt.Printf("  Would copy: %s → %s\n", config.FlatScriptDir, targetPath)
+					fmt.Fprintf(out, "  Would copy: %s → %s\n", config.FlatScriptDir, targetPath)
 				} else {
 					os.RemoveAll(targetPath)
 					os.MkdirAll(targetPath, 0755)
@@ -707,21 +741,21 @@ func Run(config *Config) error {
 							return fmt.Errorf("failed to copy %s: %w", agent.EntryPoint, err)
 						}
 					}
-					fmt.Printf("    - %s (%d scripts)\n", dirName, len(config.Agents))
+					fmt.Fprintf(out, "    - %s (%d scripts)\n", dirName, len(config.Agents))
 				}
 			}
 
 			// List discovered scripts
-			fmt.Println("  Scripts:")
+			fmt.Fprintln(out, "  Scripts:")
 			for _, agent := range config.Agents {
-				fmt.Printf("    - %s\n", agent.EntryPoint)
+				fmt.Fprintf(out, "    - %s\n", agent.EntryPoint)
 			}
 		} else {
 			// Standard mode: copy/symlink each agent directory
 			if config.UseSymlinks {
-				fmt.Println("✓ Creating artifact symlinks:")
+				fmt.Fprintln(out, "✓ Creating artifact symlinks:")
 				for _, agent := range config.Agents {
-					_, err := symlinkAgentToArtifacts(&agent, artifactsDir, config.DryRun)
+					_, err := symlinkAgentToArtifacts(out, &agent, artifactsDir, config.DryRun)
 					if err != nil {
 						return fmt.Errorf("failed to create symlink for %s: %w", agent.Name, err)
 					}
@@ -729,22 +763,22 @@ func Run(config *Config) error {
 					if agent.AgentType == "typescript" {
 						typeLabel = "TypeScript"
 					}
-					fmt.Printf("    - %s → %s (%s)\n", agent.Name, agent.Path, typeLabel)
+					fmt.Fprintf(out, "    - %s → %s (%s)\n", agent.Name, agent.Path, typeLabel)
 				}
 			} else {
-				fmt.Println("✓ Copying artifacts:")
+				fmt.Fprintln(out, "✓ Copying artifacts:")
 				for _, agent := range config.Agents {
-					copyAgentToArtifacts(&agent, artifactsDir, config.DryRun)
+					copyAgentToArtifacts(out, &agent, artifactsDir, config.DryRun)
 					typeLabel := "Python"
 					if agent.AgentType == "typescript" {
 						typeLabel = "TypeScript"
 					}
-					fmt.Printf("    - %s (%s)\n", agent.Name, typeLabel)
+					fmt.Fprintf(out, "    - %s (%s)\n", agent.Name, typeLabel)
 				}
 			}
 		}
 	} else {
-		fmt.Println("! Skipping artifact copy (--skip-artifact-copy)")
+		fmt.Fprintln(out, "! Skipping artifact copy (--skip-artifact-copy)")
 	}
 
 	// Generate test.yaml
@@ -752,22 +786,22 @@ func Run(config *Config) error {
 	testYAMLContent := GenerateTestYAML(config)
 
 	if config.DryRun {
-		fmt.Printf("\nWould create: %s\n", testYAMLPath)
-		fmt.Println("\nGenerated test.yaml:")
-		fmt.Println(testYAMLContent)
+		fmt.Fprintf(out, "\nWould create: %s\n", testYAMLPath)
+		fmt.Fprintln(out, "\nGenerated test.yaml:")
+		fmt.Fprintln(out, testYAMLContent)
 	} else {
 		if err := os.WriteFile(testYAMLPath, []byte(testYAMLContent), 0644); err != nil {
 			return fmt.Errorf("failed to write test.yaml: %w", err)
 		}
-		fmt.Printf("✓ Generated: %s/%s/test.yaml\n", config.UCName, config.TCName)
+		fmt.Fprintf(out, "✓ Generated: %s/%s/test.yaml\n", config.UCName, config.TCName)
 	}
 
 	// Print completion message
 	if !config.DryRun {
-		fmt.Println("\nScaffold complete!")
-		fmt.Println("\nNext steps:")
-		fmt.Printf("  1. Edit test.yaml to add your test steps and assertions\n")
-		fmt.Printf("  2. Run with: tsuite run --suite %s --tc %s/%s\n", config.SuitePath, config.UCName, config.TCName)
+		fmt.Fprintln(out, "\nScaffold complete!")
+		fmt.Fprintln(out, "\nNext steps:")
+		fmt.Fprintf(out, "  1. Edit test.yaml to add your test steps and assertions\n")
+		fmt.Fprintf(out, "  2. Run with: tsuite run --suite %s --tc %s/%s\n", config.SuitePath, config.UCName, config.TCName)
 	}
 
 	return nil
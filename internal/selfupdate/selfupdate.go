@@ -0,0 +1,215 @@
+// Package selfupdate installs tsuite and tsuite-runner binaries published as
+// GitHub release assets (tsuite-<goos>-<goarch>, tsuite-runner-<goos>-<goarch>
+// and a checksums.txt in sha256sum format).
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultRepo is the GitHub repository tsuite releases are published in
+const DefaultRepo = "mcpmesh/mcp-mesh-test-suite"
+
+// checksumsAsset lists the SHA-256 of every other asset of a release
+const checksumsAsset = "checksums.txt"
+
+// Release is a GitHub release
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the tsuite version of the release (its tag without the v)
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// HasChecksums reports whether the release publishes a checksums.txt
+func (r *Release) HasChecksums() bool {
+	for _, asset := range r.Assets {
+		if asset.Name == checksumsAsset {
+			return true
+		}
+	}
+	return false
+}
+
+// AssetName returns the release asset name of a binary (tsuite or
+// tsuite-runner) for a platform
+func AssetName(binary, goos, goarch string) string {
+	return fmt.Sprintf("%s-%s-%s", binary, goos, goarch)
+}
+
+// Client fetches releases from GitHub
+type Client struct {
+	repo       string
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the releases of repo (owner/name)
+func NewClient(repo string) *Client {
+	return &Client{
+		repo:   repo,
+		apiURL: "https://api.github.com",
+		httpClient: &http.Client{
+			Timeout: 5 * time.Minute, // Binaries are tens of MB
+		},
+	}
+}
+
+// GetRelease returns the release of a tsuite version, or the latest release
+// if version is empty
+func (c *Client) GetRelease(version string) (*Release, error) {
+	path := "/releases/latest"
+	if version != "" {
+		path = "/releases/tags/v" + strings.TrimPrefix(version, "v")
+	}
+	resp, err := c.httpClient.Get(c.apiURL + "/repos/" + c.repo + path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		if version == "" {
+			return nil, fmt.Errorf("no release found in %s", c.repo)
+		}
+		return nil, fmt.Errorf("release v%s not found in %s", strings.TrimPrefix(version, "v"), c.repo)
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to fetch release: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &release, nil
+}
+
+// Download fetches an asset of a release and verifies it against the
+// release's checksums.txt
+func (c *Client) Download(release *Release, name string) ([]byte, error) {
+	sums, err := c.fetchAsset(release, checksumsAsset)
+	if err != nil {
+		return nil, err
+	}
+	want, ok := parseChecksums(sums)[name]
+	if !ok {
+		return nil, fmt.Errorf("%s of %s has no checksum for %s", checksumsAsset, release.TagName, name)
+	}
+
+	data, err := c.fetchAsset(release, name)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+	return data, nil
+}
+
+// DownloadUnverified fetches an asset of a release that publishes no
+// checksums.txt
+func (c *Client) DownloadUnverified(release *Release, name string) ([]byte, error) {
+	return c.fetchAsset(release, name)
+}
+
+// DownloadURL fetches a file from a URL outside GitHub releases, e.g. a
+// mirror
+func (c *Client) DownloadURL(url string) ([]byte, error) {
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// fetchAsset downloads an asset of a release
+func (c *Client) fetchAsset(release *Release, name string) ([]byte, error) {
+	var assetURL string
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			assetURL = asset.URL
+			break
+		}
+	}
+	if assetURL == "" {
+		return nil, fmt.Errorf("release %s has no asset %s", release.TagName, name)
+	}
+
+	resp, err := c.httpClient.Get(assetURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", name, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseChecksums parses sha256sum output into SHA-256 by file name
+func parseChecksums(data []byte) map[string]string {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			sums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+		}
+	}
+	return sums
+}
+
+// Install atomically replaces the binary at path with data, so a running
+// tsuite or concurrent runs never see a partial file
+func Install(data []byte, path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to install %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to install %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to install %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to install %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to install %s: %w", path, err)
+	}
+	return nil
+}
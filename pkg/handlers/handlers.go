@@ -0,0 +1,77 @@
+// Package handlers is the public extension API for tsuite step handlers.
+//
+// Downstream builds can compile in extra handlers by implementing Handler
+// and calling Register from an init function, then blank-importing the
+// package from the runner (see cmd/runner/plugins.go):
+//
+//	package myhandler
+//
+//	import "github.com/dhyansraj/mcp-mesh-test-suite/go/pkg/handlers"
+//
+//	func init() {
+//		handlers.Register(&MyHandler{})
+//	}
+package handlers
+
+import (
+	"sort"
+	"sync"
+)
+
+// Result holds the result of executing a step
+type Result struct {
+	Success  bool   `json:"success"`
+	ExitCode int    `json:"exit_code"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Context exposes the test context to a handler. Maps are copies, so changes
+// made by a handler are not visible to later steps.
+type Context struct {
+	SuitePath   string         `json:"suite_path"`   // Suite directory path
+	Workdir     string         `json:"workdir"`      // Working directory
+	FixturesDir string         `json:"fixtures_dir"` // Fixtures directory
+	Artifacts   string         `json:"artifacts"`    // Test-specific artifacts directory
+	Config      map[string]any `json:"config"`       // Configuration values
+	State       map[string]any `json:"state"`        // Shared state
+	Captured    map[string]any `json:"captured"`     // Captured variables
+	Params      map[string]any `json:"params"`       // Routine parameters
+}
+
+// Handler is the interface for custom step handlers
+type Handler interface {
+	// Name returns the handler name used in test.yaml (e.g., "kafka-produce")
+	Name() string
+	// Execute runs the handler with the interpolated step fields
+	Execute(step map[string]any, ctx *Context) Result
+}
+
+var (
+	mu         sync.RWMutex
+	registered = make(map[string]Handler)
+)
+
+// Register makes a handler available to all test runners. Registering a
+// handler with the name of a built-in handler replaces the built-in.
+func Register(h Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	registered[h.Name()] = h
+}
+
+// Registered returns all registered handlers sorted by name
+func Registered() []Handler {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make([]Handler, 0, len(registered))
+	for _, h := range registered {
+		result = append(result, h)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}